@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -9,21 +10,36 @@ import (
 
 	"github.com/invopop/jsonschema"
 	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/vars"
+	"github.com/sentoz/yc-sheduler/static"
 )
 
+// schemaIDFormat is the $id of a generated schema: the raw URL of the schema
+// file at the given version tag.
+const schemaIDFormat = "https://raw.githubusercontent.com/sentoz/yc-sheduler/%s/static/schemas/%s.json"
+
 func main() {
 	var (
 		outFile         string
 		scheduleOutFile string
 		modulePath      string
+		version         string
+		format          string
 		prettyPrint     bool
 	)
 	flag.StringVar(&outFile, "out", "", "output file path (default: stdout)")
 	flag.StringVar(&scheduleOutFile, "schedule-out", "", "output file path for schedule schema (default: stdout)")
 	flag.StringVar(&modulePath, "module", "github.com/sentoz/yc-sheduler", "go module path (for extracting comments)")
+	flag.StringVar(&version, "version", vars.Version, "schema version embedded in $id")
+	flag.StringVar(&format, "format", "json", "output format: json or yaml")
 	flag.BoolVar(&prettyPrint, "pretty", true, "pretty print JSON output")
 	flag.Parse()
 
+	if format != "json" && format != "yaml" {
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q, want json or yaml\n", format)
+		os.Exit(2)
+	}
+
 	// Create reflector
 	r := &jsonschema.Reflector{
 		AllowAdditionalProperties: false,
@@ -41,18 +57,20 @@ func main() {
 	// Use draft-07 which is supported by github.com/santhosh-tekuri/jsonschema/v6.
 	// Newer drafts like 2020-12 are not supported and cause metaschema validation errors.
 	configSchema.Version = "http://json-schema.org/draft-07/schema#"
+	configSchema.ID = jsonschema.ID(fmt.Sprintf(schemaIDFormat, version, "config"))
 	configSchema.Title = "YC Scheduler Configuration"
 	configSchema.Description = "Configuration schema for YC Scheduler application"
 
 	scheduleSchema.Version = "http://json-schema.org/draft-07/schema#"
+	scheduleSchema.ID = jsonschema.ID(fmt.Sprintf(schemaIDFormat, version, "schedule"))
 	scheduleSchema.Title = "YC Scheduler Schedule Manifest"
 	scheduleSchema.Description = "Schedule manifest schema for YC Scheduler application"
 
-	if err := writeSchema(outFile, configSchema, prettyPrint); err != nil {
+	if err := writeSchema(outFile, configSchema, format, prettyPrint); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: failed to write config schema: %v\n", err)
 		os.Exit(1)
 	}
-	if err := writeSchema(scheduleOutFile, scheduleSchema, prettyPrint); err != nil {
+	if err := writeSchema(scheduleOutFile, scheduleSchema, format, prettyPrint); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: failed to write schedule schema: %v\n", err)
 		os.Exit(1)
 	}
@@ -65,7 +83,7 @@ func main() {
 	}
 }
 
-func writeSchema(outFile string, schema interface{}, prettyPrint bool) error {
+func writeSchema(outFile string, schema interface{}, format string, prettyPrint bool) error {
 	output := os.Stdout
 	if outFile != "" {
 		if dir := filepath.Dir(outFile); dir != "" {
@@ -86,7 +104,8 @@ func writeSchema(outFile string, schema interface{}, prettyPrint bool) error {
 		output = f
 	}
 
-	enc := json.NewEncoder(output)
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
 	if prettyPrint {
 		enc.SetIndent("", "  ")
 	}
@@ -94,5 +113,16 @@ func writeSchema(outFile string, schema interface{}, prettyPrint bool) error {
 		return fmt.Errorf("encode schema: %w", err)
 	}
 
+	data := buf.Bytes()
+	if format == "yaml" {
+		var err error
+		if data, err = static.SchemaYAML(data); err != nil {
+			return err
+		}
+	}
+	if _, err := output.Write(data); err != nil {
+		return fmt.Errorf("write schema: %w", err)
+	}
+
 	return nil
 }
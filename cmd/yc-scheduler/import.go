@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf8"
+
+	"github.com/sentoz/yc-sheduler/internal/csvimport"
+)
+
+// importCommand groups the commands generating schedule manifests from
+// other formats.
+type importCommand struct{}
+
+// importCSVCommand generates business_hours manifests from a CSV table of
+// office hours.
+type importCSVCommand struct {
+	Out       string `long:"out" default:"schedules" description:"Directory to write the manifests to, or - to print them as one multi-document YAML"`
+	Delimiter string `long:"delimiter" default:"," description:"Field delimiter of the table, e.g. ; for spreadsheets exported with a comma decimal separator"`
+	Force     bool   `long:"force" description:"Overwrite existing manifest files"`
+
+	Args struct {
+		File string `positional-arg-name:"file" description:"CSV file with the resource_id, type, folder, start, stop, days and timezone columns, or - for stdin"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+// Execute parses the table and writes a manifest per row. Nothing is written
+// when any row is invalid.
+func (c *importCSVCommand) Execute([]string) error {
+	delimiter, size := utf8.DecodeRuneInString(c.Delimiter)
+	if size == 0 || size != len(c.Delimiter) {
+		return fmt.Errorf("yc-scheduler import csv: --delimiter must be a single character")
+	}
+
+	var in io.Reader = os.Stdin
+	if c.Args.File != "-" {
+		f, err := os.Open(c.Args.File)
+		if err != nil {
+			return fmt.Errorf("yc-scheduler import csv: %w", err)
+		}
+		defer f.Close()
+		in = f
+	}
+	manifests, err := csvimport.Parse(in, delimiter)
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs := joined.Unwrap()
+		for _, err := range errs {
+			fmt.Printf("%s: %v\n", c.Args.File, err)
+		}
+		return fmt.Errorf("yc-scheduler import csv: %d invalid rows", len(errs))
+	}
+	if err != nil {
+		return fmt.Errorf("yc-scheduler import csv: %s: %w", c.Args.File, err)
+	}
+
+	if c.Out == "-" {
+		for i, m := range manifests {
+			raw, err := csvimport.Marshal(m)
+			if err != nil {
+				return fmt.Errorf("yc-scheduler import csv: %w", err)
+			}
+			if i > 0 {
+				fmt.Println("---")
+			}
+			os.Stdout.Write(raw)
+		}
+		return nil
+	}
+	files, err := csvimport.Write(c.Out, manifests, c.Force)
+	if err != nil {
+		return fmt.Errorf("yc-scheduler import csv: %w", err)
+	}
+	for _, file := range files {
+		fmt.Println("created", file)
+	}
+	return nil
+}
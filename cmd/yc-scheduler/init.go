@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/sentoz/yc-sheduler/internal/scaffold"
+)
+
+// initCommand scaffolds a configuration with example schedule manifests.
+type initCommand struct {
+	Dir      string `long:"dir" default:"." description:"Directory to write config.yaml and schedules/ to"`
+	Timezone string `long:"timezone" default:"Europe/Moscow" description:"Timezone of schedule times"`
+	FolderID string `long:"folder-id" default:"b1g1234567890abcdef" description:"Folder ID of the example resources"`
+	Force    bool   `long:"force" description:"Overwrite existing files"`
+}
+
+// Execute writes the scaffolded files and prints their paths.
+func (c *initCommand) Execute([]string) error {
+	files, err := scaffold.Write(c.Dir, scaffold.Data{Timezone: c.Timezone, FolderID: c.FolderID}, c.Force)
+	if err != nil {
+		return fmt.Errorf("yc-scheduler init: %w", err)
+	}
+	for _, file := range files {
+		fmt.Println("created", file)
+	}
+	return nil
+}
@@ -1,19 +1,13 @@
 package main
 
 import (
-	"context"
 	"fmt"
 	"os"
 
 	"github.com/jessevdk/go-flags"
-	"github.com/rs/zerolog/log"
 
-	"github.com/sentoz/yc-sheduler/internal/app"
-	"github.com/sentoz/yc-sheduler/internal/config"
 	"github.com/sentoz/yc-sheduler/internal/logger"
-	"github.com/sentoz/yc-sheduler/internal/signals"
 	"github.com/sentoz/yc-sheduler/internal/vars"
-	"github.com/sentoz/yc-sheduler/internal/yc"
 )
 
 func main() {
@@ -25,16 +19,70 @@ func main() {
 
 func run() error {
 	var opts struct {
-		Version bool   `long:"version" description:"Print version information and exit"`
-		Config  string `short:"c" long:"config" env:"YC_SHEDULER_CONFIG" description:"Path to configuration file (YAML or JSON)"`
-		Token   string `short:"t" long:"token" env:"YC_TOKEN" description:"Yandex Cloud OAuth/IAM token (discouraged; prefer --sa-key)"`
-		SaKey   string `long:"sa-key" env:"YC_SA_KEY_FILE" description:"Path to Yandex Cloud service account key JSON file (preferred)"`
-		DryRun  bool   `short:"n" long:"dry-run" description:"Dry run mode: log planned actions without calling YC APIs"`
+		Version bool `long:"version" description:"Print version information and exit"`
+
+		serveOptions
 
 		logger.Logger `group:"Logging"`
 	}
 
-	if _, err := flags.Parse(&opts); err != nil {
+	parser := flags.NewParser(&opts, flags.Default)
+	// Without a command the scheduler runs.
+	parser.SubcommandsOptional = true
+	if _, err := parser.AddCommand("serve", "Run the scheduler",
+		"Load the config and schedules and run the scheduler with its HTTP server until it is stopped by a signal. This is the default when no command is given, so yc-scheduler -c config.yaml keeps working.",
+		&serveCommand{opts: &opts.serveOptions, logger: &opts.Logger}); err != nil {
+		return err
+	}
+	if _, err := parser.AddCommand("init", "Scaffold a configuration",
+		"Write config.yaml and a schedules/ directory with example manifests for a VM, a Kubernetes cluster and a node group.",
+		&initCommand{}); err != nil {
+		return err
+	}
+	if _, err := parser.AddCommand("new-schedule", "Scaffold a schedule manifest",
+		"Print or write a business_hours manifest keeping a resource running in a window, e.g. --type vm --id fhm1234567890abcdef --folder b1g1234567890abcdef --start 09:00 --stop 19:00. With --interactive the values not given as flags are asked for. The manifest is checked against the schedule schema.",
+		&newScheduleCommand{}); err != nil {
+		return err
+	}
+	if _, err := parser.AddCommand("validate", "Validate a configuration",
+		"Load the config and all schedule manifests, check them against the schemas and parse every cron and time expression without contacting Yandex Cloud. Errors of every file are printed and the exit code is non-zero when there are any.",
+		&validateCommand{}); err != nil {
+		return err
+	}
+	if _, err := parser.AddCommand("plan", "Print upcoming runs",
+		"Load the config and schedules like the scheduler does and print every job with its next runs in the configured timezone and the action it takes, without contacting Yandex Cloud.",
+		&planCommand{}); err != nil {
+		return err
+	}
+	if _, err := parser.AddCommand("run", "Run a schedule action now",
+		"Run one action of a schedule right away for all its resources, e.g. --schedule vm-nightly --action stop, honoring --dry-run and the denied resources of the config. Schedule conditions such as the active period, pauses and blackout windows are not checked.",
+		&runCommand{logger: &opts.Logger}); err != nil {
+		return err
+	}
+	if _, err := parser.AddCommand("resources", "List resources of a folder",
+		"List the VMs, Kubernetes clusters and node groups of a folder with their IDs, names, current states and labels, e.g. to fill in the resources of schedule manifests.",
+		&resourcesCommand{}); err != nil {
+		return err
+	}
+	importCmd, err := parser.AddCommand("import", "Generate schedules from other formats",
+		"Generate schedule manifests from data kept outside of yc-scheduler.",
+		&importCommand{})
+	if err != nil {
+		return err
+	}
+	importCmd.SubcommandsOptional = false
+	if _, err := importCmd.AddCommand("csv", "Generate schedules from a CSV table",
+		"Generate a business_hours manifest per row of a CSV table of office hours with the resource_id, type, folder, start, stop, days and timezone columns, e.g. exported from a capacity planning spreadsheet. Columns are matched by the header row; days and timezone are optional.",
+		&importCSVCommand{}); err != nil {
+		return err
+	}
+	if _, err := parser.AddCommand("version", "Print version information",
+		"Print the version, commit and build time of the binary, like --version.",
+		&versionCommand{}); err != nil {
+		return err
+	}
+
+	if _, err := parser.Parse(); err != nil {
 		// go-flags returns an error even for --help; in that case do not treat
 		// it as a failure exit code.
 		if ferr, ok := err.(*flags.Error); ok && ferr.Type == flags.ErrHelp {
@@ -42,6 +90,10 @@ func run() error {
 		}
 		return err
 	}
+	if parser.Active != nil {
+		// The command has been executed by the parser.
+		return nil
+	}
 
 	// Handle --version flag
 	if opts.Version {
@@ -49,59 +101,6 @@ func run() error {
 		return nil
 	}
 
-	// Validate that config is provided when not using --version
-	if opts.Config == "" {
-		return fmt.Errorf("--config is required")
-	}
-
-	opts.Setup()
-
-	log.Debug().
-		Str("config_path", opts.Config).
-		Bool("dry_run", opts.DryRun).
-		Msg("CLI options parsed")
-
-	cfg, err := config.Load(context.Background(), opts.Config)
-	if err != nil {
-		return fmt.Errorf("yc-scheduler: load config: %w", err)
-	}
-
-	ctx, cancel := signals.WithSignalContext(context.Background())
-	defer cancel()
-
-	auth := yc.AuthConfig{
-		ServiceAccountKeyFile: opts.SaKey,
-		Token:                 opts.Token,
-	}
-
-	client, err := yc.NewClient(ctx, auth)
-	if err != nil {
-		return fmt.Errorf("yc-scheduler: create YC client: %w", err)
-	}
-
-	// Validate credentials before proceeding
-	log.Info().Msg("Validating Yandex Cloud credentials")
-	if err := client.ValidateCredentials(ctx); err != nil {
-		return fmt.Errorf("yc-scheduler: credentials validation failed: %w", err)
-	}
-	log.Info().Msg("Credentials validated successfully")
-
-	defer signals.GracefulShutdown(client, cfg.ShutdownTimeout.Std())
-
-	// Create and initialize application
-	application, err := app.New(cfg, client, opts.DryRun)
-	if err != nil {
-		return fmt.Errorf("yc-scheduler: create app: %w", err)
-	}
-
-	defer func() {
-		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout.Std())
-		defer shutdownCancel()
-		if err := application.Shutdown(shutdownCtx); err != nil {
-			log.Warn().Err(err).Msg("Failed to shutdown application gracefully")
-		}
-	}()
-
-	// Run application
-	return application.Run(ctx)
+	// Without a command the scheduler runs like the serve command.
+	return (&serveCommand{opts: &opts.serveOptions, logger: &opts.Logger}).Execute(nil)
 }
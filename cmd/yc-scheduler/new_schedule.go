@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/sentoz/yc-sheduler/internal/csvimport"
+)
+
+// newScheduleCommand scaffolds a business_hours manifest for one resource.
+type newScheduleCommand struct {
+	Name     string `long:"name" description:"Name of the schedule; defaults to <type>-<id>"`
+	Type     string `long:"type" description:"Resource type, e.g. vm, k8s_cluster or k8s_node_group"`
+	ID       string `long:"id" description:"ID of the resource"`
+	Folder   string `long:"folder" description:"ID of the folder of the resource"`
+	Start    string `long:"start" description:"Time to start the resource, e.g. 09:00"`
+	Stop     string `long:"stop" description:"Time to stop the resource, e.g. 19:00"`
+	Days     string `long:"days" description:"Days of the week as numbers (0=Sunday), names or ranges like mon-fri; defaults to Monday to Friday"`
+	Timezone string `long:"timezone" description:"Timezone of the times; defaults to the timezone of the config"`
+	Out      string `long:"out" default:"-" description:"Directory to write the manifest to, or - to print it"`
+	Force    bool   `long:"force" description:"Overwrite an existing manifest file"`
+
+	Interactive bool `short:"i" long:"interactive" description:"Ask for the values not given as flags"`
+}
+
+// prompt is a value asked for in interactive mode when its flag is not set.
+type prompt struct {
+	flag, question, fallback string
+	value                    *string
+	optional                 bool
+}
+
+// Execute builds the manifest from the flags, asking for missing values in
+// interactive mode, and prints or writes it.
+func (c *newScheduleCommand) Execute([]string) error {
+	prompts := []prompt{
+		{flag: "type", question: "Resource type", fallback: "vm", value: &c.Type},
+		{flag: "id", question: "Resource ID", value: &c.ID},
+		{flag: "folder", question: "Folder ID", value: &c.Folder},
+		{flag: "start", question: "Start time", fallback: "09:00", value: &c.Start},
+		{flag: "stop", question: "Stop time", fallback: "19:00", value: &c.Stop},
+		{flag: "days", question: "Days", fallback: "mon-fri", value: &c.Days, optional: true},
+		{flag: "timezone", question: "Timezone (empty for the config timezone)", value: &c.Timezone, optional: true},
+	}
+	if c.Interactive {
+		if err := ask(bufio.NewReader(os.Stdin), os.Stderr, prompts); err != nil {
+			return fmt.Errorf("yc-scheduler new-schedule: %w", err)
+		}
+	}
+	var missing []string
+	for _, p := range prompts {
+		if !p.optional && *p.value == "" {
+			missing = append(missing, "--"+p.flag)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("yc-scheduler new-schedule: missing %s", strings.Join(missing, ", "))
+	}
+
+	manifest, err := csvimport.BusinessHours(csvimport.Row{
+		ResourceID: c.ID,
+		Type:       c.Type,
+		Folder:     c.Folder,
+		Start:      c.Start,
+		Stop:       c.Stop,
+		Days:       c.Days,
+		Timezone:   c.Timezone,
+	})
+	if err != nil {
+		return fmt.Errorf("yc-scheduler new-schedule: %w", err)
+	}
+	if c.Name != "" {
+		manifest.Metadata.Name = c.Name
+	}
+	m := csvimport.Manifest{Manifest: manifest}
+
+	if c.Out == "-" {
+		raw, err := csvimport.Marshal(m)
+		if err != nil {
+			return fmt.Errorf("yc-scheduler new-schedule: %w", err)
+		}
+		_, err = os.Stdout.Write(raw)
+		return err
+	}
+	files, err := csvimport.Write(c.Out, []csvimport.Manifest{m}, c.Force)
+	if err != nil {
+		return fmt.Errorf("yc-scheduler new-schedule: %w", err)
+	}
+	for _, file := range files {
+		fmt.Println("created", file)
+	}
+	return nil
+}
+
+// ask asks for the values of prompts whose flags are not set. An empty
+// answer takes the fallback; required values are asked for until given.
+func ask(in *bufio.Reader, out io.Writer, prompts []prompt) error {
+	for _, p := range prompts {
+		for *p.value == "" {
+			if p.fallback != "" {
+				fmt.Fprintf(out, "%s [%s]: ", p.question, p.fallback)
+			} else {
+				fmt.Fprintf(out, "%s: ", p.question)
+			}
+			answer, err := in.ReadString('\n')
+			if err != nil && (err != io.EOF || answer == "") {
+				return fmt.Errorf("read %s: %w", p.flag, err)
+			}
+			*p.value = strings.TrimSpace(answer)
+			if *p.value == "" {
+				*p.value = p.fallback
+			}
+			if p.optional {
+				break
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/scheduler"
+)
+
+// planTimeFormat formats run times in the plan table.
+const planTimeFormat = "Mon 2006-01-02 15:04 MST"
+
+// planCommand prints the jobs of a configuration with their upcoming runs
+// without contacting Yandex Cloud.
+type planCommand struct {
+	Config string `short:"c" long:"config" env:"YC_SHEDULER_CONFIG" required:"true" description:"Path to configuration file (YAML or JSON) or HTTP(S) URL serving it"`
+	Runs   int    `long:"runs" default:"3" description:"Number of upcoming runs of every job"`
+}
+
+// Execute loads the schedules like the scheduler does and prints every job
+// with its upcoming runs in the configured timezone.
+func (c *planCommand) Execute([]string) error {
+	if c.Runs < 1 {
+		return fmt.Errorf("yc-scheduler plan: --runs must be positive")
+	}
+	// Progress messages of loading would interleave with the table.
+	zerolog.SetGlobalLevel(zerolog.WarnLevel)
+
+	cfg, err := config.Load(context.Background(), c.Config)
+	if err != nil {
+		return fmt.Errorf("yc-scheduler plan: load config: %w", err)
+	}
+	jobs, err := scheduler.Plan(cfg.Schedules, cfg.Timezone.String(), time.Now(), c.Runs)
+	if err != nil {
+		return fmt.Errorf("yc-scheduler plan: %w", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "JOB\tACTION\tRESOURCES\tRUN")
+	failed := 0
+	for _, job := range jobs {
+		resources := make([]string, 0, len(job.Resources))
+		for _, r := range job.Resources {
+			resources = append(resources, r.Type+"/"+r.Identifier())
+		}
+
+		var runs []string
+		for _, at := range job.Runs {
+			runs = append(runs, at.Format(planTimeFormat))
+		}
+		switch {
+		case job.Err != nil:
+			failed++
+			runs = append(runs, "error: "+job.Err.Error())
+		case len(runs) == 0:
+			runs = append(runs, "no upcoming runs")
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", job.Job, job.Action, strings.Join(resources, ","), runs[0])
+		for _, run := range runs[1:] {
+			fmt.Fprintf(w, "\t\t\t%s\n", run)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("yc-scheduler plan: runs of %d jobs cannot be computed", failed)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/sentoz/yc-sheduler/internal/resource"
+	"github.com/sentoz/yc-sheduler/internal/signals"
+	"github.com/sentoz/yc-sheduler/internal/yc"
+)
+
+// resourcesShutdownTimeout bounds closing the client; there is no config to
+// take the shutdown timeout from.
+const resourcesShutdownTimeout = 10 * time.Second
+
+// resourcesCommand lists the resources of a folder that schedules can
+// target.
+type resourcesCommand struct {
+	Folder string `long:"folder" required:"true" description:"ID of the folder to list"`
+	Type   string `long:"type" choice:"vm" choice:"k8s_cluster" choice:"k8s_node_group" description:"List only resources of the type"`
+	Token  string `short:"t" long:"token" env:"YC_TOKEN" description:"Yandex Cloud OAuth/IAM token (discouraged; prefer --sa-key)"`
+	SaKey  string `long:"sa-key" env:"YC_SA_KEY_FILE" description:"Path to Yandex Cloud service account key JSON file (preferred)"`
+}
+
+// Execute prints the VMs, Kubernetes clusters and node groups of the folder
+// with their IDs, names, states and labels.
+func (c *resourcesCommand) Execute([]string) error {
+	// Progress messages of the client would interleave with the table.
+	zerolog.SetGlobalLevel(zerolog.WarnLevel)
+
+	ctx, cancel := signals.WithSignalContext(context.Background())
+	defer cancel()
+
+	client, err := yc.NewClient(ctx, yc.AuthConfig{
+		ServiceAccountKeyFile: c.SaKey,
+		Token:                 c.Token,
+	}, yc.ClientOptions{})
+	if err != nil {
+		return fmt.Errorf("yc-scheduler resources: create YC client: %w", err)
+	}
+	defer signals.GracefulShutdown(client, resourcesShutdownTimeout)
+	if err := client.ValidateCredentials(ctx); err != nil {
+		return fmt.Errorf("yc-scheduler resources: credentials validation failed: %w", err)
+	}
+
+	resources, err := resource.ListFolder(ctx, client, c.Folder)
+	if err != nil {
+		return fmt.Errorf("yc-scheduler resources: %w", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TYPE\tID\tNAME\tSTATE\tLABELS")
+	for _, r := range resources {
+		if c.Type != "" && r.Type != c.Type {
+			continue
+		}
+		labels := make([]string, 0, len(r.Labels))
+		for _, key := range slices.Sorted(maps.Keys(r.Labels)) {
+			labels = append(labels, key+"="+r.Labels[key])
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.Type, r.ID, r.Name, r.State, strings.Join(labels, ","))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/sentoz/yc-sheduler/internal/app"
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/logger"
+	"github.com/sentoz/yc-sheduler/internal/signals"
+	"github.com/sentoz/yc-sheduler/internal/yc"
+)
+
+// runCommand runs one action of a schedule right away.
+type runCommand struct {
+	Config   string `short:"c" long:"config" env:"YC_SHEDULER_CONFIG" required:"true" description:"Path to configuration file (YAML or JSON) or HTTP(S) URL serving it"`
+	Token    string `short:"t" long:"token" env:"YC_TOKEN" description:"Yandex Cloud OAuth/IAM token (discouraged; prefer --sa-key)"`
+	SaKey    string `long:"sa-key" env:"YC_SA_KEY_FILE" description:"Path to Yandex Cloud service account key JSON file (preferred)"`
+	DryRun   bool   `short:"n" long:"dry-run" description:"Dry run mode: log planned actions without calling YC APIs"`
+	Schedule string `long:"schedule" required:"true" description:"Name of the schedule"`
+	Action   string `long:"action" required:"true" description:"Action of the schedule, e.g. stop or scale:1 for one of several scale entries"`
+
+	// logger is the logging configuration of the global options.
+	logger *logger.Logger
+}
+
+// Execute runs the action for every resource of the schedule and prints the
+// outcome per resource. It fails when the action failed for any of them.
+func (c *runCommand) Execute([]string) error {
+	c.logger.Setup()
+
+	cfg, err := config.Load(context.Background(), c.Config)
+	if err != nil {
+		return fmt.Errorf("yc-scheduler run: load config: %w", err)
+	}
+	sch, action, err := app.ScheduleAction(cfg, c.Schedule, c.Action)
+	if err != nil {
+		return fmt.Errorf("yc-scheduler run: %w", err)
+	}
+
+	ctx, cancel := signals.WithSignalContext(context.Background())
+	defer cancel()
+
+	client, err := yc.NewClient(ctx, yc.AuthConfig{
+		ServiceAccountKeyFile: c.SaKey,
+		Token:                 c.Token,
+	}, yc.ClientOptions{Compression: cfg.APICompression})
+	if err != nil {
+		return fmt.Errorf("yc-scheduler run: create YC client: %w", err)
+	}
+	defer signals.GracefulShutdown(client, cfg.ShutdownTimeout.Std())
+	if err := client.ValidateCredentials(ctx); err != nil {
+		return fmt.Errorf("yc-scheduler run: credentials validation failed: %w", err)
+	}
+
+	log.Info().
+		Str("schedule", c.Schedule).
+		Str("action", c.Action).
+		Bool("dry_run", c.DryRun).
+		Msg("Running schedule action")
+	report := app.RunAction(cfg, client, sch, action, c.DryRun)
+
+	for _, outcome := range report.Resources {
+		result := "ok"
+		if !outcome.OK {
+			result = "failed"
+		}
+		fmt.Printf("%s/%s: %s\n", outcome.Type, outcome.ID, result)
+	}
+	if !report.OK() {
+		return fmt.Errorf("yc-scheduler run: action %s of schedule %q failed (%s)", c.Action, c.Schedule, report.Status())
+	}
+	return nil
+}
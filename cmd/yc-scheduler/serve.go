@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+
+	"github.com/sentoz/yc-sheduler/internal/app"
+	"github.com/sentoz/yc-sheduler/internal/chaos"
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/logger"
+	"github.com/sentoz/yc-sheduler/internal/notify"
+	"github.com/sentoz/yc-sheduler/internal/signals"
+	"github.com/sentoz/yc-sheduler/internal/soak"
+	"github.com/sentoz/yc-sheduler/internal/source"
+	"github.com/sentoz/yc-sheduler/internal/vars"
+	"github.com/sentoz/yc-sheduler/internal/yc"
+)
+
+// serveOptions are the options of the scheduler. They are global options so
+// that running the binary without a command keeps starting the scheduler.
+type serveOptions struct {
+	Config string `short:"c" long:"config" env:"YC_SHEDULER_CONFIG" description:"Path to configuration file (YAML or JSON) or HTTP(S) URL serving it"`
+	Token  string `short:"t" long:"token" env:"YC_TOKEN" description:"Yandex Cloud OAuth/IAM token (discouraged; prefer --sa-key)"`
+	SaKey  string `long:"sa-key" env:"YC_SA_KEY_FILE" description:"Path to Yandex Cloud service account key JSON file (preferred)"`
+	DryRun bool   `short:"n" long:"dry-run" description:"Dry run mode: log planned actions without calling YC APIs"`
+
+	OperatorToken string `long:"operator-token" env:"YC_SHEDULER_OPERATOR_TOKEN" description:"Bearer token of operators; enables operator-only HTTP endpoints"`
+
+	// Developer soak-test mode, hidden from help.
+	FakeProvider bool `long:"fake-provider" hidden:"true" description:"Run against an in-process fake YC API seeded with the scheduled resources"`
+	Accelerate   int  `long:"accelerate" hidden:"true" description:"Run the clock N times faster; requires --fake-provider"`
+
+	// Developer failure injection, hidden from help.
+	Chaos chaos.Config `group:"Failure injection" hidden:"true"`
+}
+
+// serveCommand runs the scheduler until it is stopped by a signal.
+type serveCommand struct {
+	// opts are the scheduler options of the global options.
+	opts *serveOptions
+	// logger is the logging configuration of the global options.
+	logger *logger.Logger
+}
+
+// Execute loads the config, connects to Yandex Cloud and runs the scheduler
+// with its HTTP server until the context is canceled by a signal.
+func (c *serveCommand) Execute([]string) error {
+	if c.opts.Config == "" {
+		return fmt.Errorf("--config is required")
+	}
+	if c.opts.Accelerate < 0 {
+		return fmt.Errorf("--accelerate must be positive")
+	}
+	if c.opts.Accelerate > 1 && !c.opts.FakeProvider {
+		return fmt.Errorf("--accelerate works only with --fake-provider")
+	}
+	if err := c.opts.Chaos.Validate(); err != nil {
+		return err
+	}
+
+	c.logger.Setup()
+
+	log.Info().
+		Str("version", vars.Version).
+		Str("commit", vars.Commit).
+		Time("build_time", vars.BuildTime).
+		Msg("Starting yc-scheduler")
+
+	log.Debug().
+		Str("config_path", source.Redact(c.opts.Config)).
+		Bool("dry_run", c.opts.DryRun).
+		Msg("CLI options parsed")
+
+	cfg, err := config.Load(context.Background(), c.opts.Config)
+	if err != nil {
+		return fmt.Errorf("yc-scheduler: load config: %w", err)
+	}
+
+	notifier := notify.New(cfg.Notifications)
+
+	ctx, cancel := signals.WithSignalContext(context.Background())
+	defer cancel()
+
+	auth := yc.AuthConfig{
+		ServiceAccountKeyFile: c.opts.SaKey,
+		Token:                 c.opts.Token,
+	}
+	clientOpts := yc.ClientOptions{
+		Compression: cfg.APICompression,
+	}
+
+	var chaosInjector *chaos.Injector
+	if c.opts.Chaos.Enabled() {
+		chaosInjector = chaos.New(c.opts.Chaos)
+		log.Warn().
+			Float64("error_rate", c.opts.Chaos.ErrorRate).
+			Float64("slow_rate", c.opts.Chaos.SlowRate).
+			Float64("invalid_state_rate", c.opts.Chaos.InvalidStateRate).
+			Msg("Failure injection is enabled")
+	}
+
+	var clock clockwork.Clock = clockwork.NewRealClock()
+	if c.opts.FakeProvider {
+		var interceptors []grpc.UnaryServerInterceptor
+		if chaosInjector != nil {
+			interceptors = append(interceptors, chaosInjector.UnaryServerInterceptor())
+		}
+		stub, err := soak.StartProvider(cfg.Schedules, interceptors...)
+		if err != nil {
+			return fmt.Errorf("yc-scheduler: %w", err)
+		}
+		defer stub.Close()
+
+		auth = yc.AuthConfig{}
+		clientOpts.Endpoint = stub.Addr()
+		clientOpts.Plaintext = true
+
+		if c.opts.Accelerate > 1 {
+			accelerated := soak.NewClock(c.opts.Accelerate)
+			clock = accelerated
+			// Log timestamps follow the accelerated clock.
+			zerolog.TimestampFunc = accelerated.Now
+			log.Warn().
+				Int("factor", c.opts.Accelerate).
+				Msg("Soak mode: clock is accelerated")
+		}
+	}
+
+	if chaosInjector != nil && !c.opts.FakeProvider {
+		// The fake provider injects failures itself.
+		clientOpts.Interceptors = append(clientOpts.Interceptors, chaosInjector.UnaryClientInterceptor())
+	}
+
+	client, err := yc.NewClient(ctx, auth, clientOpts)
+	if err != nil {
+		err = fmt.Errorf("yc-scheduler: create YC client: %w", err)
+		notify.Send(notifier, notify.EventStartFailed, err)
+		return err
+	}
+
+	// Validate credentials before proceeding
+	log.Info().Msg("Validating Yandex Cloud credentials")
+	if err := client.ValidateCredentials(ctx); err != nil {
+		err = fmt.Errorf("yc-scheduler: credentials validation failed: %w", err)
+		notify.Send(notifier, notify.EventStartFailed, err)
+		return err
+	}
+	log.Info().Msg("Credentials validated successfully")
+
+	defer signals.GracefulShutdown(client, cfg.ShutdownTimeout.Std())
+
+	// Create and initialize application
+	application, err := app.New(cfg, client, notifier, c.opts.DryRun, c.opts.OperatorToken, clock)
+	if err != nil {
+		err = fmt.Errorf("yc-scheduler: create app: %w", err)
+		notify.Send(notifier, notify.EventStartFailed, err)
+		return err
+	}
+	if err := application.WatchConfig(c.opts.Config); err != nil {
+		err = fmt.Errorf("yc-scheduler: %w", err)
+		notify.Send(notifier, notify.EventStartFailed, err)
+		return err
+	}
+
+	defer func() {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout.Std())
+		defer shutdownCancel()
+		if err := application.Shutdown(shutdownCtx); err != nil {
+			log.Warn().Err(err).Msg("Failed to shutdown application gracefully")
+		}
+	}()
+
+	// Run application
+	return application.Run(ctx)
+}
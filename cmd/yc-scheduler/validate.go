@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/scheduler"
+	"github.com/sentoz/yc-sheduler/internal/source"
+)
+
+// validateCommand checks a configuration and its schedules without
+// contacting Yandex Cloud.
+type validateCommand struct {
+	Config string `short:"c" long:"config" env:"YC_SHEDULER_CONFIG" required:"true" description:"Path to configuration file (YAML or JSON) or HTTP(S) URL serving it"`
+}
+
+// Execute prints the errors of every file and fails when there are any.
+func (c *validateCommand) Execute([]string) error {
+	result := config.Check(context.Background(), c.Config)
+	errs := result.Errors
+	if cfg := result.Config; cfg != nil {
+		scheduleErrs, err := scheduler.CheckSchedules(cfg.Schedules, cfg.Timezone.String(), time.Now())
+		if err != nil {
+			errs = append(errs, config.FileError{File: source.Redact(c.Config), Err: err})
+		}
+		for _, err := range scheduleErrs {
+			file, ok := result.Sources[err.Schedule]
+			if !ok {
+				file = source.Redact(c.Config)
+			}
+			errs = append(errs, config.FileError{File: file, Err: err})
+		}
+	}
+
+	if len(errs) > 0 {
+		slices.SortStableFunc(errs, func(a, b config.FileError) int {
+			return strings.Compare(a.File, b.File)
+		})
+		for _, err := range errs {
+			fmt.Println(err)
+		}
+		return fmt.Errorf("yc-scheduler validate: %d errors found", len(errs))
+	}
+
+	fmt.Printf("%s: %d schedules in %d files are valid\n",
+		source.Redact(c.Config), len(result.Config.Schedules), result.Files)
+	if result.Config.HasRemoteSchedules() {
+		fmt.Println("schedules of schedules_source, schedules_url, schedules_git and schedules_kubernetes are not checked")
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package main
+
+import "github.com/sentoz/yc-sheduler/internal/vars"
+
+// versionCommand prints build information like the --version flag.
+type versionCommand struct{}
+
+// Execute prints the version, commit and build time of the binary.
+func (c *versionCommand) Execute([]string) error {
+	vars.Print()
+	return nil
+}
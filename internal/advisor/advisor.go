@@ -0,0 +1,245 @@
+// Package advisor suggests tighter schedule windows by comparing VM
+// activity history from Monitoring with configured start and stop times.
+package advisor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/schedule"
+	"github.com/sentoz/yc-sheduler/internal/yc"
+)
+
+const (
+	// DefaultDays is the default activity history analyzed, in days.
+	DefaultDays = 30
+
+	// activityThreshold is the CPU utilization (percent) above which a VM
+	// is considered in use.
+	activityThreshold = 10.0
+
+	// sampleStep is the resolution of the CPU utilization history.
+	sampleStep = 10 * time.Minute
+
+	// margin is kept between the observed activity and a suggested time.
+	margin = 30 * time.Minute
+
+	// rounding aligns suggested times to readable clock values.
+	rounding = 30 * time.Minute
+
+	// minSaving is the smallest window change worth suggesting.
+	minSaving = time.Hour
+
+	// minActiveDays is the number of days with activity required before a
+	// suggestion is made.
+	minActiveDays = 7
+)
+
+// ActivityReader reads VM CPU utilization history.
+type ActivityReader interface {
+	ReadCPUUtilization(ctx context.Context, folderID, instanceID string, from, to time.Time, step time.Duration) ([]yc.MetricPoint, error)
+}
+
+// Suggestion proposes moving a scheduled action to a different time.
+type Suggestion struct {
+	Schedule      string `json:"schedule"`
+	ResourceID    string `json:"resource_id"`
+	Action        string `json:"action"`
+	CurrentTime   string `json:"current_time"`
+	SuggestedTime string `json:"suggested_time"`
+	Message       string `json:"message"`
+	ActiveDays    int    `json:"active_days"`
+
+	// observed is the earliest (start) or latest (stop) activity seen.
+	observed time.Duration
+}
+
+// Advisor analyzes daily VM schedules against CPU activity.
+type Advisor struct {
+	reader   ActivityReader
+	location *time.Location
+	now      func() time.Time
+}
+
+// New creates an Advisor reading activity with reader and interpreting
+// schedule times in location.
+func New(reader ActivityReader, location *time.Location) *Advisor {
+	if location == nil {
+		location = time.UTC
+	}
+	return &Advisor{
+		reader:   reader,
+		location: location,
+		now:      time.Now,
+	}
+}
+
+// Suggest returns suggestions for daily schedules of vm resources based on
+// the last days of activity. Resources whose history cannot be read are
+// skipped.
+func (a *Advisor) Suggest(ctx context.Context, schedules []config.Schedule, days int) []Suggestion {
+	if days <= 0 {
+		days = DefaultDays
+	}
+	to := a.now()
+	from := to.AddDate(0, 0, -days)
+
+	var suggestions []Suggestion
+	for _, sch := range schedules {
+		if sch.Type != "daily" {
+			continue
+		}
+		w, ok := windowFor(sch.Actions)
+		if !ok {
+			continue
+		}
+		for _, target := range sch.Targets() {
+			if target.Type != "vm" || target.IsPattern() {
+				continue
+			}
+			points, err := a.reader.ReadCPUUtilization(ctx, target.FolderID, target.ID, from, to, sampleStep)
+			if err != nil {
+				log.Warn().
+					Err(err).
+					Str("schedule", sch.Name).
+					Str("resource_type", target.Type).
+					Str("resource_id", target.ID).
+					Msg("Failed to read resource activity")
+				continue
+			}
+			for _, s := range w.suggest(points, a.location) {
+				s.Schedule = sch.Name
+				s.ResourceID = target.ID
+				s.Message = s.message(days)
+				suggestions = append(suggestions, s)
+			}
+		}
+	}
+	return suggestions
+}
+
+// window is the part of the day in which a VM is scheduled to run.
+type window struct {
+	start, stop       time.Duration
+	hasStart, hasStop bool
+}
+
+// windowFor returns the running window of enabled start and stop actions.
+// Windows crossing midnight are not analyzed.
+func windowFor(actions config.Actions) (window, bool) {
+	var w window
+	if a := actions.Start; a != nil && a.Enabled {
+		if at, err := schedule.ParseTimeOfDay(a.Time); err == nil {
+			w.start, w.hasStart = at, true
+		}
+	}
+	if a := actions.Stop; a != nil && a.Enabled {
+		if at, err := schedule.ParseTimeOfDay(a.Time); err == nil {
+			w.stop, w.hasStop = at, true
+		}
+	}
+	if !w.hasStart && !w.hasStop {
+		return w, false
+	}
+	if !w.hasStop {
+		w.stop = 24 * time.Hour
+	}
+	if w.hasStart && w.hasStop && w.start >= w.stop {
+		return w, false
+	}
+	return w, true
+}
+
+// suggest finds the earliest and latest activity inside the window on each
+// day and proposes tighter start and stop times.
+func (w window) suggest(points []yc.MetricPoint, loc *time.Location) []Suggestion {
+	type bounds struct{ first, last time.Duration }
+	days := make(map[string]bounds)
+	for _, p := range points {
+		if p.Value < activityThreshold {
+			continue
+		}
+		t := p.Time.In(loc)
+		offset := sinceMidnight(t)
+		if offset < w.start || offset >= w.stop {
+			continue
+		}
+		// A sample covers activity until the end of its interval.
+		end := min(offset+sampleStep, w.stop)
+		day := t.Format(time.DateOnly)
+		b, ok := days[day]
+		if !ok {
+			days[day] = bounds{first: offset, last: end}
+			continue
+		}
+		b.first = min(b.first, offset)
+		b.last = max(b.last, end)
+		days[day] = b
+	}
+	if len(days) < minActiveDays {
+		return nil
+	}
+
+	first, last := w.stop, w.start
+	for _, b := range days {
+		first = min(first, b.first)
+		last = max(last, b.last)
+	}
+
+	var suggestions []Suggestion
+	if w.hasStart {
+		suggested := (first - margin).Truncate(rounding)
+		if suggested-w.start >= minSaving {
+			suggestions = append(suggestions, Suggestion{
+				Action:        "start",
+				CurrentTime:   clock(w.start),
+				SuggestedTime: clock(suggested),
+				ActiveDays:    len(days),
+				observed:      first,
+			})
+		}
+	}
+	if w.hasStop {
+		suggested := roundUp(last+margin, rounding)
+		if w.stop-suggested >= minSaving {
+			suggestions = append(suggestions, Suggestion{
+				Action:        "stop",
+				CurrentTime:   clock(w.stop),
+				SuggestedTime: clock(suggested),
+				ActiveDays:    len(days),
+				observed:      last,
+			})
+		}
+	}
+	return suggestions
+}
+
+// message explains the suggestion in human-readable form.
+func (s Suggestion) message(days int) string {
+	if s.Action == "start" {
+		return fmt.Sprintf("VM %s was never active before %s in the last %d days; consider moving start from %s to %s",
+			s.ResourceID, clock(s.observed), days, s.CurrentTime, s.SuggestedTime)
+	}
+	return fmt.Sprintf("VM %s was never active after %s in the last %d days; consider moving stop from %s to %s",
+		s.ResourceID, clock(s.observed), days, s.CurrentTime, s.SuggestedTime)
+}
+
+func sinceMidnight(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+}
+
+func roundUp(d, m time.Duration) time.Duration {
+	if r := d.Truncate(m); r != d {
+		return r + m
+	}
+	return d
+}
+
+// clock formats an offset from midnight as HH:MM.
+func clock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
@@ -0,0 +1,92 @@
+package advisor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/yc"
+)
+
+type fakeReader struct {
+	points []yc.MetricPoint
+}
+
+func (f fakeReader) ReadCPUUtilization(context.Context, string, string, time.Time, time.Time, time.Duration) ([]yc.MetricPoint, error) {
+	return f.points, nil
+}
+
+// workdayActivity returns busy samples between from and to (clock offsets)
+// on each of days consecutive days, with idle samples around them.
+func workdayActivity(days int, from, to time.Duration) []yc.MetricPoint {
+	base := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	var points []yc.MetricPoint
+	for d := range days {
+		day := base.AddDate(0, 0, d)
+		for offset := time.Duration(0); offset < 24*time.Hour; offset += sampleStep {
+			value := 2.0
+			if offset >= from && offset < to {
+				value = 55
+			}
+			points = append(points, yc.MetricPoint{Time: day.Add(offset), Value: value})
+		}
+	}
+	return points
+}
+
+func vmSchedule(start, stop string) config.Schedule {
+	return config.Schedule{
+		Name: "dev-vm",
+		Type: "daily",
+		Actions: config.Actions{
+			Start: &config.ActionConfig{Time: start, Enabled: true},
+			Stop:  &config.ActionConfig{Time: stop, Enabled: true},
+		},
+		Resource: config.Resource{Type: "vm", ID: "vm-1", FolderID: "folder-1"},
+	}
+}
+
+func TestSuggestTightensWindow(t *testing.T) {
+	t.Parallel()
+
+	reader := fakeReader{points: workdayActivity(10, 10*time.Hour, 17*time.Hour+30*time.Minute)}
+	a := New(reader, time.UTC)
+
+	got := a.Suggest(context.Background(), []config.Schedule{vmSchedule("07:00", "20:00")}, 30)
+	if len(got) != 2 {
+		t.Fatalf("Suggest() returned %d suggestions, want 2: %+v", len(got), got)
+	}
+	if got[0].Action != "start" || got[0].SuggestedTime != "09:30" {
+		t.Fatalf("start suggestion = %+v, want 09:30", got[0])
+	}
+	if got[1].Action != "stop" || got[1].SuggestedTime != "18:00" {
+		t.Fatalf("stop suggestion = %+v, want 18:00", got[1])
+	}
+	want := "VM vm-1 was never active after 17:30 in the last 30 days; consider moving stop from 20:00 to 18:00"
+	if got[1].Message != want {
+		t.Fatalf("message = %q, want %q", got[1].Message, want)
+	}
+}
+
+func TestSuggestSkipsTightWindow(t *testing.T) {
+	t.Parallel()
+
+	reader := fakeReader{points: workdayActivity(10, 9*time.Hour, 18*time.Hour)}
+	a := New(reader, time.UTC)
+
+	if got := a.Suggest(context.Background(), []config.Schedule{vmSchedule("08:30", "19:00")}, 30); len(got) != 0 {
+		t.Fatalf("Suggest() = %+v, want none", got)
+	}
+}
+
+func TestSuggestRequiresEnoughActiveDays(t *testing.T) {
+	t.Parallel()
+
+	reader := fakeReader{points: workdayActivity(minActiveDays-1, 10*time.Hour, 12*time.Hour)}
+	a := New(reader, time.UTC)
+
+	if got := a.Suggest(context.Background(), []config.Schedule{vmSchedule("07:00", "20:00")}, 30); len(got) != 0 {
+		t.Fatalf("Suggest() = %+v, want none", got)
+	}
+}
@@ -2,16 +2,33 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"sync"
 	"time"
 
+	"github.com/jonboulle/clockwork"
 	"github.com/rs/zerolog/log"
 
+	"github.com/sentoz/yc-sheduler/internal/blackout"
 	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/denylist"
+	"github.com/sentoz/yc-sheduler/internal/diff"
+	"github.com/sentoz/yc-sheduler/internal/executor"
+	"github.com/sentoz/yc-sheduler/internal/grace"
+	"github.com/sentoz/yc-sheduler/internal/idle"
+	"github.com/sentoz/yc-sheduler/internal/logger"
 	"github.com/sentoz/yc-sheduler/internal/metrics"
+	"github.com/sentoz/yc-sheduler/internal/notify"
+	"github.com/sentoz/yc-sheduler/internal/pause"
 	"github.com/sentoz/yc-sheduler/internal/reloader"
 	"github.com/sentoz/yc-sheduler/internal/resource"
 	"github.com/sentoz/yc-sheduler/internal/scheduler"
+	"github.com/sentoz/yc-sheduler/internal/scheduleset"
+	"github.com/sentoz/yc-sheduler/internal/source"
+	"github.com/sentoz/yc-sheduler/internal/vacation"
 	"github.com/sentoz/yc-sheduler/internal/validator"
 	"github.com/sentoz/yc-sheduler/internal/web"
 	"github.com/sentoz/yc-sheduler/internal/yc"
@@ -19,88 +36,282 @@ import (
 
 // App represents the main application with all its dependencies.
 type App struct {
+	client         *yc.Client
+	stateChecker   resource.StateChecker
+	labels         labelReader
+	operator       resource.Operator
+	executor       *executor.Executor
+	scheduler      *scheduler.Scheduler
+	validator      *validator.Validator
+	metrics        *metrics.Metrics
+	webServer      *web.Server
+	reloader       *reloader.Reloader
+	configReloader *reloader.Reloader
+	scheduleStore  *ScheduleStore
+	uiProvider     *UIProvider
+	sets           *scheduleset.Store
+	reloadGuard    *reloadGuard
+	deprecations   *deprecationTracker
+	pauses         *pause.Registry
+	denied         *denylist.List
+	blackouts      *blackout.Calendar
+	idlePolicy     *idle.Policy
+	statuses       *statusReporter
+	vacations      *vacation.Manager
+	dryRun         bool
+
+	// reloadMu serializes schedule reloads of the schedules and config
+	// reloaders.
+	reloadMu sync.Mutex
+
+	// mu guards the settings replaced when the config file is reloaded.
+	mu            sync.Mutex
 	cfg           *config.Config
-	client        *yc.Client
-	stateChecker  resource.StateChecker
-	operator      resource.Operator
-	scheduler     *scheduler.Scheduler
-	validator     *validator.Validator
-	metrics       *metrics.Metrics
-	webServer     *web.Server
-	reloader      *reloader.Reloader
-	scheduleStore *ScheduleStore
-	dryRun        bool
+	timezones     *timezoneInferrer
+	notifier      notify.Notifier
+	runCtx        context.Context
+	stopValidator context.CancelFunc
 }
 
-const schedulesReloadInterval = 10 * time.Second
+const (
+	vacationCheckInterval  = time.Minute
+	consistencyInterval    = time.Minute
+	scheduleStatusInterval = 30 * time.Second
+)
 
 // New creates and initializes a new App instance.
-func New(cfg *config.Config, client *yc.Client, dryRun bool) (*App, error) {
+// If notifier is nil, lifecycle notifications are not sent.
+// Scheduled runs and validation are driven by clock. A non-empty
+// operatorToken enables operator-only HTTP endpoints.
+func New(cfg *config.Config, client *yc.Client, notifier notify.Notifier, dryRun bool, operatorToken string, clock clockwork.Clock) (*App, error) {
 	// Initialize metrics if enabled
 	var m *metrics.Metrics
 	if cfg.MetricsEnabled {
 		m = metrics.New()
 	}
 
+	// Scheduled jobs and validator corrections share an executor, so their
+	// operations on a resource never overlap.
+	exec := executor.New()
+	exec.SetDefaultTimeout(cfg.EffectiveActionTimeout())
+	if client != nil {
+		exec.SetUtilizationSnapshots(client, cfg.UtilizationSnapshot)
+	}
+
 	// Create resource state checker and operator
 	stateChecker := resource.NewYCStateChecker(client)
-	operator := resource.NewYCOperator(client)
+
+	// Inferred timezones are applied before the schedules are published.
+	timezones := newTimezoneInferrer(stateChecker, cfg.TimezoneLabel)
+	timezones.Apply(context.Background(), cfg.Schedules)
+
+	deprecations := newDeprecationTracker(m, client.UsesTokenAuth())
+	deprecations.Update(cfg.Schedules)
+
+	// Denied resources are refused by the operator itself, so no caller can
+	// operate on them.
+	denied := denylist.New()
+	denied.ReplaceSource(denylist.SourceConfig, deniedFromConfig(cfg.DeniedResources))
+	exec.SetDenyList(denied)
+	operator := denylist.Guard(resource.NewYCOperator(client), denied)
+
+	// Last actions of the scheduler let the validator attribute state drift.
+	lastActions, err := resource.NewActionStore(cfg.LastActionsFile)
+	if err != nil {
+		return nil, fmt.Errorf("create last actions store: %w", err)
+	}
+	exec.SetLastActions(lastActions)
 
 	// Create scheduler
 	timezone := cfg.Timezone.String()
-	sched, err := scheduler.New(timezone, cfg.MaxConcurrentJobs)
+	sched, err := scheduler.NewWithClock(timezone, cfg.MaxConcurrentJobs, clock)
 	if err != nil {
 		return nil, fmt.Errorf("create scheduler: %w", err)
 	}
+	sched.SetMetrics(m)
+	sched.SetWarmUp(cfg.WarmUp)
+	sched.SetExecutor(exec)
+
+	// Schedule sets are shared by the validator and the API; a reload
+	// publishes a new set to all of them at once.
+	sets := scheduleset.NewStore(cfg.Schedules)
+	if m != nil {
+		m.SetScheduleSetVersion(sets.Load().Version())
+	}
+	setConfigInfo(m, cfg, cfg.Schedules)
 
 	// Create validator
 	val := validator.New(stateChecker, operator, cfg, sched, m, dryRun)
+	val.SetClock(clock)
+	val.SetExecutor(exec)
+	val.SetScheduleSets(sets)
+	val.SetLastActions(lastActions)
+
+	// Schedule pauses are shared by scheduled runs and the validator.
+	pauses := pause.NewRegistry()
+	pauses.ReplaceSource(pause.SourceConfig, pausesFromConfig(cfg.Pauses))
+	sched.SetPauses(pauses)
+	val.SetPauses(pauses)
+
+	scheduleStore := NewScheduleStore(timezone, sets)
+
+	// Namespaces on vacation are stopped, suspended and restored afterwards.
+	vacations := vacation.NewManager(stateChecker, operator, scheduleStore, dryRun)
+	vacations.SetClock(clock)
+	if cfg.VacationsFile != "" {
+		if err := vacations.SetFile(cfg.VacationsFile); err != nil {
+			return nil, fmt.Errorf("load vacations: %w", err)
+		}
+	}
+	for _, v := range vacationsFromConfig(cfg.Vacations) {
+		vacations.Add(v)
+	}
+	sched.SetVacations(vacations)
+	val.SetVacations(vacations)
+
+	// Blackout windows suppress scheduled runs, validation and the idle policy.
+	blackouts, err := blackoutsFromConfig(cfg.BlackoutWindows, timezone)
+	if err != nil {
+		return nil, fmt.Errorf("create blackout windows: %w", err)
+	}
+	sched.SetBlackouts(blackouts)
+	val.SetBlackouts(blackouts)
+
+	// Stops with a grace period are announced and may be postponed.
+	stops := grace.NewRegistry()
+	sched.SetStops(stops)
+	sched.SetNotifier(notifier)
+	val.SetStops(stops)
 
-	scheduleStore := NewScheduleStore(timezone, cfg.Schedules)
 	var scheduleProvider web.ScheduleProvider
+	var uiProvider *UIProvider
 	if cfg.UIEnabled {
-		scheduleProvider = NewUIProvider(scheduleStore, stateChecker, cfg.ValidationInterval.String(), cfg.IsValidationResourcesEnabled())
+		uiProvider = NewUIProvider(scheduleStore, stateChecker, cfg.ValidationInterval.String(), cfg.IsValidationResourcesEnabled())
+		scheduleProvider = uiProvider
+	}
+
+	// Reloads changing too many schedules wait for a confirmation via the
+	// API, which reloads the schedules again.
+	var a *App
+	guard := &reloadGuard{}
+	scheduleSetStatus := scheduleSetProvider{
+		sets:      sets,
+		validator: val,
+		guard:     guard,
+		reload: func(ctx context.Context) error {
+			return a.reloadSchedules(ctx)
+		},
 	}
 
 	// Create web server
-	addr := fmt.Sprintf(":%d", cfg.MetricsPort)
-	webSrv, err := web.NewServer(context.Background(), addr, cfg.MetricsEnabled, scheduleProvider)
+	addrs := cfg.EffectiveListenAddresses()
+	webOpts := web.Options{
+		MetricsEnabled:   cfg.MetricsEnabled,
+		ScheduleProvider: scheduleProvider,
+		Pauses:           pauses,
+		DeniedResources:  denied,
+		Vacations:        vacations,
+		Stops:            stops,
+		ResourceStates:   stateChecker,
+		Deprecations:     deprecations,
+		Consistency:      sched,
+		Stats:            statsProvider{store: scheduleStore, runs: sched, pauses: pauses},
+		ScheduleSet:      scheduleSetStatus,
+		ReloadConfirmer:  scheduleSetStatus,
+		OperatorToken:    operatorToken,
+		Runs:             sched,
+		LastActions:      lastActions,
+	}
+	if client != nil {
+		webOpts.Suggestions = suggestionProvider{reader: client, store: scheduleStore}
+		webOpts.RawResources = rawResourceProvider{client: client}
+	}
+	if cfg.IsValidationResourcesEnabled() {
+		webOpts.Incident = incidentController{validator: val}
+	}
+	webSrv, err := web.NewServer(context.Background(), addrs, webOpts)
 	if err != nil {
 		log.Warn().
-			Str("addr", addr).
+			Strs("addrs", addrs).
 			Err(err).
 			Msg("Failed to create web server, metrics/health endpoints will be unavailable")
 		// Continue without web server
 		webSrv = nil
 	}
 
-	schedulesReloader, err := reloader.New(cfg.SchedulesDir, schedulesReloadInterval, func(ctx context.Context) error {
-		return reloadSchedules(ctx, cfg.SchedulesDir, sched, stateChecker, operator, val, dryRun, m, cfg, scheduleStore)
-	})
-	if err != nil {
-		return nil, fmt.Errorf("create schedules reloader: %w", err)
+	// The idle policy watches whole folders rather than schedules, so with
+	// sharding only the first shard runs it. VMs its schedules expect to be
+	// running are left alone, so the validator does not start them again.
+	var idlePolicy *idle.Policy
+	if cfg.IdlePolicy != nil && cfg.ShardIndex == 0 {
+		idlePolicy = idle.New(client, operator, cfg.IdlePolicy, m, dryRun)
+		idlePolicy.SetBlackouts(blackouts)
+		idlePolicy.SetExpectations(val)
+	}
+
+	// In operator mode runs are reported back to the Schedule objects.
+	var statuses *statusReporter
+	if w := statusWriter(cfg.ScheduleSources); w != nil {
+		statuses = newStatusReporter(w, sched, val)
 	}
 
-	return &App{
+	a = &App{
 		cfg:           cfg,
 		client:        client,
 		stateChecker:  stateChecker,
+		labels:        stateChecker,
 		operator:      operator,
+		executor:      exec,
 		scheduler:     sched,
 		validator:     val,
 		metrics:       m,
 		webServer:     webSrv,
-		reloader:      schedulesReloader,
 		scheduleStore: scheduleStore,
+		uiProvider:    uiProvider,
+		sets:          sets,
+		reloadGuard:   guard,
+		deprecations:  deprecations,
+		pauses:        pauses,
+		denied:        denied,
+		blackouts:     blackouts,
+		idlePolicy:    idlePolicy,
+		statuses:      statuses,
+		vacations:     vacations,
+		timezones:     timezones,
+		notifier:      notifier,
 		dryRun:        dryRun,
-	}, nil
+	}
+
+	// Inline schedules change only with the config file, so without
+	// schedules directories there is nothing to watch. Schedules sources are
+	// mirrored into them.
+	if len(cfg.SchedulesDir) > 0 {
+		a.reloader, err = reloader.New(cfg.SchedulesDir, cfg.SchedulesRecursive, cfg.EffectiveSchedulesReloadInterval(), a.reloadSchedules)
+		if err != nil {
+			return nil, fmt.Errorf("create schedules reloader: %w", err)
+		}
+		if len(cfg.ScheduleSources) > 0 {
+			a.reloader.SetRefresh(func(ctx context.Context) error {
+				return source.SyncAll(ctx, cfg.ScheduleSources)
+			})
+		}
+		if cfg.EffectiveEmptySchedulesDirPolicy() == config.EmptyDirPolicyKeep {
+			a.reloader.SetKeepLastKnownGood(a.schedulesDirsUnavailable)
+		}
+	}
+
+	return a, nil
 }
 
 // Run starts the application and blocks until the context is canceled.
 func (a *App) Run(ctx context.Context) error {
+	cfg, _, notifier := a.settings()
+
 	// Register schedules
-	if err := a.scheduler.RegisterSchedules(a.stateChecker, a.operator, a.cfg, a.dryRun, a.metrics); err != nil {
-		return fmt.Errorf("register schedules: %w", err)
+	if err := a.scheduler.RegisterSchedules(a.stateChecker, a.operator, cfg, a.dryRun, a.metrics); err != nil {
+		err = fmt.Errorf("register schedules: %w", err)
+		notify.Send(notifier, notify.EventStartFailed, err)
+		return err
 	}
 
 	// Start web server if available
@@ -108,52 +319,205 @@ func (a *App) Run(ctx context.Context) error {
 		a.webServer.Start()
 	}
 
-	if a.cfg.IsValidationResourcesEnabled() {
-		a.validator.Start(ctx, a.cfg.ValidationInterval.Std())
-	} else {
-		log.Info().Msg("Resource validation is disabled")
-	}
+	a.mu.Lock()
+	a.runCtx = ctx
+	a.mu.Unlock()
+	a.startValidator()
+	a.idlePolicy.Start(ctx)
+	a.vacations.Start(ctx, vacationCheckInterval)
+	a.scheduler.StartConsistencyCheck(ctx, consistencyInterval)
 	go a.reloader.Start(ctx)
+	a.statuses.Start(ctx, scheduleStatusInterval)
+	// Watched sources apply changes to their directories as they happen and
+	// trigger a reload right away.
+	for _, src := range cfg.ScheduleSources {
+		if w, ok := src.(source.Watcher); ok && a.reloader != nil {
+			go w.Watch(ctx, a.reloader.Trigger)
+		}
+	}
+	go a.configReloader.Start(ctx)
 
 	log.Info().Msg("yc-scheduler started")
+	notify.Send(notifier, notify.EventStarted, nil)
 
 	// Start scheduler (blocks until context is canceled)
 	if err := a.scheduler.Start(ctx); err != nil {
-		return fmt.Errorf("scheduler stopped with error: %w", err)
+		err = fmt.Errorf("scheduler stopped with error: %w", err)
+		_, _, notifier = a.settings()
+		notify.Send(notifier, notify.EventStopped, err)
+		return err
 	}
 
 	log.Info().Msg("yc-scheduler stopped")
+	_, _, notifier = a.settings()
+	notify.Send(notifier, notify.EventStopped, nil)
 	return nil
 }
 
+// settings returns the current config along with the timezone inferrer and
+// notifier built from it.
+func (a *App) settings() (*config.Config, *timezoneInferrer, notify.Notifier) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.cfg, a.timezones, a.notifier
+}
+
+// startValidator starts the validator loop with the current settings,
+// stopping the loop started before.
+func (a *App) startValidator() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.stopValidator != nil {
+		a.stopValidator()
+		a.stopValidator = nil
+	}
+	if a.runCtx == nil {
+		// Run starts the loop.
+		return
+	}
+	if !a.cfg.IsValidationResourcesEnabled() {
+		log.Info().Msg("Resource validation is disabled")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(a.runCtx)
+	a.stopValidator = cancel
+	a.validator.Start(ctx, a.cfg.ValidationInterval.Std())
+}
+
+// reloadSchedules loads the schedules of the current config again and
+// publishes them.
+func (a *App) reloadSchedules(ctx context.Context) error {
+	a.reloadMu.Lock()
+	defer a.reloadMu.Unlock()
+
+	cfg, timezones, notifier := a.settings()
+	err := reloadSchedules(ctx, a.scheduler, a.stateChecker, a.operator, a.dryRun, a.metrics, cfg, timezones, a.sets, a.reloadGuard, a.deprecations, notifier)
+	a.recordReload("schedules", err)
+	return err
+}
+
+// schedulesDirsUnavailable alerts about schedules directories the reloader
+// found unreadable or emptied while it keeps the last loaded schedules.
+func (a *App) schedulesDirsUnavailable(dirs map[string]error) {
+	if a.metrics != nil {
+		a.metrics.SetSchedulesDirsUnavailable(len(dirs))
+	}
+	if len(dirs) == 0 {
+		return
+	}
+	errs := make([]error, 0, len(dirs))
+	for _, dir := range slices.Sorted(maps.Keys(dirs)) {
+		errs = append(errs, fmt.Errorf("%s: %w", dir, dirs[dir]))
+	}
+	_, _, notifier := a.settings()
+	notify.Send(notifier, notify.EventSchedulesDirUnavailable, errors.Join(errs...))
+}
+
 func reloadSchedules(
 	ctx context.Context,
-	schedulesDir string,
 	sched *scheduler.Scheduler,
 	stateChecker resource.StateChecker,
 	operator resource.Operator,
-	val *validator.Validator,
 	dryRun bool,
 	m *metrics.Metrics,
 	cfg *config.Config,
-	store *ScheduleStore,
+	timezones *timezoneInferrer,
+	sets *scheduleset.Store,
+	guard *reloadGuard,
+	deprecations *deprecationTracker,
+	notifier notify.Notifier,
 ) error {
-	schedules, err := config.LoadSchedules(ctx, schedulesDir)
+	schedules, err := config.ReloadSchedules(ctx, cfg)
 	if err != nil {
 		return fmt.Errorf("load schedules: %w", err)
 	}
+	timezones.Apply(ctx, schedules)
+
+	current := sets.Load().Schedules()
+	changes := diff.Schedules(current, schedules)
+	hash, err := config.Hash(cfg, schedules)
+	if err != nil {
+		return err
+	}
+	if err := guard.check(cfg.MaxScheduleChangePercent, len(current), changes, hash); err != nil {
+		return err
+	}
 
 	if err := sched.ReplaceSchedules(stateChecker, operator, schedules, dryRun, m); err != nil {
 		return fmt.Errorf("replace schedules: %w", err)
 	}
 
-	cfg.Schedules = append([]config.Schedule(nil), schedules...)
-	val.UpdateSchedules(schedules)
-	store.Update(schedules)
+	if !changes.Empty() {
+		log.Info().
+			Str("summary", changes.Summary()).
+			Msg("Schedules changed on reload:\n" + changes.Render(logger.Colored()))
+		event := notify.NewEvent(notify.EventSchedulesReloaded, nil)
+		event.Summary = changes.Summary() + "\n" + changes.Render(false)
+		notify.SendEvent(notifier, event)
+	}
+
+	set := sets.Replace(schedules)
+	deprecations.Update(schedules)
+	if m != nil {
+		m.SetScheduleSetVersion(set.Version())
+	}
+	setConfigInfo(m, cfg, schedules)
+
+	log.Debug().
+		Uint64("version", set.Version()).
+		Int("schedules", set.Len()).
+		Msg("Schedule set published")
 
 	return nil
 }
 
+// pausesFromConfig converts configured pauses into registry entries.
+func pausesFromConfig(configured []config.PauseConfig) []pause.Pause {
+	pauses := make([]pause.Pause, 0, len(configured))
+	for _, pc := range configured {
+		p := pause.Pause{
+			Selector: pc.Selector,
+			Reason:   pc.Reason,
+		}
+		if pc.Until != "" {
+			// Until is validated as RFC3339 when the configuration is loaded.
+			p.Until, _ = pc.Until.Time()
+		}
+		pauses = append(pauses, p)
+	}
+	return pauses
+}
+
+// deniedFromConfig converts configured denied resources into list entries.
+func deniedFromConfig(configured []config.DeniedResourceConfig) []denylist.Entry {
+	entries := make([]denylist.Entry, 0, len(configured))
+	for _, dc := range configured {
+		entries = append(entries, denylist.Entry{ID: dc.ID, Reason: dc.Reason})
+	}
+	return entries
+}
+
+// vacationsFromConfig converts configured vacations into manager entries.
+func vacationsFromConfig(configured []config.VacationConfig) []vacation.Vacation {
+	vacations := make([]vacation.Vacation, 0, len(configured))
+	for _, vc := range configured {
+		// From and Until are validated as RFC3339 when the configuration is loaded.
+		from, _ := vc.From.Time()
+		until, _ := vc.Until.Time()
+		vacations = append(vacations, vacation.Vacation{
+			Namespace: vc.Namespace,
+			From:      from,
+			Until:     until,
+			Reason:    vc.Reason,
+			Source:    vacation.SourceConfig,
+		})
+	}
+	return vacations
+}
+
 // Shutdown gracefully shuts down the application.
 func (a *App) Shutdown(ctx context.Context) error {
 	var errs []error
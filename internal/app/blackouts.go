@@ -0,0 +1,69 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/blackout"
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/schedule"
+)
+
+// blackoutsFromConfig converts configured blackout windows into a calendar
+// evaluated in timezone. An empty timezone means the local one, as for the
+// scheduler.
+func blackoutsFromConfig(configured []config.BlackoutWindowConfig, timezone string) (*blackout.Calendar, error) {
+	location := time.Local
+	if timezone != "" {
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("load location %q: %w", timezone, err)
+		}
+		location = loc
+	}
+
+	windows := make([]blackout.Window, 0, len(configured))
+	for i, wc := range configured {
+		w, err := blackoutFromConfig(wc)
+		if err != nil {
+			return nil, fmt.Errorf("blackout window %d: %w", i, err)
+		}
+		windows = append(windows, w)
+	}
+	return blackout.New(windows, location), nil
+}
+
+func blackoutFromConfig(wc config.BlackoutWindowConfig) (blackout.Window, error) {
+	w := blackout.Window{
+		Name:   wc.Name,
+		Reason: wc.Reason,
+		Dates:  wc.Dates,
+	}
+	// From and Until are validated as RFC3339 when the configuration is loaded.
+	if wc.From != "" {
+		w.From, _ = wc.From.Time()
+	}
+	if wc.Until != "" {
+		w.Until, _ = wc.Until.Time()
+	}
+	for _, date := range wc.Dates {
+		if _, err := time.Parse(time.DateOnly, date); err != nil {
+			return blackout.Window{}, fmt.Errorf("invalid date %q: %w", date, err)
+		}
+	}
+	for _, day := range wc.Weekdays {
+		w.Weekdays = append(w.Weekdays, time.Weekday(day))
+	}
+	if wc.StartTime != "" {
+		start, err := schedule.ParseTimeOfDay(wc.StartTime.String())
+		if err != nil {
+			return blackout.Window{}, err
+		}
+		end, err := schedule.ParseTimeOfDay(wc.EndTime.String())
+		if err != nil {
+			return blackout.Window{}, err
+		}
+		w.Start, w.End = start, end
+	}
+	return w, nil
+}
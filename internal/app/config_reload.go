@@ -0,0 +1,151 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/sentoz/yc-sheduler/internal/blackout"
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/denylist"
+	"github.com/sentoz/yc-sheduler/internal/metrics"
+	"github.com/sentoz/yc-sheduler/internal/notify"
+	"github.com/sentoz/yc-sheduler/internal/pause"
+	"github.com/sentoz/yc-sheduler/internal/reloader"
+	"github.com/sentoz/yc-sheduler/internal/source"
+)
+
+// WatchConfig reloads the config file at path when it changes and applies
+// the changed settings without a restart. A config served at a URL is not
+// watched.
+func (a *App) WatchConfig(path string) error {
+	if source.IsURL(path) {
+		log.Info().
+			Str("config_path", source.Redact(path)).
+			Msg("Config is served at a URL, config auto-reload is disabled")
+		return nil
+	}
+
+	cfg, _, _ := a.settings()
+	r, err := reloader.NewFile(path, cfg.EffectiveSchedulesReloadInterval(), func(ctx context.Context) error {
+		err := a.reloadConfig(ctx, path)
+		a.recordReload("config", err)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("create config reloader: %w", err)
+	}
+	a.configReloader = r
+	return nil
+}
+
+// reloadConfig reads the config file at path again and re-initializes the
+// subsystems whose settings changed. Settings that require a restart keep
+// their values and are only reported.
+func (a *App) reloadConfig(ctx context.Context, path string) error {
+	next, err := config.LoadSettings(ctx, path)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	current, timezones, notifier := a.settings()
+	cfg, change := current.WithSettings(next)
+	if len(change.Restart) > 0 {
+		log.Warn().
+			Strs("settings", change.Restart).
+			Msg("Changed settings take effect after a restart")
+	}
+	if len(change.Applied) == 0 {
+		return nil
+	}
+
+	// Steps that may fail run first, so a failed reload changes nothing.
+	timezone := cfg.Timezone.String()
+	var blackouts *blackout.Calendar
+	if change.Has("timezone", "blackout_windows") {
+		if blackouts, err = blackoutsFromConfig(cfg.BlackoutWindows, timezone); err != nil {
+			return fmt.Errorf("create blackout windows: %w", err)
+		}
+	}
+	if change.Has("timezone", "max_concurrent_jobs") {
+		if err := a.scheduler.Reconfigure(timezone, cfg.MaxConcurrentJobs); err != nil {
+			return fmt.Errorf("reconfigure scheduler: %w", err)
+		}
+	}
+	if blackouts != nil {
+		a.blackouts.Replace(blackouts)
+	}
+	a.scheduleStore.SetTimezone(timezone)
+
+	if change.Has("timezone_label") {
+		timezones = newTimezoneInferrer(a.labels, cfg.TimezoneLabel)
+	}
+	if change.Has("notifications") {
+		notifier = notify.New(cfg.Notifications)
+		a.scheduler.SetNotifier(notifier)
+	}
+
+	a.mu.Lock()
+	a.cfg, a.timezones, a.notifier = cfg, timezones, notifier
+	a.mu.Unlock()
+
+	// Schedule reloads publish the config info too, so they must not
+	// interleave with publishing the changed settings.
+	a.reloadMu.Lock()
+	setConfigInfo(a.metrics, cfg, a.sets.Load().Schedules())
+	a.reloadMu.Unlock()
+
+	a.validator.SetConfig(cfg)
+	a.executor.SetDefaultTimeout(cfg.EffectiveActionTimeout())
+	if a.client != nil {
+		a.executor.SetUtilizationSnapshots(a.client, cfg.UtilizationSnapshot)
+	}
+	a.scheduler.SetWarmUp(cfg.WarmUp)
+	a.pauses.ReplaceSource(pause.SourceConfig, pausesFromConfig(cfg.Pauses))
+	a.denied.ReplaceSource(denylist.SourceConfig, deniedFromConfig(cfg.DeniedResources))
+	if change.Has("validation_interval", "validation_resources") {
+		a.startValidator()
+		a.uiProvider.SetValidation(cfg.ValidationInterval.String(), cfg.IsValidationResourcesEnabled())
+	}
+
+	log.Info().
+		Strs("settings", change.Applied).
+		Msg("Changed settings applied")
+
+	// Inline schedules and inferred timezones change the schedules, which
+	// are published like on a schedules directory change.
+	if change.Has("schedules", "timezone_label") {
+		if err := a.reloadSchedules(ctx); err != nil {
+			log.Error().
+				Err(err).
+				Msg("Failed to reload schedules with the changed settings, keeping previous schedule set")
+		}
+	}
+	return nil
+}
+
+// recordReload records the outcome of reloading source ("config" or
+// "schedules") in the metrics.
+func (a *App) recordReload(source string, err error) {
+	if a.metrics != nil {
+		a.metrics.SetConfigReload(source, err == nil)
+	}
+}
+
+// setConfigInfo publishes the hash of the settings of cfg and the schedules
+// in effect, so instances left with a stale configuration after a failed
+// reload stand out.
+func setConfigInfo(m *metrics.Metrics, cfg *config.Config, schedules []config.Schedule) {
+	if m == nil {
+		return
+	}
+	hash, err := config.Hash(cfg, schedules)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Failed to hash configuration, config info metric is not updated")
+		return
+	}
+	m.SetConfigInfo(hash, len(schedules), config.ScheduleAPIVersion)
+}
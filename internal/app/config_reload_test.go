@@ -0,0 +1,109 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/blackout"
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/denylist"
+	"github.com/sentoz/yc-sheduler/internal/executor"
+	"github.com/sentoz/yc-sheduler/internal/pause"
+	"github.com/sentoz/yc-sheduler/internal/scheduler"
+	"github.com/sentoz/yc-sheduler/internal/scheduleset"
+	"github.com/sentoz/yc-sheduler/internal/validator"
+)
+
+// inlineSchedule is the part of the config file that does not change.
+const inlineSchedule = `validation_interval: 10m
+shutdown_timeout: 5m
+schedules:
+  - apiVersion: scheduler.yc/v1alpha1
+    kind: Schedule
+    metadata:
+      name: vm-start
+    spec:
+      type: daily
+      resource:
+        type: vm
+        id: fhm1234567890abcdef
+        folder_id: b1g1234567890abcdef
+      actions:
+        start:
+          enabled: true
+          time: "09:00"
+`
+
+func TestReloadConfigAppliesChangedSettings(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(configPath, []byte(inlineSchedule+content), 0o600); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+	}
+
+	writeConfig("timezone: UTC\nvalidation_resources: false\n")
+	cfg, err := config.Load(context.Background(), configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	sched, err := scheduler.New("UTC", 5)
+	if err != nil {
+		t.Fatalf("scheduler.New() error = %v", err)
+	}
+	sets := scheduleset.NewStore(nil)
+	a := &App{
+		cfg:           cfg,
+		executor:      executor.New(),
+		scheduler:     sched,
+		validator:     validator.New(nil, nil, cfg, sched, nil, false),
+		scheduleStore: NewScheduleStore("UTC", sets),
+		sets:          sets,
+		deprecations:  newDeprecationTracker(nil, false),
+		pauses:        pause.NewRegistry(),
+		denied:        denylist.New(),
+		blackouts:     blackout.New(nil, time.UTC),
+	}
+
+	writeConfig(`timezone: Europe/Moscow
+validation_resources: false
+metrics_port: 9191
+pauses:
+  - selector:
+      team: payments
+    reason: release freeze
+denied_resources:
+  - id: fhm-prod
+blackout_windows:
+  - name: release-night
+    start_time: "22:00"
+    end_time: "23:00"
+`)
+	if err := a.reloadConfig(context.Background(), configPath); err != nil {
+		t.Fatalf("reloadConfig() error = %v", err)
+	}
+
+	if got := a.scheduleStore.Timezone(); got != "Europe/Moscow" {
+		t.Errorf("schedule store timezone = %q, want Europe/Moscow", got)
+	}
+	if _, paused := a.pauses.Paused(map[string]string{"team": "payments"}); !paused {
+		t.Error("configured pause is not applied")
+	}
+	if _, denied := a.denied.Denied("fhm-prod"); !denied {
+		t.Error("configured denied resource is not applied")
+	}
+	// 19:30 UTC is 22:30 in Moscow.
+	if w, active := a.blackouts.Active(time.Date(2026, 10, 16, 19, 30, 0, 0, time.UTC)); !active || w.Name != "release-night" {
+		t.Errorf("blackouts Active() = %+v, %v; want release-night in the new timezone", w, active)
+	}
+
+	current, _, _ := a.settings()
+	if current.Timezone != "Europe/Moscow" || current.MetricsPort != 9090 {
+		t.Errorf("current timezone = %q, metrics_port = %d; want Europe/Moscow and 9090 until a restart", current.Timezone, current.MetricsPort)
+	}
+}
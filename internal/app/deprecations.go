@@ -0,0 +1,63 @@
+package app
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/metrics"
+)
+
+// deprecationTracker tracks deprecated features used by the configuration
+// and reports them in logs, metrics and the deprecations API.
+type deprecationTracker struct {
+	metrics      *metrics.Metrics
+	deprecations []config.Deprecation
+	mu           sync.RWMutex
+	tokenAuth    bool
+}
+
+// newDeprecationTracker creates a tracker. tokenAuth reports whether the
+// client authenticates with a token.
+func newDeprecationTracker(m *metrics.Metrics, tokenAuth bool) *deprecationTracker {
+	return &deprecationTracker{metrics: m, tokenAuth: tokenAuth}
+}
+
+// Update recomputes deprecated features used with the given schedules.
+func (t *deprecationTracker) Update(schedules []config.Schedule) {
+	deprecations := config.ScheduleDeprecations(schedules)
+	if t.tokenAuth {
+		deprecations = append(deprecations, config.NewDeprecation(config.DeprecatedTokenAuth, "--token"))
+	}
+
+	for _, d := range deprecations {
+		log.Warn().
+			Str("feature", d.Feature).
+			Strs("sources", d.Sources).
+			Msg("Deprecated feature in use: " + d.Message)
+	}
+	if t.metrics != nil {
+		for _, feature := range config.DeprecatedFeatures {
+			usage := 0
+			if i := slices.IndexFunc(deprecations, func(d config.Deprecation) bool { return d.Feature == feature }); i >= 0 {
+				usage = len(deprecations[i].Sources)
+			}
+			t.metrics.SetDeprecatedFeatureUsage(feature, usage)
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.deprecations = deprecations
+}
+
+// Deprecations returns the deprecated features in use.
+func (t *deprecationTracker) Deprecations() []config.Deprecation {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return slices.Clone(t.deprecations)
+}
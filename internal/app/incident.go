@@ -0,0 +1,30 @@
+package app
+
+import (
+	"github.com/sentoz/yc-sheduler/internal/validator"
+	"github.com/sentoz/yc-sheduler/internal/web"
+)
+
+// incidentController exposes validator incident mode to the web API.
+type incidentController struct {
+	validator *validator.Validator
+}
+
+// IncidentStatus returns the current validator incident mode.
+func (c incidentController) IncidentStatus() web.IncidentStatus {
+	return toIncidentStatus(c.validator.IncidentMode())
+}
+
+// SetIncident enables or disables validator incident mode.
+func (c incidentController) SetIncident(active bool, reason string) web.IncidentStatus {
+	return toIncidentStatus(c.validator.SetIncidentMode(active, reason))
+}
+
+func toIncidentStatus(state validator.IncidentState) web.IncidentStatus {
+	return web.IncidentStatus{
+		Since:     state.Since,
+		Reason:    state.Reason,
+		Active:    state.Active,
+		Automatic: state.Automatic,
+	}
+}
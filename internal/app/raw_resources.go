@@ -0,0 +1,42 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/sentoz/yc-sheduler/internal/web"
+	"github.com/sentoz/yc-sheduler/internal/yc"
+)
+
+// rawResourceProvider fetches VMs, Kubernetes clusters and node groups live
+// for the raw resource details API.
+type rawResourceProvider struct {
+	client yc.ClientInterface
+}
+
+// RawResource returns the resource as returned by the Yandex Cloud API. Get
+// calls address resources by ID, so no folder is needed.
+func (p rawResourceProvider) RawResource(ctx context.Context, resourceType, id string) (proto.Message, error) {
+	var (
+		msg proto.Message
+		err error
+	)
+	switch resourceType {
+	case "vm":
+		msg, err = p.client.GetInstance(ctx, "", id)
+	case "k8s_cluster":
+		msg, err = p.client.GetCluster(ctx, "", id)
+	case "k8s_node_group":
+		msg, err = p.client.GetNodeGroup(ctx, "", id)
+	default:
+		return nil, fmt.Errorf("%w: %s", web.ErrUnsupportedResourceType, resourceType)
+	}
+	if status.Code(err) == codes.NotFound {
+		return nil, fmt.Errorf("%w: %s %s", web.ErrResourceNotFound, resourceType, id)
+	}
+	return msg, err
+}
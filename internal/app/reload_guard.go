@@ -0,0 +1,105 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/diff"
+	"github.com/sentoz/yc-sheduler/internal/web"
+)
+
+// ErrReloadTooLarge is returned when a schedules reload changes more
+// schedules than max_schedule_change_percent allows.
+var ErrReloadTooLarge = errors.New("reload changes too many schedules")
+
+// heldReload is a schedules reload held back by the reload guard.
+type heldReload struct {
+	// hash is the config hash of the held schedules; a confirmation applies
+	// these schedules only.
+	hash    string
+	summary string
+	percent int
+	since   time.Time
+}
+
+// reloadGuard holds back schedules reloads changing too large a share of the
+// schedules until they are confirmed.
+type reloadGuard struct {
+	mu   sync.Mutex
+	held *heldReload
+	// confirmed is the hash of the held reload confirmed to be applied.
+	confirmed string
+}
+
+// check returns ErrReloadTooLarge and holds the reload back if changes touch
+// more than maxPercent of the current schedules, unless the reload with hash
+// has been confirmed. A reload within the limit drops a held one. A nil guard
+// lets every reload through.
+func (g *reloadGuard) check(maxPercent, current int, changes diff.Diff, hash string) error {
+	if g == nil {
+		return nil
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// Adding schedules to an empty set is no reason to hold a reload back.
+	if maxPercent == 0 || current == 0 || len(changes)*100 <= maxPercent*current || hash == g.confirmed {
+		g.held, g.confirmed = nil, ""
+		return nil
+	}
+
+	percent := len(changes) * 100 / current
+	if g.held == nil || g.held.hash != hash {
+		g.held = &heldReload{hash: hash, since: time.Now()}
+	}
+	g.held.summary, g.held.percent = changes.Summary(), percent
+	return fmt.Errorf("%w: %s of %d schedules (%d%%), max_schedule_change_percent is %d; confirm with POST /api/v1/schedule-set/confirm or restart",
+		ErrReloadTooLarge, changes.Summary(), current, percent, maxPercent)
+}
+
+// confirm marks the held reload to be applied by the next reload loading the
+// same schedules.
+func (g *reloadGuard) confirm() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.held == nil {
+		return web.ErrNoHeldReload
+	}
+	g.confirmed = g.held.hash
+	return nil
+}
+
+// status returns the held reload for the schedule set API, or nil.
+func (g *reloadGuard) status() *web.HeldReload {
+	if g == nil {
+		return nil
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.held == nil {
+		return nil
+	}
+	return &web.HeldReload{
+		Since:   g.held.since,
+		Summary: g.held.summary,
+		Percent: g.held.percent,
+	}
+}
+
+// ConfirmReload applies the schedules reload held back by the reload guard
+// by loading the schedules again. The reload is held back again if the
+// schedules changed since.
+func (p scheduleSetProvider) ConfirmReload(ctx context.Context) (web.ScheduleSetStatus, error) {
+	if err := p.guard.confirm(); err != nil {
+		return web.ScheduleSetStatus{}, err
+	}
+	if err := p.reload(ctx); err != nil {
+		return web.ScheduleSetStatus{}, err
+	}
+	return p.ScheduleSetStatus(), nil
+}
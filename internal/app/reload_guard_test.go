@@ -0,0 +1,54 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sentoz/yc-sheduler/internal/diff"
+	"github.com/sentoz/yc-sheduler/internal/web"
+)
+
+func TestReloadGuardHoldsLargeReloadsUntilConfirmed(t *testing.T) {
+	t.Parallel()
+
+	guard := &reloadGuard{}
+	removed := diff.Diff{
+		{Name: "a", Kind: diff.Removed},
+		{Name: "b", Kind: diff.Removed},
+		{Name: "c", Kind: diff.Removed},
+	}
+
+	if err := guard.check(50, 10, removed, "small"); err != nil {
+		t.Fatalf("check() of 30%% error = %v, want nil", err)
+	}
+	if err := guard.check(0, 4, removed, "unguarded"); err != nil {
+		t.Fatalf("check() without a limit error = %v, want nil", err)
+	}
+	if err := guard.confirm(); !errors.Is(err, web.ErrNoHeldReload) {
+		t.Fatalf("confirm() without a held reload error = %v, want %v", err, web.ErrNoHeldReload)
+	}
+
+	if err := guard.check(50, 4, removed, "truncated"); !errors.Is(err, ErrReloadTooLarge) {
+		t.Fatalf("check() of 75%% error = %v, want %v", err, ErrReloadTooLarge)
+	}
+	if held := guard.status(); held == nil || held.Percent != 75 || held.Summary != "0 added, 3 removed, 0 changed" {
+		t.Fatalf("status() = %+v, want the held reload changing 75%%", held)
+	}
+
+	// A confirmation applies the held schedules only.
+	if err := guard.confirm(); err != nil {
+		t.Fatalf("confirm() error = %v", err)
+	}
+	if err := guard.check(50, 4, removed, "truncated-further"); !errors.Is(err, ErrReloadTooLarge) {
+		t.Fatalf("check() of other schedules after confirm() error = %v, want %v", err, ErrReloadTooLarge)
+	}
+	if err := guard.confirm(); err != nil {
+		t.Fatalf("confirm() error = %v", err)
+	}
+	if err := guard.check(50, 4, removed, "truncated-further"); err != nil {
+		t.Fatalf("check() of confirmed schedules error = %v, want nil", err)
+	}
+	if held := guard.status(); held != nil {
+		t.Fatalf("status() after the applied reload = %+v, want nil", held)
+	}
+}
@@ -0,0 +1,43 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/denylist"
+	"github.com/sentoz/yc-sheduler/internal/executor"
+	"github.com/sentoz/yc-sheduler/internal/resource"
+	"github.com/sentoz/yc-sheduler/internal/scheduler"
+	"github.com/sentoz/yc-sheduler/internal/yc"
+)
+
+// ScheduleAction returns the schedule of cfg named name, narrowed for action
+// with scheduler.ScheduleForAction, and the bare action name.
+func ScheduleAction(cfg *config.Config, name, action string) (config.Schedule, string, error) {
+	for _, sch := range cfg.Schedules {
+		if sch.Name == name {
+			return scheduler.ScheduleForAction(sch, action)
+		}
+	}
+	return config.Schedule{}, "", fmt.Errorf("schedule %q not found", name)
+}
+
+// RunAction runs action of the schedule once, right away, with the action
+// timeout and denied resources of cfg. Conditions the scheduler checks before
+// a run, such as the active period, pauses, blackout windows, jitter and
+// dependencies, do not apply. The action is not recorded as the last action
+// of its resources, since a running scheduler owns the last actions file.
+func RunAction(cfg *config.Config, client *yc.Client, sch config.Schedule, action string, dryRun bool) executor.RunReport {
+	exec := executor.New()
+	exec.SetDefaultTimeout(cfg.EffectiveActionTimeout())
+	denied := denylist.New()
+	denied.ReplaceSource(denylist.SourceConfig, deniedFromConfig(cfg.DeniedResources))
+	exec.SetDenyList(denied)
+	operator := denylist.Guard(resource.NewYCOperator(client), denied)
+
+	var report executor.RunReport
+	exec.MakeWithRunReport(resource.NewYCStateChecker(client), operator, sch, action, dryRun, nil, func(run executor.RunReport) {
+		report = run
+	})()
+	return report
+}
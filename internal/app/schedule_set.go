@@ -0,0 +1,43 @@
+package app
+
+import (
+	"context"
+
+	"github.com/sentoz/yc-sheduler/internal/scheduleset"
+	"github.com/sentoz/yc-sheduler/internal/web"
+)
+
+// evaluatedVersionSource supplies the version of the schedule set the
+// validator last evaluated.
+type evaluatedVersionSource interface {
+	EvaluatedVersion() uint64
+}
+
+// scheduleSetProvider reports the published schedule set and the version the
+// validator has caught up with for the schedule set API, and confirms
+// reloads held back by guard.
+type scheduleSetProvider struct {
+	sets      *scheduleset.Store
+	validator evaluatedVersionSource
+	guard     *reloadGuard
+	reload    func(ctx context.Context) error
+}
+
+// ScheduleSetStatus returns the versions of the published set and its
+// schedules.
+func (p scheduleSetProvider) ScheduleSetStatus() web.ScheduleSetStatus {
+	set := p.sets.Load()
+	status := web.ScheduleSetStatus{
+		Version:          set.Version(),
+		ValidatorVersion: p.validator.EvaluatedVersion(),
+		Schedules:        make([]web.ScheduleVersion, 0, set.Len()),
+	}
+	status.Held = p.guard.status()
+	for _, sch := range set.Schedules() {
+		status.Schedules = append(status.Schedules, web.ScheduleVersion{
+			Name:    sch.Name,
+			Version: set.ScheduleVersion(sch.Name),
+		})
+	}
+	return status
+}
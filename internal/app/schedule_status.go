@@ -0,0 +1,275 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/sentoz/yc-sheduler/internal/logger"
+	"github.com/sentoz/yc-sheduler/internal/scheduler"
+	"github.com/sentoz/yc-sheduler/internal/source"
+	"github.com/sentoz/yc-sheduler/internal/validator"
+)
+
+// Results of the last run reported in the status of Schedule objects.
+const (
+	runSucceeded = "Succeeded"
+	runPartial   = "PartiallySucceeded"
+	runFailed    = "Failed"
+	runMissed    = "Missed"
+)
+
+// Types of the conditions in the status of Schedule objects.
+const (
+	conditionLastRun    = "LastRun"
+	conditionLastResult = "LastResult"
+	conditionNextRun    = "NextRun"
+	conditionDrifted    = "Drifted"
+)
+
+// Condition statuses.
+const (
+	conditionTrue    = "True"
+	conditionFalse   = "False"
+	conditionUnknown = "Unknown"
+)
+
+// scheduleStatus is the status subresource of a Schedule object. Missing
+// times are written as null, so a JSON merge patch clears them.
+type scheduleStatus struct {
+	LastRun    *time.Time          `json:"lastRun"`
+	LastAction string              `json:"lastAction,omitempty"`
+	LastResult string              `json:"lastResult,omitempty"`
+	NextRun    *time.Time          `json:"nextRun"`
+	Conditions []scheduleCondition `json:"conditions"`
+}
+
+// scheduleCondition is a condition of a Schedule object in the format of
+// Kubernetes conditions. LastTransitionTime changes only with Status.
+type scheduleCondition struct {
+	Type               string    `json:"type"`
+	Status             string    `json:"status"`
+	Reason             string    `json:"reason"`
+	Message            string    `json:"message,omitempty"`
+	LastTransitionTime time.Time `json:"lastTransitionTime"`
+}
+
+// runTracker supplies the last and next runs of registered schedules.
+type runTracker interface {
+	runSource
+	NextRuns() map[string]time.Time
+	Registered(name string) bool
+}
+
+// driftSource supplies the resource state mismatches of schedules.
+type driftSource interface {
+	Drifts() map[string][]validator.Drift
+}
+
+// statusReporter writes the runs of schedules back to the Schedule objects
+// they were loaded from and keeps the objects of registered schedules from
+// being deleted before their jobs are removed.
+type statusReporter struct {
+	writer  source.StatusWriter
+	runs    runTracker
+	drifts  driftSource
+	now     func() time.Time
+	written map[string]scheduleStatus
+}
+
+func newStatusReporter(writer source.StatusWriter, runs runTracker, drifts driftSource) *statusReporter {
+	return &statusReporter{writer: writer, runs: runs, drifts: drifts, now: time.Now, written: make(map[string]scheduleStatus)}
+}
+
+// Start reports statuses every interval until ctx is canceled.
+func (r *statusReporter) Start(ctx context.Context, interval time.Duration) {
+	if r == nil {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.report(ctx)
+			}
+		}
+	}()
+}
+
+// report writes the statuses that changed since they were last written.
+// Objects without a registered schedule, e.g. invalid ones or schedules of
+// other shards, are left as they are. Deleted objects are released once
+// their schedules are no longer registered.
+func (r *statusReporter) report(ctx context.Context) {
+	statuses := make(map[string]scheduleStatus)
+	for _, run := range r.runs.LastRuns() {
+		status := statuses[run.Schedule]
+		if status.LastRun != nil && !run.At.After(*status.LastRun) {
+			continue
+		}
+		at := run.At
+		status.LastRun = &at
+		status.LastAction = run.Action
+		switch {
+		case run.OK:
+			status.LastResult = runSucceeded
+		case run.Partial:
+			status.LastResult = runPartial
+		case run.Missed:
+			status.LastResult = runMissed
+		default:
+			status.LastResult = runFailed
+		}
+		statuses[run.Schedule] = status
+	}
+	for name, at := range r.runs.NextRuns() {
+		status := statuses[name]
+		status.NextRun = &at
+		statuses[name] = status
+	}
+
+	drifts := r.drifts.Drifts()
+	now := r.now()
+	for _, name := range r.writer.Objects() {
+		if !r.runs.Registered(name) {
+			continue
+		}
+		if err := r.writer.AddFinalizer(ctx, name); err != nil {
+			logger.Sampled("schedule-status").Warn().Err(err).Str("schedule", name).Msg("Failed to add Schedule finalizer")
+		}
+
+		status := statuses[name]
+		status.Conditions = conditions(status, drifts, name, r.written[name].Conditions, now)
+		if equalStatus(r.written[name], status) {
+			continue
+		}
+		if err := r.writer.WriteStatus(ctx, name, status); err != nil {
+			logger.Sampled("schedule-status").Warn().Err(err).Str("schedule", name).Msg("Failed to write Schedule status")
+			continue
+		}
+		r.written[name] = status
+		log.Debug().Str("schedule", name).Msg("Schedule status written")
+	}
+
+	for _, name := range r.writer.Deleting() {
+		if r.runs.Registered(name) {
+			continue
+		}
+		if err := r.writer.RemoveFinalizer(ctx, name); err != nil {
+			logger.Sampled("schedule-status").Warn().Err(err).Str("schedule", name).Msg("Failed to remove Schedule finalizer")
+			continue
+		}
+		delete(r.written, name)
+	}
+}
+
+// conditions returns the conditions of the status of the named schedule.
+// Transition times are kept from previous conditions of the same status.
+// Without drifts the validator has not run yet, so Drifted is unknown.
+func conditions(status scheduleStatus, drifts map[string][]validator.Drift, name string, previous []scheduleCondition, now time.Time) []scheduleCondition {
+	lastRun := scheduleCondition{Type: conditionLastRun, Status: conditionFalse, Reason: "NotRun",
+		Message: "The schedule has not run since the scheduler started"}
+	lastResult := scheduleCondition{Type: conditionLastResult, Status: conditionUnknown, Reason: "NotRun"}
+	if status.LastRun != nil {
+		lastRun = scheduleCondition{Type: conditionLastRun, Status: conditionTrue, Reason: "Ran",
+			Message: fmt.Sprintf("Last run was the %s action at %s", status.LastAction, status.LastRun.UTC().Format(time.RFC3339))}
+		lastResult = scheduleCondition{Type: conditionLastResult, Status: conditionFalse, Reason: status.LastResult,
+			Message: fmt.Sprintf("The %s action ended as %s", status.LastAction, status.LastResult)}
+		if status.LastResult == runSucceeded {
+			lastResult.Status = conditionTrue
+		}
+	}
+
+	nextRun := scheduleCondition{Type: conditionNextRun, Status: conditionFalse, Reason: "NotScheduled",
+		Message: "The schedule has no upcoming runs"}
+	if status.NextRun != nil {
+		nextRun = scheduleCondition{Type: conditionNextRun, Status: conditionTrue, Reason: "Scheduled",
+			Message: "Next run at " + status.NextRun.UTC().Format(time.RFC3339)}
+	}
+
+	drifted := scheduleCondition{Type: conditionDrifted, Status: conditionUnknown, Reason: "NotValidated"}
+	if drifts != nil {
+		drifted = driftCondition(drifts[name])
+	}
+
+	result := []scheduleCondition{lastRun, lastResult, nextRun, drifted}
+	for i := range result {
+		result[i].LastTransitionTime = now
+		j := slices.IndexFunc(previous, func(c scheduleCondition) bool { return c.Type == result[i].Type })
+		if j >= 0 && previous[j].Status == result[i].Status {
+			result[i].LastTransitionTime = previous[j].LastTransitionTime
+		}
+	}
+	return result
+}
+
+// maxDriftMessages limits the resources listed in the Drifted condition.
+const maxDriftMessages = 3
+
+// driftCondition returns the Drifted condition of a schedule with drifts.
+// Changes made outside the scheduler take precedence as the reason.
+func driftCondition(drifts []validator.Drift) scheduleCondition {
+	if len(drifts) == 0 {
+		return scheduleCondition{Type: conditionDrifted, Status: conditionFalse, Reason: "InSync",
+			Message: "Resources are in the expected state"}
+	}
+
+	reason := "UnknownChange"
+	var messages []string
+	for i, d := range drifts {
+		switch {
+		case d.Cause == validator.DriftExternal:
+			reason = "ExternalChange"
+		case d.Cause == validator.DriftScheduler && reason == "UnknownChange":
+			reason = "SchedulerChange"
+		}
+		if i < maxDriftMessages {
+			messages = append(messages, fmt.Sprintf("%s %s is %s, expected %s", d.Resource.Type, d.Resource.ID, d.Actual, d.Expected))
+		}
+	}
+	if len(drifts) > maxDriftMessages {
+		messages = append(messages, fmt.Sprintf("%d more", len(drifts)-maxDriftMessages))
+	}
+	return scheduleCondition{Type: conditionDrifted, Status: conditionTrue, Reason: reason, Message: strings.Join(messages, "; ")}
+}
+
+func equalStatus(a, b scheduleStatus) bool {
+	return equalTime(a.LastRun, b.LastRun) && equalTime(a.NextRun, b.NextRun) &&
+		a.LastAction == b.LastAction && a.LastResult == b.LastResult &&
+		slices.EqualFunc(a.Conditions, b.Conditions, func(x, y scheduleCondition) bool {
+			return x.Type == y.Type && x.Status == y.Status && x.Reason == y.Reason && x.Message == y.Message &&
+				x.LastTransitionTime.Equal(y.LastTransitionTime)
+		})
+}
+
+func equalTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// statusWriter returns the source of Schedule objects among sources.
+func statusWriter(sources []source.Source) source.StatusWriter {
+	for _, src := range sources {
+		if w, ok := src.(source.StatusWriter); ok {
+			return w
+		}
+	}
+	return nil
+}
+
+// Ensure Scheduler implements runTracker and Validator implements driftSource.
+var (
+	_ runTracker  = (*scheduler.Scheduler)(nil)
+	_ driftSource = (*validator.Validator)(nil)
+)
@@ -0,0 +1,135 @@
+package app
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/validator"
+)
+
+type fakeRunTracker struct {
+	fakeRunSource
+	next       map[string]time.Time
+	registered []string
+}
+
+func (f fakeRunTracker) NextRuns() map[string]time.Time {
+	return f.next
+}
+
+func (f fakeRunTracker) Registered(name string) bool {
+	return slices.Contains(f.registered, name)
+}
+
+type fakeDriftSource map[string][]validator.Drift
+
+func (f fakeDriftSource) Drifts() map[string][]validator.Drift {
+	return f
+}
+
+type fakeStatusWriter struct {
+	objects    []string
+	deleting   []string
+	finalizers map[string]bool
+	written    map[string]scheduleStatus
+}
+
+func (f *fakeStatusWriter) Dir() string                   { return "" }
+func (f *fakeStatusWriter) Sync(context.Context) error    { return nil }
+func (f *fakeStatusWriter) Watch(context.Context, func()) {}
+func (f *fakeStatusWriter) Objects() []string             { return f.objects }
+func (f *fakeStatusWriter) WriteStatus(_ context.Context, name string, status any) error {
+	f.written[name] = status.(scheduleStatus)
+	return nil
+}
+func (f *fakeStatusWriter) Deleting() []string { return f.deleting }
+func (f *fakeStatusWriter) AddFinalizer(_ context.Context, name string) error {
+	f.finalizers[name] = true
+	return nil
+}
+func (f *fakeStatusWriter) RemoveFinalizer(_ context.Context, name string) error {
+	delete(f.finalizers, name)
+	return nil
+}
+
+// condition returns the condition of status with the given type.
+func condition(t *testing.T, status scheduleStatus, conditionType string) scheduleCondition {
+	t.Helper()
+
+	i := slices.IndexFunc(status.Conditions, func(c scheduleCondition) bool { return c.Type == conditionType })
+	if i < 0 {
+		t.Fatalf("status %+v has no %s condition", status, conditionType)
+	}
+	return status.Conditions[i]
+}
+
+func TestStatusReporter(t *testing.T) {
+	start := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	stop := start.Add(10 * time.Hour)
+	writer := &fakeStatusWriter{
+		objects:    []string{"vm", "idle", "other-shard"},
+		deleting:   []string{"deleted", "unregistering"},
+		finalizers: map[string]bool{"deleted": true, "unregistering": true},
+		written:    make(map[string]scheduleStatus),
+	}
+	reporter := newStatusReporter(writer, fakeRunTracker{
+		fakeRunSource: fakeRunSource{
+			{At: stop, Schedule: "vm", Action: "stop", OK: false},
+			{At: start, Schedule: "vm", Action: "start", OK: true},
+			{At: start, Schedule: "removed", Action: "stop", OK: true},
+		},
+		next:       map[string]time.Time{"vm": start.Add(24 * time.Hour), "idle": stop.Add(24 * time.Hour)},
+		registered: []string{"vm", "idle", "unregistering"},
+	}, fakeDriftSource{"vm": {{
+		Resource: config.Resource{Type: "vm", ID: "fhm1"},
+		Expected: "stopped",
+		Actual:   "running",
+		Cause:    validator.DriftExternal,
+	}}})
+	reporter.now = func() time.Time { return stop }
+
+	reporter.report(context.Background())
+	vm := writer.written["vm"]
+	if !vm.LastRun.Equal(stop) || vm.LastAction != "stop" || vm.LastResult != runFailed || !vm.NextRun.Equal(start.Add(24*time.Hour)) {
+		t.Fatalf("status of vm = %+v, want the failed stop and the next start", vm)
+	}
+	if idle := writer.written["idle"]; idle.LastRun != nil || idle.LastResult != "" || idle.NextRun == nil {
+		t.Fatalf("status of idle = %+v, want only the next run", idle)
+	}
+	if _, ok := writer.written["other-shard"]; ok {
+		t.Fatal("status of an unregistered schedule was written")
+	}
+	if c := condition(t, vm, conditionLastResult); c.Status != conditionFalse || c.Reason != runFailed {
+		t.Fatalf("LastResult condition of vm = %+v, want False with reason %s", c, runFailed)
+	}
+	if c := condition(t, vm, conditionDrifted); c.Status != conditionTrue || c.Reason != "ExternalChange" ||
+		c.Message != "vm fhm1 is running, expected stopped" {
+		t.Fatalf("Drifted condition of vm = %+v, want the external change", c)
+	}
+	idle := writer.written["idle"]
+	if c := condition(t, idle, conditionLastRun); c.Status != conditionFalse || !c.LastTransitionTime.Equal(stop) {
+		t.Fatalf("LastRun condition of idle = %+v, want False since now", c)
+	}
+	if c := condition(t, idle, conditionDrifted); c.Status != conditionFalse || c.Reason != "InSync" {
+		t.Fatalf("Drifted condition of idle = %+v, want InSync", c)
+	}
+
+	// Objects of registered schedules are finalized, deleted objects are
+	// released once their schedules are unregistered.
+	if want := map[string]bool{"vm": true, "idle": true, "unregistering": true}; !maps.Equal(writer.finalizers, want) {
+		t.Fatalf("finalizers = %v, want %v", writer.finalizers, want)
+	}
+
+	// Unchanged statuses are not written again, and conditions keep their
+	// transition times while their status does not change.
+	writer.written = make(map[string]scheduleStatus)
+	reporter.now = func() time.Time { return stop.Add(time.Hour) }
+	reporter.report(context.Background())
+	if len(writer.written) != 0 {
+		t.Fatalf("written = %+v, want no unchanged statuses", writer.written)
+	}
+}
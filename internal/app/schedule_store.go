@@ -2,33 +2,32 @@ package app
 
 import (
 	"sync"
+	"time"
 
 	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/scheduleset"
 )
 
 // ScheduleStore provides concurrent read access to current schedules for the UI.
 type ScheduleStore struct {
-	timezone  string
-	schedules []config.Schedule
+	sets *scheduleset.Store
 
-	mu sync.RWMutex
+	mu       sync.RWMutex
+	timezone string
 }
 
-// NewScheduleStore creates a store initialized with the current schedules.
-func NewScheduleStore(timezone string, schedules []config.Schedule) *ScheduleStore {
-	store := &ScheduleStore{
+// NewScheduleStore creates a store reading the schedule sets published on
+// reload.
+func NewScheduleStore(timezone string, sets *scheduleset.Store) *ScheduleStore {
+	return &ScheduleStore{
+		sets:     sets,
 		timezone: timezone,
 	}
-	store.Update(schedules)
-	return store
 }
 
 // Schedules returns a copy of the current schedules.
 func (s *ScheduleStore) Schedules() []config.Schedule {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	return append([]config.Schedule(nil), s.schedules...)
+	return s.sets.Load().Schedules()
 }
 
 // Timezone returns the application timezone used for schedule calculations.
@@ -39,10 +38,21 @@ func (s *ScheduleStore) Timezone() string {
 	return s.timezone
 }
 
-// Update replaces the current schedules with a copy of the provided slice.
-func (s *ScheduleStore) Update(schedules []config.Schedule) {
+// SetTimezone sets the application timezone, e.g. when the config file is
+// reloaded.
+func (s *ScheduleStore) SetTimezone(timezone string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.schedules = append([]config.Schedule(nil), schedules...)
+	s.timezone = timezone
+}
+
+// Location returns the location of the application timezone, or UTC when it
+// cannot be loaded.
+func (s *ScheduleStore) Location() *time.Location {
+	location, err := time.LoadLocation(s.Timezone())
+	if err != nil {
+		return time.UTC
+	}
+	return location
 }
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/sentoz/yc-sheduler/internal/pause"
+	"github.com/sentoz/yc-sheduler/internal/scheduler"
+	"github.com/sentoz/yc-sheduler/internal/web"
+)
+
+// runSource supplies the outcomes of the last runs of schedule actions.
+type runSource interface {
+	LastRuns() []scheduler.RunResult
+}
+
+// statsProvider aggregates the loaded schedules and their last runs per
+// namespace for the stats API.
+type statsProvider struct {
+	store  *ScheduleStore
+	runs   runSource
+	pauses *pause.Registry
+}
+
+// Stats returns the statistics of every namespace with schedules ordered by
+// namespace.
+func (p statsProvider) Stats() []web.NamespaceStats {
+	byNamespace := make(map[string]*web.NamespaceStats)
+	namespaceOf := make(map[string]string)
+	for _, sch := range p.store.Schedules() {
+		stats, ok := byNamespace[sch.Namespace]
+		if !ok {
+			stats = &web.NamespaceStats{Namespace: sch.Namespace}
+			byNamespace[sch.Namespace] = stats
+		}
+		namespaceOf[sch.Name] = sch.Namespace
+		stats.Schedules++
+		stats.Resources += len(sch.Targets())
+		if p.pauses != nil {
+			if _, paused := p.pauses.Paused(sch.Labels); paused {
+				stats.Paused++
+			}
+		}
+	}
+
+	for _, run := range p.runs.LastRuns() {
+		namespace, ok := namespaceOf[run.Schedule]
+		if !ok {
+			// The schedule has been removed by a reload.
+			continue
+		}
+		switch {
+		case run.OK:
+			byNamespace[namespace].Succeeded++
+		case run.Partial:
+			byNamespace[namespace].Partial++
+		case run.Missed:
+			byNamespace[namespace].Missed++
+		default:
+			byNamespace[namespace].Failed++
+		}
+	}
+
+	stats := make([]web.NamespaceStats, 0, len(byNamespace))
+	for _, s := range byNamespace {
+		stats = append(stats, *s)
+	}
+	slices.SortFunc(stats, func(a, b web.NamespaceStats) int {
+		return cmp.Compare(a.Namespace, b.Namespace)
+	})
+	return stats
+}
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/pause"
+	"github.com/sentoz/yc-sheduler/internal/scheduler"
+	"github.com/sentoz/yc-sheduler/internal/scheduleset"
+	"github.com/sentoz/yc-sheduler/internal/web"
+)
+
+type fakeRunSource []scheduler.RunResult
+
+func (f fakeRunSource) LastRuns() []scheduler.RunResult {
+	return f
+}
+
+func TestStatsProvider(t *testing.T) {
+	store := NewScheduleStore("UTC", scheduleset.NewStore([]config.Schedule{
+		{Name: "dev-vm", Namespace: "dev", Labels: map[string]string{"team": "web"}},
+		{Name: "dev-k8s", Namespace: "dev", Resources: []config.Resource{{ID: "a"}, {ID: "b"}}},
+		{Name: "prod-vm", Namespace: "prod"},
+		{Name: "shared"},
+	}))
+	pauses := pause.NewRegistry()
+	pauses.Add(pause.Pause{Selector: map[string]string{"team": "web"}})
+
+	provider := statsProvider{
+		store: store,
+		runs: fakeRunSource{
+			{Schedule: "dev-vm", Action: "start", OK: true},
+			{Schedule: "dev-vm", Action: "stop", OK: true},
+			{Schedule: "prod-vm", Action: "stop", OK: false},
+			{Schedule: "prod-vm", Action: "start", OK: false, Partial: true},
+			{Schedule: "removed", Action: "stop", OK: false},
+		},
+		pauses: pauses,
+	}
+
+	want := []web.NamespaceStats{
+		{Namespace: "", Schedules: 1, Resources: 1},
+		{Namespace: "dev", Schedules: 2, Resources: 3, Paused: 1, Succeeded: 2},
+		{Namespace: "prod", Schedules: 1, Resources: 1, Partial: 1, Failed: 1},
+	}
+	got := provider.Stats()
+	if len(got) != len(want) {
+		t.Fatalf("Stats() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Stats() = %+v, want %+v", got, want)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package app
+
+import (
+	"context"
+
+	"github.com/sentoz/yc-sheduler/internal/advisor"
+)
+
+// suggestionProvider runs the advisor against the current schedules in the
+// current application timezone.
+type suggestionProvider struct {
+	reader advisor.ActivityReader
+	store  *ScheduleStore
+}
+
+// Suggestions returns schedule optimization suggestions.
+func (p suggestionProvider) Suggestions(ctx context.Context, days int) []advisor.Suggestion {
+	return advisor.New(p.reader, p.store.Location()).Suggest(ctx, p.store.Schedules(), days)
+}
@@ -0,0 +1,169 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/resource"
+)
+
+// labelReader reads the labels of resources and their folders.
+type labelReader interface {
+	resource.LabelReader
+	resource.FolderLabelReader
+}
+
+// timezoneInferrer sets the timezone of schedules from a label of their
+// resources or folders.
+type timezoneInferrer struct {
+	reader labelReader
+	label  string
+}
+
+// newTimezoneInferrer returns an inferrer reading label, or nil when label is
+// empty and inference is disabled.
+func newTimezoneInferrer(reader labelReader, label string) *timezoneInferrer {
+	if label == "" {
+		return nil
+	}
+	return &timezoneInferrer{reader: reader, label: label}
+}
+
+// Apply sets the timezone of schedules without one to the timezone all of
+// their resources agree on. A resource without the label takes the timezone
+// of its folder. Schedules whose resources disagree or cannot be read keep
+// the global timezone.
+func (i *timezoneInferrer) Apply(ctx context.Context, schedules []config.Schedule) {
+	if i == nil {
+		return
+	}
+
+	// Labels are read once per pass, since many schedules share folders.
+	lookup := &timezoneLookup{inferrer: i, cache: make(map[string]timezoneResult)}
+	inferred := 0
+	for n := range schedules {
+		sch := &schedules[n]
+		if sch.Timezone != "" || !config.SupportsTimezone(sch.Type) {
+			continue
+		}
+
+		timezone, err := lookup.schedule(ctx, *sch)
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Str("schedule", sch.Name).
+				Str("label", i.label).
+				Msg("Failed to infer schedule timezone, using the global one")
+			continue
+		}
+		if timezone == "" {
+			continue
+		}
+
+		sch.Timezone = config.Timezone(timezone)
+		inferred++
+		log.Debug().
+			Str("schedule", sch.Name).
+			Str("timezone", timezone).
+			Msg("Schedule timezone inferred from labels")
+	}
+
+	if inferred > 0 {
+		log.Info().
+			Str("label", i.label).
+			Int("schedules", inferred).
+			Msg("Schedule timezones inferred from labels")
+	}
+}
+
+// timezoneResult is a cached label lookup.
+type timezoneResult struct {
+	timezone string
+	err      error
+}
+
+// timezoneLookup reads timezones for one pass over the schedules.
+type timezoneLookup struct {
+	inferrer *timezoneInferrer
+	cache    map[string]timezoneResult
+}
+
+// schedule returns the timezone all resources of sch agree on, or an empty
+// string when none of them has one.
+func (l *timezoneLookup) schedule(ctx context.Context, sch config.Schedule) (string, error) {
+	var timezone string
+	for n, target := range sch.Targets() {
+		current, err := l.resource(ctx, target)
+		if err != nil {
+			return "", err
+		}
+		if n > 0 && current != timezone {
+			return "", fmt.Errorf("resources are in different timezones %q and %q", timezone, current)
+		}
+		timezone = current
+	}
+	return timezone, nil
+}
+
+// resource returns the timezone of the resource, falling back to its folder.
+// Resources selected by name pattern use the timezone of their folder.
+func (l *timezoneLookup) resource(ctx context.Context, target config.Resource) (string, error) {
+	if target.ID != "" {
+		key := target.Type + ":" + target.ID
+		timezone, err := l.cached(key, func() (map[string]string, error) {
+			return l.inferrer.reader.GetLabels(ctx, target)
+		})
+		if err != nil || timezone != "" {
+			return timezone, err
+		}
+	}
+	if target.FolderID == "" {
+		return "", nil
+	}
+	return l.cached("folder:"+target.FolderID, func() (map[string]string, error) {
+		return l.inferrer.reader.GetFolderLabels(ctx, target.FolderID)
+	})
+}
+
+// cached returns the timezone in the labels read by labels, reading them once
+// per key.
+func (l *timezoneLookup) cached(key string, labels func() (map[string]string, error)) (string, error) {
+	if result, ok := l.cache[key]; ok {
+		return result.timezone, result.err
+	}
+
+	var result timezoneResult
+	values, err := labels()
+	if err != nil {
+		result.err = fmt.Errorf("read labels of %s: %w", key, err)
+	} else if value := values[l.inferrer.label]; value != "" {
+		result.timezone, result.err = labelTimezone(value)
+	}
+	l.cache[key] = result
+	return result.timezone, result.err
+}
+
+// labelTimezone converts a label value into an IANA timezone name. Label
+// values are lowercase, so "europe/moscow" and "utc" are matched as
+// "Europe/Moscow" and "UTC".
+func labelTimezone(value string) (string, error) {
+	titled := []rune(value)
+	for n, r := range titled {
+		if n == 0 || strings.ContainsRune("/_-", titled[n-1]) {
+			titled[n] = unicode.ToUpper(r)
+		}
+	}
+
+	for _, name := range []string{value, string(titled), strings.ToUpper(value)} {
+		if _, err := time.LoadLocation(name); err == nil && name != "Local" {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("unknown timezone %q", value)
+}
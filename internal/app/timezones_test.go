@@ -0,0 +1,113 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+// fakeLabelReader serves resource labels by ID and folder labels by folder ID.
+type fakeLabelReader struct {
+	resources map[string]map[string]string
+	folders   map[string]map[string]string
+	reads     int
+}
+
+func (f *fakeLabelReader) GetLabels(_ context.Context, resource config.Resource) (map[string]string, error) {
+	f.reads++
+	return f.resources[resource.ID], nil
+}
+
+func (f *fakeLabelReader) GetFolderLabels(_ context.Context, folderID string) (map[string]string, error) {
+	f.reads++
+	labels, ok := f.folders[folderID]
+	if !ok {
+		return nil, errors.New("folder not found")
+	}
+	return labels, nil
+}
+
+func TestTimezoneInferrer(t *testing.T) {
+	reader := &fakeLabelReader{
+		resources: map[string]map[string]string{
+			"vm-ural": {"timezone": "asia/yekaterinburg"},
+			"vm-utc":  {"timezone": "utc"},
+		},
+		folders: map[string]map[string]string{
+			"folder-msk": {"timezone": "europe/moscow"},
+			"folder-ny":  {"timezone": "america/new_york"},
+		},
+	}
+	vm := func(id, folder string) config.Resource {
+		return config.Resource{Type: "vm", ID: id, FolderID: folder}
+	}
+
+	schedules := []config.Schedule{
+		{Name: "resource", Type: "daily", Resource: vm("vm-ural", "folder-msk")},
+		{Name: "folder", Type: "daily", Resource: vm("vm-1", "folder-msk")},
+		{Name: "pattern", Type: "weekly", Resource: config.Resource{Type: "vm", NamePattern: "dev-*", FolderID: "folder-ny"}},
+		{Name: "upper", Type: "cron", Resource: vm("vm-utc", "folder-msk")},
+		{Name: "explicit", Type: "daily", Timezone: "Asia/Tokyo", Resource: vm("vm-ural", "folder-msk")},
+		{Name: "duration", Type: "duration", Resource: vm("vm-ural", "folder-msk")},
+		{Name: "mixed", Type: "daily", Resources: []config.Resource{vm("vm-1", "folder-msk"), vm("vm-2", "folder-ny")}},
+		{Name: "agreed", Type: "daily", Resources: []config.Resource{vm("vm-1", "folder-msk"), vm("vm-2", "folder-msk")}},
+		{Name: "unreadable", Type: "daily", Resource: vm("vm-3", "folder-missing")},
+	}
+	newTimezoneInferrer(reader, "timezone").Apply(context.Background(), schedules)
+
+	want := map[string]config.Timezone{
+		"resource":   "Asia/Yekaterinburg",
+		"folder":     "Europe/Moscow",
+		"pattern":    "America/New_York",
+		"upper":      "UTC",
+		"explicit":   "Asia/Tokyo",
+		"duration":   "",
+		"mixed":      "",
+		"agreed":     "Europe/Moscow",
+		"unreadable": "",
+	}
+	for _, sch := range schedules {
+		if sch.Timezone != want[sch.Name] {
+			t.Errorf("schedule %q timezone = %q, want %q", sch.Name, sch.Timezone, want[sch.Name])
+		}
+	}
+
+	// Labels of resources and folders are read once per pass.
+	if reader.reads != 8 {
+		t.Fatalf("label reads = %d, want 8", reader.reads)
+	}
+}
+
+func TestTimezoneInferrerDisabled(t *testing.T) {
+	reader := &fakeLabelReader{}
+	schedules := []config.Schedule{{Name: "vm", Type: "daily", Resource: config.Resource{Type: "vm", ID: "vm-1", FolderID: "folder-1"}}}
+
+	newTimezoneInferrer(reader, "").Apply(context.Background(), schedules)
+
+	if schedules[0].Timezone != "" || reader.reads != 0 {
+		t.Fatalf("timezone = %q after %d reads, want no inference", schedules[0].Timezone, reader.reads)
+	}
+}
+
+func TestLabelTimezone(t *testing.T) {
+	tests := map[string]string{
+		"europe/moscow":                  "Europe/Moscow",
+		"america/argentina/buenos_aires": "America/Argentina/Buenos_Aires",
+		"utc":                            "UTC",
+		"Asia/Tokyo":                     "Asia/Tokyo",
+	}
+	for value, want := range tests {
+		got, err := labelTimezone(value)
+		if err != nil || got != want {
+			t.Errorf("labelTimezone(%q) = %q, %v, want %q", value, got, err, want)
+		}
+	}
+
+	for _, value := range []string{"mars/olympus", "local"} {
+		if _, err := labelTimezone(value); err == nil {
+			t.Errorf("labelTimezone(%q) succeeded, want an error", value)
+		}
+	}
+}
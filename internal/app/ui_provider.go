@@ -59,6 +59,9 @@ func (p *UIProvider) ValidationInterval() string {
 	if p == nil {
 		return ""
 	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	return p.validationInterval
 }
 
@@ -67,9 +70,25 @@ func (p *UIProvider) ValidationResources() bool {
 	if p == nil {
 		return false
 	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	return p.validationResources
 }
 
+// SetValidation sets the validator settings shown in the UI, e.g. when the
+// config file is reloaded.
+func (p *UIProvider) SetValidation(validationInterval string, validationResources bool) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.validationInterval = validationInterval
+	p.validationResources = validationResources
+}
+
 // ResourceStatuses returns the current state for unique resources referenced by schedules.
 func (p *UIProvider) ResourceStatuses(ctx context.Context, schedules []config.Schedule) map[string]web.ResourceStatus {
 	statuses := make(map[string]web.ResourceStatus)
@@ -79,13 +98,19 @@ func (p *UIProvider) ResourceStatuses(ctx context.Context, schedules []config.Sc
 
 	seen := make(map[string]struct{}, len(schedules))
 	for _, schedule := range schedules {
-		key := web.ResourceKey(schedule.Resource)
-		if _, exists := seen[key]; exists {
-			continue
+		for _, target := range schedule.Targets() {
+			// Name patterns do not identify a single resource to show.
+			if target.IsPattern() {
+				continue
+			}
+			key := web.ResourceKey(target)
+			if _, exists := seen[key]; exists {
+				continue
+			}
+			seen[key] = struct{}{}
+
+			statuses[key] = p.getResourceStatus(ctx, key, target)
 		}
-		seen[key] = struct{}{}
-
-		statuses[key] = p.getResourceStatus(ctx, key, schedule.Resource)
 	}
 
 	return statuses
@@ -111,6 +136,9 @@ func (p *UIProvider) getResourceStatus(ctx context.Context, key string, resource
 			Msg("Failed to fetch resource state for UI")
 		status.State = "unknown"
 		status.Error = "failed to fetch state"
+		// Show the last known state rather than a blank status during API
+		// hiccups.
+		p.applyLastKnownState(&status, resource)
 	} else {
 		status.State = state
 		status.IsTransitional = isTransitional
@@ -126,6 +154,23 @@ func (p *UIProvider) getResourceStatus(ctx context.Context, key string, resource
 	return status
 }
 
+// applyLastKnownState fills status with the last known state of the target
+// if the state checker remembers one.
+func (p *UIProvider) applyLastKnownState(status *web.ResourceStatus, target config.Resource) {
+	reader, ok := p.stateChecker.(resource.KnownStateReader)
+	if !ok {
+		return
+	}
+	known, found := reader.LastKnownState(target)
+	if !found {
+		return
+	}
+	status.State = known.State
+	status.IsTransitional = known.IsTransitional
+	status.ObservedAt = known.ObservedAt
+	status.Stale = true
+}
+
 type cachedResourceStatus struct {
 	expiresAt time.Time
 	status    web.ResourceStatus
@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/resource"
+	"github.com/sentoz/yc-sheduler/internal/scheduleset"
 )
 
 func TestUIProviderCachesResourceStatus(t *testing.T) {
@@ -19,7 +21,7 @@ func TestUIProviderCachesResourceStatus(t *testing.T) {
 		},
 	}
 
-	store := NewScheduleStore("Europe/Moscow", nil)
+	store := NewScheduleStore("Europe/Moscow", scheduleset.NewStore(nil))
 	provider := NewUIProvider(store, checker, "10m", true)
 
 	current := time.Date(2026, time.April, 29, 12, 0, 0, 0, time.UTC)
@@ -58,7 +60,7 @@ func TestUIProviderSanitizesResourceStatusError(t *testing.T) {
 		},
 	}
 
-	store := NewScheduleStore("Europe/Moscow", nil)
+	store := NewScheduleStore("Europe/Moscow", scheduleset.NewStore(nil))
 	provider := NewUIProvider(store, checker, "10m", true)
 
 	schedules := []config.Schedule{
@@ -90,3 +92,34 @@ type fakeStateChecker struct {
 func (f fakeStateChecker) GetState(ctx context.Context, resource config.Resource) (string, bool, error) {
 	return f.getState(ctx, resource)
 }
+
+type knownStateChecker struct {
+	fakeStateChecker
+	known resource.KnownState
+}
+
+func (c knownStateChecker) LastKnownState(config.Resource) (resource.KnownState, bool) {
+	return c.known, true
+}
+
+func TestUIProviderFallsBackToLastKnownState(t *testing.T) {
+	observedAt := time.Date(2026, time.April, 29, 11, 0, 0, 0, time.UTC)
+	checker := knownStateChecker{
+		fakeStateChecker: fakeStateChecker{
+			getState: func(context.Context, config.Resource) (string, bool, error) {
+				return "", false, errors.New("unavailable")
+			},
+		},
+		known: resource.KnownState{State: "stopped", ObservedAt: observedAt},
+	}
+
+	provider := NewUIProvider(NewScheduleStore("Europe/Moscow", scheduleset.NewStore(nil)), checker, "10m", true)
+	schedules := []config.Schedule{
+		{Name: "a", Resource: config.Resource{Type: "vm", ID: "id", FolderID: "folder"}},
+	}
+
+	status := provider.ResourceStatuses(t.Context(), schedules)["vm:folder:id"]
+	if status.State != "stopped" || !status.Stale || !status.ObservedAt.Equal(observedAt) {
+		t.Fatalf("status = %+v, want stale last known state", status)
+	}
+}
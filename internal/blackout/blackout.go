@@ -0,0 +1,112 @@
+// Package blackout tracks maintenance windows during which no operations run.
+package blackout
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Window is a period during which no operations run. It combines an optional
+// absolute range, optional days and an optional time of day; a moment is in
+// the window when it satisfies all of them.
+type Window struct {
+	// From and Until bound the window; a zero bound leaves it open.
+	From  time.Time
+	Until time.Time
+	// Dates (YYYY-MM-DD) and Weekdays select the days of the window. If both
+	// are empty, every day is selected.
+	Dates    []string
+	Weekdays []time.Weekday
+	Name     string
+	Reason   string
+	// Start and End are offsets of the daily time range from midnight. Equal
+	// offsets cover whole days. A range with End before Start spans midnight
+	// and belongs to the day it starts on.
+	Start time.Duration
+	End   time.Duration
+}
+
+// Active reports whether t is within the window.
+func (w Window) Active(t time.Time) bool {
+	if !w.From.IsZero() && t.Before(w.From) {
+		return false
+	}
+	if !w.Until.IsZero() && !t.Before(w.Until) {
+		return false
+	}
+
+	if w.Start == w.End {
+		return w.onDay(t)
+	}
+	offset := sinceMidnight(t)
+	if w.Start < w.End {
+		return offset >= w.Start && offset < w.End && w.onDay(t)
+	}
+	if offset >= w.Start {
+		return w.onDay(t)
+	}
+	return offset < w.End && w.onDay(t.AddDate(0, 0, -1))
+}
+
+// onDay reports whether the day of t is selected by the window.
+func (w Window) onDay(t time.Time) bool {
+	if len(w.Dates) == 0 && len(w.Weekdays) == 0 {
+		return true
+	}
+	return slices.Contains(w.Dates, t.Format(time.DateOnly)) || slices.Contains(w.Weekdays, t.Weekday())
+}
+
+func sinceMidnight(t time.Time) time.Duration {
+	hour, minute, second := t.Clock()
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute + time.Duration(second)*time.Second
+}
+
+// Calendar holds the configured blackout windows. Days and times of day of
+// the windows are evaluated in the calendar location.
+type Calendar struct {
+	mu       sync.RWMutex
+	location *time.Location
+	windows  []Window
+}
+
+// New creates a Calendar of windows evaluated in location. A nil location
+// means time.Local.
+func New(windows []Window, location *time.Location) *Calendar {
+	if location == nil {
+		location = time.Local
+	}
+	return &Calendar{location: location, windows: slices.Clone(windows)}
+}
+
+// Active returns the first window containing now. A nil Calendar has no
+// windows.
+func (c *Calendar) Active(now time.Time) (Window, bool) {
+	if c == nil {
+		return Window{}, false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now = now.In(c.location)
+	for _, w := range c.windows {
+		if w.Active(now) {
+			return w, true
+		}
+	}
+	return Window{}, false
+}
+
+// Replace replaces the windows and location of c with those of next, so the
+// components sharing c see the windows of a reloaded config file.
+func (c *Calendar) Replace(next *Calendar) {
+	next.mu.RLock()
+	location, windows := next.location, next.windows
+	next.mu.RUnlock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.location, c.windows = location, windows
+}
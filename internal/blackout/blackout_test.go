@@ -0,0 +1,113 @@
+package blackout
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindowActive(t *testing.T) {
+	t.Parallel()
+
+	// 2026-10-16 is a Friday.
+	friday := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		window Window
+		at     time.Time
+		want   bool
+	}{
+		{
+			name:   "inside absolute range",
+			window: Window{From: friday, Until: friday.Add(6 * time.Hour)},
+			at:     friday.Add(time.Hour),
+			want:   true,
+		},
+		{
+			name:   "at end of absolute range",
+			window: Window{From: friday, Until: friday.Add(6 * time.Hour)},
+			at:     friday.Add(6 * time.Hour),
+			want:   false,
+		},
+		{
+			name:   "whole date",
+			window: Window{Dates: []string{"2026-10-16"}},
+			at:     friday.Add(23 * time.Hour),
+			want:   true,
+		},
+		{
+			name:   "other date",
+			window: Window{Dates: []string{"2026-10-16"}},
+			at:     friday.Add(25 * time.Hour),
+			want:   false,
+		},
+		{
+			name:   "weekday time range",
+			window: Window{Weekdays: []time.Weekday{time.Friday}, Start: 20 * time.Hour, End: 23 * time.Hour},
+			at:     friday.Add(21 * time.Hour),
+			want:   true,
+		},
+		{
+			name:   "weekday before time range",
+			window: Window{Weekdays: []time.Weekday{time.Friday}, Start: 20 * time.Hour, End: 23 * time.Hour},
+			at:     friday.Add(19 * time.Hour),
+			want:   false,
+		},
+		{
+			name:   "overnight range continues after midnight",
+			window: Window{Weekdays: []time.Weekday{time.Friday}, Start: 22 * time.Hour, End: 2 * time.Hour},
+			at:     friday.Add(25 * time.Hour),
+			want:   true,
+		},
+		{
+			name:   "overnight range does not start on the next day",
+			window: Window{Weekdays: []time.Weekday{time.Friday}, Start: 22 * time.Hour, End: 2 * time.Hour},
+			at:     friday.Add(time.Hour),
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.window.Active(tt.at); got != tt.want {
+				t.Fatalf("Active(%s) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendarActiveUsesLocation(t *testing.T) {
+	t.Parallel()
+
+	moscow := time.FixedZone("MSK", 3*60*60)
+	c := New([]Window{{Name: "release", Start: 22 * time.Hour, End: 23 * time.Hour}}, moscow)
+
+	// 19:30 UTC is 22:30 in Moscow.
+	if w, ok := c.Active(time.Date(2026, 10, 16, 19, 30, 0, 0, time.UTC)); !ok || w.Name != "release" {
+		t.Fatalf("Active() = %+v, %v; want window release", w, ok)
+	}
+	if _, ok := c.Active(time.Date(2026, 10, 16, 22, 30, 0, 0, time.UTC)); ok {
+		t.Fatal("Active() = true at 01:30 in Moscow")
+	}
+
+	var none *Calendar
+	if _, ok := none.Active(time.Now()); ok {
+		t.Fatal("nil Calendar has an active window")
+	}
+}
+
+func TestCalendarReplace(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2026, 10, 16, 19, 30, 0, 0, time.UTC)
+	c := New([]Window{{Name: "release", Start: 22 * time.Hour, End: 23 * time.Hour}}, time.UTC)
+	if _, ok := c.Active(at); ok {
+		t.Fatal("Active() = true at 19:30 UTC")
+	}
+
+	c.Replace(New([]Window{{Name: "freeze", Start: 22 * time.Hour, End: 23 * time.Hour}}, time.FixedZone("MSK", 3*60*60)))
+	if w, ok := c.Active(at); !ok || w.Name != "freeze" {
+		t.Fatalf("Active() = %+v, %v; want window freeze", w, ok)
+	}
+}
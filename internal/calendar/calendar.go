@@ -2,6 +2,7 @@ package calendar
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -9,12 +10,15 @@ import (
 	"github.com/robfig/cron/v3"
 
 	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/schedule"
 )
 
 // Event represents a single scheduled action occurrence in the calendar.
 type Event struct {
 	ScheduleName        string `json:"schedule_name"`
 	ScheduleDisplayName string `json:"schedule_display_name"`
+	Description         string `json:"description,omitempty"`
+	RunbookURL          string `json:"runbook_url,omitempty"`
 	ResourceType        string `json:"resource_type"`
 	ResourceID          string `json:"resource_id"`
 	FolderID            string `json:"folder_id,omitempty"`
@@ -26,6 +30,10 @@ type Event struct {
 	State               string `json:"state,omitempty"`
 	StatusError         string `json:"status_error,omitempty"`
 	Transitional        bool   `json:"transitional,omitempty"`
+	// Stale marks State as the last known state observed at ObservedAt,
+	// shown when the live state cannot be read.
+	Stale      bool      `json:"stale,omitempty"`
+	ObservedAt time.Time `json:"observed_at,omitzero"`
 }
 
 // EventsInRange expands schedules into concrete calendar events in the inclusive
@@ -50,20 +58,65 @@ func EventsInRange(schedules []config.Schedule, timezone string, from, to time.T
 	rangeEndExclusive := endDate.AddDate(0, 0, 1)
 
 	events := make([]Event, 0)
-	for _, schedule := range schedules {
-		if schedule.Actions.Start != nil && schedule.Actions.Start.Enabled {
-			actionEvents, err := expandAction(schedule, "start", schedule.Actions.Start, rangeStart, rangeEndExclusive, location)
-			if err != nil {
-				return nil, err
+	for _, multi := range schedules {
+		zone := schedule.Location(multi, location)
+		for _, target := range multi.Targets() {
+			schedule := multi.ForResource(target)
+			if schedule.Actions.Start != nil && schedule.Actions.Start.Enabled {
+				actionEvents, err := expandAction(schedule, "start", schedule.Actions.Start, rangeStart, rangeEndExclusive, zone, location)
+				if err != nil {
+					return nil, err
+				}
+				events = append(events, actionEvents...)
 			}
-			events = append(events, actionEvents...)
-		}
-		if schedule.Actions.Stop != nil && schedule.Actions.Stop.Enabled {
-			actionEvents, err := expandAction(schedule, "stop", schedule.Actions.Stop, rangeStart, rangeEndExclusive, location)
-			if err != nil {
-				return nil, err
+			if schedule.Actions.Stop != nil && schedule.Actions.Stop.Enabled {
+				actionEvents, err := expandAction(schedule, "stop", schedule.Actions.Stop, rangeStart, rangeEndExclusive, zone, location)
+				if err != nil {
+					return nil, err
+				}
+				events = append(events, actionEvents...)
+			}
+			if schedule.Actions.Snapshot != nil && schedule.Actions.Snapshot.Enabled {
+				actionEvents, err := expandAction(schedule, "snapshot", schedule.Actions.Snapshot, rangeStart, rangeEndExclusive, zone, location)
+				if err != nil {
+					return nil, err
+				}
+				events = append(events, actionEvents...)
+			}
+			if schedule.Actions.Restart != nil && schedule.Actions.Restart.Enabled {
+				actionEvents, err := expandAction(schedule, "restart", schedule.Actions.Restart, rangeStart, rangeEndExclusive, zone, location)
+				if err != nil {
+					return nil, err
+				}
+				events = append(events, actionEvents...)
+			}
+			if schedule.Actions.Resize != nil && schedule.Actions.Resize.Enabled {
+				actionEvents, err := expandAction(schedule, "resize", schedule.Actions.Resize, rangeStart, rangeEndExclusive, zone, location)
+				if err != nil {
+					return nil, err
+				}
+				events = append(events, actionEvents...)
+			}
+			for _, entry := range schedule.Actions.Scale {
+				if !entry.Enabled {
+					continue
+				}
+				actionEvents, err := expandAction(schedule, "scale", &entry, rangeStart, rangeEndExclusive, zone, location)
+				if err != nil {
+					return nil, err
+				}
+				events = append(events, actionEvents...)
+			}
+			for _, entry := range schedule.Actions.Preemptible {
+				if !entry.Enabled {
+					continue
+				}
+				actionEvents, err := expandAction(schedule, "preemptible", &entry, rangeStart, rangeEndExclusive, zone, location)
+				if err != nil {
+					return nil, err
+				}
+				events = append(events, actionEvents...)
 			}
-			events = append(events, actionEvents...)
 		}
 	}
 
@@ -74,7 +127,10 @@ func EventsInRange(schedules []config.Schedule, timezone string, from, to time.T
 		if events[i].ScheduleName != events[j].ScheduleName {
 			return events[i].ScheduleName < events[j].ScheduleName
 		}
-		return events[i].Action < events[j].Action
+		if events[i].Action != events[j].Action {
+			return events[i].Action < events[j].Action
+		}
+		return events[i].ResourceKey < events[j].ResourceKey
 	})
 
 	return events, nil
@@ -86,18 +142,51 @@ func expandAction(
 	action *config.ActionConfig,
 	rangeStart time.Time,
 	rangeEndExclusive time.Time,
+	zone *time.Location,
 	location *time.Location,
 ) ([]Event, error) {
+	// Days of the range are walked in the timezone of the schedule, and events
+	// are shown in the calendar location.
+	firstDay := dateOnly(rangeStart.In(zone))
+	endDay := rangeEndExclusive.In(zone)
+	inRange := func(at time.Time) bool {
+		return !at.Before(rangeStart) && at.Before(rangeEndExclusive)
+	}
+
 	switch schedule.Type {
-	case "daily":
+	case "daily", "random_window":
 		hour, minute, second, err := parseClock(action.Time)
 		if err != nil {
-			return nil, fmt.Errorf("calendar: daily schedule %q: %w", schedule.Name, err)
+			return nil, fmt.Errorf("calendar: %s schedule %q: %w", schedule.Type, schedule.Name, err)
+		}
+		events := make([]Event, 0)
+		for day := firstDay; day.Before(endDay); day = day.AddDate(0, 0, 1) {
+			at := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, second, 0, zone)
+			if inRange(at) {
+				events = append(events, newEvent(schedule, actionName, at.In(location)))
+			}
+		}
+		return events, nil
+	case "one-time":
+		at, err := action.At.Time()
+		if err != nil {
+			return nil, fmt.Errorf("calendar: one-time schedule %q: %w", schedule.Name, err)
 		}
+		at = at.In(location)
+		if at.Before(rangeStart) || !at.Before(rangeEndExclusive) {
+			return []Event{}, nil
+		}
+		return []Event{newEvent(schedule, actionName, at)}, nil
+	case "duration":
 		events := make([]Event, 0)
 		for day := rangeStart; day.Before(rangeEndExclusive); day = day.AddDate(0, 0, 1) {
-			at := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, second, 0, location)
-			events = append(events, newEvent(schedule, actionName, at))
+			runs, err := durationRuns(action, day, location)
+			if err != nil {
+				return nil, fmt.Errorf("calendar: duration schedule %q: %w", schedule.Name, err)
+			}
+			for _, at := range runs {
+				events = append(events, newEvent(schedule, actionName, at))
+			}
 		}
 		return events, nil
 	case "weekly":
@@ -105,16 +194,21 @@ func expandAction(
 		if err != nil {
 			return nil, fmt.Errorf("calendar: weekly schedule %q: %w", schedule.Name, err)
 		}
-		if action.Day < 0 || action.Day > 6 {
-			return nil, fmt.Errorf("calendar: weekly schedule %q: invalid day %d", schedule.Name, action.Day)
+		weekdays := action.WeekDays()
+		for _, weekday := range weekdays {
+			if weekday < 0 || weekday > 6 {
+				return nil, fmt.Errorf("calendar: weekly schedule %q: invalid day %d", schedule.Name, weekday)
+			}
 		}
 		events := make([]Event, 0)
-		for day := rangeStart; day.Before(rangeEndExclusive); day = day.AddDate(0, 0, 1) {
-			if int(day.Weekday()) != action.Day {
+		for day := firstDay; day.Before(endDay); day = day.AddDate(0, 0, 1) {
+			if !slices.Contains(weekdays, int(day.Weekday())) {
 				continue
 			}
-			at := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, second, 0, location)
-			events = append(events, newEvent(schedule, actionName, at))
+			at := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, second, 0, zone)
+			if inRange(at) {
+				events = append(events, newEvent(schedule, actionName, at.In(location)))
+			}
 		}
 		return events, nil
 	case "monthly":
@@ -126,12 +220,14 @@ func expandAction(
 			return nil, fmt.Errorf("calendar: monthly schedule %q: invalid day %d", schedule.Name, action.Day)
 		}
 		events := make([]Event, 0)
-		for day := rangeStart; day.Before(rangeEndExclusive); day = day.AddDate(0, 0, 1) {
+		for day := firstDay; day.Before(endDay); day = day.AddDate(0, 0, 1) {
 			if day.Day() != action.Day {
 				continue
 			}
-			at := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, second, 0, location)
-			events = append(events, newEvent(schedule, actionName, at))
+			at := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, second, 0, zone)
+			if inRange(at) {
+				events = append(events, newEvent(schedule, actionName, at.In(location)))
+			}
 		}
 		return events, nil
 	case "cron":
@@ -145,7 +241,7 @@ func expandAction(
 		events := make([]Event, 0)
 		cursor := rangeStart.Add(-time.Second)
 		for {
-			next := cronSchedule.Next(cursor.In(location))
+			next := cronSchedule.Next(cursor.In(zone))
 			if !next.Before(rangeEndExclusive) {
 				break
 			}
@@ -164,8 +260,10 @@ func newEvent(schedule config.Schedule, actionName string, at time.Time) Event {
 	return Event{
 		ScheduleName:        schedule.Name,
 		ScheduleDisplayName: scheduleDisplayName(schedule),
+		Description:         schedule.Description,
+		RunbookURL:          schedule.RunbookURL,
 		ResourceType:        schedule.Resource.Type,
-		ResourceID:          schedule.Resource.ID,
+		ResourceID:          schedule.Resource.Identifier(),
 		FolderID:            schedule.Resource.FolderID,
 		ResourceKey:         resourceKey(schedule.Resource),
 		Action:              actionName,
@@ -175,6 +273,19 @@ func newEvent(schedule config.Schedule, actionName string, at time.Time) Event {
 	}
 }
 
+// durationRuns returns the runs of a duration schedule action on day.
+func durationRuns(action *config.ActionConfig, day time.Time, location *time.Location) ([]time.Time, error) {
+	offsets, err := schedule.IntervalOffsets(action)
+	if err != nil {
+		return nil, err
+	}
+	runs := make([]time.Time, 0, len(offsets))
+	for _, offset := range offsets {
+		runs = append(runs, schedule.AtOffset(day, offset, location))
+	}
+	return runs, nil
+}
+
 func parseClock(value string) (hour, minute, second int, err error) {
 	for _, layout := range []string{"15:04:05", "15:04"} {
 		parsed, parseErr := time.Parse(layout, value)
@@ -214,7 +325,7 @@ func FormatMonthTitle(t time.Time) string {
 }
 
 func resourceKey(resource config.Resource) string {
-	return resource.Type + ":" + resource.FolderID + ":" + resource.ID
+	return resource.Type + ":" + resource.FolderID + ":" + resource.Identifier()
 }
 
 func scheduleDisplayName(schedule config.Schedule) string {
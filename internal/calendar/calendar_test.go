@@ -1,6 +1,7 @@
 package calendar
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -23,6 +24,44 @@ func TestEventsInRangeDaily(t *testing.T) {
 	}
 }
 
+func TestEventsInRangeScheduleTimezone(t *testing.T) {
+	// 08:00 in Yekaterinburg is 06:00 in Moscow.
+	sch := makeSchedule("vm-ural", "daily", "start", &config.ActionConfig{Enabled: true, Time: "08:00"})
+	sch.Timezone = "Asia/Yekaterinburg"
+
+	events, err := EventsInRange([]config.Schedule{sch}, "Europe/Moscow", mustDate(t, "2026-04-01"), mustDate(t, "2026-04-02"))
+	if err != nil {
+		t.Fatalf("EventsInRange() error = %v", err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("len(events) = %d, want 2", len(events))
+	}
+	if events[0].LocalDate != "2026-04-01" || events[0].LocalTime != "06:00:00" {
+		t.Fatalf("first event = %+v, want 2026-04-01 06:00:00", events[0])
+	}
+}
+
+func TestEventsInRangeScheduleTimezoneCrossesDate(t *testing.T) {
+	// 01:00 in Yekaterinburg is 23:00 of the previous day in Moscow.
+	sch := makeSchedule("vm-ural", "weekly", "stop", &config.ActionConfig{Enabled: true, Time: "01:00", Day: 2})
+	sch.Timezone = "Asia/Yekaterinburg"
+
+	events, err := EventsInRange([]config.Schedule{sch}, "Europe/Moscow", mustDate(t, "2026-04-01"), mustDate(t, "2026-04-14"))
+	if err != nil {
+		t.Fatalf("EventsInRange() error = %v", err)
+	}
+
+	var got []string
+	for _, event := range events {
+		got = append(got, event.LocalDate+" "+event.LocalTime)
+	}
+	want := []string{"2026-04-06 23:00:00", "2026-04-13 23:00:00"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("events = %v, want Mondays %v", got, want)
+	}
+}
+
 func TestEventsInRangeWeekly(t *testing.T) {
 	events, err := EventsInRange([]config.Schedule{
 		makeSchedule("vm-weekly", "weekly", "stop", &config.ActionConfig{Enabled: true, Time: "18:30", Day: 1}),
@@ -39,6 +78,62 @@ func TestEventsInRangeWeekly(t *testing.T) {
 	}
 }
 
+func TestEventsInRangeWeeklyDays(t *testing.T) {
+	events, err := EventsInRange([]config.Schedule{
+		makeSchedule("vm-workdays", "weekly", "start", &config.ActionConfig{Enabled: true, Time: "09:00", Days: []int{1, 2, 3, 4, 5}}),
+	}, "Europe/Moscow", mustDate(t, "2026-04-01"), mustDate(t, "2026-04-07"))
+	if err != nil {
+		t.Fatalf("EventsInRange() error = %v", err)
+	}
+
+	var dates []string
+	for _, event := range events {
+		dates = append(dates, event.LocalDate)
+	}
+	want := []string{"2026-04-01", "2026-04-02", "2026-04-03", "2026-04-06", "2026-04-07"}
+	if strings.Join(dates, ",") != strings.Join(want, ",") {
+		t.Fatalf("events dates = %v, want weekdays %v", dates, want)
+	}
+}
+
+func TestEventsInRangeDuration(t *testing.T) {
+	events, err := EventsInRange([]config.Schedule{
+		makeSchedule("vm-batch", "duration", "start", &config.ActionConfig{Enabled: true, Time: "08:00", Every: config.Duration{Duration: 4 * time.Hour}}),
+	}, "Europe/Moscow", mustDate(t, "2026-04-01"), mustDate(t, "2026-04-01"))
+	if err != nil {
+		t.Fatalf("EventsInRange() error = %v", err)
+	}
+
+	var times []string
+	for _, event := range events {
+		times = append(times, event.LocalTime)
+	}
+	want := []string{"08:00:00", "12:00:00", "16:00:00", "20:00:00"}
+	if strings.Join(times, ",") != strings.Join(want, ",") {
+		t.Fatalf("events times = %v, want %v", times, want)
+	}
+
+	_, err = EventsInRange([]config.Schedule{
+		makeSchedule("vm-busy", "duration", "start", &config.ActionConfig{Enabled: true, Every: config.Duration{Duration: time.Second}}),
+	}, "Europe/Moscow", mustDate(t, "2026-04-01"), mustDate(t, "2026-04-01"))
+	if err == nil {
+		t.Fatal("EventsInRange() with a 1s interval succeeded, want an error")
+	}
+}
+
+func TestEventsInRangeOneTime(t *testing.T) {
+	events, err := EventsInRange([]config.Schedule{
+		makeSchedule("cluster-migration", "one-time", "start", &config.ActionConfig{Enabled: true, At: "2026-04-02T00:00:00+03:00"}),
+	}, "Europe/Moscow", mustDate(t, "2026-04-01"), mustDate(t, "2026-04-30"))
+	if err != nil {
+		t.Fatalf("EventsInRange() error = %v", err)
+	}
+
+	if len(events) != 1 || events[0].LocalDate != "2026-04-02" || events[0].LocalTime != "00:00:00" {
+		t.Fatalf("events = %+v, want a single run at 2026-04-02 00:00", events)
+	}
+}
+
 func TestEventsInRangeMonthly(t *testing.T) {
 	events, err := EventsInRange([]config.Schedule{
 		makeSchedule("vm-monthly", "monthly", "start", &config.ActionConfig{Enabled: true, Time: "07:15", Day: 15}),
@@ -0,0 +1,145 @@
+// Package chaos injects failures into Yandex Cloud API calls for resilience
+// testing: errors, slow calls and resources in an invalid state. It is meant
+// for development against the fake provider and is never enabled by default.
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	computepb "github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
+	k8spb "github.com/yandex-cloud/go-genproto/yandex/cloud/k8s/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Config configures the injected failures. Rates are fractions of matching
+// calls in [0, 1].
+type Config struct {
+	ErrorRate        float64       `hidden:"true" long:"chaos-error-rate" description:"Fraction of YC API calls failing with Unavailable" default:"0"`
+	SlowRate         float64       `hidden:"true" long:"chaos-slow-rate" description:"Fraction of YC API calls delayed by --chaos-slow-delay" default:"0"`
+	SlowDelay        time.Duration `hidden:"true" long:"chaos-slow-delay" description:"Delay of slow YC API calls" default:"5s"`
+	InvalidStateRate float64       `hidden:"true" long:"chaos-invalid-state-rate" description:"Fraction of resource reads returning an unspecified status" default:"0"`
+	Methods          []string      `hidden:"true" long:"chaos-method" description:"Inject only into gRPC methods containing the value (repeatable; all methods by default)"`
+}
+
+// Enabled reports whether any failure is injected.
+func (c Config) Enabled() bool {
+	return c.ErrorRate > 0 || c.SlowRate > 0 || c.InvalidStateRate > 0
+}
+
+// Validate checks that rates are fractions and the delay is not negative.
+func (c Config) Validate() error {
+	for name, rate := range map[string]float64{
+		"error rate":         c.ErrorRate,
+		"slow rate":          c.SlowRate,
+		"invalid state rate": c.InvalidStateRate,
+	} {
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("chaos: %s %v is not within [0, 1]", name, rate)
+		}
+	}
+	if c.SlowDelay < 0 {
+		return fmt.Errorf("chaos: slow delay %s is negative", c.SlowDelay)
+	}
+	return nil
+}
+
+// Injector injects the configured failures into gRPC calls.
+type Injector struct {
+	cfg Config
+	// roll returns a random number in [0, 1).
+	roll func() float64
+}
+
+// New creates an injector for cfg.
+func New(cfg Config) *Injector {
+	return &Injector{cfg: cfg, roll: rand.Float64}
+}
+
+// UnaryServerInterceptor injects failures into calls served by a fake
+// provider.
+func (i *Injector) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !i.matches(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		if err := i.before(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		resp, err := handler(ctx, req)
+		if err == nil {
+			i.after(info.FullMethod, resp)
+		}
+		return resp, err
+	}
+}
+
+// UnaryClientInterceptor injects failures into calls of the yc client.
+func (i *Injector) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if !i.matches(method) {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		if err := i.before(ctx, method); err != nil {
+			return err
+		}
+		if err := invoker(ctx, method, req, reply, cc, opts...); err != nil {
+			return err
+		}
+		i.after(method, reply)
+		return nil
+	}
+}
+
+func (i *Injector) matches(method string) bool {
+	if len(i.cfg.Methods) == 0 {
+		return true
+	}
+	for _, m := range i.cfg.Methods {
+		if strings.Contains(method, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// before delays the call or fails it before it is handled.
+func (i *Injector) before(ctx context.Context, method string) error {
+	if i.cfg.SlowRate > 0 && i.roll() < i.cfg.SlowRate {
+		log.Debug().Str("method", method).Dur("delay", i.cfg.SlowDelay).Msg("Chaos: delaying YC API call")
+		select {
+		case <-time.After(i.cfg.SlowDelay):
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		}
+	}
+	if i.cfg.ErrorRate > 0 && i.roll() < i.cfg.ErrorRate {
+		log.Debug().Str("method", method).Msg("Chaos: failing YC API call")
+		return status.Errorf(codes.Unavailable, "chaos: injected failure of %s", method)
+	}
+	return nil
+}
+
+// after replaces the status of a returned resource with an unspecified one.
+func (i *Injector) after(method string, resp any) {
+	if i.cfg.InvalidStateRate <= 0 || i.roll() >= i.cfg.InvalidStateRate {
+		return
+	}
+	switch r := resp.(type) {
+	case *computepb.Instance:
+		r.Status = computepb.Instance_STATUS_UNSPECIFIED
+	case *k8spb.Cluster:
+		r.Status = k8spb.Cluster_STATUS_UNSPECIFIED
+	case *k8spb.NodeGroup:
+		r.Status = k8spb.NodeGroup_STATUS_UNSPECIFIED
+	default:
+		return
+	}
+	log.Debug().Str("method", method).Msg("Chaos: returning resource in an invalid state")
+}
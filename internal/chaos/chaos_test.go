@@ -0,0 +1,116 @@
+package chaos
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	computepb "github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sentoz/yc-sheduler/internal/yc"
+	"github.com/sentoz/yc-sheduler/internal/ycstub"
+)
+
+func newClient(t *testing.T, stub *ycstub.Server, interceptors ...grpc.UnaryClientInterceptor) *yc.Client {
+	t.Helper()
+
+	client, err := yc.NewClient(context.Background(), yc.AuthConfig{}, yc.ClientOptions{
+		Endpoint:     stub.Addr(),
+		Plaintext:    true,
+		Interceptors: interceptors,
+	})
+	if err != nil {
+		t.Fatalf("yc.NewClient() error = %v", err)
+	}
+	return client
+}
+
+func TestServerInterceptor_InjectsErrors(t *testing.T) {
+	t.Parallel()
+
+	injector := New(Config{ErrorRate: 1})
+	stub, err := ycstub.Start(injector.UnaryServerInterceptor())
+	if err != nil {
+		t.Fatalf("ycstub.Start() error = %v", err)
+	}
+	t.Cleanup(stub.Close)
+	stub.AddInstance("folder-1", "vm-1", computepb.Instance_STOPPED)
+
+	_, err = newClient(t, stub).GetInstance(context.Background(), "folder-1", "vm-1")
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("GetInstance() error = %v, want Unavailable", err)
+	}
+}
+
+func TestClientInterceptor_InjectsInvalidState(t *testing.T) {
+	t.Parallel()
+
+	stub, err := ycstub.Start()
+	if err != nil {
+		t.Fatalf("ycstub.Start() error = %v", err)
+	}
+	t.Cleanup(stub.Close)
+	stub.AddInstance("folder-1", "vm-1", computepb.Instance_RUNNING)
+
+	injector := New(Config{InvalidStateRate: 1})
+	instance, err := newClient(t, stub, injector.UnaryClientInterceptor()).GetInstance(context.Background(), "folder-1", "vm-1")
+	if err != nil {
+		t.Fatalf("GetInstance() error = %v", err)
+	}
+	if instance.GetStatus() != computepb.Instance_STATUS_UNSPECIFIED {
+		t.Fatalf("instance status = %v, want STATUS_UNSPECIFIED", instance.GetStatus())
+	}
+	if got := stub.InstanceStatus("vm-1"); got != computepb.Instance_RUNNING {
+		t.Fatalf("stub instance status = %v, want RUNNING", got)
+	}
+}
+
+func TestInjector_Rates(t *testing.T) {
+	t.Parallel()
+
+	injector := New(Config{ErrorRate: 0.5, SlowRate: 0.5, SlowDelay: 10 * time.Millisecond})
+
+	injector.roll = func() float64 { return 0.9 }
+	if err := injector.before(context.Background(), "/svc/Get"); err != nil {
+		t.Fatalf("before() with roll above rates error = %v, want nil", err)
+	}
+
+	injector.roll = func() float64 { return 0.1 }
+	start := time.Now()
+	err := injector.before(context.Background(), "/svc/Get")
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("before() error = %v, want Unavailable", err)
+	}
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Fatalf("before() took %s, want the slow delay", elapsed)
+	}
+}
+
+func TestInjector_Methods(t *testing.T) {
+	t.Parallel()
+
+	injector := New(Config{ErrorRate: 1, Methods: []string{"InstanceService/Start"}})
+	if !injector.matches("/yandex.cloud.compute.v1.InstanceService/Start") {
+		t.Fatal("matches(Start) = false, want true")
+	}
+	if injector.matches("/yandex.cloud.compute.v1.InstanceService/Get") {
+		t.Fatal("matches(Get) = true, want false")
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	if err := (Config{ErrorRate: 1, SlowRate: 0.5}).Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if err := (Config{InvalidStateRate: 1.5}).Validate(); err == nil {
+		t.Fatal("Validate() error = nil for rate above 1")
+	}
+	if err := (Config{SlowDelay: -time.Second}).Validate(); err == nil {
+		t.Fatal("Validate() error = nil for negative delay")
+	}
+}
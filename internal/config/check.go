@@ -0,0 +1,128 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sentoz/yc-sheduler/internal/source"
+)
+
+// FileError is an error found in a config or schedule file.
+type FileError struct {
+	File string
+	Err  error
+}
+
+// Error implements error.
+func (e FileError) Error() string {
+	return e.File + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e FileError) Unwrap() error {
+	return e.Err
+}
+
+// CheckResult is the outcome of checking a config file and its schedules.
+type CheckResult struct {
+	// Config holds the settings and schedules of the config file, or nil
+	// when the config file itself is invalid.
+	Config *Config
+	// Sources maps schedule names to the files defining them.
+	Sources map[string]string
+	// Files is the number of checked schedule files.
+	Files int
+	// Errors lists the errors of all files.
+	Errors []FileError
+}
+
+// Check validates the config file at path and the schedule manifests of its
+// schedules directories like Load, but reports the errors of every file
+// instead of stopping at the first one. Schedules of schedules_source,
+// schedules_url, schedules_git and schedules_kubernetes are not synced and not
+// checked. The schedules of all shards are checked.
+func Check(ctx context.Context, path string) CheckResult {
+	name := source.Redact(path)
+	settings, raw, err := loadSettings(ctx, path)
+	if err != nil {
+		return CheckResult{Errors: []FileError{{File: name, Err: err}}}
+	}
+
+	cfg := *settings
+	cfg.settings = settings
+	cfg.SchedulesDir = resolveSchedulesDirs(cfg.SchedulesDir, path)
+	result := CheckResult{Config: &cfg, Sources: make(map[string]string)}
+
+	add := func(file string, schedules []Schedule) {
+		for _, sch := range schedules {
+			if prev, exists := result.Sources[sch.Name]; exists {
+				result.Errors = append(result.Errors, FileError{
+					File: file,
+					Err:  fmt.Errorf("%w: duplicate schedule name %q, also defined in %s", ErrInvalidConfig, sch.Name, prev),
+				})
+				continue
+			}
+			result.Sources[sch.Name] = file
+			cfg.Schedules = append(cfg.Schedules, sch)
+		}
+	}
+
+	inline, err := parseInlineSchedules(raw, name)
+	if err != nil {
+		result.Errors = append(result.Errors, FileError{File: name, Err: err})
+	}
+	add(name, inline)
+
+	for _, dir := range cfg.SchedulesDir {
+		info, err := os.Stat(dir)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			result.Errors = append(result.Errors, FileError{File: dir, Err: fmt.Errorf("%w: schedules directory not found", ErrConfigNotFound)})
+			continue
+		case err != nil:
+			result.Errors = append(result.Errors, FileError{File: dir, Err: err})
+			continue
+		case !info.IsDir():
+			result.Errors = append(result.Errors, FileError{File: dir, Err: fmt.Errorf("%w: not a directory", ErrInvalidConfig)})
+			continue
+		}
+
+		files, err := ScheduleFiles(dir, cfg.SchedulesRecursive)
+		if err != nil {
+			result.Errors = append(result.Errors, FileError{File: dir, Err: fmt.Errorf("read schedules dir: %w", err)})
+			continue
+		}
+		for _, file := range files {
+			filePath := filepath.Join(dir, file)
+			result.Files++
+			raw, err := os.ReadFile(filePath)
+			if err != nil {
+				result.Errors = append(result.Errors, FileError{File: filePath, Err: err})
+				continue
+			}
+			schedules, err := ParseScheduleFile(raw, filePath)
+			if err != nil {
+				result.Errors = append(result.Errors, FileError{File: filePath, Err: err})
+				continue
+			}
+			add(filePath, schedules)
+		}
+	}
+
+	if len(cfg.SchedulesDir) == 0 && len(inline) == 0 && !cfg.HasRemoteSchedules() {
+		result.Errors = append(result.Errors, FileError{
+			File: name,
+			Err:  fmt.Errorf("%w: none of schedules_dir, schedules_source, schedules_git and schedules is set", ErrInvalidConfig),
+		})
+	}
+	return result
+}
+
+// HasRemoteSchedules reports whether schedules are also synced from a
+// schedules source, which Check does not read.
+func (c *Config) HasRemoteSchedules() bool {
+	return c.SchedulesSource != "" || c.SchedulesURL != "" || c.SchedulesGit != nil || c.SchedulesKubernetes != nil
+}
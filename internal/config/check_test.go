@@ -0,0 +1,85 @@
+package config
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckReportsErrorsOfEveryFile(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	schedulesDir := filepath.Join(tmpDir, "schedules")
+
+	mustWriteFile(t, configPath, []byte(strings.TrimSpace(`
+timezone: Europe/Moscow
+validation_interval: 10m
+shutdown_timeout: 5m
+schedules_dir: ./schedules
+`)))
+	mustMkdirAll(t, schedulesDir)
+
+	manifest := func(name, crontab string) []byte {
+		return []byte(strings.TrimSpace(`
+apiVersion: scheduler.yc/v1alpha1
+kind: Schedule
+metadata:
+  name: ` + name + `
+spec:
+  type: cron
+  resource:
+    type: vm
+    id: fhm1234567890abcdef
+    folder_id: b1g1234567890abcdef
+  actions:
+    stop:
+      enabled: true
+      crontab: "` + crontab + `"
+`))
+	}
+	mustWriteFile(t, filepath.Join(schedulesDir, "a.yaml"), manifest("vm", "0 18 * * *"))
+	mustWriteFile(t, filepath.Join(schedulesDir, "b.yaml"), manifest("vm", "0 19 * * *"))
+	mustWriteFile(t, filepath.Join(schedulesDir, "c.yaml"), manifest("db", "0 99 * * *"))
+	mustWriteFile(t, filepath.Join(schedulesDir, "d.yaml"), []byte("kind: ["))
+	mustWriteFile(t, filepath.Join(schedulesDir, "e.yaml"), manifest("web", "0 20 * * *"))
+
+	result := Check(context.Background(), configPath)
+	if result.Config == nil {
+		t.Fatal("Check().Config = nil, want settings of the valid config file")
+	}
+	if result.Files != 5 {
+		t.Fatalf("Check().Files = %d, want 5", result.Files)
+	}
+
+	var files []string
+	for _, err := range result.Errors {
+		files = append(files, filepath.Base(err.File))
+	}
+	if want := []string{"b.yaml", "c.yaml", "d.yaml"}; strings.Join(files, ",") != strings.Join(want, ",") {
+		t.Fatalf("Check().Errors = %v, want errors of %v", result.Errors, want)
+	}
+	if len(result.Config.Schedules) != 2 {
+		t.Fatalf("len(Check().Config.Schedules) = %d, want 2", len(result.Config.Schedules))
+	}
+	if got := result.Sources["web"]; got != filepath.Join(schedulesDir, "e.yaml") {
+		t.Fatalf("Check().Sources[web] = %q, want e.yaml", got)
+	}
+}
+
+func TestCheckInvalidConfigFile(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	mustWriteFile(t, configPath, []byte("timezone: [\n"))
+
+	result := Check(context.Background(), configPath)
+	if result.Config != nil {
+		t.Fatal("Check().Config != nil, want nil for an invalid config file")
+	}
+	if len(result.Errors) != 1 || result.Errors[0].File != configPath {
+		t.Fatalf("Check().Errors = %v, want one error of the config file", result.Errors)
+	}
+}
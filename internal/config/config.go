@@ -1,41 +1,536 @@
 package config
 
-// Config represents the main application configuration.
+import (
+	"strconv"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/source"
+)
+
+// Config represents the main application configuration. Top-level fields
+// with an env tag are overridden by the named environment variables, and
+// fields tagged reload:"restart" are not changed by reloading the config file.
 //
 //betteralign:ignore
 type Config struct {
 	// ValidationResources toggles periodic resource state validation and corrective jobs.
-	ValidationResources *bool `yaml:"validation_resources,omitempty" json:"validation_resources,omitempty" default:"true" jsonschema:"default=true"`
+	ValidationResources *bool `yaml:"validation_resources,omitempty" json:"validation_resources,omitempty" env:"YC_SHEDULER_VALIDATION_RESOURCES" default:"true" jsonschema:"default=true"`
 
 	// Timezone specifies the timezone for schedules (IANA timezone name).
 	// If empty, system timezone is used.
-	Timezone Timezone `yaml:"timezone,omitempty" json:"timezone,omitempty" jsonschema:"example=Europe/Moscow"`
+	Timezone Timezone `yaml:"timezone,omitempty" json:"timezone,omitempty" env:"YC_SHEDULER_TIMEZONE" jsonschema:"example=Europe/Moscow"`
+
+	// TimezoneLabel names the label holding the IANA timezone of a resource
+	// or its folder. Schedules without their own timezone whose resources
+	// have the label, directly or on their folder, take times in that
+	// timezone instead of Timezone. Empty disables the inference.
+	TimezoneLabel string `yaml:"timezone_label,omitempty" json:"timezone_label,omitempty" env:"YC_SHEDULER_TIMEZONE_LABEL" jsonschema:"example=timezone"`
+
+	// SchedulesDir specifies a directory or a list of directories containing
+	// schedule manifests (one or more YAML documents separated by ---).
+	// Schedules of all directories are loaded together, so schedule names
+	// must be unique across them. It may be omitted when all schedules are
+	// defined inline.
+	SchedulesDir SchedulesDirs `yaml:"schedules_dir,omitempty" json:"schedules_dir,omitempty" env:"YC_SHEDULER_SCHEDULES_DIR" reload:"restart"`
+
+	// SchedulesRecursive loads schedule manifests from subdirectories of
+	// SchedulesDir as well, e.g. to keep schedules of each team in its own
+	// folder. Hidden directories are skipped.
+	SchedulesRecursive bool `yaml:"schedules_recursive,omitempty" json:"schedules_recursive,omitempty" env:"YC_SHEDULER_SCHEDULES_RECURSIVE" reload:"restart"`
+
+	// SchedulesReloadInterval defines how often schedules sources are
+	// refreshed and, where file change notifications are unavailable,
+	// schedules directories and the config file are checked for changes.
+	SchedulesReloadInterval Duration `yaml:"schedules_reload_interval,omitempty" json:"schedules_reload_interval,omitempty" env:"YC_SHEDULER_SCHEDULES_RELOAD_INTERVAL" jsonschema:"default=10s,example=1m" reload:"restart"`
+
+	// MaxScheduleChangePercent holds back a schedules reload that adds,
+	// removes or changes more than this percentage of the loaded schedules
+	// until it is confirmed via the API, e.g. when a truncated schedules
+	// directory would remove almost every schedule. Zero disables the guard.
+	MaxScheduleChangePercent int `yaml:"max_schedule_change_percent,omitempty" json:"max_schedule_change_percent,omitempty" env:"YC_SHEDULER_MAX_SCHEDULE_CHANGE_PERCENT" jsonschema:"minimum=0,maximum=100,example=50"`
+
+	// EmptySchedulesDirPolicy selects whether a reload keeps the last loaded
+	// schedules while a schedules directory is unreadable, or empty after it
+	// had schedule files, e.g. during a volume remount, or applies the
+	// directories as they are.
+	EmptySchedulesDirPolicy EmptyDirPolicy `yaml:"empty_schedules_dir_policy,omitempty" json:"empty_schedules_dir_policy,omitempty" env:"YC_SHEDULER_EMPTY_SCHEDULES_DIR_POLICY" reload:"restart"`
 
-	// SchedulesDir specifies a directory containing schedule manifests
-	// (one or more YAML documents separated by ---).
-	SchedulesDir string `yaml:"schedules_dir" json:"schedules_dir" jsonschema:"minLength=1,example=./schedules"`
+	// SchedulesSource loads schedule manifests from a remote location in
+	// addition to SchedulesDir, e.g. s3://bucket/prefix for an S3-compatible
+	// bucket. The source is polled together with the schedules directories.
+	SchedulesSource string `yaml:"schedules_source,omitempty" json:"schedules_source,omitempty" env:"YC_SHEDULER_SCHEDULES_SOURCE" jsonschema:"pattern=^s3://[^/]+,example=s3://yc-scheduler/schedules/" reload:"restart"`
 
-	// Schedules contains all loaded scheduled tasks.
+	// SchedulesURL loads schedule manifests served at an HTTP(S) URL, e.g. by
+	// an internal config service, in addition to SchedulesDir. The response
+	// may hold several YAML documents. It is requested again with the ETag of
+	// the last response together with the schedules directories.
+	SchedulesURL string `yaml:"schedules_url,omitempty" json:"schedules_url,omitempty" env:"YC_SHEDULER_SCHEDULES_URL" jsonschema:"pattern=^https?://,example=https://config.example.com/yc-scheduler/schedules.yaml" reload:"restart"`
+
+	// SchedulesGit loads schedule manifests from a directory of a Git
+	// repository in addition to SchedulesDir, e.g. to manage schedules with
+	// GitOps. The repository is pulled periodically.
+	SchedulesGit *GitSourceConfig `yaml:"schedules_git,omitempty" json:"schedules_git,omitempty" reload:"restart"`
+
+	// SchedulesKubernetes loads schedule manifests from labeled ConfigMaps or
+	// Secrets, or from Schedule custom resources in operator mode, when
+	// running in a Kubernetes cluster. They are watched through the API
+	// server, so changes apply within seconds without waiting for volume
+	// updates.
+	SchedulesKubernetes *KubernetesSourceConfig `yaml:"schedules_kubernetes,omitempty" json:"schedules_kubernetes,omitempty" reload:"restart"`
+
+	// AllowRemoteCommandHooks allows command pre_hook and post_hook of
+	// schedules loaded from SchedulesSource, SchedulesURL, SchedulesGit and
+	// SchedulesKubernetes. Command hooks run on the scheduler host, so they
+	// are rejected by default unless the schedule comes from a local
+	// schedules directory or the config file.
+	AllowRemoteCommandHooks bool `yaml:"allow_remote_command_hooks,omitempty" json:"allow_remote_command_hooks,omitempty" env:"YC_SHEDULER_ALLOW_REMOTE_COMMAND_HOOKS" reload:"restart"`
+
+	// InlineSchedules defines schedule manifests directly in the config file,
+	// e.g. for small deployments without a schedules directory. They are
+	// merged with the manifests of SchedulesDir; schedule names must be unique
+	// across both.
+	InlineSchedules []ScheduleManifest `yaml:"schedules,omitempty" json:"schedules,omitempty"`
+
+	// Schedules contains all scheduled tasks loaded at startup.
 	// It is populated at runtime from SchedulesDir and is not part of config file schema.
+	// Reloaded schedules are published as schedule sets and do not change it.
 	Schedules []Schedule `yaml:"-" json:"-"`
 
+	// ScheduleSources mirror SchedulesSource, SchedulesURL, SchedulesGit and
+	// SchedulesKubernetes into the last of SchedulesDir. They are set at runtime.
+	ScheduleSources []source.Source `yaml:"-" json:"-"`
+
 	// ValidationInterval defines how often the state validator runs.
-	ValidationInterval Duration `yaml:"validation_interval,omitempty" json:"validation_interval,omitempty" default:"10m" jsonschema:"example=10m"`
+	ValidationInterval Duration `yaml:"validation_interval,omitempty" json:"validation_interval,omitempty" env:"YC_SHEDULER_VALIDATION_INTERVAL" default:"10m" jsonschema:"example=10m"`
 
 	// ShutdownTimeout defines the timeout for graceful shutdown.
-	ShutdownTimeout Duration `yaml:"shutdown_timeout,omitempty" json:"shutdown_timeout,omitempty" default:"5m" jsonschema:"example=5m"`
+	ShutdownTimeout Duration `yaml:"shutdown_timeout,omitempty" json:"shutdown_timeout,omitempty" env:"YC_SHEDULER_SHUTDOWN_TIMEOUT" default:"5m" jsonschema:"example=5m" reload:"restart"`
+
+	// ActionTimeout bounds an action run for all resources of a schedule (or
+	// of a schedule step) when the action does not set its own timeout.
+	ActionTimeout Duration `yaml:"action_timeout,omitempty" json:"action_timeout,omitempty" env:"YC_SHEDULER_ACTION_TIMEOUT" jsonschema:"default=5m,example=15m"`
 
 	// MetricsPort defines the port for the metrics HTTP server.
-	MetricsPort int `yaml:"metrics_port,omitempty" json:"metrics_port,omitempty" default:"9090" jsonschema:"default=9090"`
+	MetricsPort int `yaml:"metrics_port,omitempty" json:"metrics_port,omitempty" env:"YC_SHEDULER_METRICS_PORT" default:"9090" jsonschema:"default=9090" reload:"restart"`
+
+	// ListenAddresses lists the addresses of the HTTP server: host:port, e.g.
+	// "0.0.0.0:9090" and "[::]:9090" for dual-stack, or unix:<path> of a Unix
+	// socket. Defaults to ":<metrics_port>".
+	ListenAddresses []string `yaml:"listen_addresses,omitempty" json:"listen_addresses,omitempty" env:"YC_SHEDULER_LISTEN_ADDRESSES" jsonschema:"uniqueItems=true,example=unix:/run/yc-scheduler/admin.sock" reload:"restart"`
 
 	// MaxConcurrentJobs limits the number of concurrent job executions.
-	MaxConcurrentJobs int `yaml:"max_concurrent_jobs,omitempty" json:"max_concurrent_jobs,omitempty" default:"5" jsonschema:"default=5,minimum=1"`
+	MaxConcurrentJobs int `yaml:"max_concurrent_jobs,omitempty" json:"max_concurrent_jobs,omitempty" env:"YC_SHEDULER_MAX_CONCURRENT_JOBS" default:"5" jsonschema:"default=5,minimum=1"`
+
+	// ValidationMaxBacklog is the number of due jobs waiting for an execution
+	// slot above which the validator stops checking resources and creating
+	// corrective jobs, e.g. while the cloud API is slow. It resumes once the
+	// backlog drains to half of it.
+	ValidationMaxBacklog int `yaml:"validation_max_backlog,omitempty" json:"validation_max_backlog,omitempty" env:"YC_SHEDULER_VALIDATION_MAX_BACKLOG" jsonschema:"default=20,minimum=1"`
+
+	// LastActionsFile persists the last action the scheduler performed on
+	// each resource, so the validator keeps attributing state drift across
+	// restarts. The last actions are kept in memory only when it is empty.
+	LastActionsFile string `yaml:"last_actions_file,omitempty" json:"last_actions_file,omitempty" env:"YC_SHEDULER_LAST_ACTIONS_FILE" jsonschema:"example=/var/lib/yc-scheduler/last-actions.json" reload:"restart"`
+
+	// VacationsFile persists vacations with the resources they stopped, so
+	// the resources are started again when a vacation ends after a restart.
+	// Vacations are kept in memory only when it is empty.
+	VacationsFile string `yaml:"vacations_file,omitempty" json:"vacations_file,omitempty" env:"YC_SHEDULER_VACATIONS_FILE" jsonschema:"example=/var/lib/yc-scheduler/vacations.json" reload:"restart"`
+
+	// ShardIndex and ShardCount split schedules between several instances,
+	// e.g. pods of a StatefulSet: each instance runs only the schedules its
+	// shard owns by a hash of the schedule name. Schedules linked by
+	// depends_on belong to the same shard. ShardCount below 2 disables
+	// sharding.
+	ShardIndex int `yaml:"shard_index,omitempty" json:"shard_index,omitempty" env:"YC_SHEDULER_SHARD_INDEX" jsonschema:"minimum=0" reload:"restart"`
+	ShardCount int `yaml:"shard_count,omitempty" json:"shard_count,omitempty" env:"YC_SHEDULER_SHARD_COUNT" jsonschema:"minimum=0" reload:"restart"`
 
 	// MetricsEnabled toggles Prometheus metrics HTTP server.
-	MetricsEnabled bool `yaml:"metrics_enabled,omitempty" json:"metrics_enabled,omitempty" default:"false" jsonschema:"default=false"`
+	MetricsEnabled bool `yaml:"metrics_enabled,omitempty" json:"metrics_enabled,omitempty" env:"YC_SHEDULER_METRICS_ENABLED" default:"false" jsonschema:"default=false" reload:"restart"`
+
+	// APICompression enables gzip compression of Yandex Cloud List API calls,
+	// reducing traffic when listing folders with many resources.
+	APICompression bool `yaml:"api_compression,omitempty" json:"api_compression,omitempty" env:"YC_SHEDULER_API_COMPRESSION" default:"false" jsonschema:"default=false" reload:"restart"`
 
 	// UIEnabled toggles the calendar UI and its API endpoints.
-	UIEnabled bool `yaml:"ui_enabled,omitempty" json:"ui_enabled,omitempty" default:"false" jsonschema:"default=false"`
+	UIEnabled bool `yaml:"ui_enabled,omitempty" json:"ui_enabled,omitempty" env:"YC_SHEDULER_UI_ENABLED" default:"false" jsonschema:"default=false" reload:"restart"`
+
+	// Notifications configures scheduler lifecycle notifications.
+	Notifications *NotificationsConfig `yaml:"notifications,omitempty" json:"notifications,omitempty"`
+
+	// Pauses suspends schedules whose labels match a selector, e.g. during a release freeze.
+	Pauses []PauseConfig `yaml:"pauses,omitempty" json:"pauses,omitempty"`
+
+	// Vacations put whole schedule namespaces on vacation between two dates,
+	// e.g. company-wide holidays.
+	Vacations []VacationConfig `yaml:"vacations,omitempty" json:"vacations,omitempty" reload:"restart"`
+
+	// BlackoutWindows lists maintenance windows during which no operations
+	// run and the validator does not correct resource states, e.g. release
+	// nights when everything must stay up.
+	BlackoutWindows []BlackoutWindowConfig `yaml:"blackout_windows,omitempty" json:"blackout_windows,omitempty"`
+
+	// DeniedResources lists resources that are never operated on, even when
+	// a schedule references them, e.g. production resources guarded against
+	// copy-pasted manifests. More can be added through the HTTP API.
+	DeniedResources []DeniedResourceConfig `yaml:"denied_resources,omitempty" json:"denied_resources,omitempty"`
+
+	// ExpectedState lets the validator honor desired state hints set on
+	// resource labels by other tools.
+	ExpectedState *ExpectedStateConfig `yaml:"expected_state,omitempty" json:"expected_state,omitempty"`
+
+	// IdlePolicy stops running VMs that stay idle according to Monitoring
+	// metrics, independent of schedules.
+	IdlePolicy *IdlePolicyConfig `yaml:"idle_policy,omitempty" json:"idle_policy,omitempty" reload:"restart"`
+
+	// WarmUp spreads starts of many resources due at the same time across a
+	// window, e.g. on Monday morning after a weekend-long stop.
+	WarmUp *WarmUpConfig `yaml:"warm_up,omitempty" json:"warm_up,omitempty"`
+
+	// UtilizationSnapshot records Monitoring metrics of VMs over the window
+	// before they were stopped with the run, so capacity owners can judge
+	// whether they were actually idle.
+	UtilizationSnapshot *UtilizationSnapshotConfig `yaml:"utilization_snapshot,omitempty" json:"utilization_snapshot,omitempty"`
+
+	// settings holds the config file settings the config was loaded with,
+	// before schedules directories were resolved and sources added.
+	settings *Config
+}
+
+// EffectiveListenAddresses returns ListenAddresses or the address of
+// MetricsPort on all interfaces.
+func (c *Config) EffectiveListenAddresses() []string {
+	if len(c.ListenAddresses) > 0 {
+		return c.ListenAddresses
+	}
+	return []string{":" + strconv.Itoa(c.MetricsPort)}
+}
+
+// defaultSchedulesReloadInterval is the schedules reload interval when none
+// is configured.
+const defaultSchedulesReloadInterval = 10 * time.Second
+
+// EffectiveSchedulesReloadInterval returns SchedulesReloadInterval or its
+// default.
+func (c *Config) EffectiveSchedulesReloadInterval() time.Duration {
+	if c.SchedulesReloadInterval.Duration <= 0 {
+		return defaultSchedulesReloadInterval
+	}
+	return c.SchedulesReloadInterval.Duration
+}
+
+// EffectiveEmptySchedulesDirPolicy returns the empty schedules directory
+// policy, EmptyDirPolicyKeep when none is configured.
+func (c *Config) EffectiveEmptySchedulesDirPolicy() EmptyDirPolicy {
+	if c.EmptySchedulesDirPolicy == "" {
+		return EmptyDirPolicyKeep
+	}
+	return c.EmptySchedulesDirPolicy
+}
+
+// defaultValidationMaxBacklog is the job backlog pausing the validator when
+// ValidationMaxBacklog is not set.
+const defaultValidationMaxBacklog = 20
+
+// EffectiveValidationMaxBacklog returns ValidationMaxBacklog or its default.
+func (c *Config) EffectiveValidationMaxBacklog() int {
+	if c.ValidationMaxBacklog <= 0 {
+		return defaultValidationMaxBacklog
+	}
+	return c.ValidationMaxBacklog
+}
+
+// defaultActionTimeout bounds an action run when no timeout is configured.
+const defaultActionTimeout = 5 * time.Minute
+
+// EffectiveActionTimeout returns ActionTimeout or its default.
+func (c *Config) EffectiveActionTimeout() time.Duration {
+	if c.ActionTimeout.Duration <= 0 {
+		return defaultActionTimeout
+	}
+	return c.ActionTimeout.Duration
+}
+
+// defaultWarmUpMinResources is the number of resources starting at the same
+// time from which starts are staggered when MinResources is not set.
+const defaultWarmUpMinResources = 10
+
+// WarmUpConfig defines how starts due at the same time are staggered.
+type WarmUpConfig struct {
+	// Window is the period starts are spread across. Dependencies of a
+	// schedule start before it.
+	Window Duration `yaml:"window" json:"window" jsonschema:"example=30m"`
+
+	// MinResources is the number of resources starting at the same time from
+	// which starts are staggered.
+	MinResources int `yaml:"min_resources,omitempty" json:"min_resources,omitempty" jsonschema:"minimum=1,default=10"`
+}
+
+// EffectiveMinResources returns MinResources or its default.
+func (c *WarmUpConfig) EffectiveMinResources() int {
+	if c.MinResources <= 0 {
+		return defaultWarmUpMinResources
+	}
+	return c.MinResources
+}
+
+// defaultUtilizationWindow is the period before a stop summarized by
+// utilization snapshots when Window is not set.
+const defaultUtilizationWindow = time.Hour
+
+// UtilizationSnapshotConfig defines which metrics of a stopped VM are recorded.
+type UtilizationSnapshotConfig struct {
+	// Window is the period before the stop the metrics are summarized over.
+	Window Duration `yaml:"window,omitempty" json:"window,omitempty" jsonschema:"default=1h,example=1h"`
+
+	// Metrics lists Compute Cloud metrics of the VM to record. Defaults to
+	// cpu_utilization.
+	Metrics []string `yaml:"metrics,omitempty" json:"metrics,omitempty" jsonschema:"uniqueItems=true,example=cpu_utilization"`
+}
+
+// EffectiveWindow returns Window or its default.
+func (c *UtilizationSnapshotConfig) EffectiveWindow() time.Duration {
+	if c.Window.Duration <= 0 {
+		return defaultUtilizationWindow
+	}
+	return c.Window.Duration
+}
+
+// EffectiveMetrics returns Metrics or the CPU utilization.
+func (c *UtilizationSnapshotConfig) EffectiveMetrics() []string {
+	if len(c.Metrics) == 0 {
+		return []string{"cpu_utilization"}
+	}
+	return c.Metrics
+}
+
+// GitSourceConfig describes a Git repository with schedule manifests. The
+// git command must be installed.
+type GitSourceConfig struct {
+	// URL of the repository, e.g. https://git.example.com/ops/schedules.git
+	// or git@git.example.com:ops/schedules.git.
+	URL string `yaml:"url" json:"url" jsonschema:"minLength=1,example=git@github.com:example/schedules.git"`
+
+	// Branch to pull. Defaults to the default branch of the repository.
+	Branch string `yaml:"branch,omitempty" json:"branch,omitempty" jsonschema:"example=main"`
+
+	// Path of the manifests directory relative to the repository root.
+	// Defaults to the repository root.
+	Path string `yaml:"path,omitempty" json:"path,omitempty" jsonschema:"example=schedules"`
+
+	// PollInterval is the minimal interval between pulls.
+	PollInterval Duration `yaml:"poll_interval,omitempty" json:"poll_interval,omitempty" jsonschema:"default=1m,example=5m"`
+
+	// DeployKeyFile is the path to a private SSH key for SSH repository URLs.
+	DeployKeyFile string `yaml:"deploy_key_file,omitempty" json:"deploy_key_file,omitempty" jsonschema:"example=/git/deploy-key"`
+
+	// KnownHostsFile verifies the SSH host key of the repository server.
+	// Without it the host key is trusted on first use.
+	KnownHostsFile string `yaml:"known_hosts_file,omitempty" json:"known_hosts_file,omitempty" jsonschema:"example=/git/known_hosts"`
+}
+
+// KubernetesSourceConfig selects ConfigMaps or Secrets of a namespace with
+// schedule manifests in their *.yaml and *.yml keys, or Schedule custom
+// resources in operator mode. The service account of the pod must be allowed
+// to list and watch them.
+type KubernetesSourceConfig struct {
+	// Kind of the objects, ConfigMap, Secret or Schedule. Schedule objects
+	// are schedule manifests themselves, and the runs of their schedules are
+	// written to their status.
+	Kind string `yaml:"kind,omitempty" json:"kind,omitempty" jsonschema:"enum=ConfigMap,enum=Secret,enum=Schedule,default=ConfigMap"`
+
+	// Namespace of the objects. Defaults to the namespace of the pod.
+	Namespace string `yaml:"namespace,omitempty" json:"namespace,omitempty" jsonschema:"example=yc-scheduler"`
+
+	// LabelSelector selects the objects with schedule manifests. Required
+	// for ConfigMaps and Secrets; all Schedule objects are selected without it.
+	LabelSelector string `yaml:"label_selector,omitempty" json:"label_selector,omitempty" jsonschema:"minLength=1,example=yc-scheduler/schedules=true"`
+}
+
+// Idle policy defaults used when the corresponding fields are not set.
+const (
+	defaultIdleFor          = 4 * time.Hour
+	defaultIdleInterval     = 15 * time.Minute
+	defaultIdleCPUThreshold = 5.0
+	defaultIdleNetThreshold = 10240
+)
+
+// IdlePolicyConfig defines when running VMs are considered idle and stopped.
+type IdlePolicyConfig struct {
+	// FolderIDs lists folders whose running VMs are checked.
+	FolderIDs []string `yaml:"folder_ids" json:"folder_ids" jsonschema:"minItems=1,uniqueItems=true,example=b1g1234567890abcdef"`
+
+	// AllowLabels limits the policy to VMs having any of the listed labels.
+	// If empty, all VMs in the folders are checked.
+	AllowLabels map[string]string `yaml:"allow_labels,omitempty" json:"allow_labels,omitempty"`
+
+	// DenyLabels excludes VMs having any of the listed labels.
+	DenyLabels map[string]string `yaml:"deny_labels,omitempty" json:"deny_labels,omitempty"`
+
+	// IdleFor is how long CPU and network usage must stay below thresholds.
+	IdleFor Duration `yaml:"idle_for,omitempty" json:"idle_for,omitempty" jsonschema:"default=4h,example=4h"`
+
+	// Interval defines how often VMs are checked.
+	Interval Duration `yaml:"interval,omitempty" json:"interval,omitempty" jsonschema:"default=15m,example=15m"`
+
+	// CPUThreshold is the CPU utilization in percent below which a VM is idle.
+	CPUThreshold float64 `yaml:"cpu_threshold,omitempty" json:"cpu_threshold,omitempty" jsonschema:"minimum=0,maximum=100,default=5"`
+
+	// NetworkThreshold is the network traffic in bytes per second (received
+	// and sent each) below which a VM is idle.
+	NetworkThreshold int64 `yaml:"network_threshold,omitempty" json:"network_threshold,omitempty" jsonschema:"minimum=0,default=10240"`
+
+	// Observe only logs and counts idle VMs without stopping them.
+	Observe bool `yaml:"observe,omitempty" json:"observe,omitempty" jsonschema:"default=false"`
+}
+
+// EffectiveIdleFor returns IdleFor or its default.
+func (c *IdlePolicyConfig) EffectiveIdleFor() time.Duration {
+	if c.IdleFor.Duration <= 0 {
+		return defaultIdleFor
+	}
+	return c.IdleFor.Duration
+}
+
+// EffectiveInterval returns Interval or its default.
+func (c *IdlePolicyConfig) EffectiveInterval() time.Duration {
+	if c.Interval.Duration <= 0 {
+		return defaultIdleInterval
+	}
+	return c.Interval.Duration
+}
+
+// EffectiveCPUThreshold returns CPUThreshold or its default.
+func (c *IdlePolicyConfig) EffectiveCPUThreshold() float64 {
+	if c.CPUThreshold <= 0 {
+		return defaultIdleCPUThreshold
+	}
+	return c.CPUThreshold
+}
+
+// EffectiveNetworkThreshold returns NetworkThreshold or its default.
+func (c *IdlePolicyConfig) EffectiveNetworkThreshold() int64 {
+	if c.NetworkThreshold <= 0 {
+		return defaultIdleNetThreshold
+	}
+	return c.NetworkThreshold
+}
+
+// defaultExpectedStateLabel is the resource label read for desired state hints.
+const defaultExpectedStateLabel = "desired-state"
+
+// ExpectedStateConfig defines which expected state wins in the validator when
+// both a schedule and a resource label hint provide one.
+type ExpectedStateConfig struct {
+	// Label is the resource label holding the desired state hint (running or stopped).
+	Label string `yaml:"label,omitempty" json:"label,omitempty" jsonschema:"default=desired-state,example=desired-state"`
+
+	// Priority is the source that wins by default.
+	Priority StateSource `yaml:"priority,omitempty" json:"priority,omitempty" jsonschema:"default=schedule"`
+
+	// Namespaces overrides Priority for schedules in the given namespaces.
+	Namespaces map[string]StateSource `yaml:"namespaces,omitempty" json:"namespaces,omitempty"`
+}
+
+// LabelKey returns the effective desired state label.
+func (c *ExpectedStateConfig) LabelKey() string {
+	if c == nil || c.Label == "" {
+		return defaultExpectedStateLabel
+	}
+	return c.Label
+}
+
+// PriorityFor returns the expected state source that wins for schedules in namespace.
+func (c *ExpectedStateConfig) PriorityFor(namespace string) StateSource {
+	if c == nil {
+		return StateSourceSchedule
+	}
+	if source, ok := c.Namespaces[namespace]; ok {
+		return source
+	}
+	if c.Priority == "" {
+		return StateSourceSchedule
+	}
+	return c.Priority
+}
+
+// PauseConfig pauses all schedules matching a label selector.
+type PauseConfig struct {
+	// Selector lists labels a schedule must have (all of them) to be paused.
+	Selector map[string]string `yaml:"selector" json:"selector" jsonschema:"minProperties=1"`
+
+	// Until is the time when matching schedules resume automatically.
+	// If empty, schedules stay paused while the pause is configured.
+	Until RFC3339Time `yaml:"until,omitempty" json:"until,omitempty"`
+
+	// Reason is a free-form note shown in logs and the API.
+	Reason string `yaml:"reason,omitempty" json:"reason,omitempty" jsonschema:"example=release freeze"`
+}
+
+// DeniedResourceConfig denies all operations on a resource.
+type DeniedResourceConfig struct {
+	// ID is the resource identifier in Yandex Cloud.
+	ID string `yaml:"id" json:"id" jsonschema:"minLength=1,example=fhm1234567890abcdef"`
+
+	// Reason is a free-form note shown in logs and the API.
+	Reason string `yaml:"reason,omitempty" json:"reason,omitempty" jsonschema:"example=production database"`
+}
+
+// VacationConfig puts a schedule namespace on vacation: running resources of
+// its schedules are stopped at From, the schedules are suspended and the
+// stopped resources are started again at Until.
+type VacationConfig struct {
+	// Namespace is the schedule namespace sent on vacation.
+	Namespace string `yaml:"namespace" json:"namespace" jsonschema:"minLength=1,example=team-a"`
+
+	// From is the start of the vacation.
+	From RFC3339Time `yaml:"from" json:"from"`
+
+	// Until is the end of the vacation.
+	Until RFC3339Time `yaml:"until" json:"until"`
+
+	// Reason is a free-form note shown in logs and the API.
+	Reason string `yaml:"reason,omitempty" json:"reason,omitempty" jsonschema:"example=new year holidays"`
+}
+
+// BlackoutWindowConfig defines a maintenance window. A window combines an
+// optional absolute range, optional dates or weekdays and an optional time
+// of day; a moment is in the window when it satisfies all of them. Dates and
+// times of day are in the configured timezone.
+type BlackoutWindowConfig struct {
+	// Name identifies the window in logs.
+	Name string `yaml:"name,omitempty" json:"name,omitempty" jsonschema:"example=release-night"`
+
+	// From is the start of the window. If empty, the window has no start.
+	From RFC3339Time `yaml:"from,omitempty" json:"from,omitempty"`
+
+	// Until is the end of the window. If empty, the window has no end.
+	Until RFC3339Time `yaml:"until,omitempty" json:"until,omitempty"`
+
+	// Dates lists whole days (YYYY-MM-DD) of the window.
+	Dates []string `yaml:"dates,omitempty" json:"dates,omitempty" jsonschema:"uniqueItems=true,pattern=^\\d{4}-\\d{2}-\\d{2}$,example=2026-12-31"`
+
+	// Weekdays lists days of the week (0=Sunday, 1=Monday, ..., 6=Saturday)
+	// of the window. Combined with Dates, a day matching either is selected.
+	Weekdays []int `yaml:"weekdays,omitempty" json:"weekdays,omitempty" jsonschema:"uniqueItems=true,minimum=0,maximum=6"`
+
+	// StartTime limits the window to a time range of the selected days,
+	// starting at this time of day.
+	StartTime Time `yaml:"start_time,omitempty" json:"start_time,omitempty" jsonschema:"example=22:00"`
+
+	// EndTime ends the time range of StartTime. A range ending before it
+	// starts spans midnight.
+	EndTime Time `yaml:"end_time,omitempty" json:"end_time,omitempty" jsonschema:"example=02:00"`
+
+	// Reason is a free-form note shown in logs.
+	Reason string `yaml:"reason,omitempty" json:"reason,omitempty" jsonschema:"example=release night"`
+}
+
+// NotificationsConfig defines where scheduler lifecycle notifications are sent.
+type NotificationsConfig struct {
+	// WebhookURL receives a JSON POST request when the scheduler starts, stops
+	// or fails to start.
+	WebhookURL string `yaml:"webhook_url,omitempty" json:"webhook_url,omitempty" jsonschema:"format=uri,example=https://hooks.example.com/yc-scheduler"`
 }
 
 // IsValidationResourcesEnabled returns the effective resource validation flag.
@@ -51,6 +546,21 @@ type Schedule struct {
 	// DisplayName is a human-friendly label for UI display.
 	DisplayName string `yaml:"-" json:"display_name,omitempty"`
 
+	// Namespace is the schedule namespace from manifest metadata.
+	Namespace string `yaml:"-" json:"namespace,omitempty"`
+
+	// Labels are schedule labels from manifest metadata, used by pause selectors.
+	Labels map[string]string `yaml:"-" json:"labels,omitempty"`
+
+	// Annotations are schedule annotations from manifest metadata, passed to hooks.
+	Annotations map[string]string `yaml:"-" json:"annotations,omitempty"`
+
+	// Description explains what the schedule is for.
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+
+	// RunbookURL links the instructions for responding to failed runs.
+	RunbookURL string `yaml:"runbook_url,omitempty" json:"runbook_url,omitempty"`
+
 	// Actions defines what actions to perform at scheduled times.
 	Actions Actions `yaml:"actions" json:"actions"`
 
@@ -67,13 +577,55 @@ type Schedule struct {
 	MonthlyJob *MonthlyJobConfig `yaml:"monthly_job,omitempty" json:"monthly_job,omitempty"`
 
 	// Resource defines the target resource to manage.
-	Resource Resource `yaml:"resource" json:"resource"`
+	Resource Resource `yaml:"resource,omitempty" json:"resource,omitempty"`
+
+	// Resources defines several target resources managed by the schedule.
+	// When set, Resource is ignored.
+	Resources []Resource `yaml:"resources,omitempty" json:"resources,omitempty"`
+
+	// Steps defines ordered groups of target resources processed one after
+	// another. When set, Resource and Resources are ignored.
+	Steps []ScheduleStep `yaml:"steps,omitempty" json:"steps,omitempty"`
+
+	// DelayBetween is the pause between consecutive steps.
+	DelayBetween Duration `yaml:"delay_between,omitempty" json:"delay_between,omitempty"`
+
+	// DependsOn lists schedules whose actions must succeed before the same
+	// action of this schedule runs, e.g. a database VM started before the
+	// application cluster.
+	DependsOn []string `yaml:"depends_on,omitempty" json:"depends_on,omitempty"`
+
+	// Jitter delays every run of the schedule actions by a random amount
+	// within the window, spreading API calls of schedules due at once.
+	Jitter Duration `yaml:"jitter,omitempty" json:"jitter,omitempty"`
+
+	// Timezone is the IANA timezone the schedule times are taken in, set in
+	// the manifest or inferred from the labels of its resources. Empty means
+	// the global timezone.
+	Timezone Timezone `yaml:"timezone,omitempty" json:"timezone,omitempty"`
+
+	// DeniedResourceIDs lists resources the schedule never operates on, even
+	// when its resources reference them.
+	DeniedResourceIDs []string `yaml:"denied_resource_ids,omitempty" json:"denied_resource_ids,omitempty"`
+
+	// MaxParallel limits how many resources of the schedule are processed concurrently.
+	MaxParallel int `yaml:"max_parallel,omitempty" json:"max_parallel,omitempty"`
+
+	// ActiveFrom and ActiveUntil bound the period the schedule takes effect in.
+	ActiveFrom  RFC3339Time `yaml:"active_from,omitempty" json:"active_from,omitempty"`
+	ActiveUntil RFC3339Time `yaml:"active_until,omitempty" json:"active_until,omitempty"`
+
+	// BusinessHours is the window a business_hours schedule keeps its
+	// resources running in. Such schedules are expanded into weekly start and
+	// stop actions.
+	BusinessHours *BusinessHours `yaml:"-" json:"-"`
 
 	// Name is a unique identifier for the schedule.
 	Name string `yaml:"name" json:"name" default:"" jsonschema:"minLength=1,example=vm-production-start"`
 
-	// Type specifies the schedule type (cron, daily, weekly, monthly).
-	Type string `yaml:"type" json:"type" default:"" jsonschema:"enum=cron,enum=daily,enum=weekly,enum=monthly,example=daily"`
+	// Type specifies the schedule type (cron, daily, weekly, monthly,
+	// duration, one-time, random_window).
+	Type string `yaml:"type" json:"type" default:"" jsonschema:"enum=cron,enum=daily,enum=weekly,enum=monthly,enum=duration,enum=one-time,enum=random_window,example=daily"`
 }
 
 // ScheduleManifest is a Kubernetes-like schedule document.
@@ -87,13 +639,24 @@ type ScheduleManifest struct {
 // ScheduleManifestMeta holds schedule object metadata.
 type ScheduleManifestMeta struct {
 	Annotations map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
 	Name        string            `yaml:"name" json:"name" jsonschema:"minLength=1,example=vm-production-start"`
+	Namespace   string            `yaml:"namespace,omitempty" json:"namespace,omitempty" jsonschema:"minLength=1,example=team-a"`
 }
 
 // ScheduleManifestSpec defines schedule settings for a manifest.
 type ScheduleManifestSpec struct {
-	// Actions defines what actions to perform at scheduled times.
-	Actions Actions `yaml:"actions" json:"actions"`
+	// Description explains what the schedule is for, shown in the API, the
+	// calendar UI and failure notifications.
+	Description string `yaml:"description,omitempty" json:"description,omitempty" jsonschema:"example=Keeps the staging VM off outside working hours"`
+
+	// RunbookURL links the instructions for responding to failed runs of the
+	// schedule.
+	RunbookURL string `yaml:"runbook_url,omitempty" json:"runbook_url,omitempty" jsonschema:"format=uri,example=https://wiki.example.com/runbooks/staging-vm"`
+
+	// Actions defines what actions to perform at scheduled times. Required
+	// unless Type is "business_hours".
+	Actions Actions `yaml:"actions,omitempty" json:"actions,omitempty"`
 
 	// CronJob configuration (used when Type is "cron").
 	CronJob *CronJobConfig `yaml:"cron_job,omitempty" json:"cron_job,omitempty"`
@@ -108,19 +671,100 @@ type ScheduleManifestSpec struct {
 	MonthlyJob *MonthlyJobConfig `yaml:"monthly_job,omitempty" json:"monthly_job,omitempty"`
 
 	// Resource defines the target resource to manage.
-	Resource Resource `yaml:"resource" json:"resource"`
+	// Exactly one of Resource and Resources must be set.
+	Resource *Resource `yaml:"resource,omitempty" json:"resource,omitempty"`
+
+	// Resources defines several target resources managed by one schedule.
+	// Actions are applied to all of them with bounded concurrency.
+	Resources []Resource `yaml:"resources,omitempty" json:"resources,omitempty" jsonschema:"minItems=1"`
+
+	// Steps defines ordered groups of resources brought up one group after
+	// another; stop processes the groups in reverse order.
+	// Exactly one of Resource, Resources and Steps must be set.
+	Steps []ScheduleStep `yaml:"steps,omitempty" json:"steps,omitempty" jsonschema:"minItems=1"`
+
+	// DelayBetween is the pause between consecutive steps (e.g., "2m").
+	DelayBetween Duration `yaml:"delay_between,omitempty" json:"delay_between,omitempty" jsonschema:"example=2m"`
+
+	// DependsOn lists schedules whose actions must succeed before the same
+	// action of this schedule runs. Dependencies must not form a cycle.
+	DependsOn []string `yaml:"depends_on,omitempty" json:"depends_on,omitempty" jsonschema:"uniqueItems=true,example=db-vm"`
+
+	// Jitter delays every run of the schedule actions by a random amount
+	// within the window (e.g., "5m"), so dozens of resources scheduled at the
+	// same time do not call the API simultaneously.
+	Jitter Duration `yaml:"jitter,omitempty" json:"jitter,omitempty" jsonschema:"example=5m"`
+
+	// Timezone overrides the global timezone and the one inferred from
+	// resource labels for the schedule times. Not supported by duration and
+	// one-time schedules.
+	Timezone Timezone `yaml:"timezone,omitempty" json:"timezone,omitempty" jsonschema:"example=Asia/Yekaterinburg"`
 
-	// Type specifies the schedule type (cron, daily, weekly, monthly).
-	Type string `yaml:"type" json:"type" default:"" jsonschema:"enum=cron,enum=daily,enum=weekly,enum=monthly,example=daily"`
+	// DeniedResourceIDs lists resources the schedule never operates on, even
+	// when Resource, Resources, Steps or a name pattern reference them, e.g.
+	// production IDs of a manifest copied from a production template.
+	DeniedResourceIDs []string `yaml:"denied_resource_ids,omitempty" json:"denied_resource_ids,omitempty" jsonschema:"uniqueItems=true,example=fhm1234567890abcdef"`
+
+	// MaxParallel limits how many resources of the schedule are processed concurrently.
+	MaxParallel int `yaml:"max_parallel,omitempty" json:"max_parallel,omitempty" default:"5" jsonschema:"minimum=1,default=5"`
+
+	// ActiveFrom is the time the schedule takes effect. Runs before it are
+	// skipped.
+	ActiveFrom RFC3339Time `yaml:"active_from,omitempty" json:"active_from,omitempty"`
+
+	// ActiveUntil is the time the schedule stops taking effect, e.g. the end
+	// of a load-testing month. Its jobs are deregistered then.
+	ActiveUntil RFC3339Time `yaml:"active_until,omitempty" json:"active_until,omitempty"`
+
+	// Start and End bound the daily window of a business_hours schedule
+	// (e.g., "09:00" and "19:00"). An End before Start spans midnight.
+	Start Time `yaml:"start,omitempty" json:"start,omitempty"`
+	End   Time `yaml:"end,omitempty" json:"end,omitempty"`
+
+	// Days lists the days of the week (0=Sunday, 1=Monday, ..., 6=Saturday)
+	// of a business_hours schedule, Monday to Friday by default.
+	Days []int `yaml:"days,omitempty" json:"days,omitempty" jsonschema:"minItems=1,uniqueItems=true,minimum=0,maximum=6,example=1"`
+
+	// Type specifies the schedule type (cron, daily, weekly, monthly,
+	// duration, one-time, random_window, business_hours).
+	Type string `yaml:"type" json:"type" default:"" jsonschema:"enum=cron,enum=daily,enum=weekly,enum=monthly,enum=duration,enum=one-time,enum=random_window,enum=business_hours,example=daily"`
+}
+
+// BusinessHours is the daily window of a business_hours schedule.
+type BusinessHours struct {
+	Start Time
+	End   Time
+	Days  []int
+}
+
+// ScheduleStep is a group of resources processed together in a sequence of
+// steps.
+type ScheduleStep struct {
+	// Resources are processed concurrently, at most max_parallel at a time.
+	Resources []Resource `yaml:"resources" json:"resources" jsonschema:"minItems=1"`
 }
 
 // Resource defines a cloud resource to manage.
 type Resource struct {
-	// Type specifies the resource type (vm, k8s_cluster).
-	Type string `yaml:"type" json:"type" default:"" jsonschema:"enum=vm,enum=k8s_cluster,example=vm"`
+	// Type specifies the resource type (vm, k8s_cluster, k8s_node_group, instance_group, mdb_mongodb, mdb_greenplum, alb, vpc_address, nat_gateway, serverless_container).
+	Type string `yaml:"type" json:"type" default:"" jsonschema:"enum=vm,enum=k8s_cluster,enum=k8s_node_group,enum=instance_group,enum=mdb_mongodb,enum=mdb_greenplum,enum=alb,enum=vpc_address,enum=nat_gateway,enum=serverless_container,example=vm"`
 
 	// ID is the resource identifier in Yandex Cloud.
-	ID string `yaml:"id" json:"id" default:"" jsonschema:"minLength=1,example=fhm1234567890abcdef"`
+	ID string `yaml:"id,omitempty" json:"id,omitempty" default:"" jsonschema:"minLength=1,example=fhm1234567890abcdef"`
+
+	// NamePattern selects VMs in the folder by a glob on the instance name
+	// instead of ID. Matching instances are resolved at execution time.
+	NamePattern string `yaml:"name_pattern,omitempty" json:"name_pattern,omitempty" jsonschema:"minLength=1,example=dev-*"`
+
+	// MaxMatches caps how many instances NamePattern may resolve to.
+	// When more instances match, no action is taken.
+	MaxMatches int `yaml:"max_matches,omitempty" json:"max_matches,omitempty" jsonschema:"minimum=1,example=10"`
+
+	// ExcludeIDs lists instance IDs never matched by NamePattern.
+	ExcludeIDs []string `yaml:"exclude_ids,omitempty" json:"exclude_ids,omitempty" jsonschema:"uniqueItems=true"`
+
+	// ExcludeLabels excludes instances having any of the listed labels from NamePattern matches.
+	ExcludeLabels map[string]string `yaml:"exclude_labels,omitempty" json:"exclude_labels,omitempty"`
 
 	// FolderID is the Yandex Cloud folder ID containing the resource.
 	FolderID string `yaml:"folder_id" json:"folder_id" default:"" jsonschema:"minLength=1,example=b1g1234567890abcdef"`
@@ -133,14 +777,41 @@ type Actions struct {
 
 	// Stop defines when to stop the resource.
 	Stop *ActionConfig `yaml:"stop,omitempty" json:"stop,omitempty"`
+
+	// Snapshot defines when to create snapshots of all disks attached to a VM.
+	Snapshot *ActionConfig `yaml:"snapshot,omitempty" json:"snapshot,omitempty"`
+
+	// Restart defines when to restart the resource: stop, wait until it is
+	// stopped and start it again.
+	Restart *ActionConfig `yaml:"restart,omitempty" json:"restart,omitempty"`
+
+	// Scale defines when to scale a node group or instance group to the
+	// target_size of each entry, e.g. to N nodes in the morning and 1 at night.
+	Scale []ActionConfig `yaml:"scale,omitempty" json:"scale,omitempty"`
+
+	// Resize defines when to change the platform, cores or memory of a VM:
+	// the instance is stopped, updated and started again if it was running.
+	Resize *ActionConfig `yaml:"resize,omitempty" json:"resize,omitempty"`
+
+	// Preemptible defines when to switch a VM to or from the preemptible
+	// scheduling policy, e.g. preemptible at night and regular by day.
+	Preemptible []ActionConfig `yaml:"preemptible,omitempty" json:"preemptible,omitempty"`
 }
 
 // ActionConfig defines configuration for a specific action.
 type ActionConfig struct {
 	// Time specifies the time to perform the action.
 	// For daily, weekly, monthly schedules: HH:MM or HH:MM:SS format (e.g., "09:00").
+	// For duration schedules: the optional time of the first run of a day.
+	// For random_window schedules: the start of the daily window.
 	Time string `yaml:"time,omitempty" json:"time,omitempty"`
 
+	// WindowEnd is the end of the daily window of random_window schedules in
+	// HH:MM or HH:MM:SS format; the action runs at a random moment between
+	// Time and WindowEnd. A WindowEnd before Time ends the window on the
+	// next day.
+	WindowEnd string `yaml:"window_end,omitempty" json:"window_end,omitempty" jsonschema:"example=08:30"`
+
 	// Crontab is a cron expression for cron-based schedules (e.g., "0 9 * * *" for daily at 9 AM).
 	Crontab Crontab `yaml:"crontab,omitempty" json:"crontab,omitempty"`
 
@@ -148,8 +819,234 @@ type ActionConfig struct {
 	// or the day of the month (1-31) for monthly schedules.
 	Day int `yaml:"day,omitempty" json:"day,omitempty" jsonschema:"example=1"`
 
+	// At is the moment of the single run of one-time schedules (RFC3339,
+	// e.g., "2025-07-01T00:00:00+03:00").
+	At RFC3339Time `yaml:"at,omitempty" json:"at,omitempty"`
+
+	// Every is the interval between runs of duration schedules (e.g., "30m").
+	// Runs start at Time, midnight by default, and repeat until the end of
+	// the day.
+	Every Duration `yaml:"every,omitempty" json:"every,omitempty" jsonschema:"example=30m"`
+
+	// Days lists days of the week (0=Sunday, 1=Monday, ..., 6=Saturday) for
+	// weekly schedules running on several days. When set, Day is ignored.
+	Days []int `yaml:"days,omitempty" json:"days,omitempty" jsonschema:"minItems=1,uniqueItems=true,minimum=0,maximum=6,example=1"`
+
 	// Enabled indicates whether this action is enabled.
 	Enabled bool `yaml:"enabled" json:"enabled" jsonschema:"example=true"`
+
+	// ReleasePublicIP releases public IP addresses of a VM after it is stopped
+	// and attaches them again on the next start of the schedule.
+	// Reserved static addresses stay reserved and are attached back, while
+	// ephemeral addresses are replaced with new ones.
+	// Only applies to stop actions of vm resources.
+	ReleasePublicIP bool `yaml:"release_public_ip,omitempty" json:"release_public_ip,omitempty" jsonschema:"default=false"`
+
+	// StopMode selects whether a stop action stops or pauses the resource.
+	// A paused resource is expected to stay paused rather than stopped.
+	// Only applies to stop actions; pause is supported by instance_group
+	// resources, whose management processes are paused.
+	StopMode StopMode `yaml:"stop_mode,omitempty" json:"stop_mode,omitempty"`
+
+	// ProvisionedInstances is the number of provisioned instances restored on start
+	// of a serverless container. Stop always sets provisioned instances to 0.
+	// Required for start actions of serverless_container resources.
+	ProvisionedInstances int `yaml:"provisioned_instances,omitempty" json:"provisioned_instances,omitempty" jsonschema:"minimum=0,example=2"`
+
+	// Retention is the number of newest scheduler-created snapshots to keep per disk.
+	// Older snapshots are deleted after a new one is created; 0 keeps all snapshots.
+	// Only applies to snapshot actions.
+	Retention int `yaml:"retention,omitempty" json:"retention,omitempty" jsonschema:"minimum=0,default=0,example=7"`
+
+	// TargetSize is the fixed scale size set by a scale action.
+	// Required for scale actions; use stop to scale a group to zero.
+	TargetSize int `yaml:"target_size,omitempty" json:"target_size,omitempty" jsonschema:"minimum=1,example=3"`
+
+	// PlatformID is the VM platform set by a resize action (e.g., "standard-v3").
+	// Only applies to resize actions; empty keeps the current platform.
+	PlatformID string `yaml:"platform_id,omitempty" json:"platform_id,omitempty" jsonschema:"minLength=1,example=standard-v3"`
+
+	// Cores is the number of VM cores set by a resize action.
+	// Only applies to resize actions; 0 keeps the current value.
+	Cores int `yaml:"cores,omitempty" json:"cores,omitempty" jsonschema:"minimum=1,example=2"`
+
+	// CoreFraction is the guaranteed VM core performance in percent set by a
+	// resize action. Only applies to resize actions; 0 keeps the current value.
+	CoreFraction int `yaml:"core_fraction,omitempty" json:"core_fraction,omitempty" jsonschema:"enum=5,enum=20,enum=50,enum=100,example=20"`
+
+	// MemoryGB is the VM memory in GiB set by a resize action.
+	// Only applies to resize actions; 0 keeps the current value.
+	MemoryGB int `yaml:"memory_gb,omitempty" json:"memory_gb,omitempty" jsonschema:"minimum=1,example=4"`
+
+	// Preemptible is the VM scheduling policy set by a preemptible action.
+	// Required for preemptible actions.
+	Preemptible *bool `yaml:"preemptible,omitempty" json:"preemptible,omitempty" jsonschema:"example=true"`
+
+	// OnStateCheckError defines what happens when the resource state cannot be
+	// read before the operation: proceed (default) runs the operation anyway,
+	// skip skips it and retry rereads the state with backoff before skipping.
+	OnStateCheckError string `yaml:"on_state_check_error,omitempty" json:"on_state_check_error,omitempty" jsonschema:"enum=proceed,enum=skip,enum=retry,default=proceed"`
+
+	// Timeout bounds the action run for all resources of the schedule, or
+	// of each schedule step, overriding the global action_timeout, e.g. for
+	// k8s cluster starts that take longer than the default 5m.
+	Timeout Duration `yaml:"timeout,omitempty" json:"timeout,omitempty" jsonschema:"example=20m"`
+
+	// RunWithin skips a run that could not start within this long after its
+	// scheduled time, e.g. after a restart or a long job backlog, rather than
+	// stopping at 2am a resource meant to stop at 8pm. The validator does not
+	// correct the state of a missed action either. 0 runs late runs anyway.
+	RunWithin Duration `yaml:"run_within,omitempty" json:"run_within,omitempty" jsonschema:"example=30m"`
+
+	// GracePeriod announces a stop this long before it runs with a
+	// stop_imminent notification, so users can postpone it through the HTTP
+	// API. Only applies to stop actions.
+	GracePeriod Duration `yaml:"grace_period,omitempty" json:"grace_period,omitempty" jsonschema:"example=15m"`
+
+	// MinUptime skips the stop of a resource started less than this long
+	// ago, e.g. a VM started manually shortly before the nightly stop. Only
+	// applies to stop actions of vm resources.
+	MinUptime Duration `yaml:"min_uptime,omitempty" json:"min_uptime,omitempty" jsonschema:"example=1h"`
+
+	// Retry repeats the operation after transient API errors.
+	Retry *RetryConfig `yaml:"retry,omitempty" json:"retry,omitempty"`
+
+	// RetryFailed runs the action again as a one-time job after a backoff
+	// when a scheduled run fails for any reason, so a resource does not stay
+	// in the wrong state until the next validator pass.
+	RetryFailed *RetryConfig `yaml:"retry_failed,omitempty" json:"retry_failed,omitempty"`
+
+	// Concurrency is the concurrency class of the action: shared (default)
+	// operations run alongside each other, an exclusive operation waits until
+	// no other operation runs in its ConcurrencyScope and blocks new ones
+	// there, e.g. a k8s cluster stop versus node group operations.
+	Concurrency string `yaml:"concurrency,omitempty" json:"concurrency,omitempty" jsonschema:"enum=shared,enum=exclusive,default=shared"`
+
+	// ConcurrencyScope is what an exclusive operation locks: the folder of
+	// the resource (default) or all resources of the scheduler.
+	ConcurrencyScope string `yaml:"concurrency_scope,omitempty" json:"concurrency_scope,omitempty" jsonschema:"enum=folder,enum=global,default=folder"`
+
+	// PreHook runs before the operation, e.g. to drain traffic before a stop.
+	PreHook *HookConfig `yaml:"pre_hook,omitempty" json:"pre_hook,omitempty"`
+
+	// PostHook runs after a successful operation.
+	PostHook *HookConfig `yaml:"post_hook,omitempty" json:"post_hook,omitempty"`
+}
+
+// Concurrency classes of actions.
+const (
+	// ConcurrencyShared runs the operation alongside other operations.
+	ConcurrencyShared = "shared"
+	// ConcurrencyExclusive runs the operation alone in its scope.
+	ConcurrencyExclusive = "exclusive"
+)
+
+// Lock scopes of exclusive actions.
+const (
+	// ConcurrencyScopeFolder locks the folder of the resource.
+	ConcurrencyScopeFolder = "folder"
+	// ConcurrencyScopeGlobal locks all resources of the scheduler.
+	ConcurrencyScopeGlobal = "global"
+)
+
+// Policies for state check errors before an operation.
+const (
+	// StateCheckProceed runs the operation without knowing the state.
+	StateCheckProceed = "proceed"
+	// StateCheckSkip skips the operation.
+	StateCheckSkip = "skip"
+	// StateCheckRetry rereads the state with backoff and skips the operation
+	// if it still cannot be read.
+	StateCheckRetry = "retry"
+)
+
+// Hook failure policies.
+const (
+	// HookAbort fails the action when the hook fails: a failed pre hook
+	// skips the operation, a failed post hook marks the action as failed.
+	HookAbort = "abort"
+	// HookContinue logs hook failures and proceeds with the action.
+	HookContinue = "continue"
+)
+
+// defaultHookTimeout bounds a hook run when Timeout is not set.
+const defaultHookTimeout = 30 * time.Second
+
+// HookConfig defines an HTTP webhook or local command run before or after
+// an action. Exactly one of URL and Command must be set.
+type HookConfig struct {
+	// URL receives the execution context as a JSON POST request.
+	// A non-2xx response fails the hook.
+	URL string `yaml:"url,omitempty" json:"url,omitempty" jsonschema:"format=uri,example=https://lb.example.com/drain"`
+
+	// Command is a local command with arguments run with the execution
+	// context in YC_SCHEDULER_* environment variables.
+	// A non-zero exit status fails the hook.
+	Command []string `yaml:"command,omitempty" json:"command,omitempty" jsonschema:"minItems=1,example=/usr/local/bin/drain"`
+
+	// Timeout bounds the hook run.
+	Timeout Duration `yaml:"timeout,omitempty" json:"timeout,omitempty" jsonschema:"default=30s,example=2m"`
+
+	// OnFailure is the failure policy: abort (default) or continue.
+	OnFailure string `yaml:"on_failure,omitempty" json:"on_failure,omitempty" jsonschema:"enum=abort,enum=continue,default=abort"`
+}
+
+// EffectiveTimeout returns the configured hook timeout.
+func (h *HookConfig) EffectiveTimeout() time.Duration {
+	if h.Timeout.Duration <= 0 {
+		return defaultHookTimeout
+	}
+	return h.Timeout.Duration
+}
+
+// AbortsOnFailure reports whether a hook failure fails the action.
+func (h *HookConfig) AbortsOnFailure() bool {
+	return h.OnFailure != HookContinue
+}
+
+// Defaults of the operation retry block.
+const (
+	defaultRetries    = 3
+	defaultBackoff    = 10 * time.Second
+	defaultMaxBackoff = 2 * time.Minute
+)
+
+// RetryConfig defines how an operation is repeated after transient API
+// errors, or a failed action run with retry_failed. The delay before a retry
+// starts at Backoff and doubles with every retry up to MaxBackoff.
+type RetryConfig struct {
+	// Retries is the number of retries after the first attempt.
+	Retries int `yaml:"retries,omitempty" json:"retries,omitempty" jsonschema:"minimum=1,default=3,example=5"`
+
+	// Backoff is the delay before the first retry.
+	Backoff Duration `yaml:"backoff,omitempty" json:"backoff,omitempty" jsonschema:"default=10s,example=30s"`
+
+	// MaxBackoff caps the delay between retries.
+	MaxBackoff Duration `yaml:"max_backoff,omitempty" json:"max_backoff,omitempty" jsonschema:"default=2m,example=5m"`
+}
+
+// EffectiveRetries returns the configured number of retries.
+func (r *RetryConfig) EffectiveRetries() int {
+	if r.Retries <= 0 {
+		return defaultRetries
+	}
+	return r.Retries
+}
+
+// Delay returns the delay before the given retry, starting from 1.
+func (r *RetryConfig) Delay(retry int) time.Duration {
+	delay := r.Backoff.Duration
+	if delay <= 0 {
+		delay = defaultBackoff
+	}
+	maxDelay := r.MaxBackoff.Duration
+	if maxDelay <= 0 {
+		maxDelay = defaultMaxBackoff
+	}
+	for i := 1; i < retry && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxDelay)
 }
 
 // CronJobConfig defines configuration for a cron-based schedule.
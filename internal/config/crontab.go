@@ -2,8 +2,10 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/invopop/jsonschema"
+	"github.com/robfig/cron/v3"
 )
 
 // Crontab represents a cron expression.
@@ -51,7 +53,22 @@ func (Crontab) JSONSchema() *jsonschema.Schema {
 		Type:        "string",
 		Description: "Cron expression (5 or 6 fields: minute hour day month weekday [second])",
 		Pattern:     `^(\S+\s+){4,5}\S+$`,
+		Format:      cronFormat,
 		Examples:    []any{"0 9 * * *", "0 0 * * 0", "*/5 * * * *"},
 		MinLength:   &minLen,
 	}
 }
+
+// cronFormat is the JSON schema format of cron expressions checked by
+// Crontab.Validate.
+const cronFormat = "cron"
+
+// Validate checks that the expression parses the way jobs are registered:
+// five fields or a descriptor such as "@daily", optionally prefixed with
+// CRON_TZ=.
+func (c Crontab) Validate() error {
+	if _, err := cron.ParseStandard(string(c)); err != nil {
+		return fmt.Errorf("invalid crontab %q: %w", string(c), err)
+	}
+	return nil
+}
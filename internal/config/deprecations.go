@@ -0,0 +1,81 @@
+package config
+
+import "slices"
+
+// Deprecated features reported by Deprecations.
+const (
+	// DeprecatedCronJob is the cron_job schedule block.
+	DeprecatedCronJob = "cron_job"
+	// DeprecatedDailyJob is the daily_job schedule block.
+	DeprecatedDailyJob = "daily_job"
+	// DeprecatedWeeklyJob is the weekly_job schedule block.
+	DeprecatedWeeklyJob = "weekly_job"
+	// DeprecatedMonthlyJob is the monthly_job schedule block.
+	DeprecatedMonthlyJob = "monthly_job"
+	// DeprecatedTokenAuth is authentication with an OAuth/IAM token.
+	DeprecatedTokenAuth = "token_auth"
+)
+
+// DeprecatedFeatures lists all deprecated features.
+var DeprecatedFeatures = []string{
+	DeprecatedCronJob,
+	DeprecatedDailyJob,
+	DeprecatedWeeklyJob,
+	DeprecatedMonthlyJob,
+	DeprecatedTokenAuth,
+}
+
+// deprecationMessages describe the replacement of deprecated features.
+var deprecationMessages = map[string]string{
+	DeprecatedCronJob:    "cron_job is ignored, set crontab on actions instead",
+	DeprecatedDailyJob:   "daily_job is ignored, set time on actions instead",
+	DeprecatedWeeklyJob:  "weekly_job is ignored, set time and day on actions instead",
+	DeprecatedMonthlyJob: "monthly_job is ignored, set time and day on actions instead",
+	DeprecatedTokenAuth:  "token authentication is discouraged, use a service account key (--sa-key) instead",
+}
+
+// Deprecation is a deprecated feature in use. Sources name where it is
+// used, e.g. schedule names.
+type Deprecation struct {
+	Feature string   `json:"feature"`
+	Message string   `json:"message"`
+	Sources []string `json:"sources"`
+}
+
+// NewDeprecation creates a Deprecation of feature used by sources.
+func NewDeprecation(feature string, sources ...string) Deprecation {
+	return Deprecation{
+		Feature: feature,
+		Message: deprecationMessages[feature],
+		Sources: sources,
+	}
+}
+
+// ScheduleDeprecations returns the deprecated features used by schedules in
+// the order of DeprecatedFeatures.
+func ScheduleDeprecations(schedules []Schedule) []Deprecation {
+	used := make(map[string][]string)
+	for _, sch := range schedules {
+		if sch.CronJob != nil {
+			used[DeprecatedCronJob] = append(used[DeprecatedCronJob], sch.Name)
+		}
+		if sch.DailyJob != nil {
+			used[DeprecatedDailyJob] = append(used[DeprecatedDailyJob], sch.Name)
+		}
+		if sch.WeeklyJob != nil {
+			used[DeprecatedWeeklyJob] = append(used[DeprecatedWeeklyJob], sch.Name)
+		}
+		if sch.MonthlyJob != nil {
+			used[DeprecatedMonthlyJob] = append(used[DeprecatedMonthlyJob], sch.Name)
+		}
+	}
+
+	var deprecations []Deprecation
+	for _, feature := range DeprecatedFeatures {
+		if sources := used[feature]; len(sources) > 0 {
+			slices.Sort(sources)
+			deprecations = append(deprecations, NewDeprecation(feature, sources...))
+		}
+	}
+	return deprecations
+}
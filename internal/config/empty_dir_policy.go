@@ -0,0 +1,28 @@
+package config
+
+import "github.com/invopop/jsonschema"
+
+// EmptyDirPolicy selects how a schedules reload treats a schedules directory
+// that is unreadable, or empty after it had schedule files.
+type EmptyDirPolicy string
+
+const (
+	// EmptyDirPolicyKeep keeps the last loaded schedules and alerts until the
+	// directory is back. It is the default.
+	EmptyDirPolicyKeep EmptyDirPolicy = "keep"
+
+	// EmptyDirPolicyApply reloads the schedules as they are, dropping the
+	// schedules of an empty directory.
+	EmptyDirPolicyApply EmptyDirPolicy = "apply"
+)
+
+// JSONSchema returns the JSON schema for EmptyDirPolicy type.
+func (EmptyDirPolicy) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "string",
+		Description: "Handling of an unreadable or emptied schedules directory on reload: keep or apply",
+		Enum:        []any{string(EmptyDirPolicyKeep), string(EmptyDirPolicyApply)},
+		Default:     string(EmptyDirPolicyKeep),
+		Examples:    []any{string(EmptyDirPolicyApply)},
+	}
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+// applyEnvOverrides sets top-level fields of cfg from the environment
+// variables named by their env tags. Values are decoded as YAML like the
+// config file, so lists are written as [a, b]. Empty variables are ignored.
+func applyEnvOverrides(cfg *Config, lookup func(string) (string, bool)) error {
+	v := reflect.ValueOf(cfg).Elem()
+	t := v.Type()
+	for i := range t.NumField() {
+		name := t.Field(i).Tag.Get("env")
+		if name == "" {
+			continue
+		}
+		value, ok := lookup(name)
+		if !ok || value == "" {
+			continue
+		}
+		if err := yaml.Unmarshal([]byte(value), v.Field(i).Addr().Interface()); err != nil {
+			return fmt.Errorf("%w: environment variable %s: %v", ErrInvalidConfig, name, err)
+		}
+		log.Debug().
+			Str("env", name).
+			Msg("Configuration field overridden from environment")
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestApplyEnvOverrides(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{
+		"YC_SHEDULER_TIMEZONE":             "Europe/Moscow",
+		"YC_SHEDULER_METRICS_PORT":         "9100",
+		"YC_SHEDULER_METRICS_ENABLED":      "true",
+		"YC_SHEDULER_VALIDATION_RESOURCES": "false",
+		"YC_SHEDULER_VALIDATION_INTERVAL":  "2m",
+		"YC_SHEDULER_LISTEN_ADDRESSES":     `[":9100", "unix:/run/yc-scheduler/admin.sock"]`,
+		"YC_SHEDULER_SCHEDULES_DIR":        "/etc/yc-scheduler/schedules",
+		"YC_SHEDULER_UI_ENABLED":           "",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	cfg := Config{MetricsPort: 9090, UIEnabled: true, SchedulesDir: SchedulesDirs{"./schedules"}}
+	if err := applyEnvOverrides(&cfg, lookup); err != nil {
+		t.Fatalf("applyEnvOverrides() error = %v", err)
+	}
+
+	if cfg.Timezone != "Europe/Moscow" || cfg.MetricsPort != 9100 || !cfg.MetricsEnabled {
+		t.Fatalf("cfg = %+v, want timezone, metrics port and metrics enabled overridden", cfg)
+	}
+	if cfg.IsValidationResourcesEnabled() {
+		t.Fatal("ValidationResources = true, want false")
+	}
+	if cfg.ValidationInterval.Std() != 2*time.Minute {
+		t.Fatalf("ValidationInterval = %s, want 2m", cfg.ValidationInterval)
+	}
+	if want := []string{":9100", "unix:/run/yc-scheduler/admin.sock"}; !slices.Equal(cfg.ListenAddresses, want) {
+		t.Fatalf("ListenAddresses = %q, want %q", cfg.ListenAddresses, want)
+	}
+	if want := (SchedulesDirs{"/etc/yc-scheduler/schedules"}); !slices.Equal(cfg.SchedulesDir, want) {
+		t.Fatalf("SchedulesDir = %q, want %q", cfg.SchedulesDir, want)
+	}
+	// Empty variables do not override the file.
+	if !cfg.UIEnabled {
+		t.Fatal("UIEnabled = false, want the file value kept for an empty variable")
+	}
+}
+
+func TestApplyEnvOverridesInvalidValue(t *testing.T) {
+	t.Parallel()
+
+	lookup := func(name string) (string, bool) {
+		return "many", name == "YC_SHEDULER_MAX_CONCURRENT_JOBS"
+	}
+	if err := applyEnvOverrides(&Config{}, lookup); !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("applyEnvOverrides() error = %v, want %v", err, ErrInvalidConfig)
+	}
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// ScheduleAPIVersion is the apiVersion of schedule manifests.
+const ScheduleAPIVersion = "scheduler.yc/v1alpha1"
+
+// Hash returns a short hex digest of the settings of cfg and the given
+// schedules, which replace cfg.Schedules since those are not updated when
+// schedules are reloaded. Instances running the same effective
+// configuration have the same hash.
+func Hash(cfg *Config, schedules []Schedule) (string, error) {
+	hasher := sha256.New()
+	encoder := json.NewEncoder(hasher)
+	if err := encoder.Encode(cfg); err != nil {
+		return "", fmt.Errorf("hash settings: %w", err)
+	}
+	if err := encoder.Encode(schedules); err != nil {
+		return "", fmt.Errorf("hash schedules: %w", err)
+	}
+	return hex.EncodeToString(hasher.Sum(nil))[:16], nil
+}
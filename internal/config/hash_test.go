@@ -0,0 +1,63 @@
+package config
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHash(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	mustWriteFile(t, configPath, []byte(strings.TrimSpace(`
+timezone: Europe/Moscow
+validation_interval: 10m
+shutdown_timeout: 5m
+schedules:
+  - apiVersion: scheduler.yc/v1alpha1
+    kind: Schedule
+    metadata:
+      name: vm-stop
+    spec:
+      type: cron
+      resource:
+        type: vm
+        id: fhm1234567890abcdef
+        folder_id: b1g1234567890abcdef
+      actions:
+        stop:
+          enabled: true
+          crontab: 0 18 * * *
+`)))
+
+	cfg, err := Load(context.Background(), configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	hash, err := Hash(cfg, cfg.Schedules)
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	if len(hash) != 16 {
+		t.Fatalf("Hash() = %q, want 16 hex digits", hash)
+	}
+
+	reloaded, err := Load(context.Background(), configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if again, _ := Hash(reloaded, reloaded.Schedules); again != hash {
+		t.Fatalf("Hash() of the same config = %q, want %q", again, hash)
+	}
+
+	changed := append([]Schedule(nil), cfg.Schedules...)
+	changed[0].Actions.Stop = &ActionConfig{Enabled: true, Crontab: "0 19 * * *"}
+	if other, _ := Hash(cfg, changed); other == hash {
+		t.Fatal("Hash() did not change with the schedules")
+	}
+	if none, _ := Hash(cfg, nil); none == hash {
+		t.Fatal("Hash() without schedules equals the hash with them")
+	}
+}
@@ -7,11 +7,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/creasty/defaults"
 	"github.com/rs/zerolog/log"
@@ -19,6 +22,7 @@ import (
 	jamle "github.com/woozymasta/jamle"
 	"gopkg.in/yaml.v3"
 
+	"github.com/sentoz/yc-sheduler/internal/source"
 	"github.com/sentoz/yc-sheduler/static"
 )
 
@@ -55,6 +59,16 @@ func compileEmbeddedSchema(raw []byte, schemaURL string, loadErr error) (*jschem
 	}
 
 	compiler := jschema.NewCompiler()
+	compiler.RegisterFormat(&jschema.Format{
+		Name: cronFormat,
+		Validate: func(v any) error {
+			s, ok := v.(string)
+			if !ok {
+				return nil
+			}
+			return Crontab(s).Validate()
+		},
+	})
 
 	// Embedded schema contains raw JSON bytes. AddResource expects a decoded JSON value.
 	var schemaDoc interface{}
@@ -75,72 +89,212 @@ func compileEmbeddedSchema(raw []byte, schemaURL string, loadErr error) (*jschem
 }
 
 // Load reads, parses and validates configuration from the given path.
-// The path must point to a YAML or JSON file. Environment variables inside
-// the configuration are expanded by jamle.
-func Load(_ context.Context, path string) (*Config, error) {
-	if path == "" {
-		return nil, fmt.Errorf("%w: empty path", ErrConfigNotFound)
+// The path must point to a YAML or JSON file, or be an HTTP(S) URL serving
+// one; relative schedules directories of a config fetched from a URL are
+// resolved against the working directory. Environment variables inside
+// the configuration are expanded by jamle, and top-level fields are then
+// overridden by the environment variables named by their env tags.
+func Load(ctx context.Context, path string) (*Config, error) {
+	settings, raw, err := loadSettings(ctx, path)
+	if err != nil {
+		return nil, err
 	}
+	cfg := *settings
+	cfg.settings = settings
 
-	info, err := os.Stat(path)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return nil, fmt.Errorf("%w: %s", ErrConfigNotFound, path)
+	schedulesDirs := resolveSchedulesDirs(cfg.SchedulesDir, path)
+
+	if cfg.SchedulesSource != "" {
+		src, err := source.New(cfg.SchedulesSource, os.LookupEnv)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
 		}
-		return nil, fmt.Errorf("stat config %q: %w", path, err)
+		cfg.ScheduleSources = append(cfg.ScheduleSources, src)
+	}
+	if cfg.SchedulesURL != "" {
+		src, err := source.NewHTTP(cfg.SchedulesURL)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+		}
+		cfg.ScheduleSources = append(cfg.ScheduleSources, src)
+	}
+	if git := cfg.SchedulesGit; git != nil {
+		src, err := source.NewGit(source.GitOptions{
+			URL:            git.URL,
+			Branch:         git.Branch,
+			Path:           git.Path,
+			DeployKeyFile:  git.DeployKeyFile,
+			KnownHostsFile: git.KnownHostsFile,
+			PollInterval:   git.PollInterval.Duration,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+		}
+		cfg.ScheduleSources = append(cfg.ScheduleSources, src)
+	}
+	if kube := cfg.SchedulesKubernetes; kube != nil {
+		src, err := source.NewKubernetes(source.KubernetesOptions{
+			Kind:          kube.Kind,
+			Namespace:     kube.Namespace,
+			LabelSelector: kube.LabelSelector,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+		}
+		cfg.ScheduleSources = append(cfg.ScheduleSources, src)
+	}
+	if err := source.SyncAll(ctx, cfg.ScheduleSources); err != nil {
+		return nil, fmt.Errorf("sync schedules sources: %w", err)
+	}
+	for _, src := range cfg.ScheduleSources {
+		schedulesDirs = append(schedulesDirs, src.Dir())
 	}
 
-	if info.IsDir() {
-		return nil, fmt.Errorf("%w: %s is a directory, expected file", ErrInvalidConfig, path)
+	inline, err := parseInlineSchedules(raw, source.Redact(path))
+	if err != nil {
+		return nil, err
 	}
 
-	raw, err := os.ReadFile(path)
+	schedules, err := loadSchedules(schedulesDirs, cfg.SchedulesRecursive, inline, source.Redact(path), cfg.hooklessDirs())
 	if err != nil {
-		return nil, fmt.Errorf("read config %q: %w", path, err)
+		return nil, err
+	}
+	cfg.SchedulesDir = schedulesDirs
+	cfg.Schedules = cfg.OwnedSchedules(schedules)
+	if cfg.Sharded() {
+		log.Info().
+			Int("shard_index", cfg.ShardIndex).
+			Int("shard_count", cfg.ShardCount).
+			Int("owned", len(cfg.Schedules)).
+			Int("total", len(schedules)).
+			Msg("Schedules assigned to shard")
+	}
+
+	log.Info().
+		Str("config_path", source.Redact(path)).
+		Strs("schedules_dir", cfg.SchedulesDir).
+		Int("schedules", len(cfg.Schedules)).
+		Msg("Configuration and schedules loaded and validated")
+
+	return &cfg, nil
+}
+
+// LoadSettings reads, parses and validates the config file at path like Load,
+// without syncing schedules sources and loading schedule manifests. It is used
+// to reload the settings of a running scheduler, see Config.WithSettings.
+func LoadSettings(ctx context.Context, path string) (*Config, error) {
+	cfg, raw, err := loadSettings(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := parseInlineSchedules(raw, source.Redact(path)); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// loadSettings returns the validated settings of the config file at path
+// along with its raw content.
+func loadSettings(ctx context.Context, path string) (*Config, []byte, error) {
+	if path == "" {
+		return nil, nil, fmt.Errorf("%w: empty path", ErrConfigNotFound)
+	}
+
+	raw, err := readConfig(ctx, path)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	var cfg Config
 	if err := jamle.Unmarshal(raw, &cfg); err != nil {
-		return nil, fmt.Errorf("%w: decode: %v", ErrInvalidConfig, err)
+		return nil, nil, fmt.Errorf("%w: decode: %v", ErrInvalidConfig, err)
+	}
+
+	if err := applyEnvOverrides(&cfg, os.LookupEnv); err != nil {
+		return nil, nil, err
 	}
 
 	// Apply default values for fields that weren't set in the config.
 	if err := defaults.Set(&cfg); err != nil {
-		return nil, fmt.Errorf("%w: apply defaults: %v", ErrInvalidConfig, err)
+		return nil, nil, fmt.Errorf("%w: apply defaults: %v", ErrInvalidConfig, err)
 	}
 
 	if err := validate(&cfg); err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if err := validateShard(&cfg); err != nil {
+		return nil, nil, err
+	}
+	return &cfg, raw, nil
+}
+
+// resolveSchedulesDirs resolves relative schedules directories against the
+// directory of the config file at path.
+func resolveSchedulesDirs(dirs SchedulesDirs, path string) SchedulesDirs {
+	resolved := make(SchedulesDirs, 0, len(dirs))
+	for _, dir := range dirs {
+		if !filepath.IsAbs(dir) && !source.IsURL(path) {
+			dir = filepath.Join(filepath.Dir(path), dir)
+		}
+		resolved = append(resolved, dir)
 	}
+	return resolved
+}
 
-	schedulesDir := cfg.SchedulesDir
-	if !filepath.IsAbs(schedulesDir) {
-		schedulesDir = filepath.Join(filepath.Dir(path), schedulesDir)
+// readConfig returns the content of the config file at path or served at
+// the URL path.
+func readConfig(ctx context.Context, path string) ([]byte, error) {
+	if source.IsURL(path) {
+		raw, err := source.Fetch(ctx, path)
+		if err != nil {
+			return nil, fmt.Errorf("fetch config: %w", err)
+		}
+		return raw, nil
 	}
 
-	schedules, err := loadSchedules(schedulesDir)
+	info, err := os.Stat(path)
 	if err != nil {
-		return nil, err
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrConfigNotFound, path)
+		}
+		return nil, fmt.Errorf("stat config %q: %w", path, err)
 	}
-	cfg.SchedulesDir = schedulesDir
-	cfg.Schedules = schedules
 
-	log.Info().
-		Str("config_path", path).
-		Str("schedules_dir", cfg.SchedulesDir).
-		Int("schedules", len(cfg.Schedules)).
-		Msg("Configuration and schedules loaded and validated")
+	if info.IsDir() {
+		return nil, fmt.Errorf("%w: %s is a directory, expected file", ErrInvalidConfig, path)
+	}
 
-	return &cfg, nil
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config %q: %w", path, err)
+	}
+	return raw, nil
 }
 
-// LoadSchedules reads and validates schedule manifests from a directory.
-func LoadSchedules(_ context.Context, path string) ([]Schedule, error) {
-	if path == "" {
+// LoadSchedules reads and validates schedule manifests from directories and,
+// when recursive, their subdirectories. Schedule names must be unique across
+// all directories.
+func LoadSchedules(_ context.Context, recursive bool, paths ...string) ([]Schedule, error) {
+	if len(paths) == 0 || slices.Contains(paths, "") {
 		return nil, fmt.Errorf("%w: empty schedules directory path", ErrConfigNotFound)
 	}
 
-	return loadSchedules(path)
+	return loadSchedules(paths, recursive, nil, "", nil)
+}
+
+// ReloadSchedules reads the manifests of the schedules directories of cfg
+// again and merges them with its inline schedules, which change only with the
+// config file. Only the schedules owned by the shard of cfg are returned.
+func ReloadSchedules(_ context.Context, cfg *Config) ([]Schedule, error) {
+	inline := make([]Schedule, 0, len(cfg.InlineSchedules))
+	for _, manifest := range cfg.InlineSchedules {
+		inline = append(inline, manifest.ToSchedule())
+	}
+	schedules, err := loadSchedules(cfg.SchedulesDir, cfg.SchedulesRecursive, inline, "config file", cfg.hooklessDirs())
+	if err != nil {
+		return nil, err
+	}
+	return cfg.OwnedSchedules(schedules), nil
 }
 
 // validate checks configuration against the embedded JSON schema and
@@ -151,9 +305,14 @@ func validate(cfg *Config) error {
 		return err
 	}
 
+	// Inline schedules are validated against the schedule schema when they
+	// are parsed, reporting positions in the config file.
+	doc := *cfg
+	doc.InlineSchedules = nil
+
 	// Marshal config to JSON, then unmarshal to interface{} so Validate receives
 	// a valid JSON value (map/slice), not *bytes.Reader.
-	data, err := json.Marshal(cfg)
+	data, err := json.Marshal(doc)
 	if err != nil {
 		return fmt.Errorf("%w: marshal for validation: %v", ErrInvalidConfig, err)
 	}
@@ -170,77 +329,168 @@ func validate(cfg *Config) error {
 	return nil
 }
 
-func loadSchedules(path string) ([]Schedule, error) {
-	info, err := os.Stat(path)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return nil, fmt.Errorf("%w: schedules directory not found: %s", ErrConfigNotFound, path)
+// ScheduleFiles returns the YAML files of a schedules directory relative to
+// it, sorted by path. When recursive, files of subdirectories are included
+// except of hidden ones, such as the timestamped directories of a mounted
+// Kubernetes ConfigMap that would duplicate its files.
+func ScheduleFiles(dir string, recursive bool) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
 		}
-		return nil, fmt.Errorf("stat schedules dir %q: %w", path, err)
-	}
-	if !info.IsDir() {
-		return nil, fmt.Errorf("%w: %s is not a directory", ErrInvalidConfig, path)
-	}
-
-	entries, err := os.ReadDir(path)
+		if entry.IsDir() {
+			if path == dir {
+				return nil
+			}
+			if !recursive || strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, rel)
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("read schedules dir %q: %w", path, err)
+		return nil, err
 	}
+	sort.Strings(files)
+	return files, nil
+}
 
-	sort.Slice(entries, func(i, j int) bool {
-		return entries[i].Name() < entries[j].Name()
-	})
-
-	schedules := make([]Schedule, 0, len(entries))
-	names := make(map[string]string, len(entries))
+// loadSchedules reads the manifests of the schedules directories and merges
+// them with the inline schedules defined in inlineSource. Schedules with
+// command hooks are rejected in the directories of hookless.
+func loadSchedules(paths []string, recursive bool, inline []Schedule, inlineSource string, hookless map[string]bool) ([]Schedule, error) {
+	schedules := make([]Schedule, 0, len(inline))
+	names := make(map[string]string)
 	parsedFiles := 0
 
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-		ext := strings.ToLower(filepath.Ext(entry.Name()))
-		if ext != ".yaml" && ext != ".yml" {
-			continue
+	for _, sch := range inline {
+		if _, exists := names[sch.Name]; exists {
+			return nil, fmt.Errorf("%w: duplicate schedule name %q in schedules of %s", ErrInvalidConfig, sch.Name, inlineSource)
 		}
+		names[sch.Name] = inlineSource
+		schedules = append(schedules, sch)
+	}
 
-		filePath := filepath.Join(path, entry.Name())
-		raw, err := os.ReadFile(filePath)
+	for _, path := range paths {
+		info, err := os.Stat(path)
 		if err != nil {
-			return nil, fmt.Errorf("read schedule file %q: %w", filePath, err)
+			if errors.Is(err, os.ErrNotExist) {
+				return nil, fmt.Errorf("%w: schedules directory not found: %s", ErrConfigNotFound, path)
+			}
+			return nil, fmt.Errorf("stat schedules dir %q: %w", path, err)
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("%w: %s is not a directory", ErrInvalidConfig, path)
 		}
 
-		fileSchedules, err := parseScheduleFile(raw, filePath)
+		files, err := ScheduleFiles(path, recursive)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("read schedules dir %q: %w", path, err)
 		}
-		parsedFiles++
 
-		for _, sch := range fileSchedules {
-			if prev, exists := names[sch.Name]; exists {
-				return nil, fmt.Errorf("%w: duplicate schedule name %q in %s and %s", ErrInvalidConfig, sch.Name, prev, filePath)
+		for _, file := range files {
+			filePath := filepath.Join(path, file)
+			raw, err := os.ReadFile(filePath)
+			if err != nil {
+				return nil, fmt.Errorf("read schedule file %q: %w", filePath, err)
+			}
+
+			fileSchedules, err := ParseScheduleFile(raw, filePath)
+			if err != nil {
+				return nil, err
+			}
+			parsedFiles++
+
+			for _, sch := range fileSchedules {
+				if action := commandHookAction(sch); action != "" && hookless[path] {
+					return nil, fmt.Errorf("%w: schedule %q in %s: command hooks of %s are not allowed in schedules of remote sources, set allow_remote_command_hooks to run them", ErrInvalidConfig, sch.Name, filePath, action)
+				}
+				if prev, exists := names[sch.Name]; exists {
+					return nil, fmt.Errorf("%w: duplicate schedule name %q in %s and %s", ErrInvalidConfig, sch.Name, prev, filePath)
+				}
+				names[sch.Name] = filePath
+				schedules = append(schedules, sch)
 			}
-			names[sch.Name] = filePath
-			schedules = append(schedules, sch)
 		}
 	}
 
-	if parsedFiles == 0 {
-		return nil, fmt.Errorf("%w: no YAML schedule files found in %s", ErrInvalidConfig, path)
+	if len(paths) == 0 && len(inline) == 0 {
+		return nil, fmt.Errorf("%w: none of schedules_dir, schedules_source, schedules_git and schedules is set", ErrInvalidConfig)
+	}
+	dirs := strings.Join(paths, ", ")
+	if parsedFiles == 0 && len(inline) == 0 {
+		return nil, fmt.Errorf("%w: no YAML schedule files found in %s", ErrInvalidConfig, dirs)
 	}
 	if len(schedules) == 0 {
-		return nil, fmt.Errorf("%w: no schedule documents found in %s", ErrInvalidConfig, path)
+		return nil, fmt.Errorf("%w: no schedule documents found in %s", ErrInvalidConfig, dirs)
 	}
 
 	return schedules, nil
 }
 
-func parseScheduleFile(raw []byte, path string) ([]Schedule, error) {
-	schema, err := getScheduleSchema()
-	if err != nil {
-		return nil, err
+// hooklessDirs returns the directories the schedules sources of c are
+// mirrored to, whose schedules must not run command hooks unless
+// AllowRemoteCommandHooks is set.
+func (c *Config) hooklessDirs() map[string]bool {
+	if c.AllowRemoteCommandHooks {
+		return nil
 	}
+	dirs := make(map[string]bool, len(c.ScheduleSources))
+	for _, src := range c.ScheduleSources {
+		dirs[src.Dir()] = true
+	}
+	return dirs
+}
 
+// commandHookAction returns the name of the first action of sch with a
+// command pre_hook or post_hook, or an empty string if there is none.
+func commandHookAction(sch Schedule) string {
+	actions := []struct {
+		name    string
+		configs []ActionConfig
+	}{
+		{name: "start", configs: optionalAction(sch.Actions.Start)},
+		{name: "stop", configs: optionalAction(sch.Actions.Stop)},
+		{name: "snapshot", configs: optionalAction(sch.Actions.Snapshot)},
+		{name: "restart", configs: optionalAction(sch.Actions.Restart)},
+		{name: "scale", configs: sch.Actions.Scale},
+		{name: "resize", configs: optionalAction(sch.Actions.Resize)},
+		{name: "preemptible", configs: sch.Actions.Preemptible},
+	}
+	for _, action := range actions {
+		for _, cfg := range action.configs {
+			for _, h := range []*HookConfig{cfg.PreHook, cfg.PostHook} {
+				if h != nil && len(h.Command) > 0 {
+					return action.name
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// optionalAction returns cfg as a slice of at most one action config.
+func optionalAction(cfg *ActionConfig) []ActionConfig {
+	if cfg == nil {
+		return nil
+	}
+	return []ActionConfig{*cfg}
+}
+
+// ParseScheduleFile parses and validates the schedule manifests of a YAML
+// file; path names the file in errors.
+func ParseScheduleFile(raw []byte, path string) ([]Schedule, error) {
 	decoder := yaml.NewDecoder(bytes.NewReader(raw))
 	schedules := make([]Schedule, 0, 1)
 	docIndex := 0
@@ -259,30 +509,135 @@ func parseScheduleFile(raw []byte, path string) ([]Schedule, error) {
 			continue
 		}
 
-		var doc interface{}
-		if err := node.Decode(&doc); err != nil {
-			return nil, fmt.Errorf("%w: decode document %d in %s: %v", ErrInvalidConfig, docIndex, path, err)
+		sch, ok, err := parseScheduleDocument(&node, path, fmt.Sprintf("document %d in %s", docIndex, path))
+		if err != nil {
+			return nil, err
 		}
-		if doc == nil {
-			continue
+		if ok {
+			schedules = append(schedules, sch)
 		}
+	}
 
-		if err := schema.Validate(doc); err != nil {
-			return nil, fmt.Errorf("%w: document %d in %s: %v", ErrScheduleSchemaValidation, docIndex, path, err)
-		}
+	return schedules, nil
+}
 
-		docBytes, err := yaml.Marshal(doc)
-		if err != nil {
-			return nil, fmt.Errorf("%w: marshal document %d in %s: %v", ErrInvalidConfig, docIndex, path, err)
+// parseInlineSchedules parses the schedule manifests listed under the
+// schedules key of the config file.
+func parseInlineSchedules(raw []byte, path string) ([]Schedule, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(raw, &root); err != nil {
+		return nil, fmt.Errorf("%w: decode: %v", ErrInvalidConfig, err)
+	}
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return nil, nil
+	}
+
+	mapping := root.Content[0]
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value != "schedules" {
+			continue
+		}
+		list := mapping.Content[i+1]
+		if list.Kind != yaml.SequenceNode {
+			return nil, fmt.Errorf("%w: %s:%d: schedules must be a list of schedule manifests", ErrInvalidConfig, path, list.Line)
 		}
 
-		var manifest ScheduleManifest
-		if err := jamle.Unmarshal(docBytes, &manifest); err != nil {
-			return nil, fmt.Errorf("%w: unmarshal document %d in %s: %v", ErrInvalidConfig, docIndex, path, err)
+		schedules := make([]Schedule, 0, len(list.Content))
+		for j, item := range list.Content {
+			sch, ok, err := parseScheduleDocument(item, path, fmt.Sprintf("schedules[%d] in %s", j, path))
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				schedules = append(schedules, sch)
+			}
 		}
+		return schedules, nil
+	}
+	return nil, nil
+}
 
-		schedules = append(schedules, manifest.ToSchedule())
+// parseScheduleDocument validates a schedule manifest node of the file at
+// path and converts it into a schedule. It reports false for an empty
+// document. where names the document in errors.
+func parseScheduleDocument(node *yaml.Node, path, where string) (Schedule, bool, error) {
+	schema, err := getScheduleSchema()
+	if err != nil {
+		return Schedule{}, false, err
 	}
 
-	return schedules, nil
+	var doc interface{}
+	if err := node.Decode(&doc); err != nil {
+		return Schedule{}, false, fmt.Errorf("%w: decode %s: %v", ErrInvalidConfig, where, err)
+	}
+	if doc == nil {
+		return Schedule{}, false, nil
+	}
+
+	// Crontabs are checked first to report the line of a bad expression.
+	if err := validateCrontabs(node, path); err != nil {
+		return Schedule{}, false, err
+	}
+
+	if err := schema.Validate(doc); err != nil {
+		return Schedule{}, false, fmt.Errorf("%w: %s: %v", ErrScheduleSchemaValidation, where, err)
+	}
+
+	docBytes, err := yaml.Marshal(doc)
+	if err != nil {
+		return Schedule{}, false, fmt.Errorf("%w: marshal %s: %v", ErrInvalidConfig, where, err)
+	}
+
+	var manifest ScheduleManifest
+	if err := jamle.Unmarshal(docBytes, &manifest); err != nil {
+		return Schedule{}, false, fmt.Errorf("%w: unmarshal %s: %v", ErrInvalidConfig, where, err)
+	}
+
+	if manifest.Spec.Type == BusinessHoursType && manifest.Spec.Start == manifest.Spec.End {
+		return Schedule{}, false, fmt.Errorf("%w: %s: business hours start and end must differ", ErrInvalidConfig, where)
+	}
+
+	if tz := manifest.Spec.Timezone.String(); tz != "" {
+		if !SupportsTimezone(manifest.Spec.Type) {
+			return Schedule{}, false, fmt.Errorf("%w: %s: %s schedules do not support timezone", ErrInvalidConfig, where, manifest.Spec.Type)
+		}
+		if _, err := time.LoadLocation(tz); err != nil {
+			return Schedule{}, false, fmt.Errorf("%w: %s: invalid timezone %q: %v", ErrInvalidConfig, where, tz, err)
+		}
+	}
+
+	return manifest.ToSchedule(), true, nil
+}
+
+// validateCrontabs checks every crontab value of a YAML document and reports
+// the file and line of the first invalid one. Free-form labels and
+// annotations are not checked.
+func validateCrontabs(node *yaml.Node, path string) error {
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			if err := validateCrontabs(child, path); err != nil {
+				return err
+			}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			switch key.Value {
+			case "labels", "annotations":
+				continue
+			case "crontab":
+				if value.Kind == yaml.ScalarNode {
+					if err := Crontab(value.Value).Validate(); err != nil {
+						return fmt.Errorf("%w: %s:%d: %v", ErrScheduleSchemaValidation, path, value.Line, err)
+					}
+					continue
+				}
+			}
+			if err := validateCrontabs(value, path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
@@ -1,11 +1,19 @@
 package config
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/source"
 )
 
 func TestLoadSchedulesFromDirAndMultiDoc(t *testing.T) {
@@ -65,7 +73,7 @@ spec:
 		t.Fatalf("Load() error = %v", err)
 	}
 
-	if cfg.SchedulesDir != schedulesDir {
+	if len(cfg.SchedulesDir) != 1 || cfg.SchedulesDir[0] != schedulesDir {
 		t.Fatalf("SchedulesDir = %q, want %q", cfg.SchedulesDir, schedulesDir)
 	}
 
@@ -130,6 +138,283 @@ spec:
 	}
 }
 
+func TestLoadSchedulesDirList(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	mustWriteFile(t, configPath, []byte(strings.TrimSpace(`
+timezone: Europe/Moscow
+validation_interval: 10m
+shutdown_timeout: 5m
+schedules_dir:
+  - ./team-a
+  - ./team-b
+`)))
+
+	manifest := func(name string) []byte {
+		return []byte(strings.TrimSpace(`
+apiVersion: scheduler.yc/v1alpha1
+kind: Schedule
+metadata:
+  name: ` + name + `
+spec:
+  type: daily
+  resource:
+    type: vm
+    id: fhm1234567890abcdef
+    folder_id: b1g1234567890abcdef
+  actions:
+    start:
+      enabled: true
+      time: 09:00
+`))
+	}
+	mustMkdirAll(t, filepath.Join(tmpDir, "team-a"))
+	mustMkdirAll(t, filepath.Join(tmpDir, "team-b"))
+	mustWriteFile(t, filepath.Join(tmpDir, "team-a", "vm.yaml"), manifest("team-a-vm"))
+	mustWriteFile(t, filepath.Join(tmpDir, "team-b", "vm.yaml"), manifest("team-b-vm"))
+
+	cfg, err := Load(context.Background(), configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := SchedulesDirs{filepath.Join(tmpDir, "team-a"), filepath.Join(tmpDir, "team-b")}
+	if !slices.Equal(cfg.SchedulesDir, want) {
+		t.Fatalf("SchedulesDir = %q, want %q", cfg.SchedulesDir, want)
+	}
+	if len(cfg.Schedules) != 2 {
+		t.Fatalf("len(Schedules) = %d, want 2", len(cfg.Schedules))
+	}
+
+	// Schedule names must be unique across directories.
+	mustWriteFile(t, filepath.Join(tmpDir, "team-b", "dup.yaml"), manifest("team-a-vm"))
+	_, err = LoadSchedules(context.Background(), false, cfg.SchedulesDir...)
+	if !errors.Is(err, ErrInvalidConfig) || !strings.Contains(err.Error(), filepath.Join("team-a", "vm.yaml")) {
+		t.Fatalf("LoadSchedules() error = %v, want a duplicate name across directories", err)
+	}
+}
+
+func TestLoadSchedulesRecursive(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	mustWriteFile(t, configPath, []byte(strings.TrimSpace(`
+timezone: Europe/Moscow
+validation_interval: 10m
+shutdown_timeout: 5m
+schedules_dir: ./schedules
+schedules_recursive: true
+`)))
+
+	manifest := func(name string) []byte {
+		return []byte(strings.TrimSpace(`
+apiVersion: scheduler.yc/v1alpha1
+kind: Schedule
+metadata:
+  name: ` + name + `
+spec:
+  type: daily
+  resource:
+    type: vm
+    id: fhm1234567890abcdef
+    folder_id: b1g1234567890abcdef
+  actions:
+    start:
+      enabled: true
+      time: 09:00
+`))
+	}
+	schedulesDir := filepath.Join(tmpDir, "schedules")
+	mustMkdirAll(t, filepath.Join(schedulesDir, "team-a", "dev"))
+	mustMkdirAll(t, filepath.Join(schedulesDir, "..2026_01_01"))
+	mustWriteFile(t, filepath.Join(schedulesDir, "root.yaml"), manifest("root-vm"))
+	mustWriteFile(t, filepath.Join(schedulesDir, "team-a", "dev", "vm.yaml"), manifest("team-a-dev-vm"))
+	// Hidden directories, e.g. of a mounted ConfigMap, are skipped.
+	mustWriteFile(t, filepath.Join(schedulesDir, "..2026_01_01", "root.yaml"), manifest("root-vm"))
+
+	cfg, err := Load(context.Background(), configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(cfg.Schedules) != 2 {
+		t.Fatalf("len(Schedules) = %d, want 2", len(cfg.Schedules))
+	}
+
+	schedules, err := LoadSchedules(context.Background(), false, schedulesDir)
+	if err != nil {
+		t.Fatalf("LoadSchedules() error = %v", err)
+	}
+	if len(schedules) != 1 || schedules[0].Name != "root-vm" {
+		t.Fatalf("LoadSchedules() without recursion = %v, want only root-vm", schedules)
+	}
+}
+
+func TestLoadInlineSchedules(t *testing.T) {
+	t.Parallel()
+
+	inline := `
+schedules:
+  - apiVersion: scheduler.yc/v1alpha1
+    kind: Schedule
+    metadata:
+      name: inline-vm
+    spec:
+      type: daily
+      resource:
+        type: vm
+        id: fhm1234567890abcdef
+        folder_id: b1g1234567890abcdef
+      actions:
+        stop:
+          enabled: true
+          time: 20:00
+`
+	dirManifest := func(name string) []byte {
+		return []byte(strings.TrimSpace(`
+apiVersion: scheduler.yc/v1alpha1
+kind: Schedule
+metadata:
+  name: ` + name + `
+spec:
+  type: daily
+  resource:
+    type: vm
+    id: fhm1234567890abcdef
+    folder_id: b1g1234567890abcdef
+  actions:
+    start:
+      enabled: true
+      time: 09:00
+`))
+	}
+	header := strings.TrimSpace(`
+timezone: Europe/Moscow
+validation_interval: 10m
+shutdown_timeout: 5m
+`) + "\n"
+
+	t.Run("only inline", func(t *testing.T) {
+		t.Parallel()
+
+		configPath := filepath.Join(t.TempDir(), "config.yaml")
+		mustWriteFile(t, configPath, []byte(header+inline))
+
+		cfg, err := Load(context.Background(), configPath)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if len(cfg.Schedules) != 1 || cfg.Schedules[0].Name != "inline-vm" {
+			t.Fatalf("Schedules = %+v, want inline-vm", cfg.Schedules)
+		}
+		reloaded, err := ReloadSchedules(context.Background(), cfg)
+		if err != nil {
+			t.Fatalf("ReloadSchedules() error = %v", err)
+		}
+		if len(reloaded) != 1 || reloaded[0].Name != "inline-vm" {
+			t.Fatalf("ReloadSchedules() = %+v, want inline-vm", reloaded)
+		}
+	})
+
+	t.Run("merged with directory", func(t *testing.T) {
+		t.Parallel()
+
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, "config.yaml")
+		mustMkdirAll(t, filepath.Join(tmpDir, "schedules"))
+		mustWriteFile(t, filepath.Join(tmpDir, "schedules", "vm.yaml"), dirManifest("dir-vm"))
+		mustWriteFile(t, configPath, []byte(header+"schedules_dir: ./schedules\n"+inline))
+
+		cfg, err := Load(context.Background(), configPath)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if len(cfg.Schedules) != 2 {
+			t.Fatalf("len(Schedules) = %d, want 2", len(cfg.Schedules))
+		}
+	})
+
+	t.Run("duplicate across sources", func(t *testing.T) {
+		t.Parallel()
+
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, "config.yaml")
+		mustMkdirAll(t, filepath.Join(tmpDir, "schedules"))
+		mustWriteFile(t, filepath.Join(tmpDir, "schedules", "vm.yaml"), dirManifest("inline-vm"))
+		mustWriteFile(t, configPath, []byte(header+"schedules_dir: ./schedules\n"+inline))
+
+		_, err := Load(context.Background(), configPath)
+		if !errors.Is(err, ErrInvalidConfig) || !strings.Contains(err.Error(), configPath) {
+			t.Fatalf("Load() error = %v, want a duplicate name in the config file", err)
+		}
+	})
+
+	t.Run("invalid inline schedule", func(t *testing.T) {
+		t.Parallel()
+
+		configPath := filepath.Join(t.TempDir(), "config.yaml")
+		mustWriteFile(t, configPath, []byte(header+strings.Replace(inline, "type: daily", "type: hourly", 1)))
+
+		_, err := Load(context.Background(), configPath)
+		if !errors.Is(err, ErrScheduleSchemaValidation) || !strings.Contains(err.Error(), "schedules[0]") {
+			t.Fatalf("Load() error = %v, want %v for schedules[0]", err, ErrScheduleSchemaValidation)
+		}
+	})
+
+	t.Run("no schedules", func(t *testing.T) {
+		t.Parallel()
+
+		configPath := filepath.Join(t.TempDir(), "config.yaml")
+		mustWriteFile(t, configPath, []byte(header))
+
+		if _, err := Load(context.Background(), configPath); !errors.Is(err, ErrInvalidConfig) {
+			t.Fatalf("Load() error = %v, want %v", err, ErrInvalidConfig)
+		}
+	})
+}
+
+func TestLoadFromURL(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	mux.HandleFunc("/config.yaml", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("timezone: UTC\nvalidation_interval: 10m\nshutdown_timeout: 5m\nschedules_url: " + srv.URL + "/schedules.yaml\n"))
+	})
+	mux.HandleFunc("/schedules.yaml", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("ETag", `"1"`)
+		_, _ = w.Write([]byte(strings.TrimSpace(`
+apiVersion: scheduler.yc/v1alpha1
+kind: Schedule
+metadata:
+  name: vm-stop
+spec:
+  type: cron
+  resource:
+    type: vm
+    id: fhm1234567890abcdef
+    folder_id: b1g1234567890abcdef
+  actions:
+    stop:
+      enabled: true
+      crontab: 0 18 * * *
+`)))
+	})
+
+	cfg, err := Load(context.Background(), srv.URL+"/config.yaml")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(cfg.ScheduleSources) != 1 || len(cfg.SchedulesDir) != 1 || cfg.SchedulesDir[0] != cfg.ScheduleSources[0].Dir() {
+		t.Fatalf("SchedulesDir = %q, want the directory of the schedules URL", cfg.SchedulesDir)
+	}
+	if len(cfg.Schedules) != 1 || cfg.Schedules[0].Name != "vm-stop" {
+		t.Fatalf("Schedules = %+v, want vm-stop", cfg.Schedules)
+	}
+}
+
 func TestLoadSchedulesDuplicateNames(t *testing.T) {
 	t.Parallel()
 
@@ -240,6 +525,529 @@ spec:
 	}
 }
 
+func TestLoadScheduleDescription(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	schedulesDir := filepath.Join(tmpDir, "schedules")
+
+	mustWriteFile(t, configPath, []byte(strings.TrimSpace(`
+timezone: Europe/Moscow
+validation_interval: 10m
+shutdown_timeout: 5m
+schedules_dir: ./schedules
+`)))
+	mustMkdirAll(t, schedulesDir)
+
+	mustWriteFile(t, filepath.Join(schedulesDir, "a.yaml"), []byte(strings.TrimSpace(`
+apiVersion: scheduler.yc/v1alpha1
+kind: Schedule
+metadata:
+  name: vm-start
+spec:
+  type: daily
+  description: Staging VM for nightly tests
+  runbook_url: https://wiki.example.com/runbooks/staging-vm
+  resource:
+    type: vm
+    id: fhm1234567890abcdef
+    folder_id: b1g1234567890abcdef
+  actions:
+    start:
+      enabled: true
+      time: 09:00
+`)))
+
+	cfg, err := Load(context.Background(), configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	sch := cfg.Schedules[0]
+	if sch.Description != "Staging VM for nightly tests" || sch.RunbookURL != "https://wiki.example.com/runbooks/staging-vm" {
+		t.Fatalf("Description, RunbookURL = %q, %q", sch.Description, sch.RunbookURL)
+	}
+}
+
+func TestLoadScheduleResourcesList(t *testing.T) {
+	t.Parallel()
+
+	schedulesDir := t.TempDir()
+	mustWriteFile(t, filepath.Join(schedulesDir, "group.yaml"), []byte(strings.TrimSpace(`
+apiVersion: scheduler.yc/v1alpha1
+kind: Schedule
+metadata:
+  name: dev-vms
+spec:
+  type: daily
+  max_parallel: 2
+  resources:
+    - type: vm
+      id: fhm1111111111111111
+      folder_id: b1g1234567890abcdef
+    - type: vm
+      id: fhm2222222222222222
+      folder_id: b1g1234567890abcdef
+  actions:
+    stop:
+      enabled: true
+      time: 20:00
+`)))
+
+	schedules, err := LoadSchedules(context.Background(), false, schedulesDir)
+	if err != nil {
+		t.Fatalf("LoadSchedules() error = %v", err)
+	}
+
+	targets := schedules[0].Targets()
+	if len(targets) != 2 || targets[1].ID != "fhm2222222222222222" {
+		t.Fatalf("Targets() = %+v, want both listed resources", targets)
+	}
+	if got := schedules[0].EffectiveMaxParallel(); got != 2 {
+		t.Fatalf("EffectiveMaxParallel() = %d, want 2", got)
+	}
+}
+
+func TestLoadScheduleSteps(t *testing.T) {
+	t.Parallel()
+
+	schedulesDir := t.TempDir()
+	mustWriteFile(t, filepath.Join(schedulesDir, "steps.yaml"), []byte(strings.TrimSpace(`
+apiVersion: scheduler.yc/v1alpha1
+kind: Schedule
+metadata:
+  name: morning-start
+spec:
+  type: daily
+  delay_between: 2m
+  steps:
+    - resources:
+        - type: vm
+          id: fhm1111111111111111
+          folder_id: b1g1234567890abcdef
+    - resources:
+        - type: k8s_cluster
+          id: cat2222222222222222
+          folder_id: b1g1234567890abcdef
+  actions:
+    start:
+      enabled: true
+      time: 08:00
+`)))
+
+	schedules, err := LoadSchedules(context.Background(), false, schedulesDir)
+	if err != nil {
+		t.Fatalf("LoadSchedules() error = %v", err)
+	}
+
+	sch := schedules[0]
+	if len(sch.Steps) != 2 || sch.DelayBetween.Duration != 2*time.Minute {
+		t.Fatalf("Steps = %+v, DelayBetween = %v, want 2 steps 2m apart", sch.Steps, sch.DelayBetween)
+	}
+	targets := sch.Targets()
+	if len(targets) != 2 || targets[1].ID != "cat2222222222222222" {
+		t.Fatalf("Targets() = %+v, want step resources in order", targets)
+	}
+}
+
+func TestLoadScheduleRejectsResourceAndResources(t *testing.T) {
+	t.Parallel()
+
+	schedulesDir := t.TempDir()
+	mustWriteFile(t, filepath.Join(schedulesDir, "both.yaml"), []byte(strings.TrimSpace(`
+apiVersion: scheduler.yc/v1alpha1
+kind: Schedule
+metadata:
+  name: both
+spec:
+  type: daily
+  resource:
+    type: vm
+    id: fhm1111111111111111
+    folder_id: b1g1234567890abcdef
+  resources:
+    - type: vm
+      id: fhm2222222222222222
+      folder_id: b1g1234567890abcdef
+  actions:
+    stop:
+      enabled: true
+      time: 20:00
+`)))
+
+	if _, err := LoadSchedules(context.Background(), false, schedulesDir); !errors.Is(err, ErrScheduleSchemaValidation) {
+		t.Fatalf("LoadSchedules() error = %v, want %v", err, ErrScheduleSchemaValidation)
+	}
+}
+
+func TestLoadScheduleNamePattern(t *testing.T) {
+	t.Parallel()
+
+	schedulesDir := t.TempDir()
+	mustWriteFile(t, filepath.Join(schedulesDir, "pattern.yaml"), []byte(strings.TrimSpace(`
+apiVersion: scheduler.yc/v1alpha1
+kind: Schedule
+metadata:
+  name: dev-by-name
+spec:
+  type: daily
+  resource:
+    type: vm
+    name_pattern: dev-*
+    max_matches: 3
+    folder_id: b1g1234567890abcdef
+  actions:
+    stop:
+      enabled: true
+      time: 20:00
+`)))
+
+	schedules, err := LoadSchedules(context.Background(), false, schedulesDir)
+	if err != nil {
+		t.Fatalf("LoadSchedules() error = %v", err)
+	}
+
+	resource := schedules[0].Resource
+	if !resource.IsPattern() || resource.Identifier() != "dev-*" {
+		t.Fatalf("Resource = %+v, want name pattern dev-*", resource)
+	}
+	if got := resource.EffectiveMaxMatches(); got != 3 {
+		t.Fatalf("EffectiveMaxMatches() = %d, want 3", got)
+	}
+}
+
+func TestLoadScheduleRejectsNamePatternForNonVM(t *testing.T) {
+	t.Parallel()
+
+	schedulesDir := t.TempDir()
+	mustWriteFile(t, filepath.Join(schedulesDir, "pattern.yaml"), []byte(strings.TrimSpace(`
+apiVersion: scheduler.yc/v1alpha1
+kind: Schedule
+metadata:
+  name: clusters-by-name
+spec:
+  type: daily
+  resource:
+    type: k8s_cluster
+    name_pattern: dev-*
+    folder_id: b1g1234567890abcdef
+  actions:
+    stop:
+      enabled: true
+      time: 20:00
+`)))
+
+	if _, err := LoadSchedules(context.Background(), false, schedulesDir); !errors.Is(err, ErrScheduleSchemaValidation) {
+		t.Fatalf("LoadSchedules() error = %v, want %v", err, ErrScheduleSchemaValidation)
+	}
+}
+
+func TestLoadScheduleScaleRequiresTargetSize(t *testing.T) {
+	t.Parallel()
+
+	schedulesDir := t.TempDir()
+	mustWriteFile(t, filepath.Join(schedulesDir, "scale.yaml"), []byte(strings.TrimSpace(`
+apiVersion: scheduler.yc/v1alpha1
+kind: Schedule
+metadata:
+  name: pool-scale
+spec:
+  type: daily
+  resource:
+    type: k8s_node_group
+    id: cat1234567890abcdef
+    folder_id: b1g1234567890abcdef
+  actions:
+    scale:
+      - enabled: true
+        time: 08:00
+        target_size: 5
+      - enabled: true
+        time: 20:00
+`)))
+
+	if _, err := LoadSchedules(context.Background(), false, schedulesDir); !errors.Is(err, ErrScheduleSchemaValidation) {
+		t.Fatalf("LoadSchedules() error = %v, want %v", err, ErrScheduleSchemaValidation)
+	}
+}
+
+func TestLoadScheduleStopMode(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		mode    string
+		wantErr bool
+	}{
+		{mode: "pause"},
+		{mode: "stop"},
+		{mode: "hibernate", wantErr: true},
+	} {
+		schedulesDir := t.TempDir()
+		mustWriteFile(t, filepath.Join(schedulesDir, "pause.yaml"), []byte(strings.TrimSpace(`
+apiVersion: scheduler.yc/v1alpha1
+kind: Schedule
+metadata:
+  name: workers-night
+spec:
+  type: daily
+  resource:
+    type: instance_group
+    id: cl11234567890abcdef
+    folder_id: b1g1234567890abcdef
+  actions:
+    stop:
+      enabled: true
+      time: 20:00
+      stop_mode: `+tt.mode+`
+`)))
+
+		schedules, err := LoadSchedules(context.Background(), false, schedulesDir)
+		if tt.wantErr {
+			if !errors.Is(err, ErrScheduleSchemaValidation) {
+				t.Fatalf("LoadSchedules() with stop_mode %s error = %v, want %v", tt.mode, err, ErrScheduleSchemaValidation)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("LoadSchedules() with stop_mode %s error = %v", tt.mode, err)
+		}
+		if got := schedules[0].Actions.Stop.StopMode; got != StopMode(tt.mode) {
+			t.Fatalf("StopMode = %q, want %q", got, tt.mode)
+		}
+	}
+}
+
+func TestLoadScheduleResizeRequiresSpec(t *testing.T) {
+	t.Parallel()
+
+	schedulesDir := t.TempDir()
+	mustWriteFile(t, filepath.Join(schedulesDir, "resize.yaml"), []byte(strings.TrimSpace(`
+apiVersion: scheduler.yc/v1alpha1
+kind: Schedule
+metadata:
+  name: staging-night
+spec:
+  type: daily
+  resource:
+    type: vm
+    id: fhm1234567890abcdef
+    folder_id: b1g1234567890abcdef
+  actions:
+    resize:
+      enabled: true
+      time: 21:00
+`)))
+
+	if _, err := LoadSchedules(context.Background(), false, schedulesDir); !errors.Is(err, ErrScheduleSchemaValidation) {
+		t.Fatalf("LoadSchedules() error = %v, want %v", err, ErrScheduleSchemaValidation)
+	}
+}
+
+func TestLoadScheduleBusinessHours(t *testing.T) {
+	t.Parallel()
+
+	schedulesDir := t.TempDir()
+	mustWriteFile(t, filepath.Join(schedulesDir, "office.yaml"), []byte(strings.TrimSpace(`
+apiVersion: scheduler.yc/v1alpha1
+kind: Schedule
+metadata:
+  name: office
+spec:
+  type: business_hours
+  start: "09:00"
+  end: "19:00"
+  resource:
+    type: vm
+    id: fhm1234567890abcdef
+    folder_id: b1g1234567890abcdef
+---
+apiVersion: scheduler.yc/v1alpha1
+kind: Schedule
+metadata:
+  name: night-batch
+spec:
+  type: business_hours
+  start: "22:00"
+  end: "06:00"
+  days: [5, 6]
+  resource:
+    type: vm
+    id: fhm0987654321abcdef
+    folder_id: b1g1234567890abcdef
+`)))
+
+	schedules, err := LoadSchedules(context.Background(), false, schedulesDir)
+	if err != nil {
+		t.Fatalf("LoadSchedules() error = %v", err)
+	}
+
+	office := schedules[0]
+	if office.Type != "weekly" || office.BusinessHours == nil {
+		t.Fatalf("office = %+v, want a weekly schedule with business hours", office)
+	}
+	start, stop := office.Actions.Start, office.Actions.Stop
+	if !start.Enabled || start.Time != "09:00" || !slices.Equal(start.Days, []int{1, 2, 3, 4, 5}) {
+		t.Fatalf("office start = %+v, want 09:00 Monday to Friday", start)
+	}
+	if !stop.Enabled || stop.Time != "19:00" || !slices.Equal(stop.Days, []int{1, 2, 3, 4, 5}) {
+		t.Fatalf("office stop = %+v, want 19:00 Monday to Friday", stop)
+	}
+
+	// The overnight window stops on the following days.
+	if got := schedules[1].Actions.Stop.Days; !slices.Equal(got, []int{6, 0}) {
+		t.Fatalf("night-batch stop days = %v, want [6 0]", got)
+	}
+}
+
+func TestLoadScheduleBusinessHoursRequiresWindow(t *testing.T) {
+	t.Parallel()
+
+	schedulesDir := t.TempDir()
+	mustWriteFile(t, filepath.Join(schedulesDir, "office.yaml"), []byte(strings.TrimSpace(`
+apiVersion: scheduler.yc/v1alpha1
+kind: Schedule
+metadata:
+  name: office
+spec:
+  type: business_hours
+  start: "09:00"
+  resource:
+    type: vm
+    id: fhm1234567890abcdef
+    folder_id: b1g1234567890abcdef
+`)))
+
+	if _, err := LoadSchedules(context.Background(), false, schedulesDir); !errors.Is(err, ErrScheduleSchemaValidation) {
+		t.Fatalf("LoadSchedules() error = %v, want %v", err, ErrScheduleSchemaValidation)
+	}
+}
+
+func TestLoadScheduleRejectsInvalidCrontab(t *testing.T) {
+	t.Parallel()
+
+	schedulesDir := t.TempDir()
+	path := filepath.Join(schedulesDir, "nightly.yaml")
+	mustWriteFile(t, path, []byte(strings.TrimSpace(`
+apiVersion: scheduler.yc/v1alpha1
+kind: Schedule
+metadata:
+  name: nightly
+  labels:
+    crontab: not-a-cron
+spec:
+  type: cron
+  resource:
+    type: vm
+    id: fhm1234567890abcdef
+    folder_id: b1g1234567890abcdef
+  actions:
+    stop:
+      enabled: true
+      crontab: "0 25 * * *"
+`)))
+
+	_, err := LoadSchedules(context.Background(), false, schedulesDir)
+	if !errors.Is(err, ErrScheduleSchemaValidation) {
+		t.Fatalf("LoadSchedules() error = %v, want %v", err, ErrScheduleSchemaValidation)
+	}
+	if !strings.Contains(err.Error(), path+":16:") {
+		t.Fatalf("LoadSchedules() error = %v, want the file and line of the crontab", err)
+	}
+}
+
+func TestLoadScheduleTimezone(t *testing.T) {
+	t.Parallel()
+
+	manifest := func(scheduleType, timezone, action string) string {
+		return strings.TrimSpace(`
+apiVersion: scheduler.yc/v1alpha1
+kind: Schedule
+metadata:
+  name: vm-start
+spec:
+  type: ` + scheduleType + `
+  timezone: ` + timezone + `
+  resource:
+    type: vm
+    id: fhm1234567890abcdef
+    folder_id: b1g1234567890abcdef
+  actions:
+    start:
+      enabled: true
+      ` + action + `
+`)
+	}
+
+	tests := []struct {
+		name     string
+		manifest string
+		wantErr  bool
+	}{
+		{name: "daily", manifest: manifest("daily", "Asia/Yekaterinburg", "time: 09:00")},
+		{name: "unknown timezone", manifest: manifest("daily", "Mars/Olympus", "time: 09:00"), wantErr: true},
+		{name: "duration", manifest: manifest("duration", "Asia/Yekaterinburg", "every: 1h"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			schedulesDir := t.TempDir()
+			mustWriteFile(t, filepath.Join(schedulesDir, "vm.yaml"), []byte(tt.manifest))
+
+			schedules, err := LoadSchedules(context.Background(), false, schedulesDir)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidConfig) {
+					t.Fatalf("LoadSchedules() error = %v, want %v", err, ErrInvalidConfig)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadSchedules() error = %v", err)
+			}
+			if schedules[0].Timezone != "Asia/Yekaterinburg" {
+				t.Fatalf("Timezone = %q, want Asia/Yekaterinburg", schedules[0].Timezone)
+			}
+		})
+	}
+}
+
+func TestScheduleDeprecations(t *testing.T) {
+	t.Parallel()
+
+	schedulesDir := t.TempDir()
+	mustWriteFile(t, filepath.Join(schedulesDir, "legacy.yaml"), []byte(strings.TrimSpace(`
+apiVersion: scheduler.yc/v1alpha1
+kind: Schedule
+metadata:
+  name: legacy-vm
+spec:
+  type: daily
+  daily_job:
+    time: "20:00"
+  resource:
+    type: vm
+    id: fhm1234567890abcdef
+    folder_id: b1g1234567890abcdef
+  actions:
+    stop:
+      enabled: true
+      time: 20:00
+`)))
+
+	schedules, err := LoadSchedules(context.Background(), false, schedulesDir)
+	if err != nil {
+		t.Fatalf("LoadSchedules() error = %v", err)
+	}
+
+	deprecations := ScheduleDeprecations(schedules)
+	if len(deprecations) != 1 || deprecations[0].Feature != DeprecatedDailyJob || deprecations[0].Sources[0] != "legacy-vm" {
+		t.Fatalf("ScheduleDeprecations() = %+v, want daily_job used by legacy-vm", deprecations)
+	}
+}
+
 func mustWriteFile(t *testing.T, path string, data []byte) {
 	t.Helper()
 	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
@@ -253,3 +1061,133 @@ func mustMkdirAll(t *testing.T, path string) {
 		t.Fatalf("mkdir %s: %v", path, err)
 	}
 }
+
+func TestLoadBlackoutWindows(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		windows string
+		wantErr bool
+	}{
+		{
+			name: "weekday time range",
+			windows: `
+  - name: release-night
+    weekdays: [4]
+    start_time: "22:00"
+    end_time: "02:00"`,
+		},
+		{
+			name: "absolute range",
+			windows: `
+  - from: 2026-12-31T18:00:00+03:00
+    until: 2027-01-01T06:00:00+03:00`,
+		},
+		{
+			name: "no restriction",
+			windows: `
+  - name: always`,
+			wantErr: true,
+		},
+		{
+			name: "start time without end time",
+			windows: `
+  - dates: [2026-12-31]
+    start_time: "22:00"`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			configPath := filepath.Join(tmpDir, "config.yaml")
+			schedulesDir := filepath.Join(tmpDir, "schedules")
+			mustMkdirAll(t, schedulesDir)
+			mustWriteFile(t, filepath.Join(schedulesDir, "vm.yaml"), []byte(strings.TrimSpace(`
+apiVersion: scheduler.yc/v1alpha1
+kind: Schedule
+metadata:
+  name: vm-stop
+spec:
+  type: daily
+  resource:
+    type: vm
+    id: fhm1234567890abcdef
+    folder_id: b1g1234567890abcdef
+  actions:
+    stop:
+      enabled: true
+      time: 20:00
+`)))
+			mustWriteFile(t, configPath, []byte(strings.TrimSpace(`
+timezone: Europe/Moscow
+validation_interval: 10m
+shutdown_timeout: 5m
+schedules_dir: ./schedules
+blackout_windows:`)+tt.windows+"\n"))
+
+			_, err := Load(context.Background(), configPath)
+			if tt.wantErr {
+				if !errors.Is(err, ErrSchemaValidation) {
+					t.Fatalf("Load() error = %v, want %v", err, ErrSchemaValidation)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+		})
+	}
+}
+
+// dirSource is a schedules source already mirrored to a local directory.
+type dirSource string
+
+func (s dirSource) Dir() string                { return string(s) }
+func (s dirSource) Sync(context.Context) error { return nil }
+
+func TestReloadSchedulesRejectsRemoteCommandHooks(t *testing.T) {
+	t.Parallel()
+
+	manifest := []byte(strings.TrimSpace(`
+apiVersion: scheduler.yc/v1alpha1
+kind: Schedule
+metadata:
+  name: drained-vm
+spec:
+  type: daily
+  resource:
+    type: vm
+    id: fhm1234567890abcdef
+    folder_id: b1g1234567890abcdef
+  actions:
+    stop:
+      enabled: true
+      time: 20:00
+      pre_hook:
+        command: ["/usr/local/bin/drain"]
+`))
+	localDir := t.TempDir()
+	remoteDir := t.TempDir()
+	mustWriteFile(t, filepath.Join(localDir, "local.yaml"), manifest)
+	mustWriteFile(t, filepath.Join(remoteDir, "remote.yaml"), bytes.ReplaceAll(manifest, []byte("drained-vm"), []byte("remote-vm")))
+
+	local := &Config{SchedulesDir: []string{localDir}}
+	if _, err := ReloadSchedules(context.Background(), local); err != nil {
+		t.Fatalf("ReloadSchedules() of a local schedules directory error = %v", err)
+	}
+
+	remote := &Config{SchedulesDir: []string{localDir, remoteDir}, ScheduleSources: []source.Source{dirSource(remoteDir)}}
+	if _, err := ReloadSchedules(context.Background(), remote); !errors.Is(err, ErrInvalidConfig) || !strings.Contains(err.Error(), "allow_remote_command_hooks") {
+		t.Fatalf("ReloadSchedules() error = %v, want command hooks of the remote source rejected", err)
+	}
+
+	remote.AllowRemoteCommandHooks = true
+	if schedules, err := ReloadSchedules(context.Background(), remote); err != nil || len(schedules) != 2 {
+		t.Fatalf("ReloadSchedules() = %d schedules, %v; want both with allow_remote_command_hooks", len(schedules), err)
+	}
+}
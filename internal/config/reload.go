@@ -0,0 +1,69 @@
+package config
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// SettingsChange lists the top-level settings, by their names in the config
+// file, that differ in a reloaded config file.
+type SettingsChange struct {
+	// Applied lists the changed settings that take effect without a restart.
+	Applied []string
+	// Restart lists the changed settings that take effect after a restart.
+	Restart []string
+}
+
+// Empty reports whether no settings changed.
+func (c SettingsChange) Empty() bool {
+	return len(c.Applied) == 0 && len(c.Restart) == 0
+}
+
+// Has reports whether the named setting changed and takes effect without a
+// restart.
+func (c SettingsChange) Has(names ...string) bool {
+	for _, name := range names {
+		if slices.Contains(c.Applied, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithSettings returns a copy of c with the settings of next, a config file
+// read again by LoadSettings, along with the settings that changed since c was
+// loaded. Settings tagged reload:"restart" keep the values of c. Loaded
+// schedules, schedules directories and sources are kept as well.
+func (c *Config) WithSettings(next *Config) (*Config, SettingsChange) {
+	previous := c.settings
+	if previous == nil {
+		previous = c
+	}
+
+	updated := *c
+	updated.settings = next
+
+	var change SettingsChange
+	dst := reflect.ValueOf(&updated).Elem()
+	old := reflect.ValueOf(previous).Elem()
+	cur := reflect.ValueOf(next).Elem()
+	t := dst.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		if reflect.DeepEqual(old.Field(i).Interface(), cur.Field(i).Interface()) {
+			continue
+		}
+		if field.Tag.Get("reload") == "restart" {
+			change.Restart = append(change.Restart, name)
+			continue
+		}
+		change.Applied = append(change.Applied, name)
+		dst.Field(i).Set(cur.Field(i))
+	}
+	return &updated, change
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestConfigWithSettings(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	schedulesDir := filepath.Join(tmpDir, "schedules")
+	mustMkdirAll(t, schedulesDir)
+	mustWriteFile(t, filepath.Join(schedulesDir, "vm.yaml"), []byte(strings.TrimSpace(`
+apiVersion: scheduler.yc/v1alpha1
+kind: Schedule
+metadata:
+  name: vm-start
+spec:
+  type: daily
+  resource:
+    type: vm
+    id: fhm1234567890abcdef
+    folder_id: b1g1234567890abcdef
+  actions:
+    start:
+      enabled: true
+      time: 09:00
+`)))
+
+	const settings = `
+validation_interval: 10m
+shutdown_timeout: 5m
+schedules_dir: ./schedules
+`
+	mustWriteFile(t, configPath, []byte("timezone: UTC\nmetrics_port: 9090\n"+settings))
+	cfg, err := Load(context.Background(), configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	mustWriteFile(t, configPath, []byte("timezone: Europe/Moscow\nmax_concurrent_jobs: 2\nmetrics_port: 9191\n"+settings))
+	next, err := LoadSettings(context.Background(), configPath)
+	if err != nil {
+		t.Fatalf("LoadSettings() error = %v", err)
+	}
+
+	updated, change := cfg.WithSettings(next)
+	if want := []string{"timezone", "max_concurrent_jobs"}; !slices.Equal(change.Applied, want) {
+		t.Errorf("Applied = %v, want %v", change.Applied, want)
+	}
+	if want := []string{"metrics_port"}; !slices.Equal(change.Restart, want) {
+		t.Errorf("Restart = %v, want %v", change.Restart, want)
+	}
+	if updated.Timezone != "Europe/Moscow" || updated.MaxConcurrentJobs != 2 {
+		t.Errorf("updated timezone = %q, max_concurrent_jobs = %d", updated.Timezone, updated.MaxConcurrentJobs)
+	}
+	if updated.MetricsPort != 9090 {
+		t.Errorf("updated metrics_port = %d, want 9090 until a restart", updated.MetricsPort)
+	}
+	if !slices.Equal(updated.SchedulesDir, cfg.SchedulesDir) || len(updated.Schedules) != 1 {
+		t.Errorf("updated schedules_dir = %v with %d schedules, want the loaded ones", updated.SchedulesDir, len(updated.Schedules))
+	}
+	if cfg.Timezone != "UTC" {
+		t.Errorf("original timezone = %q, want it unchanged", cfg.Timezone)
+	}
+
+	// Changes are reported once.
+	if _, again := updated.WithSettings(next); !again.Empty() {
+		t.Errorf("second reload change = %+v, want none", again)
+	}
+}
+
+func TestLoadSettingsRejectsInvalidInlineSchedules(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	mustWriteFile(t, configPath, []byte(strings.TrimSpace(`
+validation_interval: 10m
+shutdown_timeout: 5m
+schedules:
+  - apiVersion: scheduler.yc/v1alpha1
+    kind: Schedule
+    metadata:
+      name: broken
+    spec:
+      type: daily
+`)))
+
+	if _, err := LoadSettings(context.Background(), configPath); err == nil {
+		t.Fatal("LoadSettings() succeeded, want an error for the invalid inline schedule")
+	}
+}
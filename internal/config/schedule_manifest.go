@@ -1,7 +1,29 @@
 package config
 
+import (
+	"slices"
+	"time"
+
+	"github.com/invopop/jsonschema"
+)
+
 const displayNameAnnotation = "yc-scheduler/display-name"
 
+// defaultMaxParallel is the number of schedule resources processed
+// concurrently when MaxParallel is not set.
+const defaultMaxParallel = 5
+
+// defaultMaxMatches is the number of instances a name pattern may resolve
+// to when MaxMatches is not set.
+const defaultMaxMatches = 10
+
+// BusinessHoursType is the schedule type expanded into weekly start and stop
+// actions bounding a daily window.
+const BusinessHoursType = "business_hours"
+
+// defaultBusinessDays are the days of business_hours schedules without days.
+var defaultBusinessDays = []int{1, 2, 3, 4, 5}
+
 // ToSchedule converts a manifest document into runtime schedule configuration.
 func (m ScheduleManifest) ToSchedule() Schedule {
 	displayName := m.Metadata.Name
@@ -9,15 +31,248 @@ func (m ScheduleManifest) ToSchedule() Schedule {
 		displayName = value
 	}
 
-	return Schedule{
-		Name:        m.Metadata.Name,
-		DisplayName: displayName,
-		Type:        m.Spec.Type,
-		Actions:     m.Spec.Actions,
-		CronJob:     m.Spec.CronJob,
-		DailyJob:    m.Spec.DailyJob,
-		WeeklyJob:   m.Spec.WeeklyJob,
-		MonthlyJob:  m.Spec.MonthlyJob,
-		Resource:    m.Spec.Resource,
+	schedule := Schedule{
+		Name:         m.Metadata.Name,
+		DisplayName:  displayName,
+		Namespace:    m.Metadata.Namespace,
+		Labels:       m.Metadata.Labels,
+		Annotations:  m.Metadata.Annotations,
+		Type:         m.Spec.Type,
+		Actions:      m.Spec.Actions,
+		CronJob:      m.Spec.CronJob,
+		DailyJob:     m.Spec.DailyJob,
+		WeeklyJob:    m.Spec.WeeklyJob,
+		MonthlyJob:   m.Spec.MonthlyJob,
+		Resources:    m.Spec.Resources,
+		Steps:        m.Spec.Steps,
+		DelayBetween: m.Spec.DelayBetween,
+		Jitter:       m.Spec.Jitter,
+		Timezone:     m.Spec.Timezone,
+		MaxParallel:  m.Spec.MaxParallel,
+		DependsOn:    m.Spec.DependsOn,
+		ActiveFrom:   m.Spec.ActiveFrom,
+		ActiveUntil:  m.Spec.ActiveUntil,
+
+		DeniedResourceIDs: m.Spec.DeniedResourceIDs,
+		Description:       m.Spec.Description,
+		RunbookURL:        m.Spec.RunbookURL,
+	}
+	if m.Spec.Resource != nil {
+		schedule.Resource = *m.Spec.Resource
+	}
+	if m.Spec.Type == BusinessHoursType {
+		expandBusinessHours(&schedule, m.Spec)
+	}
+
+	return schedule
+}
+
+// expandBusinessHours turns a business_hours schedule into a weekly one that
+// starts resources at the window start and stops them at its end. Settings of
+// explicitly configured start and stop actions are kept.
+func expandBusinessHours(schedule *Schedule, spec ScheduleManifestSpec) {
+	days := spec.Days
+	if len(days) == 0 {
+		days = defaultBusinessDays
+	}
+	schedule.BusinessHours = &BusinessHours{Start: spec.Start, End: spec.End, Days: slices.Clone(days)}
+
+	// A window spanning midnight stops on the next day. Times are validated
+	// as zero-padded HH:MM[:SS], so they compare as strings.
+	stopDays := slices.Clone(days)
+	if spec.End < spec.Start {
+		for i, day := range stopDays {
+			stopDays[i] = (day + 1) % 7
+		}
+	}
+
+	start, stop := ActionConfig{}, ActionConfig{}
+	if spec.Actions.Start != nil {
+		start = *spec.Actions.Start
+	}
+	if spec.Actions.Stop != nil {
+		stop = *spec.Actions.Stop
+	}
+	start.Enabled, start.Time, start.Days = true, spec.Start.String(), slices.Clone(days)
+	stop.Enabled, stop.Time, stop.Days = true, spec.End.String(), stopDays
+
+	schedule.Type = "weekly"
+	schedule.Actions.Start = &start
+	schedule.Actions.Stop = &stop
+}
+
+// JSONSchemaExtend requires exactly one of resource, resources and steps,
+// start and end for business_hours schedules and actions for other types.
+func (ScheduleManifestSpec) JSONSchemaExtend(schema *jsonschema.Schema) {
+	schema.OneOf = []*jsonschema.Schema{
+		{Required: []string{"resource"}},
+		{Required: []string{"resources"}},
+		{Required: []string{"steps"}},
+	}
+	businessHours := jsonschema.NewProperties()
+	businessHours.Set("type", &jsonschema.Schema{Const: BusinessHoursType})
+	schema.If = &jsonschema.Schema{Properties: businessHours}
+	schema.Then = &jsonschema.Schema{Required: []string{"start", "end"}}
+	schema.Else = &jsonschema.Schema{Required: []string{"actions"}}
+}
+
+// JSONSchemaExtend requires target_size on scale action entries, preemptible
+// on preemptible action entries and at least one resources field on the
+// resize action.
+func (Actions) JSONSchemaExtend(schema *jsonschema.Schema) {
+	// Draft-07 ignores keywords next to $ref, so the item reference is
+	// combined with the requirement via allOf.
+	if scale, ok := schema.Properties.Get("scale"); ok && scale.Items != nil {
+		scale.Items = &jsonschema.Schema{AllOf: []*jsonschema.Schema{
+			scale.Items,
+			{Required: []string{"target_size"}},
+		}}
+	}
+	if preemptible, ok := schema.Properties.Get("preemptible"); ok && preemptible.Items != nil {
+		preemptible.Items = &jsonschema.Schema{AllOf: []*jsonschema.Schema{
+			preemptible.Items,
+			{Required: []string{"preemptible"}},
+		}}
+	}
+	if resize, ok := schema.Properties.Get("resize"); ok {
+		schema.Properties.Set("resize", &jsonschema.Schema{AllOf: []*jsonschema.Schema{
+			resize,
+			{AnyOf: []*jsonschema.Schema{
+				{Required: []string{"platform_id"}},
+				{Required: []string{"cores"}},
+				{Required: []string{"core_fraction"}},
+				{Required: []string{"memory_gb"}},
+			}},
+		}})
+	}
+}
+
+// JSONSchemaExtend requires at least one restriction of the window and
+// start_time together with end_time.
+func (BlackoutWindowConfig) JSONSchemaExtend(schema *jsonschema.Schema) {
+	schema.AnyOf = []*jsonschema.Schema{
+		{Required: []string{"from"}},
+		{Required: []string{"until"}},
+		{Required: []string{"dates"}},
+		{Required: []string{"weekdays"}},
+		{Required: []string{"start_time"}},
+	}
+	schema.AllOf = []*jsonschema.Schema{
+		{If: &jsonschema.Schema{Required: []string{"start_time"}}, Then: &jsonschema.Schema{Required: []string{"end_time"}}},
+		{If: &jsonschema.Schema{Required: []string{"end_time"}}, Then: &jsonschema.Schema{Required: []string{"start_time"}}},
+	}
+}
+
+// JSONSchemaExtend requires exactly one of url and command.
+func (HookConfig) JSONSchemaExtend(schema *jsonschema.Schema) {
+	schema.OneOf = []*jsonschema.Schema{
+		{Required: []string{"url"}},
+		{Required: []string{"command"}},
+	}
+}
+
+// JSONSchemaExtend requires exactly one of id and name_pattern and limits
+// name patterns to VMs.
+func (Resource) JSONSchemaExtend(schema *jsonschema.Schema) {
+	vmOnly := jsonschema.NewProperties()
+	vmOnly.Set("type", &jsonschema.Schema{Const: "vm"})
+	schema.OneOf = []*jsonschema.Schema{
+		{Required: []string{"id"}},
+		{Required: []string{"name_pattern"}, Properties: vmOnly},
+	}
+}
+
+// IsPattern reports whether the resource is selected by name pattern.
+func (r Resource) IsPattern() bool {
+	return r.NamePattern != ""
+}
+
+// Identifier returns the resource ID or, for pattern resources, the name pattern.
+func (r Resource) Identifier() string {
+	if r.IsPattern() {
+		return r.NamePattern
+	}
+	return r.ID
+}
+
+// EffectiveMaxMatches returns the configured name pattern match limit.
+func (r Resource) EffectiveMaxMatches() int {
+	if r.MaxMatches > 0 {
+		return r.MaxMatches
+	}
+	return defaultMaxMatches
+}
+
+// Targets returns the resources managed by the schedule. Resources of steps
+// are returned in step order.
+func (s Schedule) Targets() []Resource {
+	if len(s.Steps) > 0 {
+		var targets []Resource
+		for _, step := range s.Steps {
+			targets = append(targets, step.Resources...)
+		}
+		return targets
+	}
+	if len(s.Resources) > 0 {
+		return s.Resources
+	}
+	return []Resource{s.Resource}
+}
+
+// ForResource returns a copy of the schedule narrowed to a single resource.
+func (s Schedule) ForResource(resource Resource) Schedule {
+	s.Resource = resource
+	s.Resources = nil
+	s.Steps = nil
+	return s
+}
+
+// ForScaleEntry returns a copy of the schedule whose scale action is narrowed
+// to the entry with index i.
+func (s Schedule) ForScaleEntry(i int) Schedule {
+	s.Actions.Scale = s.Actions.Scale[i : i+1]
+	return s
+}
+
+// ForPreemptibleEntry returns a copy of the schedule whose preemptible action
+// is narrowed to the entry with index i.
+func (s Schedule) ForPreemptibleEntry(i int) Schedule {
+	s.Actions.Preemptible = s.Actions.Preemptible[i : i+1]
+	return s
+}
+
+// WeekDays returns the days of the week a weekly action runs on: Days if set,
+// otherwise Day.
+func (a *ActionConfig) WeekDays() []int {
+	if len(a.Days) > 0 {
+		return a.Days
+	}
+	return []int{a.Day}
+}
+
+// ActivePeriod returns the period the schedule takes effect in. A zero bound
+// leaves the period open.
+func (s Schedule) ActivePeriod() (from, until time.Time) {
+	// Bounds are validated as RFC3339 when schedules are loaded.
+	if s.ActiveFrom != "" {
+		from, _ = s.ActiveFrom.Time()
+	}
+	if s.ActiveUntil != "" {
+		until, _ = s.ActiveUntil.Time()
+	}
+	return from, until
+}
+
+// ActiveAt reports whether t is within the schedule active period.
+func (s Schedule) ActiveAt(t time.Time) bool {
+	from, until := s.ActivePeriod()
+	return !t.Before(from) && (until.IsZero() || t.Before(until))
+}
+
+// EffectiveMaxParallel returns the configured resource concurrency limit.
+func (s Schedule) EffectiveMaxParallel() int {
+	if s.MaxParallel > 0 {
+		return s.MaxParallel
 	}
+	return defaultMaxParallel
 }
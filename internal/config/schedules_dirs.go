@@ -0,0 +1,73 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/invopop/jsonschema"
+)
+
+// SchedulesDirs lists directories with schedule manifests. It is configured
+// as a single path or a list of paths.
+type SchedulesDirs []string
+
+// UnmarshalYAML implements yaml.Unmarshaler interface.
+func (d *SchedulesDirs) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var single string
+	if err := unmarshal(&single); err == nil {
+		*d = SchedulesDirs{single}
+		return nil
+	}
+	var list []string
+	if err := unmarshal(&list); err != nil {
+		return fmt.Errorf("schedules_dir must be a path or a list of paths: %w", err)
+	}
+	*d = list
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler interface.
+func (d SchedulesDirs) MarshalYAML() (interface{}, error) {
+	if len(d) == 1 {
+		return d[0], nil
+	}
+	return []string(d), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (d *SchedulesDirs) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*d = SchedulesDirs{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("schedules_dir must be a path or a list of paths: %w", err)
+	}
+	*d = list
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler interface. A single directory is
+// encoded as a string.
+func (d SchedulesDirs) MarshalJSON() ([]byte, error) {
+	if len(d) == 1 {
+		return json.Marshal(d[0])
+	}
+	return json.Marshal([]string(d))
+}
+
+// JSONSchema returns the JSON schema for SchedulesDirs type.
+func (SchedulesDirs) JSONSchema() *jsonschema.Schema {
+	minLen := uint64(1)
+	path := &jsonschema.Schema{Type: "string", MinLength: &minLen}
+	return &jsonschema.Schema{
+		Description: "Directory or list of directories with schedule manifests",
+		OneOf: []*jsonschema.Schema{
+			path,
+			{Type: "array", Items: path, MinItems: &minLen, UniqueItems: true},
+		},
+		Examples: []any{"./schedules", []string{"/etc/yc-scheduler/team-a", "/etc/yc-scheduler/team-b"}},
+	}
+}
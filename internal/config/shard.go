@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// Sharded reports whether schedules are split between several instances.
+func (c *Config) Sharded() bool {
+	return c.ShardCount > 1
+}
+
+// validateShard checks that the shard index is within the shard count.
+func validateShard(cfg *Config) error {
+	if cfg.Sharded() && cfg.ShardIndex >= cfg.ShardCount {
+		return fmt.Errorf("%w: shard_index %d must be less than shard_count %d", ErrInvalidConfig, cfg.ShardIndex, cfg.ShardCount)
+	}
+	return nil
+}
+
+// OwnedSchedules returns the schedules owned by the shard of the instance, or
+// all schedules without sharding. A schedule belongs to the shard of the hash
+// of the smallest name among the schedules it is linked with by depends_on in
+// either direction, so dependent schedules always run on one instance.
+func (c *Config) OwnedSchedules(schedules []Schedule) []Schedule {
+	if !c.Sharded() {
+		return schedules
+	}
+
+	groups := scheduleGroups(schedules)
+	owned := make([]Schedule, 0, len(schedules)/c.ShardCount+1)
+	for _, sch := range schedules {
+		if shardOf(groups[sch.Name], c.ShardCount) == c.ShardIndex {
+			owned = append(owned, sch)
+		}
+	}
+	return owned
+}
+
+// shardOf returns the shard of key among count shards.
+func shardOf(key string, count int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(count))
+}
+
+// scheduleGroups returns the smallest schedule name of the depends_on group
+// of every schedule by its name.
+func scheduleGroups(schedules []Schedule) map[string]string {
+	parent := make(map[string]string, len(schedules))
+	var find func(name string) string
+	find = func(name string) string {
+		p, ok := parent[name]
+		if !ok || p == name {
+			parent[name] = name
+			return name
+		}
+		root := find(p)
+		parent[name] = root
+		return root
+	}
+	union := func(a, b string) {
+		ra, rb := find(a), find(b)
+		switch {
+		case ra < rb:
+			parent[rb] = ra
+		case rb < ra:
+			parent[ra] = rb
+		}
+	}
+
+	for _, sch := range schedules {
+		find(sch.Name)
+		for _, dep := range sch.DependsOn {
+			union(sch.Name, dep)
+		}
+	}
+
+	groups := make(map[string]string, len(schedules))
+	for _, sch := range schedules {
+		groups[sch.Name] = find(sch.Name)
+	}
+	return groups
+}
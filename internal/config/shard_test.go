@@ -0,0 +1,58 @@
+package config
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestOwnedSchedules(t *testing.T) {
+	t.Parallel()
+
+	var schedules []Schedule
+	for i := range 50 {
+		schedules = append(schedules, Schedule{Name: "vm-" + strconv.Itoa(i)})
+	}
+	schedules = append(schedules,
+		Schedule{Name: "app", DependsOn: []string{"db"}},
+		Schedule{Name: "db"},
+		Schedule{Name: "worker", DependsOn: []string{"app"}},
+	)
+
+	const count = 3
+	owners := make(map[string]int)
+	for index := range count {
+		cfg := &Config{ShardIndex: index, ShardCount: count}
+		owned := cfg.OwnedSchedules(schedules)
+		if len(owned) == 0 {
+			t.Fatalf("shard %d owns no schedules", index)
+		}
+		for _, sch := range owned {
+			if prev, ok := owners[sch.Name]; ok {
+				t.Fatalf("schedule %q is owned by shards %d and %d", sch.Name, prev, index)
+			}
+			owners[sch.Name] = index
+		}
+	}
+	if len(owners) != len(schedules) {
+		t.Fatalf("shards own %d schedules, want %d", len(owners), len(schedules))
+	}
+	if owners["app"] != owners["db"] || owners["worker"] != owners["db"] {
+		t.Fatalf("dependent schedules are owned by shards %d, %d and %d, want one shard", owners["app"], owners["db"], owners["worker"])
+	}
+
+	if got := (&Config{}).OwnedSchedules(schedules); len(got) != len(schedules) {
+		t.Fatalf("OwnedSchedules() without sharding = %d schedules, want all %d", len(got), len(schedules))
+	}
+}
+
+func TestValidateShard(t *testing.T) {
+	t.Parallel()
+
+	if err := validateShard(&Config{ShardIndex: 2, ShardCount: 3}); err != nil {
+		t.Fatalf("validateShard() error = %v", err)
+	}
+	if err := validateShard(&Config{ShardIndex: 3, ShardCount: 3}); !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("validateShard() error = %v, want ErrInvalidConfig", err)
+	}
+}
@@ -0,0 +1,24 @@
+package config
+
+import "github.com/invopop/jsonschema"
+
+// StateSource names where the validator takes the expected resource state from.
+type StateSource string
+
+const (
+	// StateSourceSchedule derives the expected state from schedule actions.
+	StateSourceSchedule StateSource = "schedule"
+
+	// StateSourceLabel takes the expected state from a resource label hint.
+	StateSourceLabel StateSource = "label"
+)
+
+// JSONSchema returns the JSON schema for StateSource type.
+func (StateSource) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "string",
+		Description: "Expected state source: schedule or label",
+		Enum:        []any{string(StateSourceSchedule), string(StateSourceLabel)},
+		Examples:    []any{string(StateSourceLabel)},
+	}
+}
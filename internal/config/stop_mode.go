@@ -0,0 +1,28 @@
+package config
+
+import "github.com/invopop/jsonschema"
+
+// StopMode selects how a stop action brings a resource out of the running
+// state.
+type StopMode string
+
+const (
+	// StopModeStop stops the resource. It is the default.
+	StopModeStop StopMode = "stop"
+
+	// StopModePause pauses the resource, leaving it in the paused state
+	// distinct from stopped. Only resource types with a paused state
+	// support it.
+	StopModePause StopMode = "pause"
+)
+
+// JSONSchema returns the JSON schema for StopMode type.
+func (StopMode) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "string",
+		Description: "Stop mode: stop or pause",
+		Enum:        []any{string(StopModeStop), string(StopModePause)},
+		Default:     string(StopModeStop),
+		Examples:    []any{string(StopModePause)},
+	}
+}
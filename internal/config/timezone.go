@@ -52,3 +52,10 @@ func (Timezone) JSONSchema() *jsonschema.Schema {
 		Examples:    []any{"UTC", "Europe/Moscow", "America/New_York", "Asia/Tokyo"},
 	}
 }
+
+// SupportsTimezone reports whether schedules of the type may take times in
+// their own timezone. One-time schedules run at absolute times, and runs of
+// duration schedules follow the global timezone.
+func SupportsTimezone(scheduleType string) bool {
+	return scheduleType != "duration" && scheduleType != "one-time"
+}
@@ -0,0 +1,359 @@
+// Package csvimport generates business_hours schedule manifests from a CSV
+// table of office hours, one manifest per resource row.
+package csvimport
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+// Columns of the table. Columns are matched by the header row, so their order
+// does not matter and other columns are ignored.
+const (
+	ColumnResourceID = "resource_id"
+	ColumnType       = "type"
+	ColumnFolder     = "folder"
+	ColumnStart      = "start"
+	ColumnStop       = "stop"
+	ColumnDays       = "days"
+	ColumnTimezone   = "timezone"
+)
+
+// requiredColumns must be present in the header row; days and timezone may be
+// left out.
+var requiredColumns = []string{ColumnResourceID, ColumnType, ColumnFolder, ColumnStart, ColumnStop}
+
+// schemaComment points editors to the schema of the generated manifests.
+const schemaComment = "# yaml-language-server: $schema=https://raw.githubusercontent.com/sentoz/yc-sheduler/refs/heads/master/static/schemas/schedule.json\n"
+
+// ErrExists is returned when a manifest file already exists and overwriting
+// is not allowed.
+var ErrExists = errors.New("file already exists")
+
+// weekdays maps day names accepted in the days column to day numbers.
+var weekdays = map[string]int{
+	"sun": 0, "sunday": 0,
+	"mon": 1, "monday": 1,
+	"tue": 2, "tuesday": 2,
+	"wed": 3, "wednesday": 3,
+	"thu": 4, "thursday": 4,
+	"fri": 5, "friday": 5,
+	"sat": 6, "saturday": 6,
+}
+
+// RowError is an error of a table row, or of the header for Line 1.
+type RowError struct {
+	Line int
+	Err  error
+}
+
+// Error implements error.
+func (e RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e RowError) Unwrap() error {
+	return e.Err
+}
+
+// Manifest is a schedule manifest generated from a table row.
+type Manifest struct {
+	// Line is the line of the row in the table.
+	Line     int
+	Manifest config.ScheduleManifest
+}
+
+// FileName returns the name of the manifest file.
+func (m Manifest) FileName() string {
+	return m.Manifest.Metadata.Name + ".yaml"
+}
+
+// Parse reads the table with the given field delimiter and returns a
+// business_hours manifest per row. Empty rows are skipped. Errors of all rows
+// are returned joined, each as a RowError.
+func Parse(r io.Reader, delimiter rune) ([]Manifest, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, RowError{Line: 1, Err: errors.New("header row is missing")}
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	var missing []string
+	for _, name := range requiredColumns {
+		if _, ok := columns[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, RowError{Line: 1, Err: fmt.Errorf("missing columns: %s", strings.Join(missing, ", "))}
+	}
+
+	var (
+		manifests []Manifest
+		errs      []error
+	)
+	lines := make(map[string]int)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		if !slices.ContainsFunc(record, func(v string) bool { return strings.TrimSpace(v) != "" }) {
+			continue
+		}
+
+		manifest, err := BusinessHours(Row{
+			ResourceID: field(ColumnResourceID),
+			Type:       field(ColumnType),
+			Folder:     field(ColumnFolder),
+			Start:      field(ColumnStart),
+			Stop:       field(ColumnStop),
+			Days:       field(ColumnDays),
+			Timezone:   field(ColumnTimezone),
+		})
+		if err != nil {
+			errs = append(errs, RowError{Line: line, Err: err})
+			continue
+		}
+		name := manifest.Metadata.Name
+		if first, ok := lines[name]; ok {
+			errs = append(errs, RowError{Line: line, Err: fmt.Errorf("resource %s is already scheduled on line %d", field(ColumnResourceID), first)})
+			continue
+		}
+		lines[name] = line
+		manifests = append(manifests, Manifest{Line: line, Manifest: manifest})
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return manifests, nil
+}
+
+// Row holds the values of a table row as written in the table.
+type Row struct {
+	ResourceID string
+	Type       string
+	Folder     string
+	Start      string
+	Stop       string
+	// Days and Timezone are optional.
+	Days     string
+	Timezone string
+}
+
+// BusinessHours returns the business_hours manifest of a row, checked against
+// the schedule schema. Times, days and the timezone are accepted in the forms
+// spreadsheets export them, e.g. 9:00 and Mon-Fri.
+func BusinessHours(row Row) (config.ScheduleManifest, error) {
+	manifest, err := manifestFromRow(row)
+	if err != nil {
+		return config.ScheduleManifest{}, err
+	}
+	if err := validate(manifest); err != nil {
+		return config.ScheduleManifest{}, err
+	}
+	return manifest, nil
+}
+
+// manifestFromRow builds the manifest of a row.
+func manifestFromRow(row Row) (config.ScheduleManifest, error) {
+	id, resourceType, folder := row.ResourceID, row.Type, row.Folder
+	for _, column := range []struct{ name, value string }{
+		{ColumnResourceID, id}, {ColumnType, resourceType}, {ColumnFolder, folder},
+	} {
+		if column.value == "" {
+			return config.ScheduleManifest{}, fmt.Errorf("%s is empty", column.name)
+		}
+	}
+	start, err := parseTime(row.Start)
+	if err != nil {
+		return config.ScheduleManifest{}, fmt.Errorf("start: %w", err)
+	}
+	stop, err := parseTime(row.Stop)
+	if err != nil {
+		return config.ScheduleManifest{}, fmt.Errorf("stop: %w", err)
+	}
+	if start == stop {
+		return config.ScheduleManifest{}, fmt.Errorf("start and stop are both %s", start)
+	}
+	days, err := parseDays(row.Days)
+	if err != nil {
+		return config.ScheduleManifest{}, fmt.Errorf("days: %w", err)
+	}
+	timezone := row.Timezone
+	if timezone != "" {
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return config.ScheduleManifest{}, fmt.Errorf("timezone: %w", err)
+		}
+	}
+
+	return config.ScheduleManifest{
+		APIVersion: config.ScheduleAPIVersion,
+		Kind:       "Schedule",
+		Metadata: config.ScheduleManifestMeta{
+			Name: strings.ReplaceAll(resourceType, "_", "-") + "-" + strings.ToLower(id),
+		},
+		Spec: config.ScheduleManifestSpec{
+			Type:     config.BusinessHoursType,
+			Resource: &config.Resource{Type: resourceType, ID: id, FolderID: folder},
+			Start:    start,
+			End:      stop,
+			Days:     days,
+			Timezone: config.Timezone(timezone),
+		},
+	}, nil
+}
+
+// parseTime parses a time of day like 9:00 or 09:00:30 as spreadsheets export
+// it and returns it zero-padded.
+func parseTime(value string) (config.Time, error) {
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		t, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+		if t.Second() != 0 {
+			return config.Time(t.Format("15:04:05")), nil
+		}
+		return config.Time(t.Format("15:04")), nil
+	}
+	return "", fmt.Errorf("invalid time %q, want HH:MM", value)
+}
+
+// parseDays parses days of the week separated by commas, semicolons or
+// spaces, given as numbers (0=Sunday) or English names, and ranges like
+// mon-fri. An empty value returns nil for the default Monday to Friday.
+func parseDays(value string) ([]int, error) {
+	fields := strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return r == ',' || r == ';' || r == ' '
+	})
+	var days []int
+	for _, f := range fields {
+		from, to, isRange := strings.Cut(f, "-")
+		first, err := parseDay(from)
+		if err != nil {
+			return nil, err
+		}
+		last := first
+		if isRange {
+			if last, err = parseDay(to); err != nil {
+				return nil, err
+			}
+		}
+		// A range like fri-mon wraps around the end of the week.
+		for day := first; ; day = (day + 1) % 7 {
+			if !slices.Contains(days, day) {
+				days = append(days, day)
+			}
+			if day == last {
+				break
+			}
+		}
+	}
+	slices.Sort(days)
+	if slices.Equal(days, []int{1, 2, 3, 4, 5}) {
+		return nil, nil
+	}
+	return days, nil
+}
+
+func parseDay(value string) (int, error) {
+	if day, ok := weekdays[value]; ok {
+		return day, nil
+	}
+	day, err := strconv.Atoi(value)
+	if err != nil || day < 0 || day > 6 {
+		return 0, fmt.Errorf("invalid day %q, want 0-6 or a day name", value)
+	}
+	return day, nil
+}
+
+// validate checks the rendered manifest against the schedule schema, e.g. for
+// an unknown resource type.
+func validate(manifest config.ScheduleManifest) error {
+	raw, err := Marshal(Manifest{Manifest: manifest})
+	if err != nil {
+		return err
+	}
+	_, err = config.ParseScheduleFile(raw, manifest.Metadata.Name)
+	return err
+}
+
+// Marshal renders the manifest as a YAML document.
+func Marshal(m Manifest) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(schemaComment)
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(m.Manifest); err != nil {
+		return nil, fmt.Errorf("marshal %s: %w", m.Manifest.Metadata.Name, err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Write writes every manifest to its file in dir, creating dir if needed, and
+// returns the paths of written files. Existing files are overwritten only
+// with force; otherwise nothing is written.
+func Write(dir string, manifests []Manifest, force bool) ([]string, error) {
+	rendered := make([][]byte, len(manifests))
+	files := make([]string, len(manifests))
+	for i, m := range manifests {
+		raw, err := Marshal(m)
+		if err != nil {
+			return nil, err
+		}
+		rendered[i] = raw
+		files[i] = filepath.Join(dir, m.FileName())
+		if _, err := os.Stat(files[i]); err == nil && !force {
+			return nil, fmt.Errorf("%w: %s", ErrExists, files[i])
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create directory: %w", err)
+	}
+	for i, file := range files {
+		if err := os.WriteFile(file, rendered[i], 0o644); err != nil {
+			return nil, fmt.Errorf("write %s: %w", file, err)
+		}
+	}
+	return files, nil
+}
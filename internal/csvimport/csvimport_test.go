@@ -0,0 +1,140 @@
+package csvimport
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	table := strings.Join([]string{
+		"Owner;Resource_ID;Type;Folder;Start;Stop;Days;Timezone",
+		"alice;fhm1234567890abcdef;vm;b1g1234567890abcdef;9:00;19:00;Mon-Fri;",
+		";;;;;;;",
+		"bob;cat1234567890abcdef;k8s_cluster;b1g1234567890abcdef;22:00;06:00;fri-mon;Asia/Yekaterinburg",
+	}, "\n")
+
+	manifests, err := Parse(strings.NewReader(table), ';')
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(manifests) != 2 {
+		t.Fatalf("Parse() = %d manifests, want 2", len(manifests))
+	}
+
+	vm := manifests[0]
+	if vm.Line != 2 || vm.FileName() != "vm-fhm1234567890abcdef.yaml" {
+		t.Fatalf("manifest = line %d, file %s; want line 2, vm-fhm1234567890abcdef.yaml", vm.Line, vm.FileName())
+	}
+	spec := vm.Manifest.Spec
+	if spec.Type != config.BusinessHoursType || spec.Start != "09:00" || spec.End != "19:00" || spec.Days != nil || spec.Timezone != "" {
+		t.Fatalf("vm spec = %+v, want business hours 09:00-19:00 on default days", spec)
+	}
+
+	if line := manifests[1].Line; line != 4 {
+		t.Fatalf("cluster line = %d, want 4", line)
+	}
+	cluster := manifests[1].Manifest.Spec
+	if !slices.Equal(cluster.Days, []int{0, 1, 5, 6}) || cluster.Timezone != "Asia/Yekaterinburg" {
+		t.Fatalf("cluster spec = %+v, want days fri-mon in Asia/Yekaterinburg", cluster)
+	}
+}
+
+func TestParseReportsEveryInvalidRow(t *testing.T) {
+	t.Parallel()
+
+	table := strings.Join([]string{
+		"resource_id,type,folder,start,stop,days",
+		"fhm1,vm,b1g1,25:00,19:00,",
+		"fhm2,vm,b1g1,09:00,19:00,holidays",
+		"fhm3,vm,b1g1,09:00,19:00,",
+		"fhm3,vm,b1g1,10:00,20:00,",
+		"fhm4,database,b1g1,09:00,19:00,",
+	}, "\n")
+
+	_, err := Parse(strings.NewReader(table), ',')
+	var lines []int
+	for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var rowErr RowError
+		if !errors.As(err, &rowErr) {
+			t.Fatalf("error %v is not a RowError", err)
+		}
+		lines = append(lines, rowErr.Line)
+	}
+	if !slices.Equal(lines, []int{2, 3, 5, 6}) {
+		t.Fatalf("invalid rows = %v, want [2 3 5 6]", lines)
+	}
+
+	if _, err := Parse(strings.NewReader("resource_id,type,start\n"), ','); err == nil || !strings.Contains(err.Error(), "missing columns: folder, stop") {
+		t.Fatalf("Parse() without columns error = %v, want missing folder and stop", err)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	table := "resource_id,type,folder,start,stop\nfhm1234567890abcdef,vm,b1g1234567890abcdef,09:00,19:00\n"
+	manifests, err := Parse(strings.NewReader(table), ',')
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "schedules")
+	files, err := Write(dir, manifests, false)
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("Write() files = %v, want one manifest", files)
+	}
+
+	// The written manifests load as is.
+	schedules, err := config.LoadSchedules(context.Background(), false, dir)
+	if err != nil {
+		t.Fatalf("LoadSchedules() error = %v", err)
+	}
+	if len(schedules) != 1 || schedules[0].Actions.Start == nil || schedules[0].Actions.Stop == nil {
+		t.Fatalf("schedules = %+v, want one schedule with start and stop", schedules)
+	}
+
+	if _, err := Write(dir, manifests, false); !errors.Is(err, ErrExists) {
+		t.Fatalf("Write() over existing files error = %v, want %v", err, ErrExists)
+	}
+	if _, err := Write(dir, manifests, true); err != nil {
+		t.Fatalf("Write() with force error = %v", err)
+	}
+}
+
+func TestBusinessHours(t *testing.T) {
+	t.Parallel()
+
+	manifest, err := BusinessHours(Row{
+		ResourceID: "cat1234567890abcdef",
+		Type:       "k8s_node_group",
+		Folder:     "b1g1234567890abcdef",
+		Start:      "8:00",
+		Stop:       "20:00",
+		Days:       "mon-sat",
+	})
+	if err != nil {
+		t.Fatalf("BusinessHours() error = %v", err)
+	}
+	if manifest.Metadata.Name != "k8s-node-group-cat1234567890abcdef" {
+		t.Fatalf("name = %q, want k8s-node-group-cat1234567890abcdef", manifest.Metadata.Name)
+	}
+	if spec := manifest.Spec; spec.Start != "08:00" || spec.End != "20:00" || !slices.Equal(spec.Days, []int{1, 2, 3, 4, 5, 6}) {
+		t.Fatalf("spec = %+v, want 08:00-20:00 from Monday to Saturday", spec)
+	}
+
+	row := Row{ResourceID: "x", Type: "database", Folder: "b1g1", Start: "09:00", Stop: "19:00"}
+	if _, err := BusinessHours(row); err == nil {
+		t.Fatal("BusinessHours() with an unknown resource type error = nil, want schema error")
+	}
+}
@@ -0,0 +1,125 @@
+// Package denylist holds IDs of resources the scheduler must never operate
+// on, whichever schedule references them.
+package denylist
+
+import (
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Entry sources.
+const (
+	// SourceConfig marks entries defined in the configuration file.
+	SourceConfig = "config"
+	// SourceAPI marks entries added through the HTTP API.
+	SourceAPI = "api"
+)
+
+// ErrDenied is returned for operations on resources of the deny list.
+var ErrDenied = errors.New("resource is on the deny list")
+
+// Entry denies all operations on the resource with ID.
+type Entry struct {
+	ID     string `json:"id"`
+	Reason string `json:"reason,omitempty"`
+	Source string `json:"source"`
+}
+
+// List holds denied resources. An ID may be denied by both sources; it stays
+// denied until removed from both.
+type List struct {
+	entries []Entry
+	mu      sync.Mutex
+}
+
+// New creates an empty List.
+func New() *List {
+	return &List{}
+}
+
+// Add denies the resource of e. An ID already denied by the same source is
+// updated with the new reason.
+func (l *List) Add(e Entry) Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.addUnlocked(e)
+	return e
+}
+
+func (l *List) addUnlocked(e Entry) {
+	i := slices.IndexFunc(l.entries, func(existing Entry) bool {
+		return existing.ID == e.ID && existing.Source == e.Source
+	})
+	if i >= 0 {
+		l.entries[i] = e
+	} else {
+		l.entries = append(l.entries, e)
+	}
+
+	log.Info().
+		Str("resource_id", e.ID).
+		Str("source", e.Source).
+		Str("reason", e.Reason).
+		Msg("Resource added to deny list")
+}
+
+// Remove deletes the API entry of id and reports whether it existed. Entries
+// of the configuration file are removed only by changing the file.
+func (l *List) Remove(id string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i, e := range l.entries {
+		if e.ID == id && e.Source == SourceAPI {
+			l.entries = slices.Delete(l.entries, i, i+1)
+			log.Info().
+				Str("resource_id", id).
+				Msg("Resource removed from deny list")
+			return true
+		}
+	}
+	return false
+}
+
+// ReplaceSource replaces all entries of the given source.
+func (l *List) ReplaceSource(source string, entries []Entry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = slices.DeleteFunc(l.entries, func(e Entry) bool {
+		return e.Source == source
+	})
+	for _, e := range entries {
+		e.Source = source
+		l.addUnlocked(e)
+	}
+}
+
+// List returns all entries.
+func (l *List) List() []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return slices.Clone(l.entries)
+}
+
+// Denied returns the first entry denying the resource with id.
+func (l *List) Denied(id string) (Entry, bool) {
+	if l == nil || id == "" {
+		return Entry{}, false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, e := range l.entries {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
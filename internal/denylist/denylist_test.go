@@ -0,0 +1,70 @@
+package denylist
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/resource"
+)
+
+func TestListSources(t *testing.T) {
+	t.Parallel()
+
+	l := New()
+	l.ReplaceSource(SourceConfig, []Entry{{ID: "fhm-prod", Reason: "production"}})
+	l.Add(Entry{ID: "fhm-prod", Source: SourceAPI})
+	l.Add(Entry{ID: "fhm-db", Source: SourceAPI})
+
+	if e, ok := l.Denied("fhm-prod"); !ok || e.Source != SourceConfig {
+		t.Fatalf("Denied(fhm-prod) = %+v, %v; want the config entry", e, ok)
+	}
+	if !l.Remove("fhm-prod") {
+		t.Fatal("Remove(fhm-prod) = false, want the API entry removed")
+	}
+	if _, ok := l.Denied("fhm-prod"); !ok {
+		t.Fatal("fhm-prod is not denied after removing its API entry, want the config entry kept")
+	}
+	if l.Remove("fhm-prod") {
+		t.Fatal("Remove(fhm-prod) removed a config entry")
+	}
+
+	l.ReplaceSource(SourceConfig, nil)
+	if _, ok := l.Denied("fhm-prod"); ok {
+		t.Fatal("fhm-prod is denied after its config entry was replaced")
+	}
+	if _, ok := l.Denied("fhm-db"); !ok {
+		t.Fatal("fhm-db is not denied after replacing config entries")
+	}
+}
+
+type countingOperator struct {
+	resource.Operator
+	stops int
+}
+
+func (o *countingOperator) Stop(context.Context, config.Resource, resource.StopOptions) error {
+	o.stops++
+	return nil
+}
+
+func TestGuardRefusesDeniedResources(t *testing.T) {
+	t.Parallel()
+
+	l := New()
+	l.Add(Entry{ID: "fhm-prod", Reason: "production", Source: SourceAPI})
+	op := &countingOperator{}
+	guarded := Guard(op, l)
+
+	err := guarded.Stop(context.Background(), config.Resource{Type: "vm", ID: "fhm-prod"}, resource.StopOptions{})
+	if !errors.Is(err, ErrDenied) {
+		t.Fatalf("Stop(fhm-prod) error = %v, want ErrDenied", err)
+	}
+	if err := guarded.Stop(context.Background(), config.Resource{Type: "vm", ID: "fhm-dev"}, resource.StopOptions{}); err != nil {
+		t.Fatalf("Stop(fhm-dev) error = %v", err)
+	}
+	if op.stops != 1 {
+		t.Fatalf("operator stops = %d, want 1", op.stops)
+	}
+}
@@ -0,0 +1,83 @@
+package denylist
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/resource"
+)
+
+// guardedOperator refuses operations on denied resources before they reach
+// the wrapped operator. It is the last guard for every caller, including
+// the validator, the idle policy and vacations.
+type guardedOperator struct {
+	operator resource.Operator
+	list     *List
+}
+
+// Guard returns an operator that fails with ErrDenied for resources of list
+// and passes other operations to operator.
+func Guard(operator resource.Operator, list *List) resource.Operator {
+	return &guardedOperator{operator: operator, list: list}
+}
+
+func (g *guardedOperator) check(target config.Resource) error {
+	e, denied := g.list.Denied(target.ID)
+	if !denied {
+		return nil
+	}
+	if e.Reason != "" {
+		return fmt.Errorf("%w: %s %s: %s", ErrDenied, target.Type, target.ID, e.Reason)
+	}
+	return fmt.Errorf("%w: %s %s", ErrDenied, target.Type, target.ID)
+}
+
+func (g *guardedOperator) Start(ctx context.Context, target config.Resource, opts resource.StartOptions) error {
+	if err := g.check(target); err != nil {
+		return err
+	}
+	return g.operator.Start(ctx, target, opts)
+}
+
+func (g *guardedOperator) Stop(ctx context.Context, target config.Resource, opts resource.StopOptions) error {
+	if err := g.check(target); err != nil {
+		return err
+	}
+	return g.operator.Stop(ctx, target, opts)
+}
+
+func (g *guardedOperator) Snapshot(ctx context.Context, target config.Resource, retention int) error {
+	if err := g.check(target); err != nil {
+		return err
+	}
+	return g.operator.Snapshot(ctx, target, retention)
+}
+
+func (g *guardedOperator) Restart(ctx context.Context, target config.Resource, opts resource.RestartOptions) error {
+	if err := g.check(target); err != nil {
+		return err
+	}
+	return g.operator.Restart(ctx, target, opts)
+}
+
+func (g *guardedOperator) Scale(ctx context.Context, target config.Resource, targetSize int) error {
+	if err := g.check(target); err != nil {
+		return err
+	}
+	return g.operator.Scale(ctx, target, targetSize)
+}
+
+func (g *guardedOperator) Resize(ctx context.Context, target config.Resource, opts resource.ResizeOptions) error {
+	if err := g.check(target); err != nil {
+		return err
+	}
+	return g.operator.Resize(ctx, target, opts)
+}
+
+func (g *guardedOperator) SetPreemptible(ctx context.Context, target config.Resource, preemptible bool) error {
+	if err := g.check(target); err != nil {
+		return err
+	}
+	return g.operator.SetPreemptible(ctx, target, preemptible)
+}
@@ -0,0 +1,264 @@
+// Package diff compares two sets of schedules and renders the added, removed
+// and changed schedules and actions for humans.
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+// Kind is the kind of change of a schedule, action or setting.
+type Kind string
+
+// Change kinds.
+const (
+	// Added is an entry present only in the new set.
+	Added Kind = "added"
+	// Removed is an entry present only in the old set.
+	Removed Kind = "removed"
+	// Changed is an entry present in both sets with different values.
+	Changed Kind = "changed"
+)
+
+// ANSI escape sequences of the colored output.
+const (
+	colorReset  = "\x1b[0m"
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+)
+
+// Entry is a change of a single action or schedule setting. Old and New are
+// compact JSON values; Old is empty for added and New for removed entries.
+type Entry struct {
+	Name string `json:"name"`
+	Kind Kind   `json:"kind"`
+	Old  string `json:"old,omitempty"`
+	New  string `json:"new,omitempty"`
+}
+
+// ScheduleChange is a change of a schedule with its actions and settings.
+type ScheduleChange struct {
+	Name     string  `json:"name"`
+	Kind     Kind    `json:"kind"`
+	Actions  []Entry `json:"actions,omitempty"`
+	Settings []Entry `json:"settings,omitempty"`
+}
+
+// Diff is the list of changed schedules ordered by name.
+type Diff []ScheduleChange
+
+// Schedules compares the old and new schedule sets by schedule name.
+func Schedules(old, updated []config.Schedule) Diff {
+	oldByName := make(map[string]config.Schedule, len(old))
+	for _, sch := range old {
+		oldByName[sch.Name] = sch
+	}
+	newByName := make(map[string]config.Schedule, len(updated))
+	for _, sch := range updated {
+		newByName[sch.Name] = sch
+	}
+
+	names := slices.Sorted(maps.Keys(oldByName))
+	for name := range newByName {
+		if _, ok := oldByName[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var d Diff
+	for _, name := range names {
+		before, hadOld := oldByName[name]
+		after, hasNew := newByName[name]
+		switch {
+		case !hadOld:
+			d = append(d, ScheduleChange{Name: name, Kind: Added, Actions: compare(nil, actionValues(after))})
+		case !hasNew:
+			d = append(d, ScheduleChange{Name: name, Kind: Removed, Actions: compare(actionValues(before), nil)})
+		default:
+			change := ScheduleChange{
+				Name:     name,
+				Kind:     Changed,
+				Actions:  compare(actionValues(before), actionValues(after)),
+				Settings: compare(settingValues(before), settingValues(after)),
+			}
+			if len(change.Actions) > 0 || len(change.Settings) > 0 {
+				d = append(d, change)
+			}
+		}
+	}
+	return d
+}
+
+// Empty reports whether the schedule sets are equal.
+func (d Diff) Empty() bool {
+	return len(d) == 0
+}
+
+// Render formats the diff one change per line: "+" marks added, "-" removed
+// and "~" changed entries, with actions and settings indented under their
+// schedule. With color, lines are colored green, red and yellow.
+func (d Diff) Render(color bool) string {
+	var b strings.Builder
+	for _, sch := range d {
+		writeLine(&b, color, sch.Kind, "", "schedule "+sch.Name)
+		for _, e := range sch.Settings {
+			writeLine(&b, color, e.Kind, "    ", describe(e))
+		}
+		for _, e := range sch.Actions {
+			writeLine(&b, color, e.Kind, "    ", "action "+describe(e))
+		}
+	}
+	return b.String()
+}
+
+// Summary returns a one-line count of added, removed and changed schedules.
+func (d Diff) Summary() string {
+	counts := make(map[Kind]int)
+	for _, sch := range d {
+		counts[sch.Kind]++
+	}
+	return fmt.Sprintf("%d added, %d removed, %d changed", counts[Added], counts[Removed], counts[Changed])
+}
+
+func writeLine(b *strings.Builder, color bool, kind Kind, indent, text string) {
+	line := indent + marker(kind) + " " + text
+	if color {
+		line = colorOf(kind) + line + colorReset
+	}
+	b.WriteString(line)
+	b.WriteByte('\n')
+}
+
+func describe(e Entry) string {
+	switch e.Kind {
+	case Added:
+		return e.Name + " " + e.New
+	case Removed:
+		return e.Name + " " + e.Old
+	default:
+		return e.Name + ": " + e.Old + " -> " + e.New
+	}
+}
+
+func marker(kind Kind) string {
+	switch kind {
+	case Added:
+		return "+"
+	case Removed:
+		return "-"
+	default:
+		return "~"
+	}
+}
+
+func colorOf(kind Kind) string {
+	switch kind {
+	case Added:
+		return colorGreen
+	case Removed:
+		return colorRed
+	default:
+		return colorYellow
+	}
+}
+
+// compare returns the entries of old and updated that differ, ordered by name.
+func compare(old, updated map[string]string) []Entry {
+	names := slices.Sorted(maps.Keys(old))
+	for name := range updated {
+		if _, ok := old[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var entries []Entry
+	for _, name := range names {
+		before, hadOld := old[name]
+		after, hasNew := updated[name]
+		switch {
+		case !hadOld:
+			entries = append(entries, Entry{Name: name, Kind: Added, New: after})
+		case !hasNew:
+			entries = append(entries, Entry{Name: name, Kind: Removed, Old: before})
+		case before != after:
+			entries = append(entries, Entry{Name: name, Kind: Changed, Old: before, New: after})
+		}
+	}
+	return entries
+}
+
+// actionValues returns the configured actions of a schedule keyed by name;
+// entries of multi-entry actions are keyed by their index.
+func actionValues(sch config.Schedule) map[string]string {
+	values := make(map[string]string)
+	for name, cfg := range map[string]*config.ActionConfig{
+		"start":    sch.Actions.Start,
+		"stop":     sch.Actions.Stop,
+		"snapshot": sch.Actions.Snapshot,
+		"restart":  sch.Actions.Restart,
+		"resize":   sch.Actions.Resize,
+	} {
+		if cfg != nil {
+			values[name] = compact(cfg)
+		}
+	}
+	for name, entries := range map[string][]config.ActionConfig{
+		"scale":       sch.Actions.Scale,
+		"preemptible": sch.Actions.Preemptible,
+	} {
+		for i := range entries {
+			values[name+"["+strconv.Itoa(i)+"]"] = compact(entries[i])
+		}
+	}
+	return values
+}
+
+// settingValues returns the top-level settings of a schedule other than its
+// actions keyed by their JSON names.
+func settingValues(sch config.Schedule) map[string]string {
+	sch.Actions = config.Actions{}
+	data, err := json.Marshal(sch)
+	if err != nil {
+		return nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil
+	}
+	delete(fields, "actions")
+	values := make(map[string]string, len(fields))
+	for name, raw := range fields {
+		values[name] = string(raw)
+	}
+	return values
+}
+
+// compact formats an action as JSON with sorted keys, leaving out zero
+// durations that are not omitted by their JSON encoding.
+func compact(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%+v", v)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return string(data)
+	}
+	maps.DeleteFunc(fields, func(_ string, raw json.RawMessage) bool {
+		return string(raw) == `"0s"`
+	})
+	data, err = json.Marshal(fields)
+	if err != nil {
+		return fmt.Sprintf("%+v", v)
+	}
+	return string(data)
+}
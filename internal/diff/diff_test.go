@@ -0,0 +1,65 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+func makeSchedule(name, startTime string) config.Schedule {
+	return config.Schedule{
+		Name:     name,
+		Type:     "daily",
+		Resource: config.Resource{Type: "vm", ID: "id-" + name, FolderID: "folder-1"},
+		Actions: config.Actions{
+			Start: &config.ActionConfig{Enabled: true, Time: startTime},
+		},
+	}
+}
+
+func TestSchedules(t *testing.T) {
+	t.Parallel()
+
+	changedType := makeSchedule("db", "09:00")
+	changedType.Type = "weekly"
+	changedType.Actions.Start.Day = 1
+	changedType.Actions.Stop = &config.ActionConfig{Enabled: true, Time: "19:00", Day: 5}
+
+	d := Schedules(
+		[]config.Schedule{makeSchedule("vm", "09:00"), makeSchedule("db", "09:00"), makeSchedule("old", "08:00")},
+		[]config.Schedule{makeSchedule("vm", "09:00"), changedType, makeSchedule("new", "10:00")},
+	)
+
+	want := strings.Join([]string{
+		"~ schedule db",
+		`    ~ type: "daily" -> "weekly"`,
+		`    ~ action start: {"enabled":true,"time":"09:00"} -> {"day":1,"enabled":true,"time":"09:00"}`,
+		`    + action stop {"day":5,"enabled":true,"time":"19:00"}`,
+		"+ schedule new",
+		`    + action start {"enabled":true,"time":"10:00"}`,
+		"- schedule old",
+		`    - action start {"enabled":true,"time":"08:00"}`,
+		"",
+	}, "\n")
+	if got := d.Render(false); got != want {
+		t.Fatalf("Render(false) =\n%s\nwant\n%s", got, want)
+	}
+	if got := d.Summary(); got != "1 added, 1 removed, 1 changed" {
+		t.Fatalf("Summary() = %q", got)
+	}
+}
+
+func TestRenderColor(t *testing.T) {
+	t.Parallel()
+
+	d := Schedules(nil, []config.Schedule{makeSchedule("vm", "09:00")})
+	got := d.Render(true)
+	if !strings.HasPrefix(got, colorGreen+"+ schedule vm"+colorReset+"\n") {
+		t.Fatalf("Render(true) = %q, want green added lines", got)
+	}
+
+	if d := Schedules([]config.Schedule{makeSchedule("vm", "09:00")}, []config.Schedule{makeSchedule("vm", "09:00")}); !d.Empty() {
+		t.Fatalf("Schedules() of equal sets = %+v, want empty", d)
+	}
+}
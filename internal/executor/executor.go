@@ -2,40 +2,61 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog/log"
 
 	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/denylist"
+	"github.com/sentoz/yc-sheduler/internal/hook"
 	"github.com/sentoz/yc-sheduler/internal/metrics"
 	"github.com/sentoz/yc-sheduler/internal/resource"
+	"github.com/sentoz/yc-sheduler/internal/yc"
 )
 
-var operationLocks = newInFlightLocks()
+// fallbackTimeout bounds an action run when neither the action nor
+// Executor.SetDefaultTimeout configures a timeout.
+const fallbackTimeout = 5 * time.Minute
 
+// stateCheckAttempts is the number of state reads made with the retry
+// on_state_check_error policy.
+const stateCheckAttempts = 4
+
+// stateCheckRetryDelay is the delay before the first state read retry. It
+// doubles with every retry.
+var stateCheckRetryDelay = 5 * time.Second
+
+// inFlightLocks tracks resources with an operation in progress, so
+// scheduled jobs and validator corrections never operate on the same
+// resource at the same time, whichever schedule or action they come from.
 type inFlightLocks struct {
-	locks map[string]struct{}
+	locks map[string]string
 	mu    sync.Mutex
 }
 
 func newInFlightLocks() *inFlightLocks {
 	return &inFlightLocks{
-		locks: make(map[string]struct{}),
+		locks: make(map[string]string),
 	}
 }
 
-func (l *inFlightLocks) tryLock(key string) bool {
+// tryLock locks key for the holder. If key is already locked, it returns
+// false with the current holder.
+func (l *inFlightLocks) tryLock(key, holder string) (string, bool) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	if _, exists := l.locks[key]; exists {
-		return false
+	if current, exists := l.locks[key]; exists {
+		return current, false
 	}
 
-	l.locks[key] = struct{}{}
-	return true
+	l.locks[key] = holder
+	return "", true
 }
 
 func (l *inFlightLocks) unlock(key string) {
@@ -45,63 +66,447 @@ func (l *inFlightLocks) unlock(key string) {
 	delete(l.locks, key)
 }
 
-// Make returns a job function that executes the given action for the schedule's resource.
+// Executor runs schedule actions. Operations started by the same Executor
+// never overlap on a resource and wait for each other's concurrency scopes,
+// so scheduled jobs and validator corrections share one; separate executors
+// do not block each other.
+type Executor struct {
+	operations  *inFlightLocks
+	concurrency *scopeLocks
+	// denied is the global deny list checked before each operation.
+	denied atomic.Pointer[denylist.List]
+	// lastActions records the last action performed on each resource, or
+	// is nil when they are not recorded.
+	lastActions atomic.Pointer[resource.ActionStore]
+	// utilization holds the settings of utilization snapshots, or nil when
+	// they are disabled.
+	utilization atomic.Pointer[utilizationSettings]
+	// defaultTimeout bounds an action run when the action has no timeout.
+	defaultTimeout atomic.Int64
+}
+
+// New creates an Executor.
+func New() *Executor {
+	return &Executor{
+		operations:  newInFlightLocks(),
+		concurrency: newScopeLocks(),
+	}
+}
+
+// SetDefaultTimeout sets the timeout of action runs whose action does not
+// configure its own timeout.
+func (e *Executor) SetDefaultTimeout(timeout time.Duration) {
+	e.defaultTimeout.Store(int64(timeout))
+}
+
+// SetDenyList sets the list of resources that are never operated on, in
+// addition to the denied resources of each schedule.
+func (e *Executor) SetDenyList(list *denylist.List) {
+	e.denied.Store(list)
+}
+
+// SetLastActions sets the store the last successful action on each resource
+// is recorded in. A nil store disables recording.
+func (e *Executor) SetLastActions(store *resource.ActionStore) {
+	e.lastActions.Store(store)
+}
+
+// DeniedReason reports whether target is denied globally or by the schedule
+// and returns the reason.
+func (e *Executor) DeniedReason(sch config.Schedule, target config.Resource) (string, bool) {
+	if slices.Contains(sch.DeniedResourceIDs, target.ID) {
+		return "denied by schedule", true
+	}
+	if entry, ok := e.denied.Load().Denied(target.ID); ok {
+		return entry.Reason, true
+	}
+	return "", false
+}
+
+// recordLastAction records a successful action on target with the ID of the
+// last cloud operation it started. stop holds the options of a stop action.
+func (e *Executor) recordLastAction(sch config.Schedule, target config.Resource, action string, stop resource.StopOptions, executionID, operationID string) {
+	store := e.lastActions.Load()
+	if store == nil {
+		return
+	}
+	last := resource.LastAction{
+		At:           time.Now(),
+		ResourceType: target.Type,
+		ResourceID:   target.ID,
+		FolderID:     target.FolderID,
+		Schedule:     sch.Name,
+		Action:       action,
+		OperationID:  operationID,
+		ExecutionID:  executionID,
+	}
+	if action == "stop" && stop.Pause {
+		last.StopMode = config.StopModePause
+	}
+	err := store.Record(last)
+	if err != nil {
+		log.Warn().Err(err).
+			Str("schedule", sch.Name).
+			Str("resource_id", target.ID).
+			Str("action", action).
+			Msg("Failed to record last resource action")
+	}
+}
+
+// Make returns a job function that executes the given action for the schedule's resources.
+// Name pattern resources are resolved each time the job runs.
+// Resources of a multi-resource schedule are processed concurrently, at most
+// sch.EffectiveMaxParallel() at a time. Steps are processed one after
+// another.
 // The returned function has no parameters to match gocron's expectations.
 // If m is nil, metrics will not be recorded.
-func Make(stateChecker resource.StateChecker, operator resource.Operator, sch config.Schedule, action string, dryRun bool, m *metrics.Metrics) func() {
-	resource := sch.Resource
+func (e *Executor) Make(stateChecker resource.StateChecker, operator resource.Operator, sch config.Schedule, action string, dryRun bool, m *metrics.Metrics) func() {
+	return e.MakeWithReport(stateChecker, operator, sch, action, dryRun, m, nil)
+}
+
+// MakeWithReport is like Make and additionally calls report after each run
+// with whether the action succeeded for all resources of the schedule.
+// A run without matching resources is reported as successful.
+func (e *Executor) MakeWithReport(stateChecker resource.StateChecker, operator resource.Operator, sch config.Schedule, action string, dryRun bool, m *metrics.Metrics, report func(ok bool)) func() {
+	if report == nil {
+		return e.MakeWithRunReport(stateChecker, operator, sch, action, dryRun, m, nil)
+	}
+	return e.MakeWithRunReport(stateChecker, operator, sch, action, dryRun, m, func(run RunReport) {
+		report(run.OK())
+	})
+}
 
+// MakeWithRunReport is like Make and additionally calls report after each run
+// with the outcome for every resource of the schedule. Runs that succeed for
+// some resources and fail for others are logged and counted as partial.
+func (e *Executor) MakeWithRunReport(stateChecker resource.StateChecker, operator resource.Operator, sch config.Schedule, action string, dryRun bool, m *metrics.Metrics, report func(RunReport)) func() {
+	opts := actionOptions{
+		start:   resource.StartOptionsFromSchedule(sch),
+		stop:    resource.StopOptionsFromAction(sch.Actions.Stop),
+		restart: resource.RestartOptionsFromAction(sch.Actions.Restart),
+		resize:  resource.ResizeOptionsFromAction(sch.Actions.Resize),
+	}
+	if sch.Actions.Snapshot != nil {
+		opts.retention = sch.Actions.Snapshot.Retention
+	}
+	// Schedules with several scale entries are narrowed to one entry per job
+	// with config.Schedule.ForScaleEntry.
+	if len(sch.Actions.Scale) > 0 {
+		opts.targetSize = sch.Actions.Scale[0].TargetSize
+	}
+	// Preemptible entries are narrowed the same way with
+	// config.Schedule.ForPreemptibleEntry.
+	if len(sch.Actions.Preemptible) > 0 {
+		opts.preemptible = sch.Actions.Preemptible[0].Preemptible
+	}
+	if cfg := actionConfig(sch, action); cfg != nil {
+		opts.preHook = cfg.PreHook
+		opts.postHook = cfg.PostHook
+		opts.onStateCheckError = cfg.OnStateCheckError
+		opts.retry = cfg.Retry
+		opts.timeout = cfg.Timeout.Duration
+		opts.minUptime = cfg.MinUptime.Duration
+		opts.concurrency = cfg.Concurrency
+		opts.concurrencyScope = cfg.ConcurrencyScope
+	}
 	return func() {
-		// Use a background context with a reasonable timeout for YC operations.
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
-		defer cancel()
-		resourceType := resource.Type
-		lockKey := resourceType + ":" + resource.ID + ":" + action
-
-		if !operationLocks.tryLock(lockKey) {
-			log.Info().
+		var run RunReport
+		e.execute(stateChecker, operator, sch, action, opts, dryRun, m, &run)
+		status := run.Status()
+		if status == RunPartial {
+			log.Warn().
 				Str("schedule", sch.Name).
-				Str("resource_type", resourceType).
-				Str("resource_id", resource.ID).
 				Str("action", action).
-				Msg("Operation for resource/action is already in progress, skipping")
-			if m != nil {
-				m.IncOperation(resourceType, action, "skipped")
-				m.IncSchedulerSkip(resourceType, action, "in_flight")
-			}
-			return
+				Strs("succeeded", run.Succeeded()).
+				Strs("failed", run.Failed()).
+				Msg("Action partially failed")
+		}
+		if m != nil {
+			m.IncScheduleRun(sch.Name, action, status)
 		}
-		defer operationLocks.unlock(lockKey)
+		if report != nil {
+			report(run)
+		}
+	}
+}
 
-		if dryRun {
-			log.Info().
+// execute runs the action for all resources of the schedule and adds their
+// outcomes to report.
+func (e *Executor) execute(stateChecker resource.StateChecker, operator resource.Operator, sch config.Schedule, action string, opts actionOptions, dryRun bool, m *metrics.Metrics, report *RunReport) {
+	if len(sch.Steps) > 0 {
+		e.executeSteps(stateChecker, operator, sch, action, opts, dryRun, m, report)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout(opts))
+	defer cancel()
+
+	e.executeTargets(ctx, stateChecker, operator, sch, sch.Targets(), action, opts, dryRun, m, report)
+}
+
+// executeSteps runs the action for the schedule steps one after another,
+// waiting sch.DelayBetween between them. Stop processes the steps in reverse
+// order. A failed step aborts the remaining ones.
+func (e *Executor) executeSteps(stateChecker resource.StateChecker, operator resource.Operator, sch config.Schedule, action string, opts actionOptions, dryRun bool, m *metrics.Metrics, report *RunReport) {
+	steps := slices.Clone(sch.Steps)
+	if action == "stop" {
+		slices.Reverse(steps)
+	}
+
+	for i, step := range steps {
+		// Dry runs only plan operations, so they do not wait between steps.
+		if i > 0 && sch.DelayBetween.Duration > 0 && !dryRun {
+			log.Debug().
 				Str("schedule", sch.Name).
-				Str("resource_type", resourceType).
-				Str("resource_id", resource.ID).
 				Str("action", action).
-				Msg("Dry-run: planned operation")
-			if m != nil {
-				m.IncOperation(resourceType, action, "dry_run")
-			}
-			return
+				Dur("delay", sch.DelayBetween.Duration).
+				Msg("Waiting before next schedule step")
+			time.Sleep(sch.DelayBetween.Duration)
 		}
 
-		// Validate action
-		if action != "start" && action != "stop" {
+		// Each step gets its own timeout, so delays do not eat into it.
+		ctx, cancel := context.WithTimeout(context.Background(), e.timeout(opts))
+		ok := e.executeTargets(ctx, stateChecker, operator, sch, step.Resources, action, opts, dryRun, m, report)
+		cancel()
+		if !ok {
 			log.Error().
-				Str("resource_type", resourceType).
-				Str("resource_id", resource.ID).
+				Str("schedule", sch.Name).
 				Str("action", action).
-				Msg("Unsupported action for resource")
-			if m != nil {
-				m.IncOperation(resourceType, action, "error")
-			}
+				Int("step", i+1).
+				Int("steps", len(steps)).
+				Msg("Schedule step failed, skipping remaining steps")
 			return
 		}
+	}
+}
+
+// executeTargets runs the action for the resources concurrently, at most
+// sch.EffectiveMaxParallel() at a time, adds their outcomes to report and
+// reports whether it succeeded for all of them.
+func (e *Executor) executeTargets(ctx context.Context, stateChecker resource.StateChecker, operator resource.Operator, sch config.Schedule, resources []config.Resource, action string, opts actionOptions, dryRun bool, m *metrics.Metrics, report *RunReport) bool {
+	// Name patterns are resolved on every run to pick up new resources.
+	targets, err := resource.ResolveTargets(ctx, stateChecker, resources)
+	if err != nil {
+		log.Error().Err(err).
+			Str("schedule", sch.Name).
+			Str("action", action).
+			Msg("Failed to resolve schedule resources")
+		if m != nil {
+			m.IncOperation(resources[0].Type, action, "error")
+		}
+		report.unresolved++
+		return false
+	}
+	if len(targets) == 0 {
+		log.Warn().
+			Str("schedule", sch.Name).
+			Str("action", action).
+			Msg("No resources matched the schedule, skipping")
+		return true
+	}
+
+	outcomes := make([]ResourceOutcome, len(targets))
+	if len(targets) == 1 {
+		outcomes[0] = outcome(targets[0], e.run(ctx, stateChecker, operator, sch, targets[0], action, opts, dryRun, m))
+	} else {
+		sem := make(chan struct{}, sch.EffectiveMaxParallel())
+		var wg sync.WaitGroup
+		for i, target := range targets {
+			sem <- struct{}{}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				outcomes[i] = outcome(target, e.run(ctx, stateChecker, operator, sch, target, action, opts, dryRun, m))
+			}()
+		}
+		wg.Wait()
+	}
+
+	// Metrics of stopped VMs are read once all of them are stopped, so the
+	// snapshots do not delay the stops.
+	if action == "stop" && !dryRun {
+		stoppedAt := time.Now()
+		for i := range outcomes {
+			if outcomes[i].OK {
+				outcomes[i].Utilization = e.snapshotUtilization(sch, targets[i], stoppedAt)
+			}
+		}
+	}
+
+	report.Resources = append(report.Resources, outcomes...)
+	return !slices.ContainsFunc(outcomes, func(o ResourceOutcome) bool { return !o.OK })
+}
+
+func outcome(target config.Resource, ok bool) ResourceOutcome {
+	return ResourceOutcome{Type: target.Type, ID: target.ID, OK: ok}
+}
+
+// actionOptions holds per-action settings resolved from the schedule.
+type actionOptions struct {
+	start       resource.StartOptions
+	stop        resource.StopOptions
+	restart     resource.RestartOptions
+	resize      resource.ResizeOptions
+	preemptible *bool
+	preHook     *config.HookConfig
+	postHook    *config.HookConfig
+	retry       *config.RetryConfig
+	timeout     time.Duration
+	minUptime   time.Duration
+	retention   int
+	targetSize  int
+
+	onStateCheckError string
+	concurrency       string
+	concurrencyScope  string
+}
+
+// timeout returns the timeout of the action run.
+func (e *Executor) timeout(opts actionOptions) time.Duration {
+	if opts.timeout > 0 {
+		return opts.timeout
+	}
+	if timeout := time.Duration(e.defaultTimeout.Load()); timeout > 0 {
+		return timeout
+	}
+	return fallbackTimeout
+}
 
-		// Check current state before executing operation to avoid conflicts
-		currentState, isTransitional, stateErr := stateChecker.GetState(ctx, resource)
+// actionConfig returns the configuration of action in the schedule. List
+// actions are narrowed to one entry per job, so their first entry is used.
+func actionConfig(sch config.Schedule, action string) *config.ActionConfig {
+	switch action {
+	case "start":
+		return sch.Actions.Start
+	case "stop":
+		return sch.Actions.Stop
+	case "snapshot":
+		return sch.Actions.Snapshot
+	case "restart":
+		return sch.Actions.Restart
+	case "resize":
+		return sch.Actions.Resize
+	case "scale":
+		if len(sch.Actions.Scale) > 0 {
+			return &sch.Actions.Scale[0]
+		}
+	case "preemptible":
+		if len(sch.Actions.Preemptible) > 0 {
+			return &sch.Actions.Preemptible[0]
+		}
+	}
+	return nil
+}
+
+// run executes the action for a single resource of the schedule. It reports
+// whether the resource reached the result of the action, including when it
+// already was in the desired state.
+func (e *Executor) run(ctx context.Context, stateChecker resource.StateChecker, operator resource.Operator, sch config.Schedule, resource config.Resource, action string, opts actionOptions, dryRun bool, m *metrics.Metrics) bool {
+	resourceType := resource.Type
+	record := func(status string) {
+		if m != nil {
+			m.IncOperation(resourceType, action, status)
+			m.IncResourceOperation(sch.Name, resourceType, resource.ID, action, status)
+		}
+	}
+	// Denied resources are skipped even in dry-run, so the plan shows that
+	// they are never touched. Like a resource already in the desired state,
+	// a denied resource does not fail the run.
+	if reason, ok := e.DeniedReason(sch, resource); ok {
+		log.Warn().
+			Str("schedule", sch.Name).
+			Str("resource_type", resourceType).
+			Str("resource_id", resource.ID).
+			Str("action", action).
+			Str("reason", reason).
+			Msg("Resource is on the deny list, skipping operation")
+		record("skipped")
+		if m != nil {
+			m.IncSchedulerSkip(resourceType, action, "denied")
+		}
+		return true
+	}
+
+	// Locks are per resource, so e.g. a stop never overlaps a start of the
+	// same resource from another schedule or a validator correction.
+	lockKey := resourceType + ":" + resource.ID
+
+	if holder, ok := e.operations.tryLock(lockKey, sch.Name+":"+action); !ok {
+		log.Info().
+			Str("schedule", sch.Name).
+			Str("resource_type", resourceType).
+			Str("resource_id", resource.ID).
+			Str("action", action).
+			Str("in_flight", holder).
+			Msg("Operation for resource is already in progress, skipping")
+		record("skipped")
+		if m != nil {
+			m.IncSchedulerSkip(resourceType, action, "in_flight")
+		}
+		return false
+	}
+	defer e.operations.unlock(lockKey)
+
+	if dryRun {
+		log.Info().
+			Str("schedule", sch.Name).
+			Str("resource_type", resourceType).
+			Str("resource_id", resource.ID).
+			Str("action", action).
+			Msg("Dry-run: planned operation")
+		record("dry_run")
+		return true
+	}
+
+	// Validate action
+	if action != "start" && action != "stop" && action != "snapshot" && action != "restart" && action != "scale" && action != "resize" && action != "preemptible" {
+		log.Error().
+			Str("resource_type", resourceType).
+			Str("resource_id", resource.ID).
+			Str("action", action).
+			Msg("Unsupported action for resource")
+		record("error")
+		return false
+	}
+
+	unlockScopes, err := e.lockScopes(ctx, resource, opts)
+	if err != nil {
+		log.Warn().Err(err).
+			Str("schedule", sch.Name).
+			Str("resource_type", resourceType).
+			Str("resource_id", resource.ID).
+			Str("action", action).
+			Str("concurrency", opts.concurrency).
+			Msg("Concurrency scope did not become available, skipping operation")
+		record("skipped")
+		if m != nil {
+			m.IncSchedulerSkip(resourceType, action, "lock_timeout")
+		}
+		return false
+	}
+	defer unlockScopes()
+
+	// Check current state before executing operation to avoid conflicts.
+	// Snapshots do not change the resource state and are taken in any state.
+	if action != "snapshot" {
+		currentState, isTransitional, stateErr := getState(ctx, stateChecker, resource, opts.onStateCheckError)
 		if stateErr != nil {
+			// Without a known state only the proceed policy runs the operation.
+			if opts.onStateCheckError == config.StateCheckSkip || opts.onStateCheckError == config.StateCheckRetry {
+				log.Warn().Err(stateErr).
+					Str("schedule", sch.Name).
+					Str("resource_type", resourceType).
+					Str("resource_id", resource.ID).
+					Str("action", action).
+					Str("on_state_check_error", opts.onStateCheckError).
+					Msg("Failed to get current resource state, skipping operation")
+				record("skipped")
+				if m != nil {
+					m.IncSchedulerSkip(resourceType, action, "state_check_failed")
+				}
+				return false
+			}
 			log.Warn().Err(stateErr).
 				Str("schedule", sch.Name).
 				Str("resource_type", resourceType).
@@ -118,16 +523,16 @@ func Make(stateChecker resource.StateChecker, operator resource.Operator, sch co
 					Str("action", action).
 					Str("current_state", currentState).
 					Msg("Resource is in transitional state, skipping operation")
+				record("skipped")
 				if m != nil {
-					m.IncOperation(resourceType, action, "skipped")
 					m.IncSchedulerSkip(resourceType, action, "transitional_state")
 				}
-				return
+				return false
 			}
 
 			// Skip operation if resource is already in desired state
 			if (action == "start" && currentState == "running") ||
-				(action == "stop" && currentState == "stopped") {
+				(action == "stop" && currentState == opts.stop.TargetState()) {
 				log.Info().
 					Str("schedule", sch.Name).
 					Str("resource_type", resourceType).
@@ -135,45 +540,304 @@ func Make(stateChecker resource.StateChecker, operator resource.Operator, sch co
 					Str("action", action).
 					Str("current_state", currentState).
 					Msg("Resource is already in desired state, skipping operation")
+				record("skipped")
 				if m != nil {
-					m.IncOperation(resourceType, action, "skipped")
 					m.IncSchedulerSkip(resourceType, action, "already_in_state")
 				}
-				return
+				return true
+			}
+
+			// Restart only resources that are running; a stopped resource
+			// is left for the start action.
+			if action == "restart" && currentState != "running" {
+				log.Info().
+					Str("schedule", sch.Name).
+					Str("resource_type", resourceType).
+					Str("resource_id", resource.ID).
+					Str("action", action).
+					Str("current_state", currentState).
+					Msg("Resource is not running, skipping restart")
+				record("skipped")
+				if m != nil {
+					m.IncSchedulerSkip(resourceType, action, "not_running")
+				}
+				return true
+			}
+
+			if action == "stop" && recentlyStarted(ctx, stateChecker, resource, opts.minUptime) {
+				log.Info().
+					Str("schedule", sch.Name).
+					Str("resource_type", resourceType).
+					Str("resource_id", resource.ID).
+					Str("action", action).
+					Dur("min_uptime", opts.minUptime).
+					Msg("Resource was started recently, skipping stop")
+				record("skipped")
+				if m != nil {
+					m.IncSchedulerSkip(resourceType, action, "recently_started")
+				}
+				return true
 			}
 		}
+	}
 
-		log.Debug().
-			Str("schedule", sch.Name).
+	// API calls of the operation share an execution ID for cloud-side tracing.
+	executionID := yc.NewExecutionID()
+	ctx = yc.WithExecutionID(ctx, executionID)
+
+	log.Debug().
+		Str("schedule", sch.Name).
+		Str("resource_type", resourceType).
+		Str("resource_id", resource.ID).
+		Str("action", action).
+		Str("execution_id", executionID).
+		Msg("Executing resource operation")
+
+	hookCtx := hook.NewContext(sch, resource, action, executionID)
+	if !runHook(ctx, opts.preHook, hook.PhasePre, hookCtx, m) {
+		record("skipped")
+		if m != nil {
+			m.IncSchedulerSkip(resourceType, action, "pre_hook_failed")
+		}
+		return false
+	}
+
+	ctx, operations := yc.WithOperationLog(ctx)
+	opErr := operateWithRetry(ctx, operator, resource, action, opts, executionID, m)
+	if opErr != nil {
+		log.Error().Err(opErr).
 			Str("resource_type", resourceType).
 			Str("resource_id", resource.ID).
 			Str("action", action).
-			Msg("Executing resource operation")
-
-		var opErr error
-		switch action {
-		case "start":
-			opErr = operator.Start(ctx, resource)
-		case "stop":
-			opErr = operator.Stop(ctx, resource)
-		default:
-			opErr = fmt.Errorf("unsupported action: %s", action)
+			Str("execution_id", executionID).
+			Msg("Resource operation failed")
+		if yc.IsDeadlineExceeded(opErr) {
+			record("deadline_exceeded")
+		} else {
+			record("error")
 		}
+		return false
+	}
+	e.recordLastAction(sch, resource, action, opts.stop, executionID, operations.Last())
 
-		if opErr != nil {
-			log.Error().Err(opErr).
-				Str("resource_type", resourceType).
-				Str("resource_id", resource.ID).
-				Str("action", action).
-				Msg("Resource operation failed")
-			if m != nil {
-				m.IncOperation(resourceType, action, "error")
+	if !runHook(ctx, opts.postHook, hook.PhasePost, hookCtx, m) {
+		record("error")
+		return false
+	}
+
+	record("success")
+	return true
+}
+
+// lockScopes acquires the concurrency scopes of the operation: the global
+// scope and the folder of the target, in shared mode or, for exclusive
+// actions, the configured scope in exclusive mode. It waits until the scopes
+// are available or ctx is done and returns the function releasing them.
+func (e *Executor) lockScopes(ctx context.Context, target config.Resource, opts actionOptions) (func(), error) {
+	exclusive := opts.concurrency == config.ConcurrencyExclusive
+	global := exclusive && opts.concurrencyScope == config.ConcurrencyScopeGlobal
+	if exclusive {
+		log.Debug().
+			Str("resource_type", target.Type).
+			Str("resource_id", target.ID).
+			Str("folder_id", target.FolderID).
+			Bool("global", global).
+			Msg("Waiting for exclusive concurrency scope")
+	}
+
+	// Scopes are always locked global first, so waiters cannot deadlock.
+	if err := e.concurrency.lock(ctx, globalScope, global); err != nil {
+		return nil, err
+	}
+	if global {
+		return func() { e.concurrency.unlock(globalScope, true) }, nil
+	}
+
+	folder := folderScopePrefix + target.FolderID
+	if err := e.concurrency.lock(ctx, folder, exclusive); err != nil {
+		e.concurrency.unlock(globalScope, false)
+		return nil, err
+	}
+	return func() {
+		e.concurrency.unlock(folder, exclusive)
+		e.concurrency.unlock(globalScope, false)
+	}, nil
+}
+
+// operate runs the operation of action for the target.
+func operate(ctx context.Context, operator resource.Operator, target config.Resource, action string, opts actionOptions, m *metrics.Metrics) error {
+	switch action {
+	case "start":
+		return operator.Start(ctx, target, opts.start)
+	case "stop":
+		return operator.Stop(ctx, target, opts.stop)
+	case "snapshot":
+		return operator.Snapshot(ctx, target, opts.retention)
+	case "restart":
+		return restart(ctx, operator, target, opts.restart, m)
+	case "scale":
+		return operator.Scale(ctx, target, opts.targetSize)
+	case "resize":
+		return operator.Resize(ctx, target, opts.resize)
+	case "preemptible":
+		return setPreemptible(ctx, operator, target, opts.preemptible)
+	default:
+		return fmt.Errorf("unsupported action: %s", action)
+	}
+}
+
+// operateWithRetry runs the operation and, if the action has a retry block,
+// repeats it after transient API errors with exponential backoff while the
+// job context is not done. Every attempt is counted in the attempts metric.
+func operateWithRetry(ctx context.Context, operator resource.Operator, target config.Resource, action string, opts actionOptions, executionID string, m *metrics.Metrics) error {
+	for attempt := 1; ; attempt++ {
+		err := operate(ctx, operator, target, action, opts, m)
+		if m != nil {
+			status := "success"
+			if err != nil {
+				status = "error"
 			}
-			return
+			m.IncOperationAttempt(target.Type, action, attempt, status)
+		}
+		if err == nil || opts.retry == nil || attempt > opts.retry.EffectiveRetries() || !yc.IsTransient(err) {
+			return err
 		}
 
-		if m != nil {
-			m.IncOperation(resourceType, action, "success")
+		delay := opts.retry.Delay(attempt)
+		log.Warn().Err(err).
+			Str("resource_type", target.Type).
+			Str("resource_id", target.ID).
+			Str("action", action).
+			Str("execution_id", executionID).
+			Int("attempt", attempt).
+			Dur("delay", delay).
+			Msg("Resource operation failed with transient error, retrying")
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// runHook runs the hook if it is configured and reports whether the action
+// may proceed: the hook succeeded or its failure policy is continue.
+func runHook(ctx context.Context, cfg *config.HookConfig, phase string, hc hook.Context, m *metrics.Metrics) bool {
+	if cfg == nil {
+		return true
+	}
+
+	err := hook.Run(ctx, cfg, phase, hc)
+	status := "success"
+	if err != nil {
+		status = "error"
+	}
+	if m != nil {
+		m.IncHookRun(hc.Action, phase, status)
+	}
+	if err == nil {
+		log.Debug().
+			Str("schedule", hc.Schedule).
+			Str("resource_type", hc.ResourceType).
+			Str("resource_id", hc.ResourceID).
+			Str("action", hc.Action).
+			Str("phase", phase).
+			Str("execution_id", hc.ExecutionID).
+			Msg("Action hook succeeded")
+		return true
+	}
+
+	log.Error().Err(err).
+		Str("schedule", hc.Schedule).
+		Str("resource_type", hc.ResourceType).
+		Str("resource_id", hc.ResourceID).
+		Str("action", hc.Action).
+		Str("phase", phase).
+		Str("execution_id", hc.ExecutionID).
+		Bool("abort", cfg.AbortsOnFailure()).
+		Msg("Action hook failed")
+	return !cfg.AbortsOnFailure()
+}
+
+// getState reads the resource state. With the retry policy, failed reads are
+// repeated with exponential backoff, at most stateCheckAttempts times.
+func getState(ctx context.Context, stateChecker resource.StateChecker, target config.Resource, policy string) (string, bool, error) {
+	state, transitional, err := stateChecker.GetState(ctx, target)
+	if err == nil || policy != config.StateCheckRetry {
+		return state, transitional, err
+	}
+
+	delay := stateCheckRetryDelay
+	for attempt := 2; attempt <= stateCheckAttempts; attempt++ {
+		log.Debug().Err(err).
+			Str("resource_type", target.Type).
+			Str("resource_id", target.ID).
+			Int("attempt", attempt).
+			Dur("delay", delay).
+			Msg("Retrying resource state check")
+		select {
+		case <-ctx.Done():
+			return "", false, err
+		case <-time.After(delay):
+		}
+		delay *= 2
+
+		state, transitional, err = stateChecker.GetState(ctx, target)
+		if err == nil {
+			return state, transitional, nil
+		}
+	}
+	return "", false, err
+}
+
+// recentlyStarted reports whether the target was started less than
+// minUptime ago. Resources whose start time cannot be read are treated as
+// started long ago.
+func recentlyStarted(ctx context.Context, stateChecker resource.StateChecker, target config.Resource, minUptime time.Duration) bool {
+	reader, ok := stateChecker.(resource.StartTimeReader)
+	if minUptime <= 0 || !ok {
+		return false
+	}
+
+	startedAt, err := reader.GetStartTime(ctx, target)
+	if err != nil {
+		log.Warn().Err(err).
+			Str("resource_type", target.Type).
+			Str("resource_id", target.ID).
+			Msg("Failed to get resource start time, ignoring min_uptime")
+		return false
+	}
+	return !startedAt.IsZero() && time.Since(startedAt) < minUptime
+}
+
+// setPreemptible switches the scheduling policy of the target to the
+// configured value.
+func setPreemptible(ctx context.Context, operator resource.Operator, target config.Resource, preemptible *bool) error {
+	if preemptible == nil {
+		return resource.ErrPreemptibleMissing
+	}
+	return operator.SetPreemptible(ctx, target, *preemptible)
+}
+
+// restart runs the restart operation and records its duration and the
+// failed phase.
+func restart(ctx context.Context, operator resource.Operator, target config.Resource, opts resource.RestartOptions, m *metrics.Metrics) error {
+	started := time.Now()
+	err := operator.Restart(ctx, target, opts)
+	if m == nil {
+		return err
+	}
+
+	if err != nil {
+		phase := "unknown"
+		var restartErr *resource.RestartError
+		if errors.As(err, &restartErr) {
+			phase = restartErr.Phase
 		}
+		m.IncRestartFailure(target.Type, phase)
+		m.ObserveRestart(target.Type, "error", time.Since(started))
+		return err
 	}
+	m.ObserveRestart(target.Type, "success", time.Since(started))
+	return nil
 }
@@ -2,11 +2,19 @@ package executor
 
 import (
 	"context"
+	"errors"
+	"slices"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/denylist"
+	"github.com/sentoz/yc-sheduler/internal/resource"
 )
 
 type lockTestStateChecker struct{}
@@ -16,11 +24,12 @@ func (lockTestStateChecker) GetState(context.Context, config.Resource) (string,
 }
 
 type lockTestOperator struct {
-	mu         sync.Mutex
-	startCalls int
+	mu                sync.Mutex
+	startCalls        int
+	snapshotRetention []int
 }
 
-func (o *lockTestOperator) Start(context.Context, config.Resource) error {
+func (o *lockTestOperator) Start(context.Context, config.Resource, resource.StartOptions) error {
 	time.Sleep(120 * time.Millisecond)
 	o.mu.Lock()
 	o.startCalls++
@@ -28,7 +37,30 @@ func (o *lockTestOperator) Start(context.Context, config.Resource) error {
 	return nil
 }
 
-func (o *lockTestOperator) Stop(context.Context, config.Resource) error {
+func (o *lockTestOperator) Stop(context.Context, config.Resource, resource.StopOptions) error {
+	return nil
+}
+
+func (o *lockTestOperator) Snapshot(_ context.Context, _ config.Resource, retention int) error {
+	o.mu.Lock()
+	o.snapshotRetention = append(o.snapshotRetention, retention)
+	o.mu.Unlock()
+	return nil
+}
+
+func (o *lockTestOperator) Restart(context.Context, config.Resource, resource.RestartOptions) error {
+	return nil
+}
+
+func (o *lockTestOperator) Scale(context.Context, config.Resource, int) error {
+	return nil
+}
+
+func (o *lockTestOperator) Resize(context.Context, config.Resource, resource.ResizeOptions) error {
+	return nil
+}
+
+func (o *lockTestOperator) SetPreemptible(context.Context, config.Resource, bool) error {
 	return nil
 }
 
@@ -41,8 +73,6 @@ func (o *lockTestOperator) calls() int {
 func TestMake_SkipsWhenSameResourceActionAlreadyInFlight(t *testing.T) {
 	t.Parallel()
 
-	operationLocks = newInFlightLocks()
-
 	sch := config.Schedule{
 		Name: "vm-start",
 		Type: "daily",
@@ -59,7 +89,7 @@ func TestMake_SkipsWhenSameResourceActionAlreadyInFlight(t *testing.T) {
 	checker := lockTestStateChecker{}
 	op := &lockTestOperator{}
 
-	job := Make(checker, op, sch, "start", false, nil)
+	job := New().Make(checker, op, sch, "start", false, nil)
 
 	firstDone := make(chan struct{})
 	go func() {
@@ -80,3 +110,710 @@ func TestMake_SkipsWhenSameResourceActionAlreadyInFlight(t *testing.T) {
 		t.Fatalf("operator start calls = %d, want 1", got)
 	}
 }
+
+func TestMake_SkipsOtherActionWhileResourceInFlight(t *testing.T) {
+	t.Parallel()
+
+	exec := New()
+
+	target := config.Resource{Type: "vm", ID: "vm-shared", FolderID: "folder-1"}
+	starter := config.Schedule{
+		Name:     "vm-start",
+		Type:     "daily",
+		Resource: target,
+		Actions:  config.Actions{Start: &config.ActionConfig{Enabled: true, Time: "09:00"}},
+	}
+	stopper := config.Schedule{
+		Name:     "vm-stop",
+		Type:     "daily",
+		Resource: target,
+		Actions:  config.Actions{Stop: &config.ActionConfig{Enabled: true, Time: "09:00"}},
+	}
+
+	startOp := &lockTestOperator{}
+	firstDone := make(chan struct{})
+	go func() {
+		defer close(firstDone)
+		exec.Make(lockTestStateChecker{}, startOp, starter, "start", false, nil)()
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	stopOp := &countingOperator{}
+	ok := true
+	exec.MakeWithReport(runningStateChecker{}, stopOp, stopper, "stop", false, nil, func(result bool) { ok = result })()
+	<-firstDone
+
+	if len(stopOp.stopped) != 0 || ok {
+		t.Fatalf("stop calls = %v, reported %v; want stop skipped while start is in flight", stopOp.stopped, ok)
+	}
+	if got := startOp.calls(); got != 1 {
+		t.Fatalf("operator start calls = %d, want 1", got)
+	}
+}
+
+type transitionalStateChecker struct{}
+
+func (transitionalStateChecker) GetState(context.Context, config.Resource) (string, bool, error) {
+	return "starting", true, nil
+}
+
+func TestMake_SnapshotIgnoresResourceStateAndPassesRetention(t *testing.T) {
+	t.Parallel()
+
+	sch := config.Schedule{
+		Name: "vm-snapshot",
+		Type: "daily",
+		Resource: config.Resource{
+			Type:     "vm",
+			ID:       "vm-snapshot-1",
+			FolderID: "folder-1",
+		},
+		Actions: config.Actions{
+			Snapshot: &config.ActionConfig{Enabled: true, Time: "03:00", Retention: 7},
+		},
+	}
+
+	op := &lockTestOperator{}
+	New().Make(transitionalStateChecker{}, op, sch, "snapshot", false, nil)()
+
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	if len(op.snapshotRetention) != 1 || op.snapshotRetention[0] != 7 {
+		t.Fatalf("operator snapshot calls = %v, want [7]", op.snapshotRetention)
+	}
+}
+
+type countingOperator struct {
+	mu          sync.Mutex
+	stopped     []string
+	restarted   []string
+	targetSizes []int
+	resized     []resource.ResizeOptions
+	preemptible []bool
+}
+
+func (o *countingOperator) Start(context.Context, config.Resource, resource.StartOptions) error {
+	return nil
+}
+
+func (o *countingOperator) Stop(_ context.Context, res config.Resource, _ resource.StopOptions) error {
+	o.mu.Lock()
+	o.stopped = append(o.stopped, res.ID)
+	o.mu.Unlock()
+	return nil
+}
+
+func (o *countingOperator) Snapshot(context.Context, config.Resource, int) error {
+	return nil
+}
+
+func (o *countingOperator) Scale(_ context.Context, _ config.Resource, targetSize int) error {
+	o.mu.Lock()
+	o.targetSizes = append(o.targetSizes, targetSize)
+	o.mu.Unlock()
+	return nil
+}
+
+func (o *countingOperator) Restart(_ context.Context, res config.Resource, _ resource.RestartOptions) error {
+	o.mu.Lock()
+	o.restarted = append(o.restarted, res.ID)
+	o.mu.Unlock()
+	return nil
+}
+
+func (o *countingOperator) Resize(_ context.Context, _ config.Resource, opts resource.ResizeOptions) error {
+	o.mu.Lock()
+	o.resized = append(o.resized, opts)
+	o.mu.Unlock()
+	return nil
+}
+
+func (o *countingOperator) SetPreemptible(_ context.Context, _ config.Resource, preemptible bool) error {
+	o.mu.Lock()
+	o.preemptible = append(o.preemptible, preemptible)
+	o.mu.Unlock()
+	return nil
+}
+
+type runningStateChecker struct{}
+
+func (runningStateChecker) GetState(context.Context, config.Resource) (string, bool, error) {
+	return "running", false, nil
+}
+
+func TestMake_FansOutToAllScheduleResources(t *testing.T) {
+	t.Parallel()
+
+	sch := config.Schedule{
+		Name:        "vm-group-stop",
+		Type:        "daily",
+		MaxParallel: 2,
+		Actions: config.Actions{
+			Stop: &config.ActionConfig{Enabled: true, Time: "20:00"},
+		},
+	}
+	for _, id := range []string{"vm-group-1", "vm-group-2", "vm-group-3"} {
+		sch.Resources = append(sch.Resources, config.Resource{Type: "vm", ID: id, FolderID: "folder-1"})
+	}
+
+	op := &countingOperator{}
+	New().Make(runningStateChecker{}, op, sch, "stop", false, nil)()
+
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	if len(op.stopped) != 3 {
+		t.Fatalf("operator stop calls = %v, want all 3 resources", op.stopped)
+	}
+}
+
+func TestMake_SkipsDeniedResources(t *testing.T) {
+	t.Parallel()
+
+	list := denylist.New()
+	list.Add(denylist.Entry{ID: "vm-denied-global", Source: denylist.SourceAPI})
+	exec := New()
+	exec.SetDenyList(list)
+
+	sch := config.Schedule{
+		Name:              "vm-denied-stop",
+		Type:              "daily",
+		DeniedResourceIDs: []string{"vm-denied-schedule"},
+		Actions: config.Actions{
+			Stop: &config.ActionConfig{Enabled: true, Time: "20:00"},
+		},
+	}
+	for _, id := range []string{"vm-denied-global", "vm-denied-schedule", "vm-denied-other"} {
+		sch.Resources = append(sch.Resources, config.Resource{Type: "vm", ID: id, FolderID: "folder-1"})
+	}
+
+	var results []bool
+	op := &countingOperator{}
+	exec.MakeWithReport(runningStateChecker{}, op, sch, "stop", false, nil, func(ok bool) { results = append(results, ok) })()
+
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	if !slices.Equal(op.stopped, []string{"vm-denied-other"}) {
+		t.Fatalf("operator stop calls = %v, want only vm-denied-other", op.stopped)
+	}
+	if len(results) != 1 || !results[0] {
+		t.Fatalf("reported results = %v, want [true] for a run with denied resources", results)
+	}
+}
+
+func TestMake_RestartsRunningResource(t *testing.T) {
+	t.Parallel()
+
+	sch := config.Schedule{
+		Name:     "vm-restart",
+		Type:     "daily",
+		Resource: config.Resource{Type: "vm", ID: "vm-restart-running", FolderID: "folder-1"},
+		Actions: config.Actions{
+			Restart: &config.ActionConfig{Enabled: true, Time: "04:00"},
+		},
+	}
+
+	op := &countingOperator{}
+	New().Make(runningStateChecker{}, op, sch, "restart", false, nil)()
+
+	if len(op.restarted) != 1 || op.restarted[0] != "vm-restart-running" {
+		t.Fatalf("operator restart calls = %v, want [vm-restart-running]", op.restarted)
+	}
+}
+
+func TestMake_SkipsRestartOfStoppedResource(t *testing.T) {
+	t.Parallel()
+
+	sch := config.Schedule{
+		Name:     "vm-restart",
+		Type:     "daily",
+		Resource: config.Resource{Type: "vm", ID: "vm-restart-stopped", FolderID: "folder-1"},
+		Actions: config.Actions{
+			Restart: &config.ActionConfig{Enabled: true, Time: "04:00"},
+		},
+	}
+
+	op := &countingOperator{}
+	New().Make(lockTestStateChecker{}, op, sch, "restart", false, nil)()
+
+	if len(op.restarted) != 0 {
+		t.Fatalf("operator restart calls = %v, want none for stopped resource", op.restarted)
+	}
+}
+
+func TestMake_ScalesToEntryTargetSize(t *testing.T) {
+	t.Parallel()
+
+	sch := config.Schedule{
+		Name:     "pool-scale",
+		Type:     "daily",
+		Resource: config.Resource{Type: "k8s_node_group", ID: "pool-1", FolderID: "folder-1"},
+		Actions: config.Actions{
+			Scale: []config.ActionConfig{
+				{Enabled: true, Time: "08:00", TargetSize: 5},
+				{Enabled: true, Time: "20:00", TargetSize: 1},
+			},
+		},
+	}
+
+	op := &countingOperator{}
+	New().Make(runningStateChecker{}, op, sch.ForScaleEntry(1), "scale", false, nil)()
+
+	if len(op.targetSizes) != 1 || op.targetSizes[0] != 1 {
+		t.Fatalf("operator scale calls = %v, want [1]", op.targetSizes)
+	}
+}
+
+func TestMake_ResizesWithActionSpec(t *testing.T) {
+	t.Parallel()
+
+	sch := config.Schedule{
+		Name:     "staging-night",
+		Type:     "daily",
+		Resource: config.Resource{Type: "vm", ID: "vm-staging", FolderID: "folder-1"},
+		Actions: config.Actions{
+			Resize: &config.ActionConfig{Enabled: true, Time: "21:00", Cores: 2, CoreFraction: 20, MemoryGB: 2},
+		},
+	}
+
+	op := &countingOperator{}
+	New().Make(runningStateChecker{}, op, sch, "resize", false, nil)()
+
+	want := resource.ResizeOptions{Cores: 2, CoreFraction: 20, MemoryGB: 2}
+	if len(op.resized) != 1 || op.resized[0] != want {
+		t.Fatalf("operator resize calls = %+v, want [%+v]", op.resized, want)
+	}
+}
+
+func TestMake_SetsPreemptibleFromEntry(t *testing.T) {
+	t.Parallel()
+
+	on, off := true, false
+	sch := config.Schedule{
+		Name:     "batch-night",
+		Type:     "daily",
+		Resource: config.Resource{Type: "vm", ID: "vm-batch", FolderID: "folder-1"},
+		Actions: config.Actions{
+			Preemptible: []config.ActionConfig{
+				{Enabled: true, Time: "20:00", Preemptible: &on},
+				{Enabled: true, Time: "08:00", Preemptible: &off},
+			},
+		},
+	}
+
+	op := &countingOperator{}
+	New().Make(runningStateChecker{}, op, sch.ForPreemptibleEntry(0), "preemptible", false, nil)()
+
+	if len(op.preemptible) != 1 || !op.preemptible[0] {
+		t.Fatalf("operator preemptible calls = %v, want [true]", op.preemptible)
+	}
+}
+
+type failingStopOperator struct {
+	countingOperator
+}
+
+func (o *failingStopOperator) Stop(context.Context, config.Resource, resource.StopOptions) error {
+	return errors.New("stop failed")
+}
+
+func TestMakeWithReport_ReportsActionResult(t *testing.T) {
+	t.Parallel()
+
+	sch := config.Schedule{
+		Name:     "vm-report",
+		Type:     "daily",
+		Resource: config.Resource{Type: "vm", ID: "vm-report", FolderID: "folder-1"},
+		Actions: config.Actions{
+			Stop: &config.ActionConfig{Enabled: true, Time: "20:00"},
+		},
+	}
+
+	var results []bool
+	report := func(ok bool) { results = append(results, ok) }
+	New().MakeWithReport(runningStateChecker{}, &failingStopOperator{}, sch, "stop", false, nil, report)()
+	New().MakeWithReport(lockTestStateChecker{}, &failingStopOperator{}, sch, "stop", false, nil, report)()
+
+	if len(results) != 2 || results[0] || !results[1] {
+		t.Fatalf("reported results = %v, want [false true] for failed and already stopped runs", results)
+	}
+}
+
+func TestMake_RecordsLastSuccessfulAction(t *testing.T) {
+	t.Parallel()
+
+	store, err := resource.NewActionStore("")
+	if err != nil {
+		t.Fatalf("NewActionStore() error = %v", err)
+	}
+	exec := New()
+	exec.SetLastActions(store)
+
+	stopped := config.Resource{Type: "vm", ID: "vm-last-action", FolderID: "folder-1"}
+	failed := config.Resource{Type: "vm", ID: "vm-last-action-failed", FolderID: "folder-1"}
+	sch := config.Schedule{
+		Name:     "vm-last-action",
+		Type:     "daily",
+		Resource: stopped,
+		Actions: config.Actions{
+			Stop: &config.ActionConfig{Enabled: true, Time: "20:00"},
+		},
+	}
+	exec.Make(runningStateChecker{}, &countingOperator{}, sch, "stop", false, nil)()
+	sch.Resource = failed
+	exec.Make(runningStateChecker{}, &failingStopOperator{}, sch, "stop", false, nil)()
+
+	last, ok := store.LastAction(stopped)
+	if !ok || last.Schedule != "vm-last-action" || last.Action != "stop" || last.ExecutionID == "" {
+		t.Fatalf("LastAction() = %+v, %v; want the stop of vm-last-action", last, ok)
+	}
+	if last, ok := store.LastAction(failed); ok {
+		t.Fatalf("LastAction() of failed stop = %+v, want none", last)
+	}
+}
+
+type pausedStateChecker struct{}
+
+func (pausedStateChecker) GetState(context.Context, config.Resource) (string, bool, error) {
+	return "paused", false, nil
+}
+
+func TestMake_PauseStopsUntilResourceIsPaused(t *testing.T) {
+	t.Parallel()
+
+	sch := config.Schedule{
+		Name:     "group-pause",
+		Type:     "daily",
+		Resource: config.Resource{Type: "instance_group", ID: "cl1-pause", FolderID: "folder-1"},
+		Actions: config.Actions{
+			Stop: &config.ActionConfig{Enabled: true, Time: "20:00", StopMode: config.StopModePause},
+		},
+	}
+
+	op := &countingOperator{}
+	New().Make(pausedStateChecker{}, op, sch, "stop", false, nil)()
+	if len(op.stopped) != 0 {
+		t.Fatalf("operator stop calls = %v, want none for paused resource", op.stopped)
+	}
+
+	// A stopped resource is not in the paused state the stop leaves it in.
+	New().Make(lockTestStateChecker{}, op, sch, "stop", false, nil)()
+	if len(op.stopped) != 1 || op.stopped[0] != "cl1-pause" {
+		t.Fatalf("operator stop calls = %v, want [cl1-pause]", op.stopped)
+	}
+}
+
+func TestMake_StopsStepsInReverseOrder(t *testing.T) {
+	t.Parallel()
+
+	sch := config.Schedule{
+		Name: "vm-steps",
+		Type: "daily",
+		Actions: config.Actions{
+			Stop: &config.ActionConfig{Enabled: true, Time: "20:00"},
+		},
+	}
+	for _, id := range []string{"vm-steps-db", "vm-steps-api", "vm-steps-web"} {
+		sch.Steps = append(sch.Steps, config.ScheduleStep{
+			Resources: []config.Resource{{Type: "vm", ID: id, FolderID: "folder-1"}},
+		})
+	}
+
+	op := &countingOperator{}
+	New().Make(runningStateChecker{}, op, sch, "stop", false, nil)()
+
+	want := []string{"vm-steps-web", "vm-steps-api", "vm-steps-db"}
+	if !slices.Equal(op.stopped, want) {
+		t.Fatalf("operator stop calls = %v, want %v", op.stopped, want)
+	}
+}
+
+func TestMake_PreHookFailurePolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		onFailure string
+		wantStops int
+	}{
+		{name: "abort skips operation", onFailure: config.HookAbort},
+		{name: "continue runs operation", onFailure: config.HookContinue, wantStops: 1},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			id := "vm-hook-" + strconv.Itoa(i)
+			sch := config.Schedule{
+				Name:     "vm-hook",
+				Type:     "daily",
+				Resource: config.Resource{Type: "vm", ID: id, FolderID: "folder-1"},
+				Actions: config.Actions{
+					Stop: &config.ActionConfig{
+						Enabled: true,
+						Time:    "20:00",
+						PreHook: &config.HookConfig{Command: []string{"false"}, OnFailure: tt.onFailure},
+					},
+				},
+			}
+
+			op := &countingOperator{}
+			var ok bool
+			New().MakeWithReport(runningStateChecker{}, op, sch, "stop", false, nil, func(result bool) { ok = result })()
+
+			if len(op.stopped) != tt.wantStops {
+				t.Fatalf("operator stop calls = %v, want %d", op.stopped, tt.wantStops)
+			}
+			if ok != (tt.wantStops == 1) {
+				t.Fatalf("reported result = %v, want %v", ok, tt.wantStops == 1)
+			}
+		})
+	}
+}
+
+type flakyStateChecker struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+}
+
+func (c *flakyStateChecker) GetState(context.Context, config.Resource) (string, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	if c.calls <= c.failures {
+		return "", false, errors.New("state unavailable")
+	}
+	return "running", false, nil
+}
+
+func TestMake_StateCheckErrorPolicy(t *testing.T) {
+	stateCheckRetryDelay = time.Millisecond
+	t.Cleanup(func() { stateCheckRetryDelay = 5 * time.Second })
+
+	tests := []struct {
+		name      string
+		policy    string
+		failures  int
+		wantStops int
+		wantCalls int
+	}{
+		{name: "proceed runs operation", policy: "", failures: 1, wantStops: 1, wantCalls: 1},
+		{name: "skip skips operation", policy: config.StateCheckSkip, failures: 1, wantCalls: 1},
+		{name: "retry runs operation after recovery", policy: config.StateCheckRetry, failures: 2, wantStops: 1, wantCalls: 3},
+		{name: "retry skips operation after attempts", policy: config.StateCheckRetry, failures: stateCheckAttempts, wantCalls: stateCheckAttempts},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch := config.Schedule{
+				Name:     "vm-state-check",
+				Type:     "daily",
+				Resource: config.Resource{Type: "vm", ID: "vm-state-check-" + strconv.Itoa(i), FolderID: "folder-1"},
+				Actions: config.Actions{
+					Stop: &config.ActionConfig{Enabled: true, Time: "20:00", OnStateCheckError: tt.policy},
+				},
+			}
+
+			checker := &flakyStateChecker{failures: tt.failures}
+			op := &countingOperator{}
+			var ok bool
+			New().MakeWithReport(checker, op, sch, "stop", false, nil, func(result bool) { ok = result })()
+
+			if len(op.stopped) != tt.wantStops {
+				t.Fatalf("operator stop calls = %v, want %d", op.stopped, tt.wantStops)
+			}
+			if checker.calls != tt.wantCalls {
+				t.Fatalf("state checks = %d, want %d", checker.calls, tt.wantCalls)
+			}
+			if ok != (tt.wantStops == 1) {
+				t.Fatalf("reported result = %v, want %v", ok, tt.wantStops == 1)
+			}
+		})
+	}
+}
+
+type flakyStopOperator struct {
+	countingOperator
+	err      error
+	failures int
+	attempts int
+}
+
+func (o *flakyStopOperator) Stop(ctx context.Context, res config.Resource, opts resource.StopOptions) error {
+	o.attempts++
+	if o.attempts <= o.failures {
+		return o.err
+	}
+	return o.countingOperator.Stop(ctx, res, opts)
+}
+
+func TestMake_RetriesTransientOperationErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		err          error
+		failures     int
+		wantAttempts int
+		wantOK       bool
+	}{
+		{name: "transient error is retried", err: status.Error(codes.Unavailable, "unavailable"), failures: 2, wantAttempts: 3, wantOK: true},
+		{name: "retries are limited", err: status.Error(codes.Unavailable, "unavailable"), failures: 5, wantAttempts: 3},
+		{name: "permanent error is not retried", err: status.Error(codes.PermissionDenied, "denied"), failures: 1, wantAttempts: 1},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sch := config.Schedule{
+				Name:     "vm-retry",
+				Type:     "daily",
+				Resource: config.Resource{Type: "vm", ID: "vm-retry-" + strconv.Itoa(i), FolderID: "folder-1"},
+				Actions: config.Actions{
+					Stop: &config.ActionConfig{
+						Enabled: true,
+						Time:    "20:00",
+						Retry:   &config.RetryConfig{Retries: 2, Backoff: config.Duration{Duration: time.Millisecond}},
+					},
+				},
+			}
+
+			op := &flakyStopOperator{err: tt.err, failures: tt.failures}
+			var ok bool
+			New().MakeWithReport(runningStateChecker{}, op, sch, "stop", false, nil, func(result bool) { ok = result })()
+
+			if op.attempts != tt.wantAttempts {
+				t.Fatalf("stop attempts = %d, want %d", op.attempts, tt.wantAttempts)
+			}
+			if ok != tt.wantOK {
+				t.Fatalf("reported result = %v, want %v", ok, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestMake_ExclusiveActionWaitsForFolderOperations(t *testing.T) {
+	t.Parallel()
+
+	exec := New()
+
+	nodeGroup := config.Schedule{
+		Name:     "ng-start",
+		Type:     "daily",
+		Resource: config.Resource{Type: "k8s_node_group", ID: "ng-exclusive", FolderID: "folder-exclusive"},
+		Actions:  config.Actions{Start: &config.ActionConfig{Enabled: true, Time: "09:00"}},
+	}
+	cluster := config.Schedule{
+		Name:     "cluster-stop",
+		Type:     "daily",
+		Resource: config.Resource{Type: "k8s_cluster", ID: "cluster-exclusive", FolderID: "folder-exclusive"},
+		Actions: config.Actions{
+			Stop: &config.ActionConfig{Enabled: true, Time: "09:00", Concurrency: config.ConcurrencyExclusive},
+		},
+	}
+
+	startOp := &lockTestOperator{}
+	firstDone := make(chan struct{})
+	go func() {
+		defer close(firstDone)
+		exec.Make(lockTestStateChecker{}, startOp, nodeGroup, "start", false, nil)()
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	stopOp := &countingOperator{}
+	exec.Make(runningStateChecker{}, stopOp, cluster, "stop", false, nil)()
+
+	if got := startOp.calls(); got != 1 {
+		t.Fatalf("node group start calls when cluster stop finished = %d, want 1", got)
+	}
+	if len(stopOp.stopped) != 1 {
+		t.Fatalf("cluster stop calls = %v, want 1", stopOp.stopped)
+	}
+	<-firstDone
+}
+
+type blockingStopOperator struct {
+	countingOperator
+}
+
+func (o *blockingStopOperator) Stop(ctx context.Context, _ config.Resource, _ resource.StopOptions) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestMake_UsesActionTimeout(t *testing.T) {
+	t.Parallel()
+
+	sch := config.Schedule{
+		Name:     "cluster-timeout",
+		Type:     "daily",
+		Resource: config.Resource{Type: "k8s_cluster", ID: "cluster-timeout", FolderID: "folder-1"},
+		Actions: config.Actions{
+			Stop: &config.ActionConfig{Enabled: true, Time: "20:00", Timeout: config.Duration{Duration: 50 * time.Millisecond}},
+		},
+	}
+
+	done := make(chan bool, 1)
+	go New().MakeWithReport(runningStateChecker{}, &blockingStopOperator{}, sch, "stop", false, nil, func(ok bool) { done <- ok })()
+
+	select {
+	case ok := <-done:
+		if ok {
+			t.Fatal("reported result = true, want false after timeout")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("action did not time out after its configured timeout")
+	}
+}
+
+type startedStateChecker struct {
+	runningStateChecker
+	startedAt time.Time
+}
+
+func (c startedStateChecker) GetStartTime(context.Context, config.Resource) (time.Time, error) {
+	return c.startedAt, nil
+}
+
+func TestMake_SkipsStopWithinMinUptime(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		startedAt time.Time
+		wantStops int
+	}{
+		{name: "recently started", startedAt: time.Now().Add(-10 * time.Minute)},
+		{name: "started before min uptime", startedAt: time.Now().Add(-2 * time.Hour), wantStops: 1},
+		{name: "unknown start time", wantStops: 1},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sch := config.Schedule{
+				Name:     "vm-min-uptime",
+				Type:     "daily",
+				Resource: config.Resource{Type: "vm", ID: "vm-min-uptime-" + strconv.Itoa(i), FolderID: "folder-1"},
+				Actions: config.Actions{
+					Stop: &config.ActionConfig{Enabled: true, Time: "22:00", MinUptime: config.Duration{Duration: time.Hour}},
+				},
+			}
+
+			op := &countingOperator{}
+			var ok bool
+			New().MakeWithReport(startedStateChecker{startedAt: tt.startedAt}, op, sch, "stop", false, nil, func(result bool) { ok = result })()
+
+			if len(op.stopped) != tt.wantStops {
+				t.Fatalf("operator stop calls = %v, want %d", op.stopped, tt.wantStops)
+			}
+			// A min_uptime skip is deliberate, not a failed run.
+			if !ok {
+				t.Fatal("report = false, want true")
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package executor
+
+import (
+	"context"
+	"sync"
+)
+
+// Concurrency scope names.
+const (
+	globalScope       = "global"
+	folderScopePrefix = "folder:"
+)
+
+// scopeLocks are shared/exclusive locks of lock scopes such as a folder.
+// Operations of shared actions hold their scopes in shared mode and run
+// concurrently; an exclusive action waits until no other operation holds its
+// scope. Waiting exclusive holders block new shared holders, so they are not
+// starved by a stream of shared operations.
+type scopeLocks struct {
+	scopes map[string]*scopeState
+	// changed is closed and replaced whenever a scope is released.
+	changed chan struct{}
+	mu      sync.Mutex
+}
+
+type scopeState struct {
+	shared         int
+	exclusive      bool
+	waitingWriters int
+}
+
+func newScopeLocks() *scopeLocks {
+	return &scopeLocks{
+		scopes:  make(map[string]*scopeState),
+		changed: make(chan struct{}),
+	}
+}
+
+// lock acquires the scope in shared or exclusive mode, waiting until it is
+// available or ctx is done.
+func (l *scopeLocks) lock(ctx context.Context, scope string, exclusive bool) error {
+	waiting := false
+	for {
+		l.mu.Lock()
+		st := l.scopes[scope]
+		if st == nil {
+			st = &scopeState{}
+			l.scopes[scope] = st
+		}
+		if exclusive {
+			if !st.exclusive && st.shared == 0 {
+				st.exclusive = true
+				if waiting {
+					st.waitingWriters--
+				}
+				l.mu.Unlock()
+				return nil
+			}
+			if !waiting {
+				st.waitingWriters++
+				waiting = true
+			}
+		} else if !st.exclusive && st.waitingWriters == 0 {
+			st.shared++
+			l.mu.Unlock()
+			return nil
+		}
+		changed := l.changed
+		l.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			if waiting {
+				l.mu.Lock()
+				st.waitingWriters--
+				l.release(scope, st)
+				l.mu.Unlock()
+			}
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// unlock releases the scope acquired by lock in the same mode.
+func (l *scopeLocks) unlock(scope string, exclusive bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	st := l.scopes[scope]
+	if st == nil {
+		return
+	}
+	if exclusive {
+		st.exclusive = false
+	} else {
+		st.shared--
+	}
+	l.release(scope, st)
+}
+
+// release wakes up waiters and drops the scope once nobody uses it. It must
+// be called with l.mu held.
+func (l *scopeLocks) release(scope string, st *scopeState) {
+	if st.shared == 0 && !st.exclusive && st.waitingWriters == 0 {
+		delete(l.scopes, scope)
+	}
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
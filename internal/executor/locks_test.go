@@ -0,0 +1,70 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestScopeLocks_ExclusiveWaitsForShared(t *testing.T) {
+	t.Parallel()
+
+	locks := newScopeLocks()
+	ctx := context.Background()
+	if err := locks.lock(ctx, "folder:a", false); err != nil {
+		t.Fatalf("shared lock() error = %v", err)
+	}
+	// Other scopes are independent.
+	if err := locks.lock(ctx, "folder:b", true); err != nil {
+		t.Fatalf("exclusive lock() of other folder error = %v", err)
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		if err := locks.lock(ctx, "folder:a", true); err != nil {
+			t.Errorf("exclusive lock() error = %v", err)
+		}
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("exclusive lock acquired while shared lock is held")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	// A waiting exclusive holder blocks new shared holders.
+	shortCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := locks.lock(shortCtx, "folder:a", false); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("shared lock() with waiting exclusive holder error = %v, want deadline exceeded", err)
+	}
+
+	locks.unlock("folder:a", false)
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("exclusive lock not acquired after shared lock was released")
+	}
+}
+
+func TestScopeLocks_CanceledWaiterReleasesShared(t *testing.T) {
+	t.Parallel()
+
+	locks := newScopeLocks()
+	if err := locks.lock(context.Background(), "global", false); err != nil {
+		t.Fatalf("shared lock() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := locks.lock(ctx, "global", true); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("exclusive lock() error = %v, want deadline exceeded", err)
+	}
+
+	// The canceled exclusive waiter no longer blocks shared holders.
+	if err := locks.lock(context.Background(), "global", false); err != nil {
+		t.Fatalf("shared lock() after canceled waiter error = %v", err)
+	}
+}
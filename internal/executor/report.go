@@ -0,0 +1,78 @@
+package executor
+
+// Statuses of a run of an action over the resources of a schedule.
+const (
+	// RunSuccess marks runs that succeeded for every resource.
+	RunSuccess = "success"
+	// RunPartial marks runs that succeeded for some resources and failed for
+	// others.
+	RunPartial = "partial"
+	// RunError marks runs that failed for every resource.
+	RunError = "error"
+)
+
+// ResourceOutcome is the result of a run for one resource of the schedule.
+type ResourceOutcome struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+	OK   bool   `json:"ok"`
+	// Utilization holds the metrics of a stopped VM when utilization
+	// snapshots are enabled.
+	Utilization *UtilizationSnapshot `json:"utilization,omitempty"`
+}
+
+// RunReport describes a run of an action over the resources of a schedule.
+type RunReport struct {
+	// Resources holds the outcome of every processed resource. Resources of
+	// steps skipped after a failed step are not included.
+	Resources []ResourceOutcome
+	// unresolved counts resource groups whose name patterns could not be
+	// resolved. Each of them counts as a failure.
+	unresolved int
+}
+
+// Status returns RunSuccess, RunPartial or RunError. A run without matching
+// resources is successful.
+func (r RunReport) Status() string {
+	succeeded, failed := 0, r.unresolved
+	for _, outcome := range r.Resources {
+		if outcome.OK {
+			succeeded++
+		} else {
+			failed++
+		}
+	}
+	switch {
+	case failed == 0:
+		return RunSuccess
+	case succeeded == 0:
+		return RunError
+	default:
+		return RunPartial
+	}
+}
+
+// OK reports whether the run succeeded for all resources.
+func (r RunReport) OK() bool {
+	return r.Status() == RunSuccess
+}
+
+// Succeeded returns the IDs of the resources the run succeeded for.
+func (r RunReport) Succeeded() []string {
+	return r.ids(true)
+}
+
+// Failed returns the IDs of the resources the run failed for.
+func (r RunReport) Failed() []string {
+	return r.ids(false)
+}
+
+func (r RunReport) ids(ok bool) []string {
+	var ids []string
+	for _, outcome := range r.Resources {
+		if outcome.OK == ok {
+			ids = append(ids, outcome.ID)
+		}
+	}
+	return ids
+}
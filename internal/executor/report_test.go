@@ -0,0 +1,80 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/resource"
+)
+
+// selectiveStopOperator fails stops of the resource with ID failing.
+type selectiveStopOperator struct {
+	countingOperator
+	failing string
+}
+
+func (o *selectiveStopOperator) Stop(ctx context.Context, res config.Resource, opts resource.StopOptions) error {
+	if res.ID == o.failing {
+		return errors.New("stop failed")
+	}
+	return o.countingOperator.Stop(ctx, res, opts)
+}
+
+func TestRunReport_Status(t *testing.T) {
+	t.Parallel()
+
+	ok := ResourceOutcome{Type: "vm", ID: "a", OK: true}
+	failed := ResourceOutcome{Type: "vm", ID: "b"}
+	tests := []struct {
+		name   string
+		report RunReport
+		want   string
+	}{
+		{name: "no resources", report: RunReport{}, want: RunSuccess},
+		{name: "all succeeded", report: RunReport{Resources: []ResourceOutcome{ok, ok}}, want: RunSuccess},
+		{name: "all failed", report: RunReport{Resources: []ResourceOutcome{failed}}, want: RunError},
+		{name: "some failed", report: RunReport{Resources: []ResourceOutcome{ok, failed}}, want: RunPartial},
+		{name: "unresolved step", report: RunReport{Resources: []ResourceOutcome{ok}, unresolved: 1}, want: RunPartial},
+		{name: "unresolved", report: RunReport{unresolved: 1}, want: RunError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.report.Status(); got != tt.want {
+				t.Fatalf("Status() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMakeWithRunReport_ReportsPartialRun(t *testing.T) {
+	t.Parallel()
+
+	sch := config.Schedule{
+		Name: "vm-partial-stop",
+		Type: "daily",
+		Actions: config.Actions{
+			Stop: &config.ActionConfig{Enabled: true, Time: "20:00"},
+		},
+	}
+	for _, id := range []string{"vm-partial-1", "vm-partial-2", "vm-partial-3"} {
+		sch.Resources = append(sch.Resources, config.Resource{Type: "vm", ID: id, FolderID: "folder-1"})
+	}
+
+	var run RunReport
+	op := &selectiveStopOperator{failing: "vm-partial-2"}
+	New().MakeWithRunReport(runningStateChecker{}, op, sch, "stop", false, nil, func(r RunReport) { run = r })()
+
+	if got := run.Status(); got != RunPartial {
+		t.Fatalf("Status() = %q, want %q", got, RunPartial)
+	}
+	if got := run.Failed(); !slices.Equal(got, []string{"vm-partial-2"}) {
+		t.Fatalf("Failed() = %v, want [vm-partial-2]", got)
+	}
+	if got := run.Succeeded(); !slices.Equal(got, []string{"vm-partial-1", "vm-partial-3"}) {
+		t.Fatalf("Succeeded() = %v, want [vm-partial-1 vm-partial-3]", got)
+	}
+}
@@ -0,0 +1,115 @@
+package executor
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/yc"
+)
+
+const (
+	// utilizationStep is the resolution of the metrics of a snapshot.
+	utilizationStep = time.Minute
+	// utilizationTimeout bounds reading the metrics of a snapshot, which
+	// runs after the stop when the action timeout may be nearly used up.
+	utilizationTimeout = 30 * time.Second
+)
+
+// MetricReader reads Monitoring metrics of compute instances.
+type MetricReader interface {
+	ReadInstanceMetric(ctx context.Context, folderID, instanceID, metric string, from, to time.Time, step time.Duration) ([]yc.MetricPoint, error)
+}
+
+// Utilization summarizes a metric of a VM over the window before it was
+// stopped. Values are the maximum of each minute.
+type Utilization struct {
+	Metric  string  `json:"metric"`
+	Avg     float64 `json:"avg"`
+	Max     float64 `json:"max"`
+	Samples int     `json:"samples"`
+}
+
+// UtilizationSnapshot holds the metrics of a VM between From and the stop at
+// To.
+type UtilizationSnapshot struct {
+	From    time.Time     `json:"from"`
+	To      time.Time     `json:"to"`
+	Metrics []Utilization `json:"metrics"`
+}
+
+// utilizationSettings configures utilization snapshots.
+type utilizationSettings struct {
+	reader  MetricReader
+	window  time.Duration
+	metrics []string
+}
+
+// SetUtilizationSnapshots enables recording the metrics of stopped VMs read
+// with reader. A nil reader or cfg disables the snapshots.
+func (e *Executor) SetUtilizationSnapshots(reader MetricReader, cfg *config.UtilizationSnapshotConfig) {
+	if reader == nil || cfg == nil {
+		e.utilization.Store(nil)
+		return
+	}
+	e.utilization.Store(&utilizationSettings{
+		reader:  reader,
+		window:  cfg.EffectiveWindow(),
+		metrics: cfg.EffectiveMetrics(),
+	})
+}
+
+// snapshotUtilization returns the metrics of a VM stopped at to, or nil when
+// snapshots are disabled or no metric could be read.
+func (e *Executor) snapshotUtilization(sch config.Schedule, target config.Resource, to time.Time) *UtilizationSnapshot {
+	settings := e.utilization.Load()
+	if settings == nil || target.Type != "vm" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), utilizationTimeout)
+	defer cancel()
+
+	snapshot := &UtilizationSnapshot{From: to.Add(-settings.window), To: to}
+	for _, metric := range settings.metrics {
+		points, err := settings.reader.ReadInstanceMetric(ctx, target.FolderID, target.ID, metric, snapshot.From, to, utilizationStep)
+		if err != nil {
+			log.Warn().Err(err).
+				Str("schedule", sch.Name).
+				Str("resource_id", target.ID).
+				Str("metric", metric).
+				Msg("Failed to read utilization of stopped VM")
+			continue
+		}
+		snapshot.Metrics = append(snapshot.Metrics, summarize(metric, points))
+	}
+	if len(snapshot.Metrics) == 0 {
+		return nil
+	}
+
+	log.Debug().
+		Str("schedule", sch.Name).
+		Str("resource_id", target.ID).
+		Interface("utilization", snapshot.Metrics).
+		Msg("Recorded utilization of stopped VM")
+	return snapshot
+}
+
+// summarize returns the average and maximum of points.
+func summarize(metric string, points []yc.MetricPoint) Utilization {
+	u := Utilization{Metric: metric, Samples: len(points)}
+	if len(points) == 0 {
+		return u
+	}
+	var sum float64
+	for n, point := range points {
+		sum += point.Value
+		if n == 0 || point.Value > u.Max {
+			u.Max = point.Value
+		}
+	}
+	u.Avg = sum / float64(len(points))
+	return u
+}
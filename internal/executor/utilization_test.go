@@ -0,0 +1,75 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/yc"
+)
+
+// fakeMetricReader serves points by metric name and fails for others.
+type fakeMetricReader map[string][]float64
+
+func (f fakeMetricReader) ReadInstanceMetric(_ context.Context, _, _, metric string, from, _ time.Time, step time.Duration) ([]yc.MetricPoint, error) {
+	values, ok := f[metric]
+	if !ok {
+		return nil, errors.New("unknown metric")
+	}
+	points := make([]yc.MetricPoint, 0, len(values))
+	for n, value := range values {
+		points = append(points, yc.MetricPoint{Time: from.Add(time.Duration(n) * step), Value: value})
+	}
+	return points, nil
+}
+
+func TestMakeWithRunReport_RecordsUtilizationOfStoppedVMs(t *testing.T) {
+	t.Parallel()
+
+	exec := New()
+	exec.SetUtilizationSnapshots(fakeMetricReader{"cpu_utilization": {2, 6, 1}}, &config.UtilizationSnapshotConfig{
+		Metrics: []string{"cpu_utilization", "memory_utilization"},
+	})
+
+	sch := config.Schedule{
+		Name: "vm-utilization-stop",
+		Type: "daily",
+		Resources: []config.Resource{
+			{Type: "vm", ID: "vm-utilization-1", FolderID: "folder-1"},
+			{Type: "vm", ID: "vm-utilization-2", FolderID: "folder-1"},
+		},
+		Actions: config.Actions{
+			Stop: &config.ActionConfig{Enabled: true, Time: "20:00"},
+		},
+	}
+
+	var run RunReport
+	exec.MakeWithRunReport(runningStateChecker{}, &countingOperator{}, sch, "stop", false, nil, func(r RunReport) { run = r })()
+
+	if len(run.Resources) != 2 {
+		t.Fatalf("outcomes = %+v, want both resources", run.Resources)
+	}
+	for _, outcome := range run.Resources {
+		snapshot := outcome.Utilization
+		if snapshot == nil || snapshot.To.Sub(snapshot.From) != time.Hour {
+			t.Fatalf("utilization of %s = %+v, want a snapshot of the last hour", outcome.ID, snapshot)
+		}
+		// The unreadable metric is left out.
+		want := Utilization{Metric: "cpu_utilization", Avg: 3, Max: 6, Samples: 3}
+		if len(snapshot.Metrics) != 1 || snapshot.Metrics[0] != want {
+			t.Fatalf("metrics of %s = %+v, want %+v", outcome.ID, snapshot.Metrics, want)
+		}
+	}
+
+	// Starts and dry runs are not recorded.
+	for action, dryRun := range map[string]bool{"start": false, "stop": true} {
+		exec.MakeWithRunReport(runningStateChecker{}, &countingOperator{}, sch, action, dryRun, nil, func(r RunReport) { run = r })()
+		for _, outcome := range run.Resources {
+			if outcome.Utilization != nil {
+				t.Fatalf("%s (dry run %v) utilization of %s = %+v, want none", action, dryRun, outcome.ID, outcome.Utilization)
+			}
+		}
+	}
+}
@@ -0,0 +1,195 @@
+// Package grace tracks announced stops of schedules with a grace period and
+// their postponements.
+package grace
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	// ErrNotFound is returned when a schedule has no announced stop.
+	ErrNotFound = errors.New("grace: no pending stop")
+	// ErrNotLater is returned when a postponement does not move the stop
+	// later than it is currently due.
+	ErrNotLater = errors.New("grace: until must be after the pending stop time")
+	// ErrTooLong is returned when a postponement moves the stop more than
+	// MaxPostponement past the time it was announced for.
+	ErrTooLong = errors.New("grace: until is too far after the announced stop time")
+)
+
+// MaxPostponement is the longest a stop can be postponed past the time it was
+// announced for, so a forgotten postponement does not keep resources running
+// for days.
+const MaxPostponement = 12 * time.Hour
+
+// Stop is a stop of a schedule announced at the start of its grace period.
+// A non-zero Until postpones the stop from At to Until.
+type Stop struct {
+	At       time.Time `json:"at"`
+	Until    time.Time `json:"until,omitzero"`
+	Schedule string    `json:"schedule"`
+	Reason   string    `json:"reason,omitempty"`
+}
+
+// Due returns the time the stop is due at.
+func (s Stop) Due() time.Time {
+	if s.Until.After(s.At) {
+		return s.Until
+	}
+	return s.At
+}
+
+// Registry holds announced stops by schedule name.
+type Registry struct {
+	stops map[string]Stop
+	mu    sync.Mutex
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{stops: make(map[string]Stop)}
+}
+
+// Announce registers the stop of a schedule due at at and reports whether
+// it was not announced yet. A postponement of a stop already announced for
+// the same time is kept.
+func (r *Registry) Announce(schedule string, at time.Time) (Stop, bool) {
+	if r == nil {
+		return Stop{Schedule: schedule, At: at}, true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if stop, ok := r.stops[schedule]; ok && stop.At.Equal(at) {
+		return stop, false
+	}
+	stop := Stop{Schedule: schedule, At: at}
+	r.stops[schedule] = stop
+
+	log.Info().
+		Str("schedule", schedule).
+		Time("at", at).
+		Msg("Stop announced")
+
+	return stop, true
+}
+
+// Postpone moves the announced stop of a schedule to until, at most
+// MaxPostponement after the time it was announced for.
+func (r *Registry) Postpone(schedule string, until time.Time, reason string) (Stop, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stop, ok := r.stops[schedule]
+	if !ok {
+		return Stop{}, ErrNotFound
+	}
+	if !until.After(stop.Due()) {
+		return Stop{}, ErrNotLater
+	}
+	if until.Sub(stop.At) > MaxPostponement {
+		return Stop{}, ErrTooLong
+	}
+	stop.Until = until
+	stop.Reason = reason
+	r.stops[schedule] = stop
+
+	log.Info().
+		Str("schedule", schedule).
+		Time("at", stop.At).
+		Time("until", until).
+		Str("reason", reason).
+		Msg("Stop postponed")
+
+	return stop, nil
+}
+
+// Postponed returns the announced stop of a schedule if it is postponed
+// past now.
+func (r *Registry) Postponed(schedule string, now time.Time) (Stop, bool) {
+	if r == nil {
+		return Stop{}, false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stop, ok := r.stops[schedule]
+	if !ok || !stop.Until.After(now) {
+		return Stop{}, false
+	}
+	return stop, true
+}
+
+// Done removes the announced stop of a schedule once it has run.
+func (r *Registry) Done(schedule string) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.stops, schedule)
+}
+
+// Rename moves the announced stop of a schedule to its new name, e.g. when
+// the schedule is renamed in manifests, keeping its postponement.
+func (r *Registry) Rename(old, updated string) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stop, ok := r.stops[old]
+	if !ok {
+		return
+	}
+	delete(r.stops, old)
+	stop.Schedule = updated
+	r.stops[updated] = stop
+}
+
+// Retain removes announced stops of schedules not listed in names, e.g.
+// after schedules are reloaded.
+func (r *Registry) Retain(names []string) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for schedule := range r.stops {
+		if !slices.Contains(names, schedule) {
+			delete(r.stops, schedule)
+		}
+	}
+}
+
+// List returns announced stops ordered by the time they are due.
+func (r *Registry) List() []Stop {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stops := make([]Stop, 0, len(r.stops))
+	for _, stop := range r.stops {
+		stops = append(stops, stop)
+	}
+	sort.Slice(stops, func(i, j int) bool {
+		if !stops[i].Due().Equal(stops[j].Due()) {
+			return stops[i].Due().Before(stops[j].Due())
+		}
+		return stops[i].Schedule < stops[j].Schedule
+	})
+	return stops
+}
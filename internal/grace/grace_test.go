@@ -0,0 +1,94 @@
+package grace
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRegistryPostponesAnnouncedStop(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 5, 1, 18, 45, 0, 0, time.UTC)
+	at := now.Add(15 * time.Minute)
+	r := NewRegistry()
+
+	if _, err := r.Postpone("dev-vm", at.Add(time.Hour), ""); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Postpone() before Announce() error = %v, want ErrNotFound", err)
+	}
+
+	if _, announced := r.Announce("dev-vm", at); !announced {
+		t.Fatal("Announce() = false for a new stop")
+	}
+	if _, ok := r.Postponed("dev-vm", now); ok {
+		t.Fatal("Postponed() = true for a stop that was not postponed")
+	}
+	if _, err := r.Postpone("dev-vm", at.Add(-time.Minute), ""); !errors.Is(err, ErrNotLater) {
+		t.Fatalf("Postpone() before the stop error = %v, want ErrNotLater", err)
+	}
+
+	if _, err := r.Postpone("dev-vm", at.Add(MaxPostponement+time.Minute), ""); !errors.Is(err, ErrTooLong) {
+		t.Fatalf("Postpone() past MaxPostponement error = %v, want ErrTooLong", err)
+	}
+
+	until := at.Add(time.Hour)
+	if _, err := r.Postpone("dev-vm", until, "demo"); err != nil {
+		t.Fatalf("Postpone() error = %v", err)
+	}
+	// Re-announcing the same stop, e.g. after a reload, keeps the postponement.
+	if _, announced := r.Announce("dev-vm", at); announced {
+		t.Fatal("Announce() = true for an announced stop")
+	}
+
+	stop, ok := r.Postponed("dev-vm", at)
+	if !ok || !stop.Until.Equal(until) || stop.Reason != "demo" {
+		t.Fatalf("Postponed() = %+v, %v; want postponed until %s", stop, ok, until)
+	}
+
+	if _, ok := r.Postponed("dev-vm", until); ok {
+		t.Fatal("Postponed() = true at Until")
+	}
+
+	r.Done("dev-vm")
+	if got := len(r.List()); got != 0 {
+		t.Fatalf("len(List()) = %d after Done(), want 0", got)
+	}
+}
+
+func TestRegistryRetain(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2026, 5, 1, 19, 0, 0, 0, time.UTC)
+	r := NewRegistry()
+	r.Announce("b", at)
+	r.Announce("a", at.Add(-time.Hour))
+	r.Announce("removed", at)
+
+	r.Retain([]string{"a", "b"})
+
+	stops := r.List()
+	if len(stops) != 2 || stops[0].Schedule != "a" || stops[1].Schedule != "b" {
+		t.Fatalf("List() = %+v, want stops of a and b ordered by time", stops)
+	}
+}
+
+func TestRegistryRename(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2026, 5, 1, 19, 0, 0, 0, time.UTC)
+	r := NewRegistry()
+	r.Announce("old", at)
+	if _, err := r.Postpone("old", at.Add(time.Hour), "demo"); err != nil {
+		t.Fatalf("Postpone() error = %v", err)
+	}
+
+	r.Rename("old", "new")
+
+	stop, ok := r.Postponed("new", at)
+	if !ok || stop.Schedule != "new" || stop.Reason != "demo" {
+		t.Fatalf("Postponed() = %+v, %v; want the postponed stop under the new name", stop, ok)
+	}
+	if got := len(r.List()); got != 1 {
+		t.Fatalf("len(List()) = %d, want 1", got)
+	}
+}
@@ -0,0 +1,86 @@
+// Package hook describes and runs hooks around schedule actions.
+package hook
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+// envPrefix prefixes environment variables passed to exec hooks.
+const envPrefix = "YC_SCHEDULER_"
+
+// Context describes an action execution for hooks, so downstream automation
+// does not need to re-read the schedule manifests. It is sent as the JSON
+// body of HTTP hooks and as environment variables to exec hooks.
+type Context struct {
+	Phase        string            `json:"phase"`
+	Schedule     string            `json:"schedule"`
+	Namespace    string            `json:"namespace,omitempty"`
+	Action       string            `json:"action"`
+	ResourceType string            `json:"resource_type"`
+	ResourceID   string            `json:"resource_id"`
+	FolderID     string            `json:"folder_id,omitempty"`
+	ExecutionID  string            `json:"execution_id"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+}
+
+// NewContext creates the hook context of action executed for target of the
+// schedule. executionID is the correlation ID shared by the API calls of the
+// operation.
+func NewContext(sch config.Schedule, target config.Resource, action, executionID string) Context {
+	return Context{
+		Schedule:     sch.Name,
+		Namespace:    sch.Namespace,
+		Action:       action,
+		ResourceType: target.Type,
+		ResourceID:   target.ID,
+		FolderID:     target.FolderID,
+		ExecutionID:  executionID,
+		Labels:       sch.Labels,
+		Annotations:  sch.Annotations,
+	}
+}
+
+// Env returns the context as environment variables, e.g.
+// YC_SCHEDULER_HOOK_PHASE and YC_SCHEDULER_RESOURCE_ID. Labels and
+// annotations are passed as YC_SCHEDULER_LABEL_<KEY> and
+// YC_SCHEDULER_ANNOTATION_<KEY> with keys upper-cased and other characters
+// than letters and digits replaced by "_".
+func (c Context) Env() []string {
+	env := []string{
+		envPrefix + "HOOK_PHASE=" + c.Phase,
+		envPrefix + "SCHEDULE=" + c.Schedule,
+		envPrefix + "NAMESPACE=" + c.Namespace,
+		envPrefix + "ACTION=" + c.Action,
+		envPrefix + "RESOURCE_TYPE=" + c.ResourceType,
+		envPrefix + "RESOURCE_ID=" + c.ResourceID,
+		envPrefix + "FOLDER_ID=" + c.FolderID,
+		envPrefix + "EXECUTION_ID=" + c.ExecutionID,
+	}
+	for _, key := range slices.Sorted(maps.Keys(c.Labels)) {
+		env = append(env, envPrefix+"LABEL_"+envKey(key)+"="+c.Labels[key])
+	}
+	for _, key := range slices.Sorted(maps.Keys(c.Annotations)) {
+		env = append(env, envPrefix+"ANNOTATION_"+envKey(key)+"="+c.Annotations[key])
+	}
+	return env
+}
+
+// envKey converts a label or annotation key to an environment variable name
+// suffix.
+func envKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+}
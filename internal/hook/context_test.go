@@ -0,0 +1,49 @@
+package hook
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+func TestContextEnvAndJSON(t *testing.T) {
+	t.Parallel()
+
+	sch := config.Schedule{
+		Name:        "app-k8s",
+		Namespace:   "team-a",
+		Labels:      map[string]string{"team": "payments"},
+		Annotations: map[string]string{"example.com/drain-url": "https://lb.example.com/drain"},
+	}
+	target := config.Resource{Type: "k8s_cluster", ID: "cat123", FolderID: "b1g123"}
+	c := NewContext(sch, target, "stop", "exec-1")
+
+	env := c.Env()
+	for _, want := range []string{
+		"YC_SCHEDULER_SCHEDULE=app-k8s",
+		"YC_SCHEDULER_NAMESPACE=team-a",
+		"YC_SCHEDULER_ACTION=stop",
+		"YC_SCHEDULER_RESOURCE_ID=cat123",
+		"YC_SCHEDULER_EXECUTION_ID=exec-1",
+		"YC_SCHEDULER_LABEL_TEAM=payments",
+		"YC_SCHEDULER_ANNOTATION_EXAMPLE_COM_DRAIN_URL=https://lb.example.com/drain",
+	} {
+		if !slices.Contains(env, want) {
+			t.Fatalf("Env() = %v, missing %q", env, want)
+		}
+	}
+
+	body, err := json.Marshal(c)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var decoded Context
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if decoded.ExecutionID != "exec-1" || decoded.Annotations["example.com/drain-url"] == "" {
+		t.Fatalf("decoded context = %+v, want execution ID and annotations", decoded)
+	}
+}
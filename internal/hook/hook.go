@@ -0,0 +1,83 @@
+package hook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+// Hook phases.
+const (
+	// PhasePre runs before the operation.
+	PhasePre = "pre"
+	// PhasePost runs after a successful operation.
+	PhasePost = "post"
+)
+
+// maxOutput caps the command output included in hook errors.
+const maxOutput = 512
+
+// Run runs the hook for the given phase with the execution context and
+// returns an error if the hook fails or does not finish within its timeout.
+func Run(ctx context.Context, cfg *config.HookConfig, phase string, hc Context) error {
+	ctx, cancel := context.WithTimeout(ctx, cfg.EffectiveTimeout())
+	defer cancel()
+
+	hc.Phase = phase
+	if cfg.URL != "" {
+		return post(ctx, cfg.URL, hc)
+	}
+	return command(ctx, cfg.Command, hc)
+}
+
+func post(ctx context.Context, url string, hc Context) error {
+	body, err := json.Marshal(hc)
+	if err != nil {
+		return fmt.Errorf("hook: marshal context: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("hook: create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("hook: post webhook: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("hook: webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func command(ctx context.Context, args []string, hc Context) error {
+	if len(args) == 0 {
+		return fmt.Errorf("hook: empty command")
+	}
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Env = append(os.Environ(), hc.Env()...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		out := strings.TrimSpace(string(output))
+		if len(out) > maxOutput {
+			out = out[:maxOutput] + "..."
+		}
+		if out == "" {
+			return fmt.Errorf("hook: run %s: %w", args[0], err)
+		}
+		return fmt.Errorf("hook: run %s: %w: %s", args[0], err, out)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package hook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+func TestRunCommandReceivesContextEnv(t *testing.T) {
+	t.Parallel()
+
+	hc := Context{Schedule: "app-k8s", Action: "stop", ResourceID: "cat123"}
+	ok := &config.HookConfig{Command: []string{"sh", "-c", `test "$YC_SCHEDULER_HOOK_PHASE" = pre && test "$YC_SCHEDULER_RESOURCE_ID" = cat123`}}
+	if err := Run(context.Background(), ok, PhasePre, hc); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	failing := &config.HookConfig{Command: []string{"sh", "-c", "echo not drained; exit 3"}}
+	if err := Run(context.Background(), failing, PhasePre, hc); err == nil {
+		t.Fatal("Run() error = nil for failing command")
+	}
+}
+
+func TestRunPostsContext(t *testing.T) {
+	t.Parallel()
+
+	received := make(chan Context, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var hc Context
+		if err := json.NewDecoder(r.Body).Decode(&hc); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		received <- hc
+		if hc.Action == "start" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	cfg := &config.HookConfig{URL: srv.URL}
+	if err := Run(context.Background(), cfg, PhasePost, Context{Action: "stop", ExecutionID: "exec-1"}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if hc := <-received; hc.Phase != PhasePost || hc.ExecutionID != "exec-1" {
+		t.Fatalf("posted context = %+v, want post phase with execution ID", hc)
+	}
+
+	if err := Run(context.Background(), cfg, PhasePost, Context{Action: "start"}); err == nil {
+		t.Fatal("Run() error = nil for non-2xx response")
+	}
+}
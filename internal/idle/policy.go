@@ -0,0 +1,223 @@
+// Package idle stops running VMs whose CPU and network usage stay below
+// thresholds for a configured time, independent of fixed schedules.
+package idle
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	computepb "github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
+
+	"github.com/sentoz/yc-sheduler/internal/blackout"
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/metrics"
+	"github.com/sentoz/yc-sheduler/internal/resource"
+	"github.com/sentoz/yc-sheduler/internal/yc"
+)
+
+// sampleStep is the resolution of the metrics checked against thresholds.
+const sampleStep = 5 * time.Minute
+
+// Client lists VMs and reads their Monitoring metrics.
+type Client interface {
+	ListInstances(ctx context.Context, folderID string) ([]*computepb.Instance, error)
+	ReadInstanceMetric(ctx context.Context, folderID, instanceID, metric string, from, to time.Time, step time.Duration) ([]yc.MetricPoint, error)
+}
+
+// Expectations reports the resources schedules expect to be running, which
+// the validator would start again after an idle stop.
+type Expectations interface {
+	ExpectedRunning(ctx context.Context, resourceType string) map[string]bool
+}
+
+// Policy periodically stops idle VMs.
+type Policy struct {
+	client       Client
+	operator     resource.Operator
+	cfg          *config.IdlePolicyConfig
+	metrics      *metrics.Metrics
+	blackouts    *blackout.Calendar
+	expectations Expectations
+	now          func() time.Time
+	observe      bool
+}
+
+// New creates a Policy. In observe mode, or when dryRun is set, idle VMs are
+// only logged and counted. If m is nil, metrics are not recorded.
+func New(client Client, operator resource.Operator, cfg *config.IdlePolicyConfig, m *metrics.Metrics, dryRun bool) *Policy {
+	return &Policy{
+		client:   client,
+		operator: operator,
+		cfg:      cfg,
+		metrics:  m,
+		now:      time.Now,
+		observe:  cfg.Observe || dryRun,
+	}
+}
+
+// SetBlackouts sets the blackout windows during which idle VMs are not
+// stopped. It must be called before Start.
+func (p *Policy) SetBlackouts(blackouts *blackout.Calendar) {
+	p.blackouts = blackouts
+}
+
+// SetExpectations sets the source of VMs that schedules expect to be running.
+// They are not stopped even when idle. It must be called before Start.
+func (p *Policy) SetExpectations(expectations Expectations) {
+	p.expectations = expectations
+}
+
+// Start runs the policy every configured interval until ctx is canceled.
+func (p *Policy) Start(ctx context.Context) {
+	if p == nil || p.cfg == nil {
+		return
+	}
+
+	go func() {
+		interval := p.cfg.EffectiveInterval()
+		log.Info().
+			Dur("interval", interval).
+			Dur("idle_for", p.cfg.EffectiveIdleFor()).
+			Bool("observe", p.observe).
+			Msg("Idle policy loop started")
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Info().Msg("Idle policy loop stopped")
+				return
+			case <-ticker.C:
+				p.runOnce(ctx)
+			}
+		}
+	}()
+}
+
+func (p *Policy) runOnce(ctx context.Context) {
+	if w, active := p.blackouts.Active(p.now()); active {
+		log.Debug().
+			Str("window", w.Name).
+			Msg("Blackout window is active, skipping idle policy run")
+		return
+	}
+	var expected map[string]bool
+	if p.expectations != nil {
+		expected = p.expectations.ExpectedRunning(ctx, "vm")
+	}
+	for _, folderID := range p.cfg.FolderIDs {
+		instances, err := p.client.ListInstances(ctx, folderID)
+		if err != nil {
+			log.Warn().Err(err).
+				Str("folder_id", folderID).
+				Msg("Idle policy failed to list instances")
+			continue
+		}
+		for _, instance := range instances {
+			if instance.GetStatus() != computepb.Instance_RUNNING || !p.eligible(instance.GetLabels()) {
+				continue
+			}
+			if expected[instance.GetId()] {
+				log.Debug().
+					Str("resource_type", "vm").
+					Str("resource_id", instance.GetId()).
+					Msg("Schedule expects VM to be running, skipping idle check")
+				continue
+			}
+			p.check(ctx, folderID, instance.GetId())
+		}
+	}
+}
+
+// check stops the instance if all its metrics stayed below thresholds.
+func (p *Policy) check(ctx context.Context, folderID, instanceID string) {
+	to := p.now()
+	from := to.Add(-p.cfg.EffectiveIdleFor())
+	thresholds := map[string]float64{
+		"cpu_utilization":        p.cfg.EffectiveCPUThreshold(),
+		"network_received_bytes": float64(p.cfg.EffectiveNetworkThreshold()),
+		"network_sent_bytes":     float64(p.cfg.EffectiveNetworkThreshold()),
+	}
+	for metric, threshold := range thresholds {
+		points, err := p.client.ReadInstanceMetric(ctx, folderID, instanceID, metric, from, to, sampleStep)
+		if err != nil {
+			log.Warn().Err(err).
+				Str("resource_type", "vm").
+				Str("resource_id", instanceID).
+				Str("metric", metric).
+				Msg("Idle policy failed to read instance metric")
+			return
+		}
+		if !below(points, from, threshold) {
+			return
+		}
+	}
+
+	if p.observe {
+		log.Info().
+			Str("resource_type", "vm").
+			Str("resource_id", instanceID).
+			Dur("idle_for", p.cfg.EffectiveIdleFor()).
+			Msg("Idle policy observe mode: VM is idle, not stopping")
+		p.record("observed")
+		return
+	}
+
+	target := config.Resource{Type: "vm", ID: instanceID, FolderID: folderID}
+	if err := p.operator.Stop(ctx, target, resource.StopOptions{}); err != nil {
+		log.Error().Err(err).
+			Str("resource_type", "vm").
+			Str("resource_id", instanceID).
+			Msg("Idle policy failed to stop VM")
+		p.record("error")
+		return
+	}
+	log.Info().
+		Str("resource_type", "vm").
+		Str("resource_id", instanceID).
+		Dur("idle_for", p.cfg.EffectiveIdleFor()).
+		Msg("Idle policy stopped VM")
+	p.record("success")
+}
+
+func (p *Policy) record(status string) {
+	if p.metrics != nil {
+		p.metrics.IncIdleStop(status)
+	}
+}
+
+// eligible reports whether a VM with labels is checked by the policy: it has
+// any allow label (or no allow labels are configured) and no deny label.
+func (p *Policy) eligible(labels map[string]string) bool {
+	if hasAnyLabel(labels, p.cfg.DenyLabels) {
+		return false
+	}
+	return len(p.cfg.AllowLabels) == 0 || hasAnyLabel(labels, p.cfg.AllowLabels)
+}
+
+func hasAnyLabel(labels, selector map[string]string) bool {
+	for key, value := range selector {
+		if v, ok := labels[key]; ok && v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// below reports whether points cover the whole window starting at from and
+// all values are below threshold. A VM started during the window has no
+// samples at its beginning and is not considered idle yet.
+func below(points []yc.MetricPoint, from time.Time, threshold float64) bool {
+	if len(points) == 0 || points[0].Time.After(from.Add(2*sampleStep)) {
+		return false
+	}
+	for _, point := range points {
+		if point.Value >= threshold {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,160 @@
+package idle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	computepb "github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
+
+	"github.com/sentoz/yc-sheduler/internal/blackout"
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/resource"
+	"github.com/sentoz/yc-sheduler/internal/yc"
+)
+
+var testNow = time.Date(2026, 10, 1, 22, 0, 0, 0, time.UTC)
+
+type fakeClient struct {
+	instances []*computepb.Instance
+	// busy lists instance IDs with CPU above any threshold.
+	busy map[string]bool
+}
+
+func (f fakeClient) ListInstances(context.Context, string) ([]*computepb.Instance, error) {
+	return f.instances, nil
+}
+
+func (f fakeClient) ReadInstanceMetric(_ context.Context, _, instanceID, _ string, from, to time.Time, step time.Duration) ([]yc.MetricPoint, error) {
+	value := 1.0
+	if f.busy[instanceID] {
+		value = 90
+	}
+	var points []yc.MetricPoint
+	for t := from; t.Before(to); t = t.Add(step) {
+		points = append(points, yc.MetricPoint{Time: t, Value: value})
+	}
+	return points, nil
+}
+
+type stopRecorder struct {
+	resource.Operator
+	stopped []string
+}
+
+func (o *stopRecorder) Stop(_ context.Context, res config.Resource, _ resource.StopOptions) error {
+	o.stopped = append(o.stopped, res.ID)
+	return nil
+}
+
+func newTestPolicy(client Client, operator resource.Operator, cfg *config.IdlePolicyConfig) *Policy {
+	p := New(client, operator, cfg, nil, false)
+	p.now = func() time.Time { return testNow }
+	return p
+}
+
+func TestPolicyStopsIdleEligibleVMs(t *testing.T) {
+	t.Parallel()
+
+	client := fakeClient{
+		instances: []*computepb.Instance{
+			{Id: "idle-dev", Status: computepb.Instance_RUNNING, Labels: map[string]string{"env": "dev"}},
+			{Id: "busy-dev", Status: computepb.Instance_RUNNING, Labels: map[string]string{"env": "dev"}},
+			{Id: "idle-prod", Status: computepb.Instance_RUNNING, Labels: map[string]string{"env": "prod"}},
+			{Id: "idle-pinned", Status: computepb.Instance_RUNNING, Labels: map[string]string{"env": "dev", "keep": "true"}},
+			{Id: "stopped-dev", Status: computepb.Instance_STOPPED, Labels: map[string]string{"env": "dev"}},
+		},
+		busy: map[string]bool{"busy-dev": true},
+	}
+	cfg := &config.IdlePolicyConfig{
+		FolderIDs:   []string{"folder-1"},
+		AllowLabels: map[string]string{"env": "dev"},
+		DenyLabels:  map[string]string{"keep": "true"},
+	}
+	op := &stopRecorder{}
+
+	newTestPolicy(client, op, cfg).runOnce(context.Background())
+
+	if len(op.stopped) != 1 || op.stopped[0] != "idle-dev" {
+		t.Fatalf("stopped = %v, want [idle-dev]", op.stopped)
+	}
+}
+
+func TestPolicyObserveModeDoesNotStop(t *testing.T) {
+	t.Parallel()
+
+	client := fakeClient{instances: []*computepb.Instance{{Id: "idle-dev", Status: computepb.Instance_RUNNING}}}
+	cfg := &config.IdlePolicyConfig{FolderIDs: []string{"folder-1"}, Observe: true}
+	op := &stopRecorder{}
+
+	newTestPolicy(client, op, cfg).runOnce(context.Background())
+
+	if len(op.stopped) != 0 {
+		t.Fatalf("stopped = %v, want none in observe mode", op.stopped)
+	}
+}
+
+func TestBelowRequiresFullWindow(t *testing.T) {
+	t.Parallel()
+
+	from := testNow.Add(-4 * time.Hour)
+	late := []yc.MetricPoint{{Time: from.Add(time.Hour), Value: 1}}
+	if below(late, from, 5) {
+		t.Fatal("below() = true for samples starting after the window start")
+	}
+	if below(nil, from, 5) {
+		t.Fatal("below() = true without samples")
+	}
+	full := []yc.MetricPoint{{Time: from, Value: 1}, {Time: from.Add(sampleStep), Value: 4.9}}
+	if !below(full, from, 5) {
+		t.Fatal("below() = false for samples under threshold")
+	}
+}
+
+func TestPolicySkipsRunDuringBlackout(t *testing.T) {
+	t.Parallel()
+
+	client := fakeClient{
+		instances: []*computepb.Instance{
+			{Id: "idle-dev", Status: computepb.Instance_RUNNING, Labels: map[string]string{"env": "dev"}},
+		},
+	}
+	cfg := &config.IdlePolicyConfig{FolderIDs: []string{"folder-1"}}
+	op := &stopRecorder{}
+
+	p := newTestPolicy(client, op, cfg)
+	p.SetBlackouts(blackout.New([]blackout.Window{{Dates: []string{testNow.Format(time.DateOnly)}}}, time.UTC))
+	p.runOnce(context.Background())
+
+	if len(op.stopped) != 0 {
+		t.Fatalf("stopped = %v during blackout window, want none", op.stopped)
+	}
+}
+
+// expectedRunning lists the VMs schedules expect to be running.
+type expectedRunning map[string]bool
+
+func (e expectedRunning) ExpectedRunning(context.Context, string) map[string]bool {
+	return e
+}
+
+func TestPolicySkipsVMsExpectedRunning(t *testing.T) {
+	t.Parallel()
+
+	client := fakeClient{
+		instances: []*computepb.Instance{
+			{Id: "idle-scheduled", Status: computepb.Instance_RUNNING},
+			{Id: "idle-unscheduled", Status: computepb.Instance_RUNNING},
+		},
+	}
+	cfg := &config.IdlePolicyConfig{FolderIDs: []string{"folder-1"}}
+	op := &stopRecorder{}
+
+	p := newTestPolicy(client, op, cfg)
+	p.SetExpectations(expectedRunning{"idle-scheduled": true})
+	p.runOnce(context.Background())
+
+	if len(op.stopped) != 1 || op.stopped[0] != "idle-unscheduled" {
+		t.Fatalf("stopped = %v, want only idle-unscheduled", op.stopped)
+	}
+}
@@ -2,6 +2,7 @@ package logger
 
 import (
 	"os"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -14,10 +15,23 @@ type Logger struct {
 	Level string `long:"log-level" env:"LOG_LEVEL" description:"Log level" default:"info" choice:"trace" choice:"debug" choice:"info" choice:"warn" choice:"error"`
 	//nolint:staticcheck // allow duplicate struct tags
 	Format string `long:"log-format" env:"LOG_FORMAT" description:"Log format" default:"console" choice:"json" choice:"console"`
+	// NoColor disables colors of the console log and of rendered diffs.
+	NoColor bool `long:"no-color" env:"NO_COLOR" description:"Disable colored console and diff output"`
+	// SampleBurst and SamplePeriod bound repetitive messages of the validator
+	// and the schedules reloader.
+	SampleBurst  uint32        `long:"log-sample-burst" env:"LOG_SAMPLE_BURST" description:"Max repetitive validator and reloader messages below error level per period (0 disables sampling)" default:"0"`
+	SamplePeriod time.Duration `long:"log-sample-period" env:"LOG_SAMPLE_PERIOD" description:"Period of repetitive message sampling" default:"1m"`
 }
 
+var (
+	settingsMu sync.Mutex
+	settings   Logger
+	samplers   = make(map[string]zerolog.Sampler)
+)
+
 // Setup initializes the global logger based on provided configuration.
-// It configures the output format (JSON or Console) and the logging level.
+// It configures the output format (JSON or Console), the logging level and
+// sampling of repetitive messages.
 func (l *Logger) Setup() {
 	level, err := zerolog.ParseLevel(l.Level)
 	if err != nil {
@@ -26,6 +40,11 @@ func (l *Logger) Setup() {
 
 	zerolog.SetGlobalLevel(level)
 
+	settingsMu.Lock()
+	settings = *l
+	clear(samplers)
+	settingsMu.Unlock()
+
 	if l.Format == "json" {
 		log.Logger = zerolog.New(os.Stderr).With().Timestamp().Logger()
 		return
@@ -36,12 +55,55 @@ func (l *Logger) Setup() {
 		TimeFormat: time.RFC3339,
 	}
 
-	// If stderr is not a TTY (e.g. redirected to file), disable colors.
-	if stat, err := os.Stderr.Stat(); err == nil {
-		if (stat.Mode() & os.ModeCharDevice) == 0 {
-			output.NoColor = true
-		}
-	}
+	output.NoColor = !l.colored()
 
 	log.Logger = log.Output(output)
 }
+
+// Colored reports whether console output of the configured logger should be
+// colored, e.g. for rendered diffs.
+func Colored() bool {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+
+	return settings.colored()
+}
+
+// colored reports whether console output should be colored: colors are
+// disabled by NoColor, the JSON format and a stderr that is not a TTY (e.g.
+// redirected to file).
+func (l *Logger) colored() bool {
+	if l.NoColor || l.Format == "json" {
+		return false
+	}
+	stat, err := os.Stderr.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
+// Sampled returns the global logger for messages repeated on every resource
+// or tick of component. With sampling configured, at most SampleBurst of its
+// messages below error level are written per SamplePeriod; errors are
+// always written.
+func Sampled(component string) *zerolog.Logger {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+
+	l := log.Logger
+	if settings.SampleBurst == 0 || settings.SamplePeriod <= 0 {
+		return &l
+	}
+
+	sampler, ok := samplers[component]
+	if !ok {
+		burst := &zerolog.BurstSampler{Burst: settings.SampleBurst, Period: settings.SamplePeriod}
+		sampler = &zerolog.LevelSampler{
+			TraceSampler: burst,
+			DebugSampler: burst,
+			InfoSampler:  burst,
+			WarnSampler:  burst,
+		}
+		samplers[component] = sampler
+	}
+	l = l.Sample(sampler)
+	return &l
+}
@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+func TestSampledLimitsMessagesBelowError(t *testing.T) {
+	var buf bytes.Buffer
+	(&Logger{Level: "debug", Format: "json", SampleBurst: 2, SamplePeriod: time.Hour}).Setup()
+	log.Logger = zerolog.New(&buf)
+
+	for range 5 {
+		Sampled("validator").Debug().Msg("repeated")
+	}
+	Sampled("validator").Error().Msg("failure")
+	Sampled("reloader").Debug().Msg("other component")
+
+	if got := strings.Count(buf.String(), "repeated"); got != 2 {
+		t.Fatalf("repeated messages = %d, want 2", got)
+	}
+	if !strings.Contains(buf.String(), "failure") || !strings.Contains(buf.String(), "other component") {
+		t.Fatalf("output = %s, want errors and other components unsampled", buf.String())
+	}
+}
+
+func TestSampledWithoutSampling(t *testing.T) {
+	var buf bytes.Buffer
+	(&Logger{Level: "debug", Format: "json"}).Setup()
+	log.Logger = zerolog.New(&buf)
+
+	for range 5 {
+		Sampled("validator").Debug().Msg("repeated")
+	}
+
+	if got := strings.Count(buf.String(), "repeated"); got != 5 {
+		t.Fatalf("repeated messages = %d, want 5", got)
+	}
+}
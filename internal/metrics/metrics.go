@@ -1,12 +1,41 @@
 package metrics
 
-import "github.com/prometheus/client_golang/prometheus"
+import (
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/sentoz/yc-sheduler/internal/vars"
+)
 
 // Metrics holds all Prometheus metrics for the application.
 type Metrics struct {
 	operationsTotal           *prometheus.CounterVec
 	validatorCorrectionsTotal *prometheus.CounterVec
 	schedulerSkipsTotal       *prometheus.CounterVec
+	resourceOperationsTotal   *prometheus.CounterVec
+	suppressedCorrections     *prometheus.CounterVec
+	incidentMode              prometheus.Gauge
+	restartDuration           *prometheus.HistogramVec
+	restartFailuresTotal      *prometheus.CounterVec
+	idleStopsTotal            *prometheus.CounterVec
+	hookRunsTotal             *prometheus.CounterVec
+	operationAttemptsTotal    *prometheus.CounterVec
+	oneTimeJobs               prometheus.Gauge
+	deprecatedFeatureUsage    *prometheus.GaugeVec
+	jobDivergences            prometheus.Gauge
+	scheduleSetVersion        prometheus.Gauge
+	validatorSetVersion       prometheus.Gauge
+	scheduleRunsTotal         *prometheus.CounterVec
+	jobBacklog                prometheus.Gauge
+	validatorBackpressure     prometheus.Gauge
+	validatorDriftTotal       *prometheus.CounterVec
+	buildInfo                 *prometheus.GaugeVec
+	configInfo                *prometheus.GaugeVec
+	configReloadSuccess       *prometheus.GaugeVec
+	schedulesDirsUnavailable  prometheus.Gauge
 }
 
 // New creates and registers a new Metrics instance.
@@ -33,17 +62,180 @@ func New() *Metrics {
 			},
 			[]string{"resource_type", "action", "reason"},
 		),
+		resourceOperationsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "yc_scheduler_resource_operations_total",
+				Help: "Total number of operations per schedule resource by action and status.",
+			},
+			[]string{"schedule", "resource_type", "resource_id", "action", "status"},
+		),
+		suppressedCorrections: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "yc_scheduler_validator_suppressed_corrections_total",
+				Help: "Total number of state mismatches observed without a corrective job while incident mode is active.",
+			},
+			[]string{"resource_type", "action"},
+		),
+		incidentMode: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "yc_scheduler_incident_mode",
+				Help: "Whether validator incident mode is active (1) or not (0).",
+			},
+		),
+		restartDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "yc_scheduler_restart_duration_seconds",
+				Help:    "Duration of restart operations (stop, wait for stopped, start) by resource type and status.",
+				Buckets: []float64{15, 30, 60, 120, 180, 300, 600},
+			},
+			[]string{"resource_type", "status"},
+		),
+		restartFailuresTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "yc_scheduler_restart_failures_total",
+				Help: "Total number of failed restart operations by resource type and failed phase.",
+			},
+			[]string{"resource_type", "phase"},
+		),
+		idleStopsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "yc_scheduler_idle_stops_total",
+				Help: "Total number of idle VMs found by the idle policy by status.",
+			},
+			[]string{"status"},
+		),
+		hookRunsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "yc_scheduler_hook_runs_total",
+				Help: "Total number of action hook runs by action, phase and status.",
+			},
+			[]string{"action", "phase", "status"},
+		),
+		operationAttemptsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "yc_scheduler_operation_attempts_total",
+				Help: "Total number of operation attempts by resource type, action, attempt number and status.",
+			},
+			[]string{"resource_type", "action", "attempt", "status"},
+		),
+		oneTimeJobs: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "yc_scheduler_one_time_jobs",
+				Help: "Number of one-time jobs, e.g. validator corrections, that have not completed yet.",
+			},
+		),
+		deprecatedFeatureUsage: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "yc_scheduler_deprecated_feature_usage",
+				Help: "Number of usages of deprecated configuration features, e.g. schedules with daily_job blocks.",
+			},
+			[]string{"feature"},
+		),
+		jobDivergences: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "yc_scheduler_job_divergences",
+				Help: "Number of differences between loaded schedules and registered scheduler jobs, e.g. jobs missing after a failed reload.",
+			},
+		),
+		scheduleSetVersion: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "yc_scheduler_schedule_set_version",
+				Help: "Version of the last published schedule set, incremented on every reload.",
+			},
+		),
+		validatorSetVersion: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "yc_scheduler_validator_schedule_set_version",
+				Help: "Version of the schedule set the validator last evaluated.",
+			},
+		),
+		scheduleRunsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "yc_scheduler_schedule_runs_total",
+				Help: "Total number of schedule action runs over all resources of the schedule by schedule, action and status.",
+			},
+			[]string{"schedule", "action", "status"},
+		),
+		jobBacklog: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "yc_scheduler_job_backlog",
+				Help: "Number of due jobs waiting for a free execution slot, as seen by the last validator run.",
+			},
+		),
+		validatorBackpressure: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "yc_scheduler_validator_backpressure",
+				Help: "Whether the validator stopped creating corrective jobs until the job backlog drains (1) or not (0).",
+			},
+		),
+		validatorDriftTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "yc_scheduler_validator_drift_total",
+				Help: "Total number of resource state mismatches found by the validator by resource type and cause (scheduler, external, unknown).",
+			},
+			[]string{"resource_type", "cause"},
+		),
+		buildInfo: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "yc_scheduler_build_info",
+				Help: "Build information of the running binary, always 1.",
+			},
+			[]string{"version", "commit", "goversion"},
+		),
+		configInfo: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "yc_scheduler_config_info",
+				Help: "Configuration in effect, always 1: hash of the settings and schedules, number of schedules and schedule manifest apiVersion. It changes only when a load succeeds.",
+			},
+			[]string{"config_hash", "schedules", "schema_version"},
+		),
+		configReloadSuccess: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "yc_scheduler_config_last_reload_successful",
+				Help: "Whether the last reload succeeded (1) or not (0) by what was reloaded (config, schedules).",
+			},
+			[]string{"source"},
+		),
+		schedulesDirsUnavailable: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "yc_scheduler_schedules_dirs_unavailable",
+				Help: "Number of schedules directories that are unreadable or emptied, while reloads keep the last loaded schedules.",
+			},
+		),
 	}
+	m.buildInfo.WithLabelValues(vars.Version, vars.Commit, runtime.Version()).Set(1)
 
 	prometheus.MustRegister(m.operationsTotal)
 	prometheus.MustRegister(m.validatorCorrectionsTotal)
 	prometheus.MustRegister(m.schedulerSkipsTotal)
+	prometheus.MustRegister(m.resourceOperationsTotal)
+	prometheus.MustRegister(m.suppressedCorrections)
+	prometheus.MustRegister(m.incidentMode)
+	prometheus.MustRegister(m.restartDuration)
+	prometheus.MustRegister(m.restartFailuresTotal)
+	prometheus.MustRegister(m.idleStopsTotal)
+	prometheus.MustRegister(m.hookRunsTotal)
+	prometheus.MustRegister(m.operationAttemptsTotal)
+	prometheus.MustRegister(m.oneTimeJobs)
+	prometheus.MustRegister(m.deprecatedFeatureUsage)
+	prometheus.MustRegister(m.jobDivergences)
+	prometheus.MustRegister(m.scheduleSetVersion)
+	prometheus.MustRegister(m.validatorSetVersion)
+	prometheus.MustRegister(m.scheduleRunsTotal)
+	prometheus.MustRegister(m.jobBacklog)
+	prometheus.MustRegister(m.validatorBackpressure)
+	prometheus.MustRegister(m.validatorDriftTotal)
+	prometheus.MustRegister(m.buildInfo)
+	prometheus.MustRegister(m.configInfo)
+	prometheus.MustRegister(m.configReloadSuccess)
+	prometheus.MustRegister(m.schedulesDirsUnavailable)
 
 	return m
 }
 
 // IncOperation increments the operations counter for the given
-// resource type, action and status ("success", "error", "dry_run", "skipped").
+// resource type, action and status ("success", "error", "deadline_exceeded",
+// "dry_run", "skipped").
 func (m *Metrics) IncOperation(resourceType, action, status string) {
 	m.operationsTotal.WithLabelValues(resourceType, action, status).Inc()
 }
@@ -54,8 +246,137 @@ func (m *Metrics) IncValidatorCorrection(resourceType, action string) {
 	m.validatorCorrectionsTotal.WithLabelValues(resourceType, action).Inc()
 }
 
+// IncValidatorDrift increments the validator drift counter for the given
+// resource type and cause of the state mismatch.
+func (m *Metrics) IncValidatorDrift(resourceType, cause string) {
+	m.validatorDriftTotal.WithLabelValues(resourceType, cause).Inc()
+}
+
 // IncSchedulerSkip increments the scheduler skips counter for the given
 // resource type, action and reason ("already_in_state", "transitional_state").
 func (m *Metrics) IncSchedulerSkip(resourceType, action, reason string) {
 	m.schedulerSkipsTotal.WithLabelValues(resourceType, action, reason).Inc()
 }
+
+// IncResourceOperation increments the per-resource operations counter for the
+// given schedule, resource, action and status.
+func (m *Metrics) IncResourceOperation(schedule, resourceType, resourceID, action, status string) {
+	m.resourceOperationsTotal.WithLabelValues(schedule, resourceType, resourceID, action, status).Inc()
+}
+
+// IncValidatorSuppressedCorrection increments the counter of corrections
+// skipped because incident mode is active.
+func (m *Metrics) IncValidatorSuppressedCorrection(resourceType, action string) {
+	m.suppressedCorrections.WithLabelValues(resourceType, action).Inc()
+}
+
+// SetIncidentMode sets the incident mode gauge.
+func (m *Metrics) SetIncidentMode(active bool) {
+	if active {
+		m.incidentMode.Set(1)
+		return
+	}
+	m.incidentMode.Set(0)
+}
+
+// SetJobBacklog sets the number of due jobs waiting for an execution slot.
+func (m *Metrics) SetJobBacklog(n int) {
+	m.jobBacklog.Set(float64(n))
+}
+
+// SetValidatorBackpressure sets the validator back-pressure gauge.
+func (m *Metrics) SetValidatorBackpressure(active bool) {
+	if active {
+		m.validatorBackpressure.Set(1)
+		return
+	}
+	m.validatorBackpressure.Set(0)
+}
+
+// ObserveRestart records the duration of a restart operation with its status.
+func (m *Metrics) ObserveRestart(resourceType, status string, duration time.Duration) {
+	m.restartDuration.WithLabelValues(resourceType, status).Observe(duration.Seconds())
+}
+
+// IncRestartFailure increments the restart failures counter for the phase
+// ("stop", "wait_stopped" or "start") in which a restart failed.
+func (m *Metrics) IncRestartFailure(resourceType, phase string) {
+	m.restartFailuresTotal.WithLabelValues(resourceType, phase).Inc()
+}
+
+// IncIdleStop increments the idle policy counter with the status of an idle
+// VM ("success", "error" or "observed").
+func (m *Metrics) IncIdleStop(status string) {
+	m.idleStopsTotal.WithLabelValues(status).Inc()
+}
+
+// IncHookRun increments the hook runs counter for the given action, phase
+// ("pre" or "post") and status ("success" or "error").
+func (m *Metrics) IncHookRun(action, phase, status string) {
+	m.hookRunsTotal.WithLabelValues(action, phase, status).Inc()
+}
+
+// IncOperationAttempt increments the operation attempts counter for the given
+// resource type, action, attempt number starting from 1 and status
+// ("success" or "error").
+func (m *Metrics) IncOperationAttempt(resourceType, action string, attempt int, status string) {
+	m.operationAttemptsTotal.WithLabelValues(resourceType, action, strconv.Itoa(attempt), status).Inc()
+}
+
+// SetOneTimeJobs sets the number of one-time jobs that have not completed yet.
+func (m *Metrics) SetOneTimeJobs(n int) {
+	m.oneTimeJobs.Set(float64(n))
+}
+
+// SetDeprecatedFeatureUsage sets the number of usages of a deprecated feature.
+func (m *Metrics) SetDeprecatedFeatureUsage(feature string, n int) {
+	m.deprecatedFeatureUsage.WithLabelValues(feature).Set(float64(n))
+}
+
+// SetJobDivergences sets the number of differences between loaded schedules
+// and registered scheduler jobs.
+func (m *Metrics) SetJobDivergences(n int) {
+	m.jobDivergences.Set(float64(n))
+}
+
+// SetScheduleSetVersion sets the version of the last published schedule set.
+func (m *Metrics) SetScheduleSetVersion(version uint64) {
+	m.scheduleSetVersion.Set(float64(version))
+}
+
+// SetConfigInfo replaces the labels of the config info metric with the hash
+// and number of schedules of the configuration in effect.
+func (m *Metrics) SetConfigInfo(hash string, schedules int, schemaVersion string) {
+	m.configInfo.Reset()
+	m.configInfo.WithLabelValues(hash, strconv.Itoa(schedules), schemaVersion).Set(1)
+}
+
+// SetConfigReload records the outcome of the last reload of source
+// ("config" or "schedules").
+func (m *Metrics) SetConfigReload(source string, ok bool) {
+	if ok {
+		m.configReloadSuccess.WithLabelValues(source).Set(1)
+		return
+	}
+	m.configReloadSuccess.WithLabelValues(source).Set(0)
+}
+
+// SetSchedulesDirsUnavailable sets the number of unavailable schedules
+// directories.
+func (m *Metrics) SetSchedulesDirsUnavailable(n int) {
+	m.schedulesDirsUnavailable.Set(float64(n))
+}
+
+// SetValidatorScheduleSetVersion sets the version of the schedule set the
+// validator last evaluated.
+func (m *Metrics) SetValidatorScheduleSetVersion(version uint64) {
+	m.validatorSetVersion.Set(float64(version))
+}
+
+// IncScheduleRun increments the schedule runs counter for the given schedule,
+// action and status ("success", "partial" when the action failed for some of
+// the resources only, "missed" when the run was skipped by run_within, or
+// "error").
+func (m *Metrics) IncScheduleRun(schedule, action, status string) {
+	m.scheduleRunsTotal.WithLabelValues(schedule, action, status).Inc()
+}
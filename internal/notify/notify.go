@@ -0,0 +1,162 @@
+// Package notify sends scheduler lifecycle notifications.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/vars"
+)
+
+// Lifecycle event types.
+const (
+	// EventStarted is sent when the scheduler has registered schedules and started.
+	EventStarted = "started"
+	// EventStopped is sent when the scheduler stops.
+	EventStopped = "stopped"
+	// EventStartFailed is sent when the scheduler fails to start.
+	EventStartFailed = "start_failed"
+	// EventStopImminent is sent when a stop with a grace period is announced.
+	EventStopImminent = "stop_imminent"
+	// EventJobsDiverged is sent when registered jobs start to diverge from
+	// the loaded schedules.
+	EventJobsDiverged = "jobs_diverged"
+	// EventSchedulesReloaded is sent when reloaded schedules differ from the
+	// running ones.
+	EventSchedulesReloaded = "schedules_reloaded"
+	// EventActionFailed is sent when a scheduled action fails and will not be
+	// retried.
+	EventActionFailed = "action_failed"
+	// EventActionPartial is sent instead of action_failed when the action
+	// succeeded for some resources of the schedule and failed for others.
+	EventActionPartial = "action_partial"
+	// EventSchedulesDirUnavailable is sent when a schedules directory becomes
+	// unreadable or empty and reloads keep the last loaded schedules.
+	EventSchedulesDirUnavailable = "schedules_dir_unavailable"
+)
+
+// sendTimeout bounds delivery of a single notification.
+const sendTimeout = 10 * time.Second
+
+// Event is a scheduler lifecycle notification.
+type Event struct {
+	Time     time.Time      `json:"time"`
+	Type     string         `json:"type"`
+	Error    string         `json:"error,omitempty"`
+	Hostname string         `json:"hostname,omitempty"`
+	Build    vars.BuildInfo `json:"build"`
+	// Schedule and At describe the announced stop of stop_imminent events.
+	Schedule string    `json:"schedule,omitempty"`
+	At       time.Time `json:"at,omitzero"`
+	// Action is the failed action of action_failed events. Description and
+	// RunbookURL come from the schedule, so whoever is paged sees what it is
+	// for and how to respond.
+	Action      string `json:"action,omitempty"`
+	Description string `json:"description,omitempty"`
+	RunbookURL  string `json:"runbook_url,omitempty"`
+	// SucceededResources and FailedResources list the resource IDs of
+	// action_failed and action_partial events by outcome.
+	SucceededResources []string `json:"succeeded_resources,omitempty"`
+	FailedResources    []string `json:"failed_resources,omitempty"`
+	// Summary describes the changes of schedules_reloaded events.
+	Summary string `json:"summary,omitempty"`
+}
+
+// Notifier delivers lifecycle events.
+type Notifier interface {
+	Notify(ctx context.Context, event Event) error
+}
+
+// New creates a Notifier from configuration.
+// It returns nil when notifications are not configured.
+func New(cfg *config.NotificationsConfig) Notifier {
+	if cfg == nil || cfg.WebhookURL == "" {
+		return nil
+	}
+	return &Webhook{
+		url:    cfg.WebhookURL,
+		client: &http.Client{Timeout: sendTimeout},
+	}
+}
+
+// NewEvent creates an event of the given type with build info filled in.
+// If err is not nil, its message is included in the event.
+func NewEvent(eventType string, err error) Event {
+	hostname, _ := os.Hostname()
+	event := Event{
+		Time:     time.Now().UTC(),
+		Type:     eventType,
+		Hostname: hostname,
+		Build:    vars.Info(),
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	return event
+}
+
+// Send delivers an event and logs delivery failures.
+// It is a no-op if n is nil.
+func Send(n Notifier, eventType string, err error) {
+	SendEvent(n, NewEvent(eventType, err))
+}
+
+// SendEvent is like Send for an event built by the caller.
+func SendEvent(n Notifier, event Event) {
+	if n == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+
+	if sendErr := n.Notify(ctx, event); sendErr != nil {
+		log.Warn().Err(sendErr).
+			Str("event", event.Type).
+			Msg("Failed to send lifecycle notification")
+		return
+	}
+
+	log.Debug().
+		Str("event", event.Type).
+		Msg("Lifecycle notification sent")
+}
+
+// Webhook posts events as JSON to an HTTP endpoint.
+type Webhook struct {
+	client *http.Client
+	url    string
+}
+
+// Notify posts the event to the webhook URL.
+func (w *Webhook) Notify(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("notify: marshal event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("notify: create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("notify: post webhook: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("notify: webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+func TestWebhookPostsEvent(t *testing.T) {
+	t.Parallel()
+
+	received := make(chan Event, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		received <- event
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	n := New(&config.NotificationsConfig{WebhookURL: srv.URL})
+	if err := n.Notify(t.Context(), NewEvent(EventStartFailed, errors.New("boom"))); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	event := <-received
+	if event.Type != EventStartFailed || event.Error != "boom" || event.Build.Version == "" {
+		t.Fatalf("received event = %+v", event)
+	}
+}
+
+func TestWebhookReportsErrorStatus(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	n := New(&config.NotificationsConfig{WebhookURL: srv.URL})
+	if err := n.Notify(t.Context(), NewEvent(EventStarted, nil)); err == nil {
+		t.Fatal("Notify() error = nil, want error for 502 response")
+	}
+}
+
+func TestNewWithoutWebhook(t *testing.T) {
+	t.Parallel()
+
+	if n := New(nil); n != nil {
+		t.Fatalf("New(nil) = %v, want nil", n)
+	}
+	if n := New(&config.NotificationsConfig{}); n != nil {
+		t.Fatalf("New(empty) = %v, want nil", n)
+	}
+}
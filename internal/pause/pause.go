@@ -0,0 +1,161 @@
+// Package pause tracks label-selector based pauses of schedules.
+package pause
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Pause sources.
+const (
+	// SourceConfig marks pauses defined in the configuration file.
+	SourceConfig = "config"
+	// SourceAPI marks pauses created through the HTTP API.
+	SourceAPI = "api"
+)
+
+// Pause suspends all schedules whose labels match Selector.
+// A zero Until pauses schedules until the pause is removed.
+type Pause struct {
+	Until    time.Time         `json:"until,omitzero"`
+	Selector map[string]string `json:"selector"`
+	ID       string            `json:"id"`
+	Reason   string            `json:"reason,omitempty"`
+	Source   string            `json:"source"`
+}
+
+// Matches reports whether labels satisfy the pause selector.
+// An empty selector matches no schedules.
+func (p Pause) Matches(labels map[string]string) bool {
+	if len(p.Selector) == 0 {
+		return false
+	}
+	for key, value := range p.Selector {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// expired reports whether the pause has ended at now.
+func (p Pause) expired(now time.Time) bool {
+	return !p.Until.IsZero() && !now.Before(p.Until)
+}
+
+// Registry holds active pauses. Expired pauses are removed on access, which
+// resumes matching schedules automatically.
+type Registry struct {
+	now    func() time.Time
+	pauses []Pause
+	mu     sync.Mutex
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{now: time.Now}
+}
+
+// Add registers a pause and returns it with a generated ID.
+func (r *Registry) Add(p Pause) Pause {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p.ID = newID()
+	p.Selector = maps.Clone(p.Selector)
+	r.pauses = append(r.pauses, p)
+
+	log.Info().
+		Str("pause_id", p.ID).
+		Interface("selector", p.Selector).
+		Time("until", p.Until).
+		Str("source", p.Source).
+		Str("reason", p.Reason).
+		Msg("Schedules paused")
+
+	return p
+}
+
+// Remove deletes the pause with the given ID and reports whether it existed.
+func (r *Registry) Remove(id string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, p := range r.pauses {
+		if p.ID == id {
+			r.pauses = slices.Delete(r.pauses, i, i+1)
+			log.Info().
+				Str("pause_id", id).
+				Msg("Schedules resumed")
+			return true
+		}
+	}
+	return false
+}
+
+// ReplaceSource replaces all pauses of the given source.
+func (r *Registry) ReplaceSource(source string, pauses []Pause) {
+	r.mu.Lock()
+	r.pauses = slices.DeleteFunc(r.pauses, func(p Pause) bool {
+		return p.Source == source
+	})
+	r.mu.Unlock()
+
+	for _, p := range pauses {
+		p.Source = source
+		r.Add(p)
+	}
+}
+
+// List returns active pauses.
+func (r *Registry) List() []Pause {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.dropExpiredUnlocked()
+	return slices.Clone(r.pauses)
+}
+
+// Paused returns the first active pause matching labels.
+func (r *Registry) Paused(labels map[string]string) (Pause, bool) {
+	if r == nil {
+		return Pause{}, false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.dropExpiredUnlocked()
+	for _, p := range r.pauses {
+		if p.Matches(labels) {
+			return p, true
+		}
+	}
+	return Pause{}, false
+}
+
+func (r *Registry) dropExpiredUnlocked() {
+	now := r.now()
+	r.pauses = slices.DeleteFunc(r.pauses, func(p Pause) bool {
+		if !p.expired(now) {
+			return false
+		}
+		log.Info().
+			Str("pause_id", p.ID).
+			Interface("selector", p.Selector).
+			Msg("Pause expired, schedules resumed")
+		return true
+	})
+}
+
+func newID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,69 @@
+package pause
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRegistryPausedMatchesSelector(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	p := r.Add(Pause{Selector: map[string]string{"team": "payments"}, Source: SourceAPI})
+
+	if got, ok := r.Paused(map[string]string{"team": "payments", "env": "dev"}); !ok || got.ID != p.ID {
+		t.Fatalf("Paused() = %+v, %v; want pause %s", got, ok, p.ID)
+	}
+	if _, ok := r.Paused(map[string]string{"team": "search"}); ok {
+		t.Fatal("Paused() matched schedule with a different label value")
+	}
+	if _, ok := r.Paused(nil); ok {
+		t.Fatal("Paused() matched schedule without labels")
+	}
+
+	if !r.Remove(p.ID) {
+		t.Fatal("Remove() = false, want true")
+	}
+	if _, ok := r.Paused(map[string]string{"team": "payments"}); ok {
+		t.Fatal("Paused() matched after Remove()")
+	}
+}
+
+func TestRegistryAutoResumesExpiredPause(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	r := NewRegistry()
+	r.now = func() time.Time { return now }
+	r.Add(Pause{Selector: map[string]string{"team": "payments"}, Until: now.Add(time.Hour)})
+
+	labels := map[string]string{"team": "payments"}
+	if _, ok := r.Paused(labels); !ok {
+		t.Fatal("Paused() = false before Until")
+	}
+
+	now = now.Add(time.Hour)
+	if _, ok := r.Paused(labels); ok {
+		t.Fatal("Paused() = true at Until, want resumed")
+	}
+	if got := len(r.List()); got != 0 {
+		t.Fatalf("len(List()) = %d, want 0", got)
+	}
+}
+
+func TestReplaceSourceKeepsOtherSources(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	r.Add(Pause{Selector: map[string]string{"team": "a"}, Source: SourceAPI})
+	r.ReplaceSource(SourceConfig, []Pause{{Selector: map[string]string{"team": "b"}}})
+	r.ReplaceSource(SourceConfig, []Pause{{Selector: map[string]string{"team": "c"}}})
+
+	pauses := r.List()
+	if len(pauses) != 2 {
+		t.Fatalf("len(List()) = %d, want 2", len(pauses))
+	}
+	if _, ok := r.Paused(map[string]string{"team": "b"}); ok {
+		t.Fatal("replaced config pause is still active")
+	}
+}
@@ -3,28 +3,83 @@ package reloader
 import (
 	"context"
 	"crypto/sha256"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
-	"sort"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/logger"
+)
+
+const (
+	// debounce delays a check after a file change notification, so a burst
+	// of changes, e.g. a git checkout, is applied by one reload.
+	debounce = 500 * time.Millisecond
+	// resyncInterval is how often directories with file change notifications
+	// are checked anyway, in case a notification was missed.
+	resyncInterval = 5 * time.Minute
+)
+
+// ErrDirEmpty is reported for a schedules directory without schedule files
+// that had them at the last reload.
+var ErrDirEmpty = errors.New("no schedule files left")
+
+// dirWatcher notifies about changes of the watched directories.
+type dirWatcher interface {
+	Events() <-chan struct{}
+	Close() error
+}
+
+// subject names what a reloader watches in its log messages.
+type subject struct {
+	title  string
+	target string
+	kept   string
+}
+
+var (
+	schedulesSubject = subject{title: "Schedules", target: "schedules directories", kept: "schedule set"}
+	configSubject    = subject{title: "Config", target: "config file", kept: "settings"}
 )
 
-// Reloader watches schedules directory and applies updates on changes.
+// Reloader watches schedules directories, or the config file, and applies
+// updates on changes.
 type Reloader struct {
-	onChange     func(context.Context) error
-	schedulesDir string
-	interval     time.Duration
-	lastSig      [sha256.Size]byte
-	hasLastSig   bool
+	onChange      func(context.Context) error
+	refresh       func(context.Context) error
+	subject       subject
+	file          string
+	schedulesDirs []string
+	recursive     bool
+	interval      time.Duration
+	lastSig       [sha256.Size]byte
+	hasLastSig    bool
+	lastCheck     time.Time
+	changed       bool
+	trigger       chan struct{}
+
+	// keep skips reloads while any directory is unavailable.
+	keep          bool
+	onUnavailable func(map[string]error)
+	// populated lists the directories with schedule files at the last
+	// reload.
+	populated map[string]bool
+	// unavailable holds the unavailable directories last reported.
+	unavailable map[string]error
 }
 
-// New creates a new schedules reloader.
-func New(schedulesDir string, interval time.Duration, onChange func(context.Context) error) (*Reloader, error) {
-	if schedulesDir == "" {
+// New creates a new schedules reloader watching all schedulesDirs and, when
+// recursive, their subdirectories.
+func New(schedulesDirs []string, recursive bool, interval time.Duration, onChange func(context.Context) error) (*Reloader, error) {
+	if len(schedulesDirs) == 0 || slices.Contains(schedulesDirs, "") {
 		return nil, fmt.Errorf("reloader: empty schedules directory")
 	}
 	if interval <= 0 {
@@ -35,48 +90,167 @@ func New(schedulesDir string, interval time.Duration, onChange func(context.Cont
 	}
 
 	return &Reloader{
-		schedulesDir: schedulesDir,
-		interval:     interval,
-		onChange:     onChange,
+		subject:       schedulesSubject,
+		schedulesDirs: schedulesDirs,
+		recursive:     recursive,
+		interval:      interval,
+		onChange:      onChange,
+		trigger:       make(chan struct{}, 1),
+	}, nil
+}
+
+// NewFile creates a reloader watching the config file at path. Notifications
+// are received for its directory, so the symlink swaps of Kubernetes ConfigMap
+// volumes are noticed as well.
+func NewFile(path string, interval time.Duration, onChange func(context.Context) error) (*Reloader, error) {
+	if path == "" {
+		return nil, fmt.Errorf("reloader: empty config path")
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("reloader: interval must be greater than zero")
+	}
+	if onChange == nil {
+		return nil, fmt.Errorf("reloader: onChange callback is required")
+	}
+
+	return &Reloader{
+		subject:       configSubject,
+		file:          path,
+		schedulesDirs: []string{filepath.Dir(path)},
+		interval:      interval,
+		onChange:      onChange,
+		trigger:       make(chan struct{}, 1),
 	}, nil
 }
 
-// Start begins watching schedules directory until ctx is canceled.
+// SetRefresh sets a function called on every check before the directories
+// are read, e.g. to download schedules of a remote source into one of them.
+// If it fails, the directories are checked as they are.
+func (r *Reloader) SetRefresh(refresh func(context.Context) error) {
+	if r == nil {
+		return
+	}
+	r.refresh = refresh
+}
+
+// SetKeepLastKnownGood makes the reloader skip reloads while any schedules
+// directory is unreadable, or empty after it had schedule files at the last
+// reload, so a directory momentarily unavailable during a volume remount does
+// not drop its schedules. Once all directories are back, they are reloaded
+// if they changed. onUnavailable, if set, is called with the unavailable
+// directories and their errors whenever they change, and with nil once all
+// are back.
+func (r *Reloader) SetKeepLastKnownGood(onUnavailable func(map[string]error)) {
+	if r == nil {
+		return
+	}
+	r.keep = true
+	r.onUnavailable = onUnavailable
+}
+
+// Trigger makes the watcher check the directories now instead of at the
+// next interval, e.g. when a watched source has changed one of them.
+func (r *Reloader) Trigger() {
+	if r == nil {
+		return
+	}
+	select {
+	case r.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Start begins watching schedules directories or the config file until ctx is
+// canceled. Where
+// file change notifications are available, directories are checked shortly
+// after they change instead of on every interval, which spares hashing large
+// directories that rarely change.
 func (r *Reloader) Start(ctx context.Context) {
 	if r == nil {
 		return
 	}
 
-	if sig, err := calcDirSignature(r.schedulesDir); err != nil {
-		log.Warn().Err(err).Str("schedules_dir", r.schedulesDir).Msg("Failed to initialize schedules watcher signature")
+	if sig, err := r.signature(); err != nil {
+		r.fields(log.Warn()).Err(err).Msgf("Failed to initialize %s watcher signature", strings.ToLower(r.subject.title))
 	} else {
 		r.lastSig = sig
 		r.hasLastSig = true
 	}
+	r.populated = r.populatedDirs()
+
+	r.lastCheck = time.Now()
+
+	var events <-chan struct{}
+	watcher, err := watchDirs(r.schedulesDirs, r.recursive)
+	if err != nil {
+		r.fields(log.Warn()).Err(err).Msg("File change notifications unavailable, polling " + r.subject.target)
+	} else {
+		defer watcher.Close()
+		events = watcher.Events()
+	}
 
 	ticker := time.NewTicker(r.interval)
 	defer ticker.Stop()
+	settled := time.NewTimer(debounce)
+	settled.Stop()
+	defer settled.Stop()
 
-	log.Info().
-		Str("schedules_dir", r.schedulesDir).
+	r.fields(log.Info()).
 		Dur("interval", r.interval).
-		Msg("Schedules auto-reload watcher started")
+		Bool("notifications", events != nil).
+		Msg(r.subject.title + " auto-reload watcher started")
 
 	for {
 		select {
 		case <-ctx.Done():
-			log.Info().Msg("Schedules auto-reload watcher stopped")
+			log.Info().Msg(r.subject.title + " auto-reload watcher stopped")
 			return
 		case <-ticker.C:
-			r.tick(ctx)
+			r.tick(ctx, events == nil || r.changed || time.Since(r.lastCheck) >= resyncInterval)
+		case <-r.trigger:
+			r.tick(ctx, true)
+		case <-events:
+			r.changed = true
+			settled.Reset(debounce)
+		case <-settled.C:
+			r.check(ctx)
+		}
+	}
+}
+
+// tick refreshes remote sources and, if check is set, checks the directories.
+// Directories with file change notifications are checked once the files
+// written by the refresh settle.
+func (r *Reloader) tick(ctx context.Context, check bool) {
+	if r.refresh != nil {
+		if err := r.refresh(ctx); err != nil {
+			logger.Sampled("reloader").Warn().Err(err).Msg("Failed to refresh remote schedules, keeping their last copy")
 		}
 	}
+	if check {
+		r.check(ctx)
+	}
 }
 
-func (r *Reloader) tick(ctx context.Context) {
-	sig, err := calcDirSignature(r.schedulesDir)
+// check reloads schedules if the directories, or the config file, changed
+// since the last reload.
+func (r *Reloader) check(ctx context.Context) {
+	r.changed = false
+	r.lastCheck = time.Now()
+
+	if r.keep {
+		r.setUnavailable(r.unavailableDirs())
+		if len(r.unavailable) > 0 {
+			// The last signature is kept, so directories that come back
+			// unchanged are not reloaded.
+			return
+		}
+	}
+
+	sig, err := r.signature()
 	if err != nil {
-		log.Warn().Err(err).Str("schedules_dir", r.schedulesDir).Msg("Failed to read schedules directory state")
+		// Repeats on every tick until the directory is readable again.
+		r.fields(logger.Sampled("reloader").Warn()).Err(err).Msg("Failed to read " + r.subject.target + " state")
 		return
 	}
 
@@ -84,37 +258,131 @@ func (r *Reloader) tick(ctx context.Context) {
 		return
 	}
 
-	log.Info().Str("schedules_dir", r.schedulesDir).Msg("Detected schedules change, applying reload")
+	name := strings.ToLower(r.subject.title)
+	r.fields(log.Info()).Msg("Detected " + name + " change, applying reload")
 	if err := r.onChange(ctx); err != nil {
-		log.Error().Err(err).Str("schedules_dir", r.schedulesDir).Msg("Schedules reload failed, keeping previous schedule set")
+		r.fields(log.Error()).Err(err).Msg(r.subject.title + " reload failed, keeping previous " + r.subject.kept)
 	} else {
-		log.Info().Str("schedules_dir", r.schedulesDir).Msg("Schedules reload applied")
+		r.fields(log.Info()).Msg(r.subject.title + " reload applied")
+		r.populated = r.populatedDirs()
 	}
 
 	r.lastSig = sig
 	r.hasLastSig = true
 }
 
-func calcDirSignature(path string) ([sha256.Size]byte, error) {
-	entries, err := os.ReadDir(path)
-	if err != nil {
-		return [sha256.Size]byte{}, fmt.Errorf("read dir %q: %w", path, err)
+// populatedDirs returns the schedules directories with schedule files.
+func (r *Reloader) populatedDirs() map[string]bool {
+	if r.file != "" {
+		return nil
+	}
+	populated := make(map[string]bool, len(r.schedulesDirs))
+	for _, dir := range r.schedulesDirs {
+		files, err := config.ScheduleFiles(dir, r.recursive)
+		populated[dir] = err == nil && len(files) > 0
 	}
+	return populated
+}
 
-	hasher := sha256.New()
-	fileNames := make([]string, 0, len(entries))
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
+// unavailableDirs returns the schedules directories that are unreadable, or
+// empty after they had schedule files at the last reload, with the reason.
+func (r *Reloader) unavailableDirs() map[string]error {
+	if r.file != "" {
+		return nil
+	}
+	var unavailable map[string]error
+	for _, dir := range r.schedulesDirs {
+		files, err := config.ScheduleFiles(dir, r.recursive)
+		if err == nil && len(files) == 0 && r.populated[dir] {
+			err = ErrDirEmpty
 		}
-		ext := strings.ToLower(filepath.Ext(entry.Name()))
-		if ext != ".yaml" && ext != ".yml" {
+		if err == nil {
 			continue
 		}
-		fileNames = append(fileNames, entry.Name())
+		if unavailable == nil {
+			unavailable = make(map[string]error)
+		}
+		unavailable[dir] = err
+	}
+	return unavailable
+}
+
+// setUnavailable records the unavailable directories, logging and reporting
+// them when they change.
+func (r *Reloader) setUnavailable(unavailable map[string]error) {
+	same := maps.EqualFunc(r.unavailable, unavailable, func(a, b error) bool {
+		return a.Error() == b.Error()
+	})
+	if same {
+		return
+	}
+	r.unavailable = unavailable
+
+	if len(unavailable) == 0 {
+		r.fields(log.Info()).Msg("Schedules directories are available again")
+	}
+	for _, dir := range slices.Sorted(maps.Keys(unavailable)) {
+		log.Error().
+			Str("schedules_dir", dir).
+			Err(unavailable[dir]).
+			Msg("Schedules directory is unavailable, keeping last known good schedule set")
+	}
+	if r.onUnavailable != nil {
+		r.onUnavailable(unavailable)
+	}
+}
+
+// signature returns the signature of the watched config file or directories.
+func (r *Reloader) signature() ([sha256.Size]byte, error) {
+	if r.file != "" {
+		data, err := os.ReadFile(r.file)
+		if err != nil {
+			return [sha256.Size]byte{}, fmt.Errorf("read file %q: %w", r.file, err)
+		}
+		return sha256.Sum256(data), nil
+	}
+	return calcDirsSignature(r.schedulesDirs, r.recursive)
+}
+
+// fields adds the watched config file or directories to a log event.
+func (r *Reloader) fields(e *zerolog.Event) *zerolog.Event {
+	if r.file != "" {
+		return e.Str("config_path", r.file)
+	}
+	return e.Strs("schedules_dir", r.schedulesDirs)
+}
+
+// calcDirsSignature combines the signatures of all directories, so a change
+// in any of them changes the result.
+func calcDirsSignature(paths []string, recursive bool) ([sha256.Size]byte, error) {
+	hasher := sha256.New()
+	for _, path := range paths {
+		sig, err := calcDirSignature(path, recursive)
+		if err != nil {
+			return [sha256.Size]byte{}, err
+		}
+		if _, err := hasher.Write([]byte(path)); err != nil {
+			return [sha256.Size]byte{}, fmt.Errorf("hash directory %q: %w", path, err)
+		}
+		if _, err := hasher.Write(sig[:]); err != nil {
+			return [sha256.Size]byte{}, fmt.Errorf("hash signature of %q: %w", path, err)
+		}
+	}
+
+	var sum [sha256.Size]byte
+	copy(sum[:], hasher.Sum(nil))
+	return sum, nil
+}
+
+// calcDirSignature hashes the names and contents of the schedule files of a
+// directory, including the whole tree when recursive.
+func calcDirSignature(path string, recursive bool) ([sha256.Size]byte, error) {
+	fileNames, err := config.ScheduleFiles(path, recursive)
+	if err != nil {
+		return [sha256.Size]byte{}, fmt.Errorf("read dir %q: %w", path, err)
 	}
 
-	sort.Strings(fileNames)
+	hasher := sha256.New()
 	for _, name := range fileNames {
 		fullPath := filepath.Join(path, name)
 		data, err := os.ReadFile(fullPath)
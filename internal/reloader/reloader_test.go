@@ -2,6 +2,8 @@ package reloader
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"sync/atomic"
@@ -19,7 +21,7 @@ func TestReloader_TriggersOnlyOnScheduleFileChanges(t *testing.T) {
 	}
 
 	var reloadCalls atomic.Int32
-	r, err := New(dir, 20*time.Millisecond, func(context.Context) error {
+	r, err := New([]string{dir}, false, 20*time.Millisecond, func(context.Context) error {
 		reloadCalls.Add(1)
 		return nil
 	})
@@ -66,3 +68,290 @@ func TestReloader_TriggersOnlyOnScheduleFileChanges(t *testing.T) {
 		t.Fatal("reloader did not stop after cancel")
 	}
 }
+
+func TestCalcDirsSignature_ChangesWithAnyDirectory(t *testing.T) {
+	t.Parallel()
+
+	dirs := []string{t.TempDir(), t.TempDir()}
+	for _, dir := range dirs {
+		if err := os.WriteFile(filepath.Join(dir, "a.yaml"), []byte("name: a\n"), 0o600); err != nil {
+			t.Fatalf("write schedule: %v", err)
+		}
+	}
+
+	before, err := calcDirsSignature(dirs, false)
+	if err != nil {
+		t.Fatalf("calcDirsSignature() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dirs[1], "b.yaml"), []byte("name: b\n"), 0o600); err != nil {
+		t.Fatalf("write schedule: %v", err)
+	}
+	after, err := calcDirsSignature(dirs, false)
+	if err != nil {
+		t.Fatalf("calcDirsSignature() error = %v", err)
+	}
+	if before == after {
+		t.Fatal("signature did not change after a change in the second directory")
+	}
+}
+
+func TestCalcDirSignature_Recursive(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	nested := filepath.Join(dir, "team-a")
+	if err := os.Mkdir(nested, 0o700); err != nil {
+		t.Fatalf("create nested dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(nested, "a.yaml"), []byte("name: a\n"), 0o600); err != nil {
+		t.Fatalf("write schedule: %v", err)
+	}
+
+	flat, err := calcDirSignature(dir, false)
+	if err != nil {
+		t.Fatalf("calcDirSignature() error = %v", err)
+	}
+	before, err := calcDirSignature(dir, true)
+	if err != nil {
+		t.Fatalf("calcDirSignature() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(nested, "a.yaml"), []byte("name: b\n"), 0o600); err != nil {
+		t.Fatalf("update schedule: %v", err)
+	}
+
+	flatAfter, err := calcDirSignature(dir, false)
+	if err != nil {
+		t.Fatalf("calcDirSignature() error = %v", err)
+	}
+	if flat != flatAfter {
+		t.Fatal("non-recursive signature changed after a change in a subdirectory")
+	}
+	after, err := calcDirSignature(dir, true)
+	if err != nil {
+		t.Fatalf("calcDirSignature() error = %v", err)
+	}
+	if before == after {
+		t.Fatal("recursive signature did not change after a change in a subdirectory")
+	}
+}
+
+func TestReloader_RefreshesBeforeCheck(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	var reloadCalls atomic.Int32
+	r, err := New([]string{dir}, false, time.Minute, func(context.Context) error {
+		reloadCalls.Add(1)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	r.lastSig, _ = calcDirsSignature([]string{dir}, false)
+	r.hasLastSig = true
+	r.SetRefresh(func(context.Context) error {
+		return os.WriteFile(filepath.Join(dir, "remote.yaml"), []byte("name: remote\n"), 0o600)
+	})
+
+	r.tick(context.Background(), true)
+	if got := reloadCalls.Load(); got != 1 {
+		t.Fatalf("reload calls after refresh = %d, want 1", got)
+	}
+}
+
+func TestReloader_TriggerChecksBeforeInterval(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	reloaded := make(chan struct{}, 1)
+	r, err := New([]string{dir}, false, time.Hour, func(context.Context) error {
+		reloaded <- struct{}{}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Start(ctx)
+
+	// The first trigger may be handled before Start reads the initial
+	// signature, so keep changing the directory until a reload is seen.
+	deadline := time.After(5 * time.Second)
+	for i := 0; ; i++ {
+		if err := os.WriteFile(filepath.Join(dir, "watched.yaml"), []byte(fmt.Sprintf("name: watched-%d\n", i)), 0o600); err != nil {
+			t.Fatalf("write schedule: %v", err)
+		}
+		r.Trigger()
+		select {
+		case <-reloaded:
+			return
+		case <-deadline:
+			t.Fatal("trigger did not reload schedules before the interval")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+func TestReloader_FileTriggersOnlyOnConfigChanges(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte("timezone: UTC\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	var reloadCalls atomic.Int32
+	r, err := NewFile(configPath, 20*time.Millisecond, func(context.Context) error {
+		reloadCalls.Add(1)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("NewFile() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.Start(ctx)
+	}()
+
+	time.Sleep(60 * time.Millisecond)
+
+	if err := os.WriteFile(filepath.Join(dir, "schedule.yaml"), []byte("name: a\n"), 0o600); err != nil {
+		t.Fatalf("write sibling file: %v", err)
+	}
+	time.Sleep(80 * time.Millisecond)
+	if got := reloadCalls.Load(); got != 0 {
+		t.Fatalf("reload calls after sibling change = %d, want 0", got)
+	}
+
+	if err := os.WriteFile(configPath, []byte("timezone: Europe/Moscow\n"), 0o600); err != nil {
+		t.Fatalf("update config: %v", err)
+	}
+
+	deadline := time.Now().Add(700 * time.Millisecond)
+	for reloadCalls.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if got := reloadCalls.Load(); got != 1 {
+		t.Fatalf("reload calls after config change = %d, want 1", got)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(1 * time.Second):
+		t.Fatal("reloader did not stop after cancel")
+	}
+}
+
+func TestReloader_KeepsLastKnownGoodWhileDirectoryIsUnavailable(t *testing.T) {
+	t.Parallel()
+
+	dirA, dirB := t.TempDir(), filepath.Join(t.TempDir(), "b")
+	pathA, pathB := filepath.Join(dirA, "a.yaml"), filepath.Join(dirB, "b.yaml")
+	writeSchedule := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("write schedule: %v", err)
+		}
+	}
+	writeSchedule(pathA, "name: a\n")
+	writeSchedule(pathB, "name: b\n")
+
+	var reloadCalls atomic.Int32
+	r, err := New([]string{dirA, dirB}, false, time.Minute, func(context.Context) error {
+		reloadCalls.Add(1)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	var reported []map[string]error
+	r.SetKeepLastKnownGood(func(dirs map[string]error) {
+		reported = append(reported, dirs)
+	})
+	r.lastSig, _ = calcDirsSignature([]string{dirA, dirB}, false)
+	r.hasLastSig = true
+	r.populated = r.populatedDirs()
+
+	// The directory is emptied during a volume remount.
+	if err := os.Remove(pathB); err != nil {
+		t.Fatalf("remove schedule: %v", err)
+	}
+	r.check(context.Background())
+	r.check(context.Background())
+	if got := reloadCalls.Load(); got != 0 {
+		t.Fatalf("reload calls with an emptied directory = %d, want 0", got)
+	}
+	if len(reported) != 1 || !errors.Is(reported[0][dirB], ErrDirEmpty) || len(reported[0]) != 1 {
+		t.Fatalf("reported = %v, want %s reported once as emptied", reported, dirB)
+	}
+
+	// It comes back unchanged: nothing to reload.
+	writeSchedule(pathB, "name: b\n")
+	r.check(context.Background())
+	if got := reloadCalls.Load(); got != 0 {
+		t.Fatalf("reload calls after the directory is back = %d, want 0", got)
+	}
+	if len(reported) != 2 || reported[1] != nil {
+		t.Fatalf("reported = %v, want the directory reported back", reported)
+	}
+
+	// An unreadable directory is kept as well.
+	if err := os.RemoveAll(dirB); err != nil {
+		t.Fatalf("remove directory: %v", err)
+	}
+	r.check(context.Background())
+	if got := reloadCalls.Load(); got != 0 || len(reported) != 3 || reported[2][dirB] == nil {
+		t.Fatalf("reload calls = %d, reported = %v; want no reload and %s reported", got, reported, dirB)
+	}
+
+	// Changes made once the directory is back are reloaded.
+	writeSchedule(pathB, "name: b2\n")
+	r.check(context.Background())
+	if got := reloadCalls.Load(); got != 1 {
+		t.Fatalf("reload calls after a change = %d, want 1", got)
+	}
+}
+
+func TestReloader_AppliesEmptiedDirectoryWithoutKeep(t *testing.T) {
+	t.Parallel()
+
+	dirA, dirB := t.TempDir(), t.TempDir()
+	pathB := filepath.Join(dirB, "b.yaml")
+	for _, path := range []string{filepath.Join(dirA, "a.yaml"), pathB} {
+		if err := os.WriteFile(path, []byte("name: "+filepath.Base(path)+"\n"), 0o600); err != nil {
+			t.Fatalf("write schedule: %v", err)
+		}
+	}
+
+	var reloadCalls atomic.Int32
+	r, err := New([]string{dirA, dirB}, false, time.Minute, func(context.Context) error {
+		reloadCalls.Add(1)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	r.lastSig, _ = calcDirsSignature([]string{dirA, dirB}, false)
+	r.hasLastSig = true
+
+	if err := os.Remove(pathB); err != nil {
+		t.Fatalf("remove schedule: %v", err)
+	}
+	r.check(context.Background())
+	if got := reloadCalls.Load(); got != 1 {
+		t.Fatalf("reload calls with an emptied directory = %d, want 1", got)
+	}
+}
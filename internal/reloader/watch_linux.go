@@ -0,0 +1,145 @@
+package reloader
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"github.com/rs/zerolog/log"
+)
+
+// watchMask selects the inotify events that may change the schedule files of
+// a directory, including the symlink swaps of Kubernetes volume updates.
+const watchMask = syscall.IN_CREATE | syscall.IN_DELETE | syscall.IN_CLOSE_WRITE | syscall.IN_MODIFY |
+	syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO | syscall.IN_DELETE_SELF | syscall.IN_MOVE_SELF
+
+// inotifyWatcher reports changes of directories through inotify.
+type inotifyWatcher struct {
+	// fd is kept apart from file, since File.Fd would make reads blocking.
+	fd        int
+	file      *os.File
+	recursive bool
+	events    chan struct{}
+
+	mu    sync.Mutex
+	paths map[int32]string
+}
+
+// watchDirs watches dirs and, when recursive, their subdirectories except
+// hidden ones, including subdirectories created later.
+func watchDirs(dirs []string, recursive bool) (dirWatcher, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("init inotify: %w", err)
+	}
+	// A non-blocking descriptor is read through the runtime poller, so Close
+	// unblocks a pending read.
+	w := &inotifyWatcher{
+		fd:        fd,
+		file:      os.NewFile(uintptr(fd), "inotify"),
+		recursive: recursive,
+		events:    make(chan struct{}, 1),
+		paths:     make(map[int32]string),
+	}
+	for _, dir := range dirs {
+		if err := w.addTree(dir); err != nil {
+			w.file.Close()
+			return nil, err
+		}
+	}
+
+	go w.read()
+	return w, nil
+}
+
+// Events returns a channel receiving a value after changes. Changes made
+// before the value is received are coalesced.
+func (w *inotifyWatcher) Events() <-chan struct{} {
+	return w.events
+}
+
+// Close stops watching.
+func (w *inotifyWatcher) Close() error {
+	return w.file.Close()
+}
+
+// addTree watches dir and, when recursive, its subdirectories.
+func (w *inotifyWatcher) addTree(dir string) error {
+	if !w.recursive {
+		return w.add(dir)
+	}
+	return filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		if path != dir && strings.HasPrefix(entry.Name(), ".") {
+			return filepath.SkipDir
+		}
+		return w.add(path)
+	})
+}
+
+func (w *inotifyWatcher) add(dir string) error {
+	wd, err := syscall.InotifyAddWatch(w.fd, dir, watchMask)
+	if err != nil {
+		return fmt.Errorf("watch %q: %w", dir, err)
+	}
+
+	w.mu.Lock()
+	w.paths[int32(wd)] = dir
+	w.mu.Unlock()
+	return nil
+}
+
+// read reports events until the watcher is closed.
+func (w *inotifyWatcher) read() {
+	buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+	for {
+		n, err := w.file.Read(buf)
+		if err != nil {
+			if !errors.Is(err, os.ErrClosed) {
+				log.Warn().Err(err).Msg("Failed to read schedules directory changes")
+			}
+			return
+		}
+
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+			event := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			name := strings.TrimRight(string(buf[offset+syscall.SizeofInotifyEvent:offset+syscall.SizeofInotifyEvent+int(event.Len)]), "\x00")
+			offset += syscall.SizeofInotifyEvent + int(event.Len)
+			w.handle(event, name)
+		}
+
+		select {
+		case w.events <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// handle keeps the watches in sync with the directory tree.
+func (w *inotifyWatcher) handle(event *syscall.InotifyEvent, name string) {
+	w.mu.Lock()
+	dir, ok := w.paths[event.Wd]
+	if event.Mask&syscall.IN_IGNORED != 0 {
+		delete(w.paths, event.Wd)
+	}
+	w.mu.Unlock()
+
+	created := event.Mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0
+	if !ok || !w.recursive || !created || event.Mask&syscall.IN_ISDIR == 0 || strings.HasPrefix(name, ".") {
+		return
+	}
+	if err := w.addTree(filepath.Join(dir, name)); err != nil {
+		log.Warn().Err(err).Msg("Failed to watch new schedules directory")
+	}
+}
@@ -0,0 +1,62 @@
+package reloader
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReloader_ReloadsOnFileChangeNotification(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	reloaded := make(chan struct{}, 10)
+	r, err := New([]string{dir}, true, time.Hour, func(context.Context) error {
+		reloaded <- struct{}{}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Start(ctx)
+	// Start hashes the directory and adds watches before changes are seen.
+	time.Sleep(100 * time.Millisecond)
+
+	waitReload := func(what string) {
+		t.Helper()
+		select {
+		case <-reloaded:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s was not reloaded before the interval", what)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "a.yaml"), []byte("name: a\n"), 0o600); err != nil {
+		t.Fatalf("write schedule: %v", err)
+	}
+	waitReload("new schedule file")
+
+	// Subdirectories created later are watched too.
+	team := filepath.Join(dir, "team-a")
+	if err := os.Mkdir(team, 0o700); err != nil {
+		t.Fatalf("create subdirectory: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if err := os.WriteFile(filepath.Join(team, "b.yaml"), []byte("name: b\n"), 0o600); err != nil {
+		t.Fatalf("write schedule: %v", err)
+	}
+	waitReload("schedule file of a new subdirectory")
+}
+
+func TestWatchDirs_MissingDirectory(t *testing.T) {
+	t.Parallel()
+
+	if _, err := watchDirs([]string{filepath.Join(t.TempDir(), "missing")}, false); err == nil {
+		t.Fatal("watchDirs() of a missing directory succeeded")
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package reloader
+
+import "errors"
+
+// watchDirs is not supported without inotify, so directories are polled.
+func watchDirs([]string, bool) (dirWatcher, error) {
+	return nil, errors.New("file change notifications are not supported on this platform")
+}
@@ -0,0 +1,164 @@
+package resource
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+// LastAction is the last action the scheduler performed on a resource.
+type LastAction struct {
+	At           time.Time `json:"at"`
+	ResourceType string    `json:"resource_type"`
+	ResourceID   string    `json:"resource_id"`
+	FolderID     string    `json:"folder_id,omitempty"`
+	Schedule     string    `json:"schedule"`
+	Action       string    `json:"action"`
+	// StopMode is set for stop actions that paused the resource.
+	StopMode    config.StopMode `json:"stop_mode,omitempty"`
+	OperationID string          `json:"operation_id,omitempty"`
+	ExecutionID string          `json:"execution_id,omitempty"`
+}
+
+// ResultState returns the state the action leaves the resource in: running
+// after a start or restart and stopped or paused after a stop. It is empty
+// for actions that do not change the state.
+func (a LastAction) ResultState() string {
+	switch a.Action {
+	case "start", "restart":
+		return "running"
+	case "stop":
+		if a.StopMode == config.StopModePause {
+			return "paused"
+		}
+		return "stopped"
+	default:
+		return ""
+	}
+}
+
+// ActionStore keeps the last action the scheduler performed per resource.
+// With a file, the actions are loaded from it and written back after every
+// recorded action, so they survive restarts.
+type ActionStore struct {
+	actions map[string]LastAction
+	path    string
+	mu      sync.RWMutex
+}
+
+// NewActionStore creates an ActionStore persisted to the file at path, or
+// kept in memory only if path is empty. Actions of an existing file are
+// loaded.
+func NewActionStore(path string) (*ActionStore, error) {
+	s := &ActionStore{actions: make(map[string]LastAction), path: path}
+	if path == "" {
+		return s, nil
+	}
+
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read last actions: %w", err)
+	}
+	var actions []LastAction
+	if err := json.Unmarshal(raw, &actions); err != nil {
+		return nil, fmt.Errorf("decode last actions %s: %w", path, err)
+	}
+	for _, a := range actions {
+		s.actions[actionKey(a.ResourceType, a.ResourceID)] = a
+	}
+	return s, nil
+}
+
+// Record stores the action as the last one of its resource. The action is
+// kept in memory even when writing the file of the store fails.
+func (s *ActionStore) Record(action LastAction) error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.actions[actionKey(action.ResourceType, action.ResourceID)] = action
+	if s.path == "" {
+		return nil
+	}
+	if err := s.saveLocked(); err != nil {
+		return fmt.Errorf("persist last actions: %w", err)
+	}
+	return nil
+}
+
+// LastAction returns the last action performed on the resource.
+func (s *ActionStore) LastAction(resource config.Resource) (LastAction, bool) {
+	if s == nil {
+		return LastAction{}, false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	action, ok := s.actions[actionKey(resource.Type, resource.ID)]
+	return action, ok
+}
+
+// LastActions returns the last actions of all resources sorted by resource
+// type and ID.
+func (s *ActionStore) LastActions() []LastAction {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.RLock()
+	actions := slices.Collect(maps.Values(s.actions))
+	s.mu.RUnlock()
+
+	sortLastActions(actions)
+	return actions
+}
+
+// saveLocked writes the actions to a temporary file renamed over the file
+// of the store, so a crash never leaves a truncated file. It must be called
+// with s.mu held.
+func (s *ActionStore) saveLocked() error {
+	actions := slices.Collect(maps.Values(s.actions))
+	sortLastActions(actions)
+	raw, err := json.MarshalIndent(actions, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+func sortLastActions(actions []LastAction) {
+	slices.SortFunc(actions, func(a, b LastAction) int {
+		return cmp.Or(cmp.Compare(a.ResourceType, b.ResourceType), cmp.Compare(a.ResourceID, b.ResourceID))
+	})
+}
+
+func actionKey(resourceType, id string) string {
+	return resourceType + ":" + id
+}
@@ -0,0 +1,80 @@
+package resource
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+func TestActionStorePersistsLastActions(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "last-actions.json")
+	store, err := NewActionStore(path)
+	if err != nil {
+		t.Fatalf("NewActionStore() error = %v", err)
+	}
+
+	at := time.Date(2026, time.May, 4, 9, 0, 0, 0, time.UTC)
+	vm := config.Resource{Type: "vm", ID: "fhm123"}
+	for _, action := range []LastAction{
+		{At: at, ResourceType: "vm", ResourceID: "fhm123", Schedule: "dev", Action: "start", OperationID: "op-1"},
+		{At: at, ResourceType: "k8s_cluster", ResourceID: "cat123", Schedule: "k8s", Action: "stop", OperationID: "op-2"},
+		{At: at.Add(time.Hour), ResourceType: "vm", ResourceID: "fhm123", Schedule: "dev", Action: "stop", OperationID: "op-3"},
+	} {
+		if err := store.Record(action); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	reloaded, err := NewActionStore(path)
+	if err != nil {
+		t.Fatalf("NewActionStore() after restart error = %v", err)
+	}
+	last, ok := reloaded.LastAction(vm)
+	if !ok || last.OperationID != "op-3" || last.ResultState() != "stopped" || !last.At.Equal(at.Add(time.Hour)) {
+		t.Fatalf("LastAction() = %+v, %v; want stop op-3", last, ok)
+	}
+	actions := reloaded.LastActions()
+	if len(actions) != 2 || actions[0].ResourceType != "k8s_cluster" {
+		t.Fatalf("LastActions() = %+v, want cluster first", actions)
+	}
+}
+
+func TestActionStoreInMemory(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewActionStore("")
+	if err != nil {
+		t.Fatalf("NewActionStore() error = %v", err)
+	}
+	if err := store.Record(LastAction{ResourceType: "vm", ResourceID: "fhm123", Action: "snapshot"}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	last, ok := store.LastAction(config.Resource{Type: "vm", ID: "fhm123"})
+	if !ok || last.ResultState() != "" {
+		t.Fatalf("LastAction() = %+v, %v; want snapshot without result state", last, ok)
+	}
+}
+
+func TestLastActionResultState(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		action LastAction
+		want   string
+	}{
+		{LastAction{Action: "start"}, "running"},
+		{LastAction{Action: "restart"}, "running"},
+		{LastAction{Action: "stop"}, "stopped"},
+		{LastAction{Action: "stop", StopMode: config.StopModePause}, "paused"},
+		{LastAction{Action: "snapshot"}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.action.ResultState(); got != tt.want {
+			t.Errorf("ResultState() of %+v = %q, want %q", tt.action, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	albpb "github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
+	computepb "github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
+	instancegrouppb "github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1/instancegroup"
+	greenplumpb "github.com/yandex-cloud/go-genproto/yandex/cloud/mdb/greenplum/v1"
+	mongodbpb "github.com/yandex-cloud/go-genproto/yandex/cloud/mdb/mongodb/v1"
+	vpcpb "github.com/yandex-cloud/go-genproto/yandex/cloud/vpc/v1"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/yc"
+)
+
+// fakeClient is an in-memory yc.ClientInterface with compute instances,
+// instance groups, MongoDB and Greenplum clusters, load balancers and
+// addresses only.
+type fakeClient struct {
+	yc.ClientInterface
+	instances     map[string]computepb.Instance_Status
+	groups        map[string]instancegrouppb.InstanceGroup_Status
+	mongodb       map[string]mongodbpb.Cluster_Status
+	greenplum     map[string]greenplumpb.Cluster_Status
+	loadBalancers map[string]albpb.LoadBalancer_Status
+	addresses     map[string]*vpcpb.Address
+	restoredIPs   int
+}
+
+func (c *fakeClient) GetInstance(_ context.Context, _, instanceID string) (*computepb.Instance, error) {
+	return &computepb.Instance{Id: instanceID, Status: c.instances[instanceID]}, nil
+}
+
+func (c *fakeClient) StartInstance(_ context.Context, _, instanceID string) error {
+	c.instances[instanceID] = computepb.Instance_RUNNING
+	return nil
+}
+
+func (c *fakeClient) RestoreInstancePublicIPs(context.Context, string, string) error {
+	c.restoredIPs++
+	return nil
+}
+
+func (c *fakeClient) StopInstance(_ context.Context, _, instanceID string) error {
+	c.instances[instanceID] = computepb.Instance_STOPPED
+	return nil
+}
+
+func (c *fakeClient) GetInstanceGroup(_ context.Context, _, groupID string) (*instancegrouppb.InstanceGroup, error) {
+	return &instancegrouppb.InstanceGroup{Id: groupID, Status: c.groups[groupID]}, nil
+}
+
+func (c *fakeClient) PauseInstanceGroup(_ context.Context, _, groupID string) error {
+	c.groups[groupID] = instancegrouppb.InstanceGroup_PAUSED
+	return nil
+}
+
+func (c *fakeClient) ResumeInstanceGroup(_ context.Context, _, groupID string) error {
+	if c.groups[groupID] == instancegrouppb.InstanceGroup_PAUSED {
+		c.groups[groupID] = instancegrouppb.InstanceGroup_ACTIVE
+	}
+	return nil
+}
+
+func (c *fakeClient) StartInstanceGroup(context.Context, string, string) error {
+	return nil
+}
+
+func (c *fakeClient) GetMongoDBCluster(_ context.Context, _, clusterID string) (*mongodbpb.Cluster, error) {
+	return &mongodbpb.Cluster{Id: clusterID, Status: c.mongodb[clusterID]}, nil
+}
+
+func (c *fakeClient) GetLoadBalancer(_ context.Context, _, loadBalancerID string) (*albpb.LoadBalancer, error) {
+	return &albpb.LoadBalancer{Id: loadBalancerID, Status: c.loadBalancers[loadBalancerID]}, nil
+}
+
+func (c *fakeClient) GetGreenplumCluster(_ context.Context, _, clusterID string) (*greenplumpb.Cluster, error) {
+	return &greenplumpb.Cluster{Id: clusterID, Status: c.greenplum[clusterID]}, nil
+}
+
+func (c *fakeClient) GetAddress(_ context.Context, _, addressID string) (*vpcpb.Address, error) {
+	return c.addresses[addressID], nil
+}
+
+func (c *fakeClient) SetAddressReserved(_ context.Context, addressID string, reserved bool) error {
+	c.addresses[addressID].Reserved = reserved
+	return nil
+}
+
+func TestYCOperatorWithMockClient(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeClient{instances: map[string]computepb.Instance_Status{"fhm1": computepb.Instance_RUNNING}}
+	checker := NewYCStateChecker(client)
+	operator := NewYCOperator(client)
+	vm := config.Resource{Type: "vm", ID: "fhm1", FolderID: "b1g1"}
+
+	if state, _, err := checker.GetState(context.Background(), vm); err != nil || state != "running" {
+		t.Fatalf("GetState() = %q, %v; want running", state, err)
+	}
+	if err := operator.Stop(context.Background(), vm, StopOptions{}); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if state, _, err := checker.GetState(context.Background(), vm); err != nil || state != "stopped" {
+		t.Fatalf("GetState() after Stop() = %q, %v; want stopped", state, err)
+	}
+}
+
+func TestYCOperatorPausesInstanceGroup(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeClient{groups: map[string]instancegrouppb.InstanceGroup_Status{"cl1": instancegrouppb.InstanceGroup_ACTIVE}}
+	checker := NewYCStateChecker(client)
+	operator := NewYCOperator(client)
+	group := config.Resource{Type: "instance_group", ID: "cl1", FolderID: "b1g1"}
+
+	opts := StopOptionsFromAction(&config.ActionConfig{StopMode: config.StopModePause})
+	if err := operator.Stop(context.Background(), group, opts); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if state, _, err := checker.GetState(context.Background(), group); err != nil || state != opts.TargetState() {
+		t.Fatalf("GetState() after pause = %q, %v; want %s", state, err, opts.TargetState())
+	}
+	if err := operator.Start(context.Background(), group, StartOptions{}); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if state, _, err := checker.GetState(context.Background(), group); err != nil || state != "running" {
+		t.Fatalf("GetState() after Start() = %q, %v; want running", state, err)
+	}
+}
+
+func TestYCOperatorPauseUnsupported(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeClient{instances: map[string]computepb.Instance_Status{"fhm1": computepb.Instance_RUNNING}}
+	vm := config.Resource{Type: "vm", ID: "fhm1", FolderID: "b1g1"}
+
+	err := NewYCOperator(client).Stop(context.Background(), vm, StopOptions{Pause: true})
+	if !errors.Is(err, ErrPauseUnsupported) {
+		t.Fatalf("Stop() error = %v, want ErrPauseUnsupported", err)
+	}
+	if client.instances["fhm1"] != computepb.Instance_RUNNING {
+		t.Fatalf("instance status = %v, want RUNNING", client.instances["fhm1"])
+	}
+}
+
+func TestYCOperatorRestoresPublicIPsOnlyWhenReleased(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		stop    *config.ActionConfig
+		restore int
+	}{
+		{name: "stop without release", stop: &config.ActionConfig{Enabled: true}},
+		{name: "stop with release", stop: &config.ActionConfig{Enabled: true, ReleasePublicIP: true}, restore: 1},
+	}
+	for _, tt := range tests {
+		client := &fakeClient{instances: map[string]computepb.Instance_Status{"fhm1": computepb.Instance_STOPPED}}
+		vm := config.Resource{Type: "vm", ID: "fhm1", FolderID: "b1g1"}
+		sch := config.Schedule{Resource: vm, Actions: config.Actions{Stop: tt.stop}}
+
+		if err := NewYCOperator(client).Start(context.Background(), vm, StartOptionsFromSchedule(sch)); err != nil {
+			t.Fatalf("%s: Start() error = %v", tt.name, err)
+		}
+		if client.restoredIPs != tt.restore {
+			t.Errorf("%s: public IP restores = %d, want %d", tt.name, client.restoredIPs, tt.restore)
+		}
+	}
+}
+
+func TestYCOperatorRefusesUnattachedAddress(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeClient{addresses: map[string]*vpcpb.Address{
+		"e9b1": {Id: "e9b1", Reserved: true, Used: true},
+		"e9b2": {Id: "e9b2", Reserved: true},
+	}}
+	operator := NewYCOperator(client)
+
+	attached := config.Resource{Type: "vpc_address", ID: "e9b1", FolderID: "b1g1"}
+	if err := operator.Stop(context.Background(), attached, StopOptions{}); err != nil {
+		t.Fatalf("Stop() attached address error = %v", err)
+	}
+	if client.addresses["e9b1"].GetReserved() {
+		t.Fatal("Stop() kept the attached address reserved")
+	}
+
+	unattached := config.Resource{Type: "vpc_address", ID: "e9b2", FolderID: "b1g1"}
+	if err := operator.Stop(context.Background(), unattached, StopOptions{}); !errors.Is(err, ErrAddressNotAttached) {
+		t.Fatalf("Stop() unattached address error = %v, want %v", err, ErrAddressNotAttached)
+	}
+	if !client.addresses["e9b2"].GetReserved() {
+		t.Fatal("Stop() released the unattached address")
+	}
+}
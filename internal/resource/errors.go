@@ -1,9 +1,79 @@
 package resource
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// ErrUnsupportedResourceType is returned when an operation is attempted
 	// on an unsupported resource type.
 	ErrUnsupportedResourceType = errors.New("unsupported resource type")
+
+	// ErrProvisionedInstancesMissing is returned when a serverless container
+	// is started without a positive provisioned_instances on the start action.
+	ErrProvisionedInstancesMissing = errors.New("provisioned_instances is not set on start action")
+
+	// ErrPauseUnsupported is returned when a stop action with stop_mode
+	// pause targets a resource type without a paused state.
+	ErrPauseUnsupported = errors.New("stop_mode pause is not supported by the resource type")
+
+	// ErrTargetSizeMissing is returned when a scale action has no positive
+	// target_size.
+	ErrTargetSizeMissing = errors.New("target_size is not set on scale action")
+
+	// ErrResizeSpecMissing is returned when a resize action sets none of
+	// platform_id, cores, core_fraction and memory_gb.
+	ErrResizeSpecMissing = errors.New("resize action sets no platform or resources")
+
+	// ErrPreemptibleMissing is returned when a preemptible action does not
+	// set preemptible.
+	ErrPreemptibleMissing = errors.New("preemptible is not set on preemptible action")
+
+	// ErrAddressNotAttached is returned when a stop action targets a
+	// vpc_address that is not attached to a resource. The platform deletes
+	// such an address once it becomes ephemeral.
+	ErrAddressNotAttached = errors.New("address is not attached to a resource")
+
+	// ErrTooManyMatches is returned when a name pattern matches more
+	// resources than allowed by max_matches.
+	ErrTooManyMatches = errors.New("name pattern matches too many resources")
+
+	// ErrPatternNotSupported is returned when name pattern resources cannot
+	// be resolved by the configured state checker.
+	ErrPatternNotSupported = errors.New("name pattern resolution is not supported")
 )
+
+// RestartError reports the phase of a restart that failed: "stop",
+// "wait_stopped" or "start".
+type RestartError struct {
+	Err   error
+	Phase string
+}
+
+// Error implements the error interface.
+func (e *RestartError) Error() string {
+	return fmt.Sprintf("restart %s: %v", e.Phase, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *RestartError) Unwrap() error {
+	return e.Err
+}
+
+// ResizeError reports the phase of a VM resize or scheduling policy change
+// that failed: "get", "stop", "wait_stopped", "update" or "start".
+type ResizeError struct {
+	Err   error
+	Phase string
+}
+
+// Error implements the error interface.
+func (e *ResizeError) Error() string {
+	return fmt.Sprintf("resize %s: %v", e.Phase, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ResizeError) Unwrap() error {
+	return e.Err
+}
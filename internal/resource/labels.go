@@ -0,0 +1,68 @@
+package resource
+
+import (
+	"context"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+// LabelReader provides read access to cloud resource labels.
+type LabelReader interface {
+	// GetLabels returns the labels of the resource, or nil for resource
+	// types whose labels are not read.
+	GetLabels(ctx context.Context, resource config.Resource) (map[string]string, error)
+}
+
+// FolderLabelReader provides read access to cloud folder labels.
+type FolderLabelReader interface {
+	// GetFolderLabels returns the labels of the folder.
+	GetFolderLabels(ctx context.Context, folderID string) (map[string]string, error)
+}
+
+// labeled is implemented by all Yandex Cloud resource messages with labels.
+type labeled interface {
+	GetLabels() map[string]string
+}
+
+// GetLabels returns the labels of the resource.
+func (c *YCStateChecker) GetLabels(ctx context.Context, resource config.Resource) (map[string]string, error) {
+	var (
+		object labeled
+		err    error
+	)
+	switch resource.Type {
+	case "vm":
+		object, err = c.client.GetInstance(ctx, resource.FolderID, resource.ID)
+	case "k8s_cluster":
+		object, err = c.client.GetCluster(ctx, resource.FolderID, resource.ID)
+	case "k8s_node_group":
+		object, err = c.client.GetNodeGroup(ctx, resource.FolderID, resource.ID)
+	case "instance_group":
+		object, err = c.client.GetInstanceGroup(ctx, resource.FolderID, resource.ID)
+	case "mdb_mongodb":
+		object, err = c.client.GetMongoDBCluster(ctx, resource.FolderID, resource.ID)
+	case "mdb_greenplum":
+		object, err = c.client.GetGreenplumCluster(ctx, resource.FolderID, resource.ID)
+	case "alb":
+		object, err = c.client.GetLoadBalancer(ctx, resource.FolderID, resource.ID)
+	case "vpc_address":
+		object, err = c.client.GetAddress(ctx, resource.FolderID, resource.ID)
+	default:
+		// NAT gateway state lives in route tables and serverless containers
+		// are read by revision, so their labels are not read.
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return object.GetLabels(), nil
+}
+
+// GetFolderLabels returns the labels of the folder.
+func (c *YCStateChecker) GetFolderLabels(ctx context.Context, folderID string) (map[string]string, error) {
+	folder, err := c.client.GetFolder(ctx, folderID)
+	if err != nil {
+		return nil, err
+	}
+	return folder.GetLabels(), nil
+}
@@ -0,0 +1,73 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sentoz/yc-sheduler/internal/yc"
+)
+
+// FolderResource is a resource found in a folder with its current state.
+type FolderResource struct {
+	Type   string
+	ID     string
+	Name   string
+	Labels map[string]string
+	// State is "running", "stopped" or a transitional state name, as
+	// reported by StateChecker.
+	State        string
+	Transitional bool
+}
+
+// ListFolder returns the VMs, Kubernetes clusters and node groups of the
+// folder with their current states, grouped by type in that order.
+func ListFolder(ctx context.Context, client yc.ClientInterface, folderID string) ([]FolderResource, error) {
+	instances, err := client.ListInstances(ctx, folderID)
+	if err != nil {
+		return nil, fmt.Errorf("list instances: %w", err)
+	}
+	clusters, err := client.ListClusters(ctx, folderID)
+	if err != nil {
+		return nil, fmt.Errorf("list clusters: %w", err)
+	}
+	nodeGroups, err := client.ListNodeGroups(ctx, folderID)
+	if err != nil {
+		return nil, fmt.Errorf("list node groups: %w", err)
+	}
+
+	resources := make([]FolderResource, 0, len(instances)+len(clusters)+len(nodeGroups))
+	for _, instance := range instances {
+		state, isTransitional := vmState(instance)
+		resources = append(resources, FolderResource{
+			Type:         "vm",
+			ID:           instance.GetId(),
+			Name:         instance.GetName(),
+			Labels:       instance.GetLabels(),
+			State:        state,
+			Transitional: isTransitional,
+		})
+	}
+	for _, cluster := range clusters {
+		state, isTransitional := clusterState(cluster)
+		resources = append(resources, FolderResource{
+			Type:         "k8s_cluster",
+			ID:           cluster.GetId(),
+			Name:         cluster.GetName(),
+			Labels:       cluster.GetLabels(),
+			State:        state,
+			Transitional: isTransitional,
+		})
+	}
+	for _, nodeGroup := range nodeGroups {
+		state, isTransitional := nodeGroupState(nodeGroup)
+		resources = append(resources, FolderResource{
+			Type:         "k8s_node_group",
+			ID:           nodeGroup.GetId(),
+			Name:         nodeGroup.GetName(),
+			Labels:       nodeGroup.GetLabels(),
+			State:        state,
+			Transitional: isTransitional,
+		})
+	}
+	return resources, nil
+}
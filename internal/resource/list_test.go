@@ -0,0 +1,76 @@
+package resource
+
+import (
+	"context"
+	"testing"
+
+	computepb "github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
+	k8spb "github.com/yandex-cloud/go-genproto/yandex/cloud/k8s/v1"
+
+	"github.com/sentoz/yc-sheduler/internal/yc"
+)
+
+// listClient is a yc.ClientInterface listing fixed folder resources.
+type listClient struct {
+	yc.ClientInterface
+	instances  []*computepb.Instance
+	clusters   []*k8spb.Cluster
+	nodeGroups []*k8spb.NodeGroup
+}
+
+func (c listClient) ListInstances(context.Context, string) ([]*computepb.Instance, error) {
+	return c.instances, nil
+}
+
+func (c listClient) ListClusters(context.Context, string) ([]*k8spb.Cluster, error) {
+	return c.clusters, nil
+}
+
+func (c listClient) ListNodeGroups(context.Context, string) ([]*k8spb.NodeGroup, error) {
+	return c.nodeGroups, nil
+}
+
+func TestListFolder(t *testing.T) {
+	t.Parallel()
+
+	client := listClient{
+		instances: []*computepb.Instance{
+			{Id: "fhm1", Name: "dev-api", Labels: map[string]string{"env": "dev"}, Status: computepb.Instance_RUNNING},
+			{Id: "fhm2", Name: "dev-worker", Status: computepb.Instance_STOPPING},
+		},
+		clusters: []*k8spb.Cluster{
+			{Id: "cat1", Name: "dev", Status: k8spb.Cluster_STOPPED},
+		},
+		nodeGroups: []*k8spb.NodeGroup{{
+			Id:          "cat2",
+			Name:        "dev-workers",
+			Status:      k8spb.NodeGroup_RUNNING,
+			ScalePolicy: &k8spb.ScalePolicy{ScaleType: &k8spb.ScalePolicy_FixedScale_{FixedScale: &k8spb.ScalePolicy_FixedScale{Size: 0}}},
+		}},
+	}
+
+	resources, err := ListFolder(context.Background(), client, "b1g1")
+	if err != nil {
+		t.Fatalf("ListFolder() error = %v", err)
+	}
+
+	want := []FolderResource{
+		{Type: "vm", ID: "fhm1", Name: "dev-api", State: "running"},
+		{Type: "vm", ID: "fhm2", Name: "dev-worker", State: "STOPPING", Transitional: true},
+		{Type: "k8s_cluster", ID: "cat1", Name: "dev", State: "stopped"},
+		// A node group scaled to zero is reported stopped like GetState does.
+		{Type: "k8s_node_group", ID: "cat2", Name: "dev-workers", State: "stopped"},
+	}
+	if len(resources) != len(want) {
+		t.Fatalf("ListFolder() = %+v, want %d resources", resources, len(want))
+	}
+	for i, w := range want {
+		got := resources[i]
+		if got.Type != w.Type || got.ID != w.ID || got.Name != w.Name || got.State != w.State || got.Transitional != w.Transitional {
+			t.Errorf("resource %d = %+v, want %+v", i, got, w)
+		}
+	}
+	if resources[0].Labels["env"] != "dev" {
+		t.Errorf("vm labels = %v, want env=dev", resources[0].Labels)
+	}
+}
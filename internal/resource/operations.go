@@ -2,6 +2,9 @@ package resource
 
 import (
 	"context"
+	"time"
+
+	computepb "github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
 
 	"github.com/sentoz/yc-sheduler/internal/config"
 	"github.com/sentoz/yc-sheduler/internal/yc"
@@ -10,41 +13,358 @@ import (
 // Operator provides an interface for performing operations on resources.
 type Operator interface {
 	// Start starts the resource.
-	Start(ctx context.Context, resource config.Resource) error
+	Start(ctx context.Context, resource config.Resource, opts StartOptions) error
 	// Stop stops the resource.
-	Stop(ctx context.Context, resource config.Resource) error
+	Stop(ctx context.Context, resource config.Resource, opts StopOptions) error
+	// Snapshot creates snapshots of the resource disks, keeping at most
+	// retention newest snapshots per disk (0 keeps all).
+	Snapshot(ctx context.Context, resource config.Resource, retention int) error
+	// Restart stops the resource, waits until it is stopped and starts it.
+	Restart(ctx context.Context, resource config.Resource, opts RestartOptions) error
+	// Scale sets the fixed scale size of a group resource.
+	Scale(ctx context.Context, resource config.Resource, targetSize int) error
+	// Resize changes the platform and computing resources of a VM, stopping
+	// it for the update and starting it again if it was running.
+	Resize(ctx context.Context, resource config.Resource, opts ResizeOptions) error
+	// SetPreemptible switches a VM to or from the preemptible scheduling
+	// policy, stopping it for the update and starting it again if it was running.
+	SetPreemptible(ctx context.Context, resource config.Resource, preemptible bool) error
+}
+
+// StartOptions holds optional start behavior configured on the start action.
+type StartOptions struct {
+	// ProvisionedInstances is the number of provisioned instances restored
+	// for a serverless container.
+	ProvisionedInstances int
+	// RestorePublicIP attaches the public IP addresses a stop with
+	// release_public_ip released from a VM.
+	RestorePublicIP bool
+}
+
+// StartOptionsFromAction builds StartOptions from a start action configuration.
+func StartOptionsFromAction(action *config.ActionConfig) StartOptions {
+	if action == nil {
+		return StartOptions{}
+	}
+	return StartOptions{
+		ProvisionedInstances: action.ProvisionedInstances,
+	}
+}
+
+// StartOptionsFromSchedule builds StartOptions from the start action of a
+// schedule. Public IPs are restored only if its stop action releases them.
+func StartOptionsFromSchedule(sch config.Schedule) StartOptions {
+	opts := StartOptionsFromAction(sch.Actions.Start)
+	opts.RestorePublicIP = sch.Actions.Stop != nil && sch.Actions.Stop.ReleasePublicIP
+	return opts
+}
+
+// StopOptions holds optional stop behavior configured on the stop action.
+type StopOptions struct {
+	// ReleasePublicIP releases public IP addresses of a stopped VM.
+	ReleasePublicIP bool
+	// Pause pauses the resource instead of stopping it.
+	Pause bool
+}
+
+// TargetState returns the state the stop leaves the resource in: paused when
+// it pauses the resource and stopped otherwise.
+func (o StopOptions) TargetState() string {
+	if o.Pause {
+		return "paused"
+	}
+	return "stopped"
+}
+
+// StopOptionsFromAction builds StopOptions from a stop action configuration.
+func StopOptionsFromAction(action *config.ActionConfig) StopOptions {
+	if action == nil {
+		return StopOptions{}
+	}
+	return StopOptions{
+		ReleasePublicIP: action.ReleasePublicIP,
+		Pause:           action.StopMode == config.StopModePause,
+	}
+}
+
+// RestartOptions holds the stop and start behavior of a restart.
+type RestartOptions struct {
+	Stop  StopOptions
+	Start StartOptions
+}
+
+// RestartOptionsFromAction builds RestartOptions from a restart action configuration.
+// A restart always stops the resource, so stop_mode is ignored.
+func RestartOptionsFromAction(action *config.ActionConfig) RestartOptions {
+	stop := StopOptionsFromAction(action)
+	stop.Pause = false
+	start := StartOptionsFromAction(action)
+	start.RestorePublicIP = stop.ReleasePublicIP
+	return RestartOptions{
+		Stop:  stop,
+		Start: start,
+	}
+}
+
+// ResizeOptions holds the VM spec set by a resize action. Zero fields keep
+// the current value.
+type ResizeOptions struct {
+	PlatformID   string
+	Cores        int
+	CoreFraction int
+	MemoryGB     int
+}
+
+// ResizeOptionsFromAction builds ResizeOptions from a resize action configuration.
+func ResizeOptionsFromAction(action *config.ActionConfig) ResizeOptions {
+	if action == nil {
+		return ResizeOptions{}
+	}
+	return ResizeOptions{
+		PlatformID:   action.PlatformID,
+		Cores:        action.Cores,
+		CoreFraction: action.CoreFraction,
+		MemoryGB:     action.MemoryGB,
+	}
+}
+
+// instanceSpec converts the options into a Yandex Cloud instance spec.
+func (o ResizeOptions) instanceSpec() yc.InstanceSpec {
+	return yc.InstanceSpec{
+		PlatformID:   o.PlatformID,
+		Cores:        int64(o.Cores),
+		CoreFraction: int64(o.CoreFraction),
+		Memory:       int64(o.MemoryGB) << 30,
+	}
 }
 
 // YCOperator implements Operator using Yandex Cloud client.
 type YCOperator struct {
-	client *yc.Client
+	client yc.ClientInterface
 }
 
 // NewYCOperator creates a new YCOperator.
-func NewYCOperator(client *yc.Client) *YCOperator {
+func NewYCOperator(client yc.ClientInterface) *YCOperator {
 	return &YCOperator{client: client}
 }
 
 // Start starts the resource.
-func (o *YCOperator) Start(ctx context.Context, resource config.Resource) error {
+func (o *YCOperator) Start(ctx context.Context, resource config.Resource, opts StartOptions) error {
 	switch resource.Type {
 	case "vm":
+		// Public IPs released by a previous stop are attached before start;
+		// this is a no-op for instances without released addresses.
+		if opts.RestorePublicIP {
+			if err := o.client.RestoreInstancePublicIPs(ctx, resource.FolderID, resource.ID); err != nil {
+				return err
+			}
+		}
 		return o.client.StartInstance(ctx, resource.FolderID, resource.ID)
 	case "k8s_cluster":
 		return o.client.StartCluster(ctx, resource.FolderID, resource.ID)
+	case "k8s_node_group":
+		return o.client.StartNodeGroup(ctx, resource.FolderID, resource.ID)
+	case "instance_group":
+		// A group paused by a stop with stop_mode pause is resumed; a group
+		// scaled to zero is scaled back.
+		if err := o.client.ResumeInstanceGroup(ctx, resource.FolderID, resource.ID); err != nil {
+			return err
+		}
+		return o.client.StartInstanceGroup(ctx, resource.FolderID, resource.ID)
+	case "mdb_mongodb":
+		return o.client.StartMongoDBCluster(ctx, resource.FolderID, resource.ID)
+	case "mdb_greenplum":
+		return o.client.StartGreenplumCluster(ctx, resource.FolderID, resource.ID)
+	case "alb":
+		return o.client.StartLoadBalancer(ctx, resource.FolderID, resource.ID)
+	case "vpc_address":
+		return o.client.SetAddressReserved(ctx, resource.ID, true)
+	case "nat_gateway":
+		return o.client.EnableNATGateway(ctx, resource.FolderID, resource.ID)
+	case "serverless_container":
+		if opts.ProvisionedInstances <= 0 {
+			return ErrProvisionedInstancesMissing
+		}
+		return o.client.SetContainerProvisionedInstances(ctx, resource.FolderID, resource.ID, int64(opts.ProvisionedInstances))
 	default:
 		return ErrUnsupportedResourceType
 	}
 }
 
-// Stop stops the resource.
-func (o *YCOperator) Stop(ctx context.Context, resource config.Resource) error {
+// Stop stops the resource, or pauses it if opts.Pause is set.
+func (o *YCOperator) Stop(ctx context.Context, resource config.Resource, opts StopOptions) error {
+	if opts.Pause {
+		return o.pause(ctx, resource)
+	}
 	switch resource.Type {
 	case "vm":
-		return o.client.StopInstance(ctx, resource.FolderID, resource.ID)
+		if err := o.client.StopInstance(ctx, resource.FolderID, resource.ID); err != nil {
+			return err
+		}
+		if opts.ReleasePublicIP {
+			return o.client.ReleaseInstancePublicIPs(ctx, resource.FolderID, resource.ID)
+		}
+		return nil
 	case "k8s_cluster":
 		return o.client.StopCluster(ctx, resource.FolderID, resource.ID)
+	case "k8s_node_group":
+		return o.client.StopNodeGroup(ctx, resource.FolderID, resource.ID)
+	case "instance_group":
+		return o.client.StopInstanceGroup(ctx, resource.FolderID, resource.ID)
+	case "mdb_mongodb":
+		return o.client.StopMongoDBCluster(ctx, resource.FolderID, resource.ID)
+	case "mdb_greenplum":
+		return o.client.StopGreenplumCluster(ctx, resource.FolderID, resource.ID)
+	case "alb":
+		return o.client.StopLoadBalancer(ctx, resource.FolderID, resource.ID)
+	case "vpc_address":
+		// The platform deletes an ephemeral address that is not attached to
+		// a resource, so releasing it would make the next start impossible.
+		address, err := o.client.GetAddress(ctx, resource.FolderID, resource.ID)
+		if err != nil {
+			return err
+		}
+		if !address.GetUsed() {
+			return ErrAddressNotAttached
+		}
+		return o.client.SetAddressReserved(ctx, resource.ID, false)
+	case "nat_gateway":
+		return o.client.DisableNATGateway(ctx, resource.FolderID, resource.ID)
+	case "serverless_container":
+		return o.client.SetContainerProvisionedInstances(ctx, resource.FolderID, resource.ID, 0)
+	default:
+		return ErrUnsupportedResourceType
+	}
+}
+
+// pause pauses a resource type that has a paused state.
+func (o *YCOperator) pause(ctx context.Context, resource config.Resource) error {
+	switch resource.Type {
+	case "instance_group":
+		return o.client.PauseInstanceGroup(ctx, resource.FolderID, resource.ID)
+	default:
+		return ErrPauseUnsupported
+	}
+}
+
+// Snapshot creates snapshots of the resource disks.
+func (o *YCOperator) Snapshot(ctx context.Context, resource config.Resource, retention int) error {
+	switch resource.Type {
+	case "vm":
+		return o.client.SnapshotInstanceDisks(ctx, resource.FolderID, resource.ID, retention)
+	default:
+		return ErrUnsupportedResourceType
+	}
+}
+
+// Scale sets the fixed scale size of a node group or instance group.
+func (o *YCOperator) Scale(ctx context.Context, resource config.Resource, targetSize int) error {
+	if targetSize <= 0 {
+		return ErrTargetSizeMissing
+	}
+	switch resource.Type {
+	case "k8s_node_group":
+		return o.client.ScaleNodeGroup(ctx, resource.FolderID, resource.ID, int64(targetSize))
+	case "instance_group":
+		return o.client.ScaleInstanceGroup(ctx, resource.FolderID, resource.ID, int64(targetSize))
 	default:
 		return ErrUnsupportedResourceType
 	}
 }
+
+// restartPollInterval is how often the resource state is checked while a
+// restart waits for the resource to stop.
+const restartPollInterval = 5 * time.Second
+
+// Restart stops the resource, waits until it is stopped and starts it again.
+// Errors are returned as *RestartError carrying the failed phase.
+func (o *YCOperator) Restart(ctx context.Context, resource config.Resource, opts RestartOptions) error {
+	if err := o.Stop(ctx, resource, opts.Stop); err != nil {
+		return &RestartError{Phase: "stop", Err: err}
+	}
+	if err := waitStopped(ctx, NewYCStateChecker(o.client), resource); err != nil {
+		return &RestartError{Phase: "wait_stopped", Err: err}
+	}
+	if err := o.Start(ctx, resource, opts.Start); err != nil {
+		return &RestartError{Phase: "start", Err: err}
+	}
+	return nil
+}
+
+// Resize stops a running VM, updates its platform and computing resources
+// and starts it again. A stopped VM is updated and left stopped. Errors are
+// returned as *ResizeError carrying the failed phase.
+func (o *YCOperator) Resize(ctx context.Context, resource config.Resource, opts ResizeOptions) error {
+	if resource.Type != "vm" {
+		return ErrUnsupportedResourceType
+	}
+	spec := opts.instanceSpec()
+	if spec == (yc.InstanceSpec{}) {
+		return ErrResizeSpecMissing
+	}
+	return o.updateInstance(ctx, resource, spec)
+}
+
+// SetPreemptible stops a running VM, switches its scheduling policy and
+// starts it again. A stopped VM is updated and left stopped. Errors are
+// returned as *ResizeError carrying the failed phase.
+func (o *YCOperator) SetPreemptible(ctx context.Context, resource config.Resource, preemptible bool) error {
+	if resource.Type != "vm" {
+		return ErrUnsupportedResourceType
+	}
+	return o.updateInstance(ctx, resource, yc.InstanceSpec{Preemptible: &preemptible})
+}
+
+// updateInstance applies spec to a VM, stopping a running VM for the update
+// and starting it again afterwards. It is a no-op when the VM already has
+// the spec.
+func (o *YCOperator) updateInstance(ctx context.Context, resource config.Resource, spec yc.InstanceSpec) error {
+	instance, err := o.client.GetInstance(ctx, resource.FolderID, resource.ID)
+	if err != nil {
+		return &ResizeError{Phase: "get", Err: err}
+	}
+	if spec.Matches(instance) {
+		return nil
+	}
+
+	running := instance.GetStatus() == computepb.Instance_RUNNING
+	if running {
+		if err := o.client.StopInstance(ctx, resource.FolderID, resource.ID); err != nil {
+			return &ResizeError{Phase: "stop", Err: err}
+		}
+		if err := waitStopped(ctx, NewYCStateChecker(o.client), resource); err != nil {
+			return &ResizeError{Phase: "wait_stopped", Err: err}
+		}
+	}
+	if err := o.client.ResizeInstance(ctx, resource.FolderID, resource.ID, spec); err != nil {
+		return &ResizeError{Phase: "update", Err: err}
+	}
+	if running {
+		if err := o.client.StartInstance(ctx, resource.FolderID, resource.ID); err != nil {
+			return &ResizeError{Phase: "start", Err: err}
+		}
+	}
+	return nil
+}
+
+// waitStopped polls the resource state until it is stopped or ctx is done.
+func waitStopped(ctx context.Context, checker StateChecker, resource config.Resource) error {
+	ticker := time.NewTicker(restartPollInterval)
+	defer ticker.Stop()
+
+	for {
+		state, isTransitional, err := checker.GetState(ctx, resource)
+		if err != nil {
+			return err
+		}
+		if state == "stopped" && !isTransitional {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
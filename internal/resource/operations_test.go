@@ -0,0 +1,66 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+type sequenceStateChecker struct {
+	states []string
+	calls  int
+}
+
+func (c *sequenceStateChecker) GetState(context.Context, config.Resource) (string, bool, error) {
+	state := c.states[min(c.calls, len(c.states)-1)]
+	c.calls++
+	return state, state != "running" && state != "stopped", nil
+}
+
+func TestWaitStoppedReturnsOnceStopped(t *testing.T) {
+	t.Parallel()
+
+	checker := &sequenceStateChecker{states: []string{"stopped"}}
+	if err := waitStopped(context.Background(), checker, config.Resource{Type: "vm", ID: "vm-1"}); err != nil {
+		t.Fatalf("waitStopped() error = %v", err)
+	}
+	if checker.calls != 1 {
+		t.Fatalf("GetState calls = %d, want 1", checker.calls)
+	}
+}
+
+func TestWaitStoppedHonorsContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	checker := &sequenceStateChecker{states: []string{"stopping"}}
+	if err := waitStopped(ctx, checker, config.Resource{Type: "vm", ID: "vm-1"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("waitStopped() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestRestartErrorUnwraps(t *testing.T) {
+	t.Parallel()
+
+	err := error(&RestartError{Phase: "start", Err: ErrUnsupportedResourceType})
+	if !errors.Is(err, ErrUnsupportedResourceType) {
+		t.Fatalf("errors.Is(%v, ErrUnsupportedResourceType) = false", err)
+	}
+	if got, want := err.Error(), "restart start: unsupported resource type"; got != want {
+		t.Fatalf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestResizeOptionsInstanceSpec(t *testing.T) {
+	t.Parallel()
+
+	opts := ResizeOptionsFromAction(&config.ActionConfig{PlatformID: "standard-v3", Cores: 2, MemoryGB: 4})
+	spec := opts.instanceSpec()
+	if spec.PlatformID != "standard-v3" || spec.Cores != 2 || spec.CoreFraction != 0 || spec.Memory != 4<<30 {
+		t.Fatalf("instanceSpec() = %+v", spec)
+	}
+}
@@ -0,0 +1,116 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+
+	computepb "github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+// Resolver expands name pattern resources into the resources they match.
+type Resolver interface {
+	// ResolveResources returns the resources matching a name pattern resource.
+	ResolveResources(ctx context.Context, resource config.Resource) ([]config.Resource, error)
+}
+
+// ResolveTargets returns targets with every name pattern resource replaced
+// by its matches. Resources matched more than once are returned once.
+// Name patterns are resolved only if checker implements Resolver.
+func ResolveTargets(ctx context.Context, checker StateChecker, targets []config.Resource) ([]config.Resource, error) {
+	if !slices.ContainsFunc(targets, config.Resource.IsPattern) {
+		return targets, nil
+	}
+
+	resolver, ok := checker.(Resolver)
+	if !ok {
+		return nil, ErrPatternNotSupported
+	}
+
+	resolved := make([]config.Resource, 0, len(targets))
+	seen := make(map[string]struct{}, len(targets))
+	add := func(r config.Resource) {
+		key := r.Type + ":" + r.ID
+		if _, exists := seen[key]; exists {
+			return
+		}
+		seen[key] = struct{}{}
+		resolved = append(resolved, r)
+	}
+
+	for _, target := range targets {
+		if !target.IsPattern() {
+			add(target)
+			continue
+		}
+		matches, err := resolver.ResolveResources(ctx, target)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			add(match)
+		}
+	}
+
+	return resolved, nil
+}
+
+// ResolveResources returns the VMs in the folder whose names match the
+// resource name pattern.
+func (c *YCStateChecker) ResolveResources(ctx context.Context, resource config.Resource) ([]config.Resource, error) {
+	if resource.Type != "vm" {
+		return nil, fmt.Errorf("%w: name pattern for %s", ErrUnsupportedResourceType, resource.Type)
+	}
+
+	instances, err := c.client.ListInstances(ctx, resource.FolderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return matchInstances(instances, resource)
+}
+
+// matchInstances returns the instances matching the resource name pattern
+// or ErrTooManyMatches if there are more than the resource allows. Excluded
+// instances do not count towards the limit.
+func matchInstances(instances []*computepb.Instance, resource config.Resource) ([]config.Resource, error) {
+	var matches []config.Resource
+	for _, instance := range instances {
+		ok, err := path.Match(resource.NamePattern, instance.GetName())
+		if err != nil {
+			return nil, fmt.Errorf("name pattern %q: %w", resource.NamePattern, err)
+		}
+		if !ok || excluded(instance, resource) {
+			continue
+		}
+		matches = append(matches, config.Resource{
+			Type:     resource.Type,
+			ID:       instance.GetId(),
+			FolderID: resource.FolderID,
+		})
+	}
+
+	if limit := resource.EffectiveMaxMatches(); len(matches) > limit {
+		return nil, fmt.Errorf("%w: %q matched %d instances, max_matches is %d", ErrTooManyMatches, resource.NamePattern, len(matches), limit)
+	}
+
+	return matches, nil
+}
+
+// excluded reports whether the instance is carved out of the resource name
+// pattern by ID or by any of the exclude labels.
+func excluded(instance *computepb.Instance, resource config.Resource) bool {
+	if slices.Contains(resource.ExcludeIDs, instance.GetId()) {
+		return true
+	}
+	labels := instance.GetLabels()
+	for key, value := range resource.ExcludeLabels {
+		if labelValue, ok := labels[key]; ok && labelValue == value {
+			return true
+		}
+	}
+	return false
+}
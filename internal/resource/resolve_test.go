@@ -0,0 +1,102 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	computepb "github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+func TestMatchInstances(t *testing.T) {
+	t.Parallel()
+
+	instances := []*computepb.Instance{
+		{Id: "a", Name: "dev-api"},
+		{Id: "b", Name: "prod-api"},
+		{Id: "c", Name: "dev-worker"},
+	}
+	pattern := config.Resource{Type: "vm", NamePattern: "dev-*", FolderID: "folder"}
+
+	matches, err := matchInstances(instances, pattern)
+	if err != nil {
+		t.Fatalf("matchInstances() error = %v", err)
+	}
+	if len(matches) != 2 || matches[0].ID != "a" || matches[1].ID != "c" {
+		t.Fatalf("matchInstances() = %v, want instances a and c", matches)
+	}
+	if matches[0].FolderID != "folder" || matches[0].NamePattern != "" {
+		t.Fatalf("matchInstances() resource = %+v, want concrete resource in folder", matches[0])
+	}
+}
+
+func TestMatchInstancesCap(t *testing.T) {
+	t.Parallel()
+
+	instances := []*computepb.Instance{
+		{Id: "a", Name: "dev-api"},
+		{Id: "b", Name: "dev-worker"},
+	}
+	pattern := config.Resource{Type: "vm", NamePattern: "*", MaxMatches: 1}
+
+	if _, err := matchInstances(instances, pattern); !errors.Is(err, ErrTooManyMatches) {
+		t.Fatalf("matchInstances() error = %v, want ErrTooManyMatches", err)
+	}
+}
+
+func TestMatchInstancesExclusions(t *testing.T) {
+	t.Parallel()
+
+	instances := []*computepb.Instance{
+		{Id: "a", Name: "dev-api"},
+		{Id: "b", Name: "dev-bastion", Labels: map[string]string{"role": "bastion"}},
+		{Id: "c", Name: "dev-worker"},
+	}
+	pattern := config.Resource{
+		Type:          "vm",
+		NamePattern:   "dev-*",
+		MaxMatches:    1,
+		ExcludeIDs:    []string{"c"},
+		ExcludeLabels: map[string]string{"role": "bastion"},
+	}
+
+	matches, err := matchInstances(instances, pattern)
+	if err != nil {
+		t.Fatalf("matchInstances() error = %v", err)
+	}
+	if len(matches) != 1 || matches[0].ID != "a" {
+		t.Fatalf("matchInstances() = %v, want only instance a", matches)
+	}
+}
+
+type stubResolver struct {
+	StateChecker
+	matches []config.Resource
+}
+
+func (s stubResolver) ResolveResources(context.Context, config.Resource) ([]config.Resource, error) {
+	return s.matches, nil
+}
+
+func TestResolveTargetsDeduplicates(t *testing.T) {
+	t.Parallel()
+
+	checker := stubResolver{matches: []config.Resource{
+		{Type: "vm", ID: "a"},
+		{Type: "vm", ID: "b"},
+	}}
+	targets := []config.Resource{
+		{Type: "vm", ID: "a"},
+		{Type: "vm", NamePattern: "dev-*"},
+	}
+
+	resolved, err := ResolveTargets(context.Background(), checker, targets)
+	if err != nil {
+		t.Fatalf("ResolveTargets() error = %v", err)
+	}
+	if len(resolved) != 2 || resolved[0].ID != "a" || resolved[1].ID != "b" {
+		t.Fatalf("ResolveTargets() = %v, want a and b", resolved)
+	}
+}
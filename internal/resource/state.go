@@ -3,8 +3,12 @@ package resource
 import (
 	"context"
 
+	albpb "github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
 	computepb "github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
+	instancegrouppb "github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1/instancegroup"
 	k8spb "github.com/yandex-cloud/go-genproto/yandex/cloud/k8s/v1"
+	greenplumpb "github.com/yandex-cloud/go-genproto/yandex/cloud/mdb/greenplum/v1"
+	mongodbpb "github.com/yandex-cloud/go-genproto/yandex/cloud/mdb/mongodb/v1"
 
 	"github.com/sentoz/yc-sheduler/internal/config"
 	"github.com/sentoz/yc-sheduler/internal/yc"
@@ -14,28 +18,65 @@ import (
 type StateChecker interface {
 	// GetState retrieves the current state of the resource.
 	// Returns (state, isTransitional, error).
-	// state: "running", "stopped", or a transitional state name
+	// state: "running", "stopped", "paused" for resource types with a
+	// paused state, or a transitional state name
 	// isTransitional: true if resource is in a transitional state
 	GetState(ctx context.Context, resource config.Resource) (string, bool, error)
 }
 
-// YCStateChecker implements StateChecker using Yandex Cloud client.
+// YCStateChecker implements StateChecker using Yandex Cloud client. It
+// remembers the last successfully read state of every resource.
 type YCStateChecker struct {
-	client *yc.Client
+	client yc.ClientInterface
+	store  *StateStore
 }
 
 // NewYCStateChecker creates a new YCStateChecker.
-func NewYCStateChecker(client *yc.Client) *YCStateChecker {
-	return &YCStateChecker{client: client}
+func NewYCStateChecker(client yc.ClientInterface) *YCStateChecker {
+	return &YCStateChecker{client: client, store: NewStateStore()}
 }
 
 // GetState retrieves the current state of the resource.
 func (c *YCStateChecker) GetState(ctx context.Context, resource config.Resource) (string, bool, error) {
+	state, isTransitional, err := c.getState(ctx, resource)
+	if err == nil && state != "" {
+		c.store.Record(resource, state, isTransitional)
+	}
+	return state, isTransitional, err
+}
+
+// LastKnownState returns the last successfully read state of the resource.
+func (c *YCStateChecker) LastKnownState(resource config.Resource) (KnownState, bool) {
+	return c.store.LastKnownState(resource)
+}
+
+// LastKnownStates returns the last successfully read states of all resources.
+func (c *YCStateChecker) LastKnownStates() []KnownState {
+	return c.store.LastKnownStates()
+}
+
+func (c *YCStateChecker) getState(ctx context.Context, resource config.Resource) (string, bool, error) {
 	switch resource.Type {
 	case "vm":
 		return c.getVMState(ctx, resource)
 	case "k8s_cluster":
 		return c.getClusterState(ctx, resource)
+	case "k8s_node_group":
+		return c.getNodeGroupState(ctx, resource)
+	case "instance_group":
+		return c.getInstanceGroupState(ctx, resource)
+	case "mdb_mongodb":
+		return c.getMongoDBClusterState(ctx, resource)
+	case "mdb_greenplum":
+		return c.getGreenplumClusterState(ctx, resource)
+	case "alb":
+		return c.getLoadBalancerState(ctx, resource)
+	case "vpc_address":
+		return c.getAddressState(ctx, resource)
+	case "nat_gateway":
+		return c.getNATGatewayState(ctx, resource)
+	case "serverless_container":
+		return c.getContainerState(ctx, resource)
 	default:
 		return "", false, nil
 	}
@@ -46,31 +87,182 @@ func (c *YCStateChecker) getVMState(ctx context.Context, resource config.Resourc
 	if err != nil {
 		return "", false, err
 	}
+	state, isTransitional := vmState(instance)
+	return state, isTransitional, nil
+}
+
+func (c *YCStateChecker) getClusterState(ctx context.Context, resource config.Resource) (string, bool, error) {
+	cluster, err := c.client.GetCluster(ctx, resource.FolderID, resource.ID)
+	if err != nil {
+		return "", false, err
+	}
+	state, isTransitional := clusterState(cluster)
+	return state, isTransitional, nil
+}
+
+func (c *YCStateChecker) getNodeGroupState(ctx context.Context, resource config.Resource) (string, bool, error) {
+	nodeGroup, err := c.client.GetNodeGroup(ctx, resource.FolderID, resource.ID)
+	if err != nil {
+		return "", false, err
+	}
+	state, isTransitional := nodeGroupState(nodeGroup)
+	return state, isTransitional, nil
+}
+
+// vmState maps the status of an instance to a resource state.
+func vmState(instance *computepb.Instance) (string, bool) {
 	status := instance.GetStatus()
 	switch status {
 	case computepb.Instance_RUNNING:
-		return "running", false, nil
+		return "running", false
 	case computepb.Instance_STOPPED:
+		return "stopped", false
+	default:
+		// Resource is in transitional state
+		return status.String(), true
+	}
+}
+
+// clusterState maps the status of a Kubernetes cluster to a resource state.
+func clusterState(cluster *k8spb.Cluster) (string, bool) {
+	status := cluster.GetStatus()
+	switch status {
+	case k8spb.Cluster_RUNNING:
+		return "running", false
+	case k8spb.Cluster_STOPPED:
+		return "stopped", false
+	default:
+		// Resource is in transitional state
+		return status.String(), true
+	}
+}
+
+// nodeGroupState maps the status of a node group to a resource state.
+func nodeGroupState(nodeGroup *k8spb.NodeGroup) (string, bool) {
+	status := nodeGroup.GetStatus()
+	switch status {
+	case k8spb.NodeGroup_RUNNING:
+		// A node group scaled to zero by the scheduler stays RUNNING, so the
+		// scale size decides whether it is considered running.
+		if nodeGroup.GetScalePolicy().GetFixedScale() != nil && nodeGroup.GetScalePolicy().GetFixedScale().GetSize() == 0 {
+			return "stopped", false
+		}
+		return "running", false
+	case k8spb.NodeGroup_STOPPED:
+		return "stopped", false
+	default:
+		// Resource is in transitional state
+		return status.String(), true
+	}
+}
+
+func (c *YCStateChecker) getInstanceGroupState(ctx context.Context, resource config.Resource) (string, bool, error) {
+	group, err := c.client.GetInstanceGroup(ctx, resource.FolderID, resource.ID)
+	if err != nil {
+		return "", false, err
+	}
+	status := group.GetStatus()
+	switch status {
+	case instancegrouppb.InstanceGroup_ACTIVE:
+		// A group scaled to zero by the scheduler stays ACTIVE, so the
+		// scale size decides whether it is considered running.
+		if group.GetScalePolicy().GetFixedScale() != nil && group.GetScalePolicy().GetFixedScale().GetSize() == 0 {
+			return "stopped", false, nil
+		}
+		return "running", false, nil
+	case instancegrouppb.InstanceGroup_STOPPED:
 		return "stopped", false, nil
+	case instancegrouppb.InstanceGroup_PAUSED:
+		return "paused", false, nil
 	default:
 		// Resource is in transitional state
 		return status.String(), true, nil
 	}
 }
 
-func (c *YCStateChecker) getClusterState(ctx context.Context, resource config.Resource) (string, bool, error) {
-	cluster, err := c.client.GetCluster(ctx, resource.FolderID, resource.ID)
+func (c *YCStateChecker) getMongoDBClusterState(ctx context.Context, resource config.Resource) (string, bool, error) {
+	cluster, err := c.client.GetMongoDBCluster(ctx, resource.FolderID, resource.ID)
 	if err != nil {
 		return "", false, err
 	}
 	status := cluster.GetStatus()
 	switch status {
-	case k8spb.Cluster_RUNNING:
+	case mongodbpb.Cluster_RUNNING:
 		return "running", false, nil
-	case k8spb.Cluster_STOPPED:
+	case mongodbpb.Cluster_STOPPED:
+		return "stopped", false, nil
+	default:
+		// Resource is in transitional state
+		return status.String(), true, nil
+	}
+}
+
+func (c *YCStateChecker) getGreenplumClusterState(ctx context.Context, resource config.Resource) (string, bool, error) {
+	cluster, err := c.client.GetGreenplumCluster(ctx, resource.FolderID, resource.ID)
+	if err != nil {
+		return "", false, err
+	}
+	status := cluster.GetStatus()
+	switch status {
+	case greenplumpb.Cluster_RUNNING:
+		return "running", false, nil
+	case greenplumpb.Cluster_STOPPED:
+		return "stopped", false, nil
+	default:
+		// Resource is in transitional state
+		return status.String(), true, nil
+	}
+}
+
+func (c *YCStateChecker) getLoadBalancerState(ctx context.Context, resource config.Resource) (string, bool, error) {
+	loadBalancer, err := c.client.GetLoadBalancer(ctx, resource.FolderID, resource.ID)
+	if err != nil {
+		return "", false, err
+	}
+	status := loadBalancer.GetStatus()
+	switch status {
+	case albpb.LoadBalancer_ACTIVE:
+		return "running", false, nil
+	case albpb.LoadBalancer_STOPPED:
 		return "stopped", false, nil
 	default:
 		// Resource is in transitional state
 		return status.String(), true, nil
 	}
 }
+
+func (c *YCStateChecker) getAddressState(ctx context.Context, resource config.Resource) (string, bool, error) {
+	address, err := c.client.GetAddress(ctx, resource.FolderID, resource.ID)
+	if err != nil {
+		return "", false, err
+	}
+	// A reserved (static) address is considered running, an ephemeral one stopped.
+	if address.GetReserved() {
+		return "running", false, nil
+	}
+	return "stopped", false, nil
+}
+
+func (c *YCStateChecker) getNATGatewayState(ctx context.Context, resource config.Resource) (string, bool, error) {
+	routed, err := c.client.IsNATGatewayRouted(ctx, resource.FolderID, resource.ID)
+	if err != nil {
+		return "", false, err
+	}
+	// The gateway is considered running while any route table routes via it.
+	if routed {
+		return "running", false, nil
+	}
+	return "stopped", false, nil
+}
+
+func (c *YCStateChecker) getContainerState(ctx context.Context, resource config.Resource) (string, bool, error) {
+	revision, err := c.client.GetContainerRevision(ctx, resource.FolderID, resource.ID)
+	if err != nil {
+		return "", false, err
+	}
+	// A container without provisioned instances is considered stopped.
+	if revision.GetProvisionPolicy().GetMinInstances() > 0 {
+		return "running", false, nil
+	}
+	return "stopped", false, nil
+}
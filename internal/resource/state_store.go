@@ -0,0 +1,85 @@
+package resource
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+// KnownState is the last successfully observed state of a resource.
+type KnownState struct {
+	ObservedAt     time.Time `json:"observed_at"`
+	ResourceType   string    `json:"resource_type"`
+	ResourceID     string    `json:"resource_id"`
+	FolderID       string    `json:"folder_id,omitempty"`
+	State          string    `json:"state"`
+	IsTransitional bool      `json:"is_transitional,omitempty"`
+}
+
+// KnownStateReader is implemented by state checkers that remember the last
+// successfully observed state of resources. It is a fallback signal when a
+// live state read fails.
+type KnownStateReader interface {
+	// LastKnownState returns the last observed state of the resource.
+	LastKnownState(resource config.Resource) (KnownState, bool)
+}
+
+// StateStore keeps the last successfully observed state per resource.
+type StateStore struct {
+	now    func() time.Time
+	states map[string]KnownState
+	mu     sync.RWMutex
+}
+
+// NewStateStore creates an empty StateStore.
+func NewStateStore() *StateStore {
+	return &StateStore{
+		now:    time.Now,
+		states: make(map[string]KnownState),
+	}
+}
+
+// Record stores the observed state of the resource with the current time.
+func (s *StateStore) Record(resource config.Resource, state string, isTransitional bool) {
+	known := KnownState{
+		ObservedAt:     s.now(),
+		ResourceType:   resource.Type,
+		ResourceID:     resource.ID,
+		FolderID:       resource.FolderID,
+		State:          state,
+		IsTransitional: isTransitional,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[stateKey(resource)] = known
+}
+
+// LastKnownState returns the last observed state of the resource.
+func (s *StateStore) LastKnownState(resource config.Resource) (KnownState, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	known, ok := s.states[stateKey(resource)]
+	return known, ok
+}
+
+// LastKnownStates returns the last observed states of all resources sorted
+// by resource type and ID.
+func (s *StateStore) LastKnownStates() []KnownState {
+	s.mu.RLock()
+	states := slices.Collect(maps.Values(s.states))
+	s.mu.RUnlock()
+
+	slices.SortFunc(states, func(a, b KnownState) int {
+		return cmp.Or(cmp.Compare(a.ResourceType, b.ResourceType), cmp.Compare(a.ResourceID, b.ResourceID))
+	})
+	return states
+}
+
+func stateKey(resource config.Resource) string {
+	return resource.Type + ":" + resource.ID
+}
@@ -0,0 +1,37 @@
+package resource
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+func TestStateStoreKeepsLastObservedState(t *testing.T) {
+	t.Parallel()
+
+	store := NewStateStore()
+	current := time.Date(2026, time.May, 4, 9, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return current }
+
+	vm := config.Resource{Type: "vm", ID: "fhm123", FolderID: "b1g123"}
+	cluster := config.Resource{Type: "k8s_cluster", ID: "cat123", FolderID: "b1g123"}
+	if _, ok := store.LastKnownState(vm); ok {
+		t.Fatal("LastKnownState() found state before any read")
+	}
+
+	store.Record(vm, "running", false)
+	store.Record(cluster, "STOPPING", true)
+	current = current.Add(time.Hour)
+	store.Record(vm, "stopped", false)
+
+	known, ok := store.LastKnownState(vm)
+	if !ok || known.State != "stopped" || !known.ObservedAt.Equal(current) {
+		t.Fatalf("LastKnownState() = %+v, %v; want stopped observed at %v", known, ok, current)
+	}
+
+	states := store.LastKnownStates()
+	if len(states) != 2 || states[0].ResourceType != "k8s_cluster" || !states[0].IsTransitional {
+		t.Fatalf("LastKnownStates() = %+v, want cluster first", states)
+	}
+}
@@ -0,0 +1,93 @@
+package resource
+
+import (
+	"context"
+	"testing"
+
+	albpb "github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
+	greenplumpb "github.com/yandex-cloud/go-genproto/yandex/cloud/mdb/greenplum/v1"
+	mongodbpb "github.com/yandex-cloud/go-genproto/yandex/cloud/mdb/mongodb/v1"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+func TestMongoDBClusterState(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		status           mongodbpb.Cluster_Status
+		wantState        string
+		wantTransitional bool
+	}{
+		{status: mongodbpb.Cluster_RUNNING, wantState: "running"},
+		{status: mongodbpb.Cluster_STOPPED, wantState: "stopped"},
+		{status: mongodbpb.Cluster_STARTING, wantState: "STARTING", wantTransitional: true},
+		{status: mongodbpb.Cluster_STOPPING, wantState: "STOPPING", wantTransitional: true},
+		{status: mongodbpb.Cluster_UPDATING, wantState: "UPDATING", wantTransitional: true},
+		{status: mongodbpb.Cluster_ERROR, wantState: "ERROR", wantTransitional: true},
+	}
+
+	for _, tt := range tests {
+		client := &fakeClient{mongodb: map[string]mongodbpb.Cluster_Status{"c9q1": tt.status}}
+		cluster := config.Resource{Type: "mdb_mongodb", ID: "c9q1", FolderID: "b1g1"}
+
+		state, transitional, err := NewYCStateChecker(client).GetState(context.Background(), cluster)
+		if err != nil || state != tt.wantState || transitional != tt.wantTransitional {
+			t.Errorf("GetState() for %v = %q, %v, %v; want %q, %v", tt.status, state, transitional, err, tt.wantState, tt.wantTransitional)
+		}
+	}
+}
+
+func TestGreenplumClusterState(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		status           greenplumpb.Cluster_Status
+		wantState        string
+		wantTransitional bool
+	}{
+		{status: greenplumpb.Cluster_RUNNING, wantState: "running"},
+		{status: greenplumpb.Cluster_STOPPED, wantState: "stopped"},
+		{status: greenplumpb.Cluster_STARTING, wantState: "STARTING", wantTransitional: true},
+		{status: greenplumpb.Cluster_STOPPING, wantState: "STOPPING", wantTransitional: true},
+		{status: greenplumpb.Cluster_UPDATING, wantState: "UPDATING", wantTransitional: true},
+		{status: greenplumpb.Cluster_ERROR, wantState: "ERROR", wantTransitional: true},
+	}
+
+	for _, tt := range tests {
+		client := &fakeClient{greenplum: map[string]greenplumpb.Cluster_Status{"c9q2": tt.status}}
+		cluster := config.Resource{Type: "mdb_greenplum", ID: "c9q2", FolderID: "b1g1"}
+
+		state, transitional, err := NewYCStateChecker(client).GetState(context.Background(), cluster)
+		if err != nil || state != tt.wantState || transitional != tt.wantTransitional {
+			t.Errorf("GetState() for %v = %q, %v, %v; want %q, %v", tt.status, state, transitional, err, tt.wantState, tt.wantTransitional)
+		}
+	}
+}
+
+func TestLoadBalancerState(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		status           albpb.LoadBalancer_Status
+		wantState        string
+		wantTransitional bool
+	}{
+		{status: albpb.LoadBalancer_ACTIVE, wantState: "running"},
+		{status: albpb.LoadBalancer_STOPPED, wantState: "stopped"},
+		{status: albpb.LoadBalancer_STARTING, wantState: "STARTING", wantTransitional: true},
+		{status: albpb.LoadBalancer_STOPPING, wantState: "STOPPING", wantTransitional: true},
+		{status: albpb.LoadBalancer_CREATING, wantState: "CREATING", wantTransitional: true},
+		{status: albpb.LoadBalancer_DELETING, wantState: "DELETING", wantTransitional: true},
+	}
+
+	for _, tt := range tests {
+		client := &fakeClient{loadBalancers: map[string]albpb.LoadBalancer_Status{"ds71": tt.status}}
+		alb := config.Resource{Type: "alb", ID: "ds71", FolderID: "b1g1"}
+
+		state, transitional, err := NewYCStateChecker(client).GetState(context.Background(), alb)
+		if err != nil || state != tt.wantState || transitional != tt.wantTransitional {
+			t.Errorf("GetState() for %v = %q, %v, %v; want %q, %v", tt.status, state, transitional, err, tt.wantState, tt.wantTransitional)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package resource
+
+import (
+	"context"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+// StartTimeReader provides the time cloud resources were last started.
+type StartTimeReader interface {
+	// GetStartTime returns when the resource was last started, or the zero
+	// time for resource types whose start time is not read.
+	GetStartTime(ctx context.Context, resource config.Resource) (time.Time, error)
+}
+
+// GetStartTime returns when the resource was last started. Only vm
+// resources are supported.
+func (c *YCStateChecker) GetStartTime(ctx context.Context, resource config.Resource) (time.Time, error) {
+	if resource.Type != "vm" {
+		return time.Time{}, nil
+	}
+	return c.client.InstanceStartTime(ctx, resource.FolderID, resource.ID)
+}
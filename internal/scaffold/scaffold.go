@@ -0,0 +1,86 @@
+// Package scaffold writes a starter configuration with example schedule
+// manifests rendered from the templates embedded in static.
+package scaffold
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/sentoz/yc-sheduler/static"
+)
+
+const (
+	// templatesRoot is the directory of the init templates in static.
+	templatesRoot = "templates/init"
+
+	// templateExt is stripped from template names to get file names.
+	templateExt = ".tmpl"
+)
+
+// ErrExists is returned when a scaffolded file already exists and overwriting
+// is not allowed.
+var ErrExists = errors.New("file already exists")
+
+// Data holds the values substituted into the templates.
+type Data struct {
+	// Timezone is the timezone of schedule times, e.g. "Europe/Moscow".
+	Timezone string
+	// FolderID is the folder of the example resources.
+	FolderID string
+}
+
+// Write renders the templates into dir, creating it and the schedules
+// directory if needed, and returns the paths of written files. Existing files
+// are overwritten only with force; otherwise nothing is written.
+func Write(dir string, data Data, force bool) ([]string, error) {
+	rendered := make(map[string][]byte)
+	err := fs.WalkDir(static.InitTemplates, templatesRoot, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		raw, err := fs.ReadFile(static.InitTemplates, name)
+		if err != nil {
+			return err
+		}
+		tmpl, err := template.New(path.Base(name)).Option("missingkey=error").Parse(string(raw))
+		if err != nil {
+			return fmt.Errorf("parse template %s: %w", name, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return fmt.Errorf("render template %s: %w", name, err)
+		}
+		rel := strings.TrimSuffix(strings.TrimPrefix(name, templatesRoot+"/"), templateExt)
+		rendered[filepath.Join(dir, filepath.FromSlash(rel))] = buf.Bytes()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scaffold: %w", err)
+	}
+
+	files := slices.Sorted(maps.Keys(rendered))
+	for _, file := range files {
+		if _, err := os.Stat(file); err == nil && !force {
+			return nil, fmt.Errorf("scaffold: %w: %s", ErrExists, file)
+		}
+	}
+
+	for _, file := range files {
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return nil, fmt.Errorf("scaffold: create directory: %w", err)
+		}
+		if err := os.WriteFile(file, rendered[file], 0o644); err != nil {
+			return nil, fmt.Errorf("scaffold: write %s: %w", file, err)
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,39 @@
+package scaffold
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	files, err := Write(dir, Data{Timezone: "Europe/Moscow", FolderID: "b1g1234567890abcdef"}, false)
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if len(files) != 4 {
+		t.Fatalf("Write() files = %v, want config.yaml and 3 schedules", files)
+	}
+
+	// The scaffolded configuration loads as is.
+	cfg, err := config.Load(context.Background(), filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		t.Fatalf("config.Load() error = %v", err)
+	}
+	if cfg.Timezone.String() != "Europe/Moscow" || len(cfg.Schedules) != 3 {
+		t.Fatalf("config = timezone %q, %d schedules; want Europe/Moscow, 3", cfg.Timezone, len(cfg.Schedules))
+	}
+
+	if _, err := Write(dir, Data{Timezone: "UTC", FolderID: "b1g1234567890abcdef"}, false); !errors.Is(err, ErrExists) {
+		t.Fatalf("Write() over existing files error = %v, want %v", err, ErrExists)
+	}
+	if _, err := Write(dir, Data{Timezone: "UTC", FolderID: "b1g1234567890abcdef"}, true); err != nil {
+		t.Fatalf("Write() with force error = %v", err)
+	}
+}
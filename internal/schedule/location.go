@@ -0,0 +1,41 @@
+package schedule
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+// locations caches loaded schedule timezones by name.
+var locations sync.Map
+
+// Location returns the location the times of sch are taken in: its own
+// timezone when set, fallback otherwise.
+func Location(sch config.Schedule, fallback *time.Location) *time.Location {
+	name := sch.Timezone.String()
+	if name == "" {
+		return fallback
+	}
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location)
+	}
+	// Timezones are validated when schedules are loaded.
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return fallback
+	}
+	locations.Store(name, loc)
+	return loc
+}
+
+// Crontab returns the crontab of a cron schedule action prefixed with the
+// timezone of the schedule, unless the crontab sets its own.
+func Crontab(sch config.Schedule, action *config.ActionConfig) string {
+	crontab := action.Crontab.String()
+	if sch.Timezone == "" || crontab == "" || strings.HasPrefix(crontab, "TZ=") || strings.HasPrefix(crontab, "CRON_TZ=") {
+		return crontab
+	}
+	return "CRON_TZ=" + sch.Timezone.String() + " " + crontab
+}
@@ -2,6 +2,7 @@ package schedule
 
 import (
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/go-co-op/gocron/v2"
@@ -208,6 +209,306 @@ func GetLastCronTime(crontab string, now time.Time) (time.Time, error) {
 	return time.Time{}, fmt.Errorf("failed to find last cron execution time")
 }
 
+// LastActionTime calculates the last execution time of a schedule action
+// before now, with times taken in the timezone of the schedule or location.
+func LastActionTime(sch config.Schedule, action *config.ActionConfig, now time.Time, location *time.Location) (time.Time, error) {
+	if sch.Timezone != "" {
+		location = Location(sch, location)
+		now = now.In(location)
+	}
+	switch sch.Type {
+	case "daily", "random_window":
+		if action.Time == "" {
+			return time.Time{}, fmt.Errorf("%s schedule missing time", sch.Type)
+		}
+		return GetLastDailyTime(action.Time, now, location)
+	case "weekly":
+		if action.Time == "" {
+			return time.Time{}, fmt.Errorf("weekly schedule missing time")
+		}
+		var last time.Time
+		for _, day := range action.WeekDays() {
+			if day < 0 || day > 6 {
+				return time.Time{}, fmt.Errorf("weekly schedule invalid day: %d", day)
+			}
+			t, err := GetLastWeeklyTime(action.Time, day, now, location)
+			if err != nil {
+				return time.Time{}, err
+			}
+			if t.After(last) {
+				last = t
+			}
+		}
+		return last, nil
+	case "monthly":
+		if action.Time == "" {
+			return time.Time{}, fmt.Errorf("monthly schedule missing time")
+		}
+		if action.Day < 1 || action.Day > 31 {
+			return time.Time{}, fmt.Errorf("monthly schedule invalid day: %d", action.Day)
+		}
+		return GetLastMonthlyTime(action.Time, action.Day, now, location)
+	case "one-time":
+		at, err := action.At.Time()
+		if err != nil {
+			return time.Time{}, fmt.Errorf("one-time schedule invalid at %q: %w", action.At, err)
+		}
+		if !at.Before(now) {
+			return time.Time{}, fmt.Errorf("one-time schedule has not run yet")
+		}
+		return at, nil
+	case "duration":
+		offsets, err := IntervalOffsets(action)
+		if err != nil {
+			return time.Time{}, err
+		}
+		local := now.In(location)
+		for days := 0; days >= -1; days-- {
+			day := local.AddDate(0, 0, days)
+			for i := len(offsets) - 1; i >= 0; i-- {
+				if at := AtOffset(day, offsets[i], location); at.Before(now) {
+					return at, nil
+				}
+			}
+		}
+		return time.Time{}, fmt.Errorf("no duration execution found before now")
+	case "cron":
+		if action.Crontab.String() == "" {
+			return time.Time{}, fmt.Errorf("cron schedule missing crontab")
+		}
+		return GetLastCronTime(Crontab(sch, action), now)
+	default:
+		return time.Time{}, fmt.Errorf("unknown schedule type: %s", sch.Type)
+	}
+}
+
+// NextActionTime calculates the next execution time of a schedule action
+// after now, with times taken in the timezone of the schedule or location.
+func NextActionTime(sch config.Schedule, action *config.ActionConfig, now time.Time, location *time.Location) (time.Time, error) {
+	location = Location(sch, location)
+	switch sch.Type {
+	case "daily", "weekly", "monthly", "random_window":
+		if action.Time == "" {
+			return time.Time{}, fmt.Errorf("%s schedule missing time", sch.Type)
+		}
+		hour, minute, second, err := parseTimeString(action.Time)
+		if err != nil {
+			return time.Time{}, err
+		}
+		local := now.In(location)
+		switch sch.Type {
+		case "daily", "random_window":
+			for days := 0; days <= 1; days++ {
+				next := time.Date(local.Year(), local.Month(), local.Day()+days, hour, minute, second, 0, location)
+				if next.After(now) {
+					return next, nil
+				}
+			}
+		case "weekly":
+			var earliest time.Time
+			for _, day := range action.WeekDays() {
+				if day < 0 || day > 6 {
+					return time.Time{}, fmt.Errorf("weekly schedule invalid day: %d", day)
+				}
+				daysAhead := (day - int(local.Weekday()) + 7) % 7
+				for _, days := range []int{daysAhead, daysAhead + 7} {
+					next := time.Date(local.Year(), local.Month(), local.Day()+days, hour, minute, second, 0, location)
+					if next.After(now) {
+						if earliest.IsZero() || next.Before(earliest) {
+							earliest = next
+						}
+						break
+					}
+				}
+			}
+			if !earliest.IsZero() {
+				return earliest, nil
+			}
+		case "monthly":
+			if action.Day < 1 || action.Day > 31 {
+				return time.Time{}, fmt.Errorf("monthly schedule invalid day: %d", action.Day)
+			}
+			// Months without the day are skipped.
+			for months := 0; months <= 12; months++ {
+				next := time.Date(local.Year(), local.Month()+time.Month(months), action.Day, hour, minute, second, 0, location)
+				if next.Day() == action.Day && next.After(now) {
+					return next, nil
+				}
+			}
+		}
+		return time.Time{}, fmt.Errorf("no %s execution found after now", sch.Type)
+	case "one-time":
+		at, err := action.At.Time()
+		if err != nil {
+			return time.Time{}, fmt.Errorf("one-time schedule invalid at %q: %w", action.At, err)
+		}
+		if !at.After(now) {
+			return time.Time{}, fmt.Errorf("no one-time execution found after now")
+		}
+		return at, nil
+	case "duration":
+		offsets, err := IntervalOffsets(action)
+		if err != nil {
+			return time.Time{}, err
+		}
+		local := now.In(location)
+		for days := 0; days <= 1; days++ {
+			day := local.AddDate(0, 0, days)
+			for _, offset := range offsets {
+				if at := AtOffset(day, offset, location); at.After(now) {
+					return at, nil
+				}
+			}
+		}
+		return time.Time{}, fmt.Errorf("no duration execution found after now")
+	case "cron":
+		if action.Crontab.String() == "" {
+			return time.Time{}, fmt.Errorf("cron schedule missing crontab")
+		}
+		parser := cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+		cronSchedule, err := parser.Parse(Crontab(sch, action))
+		if err != nil {
+			cronSchedule, err = cron.ParseStandard(Crontab(sch, action))
+			if err != nil {
+				return time.Time{}, fmt.Errorf("invalid cron expression: %w", err)
+			}
+		}
+		next := cronSchedule.Next(now)
+		if next.IsZero() {
+			return time.Time{}, fmt.Errorf("no cron execution found after now")
+		}
+		return next, nil
+	default:
+		return time.Time{}, fmt.Errorf("unknown schedule type: %s", sch.Type)
+	}
+}
+
+// InBusinessHours reports whether t is within the business hours window,
+// with days and times of day taken in the location of t. A window with End
+// before Start spans midnight and belongs to the day it starts on.
+func InBusinessHours(hours config.BusinessHours, t time.Time) (bool, error) {
+	start, err := ParseTimeOfDay(hours.Start.String())
+	if err != nil {
+		return false, err
+	}
+	end, err := ParseTimeOfDay(hours.End.String())
+	if err != nil {
+		return false, err
+	}
+
+	offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	onDay := func(day time.Time) bool {
+		return slices.Contains(hours.Days, int(day.Weekday()))
+	}
+	if start < end {
+		return offset >= start && offset < end && onDay(t), nil
+	}
+	if offset >= start {
+		return onDay(t), nil
+	}
+	return offset < end && onDay(t.AddDate(0, 0, -1)), nil
+}
+
+// MinInterval is the shortest interval between runs of duration schedules.
+const MinInterval = time.Minute
+
+// IntervalOffsets returns the offsets from midnight of the daily runs of a
+// duration schedule action: from the action time, midnight by default,
+// every interval until the end of the day.
+func IntervalOffsets(action *config.ActionConfig) ([]time.Duration, error) {
+	every := action.Every.Duration
+	if every < MinInterval {
+		return nil, fmt.Errorf("duration schedule interval %s is shorter than %s", every, MinInterval)
+	}
+	var start time.Duration
+	if action.Time != "" {
+		var err error
+		if start, err = ParseTimeOfDay(action.Time); err != nil {
+			return nil, err
+		}
+	}
+
+	var offsets []time.Duration
+	for offset := start; offset < 24*time.Hour; offset += every {
+		offsets = append(offsets, offset)
+	}
+	return offsets, nil
+}
+
+// RandomWindow returns the length of the daily window of a random_window
+// schedule action, from its time to its window end.
+func RandomWindow(action *config.ActionConfig) (time.Duration, error) {
+	if action.Time == "" || action.WindowEnd == "" {
+		return 0, fmt.Errorf("random_window schedule missing time or window_end")
+	}
+	start, err := ParseTimeOfDay(action.Time)
+	if err != nil {
+		return 0, err
+	}
+	end, err := ParseTimeOfDay(action.WindowEnd)
+	if err != nil {
+		return 0, err
+	}
+	if end == start {
+		return 0, fmt.Errorf("random_window schedule window is empty")
+	}
+	if end < start {
+		end += 24 * time.Hour
+	}
+	return end - start, nil
+}
+
+// DeferralWindow returns how long after its scheduled time a run of the
+// action may happen: the schedule jitter plus the window of random_window
+// schedules.
+func DeferralWindow(sch config.Schedule, action *config.ActionConfig) time.Duration {
+	window := sch.Jitter.Duration
+	if sch.Type == "random_window" && action != nil {
+		// The window is validated when the schedule is registered.
+		random, _ := RandomWindow(action)
+		window += random
+	}
+	return window
+}
+
+// Missed reports whether a run of the action starting at now is too late
+// for the run due last at or before now, because its deferral window and
+// run_within have passed, and returns the time the run was due. It only
+// decides about a run starting now; whether a past run actually was missed
+// is known to the scheduler alone. Actions without run_within are never
+// missed.
+func Missed(sch config.Schedule, action *config.ActionConfig, now time.Time, location *time.Location) (time.Time, bool) {
+	if action == nil || action.RunWithin.Duration <= 0 {
+		return time.Time{}, false
+	}
+	// Last runs are taken strictly before the time given, and schedule times
+	// have a resolution of a second, so a run due exactly now is found.
+	due, err := LastActionTime(sch, action, now.Add(time.Second), location)
+	if err != nil || due.IsZero() {
+		return time.Time{}, false
+	}
+	return due, now.Sub(due) > DeferralWindow(sch, action)+action.RunWithin.Duration
+}
+
+// AtOffset returns the wall clock time offset from midnight of the day of t
+// in location.
+func AtOffset(t time.Time, offset time.Duration, location *time.Location) time.Time {
+	hour := int(offset / time.Hour)
+	minute := int(offset % time.Hour / time.Minute)
+	second := int(offset % time.Minute / time.Second)
+	return time.Date(t.Year(), t.Month(), t.Day(), hour, minute, second, 0, location)
+}
+
+// ParseTimeOfDay parses a time string (HH:MM or HH:MM:SS) into the offset
+// from midnight.
+func ParseTimeOfDay(timeStr string) (time.Duration, error) {
+	hour, minute, second, err := parseTimeString(timeStr)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute + time.Duration(second)*time.Second, nil
+}
+
 // parseTimeString parses a time string (HH:MM or HH:MM:SS) and returns hour, minute, second.
 func parseTimeString(timeStr string) (hour, minute, second int, err error) {
 	parts := [3]int{}
@@ -0,0 +1,37 @@
+package scheduler
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+// ScheduleForAction returns the schedule narrowed the way the job of action
+// runs it, with the bare action name. action names an enabled action of the
+// schedule like its job does: "stop", or "scale:1" for one of several scale
+// or preemptible entries.
+func ScheduleForAction(sch config.Schedule, action string) (config.Schedule, string, error) {
+	jobs := expectedJobs(sch)
+	if _, ok := jobs[sch.Name+":"+action]; !ok {
+		enabled := make([]string, 0, len(jobs))
+		for _, name := range slices.Sorted(maps.Keys(jobs)) {
+			enabled = append(enabled, strings.TrimPrefix(name, sch.Name+":"))
+		}
+		return sch, "", fmt.Errorf("schedule %q has no enabled action %q (enabled: %s)", sch.Name, action, strings.Join(enabled, ", "))
+	}
+
+	name, entry, ok := strings.Cut(action, ":")
+	if !ok {
+		return sch, name, nil
+	}
+	// Entry indexes of job names are valid by construction.
+	i, _ := strconv.Atoi(entry)
+	if name == "preemptible" {
+		return sch.ForPreemptibleEntry(i), name, nil
+	}
+	return sch.ForScaleEntry(i), name, nil
+}
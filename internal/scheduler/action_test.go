@@ -0,0 +1,37 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+func TestScheduleForAction(t *testing.T) {
+	t.Parallel()
+
+	sch := makeSchedule("pool", "daily", true, true)
+	sch.Actions.Scale = []config.ActionConfig{
+		{Enabled: true, Time: "08:00", TargetSize: 5},
+		{Enabled: true, Time: "20:00", TargetSize: 1},
+	}
+
+	narrowed, action, err := ScheduleForAction(sch, "scale:1")
+	if err != nil {
+		t.Fatalf("ScheduleForAction(scale:1) error = %v", err)
+	}
+	if action != "scale" || len(narrowed.Actions.Scale) != 1 || narrowed.Actions.Scale[0].TargetSize != 1 {
+		t.Fatalf("ScheduleForAction(scale:1) = %+v, %q; want the second scale entry", narrowed.Actions.Scale, action)
+	}
+
+	if _, action, err := ScheduleForAction(sch, "stop"); err != nil || action != "stop" {
+		t.Fatalf("ScheduleForAction(stop) = %q, %v; want stop", action, err)
+	}
+
+	for _, action := range []string{"scale", "snapshot", "scale:2"} {
+		_, _, err := ScheduleForAction(sch, action)
+		if err == nil || !strings.Contains(err.Error(), "enabled: scale:0, scale:1, start, stop") {
+			t.Errorf("ScheduleForAction(%s) error = %v, want the enabled actions listed", action, err)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package scheduler
+
+import (
+	"github.com/rs/zerolog/log"
+
+	"github.com/sentoz/yc-sheduler/internal/blackout"
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/metrics"
+)
+
+// SetBlackouts sets the blackout windows consulted before each scheduled run.
+// Runs during an active window are skipped.
+func (s *Scheduler) SetBlackouts(blackouts *blackout.Calendar) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.blackouts = blackouts
+}
+
+// outsideBlackouts wraps a job function so it is skipped while a blackout
+// window is active.
+func (s *Scheduler) outsideBlackouts(sch config.Schedule, action string, m *metrics.Metrics, fn func()) func() {
+	return func() {
+		s.mu.Lock()
+		blackouts := s.blackouts
+		s.mu.Unlock()
+
+		w, active := blackouts.Active(s.clock.Now())
+		if !active {
+			fn()
+			return
+		}
+
+		log.Info().
+			Str("schedule", sch.Name).
+			Str("action", action).
+			Str("window", w.Name).
+			Str("reason", w.Reason).
+			Msg("Blackout window is active, skipping run")
+		// Dependents are skipped as well, as for paused schedules.
+		s.deps.record(sch.Name, action, false)
+		if m != nil {
+			for _, target := range sch.Targets() {
+				m.IncOperation(target.Type, action, "skipped")
+				m.IncSchedulerSkip(target.Type, action, "blackout")
+			}
+		}
+	}
+}
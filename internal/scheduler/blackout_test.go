@@ -0,0 +1,35 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/blackout"
+)
+
+func TestOutsideBlackouts_SkipsRunsDuringWindow(t *testing.T) {
+	t.Parallel()
+
+	s, err := New("", 1)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	now := time.Now()
+	sch := makeSchedule("vm", "daily", true, false)
+
+	ran := false
+	s.SetBlackouts(blackout.New([]blackout.Window{{Name: "release", From: now.Add(-time.Hour), Until: now.Add(time.Hour)}}, nil))
+	s.outsideBlackouts(sch, "start", nil, func() { ran = true })()
+	if ran {
+		t.Fatal("run was not skipped during the blackout window")
+	}
+	if result, recorded := s.deps.results["vm:start"]; !recorded || result.ok {
+		t.Fatalf("recorded result = %+v, %v; want a failed run for dependents", result, recorded)
+	}
+
+	s.SetBlackouts(blackout.New([]blackout.Window{{Name: "release", Until: now.Add(-time.Hour)}}, nil))
+	s.outsideBlackouts(sch, "start", nil, func() { ran = true })()
+	if !ran {
+		t.Fatal("run was skipped after the blackout window ended")
+	}
+}
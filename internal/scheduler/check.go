@@ -0,0 +1,65 @@
+package scheduler
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/schedule"
+)
+
+// ScheduleError is an error of a schedule found by CheckSchedules. Schedule
+// is empty for errors of the dependencies between schedules.
+type ScheduleError struct {
+	Schedule string
+	Err      error
+}
+
+// Error implements error.
+func (e ScheduleError) Error() string {
+	if e.Schedule == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("schedule %q: %v", e.Schedule, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e ScheduleError) Unwrap() error {
+	return e.Err
+}
+
+// CheckSchedules verifies schedules without registering them: dependencies
+// must be valid and every enabled action must convert into a job with a next
+// run after now in timezone. Unlike RegisterSchedules, which stops at the
+// first invalid schedule, it reports the errors of every job.
+func CheckSchedules(schedules []config.Schedule, timezone string, now time.Time) ([]ScheduleError, error) {
+	location, err := loadLocation(timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []ScheduleError
+	if err := validateDependencies(schedules); err != nil {
+		errs = append(errs, ScheduleError{Err: err})
+	}
+	for _, sch := range schedules {
+		if seasonEnded(sch, now) {
+			continue
+		}
+		active := withoutExpiredOneTimeActions(sch, now, false)
+		jobs := expectedJobs(active)
+		for _, name := range slices.Sorted(maps.Keys(jobs)) {
+			action := jobs[name]
+			if _, err := ScheduleToJobDefinition(active, action); err != nil {
+				errs = append(errs, ScheduleError{Schedule: sch.Name, Err: fmt.Errorf("job %q: %w", name, err)})
+				continue
+			}
+			if _, err := schedule.NextActionTime(active, action, now, location); err != nil {
+				errs = append(errs, ScheduleError{Schedule: sch.Name, Err: fmt.Errorf("job %q: next run: %w", name, err)})
+			}
+		}
+	}
+	return errs, nil
+}
@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+func TestCheckSchedulesReportsEveryInvalidJob(t *testing.T) {
+	t.Parallel()
+
+	badTime := makeSchedule("bad-time", "daily", true, true)
+	badTime.Actions.Stop.Time = "25:00"
+	badCron := makeSchedule("bad-cron", "cron", true, false)
+	badCron.Actions.Start = &config.ActionConfig{Enabled: true, Crontab: "0 99 * * *"}
+	orphan := makeSchedule("orphan", "daily", true, false)
+	orphan.DependsOn = []string{"missing"}
+	schedules := []config.Schedule{makeSchedule("ok", "daily", true, true), badTime, badCron, orphan}
+
+	errs, err := CheckSchedules(schedules, "Europe/Moscow", time.Now())
+	if err != nil {
+		t.Fatalf("CheckSchedules() error = %v", err)
+	}
+
+	got := make(map[string]int)
+	for _, e := range errs {
+		got[e.Schedule]++
+	}
+	want := map[string]int{"": 1, "bad-time": 1, "bad-cron": 1}
+	if len(got) != len(want) {
+		t.Fatalf("CheckSchedules() = %v, want errors of %v", errs, want)
+	}
+	for name, n := range want {
+		if got[name] != n {
+			t.Fatalf("CheckSchedules() = %v, want %d errors of %q", errs, n, name)
+		}
+	}
+	if !errors.Is(errs[0].Err, ErrUnknownDependency) {
+		t.Fatalf("CheckSchedules()[0] = %v, want ErrUnknownDependency", errs[0])
+	}
+}
+
+func TestCheckSchedulesInvalidTimezone(t *testing.T) {
+	t.Parallel()
+
+	if _, err := CheckSchedules(nil, "Mars/Olympus", time.Now()); err == nil {
+		t.Fatal("CheckSchedules() error = nil, want invalid timezone error")
+	}
+}
@@ -0,0 +1,185 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/notify"
+	"github.com/sentoz/yc-sheduler/internal/schedule"
+)
+
+// Divergence kinds.
+const (
+	// DivergenceMissing marks a job of a loaded schedule that is not registered.
+	DivergenceMissing = "missing"
+	// DivergenceUnexpected marks a registered job no loaded schedule expects.
+	DivergenceUnexpected = "unexpected"
+	// DivergenceNextRun marks a job whose next run differs from the schedule.
+	DivergenceNextRun = "next_run"
+)
+
+// Divergence is a difference between the loaded schedules and the jobs
+// registered in gocron.
+type Divergence struct {
+	Expected time.Time `json:"expected,omitzero"`
+	Actual   time.Time `json:"actual,omitzero"`
+	Job      string    `json:"job"`
+	Kind     string    `json:"kind"`
+}
+
+// expectedJobs returns the regular jobs registerScheduleUnlocked adds for
+// the schedule, keyed by job name.
+func expectedJobs(sch config.Schedule) map[string]*config.ActionConfig {
+	jobs := make(map[string]*config.ActionConfig)
+	for action, cfg := range map[string]*config.ActionConfig{
+		"start":    sch.Actions.Start,
+		"stop":     sch.Actions.Stop,
+		"snapshot": sch.Actions.Snapshot,
+		"restart":  sch.Actions.Restart,
+		"resize":   sch.Actions.Resize,
+	} {
+		if cfg != nil && cfg.Enabled {
+			jobs[sch.Name+":"+action] = cfg
+		}
+	}
+	for action, entries := range map[string][]config.ActionConfig{
+		"scale":       sch.Actions.Scale,
+		"preemptible": sch.Actions.Preemptible,
+	} {
+		for i := range entries {
+			if !entries[i].Enabled {
+				continue
+			}
+			name := sch.Name + ":" + action
+			if len(entries) > 1 {
+				name += ":" + strconv.Itoa(i)
+			}
+			jobs[name] = &entries[i]
+		}
+	}
+	return jobs
+}
+
+// CheckConsistency compares the loaded schedules with the jobs registered in
+// gocron and returns the divergences sorted by job name. Next runs are only
+// compared once the scheduler has started.
+func (s *Scheduler) CheckConsistency() []Divergence {
+	now := s.clock.Now()
+	location := s.deps.getLocation()
+
+	expected := make(map[string]config.Schedule)
+	actions := make(map[string]*config.ActionConfig)
+	// One-time jobs that already fired stay registered in gocron.
+	fired := make(map[string]bool)
+	s.deps.mu.Lock()
+	for _, sch := range s.deps.schedules {
+		if seasonEnded(sch, now) {
+			continue
+		}
+		for name := range expectedJobs(sch) {
+			fired[name] = true
+		}
+		active := withoutExpiredOneTimeActions(sch, now, false)
+		for name, cfg := range expectedJobs(active) {
+			delete(fired, name)
+			expected[name] = active
+			actions[name] = cfg
+		}
+	}
+	s.deps.mu.Unlock()
+
+	var divergences []Divergence
+	registered := make(map[string]bool)
+	for _, job := range s.cron().Jobs() {
+		if !slices.Contains(job.Tags(), managedScheduleTag) {
+			continue
+		}
+		name := job.Name()
+		registered[name] = true
+		sch, ok := expected[name]
+		if !ok && fired[name] {
+			continue
+		}
+		if !ok {
+			divergences = append(divergences, Divergence{Job: name, Kind: DivergenceUnexpected})
+			continue
+		}
+
+		actual, err := job.NextRun()
+		if err != nil || actual.IsZero() {
+			continue
+		}
+		// A job firing right now may still report its current run.
+		next, err := schedule.NextActionTime(sch, actions[name], now, location)
+		if err != nil {
+			continue
+		}
+		current, _ := schedule.NextActionTime(sch, actions[name], now.Add(-time.Minute), location)
+		if !actual.Equal(next) && !actual.Equal(current) {
+			divergences = append(divergences, Divergence{Job: name, Kind: DivergenceNextRun, Expected: next, Actual: actual})
+		}
+	}
+	for name := range expected {
+		if !registered[name] {
+			divergences = append(divergences, Divergence{Job: name, Kind: DivergenceMissing})
+		}
+	}
+
+	slices.SortFunc(divergences, func(a, b Divergence) int {
+		return strings.Compare(a.Job, b.Job)
+	})
+	return divergences
+}
+
+// StartConsistencyCheck checks consistency every interval until ctx is
+// canceled. Divergences are logged and counted, and a notification is sent
+// when they appear.
+func (s *Scheduler) StartConsistencyCheck(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := s.clock.NewTicker(interval)
+		defer ticker.Stop()
+
+		diverged := false
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.Chan():
+				divergences := s.CheckConsistency()
+				s.reportDivergences(divergences, !diverged)
+				diverged = len(divergences) > 0
+			}
+		}
+	}()
+}
+
+// reportDivergences logs and counts divergences and, if notifyNew is set,
+// sends a notification about them.
+func (s *Scheduler) reportDivergences(divergences []Divergence, notifyNew bool) {
+	s.mu.Lock()
+	m, notifier := s.metrics, s.notifier
+	s.mu.Unlock()
+
+	if m != nil {
+		m.SetJobDivergences(len(divergences))
+	}
+	for _, d := range divergences {
+		log.Error().
+			Str("job_name", d.Job).
+			Str("kind", d.Kind).
+			Time("expected", d.Expected).
+			Time("actual", d.Actual).
+			Msg("Scheduler jobs diverge from loaded schedules")
+	}
+	if notifyNew && len(divergences) > 0 {
+		err := fmt.Errorf("%d scheduler jobs diverge from loaded schedules, first: %s (%s)", len(divergences), divergences[0].Job, divergences[0].Kind)
+		notify.Send(notifier, notify.EventJobsDiverged, err)
+	}
+}
@@ -0,0 +1,124 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/schedule"
+)
+
+func TestCheckConsistency(t *testing.T) {
+	t.Parallel()
+
+	s, err := New("", 1)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = s.Start(ctx) }()
+
+	schedules := []config.Schedule{
+		makeSchedule("vm", "daily", true, true),
+		makeSchedule("db", "daily", true, false),
+	}
+	if err := s.RegisterSchedules(testStateChecker{}, testOperator{}, &config.Config{Schedules: schedules}, false, nil); err != nil {
+		t.Fatalf("RegisterSchedules() error = %v", err)
+	}
+	// Wait until gocron has computed next runs.
+	time.Sleep(100 * time.Millisecond)
+
+	if divergences := s.CheckConsistency(); len(divergences) != 0 {
+		t.Fatalf("CheckConsistency() = %+v, want none", divergences)
+	}
+
+	// A lost job and a stray job, e.g. after a partial reload.
+	s.cron().RemoveByTags(scheduleTag("db"))
+	if err := s.AddJob(gocron.DailyJob(1, gocron.NewAtTimes(gocron.NewAtTime(9, 0, 0))), "stray:start", func() {}, ""); err != nil {
+		t.Fatalf("AddJob() error = %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	divergences := s.CheckConsistency()
+	want := []Divergence{{Job: "db:start", Kind: DivergenceMissing}, {Job: "stray:start", Kind: DivergenceUnexpected}}
+	if len(divergences) != len(want) {
+		t.Fatalf("CheckConsistency() = %+v, want %+v", divergences, want)
+	}
+	for i := range want {
+		if divergences[i].Job != want[i].Job || divergences[i].Kind != want[i].Kind {
+			t.Fatalf("CheckConsistency() = %+v, want %+v", divergences, want)
+		}
+	}
+}
+
+func TestDurationScheduleMatchesNextActionTime(t *testing.T) {
+	t.Parallel()
+
+	s, err := New("", 1)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = s.Start(ctx) }()
+
+	sch := config.Schedule{
+		Name:     "batch",
+		Type:     "duration",
+		Resource: config.Resource{Type: "vm", ID: "vm-1"},
+		Actions: config.Actions{
+			Restart: &config.ActionConfig{Enabled: true, Time: "00:07", Every: config.Duration{Duration: 90 * time.Minute}},
+		},
+	}
+	if err := s.RegisterSchedules(testStateChecker{}, testOperator{}, &config.Config{Schedules: []config.Schedule{sch}}, false, nil); err != nil {
+		t.Fatalf("RegisterSchedules() error = %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	if divergences := s.CheckConsistency(); len(divergences) != 0 {
+		t.Fatalf("CheckConsistency() = %+v, want the duration job to run when NextActionTime expects", divergences)
+	}
+}
+
+func TestZonedScheduleMatchesNextActionTime(t *testing.T) {
+	t.Parallel()
+
+	s, err := New("UTC", 1)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = s.Start(ctx) }()
+
+	sch := config.Schedule{
+		Name:     "ural",
+		Type:     "weekly",
+		Timezone: "Asia/Yekaterinburg",
+		Resource: config.Resource{Type: "vm", ID: "vm-1"},
+		Actions: config.Actions{
+			Start: &config.ActionConfig{Enabled: true, Time: "08:30", Days: []int{1, 3, 5}},
+		},
+	}
+	if err := s.RegisterSchedules(testStateChecker{}, testOperator{}, &config.Config{Schedules: []config.Schedule{sch}}, false, nil); err != nil {
+		t.Fatalf("RegisterSchedules() error = %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	if divergences := s.CheckConsistency(); len(divergences) != 0 {
+		t.Fatalf("CheckConsistency() = %+v, want the job to run in the schedule timezone", divergences)
+	}
+
+	next, err := schedule.NextActionTime(sch, sch.Actions.Start, time.Now(), time.UTC)
+	if err != nil {
+		t.Fatalf("NextActionTime() error = %v", err)
+	}
+	location, _ := time.LoadLocation("Asia/Yekaterinburg")
+	if local := next.In(location); local.Hour() != 8 || local.Minute() != 30 {
+		t.Fatalf("NextActionTime() = %s, want 08:30 in Asia/Yekaterinburg", local)
+	}
+}
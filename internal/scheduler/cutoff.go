@@ -0,0 +1,93 @@
+package scheduler
+
+import (
+	"github.com/rs/zerolog/log"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/metrics"
+	"github.com/sentoz/yc-sheduler/internal/schedule"
+)
+
+// runMissed is the schedule run status of runs skipped by run_within.
+const runMissed = "missed"
+
+// punctual wraps a job function so a run starting more than run_within after
+// its scheduled time, e.g. after waiting in the job backlog, is skipped and
+// recorded as missed. Runs deferred by jitter or a random window are checked
+// when they become due, not when they start, and postponed stops run when
+// their postponement ends.
+func (s *Scheduler) punctual(sch config.Schedule, action string, m *metrics.Metrics, fn func()) func() {
+	cfg := actionConfig(sch, action)
+	if cfg == nil || cfg.RunWithin.Duration <= 0 {
+		return fn
+	}
+
+	return func() {
+		now := s.clock.Now()
+		location := s.deps.getLocation()
+		due, missed := schedule.Missed(sch, cfg, now.In(location), location)
+		if !missed || action == "stop" && s.stopPostponed(sch) {
+			fn()
+			return
+		}
+
+		log.Warn().
+			Str("schedule", sch.Name).
+			Str("action", action).
+			Time("due", due).
+			Dur("late", now.Sub(due)).
+			Dur("run_within", cfg.RunWithin.Duration).
+			Msg("Run is too late after its scheduled time, skipping it as missed")
+		// Dependents are skipped as well, as for paused schedules.
+		s.deps.recordMissed(sch.Name, action)
+		if action == "stop" {
+			s.skipStop(sch)
+		}
+		if m != nil {
+			for _, target := range sch.Targets() {
+				m.IncOperation(target.Type, action, "skipped")
+				m.IncSchedulerSkip(target.Type, action, runMissed)
+			}
+			m.IncScheduleRun(sch.Name, action, runMissed)
+		}
+	}
+}
+
+// recordMissedOnRegister records the last runs of the schedule actions as
+// missed when they were due longer than run_within ago and have no result
+// since, e.g. because the process was down. Otherwise the validator would
+// apply the state a missed run sets, such as a stop due at 20:00 after a
+// restart at 2:00. It must be called with s.mu held.
+func (s *Scheduler) recordMissedOnRegister(sch config.Schedule, m *metrics.Metrics) {
+	now := s.clock.Now()
+	location := s.deps.getLocation()
+	for _, action := range []string{"start", "stop", "snapshot", "restart", "resize"} {
+		cfg := actionConfig(sch, action)
+		if cfg == nil || !cfg.Enabled {
+			continue
+		}
+		due, missed := schedule.Missed(sch, cfg, now.In(location), location)
+		if !missed || s.deps.ranSince(sch.Name, action, due) {
+			continue
+		}
+		if action == "stop" && gracePeriod(sch) > 0 {
+			if _, postponed := s.stops.Postponed(sch.Name, now); postponed {
+				continue
+			}
+		}
+
+		log.Warn().
+			Str("schedule", sch.Name).
+			Str("action", action).
+			Time("due", due).
+			Dur("run_within", cfg.RunWithin.Duration).
+			Msg("Last run was due longer than run_within ago and did not run, recording it as missed")
+		s.deps.recordMissed(sch.Name, action)
+		if m != nil {
+			for _, target := range sch.Targets() {
+				m.IncSchedulerSkip(target.Type, action, runMissed)
+			}
+			m.IncScheduleRun(sch.Name, action, runMissed)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+func TestPunctual_SkipsRunsPastRunWithin(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{name: "on time", now: time.Date(2026, time.May, 4, 20, 0, 0, 0, time.UTC), want: true},
+		{name: "within", now: time.Date(2026, time.May, 4, 20, 25, 0, 0, time.UTC), want: true},
+		{name: "late", now: time.Date(2026, time.May, 4, 20, 31, 0, 0, time.UTC), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := NewWithClock("UTC", 1, clockwork.NewFakeClockAt(tt.now))
+			if err != nil {
+				t.Fatalf("NewWithClock() error = %v", err)
+			}
+			sch := makeSchedule("vm-night", "daily", false, true)
+			sch.Actions.Stop.Time = "20:00"
+			sch.Actions.Stop.RunWithin = config.Duration{Duration: 30 * time.Minute}
+
+			ran := false
+			s.punctual(sch, "stop", nil, func() { ran = true })()
+			if ran != tt.want {
+				t.Fatalf("ran = %v, want %v", ran, tt.want)
+			}
+			due := time.Date(2026, time.May, 4, 20, 0, 0, 0, time.UTC)
+			if got := s.MissedSince(sch.Name, "stop", due); got == tt.want {
+				t.Fatalf("MissedSince() = %v, want %v", got, !tt.want)
+			}
+			if tt.want {
+				return
+			}
+			runs := s.LastRuns()
+			if len(runs) != 1 || !runs[0].Missed || runs[0].OK {
+				t.Fatalf("LastRuns() = %+v, want one missed run", runs)
+			}
+		})
+	}
+}
+
+func TestPunctual_WithoutRunWithinRunsLate(t *testing.T) {
+	t.Parallel()
+
+	s, err := NewWithClock("UTC", 1, clockwork.NewFakeClockAt(time.Date(2026, time.May, 5, 2, 0, 0, 0, time.UTC)))
+	if err != nil {
+		t.Fatalf("NewWithClock() error = %v", err)
+	}
+	sch := makeSchedule("vm-night", "daily", false, true)
+
+	ran := false
+	s.punctual(sch, "stop", nil, func() { ran = true })()
+	if !ran {
+		t.Fatal("late run without run_within was skipped")
+	}
+}
+
+func TestRegisterSchedules_RecordsRunsMissedWhileDown(t *testing.T) {
+	t.Parallel()
+
+	due := time.Date(2026, time.May, 4, 20, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{name: "restart within run_within", now: due.Add(25 * time.Minute), want: false},
+		{name: "restart after run_within", now: time.Date(2026, time.May, 5, 2, 0, 0, 0, time.UTC), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := NewWithClock("UTC", 1, clockwork.NewFakeClockAt(tt.now))
+			if err != nil {
+				t.Fatalf("NewWithClock() error = %v", err)
+			}
+			sch := makeSchedule("vm-night", "daily", true, true)
+			sch.Actions.Stop.Time = "20:00"
+			sch.Actions.Stop.RunWithin = config.Duration{Duration: 30 * time.Minute}
+			cfg := &config.Config{Schedules: []config.Schedule{sch}}
+			if err := s.RegisterSchedules(testStateChecker{}, testOperator{}, cfg, false, nil); err != nil {
+				t.Fatalf("RegisterSchedules() error = %v", err)
+			}
+
+			if got := s.MissedSince(sch.Name, "stop", due); got != tt.want {
+				t.Fatalf("MissedSince(stop) = %v, want %v", got, tt.want)
+			}
+			// Actions without run_within are never missed.
+			if s.MissedSince(sch.Name, "start", time.Time{}) {
+				t.Fatal("MissedSince(start) = true for an action without run_within")
+			}
+		})
+	}
+}
+
+func TestReplaceSchedules_KeepsRunsThatRan(t *testing.T) {
+	t.Parallel()
+
+	clock := clockwork.NewFakeClockAt(time.Date(2026, time.May, 4, 20, 0, 0, 0, time.UTC))
+	s, err := NewWithClock("UTC", 1, clock)
+	if err != nil {
+		t.Fatalf("NewWithClock() error = %v", err)
+	}
+	sch := makeSchedule("vm-night", "daily", false, true)
+	sch.Actions.Stop.Time = "20:00"
+	sch.Actions.Stop.RunWithin = config.Duration{Duration: 30 * time.Minute}
+	if err := s.RegisterSchedules(testStateChecker{}, testOperator{}, &config.Config{Schedules: []config.Schedule{sch}}, false, nil); err != nil {
+		t.Fatalf("RegisterSchedules() error = %v", err)
+	}
+	s.deps.record(sch.Name, "stop", true)
+
+	// A reload at 2am must not turn the stop that ran at 20:00 into a miss.
+	clock.Advance(6 * time.Hour)
+	if err := s.ReplaceSchedules(testStateChecker{}, testOperator{}, []config.Schedule{sch}, false, nil); err != nil {
+		t.Fatalf("ReplaceSchedules() error = %v", err)
+	}
+	if s.MissedSince(sch.Name, "stop", time.Date(2026, time.May, 4, 20, 0, 0, 0, time.UTC)) {
+		t.Fatal("MissedSince() = true for a stop that ran before the reload")
+	}
+}
@@ -0,0 +1,397 @@
+package scheduler
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/rs/zerolog/log"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/executor"
+	"github.com/sentoz/yc-sheduler/internal/metrics"
+	"github.com/sentoz/yc-sheduler/internal/schedule"
+)
+
+const (
+	// dependencyWaitTimeout bounds how long a dependent run waits for the
+	// results of its dependencies.
+	dependencyWaitTimeout = 5 * time.Minute
+
+	// dependencyPollInterval is how often a dependent run rechecks results.
+	dependencyPollInterval = time.Second
+)
+
+// actionResult is the outcome of the last run of a schedule action.
+type actionResult struct {
+	at        time.Time
+	ok        bool
+	partial   bool
+	missed    bool
+	resources []executor.ResourceOutcome
+}
+
+// RunResult is the outcome of the last run of a schedule action.
+type RunResult struct {
+	At       time.Time `json:"at"`
+	Schedule string    `json:"schedule"`
+	Action   string    `json:"action"`
+	OK       bool      `json:"ok"`
+	// Partial marks failed runs that succeeded for some of the resources.
+	Partial bool `json:"partial,omitempty"`
+	// Missed marks runs skipped because they could not start within
+	// run_within after their scheduled time.
+	Missed bool `json:"missed,omitempty"`
+	// Resources holds the outcome for every resource of the run, including
+	// utilization snapshots of stopped VMs.
+	Resources []executor.ResourceOutcome `json:"resources,omitempty"`
+}
+
+// dependencies orders schedule actions by depends_on. A dependent run waits
+// until every dependency has finished the same action due at or before the
+// run and proceeds only if all of them succeeded.
+type dependencies struct {
+	mu        sync.Mutex
+	schedules map[string]config.Schedule
+	results   map[string]actionResult
+	location  *time.Location
+	startedAt time.Time
+	now       func() time.Time
+	poll      time.Duration
+	timeout   time.Duration
+}
+
+func newDependencies(location *time.Location, clock clockwork.Clock) *dependencies {
+	return &dependencies{
+		schedules: make(map[string]config.Schedule),
+		results:   make(map[string]actionResult),
+		location:  location,
+		startedAt: clock.Now(),
+		now:       clock.Now,
+		poll:      dependencyPollInterval,
+		timeout:   dependencyWaitTimeout,
+	}
+}
+
+// validateDependencies checks that schedules depend only on existing
+// schedules and that dependencies do not form a cycle.
+func validateDependencies(schedules []config.Schedule) error {
+	graph := make(map[string][]string, len(schedules))
+	for _, sch := range schedules {
+		graph[sch.Name] = sch.DependsOn
+	}
+	for _, sch := range schedules {
+		for _, dep := range sch.DependsOn {
+			if _, ok := graph[dep]; !ok {
+				return fmt.Errorf("schedule %q depends on %q: %w", sch.Name, dep, ErrUnknownDependency)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(graph))
+	var path []string
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			start := 0
+			for i, p := range path {
+				if p == name {
+					start = i
+				}
+			}
+			cycle := append(append([]string(nil), path[start:]...), name)
+			return fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(cycle, " -> "))
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range graph[name] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+	for _, sch := range schedules {
+		if err := visit(sch.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getLocation returns the location schedule times are taken in.
+func (d *dependencies) getLocation() *time.Location {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.location
+}
+
+// setLocation changes the location schedule times are taken in.
+func (d *dependencies) setLocation(location *time.Location) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.location = location
+}
+
+// setSchedules replaces the schedules dependencies are looked up in and
+// returns the renamed schedules by their old names. Recorded results are kept
+// across reloads and move to the new names of renamed schedules.
+func (d *dependencies) setSchedules(schedules []config.Schedule) map[string]string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	renames := renamedSchedules(d.schedules, schedules)
+	for key, result := range d.results {
+		i := strings.LastIndex(key, ":")
+		if updated, ok := renames[key[:i]]; ok {
+			delete(d.results, key)
+			d.results[updated+key[i:]] = result
+		}
+	}
+
+	d.schedules = make(map[string]config.Schedule, len(schedules))
+	for _, sch := range schedules {
+		d.schedules[sch.Name] = sch
+	}
+	return renames
+}
+
+// record stores the result of a finished run of a schedule action.
+func (d *dependencies) record(name, action string, ok bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.results[name+":"+action] = actionResult{at: d.now(), ok: ok}
+}
+
+// recordMissed records a run of a schedule action skipped as missed.
+func (d *dependencies) recordMissed(name, action string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.results[name+":"+action] = actionResult{at: d.now(), missed: true}
+}
+
+// missedSince reports whether the last run of the schedule action was
+// skipped as missed at or after since.
+func (d *dependencies) missedSince(name, action string, since time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	result, ok := d.results[name+":"+action]
+	return ok && result.missed && !result.at.Before(since)
+}
+
+// ranSince reports whether a result of the schedule action was recorded at
+// or after since.
+func (d *dependencies) ranSince(name, action string, since time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	result, ok := d.results[name+":"+action]
+	return ok && !result.at.Before(since)
+}
+
+// registered reports whether the named schedule is registered.
+func (d *dependencies) registered(name string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	_, ok := d.schedules[name]
+	return ok
+}
+
+// recordRun records the outcome of a run of the schedule action.
+func (d *dependencies) recordRun(name, action string, run executor.RunReport) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.results[name+":"+action] = actionResult{
+		at:        d.now(),
+		ok:        run.OK(),
+		partial:   run.Status() == executor.RunPartial,
+		resources: run.Resources,
+	}
+}
+
+// lastRuns returns the recorded results ordered by schedule and action.
+func (d *dependencies) lastRuns() []RunResult {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	runs := make([]RunResult, 0, len(d.results))
+	for key, result := range d.results {
+		i := strings.LastIndex(key, ":")
+		runs = append(runs, RunResult{
+			At:        result.at,
+			Schedule:  key[:i],
+			Action:    key[i+1:],
+			OK:        result.ok,
+			Partial:   result.partial,
+			Missed:    result.missed,
+			Resources: result.resources,
+		})
+	}
+	slices.SortFunc(runs, func(a, b RunResult) int {
+		return cmp.Or(strings.Compare(a.Schedule, b.Schedule), strings.Compare(a.Action, b.Action))
+	})
+	return runs
+}
+
+// wait blocks until all dependencies of sch finished action and returns an
+// empty reason if they succeeded, or the reason the run must be skipped.
+func (d *dependencies) wait(sch config.Schedule, action string) string {
+	deadline := d.now().Add(d.timeout)
+	for _, name := range sch.DependsOn {
+		for {
+			done, ok := d.finished(name, action)
+			if done && ok {
+				break
+			}
+			if done {
+				return "dependency_failed"
+			}
+			if d.now().After(deadline) {
+				return "dependency_timeout"
+			}
+			time.Sleep(d.poll)
+		}
+	}
+	return ""
+}
+
+// finished reports whether the dependency finished action due at or before
+// now and whether it succeeded. A dependency without the action, or whose
+// last run was due before the scheduler started, does not block.
+func (d *dependencies) finished(name, action string) (done bool, ok bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	dep, exists := d.schedules[name]
+	if !exists {
+		return true, true
+	}
+
+	now := d.now()
+	var due time.Time
+	for _, cfg := range enabledActions(dep, action) {
+		last, err := schedule.LastActionTime(dep, cfg, now.In(d.location), d.location)
+		if err != nil {
+			log.Debug().Err(err).
+				Str("schedule", dep.Name).
+				Str("action", action).
+				Msg("Failed to calculate last dependency run time")
+			continue
+		}
+		if last.After(due) {
+			due = last
+		}
+	}
+	if due.IsZero() || due.Before(d.startedAt) {
+		return true, true
+	}
+
+	result, recorded := d.results[name+":"+action]
+	if !recorded || result.at.Before(due) {
+		return false, false
+	}
+	return true, result.ok
+}
+
+// enabledActions returns the enabled configs of action in the schedule.
+func enabledActions(sch config.Schedule, action string) []*config.ActionConfig {
+	var entries []config.ActionConfig
+	switch action {
+	case "start":
+		return enabledAction(sch.Actions.Start)
+	case "stop":
+		return enabledAction(sch.Actions.Stop)
+	case "snapshot":
+		return enabledAction(sch.Actions.Snapshot)
+	case "restart":
+		return enabledAction(sch.Actions.Restart)
+	case "resize":
+		return enabledAction(sch.Actions.Resize)
+	case "scale":
+		entries = sch.Actions.Scale
+	case "preemptible":
+		entries = sch.Actions.Preemptible
+	}
+
+	var enabled []*config.ActionConfig
+	for i := range entries {
+		enabled = append(enabled, enabledAction(&entries[i])...)
+	}
+	return enabled
+}
+
+func enabledAction(cfg *config.ActionConfig) []*config.ActionConfig {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	return []*config.ActionConfig{cfg}
+}
+
+// ordered wraps a job function so it runs only after the dependencies of the
+// schedule succeeded with the same action. Skipped runs are recorded as
+// failed, so transitive dependents are skipped too.
+func (s *Scheduler) ordered(sch config.Schedule, action string, m *metrics.Metrics, fn func()) func() {
+	if len(sch.DependsOn) == 0 {
+		return fn
+	}
+
+	return func() {
+		reason := s.deps.wait(sch, action)
+		if reason == "" {
+			fn()
+			return
+		}
+
+		log.Warn().
+			Str("schedule", sch.Name).
+			Str("action", action).
+			Strs("depends_on", sch.DependsOn).
+			Str("reason", reason).
+			Msg("Schedule dependencies did not succeed, skipping run")
+		s.deps.record(sch.Name, action, false)
+		if m != nil {
+			for _, target := range sch.Targets() {
+				m.IncOperation(target.Type, action, "skipped")
+				m.IncSchedulerSkip(target.Type, action, reason)
+			}
+		}
+	}
+}
+
+// LastRuns returns the outcome of the last run of every schedule action
+// since the scheduler started, ordered by schedule and action.
+func (s *Scheduler) LastRuns() []RunResult {
+	return s.deps.lastRuns()
+}
+
+// MissedSince reports whether the last run of the schedule action was
+// skipped as missed by run_within at or after since.
+func (s *Scheduler) MissedSince(schedule, action string, since time.Time) bool {
+	return s.deps.missedSince(schedule, action, since)
+}
+
+// Registered reports whether the named schedule is among the schedules the
+// jobs were last registered for.
+func (s *Scheduler) Registered(name string) bool {
+	return s.deps.registered(name)
+}
@@ -0,0 +1,95 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+func TestReplaceSchedules_RejectsInvalidDependencies(t *testing.T) {
+	t.Parallel()
+
+	s, err := New("", 1)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	db := makeSchedule("db", "daily", true, false)
+	app := makeSchedule("app", "daily", true, false)
+	app.DependsOn = []string{"db"}
+	cfg := &config.Config{Schedules: []config.Schedule{db, app}}
+	if err := s.RegisterSchedules(testStateChecker{}, testOperator{}, cfg, false, nil); err != nil {
+		t.Fatalf("RegisterSchedules() error = %v", err)
+	}
+
+	cyclic := db
+	cyclic.DependsOn = []string{"app"}
+	err = s.ReplaceSchedules(testStateChecker{}, testOperator{}, []config.Schedule{cyclic, app}, false, nil)
+	if !errors.Is(err, ErrDependencyCycle) {
+		t.Fatalf("ReplaceSchedules() error = %v, want ErrDependencyCycle", err)
+	}
+	if got := len(s.cron().Jobs()); got != 2 {
+		t.Fatalf("jobs after rejected replace = %d, want 2 kept", got)
+	}
+
+	err = s.ReplaceSchedules(testStateChecker{}, testOperator{}, []config.Schedule{app}, false, nil)
+	if !errors.Is(err, ErrUnknownDependency) {
+		t.Fatalf("ReplaceSchedules() error = %v, want ErrUnknownDependency", err)
+	}
+}
+
+func TestDependenciesWait(t *testing.T) {
+	t.Parallel()
+
+	// The database starts at 09:00 and the dependent run starts at 09:00:30.
+	due := time.Date(2026, 10, 5, 9, 0, 0, 0, time.UTC)
+	db := makeSchedule("db", "daily", true, false)
+	db.Actions.Start.Time = "09:00"
+	app := makeSchedule("app", "daily", true, false)
+	app.DependsOn = []string{"db"}
+
+	tests := []struct {
+		name      string
+		result    *actionResult
+		startedAt time.Time
+		want      string
+	}{
+		{name: "dependency succeeded", result: &actionResult{at: due.Add(10 * time.Second), ok: true}},
+		{name: "dependency failed", result: &actionResult{at: due.Add(10 * time.Second)}, want: "dependency_failed"},
+		{name: "no result", want: "dependency_timeout"},
+		{name: "stale result", result: &actionResult{at: due.Add(-24 * time.Hour), ok: true}, want: "dependency_timeout"},
+		{name: "due before scheduler start", startedAt: due.Add(time.Minute)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Every clock read advances by a second, so waits time out quickly.
+			now := due.Add(30 * time.Second)
+			d := newDependencies(time.UTC, clockwork.NewRealClock())
+			d.now = func() time.Time {
+				now = now.Add(time.Second)
+				return now
+			}
+			d.startedAt = due.Add(-time.Hour)
+			if !tt.startedAt.IsZero() {
+				d.startedAt = tt.startedAt
+			}
+			d.poll = time.Millisecond
+			d.timeout = 5 * time.Second
+			d.setSchedules([]config.Schedule{db, app})
+			if tt.result != nil {
+				d.results["db:start"] = *tt.result
+			}
+
+			if got := d.wait(app, "start"); got != tt.want {
+				t.Fatalf("wait() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
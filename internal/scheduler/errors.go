@@ -9,4 +9,11 @@ var (
 	// ErrMissingJobConfig is returned when a schedule does not contain the
 	// corresponding job configuration for its type.
 	ErrMissingJobConfig = errors.New("missing job configuration for schedule")
+
+	// ErrUnknownDependency is returned when a schedule depends on a schedule
+	// that does not exist.
+	ErrUnknownDependency = errors.New("unknown schedule dependency")
+
+	// ErrDependencyCycle is returned when schedule dependencies form a cycle.
+	ErrDependencyCycle = errors.New("schedule dependency cycle")
 )
@@ -0,0 +1,176 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/grace"
+	"github.com/sentoz/yc-sheduler/internal/notify"
+	"github.com/sentoz/yc-sheduler/internal/schedule"
+)
+
+// SetStops sets the registry of announced stops consulted before stops with
+// a grace period run. Postponed stops are deferred until their new time.
+func (s *Scheduler) SetStops(stops *grace.Registry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stops = stops
+}
+
+// SetNotifier sets the notifier receiving stop_imminent and action_failed
+// events.
+func (s *Scheduler) SetNotifier(n notify.Notifier) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.notifier = n
+}
+
+// gracePeriod returns the grace period of the schedule stop action.
+func gracePeriod(sch config.Schedule) time.Duration {
+	if sch.Actions.Stop == nil {
+		return 0
+	}
+	return sch.Actions.Stop.GracePeriod.Duration
+}
+
+// graced wraps the stop job function of a schedule with a grace period. Each
+// run announces the next stop, and a run postponed through the stop registry
+// is deferred as a one-time job until the postponement ends. A deferred run
+// of a schedule replaced since is dropped: the replaced schedule may target
+// other resources, and the validator applies the stop of the current one
+// once the postponement ends.
+func (s *Scheduler) graced(sch config.Schedule, fn func()) func() {
+	if gracePeriod(sch) <= 0 {
+		return fn
+	}
+
+	var run func()
+	run = func() {
+		s.mu.Lock()
+		stops := s.stops
+		s.mu.Unlock()
+
+		if stop, postponed := stops.Postponed(sch.Name, s.clock.Now()); postponed {
+			s.mu.Lock()
+			generation := s.generation
+			err := s.addOneTimeJobUnlocked(sch.Name+":stop:postponed", stop.Until, func() {
+				s.mu.Lock()
+				current := s.generation == generation
+				s.mu.Unlock()
+				if !current {
+					log.Info().
+						Str("schedule", sch.Name).
+						Msg("Schedule was replaced, dropping postponed stop")
+					return
+				}
+				run()
+			})
+			s.mu.Unlock()
+			if err == nil {
+				log.Info().
+					Str("schedule", sch.Name).
+					Time("until", stop.Until).
+					Str("reason", stop.Reason).
+					Msg("Stop is postponed, deferring run")
+				return
+			}
+			log.Error().Err(err).
+				Str("schedule", sch.Name).
+				Msg("Failed to defer postponed stop, running it now")
+		}
+		stops.Done(sch.Name)
+		fn()
+	}
+
+	return func() {
+		s.mu.Lock()
+		err := s.announceNextStopUnlocked(sch, s.clock.Now())
+		s.mu.Unlock()
+		if err != nil {
+			log.Error().Err(err).
+				Str("schedule", sch.Name).
+				Msg("Failed to schedule next stop announcement")
+		}
+		run()
+	}
+}
+
+// stopPostponed reports whether the stop of a schedule with a grace period
+// is postponed at now.
+func (s *Scheduler) stopPostponed(sch config.Schedule) bool {
+	if gracePeriod(sch) <= 0 {
+		return false
+	}
+	s.mu.Lock()
+	stops := s.stops
+	s.mu.Unlock()
+
+	_, postponed := stops.Postponed(sch.Name, s.clock.Now())
+	return postponed
+}
+
+// skipStop completes the announced stop of a schedule with a grace period
+// that is skipped without running and announces the next one, like a run of
+// the stop does.
+func (s *Scheduler) skipStop(sch config.Schedule) {
+	if gracePeriod(sch) <= 0 {
+		return
+	}
+	s.mu.Lock()
+	stops := s.stops
+	err := s.announceNextStopUnlocked(sch, s.clock.Now())
+	s.mu.Unlock()
+	if err != nil {
+		log.Error().Err(err).
+			Str("schedule", sch.Name).
+			Msg("Failed to schedule next stop announcement")
+	}
+	stops.Done(sch.Name)
+}
+
+// announceNextStopUnlocked adds a one-time job announcing the next stop of
+// the schedule after now at the start of its grace period, or immediately
+// when the grace period has already started. It must be called with s.mu
+// held.
+func (s *Scheduler) announceNextStopUnlocked(sch config.Schedule, now time.Time) error {
+	period := gracePeriod(sch)
+	at, err := schedule.NextActionTime(sch, sch.Actions.Stop, now, s.deps.getLocation())
+	if err != nil {
+		return fmt.Errorf("scheduler: next stop of %q: %w", sch.Name, err)
+	}
+	// Stops after the schedule active period are not announced.
+	if seasonEnded(sch, at) {
+		return nil
+	}
+
+	generation := s.generation
+	announce := func() {
+		s.mu.Lock()
+		current := s.generation == generation
+		stops, notifier := s.stops, s.notifier
+		s.mu.Unlock()
+		// Announcements of replaced schedules are dropped.
+		if !current {
+			return
+		}
+
+		if _, announced := stops.Announce(sch.Name, at); !announced {
+			return
+		}
+		event := notify.NewEvent(notify.EventStopImminent, nil)
+		event.Schedule = sch.Name
+		event.At = at
+		notify.SendEvent(notifier, event)
+	}
+
+	var start time.Time
+	if noticeAt := at.Add(-period); noticeAt.After(now) {
+		start = noticeAt
+	}
+	return s.addOneTimeJobUnlocked(sch.Name+":stop:notice", start, announce)
+}
@@ -0,0 +1,125 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/grace"
+	"github.com/sentoz/yc-sheduler/internal/notify"
+)
+
+type eventRecorder chan notify.Event
+
+func (r eventRecorder) Notify(_ context.Context, event notify.Event) error {
+	r <- event
+	return nil
+}
+
+func TestRegisterSchedules_AnnouncesStopWithinGracePeriod(t *testing.T) {
+	t.Parallel()
+
+	s, err := New("", 1)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	stops := grace.NewRegistry()
+	events := make(eventRecorder, 1)
+	s.SetStops(stops)
+	s.SetNotifier(events)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = s.Start(ctx) }()
+
+	// The grace period covers the next daily stop, so it is announced at once.
+	sch := makeSchedule("vm", "daily", false, true)
+	sch.Actions.Stop.GracePeriod = config.Duration{Duration: 25 * time.Hour}
+	if err := s.RegisterSchedules(testStateChecker{}, testOperator{}, &config.Config{Schedules: []config.Schedule{sch}}, false, nil); err != nil {
+		t.Fatalf("RegisterSchedules() error = %v", err)
+	}
+
+	select {
+	case event := <-events:
+		if event.Type != notify.EventStopImminent || event.Schedule != "vm" || !event.At.After(time.Now()) {
+			t.Fatalf("event = %+v, want stop_imminent of vm in the future", event)
+		}
+		listed := stops.List()
+		if len(listed) != 1 || !listed[0].At.Equal(event.At) {
+			t.Fatalf("stops = %+v, want the announced stop at %s", listed, event.At)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("stop was not announced")
+	}
+}
+
+func TestGraced_DefersPostponedStop(t *testing.T) {
+	t.Parallel()
+
+	s, err := New("", 1)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	stops := grace.NewRegistry()
+	s.SetStops(stops)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = s.Start(ctx) }()
+
+	sch := makeSchedule("vm", "daily", false, true)
+	sch.Actions.Stop.GracePeriod = config.Duration{Duration: 15 * time.Minute}
+	started := time.Now()
+	stops.Announce("vm", started)
+	if _, err := stops.Postpone("vm", started.Add(100*time.Millisecond), "demo"); err != nil {
+		t.Fatalf("Postpone() error = %v", err)
+	}
+
+	ran := make(chan time.Time, 1)
+	s.graced(sch, func() { ran <- time.Now() })()
+
+	select {
+	case at := <-ran:
+		if delay := at.Sub(started); delay < 100*time.Millisecond {
+			t.Fatalf("stop ran after %v, want it postponed by 100ms", delay)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("postponed stop did not run")
+	}
+	if _, postponed := stops.Postponed("vm", time.Now()); postponed {
+		t.Fatal("stop is still postponed after it ran")
+	}
+}
+
+func TestGraced_DropsPostponedStopOfReplacedSchedule(t *testing.T) {
+	t.Parallel()
+
+	s, err := New("", 1)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	stops := grace.NewRegistry()
+	s.SetStops(stops)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = s.Start(ctx) }()
+
+	sch := makeSchedule("vm", "daily", false, true)
+	sch.Actions.Stop.GracePeriod = config.Duration{Duration: 15 * time.Minute}
+	started := time.Now()
+	stops.Announce("vm", started)
+	if _, err := stops.Postpone("vm", started.Add(100*time.Millisecond), "demo"); err != nil {
+		t.Fatalf("Postpone() error = %v", err)
+	}
+
+	ran := make(chan struct{}, 1)
+	s.graced(sch, func() { ran <- struct{}{} })()
+	if err := s.ReplaceSchedules(testStateChecker{}, testOperator{}, []config.Schedule{sch}, false, nil); err != nil {
+		t.Fatalf("ReplaceSchedules() error = %v", err)
+	}
+
+	select {
+	case <-ran:
+		t.Fatal("postponed stop of the replaced schedule ran")
+	case <-time.After(500 * time.Millisecond):
+	}
+}
@@ -0,0 +1,83 @@
+package scheduler
+
+import (
+	"math/rand/v2"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/schedule"
+)
+
+// jitterOffset returns the delay of a run within the jitter window.
+var jitterOffset = defaultJitterOffset
+
+// defaultJitterOffset returns a random delay in [0, window).
+func defaultJitterOffset(window time.Duration) time.Duration {
+	return rand.N(window)
+}
+
+// jittered wraps a job function of a schedule with jitter so every run is
+// deferred by a random offset within the window: the schedule jitter plus
+// the daily window of random_window schedules. The deferred run is added as
+// a one-time job, so waiting does not hold a concurrency slot.
+func (s *Scheduler) jittered(sch config.Schedule, action string, fn func()) func() {
+	window := schedule.DeferralWindow(sch, actionConfig(sch, action))
+	if window <= 0 {
+		return fn
+	}
+
+	return func() {
+		offset := jitterOffset(window)
+		if offset <= 0 {
+			fn()
+			return
+		}
+
+		name := sch.Name + ":" + action + ":jitter"
+		s.mu.Lock()
+		err := s.addOneTimeJobUnlocked(name, s.clock.Now().Add(offset), fn)
+		s.mu.Unlock()
+		if err != nil {
+			log.Error().Err(err).
+				Str("schedule", sch.Name).
+				Str("action", action).
+				Msg("Failed to defer jittered run, running it now")
+			fn()
+			return
+		}
+
+		log.Debug().
+			Str("schedule", sch.Name).
+			Str("action", action).
+			Dur("jitter", offset).
+			Msg("Scheduled run deferred by jitter")
+	}
+}
+
+// actionConfig returns the configuration of action of a schedule narrowed to
+// a single scale or preemptible entry.
+func actionConfig(sch config.Schedule, action string) *config.ActionConfig {
+	switch action {
+	case "start":
+		return sch.Actions.Start
+	case "stop":
+		return sch.Actions.Stop
+	case "snapshot":
+		return sch.Actions.Snapshot
+	case "restart":
+		return sch.Actions.Restart
+	case "resize":
+		return sch.Actions.Resize
+	case "scale":
+		if len(sch.Actions.Scale) > 0 {
+			return &sch.Actions.Scale[0]
+		}
+	case "preemptible":
+		if len(sch.Actions.Preemptible) > 0 {
+			return &sch.Actions.Preemptible[0]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+func TestJittered_DefersRunByOffset(t *testing.T) {
+	jitterOffset = func(time.Duration) time.Duration { return 100 * time.Millisecond }
+	t.Cleanup(func() { jitterOffset = defaultJitterOffset })
+
+	s, err := New("", 1)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = s.Start(ctx) }()
+
+	ran := make(chan time.Time, 1)
+	sch := config.Schedule{Name: "vm", Jitter: config.Duration{Duration: 5 * time.Minute}}
+	started := time.Now()
+	s.jittered(sch, "start", func() { ran <- time.Now() })()
+
+	select {
+	case at := <-ran:
+		if delay := at.Sub(started); delay < 100*time.Millisecond {
+			t.Fatalf("run delayed by %v, want at least the 100ms jitter", delay)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("jittered run did not happen")
+	}
+
+	// Without jitter the job function runs as is.
+	direct := false
+	s.jittered(config.Schedule{Name: "vm"}, "start", func() { direct = true })()
+	if !direct {
+		t.Fatal("run without jitter was deferred")
+	}
+}
+
+func TestJittered_RandomWindow(t *testing.T) {
+	var got time.Duration
+	jitterOffset = func(window time.Duration) time.Duration {
+		got = window
+		return 0
+	}
+	t.Cleanup(func() { jitterOffset = defaultJitterOffset })
+
+	s, err := New("", 1)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	sch := config.Schedule{
+		Name: "vm",
+		Type: "random_window",
+		Actions: config.Actions{
+			Restart: &config.ActionConfig{Enabled: true, Time: "23:30", WindowEnd: "00:30"},
+		},
+	}
+	if _, err := ScheduleToJobDefinition(sch, sch.Actions.Restart); err != nil {
+		t.Fatalf("ScheduleToJobDefinition() error = %v", err)
+	}
+
+	ran := false
+	s.jittered(sch, "restart", func() { ran = true })()
+	if !ran || got != time.Hour {
+		t.Fatalf("ran = %v, window = %v, want a run within the 1h window across midnight", ran, got)
+	}
+
+	sch.Actions.Restart.WindowEnd = ""
+	if _, err := ScheduleToJobDefinition(sch, sch.Actions.Restart); err == nil {
+		t.Fatal("ScheduleToJobDefinition() without window_end succeeded, want an error")
+	}
+}
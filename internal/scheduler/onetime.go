@@ -0,0 +1,60 @@
+package scheduler
+
+import (
+	"slices"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+// withoutExpiredOneTimeActions returns the schedule with the actions of a
+// one-time schedule that are not after now disabled, since they have run or
+// were missed. With logSkipped, every disabled action is logged.
+func withoutExpiredOneTimeActions(sch config.Schedule, now time.Time, logSkipped bool) config.Schedule {
+	if sch.Type != "one-time" {
+		return sch
+	}
+
+	expire := func(action string, cfg config.ActionConfig) config.ActionConfig {
+		if !cfg.Enabled {
+			return cfg
+		}
+		at, err := cfg.At.Time()
+		if err != nil || at.After(now) {
+			return cfg
+		}
+		if logSkipped {
+			log.Warn().
+				Str("schedule", sch.Name).
+				Str("action", action).
+				Time("at", at).
+				Msg("One-time action is in the past, skipping it")
+		}
+		cfg.Enabled = false
+		return cfg
+	}
+	single := func(action string, cfg *config.ActionConfig) *config.ActionConfig {
+		if cfg == nil {
+			return nil
+		}
+		expired := expire(action, *cfg)
+		return &expired
+	}
+
+	sch.Actions.Start = single("start", sch.Actions.Start)
+	sch.Actions.Stop = single("stop", sch.Actions.Stop)
+	sch.Actions.Snapshot = single("snapshot", sch.Actions.Snapshot)
+	sch.Actions.Restart = single("restart", sch.Actions.Restart)
+	sch.Actions.Resize = single("resize", sch.Actions.Resize)
+	sch.Actions.Scale = slices.Clone(sch.Actions.Scale)
+	for i := range sch.Actions.Scale {
+		sch.Actions.Scale[i] = expire("scale", sch.Actions.Scale[i])
+	}
+	sch.Actions.Preemptible = slices.Clone(sch.Actions.Preemptible)
+	for i := range sch.Actions.Preemptible {
+		sch.Actions.Preemptible[i] = expire("preemptible", sch.Actions.Preemptible[i])
+	}
+	return sch
+}
@@ -0,0 +1,37 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+func TestRegisterSchedules_SkipsExpiredOneTimeActions(t *testing.T) {
+	t.Parallel()
+
+	s, err := New("", 1)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = s.Start(ctx) }()
+
+	sch := makeSchedule("migration", "one-time", true, true)
+	sch.Actions.Stop.At = config.RFC3339Time(time.Now().Add(-time.Hour).Format(time.RFC3339))
+	sch.Actions.Start.At = config.RFC3339Time(time.Now().Add(time.Hour).Format(time.RFC3339))
+	if err := s.RegisterSchedules(testStateChecker{}, testOperator{}, &config.Config{Schedules: []config.Schedule{sch}}, false, nil); err != nil {
+		t.Fatalf("RegisterSchedules() error = %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	jobs := s.cron().Jobs()
+	if len(jobs) != 1 || jobs[0].Name() != "migration:start" {
+		t.Fatalf("jobs = %d, want only migration:start", len(jobs))
+	}
+	if divergences := s.CheckConsistency(); len(divergences) != 0 {
+		t.Fatalf("CheckConsistency() = %+v, want none", divergences)
+	}
+}
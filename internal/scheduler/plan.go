@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/schedule"
+)
+
+// planLookahead bounds the number of computed runs of a job, so a job
+// without runs in the active period does not loop for long.
+const planLookahead = 1000
+
+// PlannedJob is a job registerScheduleUnlocked adds for a schedule action,
+// with its upcoming runs.
+type PlannedJob struct {
+	Job       string
+	Schedule  string
+	Action    string
+	Resources []config.Resource
+	// Runs holds the nominal times of the upcoming runs, before the schedule
+	// jitter or random window defers them.
+	Runs []time.Time
+	// Err is set when the runs of the job cannot be computed.
+	Err error
+}
+
+// Plan returns the jobs of the schedules with at most count upcoming runs
+// after now, sorted by job name. Runs outside the active period of a
+// schedule are left out; schedules whose active period has ended have no
+// jobs.
+func Plan(schedules []config.Schedule, timezone string, now time.Time, count int) ([]PlannedJob, error) {
+	location, err := loadLocation(timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []PlannedJob
+	for _, sch := range schedules {
+		if seasonEnded(sch, now) {
+			continue
+		}
+		active := withoutExpiredOneTimeActions(sch, now, false)
+		expected := expectedJobs(active)
+		for _, name := range slices.Sorted(maps.Keys(expected)) {
+			action, _, _ := strings.Cut(strings.TrimPrefix(name, sch.Name+":"), ":")
+			job := PlannedJob{Job: name, Schedule: sch.Name, Action: action, Resources: sch.Targets()}
+			job.Runs, job.Err = upcomingRuns(active, expected[name], now, location, count)
+			jobs = append(jobs, job)
+		}
+	}
+
+	slices.SortFunc(jobs, func(a, b PlannedJob) int {
+		return strings.Compare(a.Job, b.Job)
+	})
+	return jobs, nil
+}
+
+// upcomingRuns returns at most count runs of the action after now within the
+// active period of the schedule.
+func upcomingRuns(sch config.Schedule, action *config.ActionConfig, now time.Time, location *time.Location, count int) ([]time.Time, error) {
+	var runs []time.Time
+	at := now
+	// Runs before the active period are skipped right away.
+	if from, _ := sch.ActivePeriod(); from.After(at) {
+		at = from.Add(-time.Nanosecond)
+	}
+	for range planLookahead {
+		if len(runs) == count {
+			break
+		}
+		next, err := schedule.NextActionTime(sch, action, at, location)
+		if err != nil {
+			// One-time actions have no run after their only one.
+			if len(runs) > 0 && sch.Type == "one-time" {
+				break
+			}
+			return runs, err
+		}
+		if seasonEnded(sch, next) {
+			break
+		}
+		if sch.ActiveAt(next) {
+			runs = append(runs, next.In(location))
+		}
+		at = next
+	}
+	return runs, nil
+}
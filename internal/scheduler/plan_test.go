@@ -0,0 +1,64 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+func TestPlan(t *testing.T) {
+	t.Parallel()
+
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+	now := time.Date(2026, time.May, 4, 12, 0, 0, 0, moscow)
+
+	daily := makeSchedule("vm", "daily", true, true)
+	seasonal := makeSchedule("summer", "daily", true, false)
+	seasonal.ActiveFrom = "2026-06-01T00:00:00+03:00"
+	seasonal.ActiveUntil = "2026-06-03T00:00:00+03:00"
+	ended := makeSchedule("spring", "daily", true, false)
+	ended.ActiveUntil = "2026-05-01T00:00:00+03:00"
+
+	jobs, err := Plan([]config.Schedule{seasonal, daily, ended}, "Europe/Moscow", now, 3)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+
+	want := []struct {
+		job, action string
+		runs        []time.Time
+	}{
+		{job: "summer:start", action: "start", runs: []time.Time{
+			time.Date(2026, time.June, 1, 9, 0, 0, 0, moscow),
+			time.Date(2026, time.June, 2, 9, 0, 0, 0, moscow),
+		}},
+		{job: "vm:start", action: "start", runs: []time.Time{
+			time.Date(2026, time.May, 5, 9, 0, 0, 0, moscow),
+			time.Date(2026, time.May, 6, 9, 0, 0, 0, moscow),
+			time.Date(2026, time.May, 7, 9, 0, 0, 0, moscow),
+		}},
+		{job: "vm:stop", action: "stop", runs: []time.Time{
+			time.Date(2026, time.May, 4, 18, 0, 0, 0, moscow),
+			time.Date(2026, time.May, 5, 18, 0, 0, 0, moscow),
+			time.Date(2026, time.May, 6, 18, 0, 0, 0, moscow),
+		}},
+	}
+	if len(jobs) != len(want) {
+		t.Fatalf("Plan() = %+v, want %d jobs", jobs, len(want))
+	}
+	for i, w := range want {
+		job := jobs[i]
+		if job.Job != w.job || job.Action != w.action || job.Err != nil || len(job.Runs) != len(w.runs) {
+			t.Fatalf("Plan()[%d] = %+v, want %s with %d runs", i, job, w.job, len(w.runs))
+		}
+		for j, run := range w.runs {
+			if !job.Runs[j].Equal(run) {
+				t.Fatalf("Plan()[%d].Runs = %v, want %v", i, job.Runs, w.runs)
+			}
+		}
+	}
+}
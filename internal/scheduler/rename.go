@@ -0,0 +1,61 @@
+package scheduler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+// scheduleFingerprint identifies a schedule by its type, resources and
+// actions, which stay the same when only the schedule name changes.
+func scheduleFingerprint(sch config.Schedule) string {
+	data, err := json.Marshal(struct {
+		Type      string            `json:"type"`
+		Resources []config.Resource `json:"resources"`
+		Actions   config.Actions    `json:"actions"`
+	}{sch.Type, sch.Targets(), sch.Actions})
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// renamedSchedules matches schedules removed from old with schedules added in
+// updated by their fingerprints and returns the new names by old names. Only
+// fingerprints unique among both removed and added schedules are matched, so
+// copies of a schedule are never taken for a rename.
+func renamedSchedules(old map[string]config.Schedule, updated []config.Schedule) map[string]string {
+	names := make(map[string]struct{}, len(updated))
+	for _, sch := range updated {
+		names[sch.Name] = struct{}{}
+	}
+
+	removed := make(map[string][]string)
+	for name, sch := range old {
+		if _, ok := names[name]; !ok {
+			fp := scheduleFingerprint(sch)
+			removed[fp] = append(removed[fp], name)
+		}
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+	added := make(map[string][]string)
+	for _, sch := range updated {
+		if _, ok := old[sch.Name]; !ok {
+			fp := scheduleFingerprint(sch)
+			added[fp] = append(added[fp], sch.Name)
+		}
+	}
+
+	renames := make(map[string]string)
+	for fp, oldNames := range removed {
+		if newNames := added[fp]; fp != "" && len(oldNames) == 1 && len(newNames) == 1 {
+			renames[oldNames[0]] = newNames[0]
+		}
+	}
+	return renames
+}
@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/grace"
+)
+
+func TestReplaceSchedules_KeepsStateOfRenamedSchedule(t *testing.T) {
+	t.Parallel()
+
+	s, err := New("", 1)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	stops := grace.NewRegistry()
+	s.SetStops(stops)
+
+	old := makeSchedule("vm-old", "daily", true, true)
+	if err := s.RegisterSchedules(testStateChecker{}, testOperator{}, &config.Config{Schedules: []config.Schedule{old}}, false, nil); err != nil {
+		t.Fatalf("RegisterSchedules() error = %v", err)
+	}
+	s.deps.record("vm-old", "start", true)
+	at := time.Now().Add(time.Hour)
+	stops.Announce("vm-old", at)
+	if _, err := stops.Postpone("vm-old", at.Add(time.Hour), "demo"); err != nil {
+		t.Fatalf("Postpone() error = %v", err)
+	}
+
+	renamed := makeSchedule("vm-new", "daily", true, true)
+	if err := s.ReplaceSchedules(testStateChecker{}, testOperator{}, []config.Schedule{renamed}, false, nil); err != nil {
+		t.Fatalf("ReplaceSchedules() error = %v", err)
+	}
+
+	runs := s.LastRuns()
+	if len(runs) != 1 || runs[0].Schedule != "vm-new" || !runs[0].OK {
+		t.Fatalf("LastRuns() = %+v, want the run under the new name", runs)
+	}
+	if stop, ok := stops.Postponed("vm-new", at); !ok || stop.Reason != "demo" {
+		t.Fatalf("Postponed() = %+v, %v; want the postponed stop under the new name", stop, ok)
+	}
+}
+
+func TestRenamedSchedules(t *testing.T) {
+	t.Parallel()
+
+	old := map[string]config.Schedule{
+		"a":      makeSchedule("a", "daily", true, false),
+		"copy-1": makeSchedule("copy-1", "daily", false, true),
+		"copy-2": makeSchedule("copy-2", "daily", false, true),
+		"kept":   makeSchedule("kept", "weekly", true, true),
+	}
+	changed := makeSchedule("changed", "daily", true, false)
+	changed.Actions.Start.Time = "10:00"
+	updated := []config.Schedule{
+		makeSchedule("b", "daily", true, false),
+		makeSchedule("copy-3", "daily", false, true),
+		makeSchedule("kept", "weekly", true, true),
+		changed,
+	}
+
+	renames := renamedSchedules(old, updated)
+	if len(renames) != 1 || renames["a"] != "b" {
+		t.Fatalf("renamedSchedules() = %v, want only a -> b", renames)
+	}
+}
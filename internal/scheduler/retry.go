@@ -0,0 +1,121 @@
+package scheduler
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/executor"
+	"github.com/sentoz/yc-sheduler/internal/metrics"
+	"github.com/sentoz/yc-sheduler/internal/notify"
+	"github.com/sentoz/yc-sheduler/internal/resource"
+)
+
+// retrying returns the job function executing action for the schedule and
+// reporting every run to record. If the action has a retry_failed block, a
+// failed run is repeated as a one-time job after a backoff until it succeeds
+// or the retries run out. Retries are skipped while the schedule is paused, a
+// blackout window is active or its dependencies did not succeed, and dropped
+// once the schedule is replaced. A run failing for good is reported as an
+// action_failed event, or action_partial if it succeeded for some resources.
+// It must be called with s.mu held.
+func (s *Scheduler) retrying(stateChecker resource.StateChecker, operator resource.Operator, sch config.Schedule, action string, dryRun bool, m *metrics.Metrics, record func(executor.RunReport)) func() {
+	var retry *config.RetryConfig
+	if cfg := actionConfig(sch, action); cfg != nil {
+		retry = cfg.RetryFailed
+	}
+	exec := s.executor
+	if retry == nil {
+		return exec.MakeWithRunReport(stateChecker, operator, sch, action, dryRun, m, func(run executor.RunReport) {
+			record(run)
+			if !run.OK() {
+				s.notifyFailed(sch, action, run)
+			}
+		})
+	}
+
+	generation := s.generation
+	var attemptRun func(attempt int) func()
+	attemptRun = func(attempt int) func() {
+		return exec.MakeWithRunReport(stateChecker, operator, sch, action, dryRun, m, func(run executor.RunReport) {
+			record(run)
+			if run.OK() {
+				return
+			}
+			if attempt > retry.EffectiveRetries() {
+				log.Error().
+					Str("schedule", sch.Name).
+					Str("action", action).
+					Int("attempts", attempt).
+					Msg("Action failed, retries exhausted")
+				s.notifyFailed(sch, action, run)
+				return
+			}
+			next := s.pausable(sch, action, m, s.outsideBlackouts(sch, action, m, s.ordered(sch, action, m, attemptRun(attempt+1))))
+			s.scheduleRetry(sch, action, attempt, retry, generation, next)
+		})
+	}
+	return attemptRun(1)
+}
+
+// scheduleRetry adds fn as a one-time job running after the backoff of the
+// failed attempt. One-time jobs survive reloads, so the retry is dropped if
+// the schedules were replaced since generation.
+func (s *Scheduler) scheduleRetry(sch config.Schedule, action string, attempt int, retry *config.RetryConfig, generation uint64, fn func()) {
+	delay := retry.Delay(attempt)
+	name := fmt.Sprintf("%s:%s:retry:%d", sch.Name, action, attempt)
+	run := func() {
+		s.mu.Lock()
+		current := s.generation == generation
+		s.mu.Unlock()
+		if !current {
+			log.Info().
+				Str("schedule", sch.Name).
+				Str("action", action).
+				Int("attempt", attempt).
+				Msg("Schedule was replaced, dropping retry")
+			return
+		}
+		fn()
+	}
+
+	s.mu.Lock()
+	err := s.addOneTimeJobUnlocked(name, s.clock.Now().Add(delay), run)
+	s.mu.Unlock()
+	if err != nil {
+		log.Error().Err(err).
+			Str("schedule", sch.Name).
+			Str("action", action).
+			Msg("Failed to schedule retry of failed action")
+		return
+	}
+
+	log.Warn().
+		Str("schedule", sch.Name).
+		Str("action", action).
+		Int("attempt", attempt).
+		Dur("delay", delay).
+		Msg("Action failed, retry scheduled")
+}
+
+// notifyFailed sends an action_failed or action_partial event for a failed
+// run of the schedule action that will not be retried.
+func (s *Scheduler) notifyFailed(sch config.Schedule, action string, run executor.RunReport) {
+	s.mu.Lock()
+	notifier := s.notifier
+	s.mu.Unlock()
+
+	eventType := notify.EventActionFailed
+	if run.Status() == executor.RunPartial {
+		eventType = notify.EventActionPartial
+	}
+	event := notify.NewEvent(eventType, nil)
+	event.Schedule = sch.Name
+	event.Action = action
+	event.SucceededResources = run.Succeeded()
+	event.FailedResources = run.Failed()
+	event.Description = sch.Description
+	event.RunbookURL = sch.RunbookURL
+	notify.SendEvent(notifier, event)
+}
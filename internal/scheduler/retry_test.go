@@ -0,0 +1,182 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/executor"
+	"github.com/sentoz/yc-sheduler/internal/notify"
+	"github.com/sentoz/yc-sheduler/internal/resource"
+)
+
+// flakyOperator fails the first failures starts.
+type flakyOperator struct {
+	testOperator
+	failures int32
+	starts   atomic.Int32
+}
+
+func (o *flakyOperator) Start(context.Context, config.Resource, resource.StartOptions) error {
+	if o.starts.Add(1) <= o.failures {
+		return errors.New("start failed")
+	}
+	return nil
+}
+
+func TestJob_RetriesFailedRun(t *testing.T) {
+	t.Parallel()
+
+	s, err := New("", 1)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = s.Start(ctx) }()
+
+	sch := makeSchedule("vm", "daily", true, false)
+	sch.Actions.Start.RetryFailed = &config.RetryConfig{
+		Retries: 2,
+		Backoff: config.Duration{Duration: 100 * time.Millisecond},
+	}
+	op := &flakyOperator{failures: 2}
+	s.job(testStateChecker{}, op, sch, "start", false, nil)()
+
+	deadline := time.Now().Add(3 * time.Second)
+	for op.starts.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if got := op.starts.Load(); got != 3 {
+		t.Fatalf("starts = %d, want the first run and 2 retries", got)
+	}
+	if runs := s.LastRuns(); len(runs) != 1 || !runs[0].OK {
+		t.Fatalf("LastRuns() = %+v, want the successful retry", runs)
+	}
+
+	// Retries run out after the configured number.
+	op = &flakyOperator{failures: 10}
+	s.job(testStateChecker{}, op, sch, "start", false, nil)()
+	time.Sleep(500 * time.Millisecond)
+	if got := op.starts.Load(); got != 3 {
+		t.Fatalf("starts = %d, want no more than 2 retries", got)
+	}
+}
+
+func TestJob_NotifiesFailedRun(t *testing.T) {
+	t.Parallel()
+
+	s, err := New("", 1)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	events := make(eventRecorder, 1)
+	s.SetNotifier(events)
+
+	sch := makeSchedule("vm", "daily", true, false)
+	sch.Description = "Staging VM for nightly tests"
+	sch.RunbookURL = "https://wiki.example.com/runbooks/staging-vm"
+	s.job(testStateChecker{}, &flakyOperator{failures: 1}, sch, "start", false, nil)()
+
+	select {
+	case event := <-events:
+		if event.Type != notify.EventActionFailed || event.Schedule != "vm" || event.Action != "start" ||
+			event.Description != sch.Description || event.RunbookURL != sch.RunbookURL {
+			t.Fatalf("event = %+v, want action_failed of vm start with its description and runbook", event)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("failed run was not notified")
+	}
+
+	// Runs failing for some resources only are notified as partial.
+	sch.Resources = []config.Resource{{Type: "vm", ID: "vm-1"}, {Type: "vm", ID: "vm-2"}}
+	s.job(testStateChecker{}, &flakyOperator{failures: 1}, sch, "start", false, nil)()
+	select {
+	case event := <-events:
+		if event.Type != notify.EventActionPartial || len(event.SucceededResources) != 1 || len(event.FailedResources) != 1 {
+			t.Fatalf("event = %+v, want action_partial with one succeeded and one failed resource", event)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("partial run was not notified")
+	}
+	if runs := s.LastRuns(); len(runs) != 1 || runs[0].OK || !runs[0].Partial {
+		t.Fatalf("LastRuns() = %+v, want a partial run", runs)
+	}
+
+	// Successful runs are not notified.
+	s.job(testStateChecker{}, &flakyOperator{}, sch, "start", false, nil)()
+	select {
+	case event := <-events:
+		t.Fatalf("event = %+v, want none for a successful run", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestJob_DropsRetryOfReplacedSchedule(t *testing.T) {
+	t.Parallel()
+
+	s, err := New("", 1)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = s.Start(ctx) }()
+
+	sch := makeSchedule("vm", "daily", true, false)
+	sch.Actions.Start.RetryFailed = &config.RetryConfig{
+		Retries: 2,
+		Backoff: config.Duration{Duration: 200 * time.Millisecond},
+	}
+	op := &flakyOperator{failures: 1}
+	s.job(testStateChecker{}, op, sch, "start", false, nil)()
+	if err := s.ReplaceSchedules(testStateChecker{}, testOperator{}, []config.Schedule{sch}, false, nil); err != nil {
+		t.Fatalf("ReplaceSchedules() error = %v", err)
+	}
+
+	time.Sleep(500 * time.Millisecond)
+	if got := op.starts.Load(); got != 1 {
+		t.Fatalf("starts = %d, want the retry of the replaced schedule dropped", got)
+	}
+}
+
+func TestJob_RetryWaitsForDependencies(t *testing.T) {
+	t.Parallel()
+
+	s, err := New("", 1)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = s.Start(ctx) }()
+
+	db := makeSchedule("db", "daily", true, false)
+	app := makeSchedule("app", "daily", true, false)
+	app.DependsOn = []string{"db"}
+	app.Actions.Start.RetryFailed = &config.RetryConfig{
+		Retries: 2,
+		Backoff: config.Duration{Duration: 100 * time.Millisecond},
+	}
+	// The database start due today failed after the first attempt of app.
+	s.deps.startedAt = time.Now().Add(-48 * time.Hour)
+	s.deps.setSchedules([]config.Schedule{db, app})
+
+	op := &flakyOperator{failures: 1}
+	s.mu.Lock()
+	fn := s.retrying(testStateChecker{}, op, app, "start", false, nil, func(run executor.RunReport) {
+		s.deps.recordRun(app.Name, "start", run)
+		s.deps.record(db.Name, "start", false)
+	})
+	s.mu.Unlock()
+	fn()
+
+	time.Sleep(500 * time.Millisecond)
+	if got := op.starts.Load(); got != 1 {
+		t.Fatalf("starts = %d, want the retry skipped after the dependency failed", got)
+	}
+}
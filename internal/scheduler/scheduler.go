@@ -3,18 +3,30 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-co-op/gocron/v2"
+	"github.com/google/uuid"
+	"github.com/jonboulle/clockwork"
 	"github.com/rs/zerolog/log"
 
+	"github.com/sentoz/yc-sheduler/internal/blackout"
 	"github.com/sentoz/yc-sheduler/internal/config"
 	"github.com/sentoz/yc-sheduler/internal/executor"
+	"github.com/sentoz/yc-sheduler/internal/grace"
 	"github.com/sentoz/yc-sheduler/internal/metrics"
+	"github.com/sentoz/yc-sheduler/internal/notify"
+	"github.com/sentoz/yc-sheduler/internal/pause"
 	"github.com/sentoz/yc-sheduler/internal/resource"
 	"github.com/sentoz/yc-sheduler/internal/schedule"
+	"github.com/sentoz/yc-sheduler/internal/vacation"
 )
 
 // Interface defines the interface for scheduler operations.
@@ -29,8 +41,52 @@ type Interface interface {
 // Scheduler wraps gocron.Scheduler and provides a higher-level API
 // tailored for yc-scheduler configuration.
 type Scheduler struct {
-	s  gocron.Scheduler
-	mu sync.Mutex
+	s         gocron.Scheduler
+	pauses    *pause.Registry
+	vacations *vacation.Manager
+	blackouts *blackout.Calendar
+	warmUp    *config.WarmUpConfig
+	stops     *grace.Registry
+	notifier  notify.Notifier
+	executor  *executor.Executor
+	deps      *dependencies
+	clock     clockwork.Clock
+	metrics   *metrics.Metrics
+	// generation changes whenever schedules are registered or replaced.
+	generation uint64
+	// jobs holds the recurring jobs, moved to the new gocron scheduler on
+	// Reconfigure.
+	jobs []recurringJob
+	// started is set once the gocron scheduler is started.
+	started bool
+	// oneTime holds the one-time jobs that have not completed yet.
+	oneTime   map[uuid.UUID]*oneTimeJob
+	mu        sync.Mutex
+	oneTimeMu sync.Mutex
+}
+
+// recurringJob is a registered recurring job.
+type recurringJob struct {
+	def  gocron.JobDefinition
+	name string
+	fn   func()
+	tags []string
+}
+
+// oneTimeJob is a registered one-time job. It runs at most once, even if it
+// was moved to a new gocron scheduler while the previous one started it.
+type oneTimeJob struct {
+	name    string
+	at      time.Time
+	fn      func()
+	claimed atomic.Bool
+}
+
+// run runs the job unless it has already run.
+func (j *oneTimeJob) run() {
+	if j.claimed.CompareAndSwap(false, true) {
+		j.fn()
+	}
 }
 
 const managedScheduleTag = "managed_schedule"
@@ -42,17 +98,53 @@ var _ Interface = (*Scheduler)(nil)
 // concurrency limit. If timezone is empty, the local system timezone is
 // used.
 func New(timezone string, maxConcurrentJobs int) (*Scheduler, error) {
-	location := time.Local
-	if timezone != "" {
-		loc, err := time.LoadLocation(timezone)
-		if err != nil {
-			return nil, fmt.Errorf("scheduler: load location %q: %w", timezone, err)
-		}
-		location = loc
+	return NewWithClock(timezone, maxConcurrentJobs, clockwork.NewRealClock())
+}
+
+// NewWithClock is like New and triggers jobs by the given clock, e.g. an
+// accelerated clock in soak tests.
+func NewWithClock(timezone string, maxConcurrentJobs int, clock clockwork.Clock) (*Scheduler, error) {
+	location, err := loadLocation(timezone)
+	if err != nil {
+		return nil, err
+	}
+	s, err := newGocron(location, maxConcurrentJobs, clock)
+	if err != nil {
+		return nil, err
 	}
 
+	log.Info().
+		Str("timezone", location.String()).
+		Int("max_concurrent_jobs", maxConcurrentJobs).
+		Msg("Scheduler initialized")
+
+	return &Scheduler{
+		s:        s,
+		executor: executor.New(),
+		deps:     newDependencies(location, clock),
+		clock:    clock,
+		oneTime:  make(map[uuid.UUID]*oneTimeJob),
+	}, nil
+}
+
+// loadLocation loads timezone, the local one if it is empty.
+func loadLocation(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return time.Local, nil
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("scheduler: load location %q: %w", timezone, err)
+	}
+	return location, nil
+}
+
+// newGocron creates a gocron scheduler in location running at most
+// maxConcurrentJobs jobs at once, any number if it is not positive.
+func newGocron(location *time.Location, maxConcurrentJobs int, clock clockwork.Clock) (gocron.Scheduler, error) {
 	opts := []gocron.SchedulerOption{
 		gocron.WithLocation(location),
+		gocron.WithClock(clock),
 	}
 	if maxConcurrentJobs > 0 {
 		opts = append(opts, gocron.WithLimitConcurrentJobs(uint(maxConcurrentJobs), gocron.LimitModeWait))
@@ -62,13 +154,69 @@ func New(timezone string, maxConcurrentJobs int) (*Scheduler, error) {
 	if err != nil {
 		return nil, fmt.Errorf("scheduler: new: %w", err)
 	}
+	return s, nil
+}
+
+// Reconfigure moves all jobs to a new gocron scheduler with the given
+// timezone and concurrency limit, as neither can be changed in place. Runs
+// in progress complete in the previous scheduler, and pending one-time jobs
+// still run once.
+func (s *Scheduler) Reconfigure(timezone string, maxConcurrentJobs int) error {
+	if s == nil || s.cron() == nil {
+		return fmt.Errorf("scheduler: not initialized")
+	}
+
+	location, err := loadLocation(timezone)
+	if err != nil {
+		return err
+	}
+	cron, err := newGocron(location, maxConcurrentJobs, s.clock)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	previous := s.s
+	s.s = cron
+	s.deps.setLocation(location)
+	for _, job := range s.jobs {
+		if _, err := cron.NewJob(job.def, gocron.NewTask(job.fn), gocron.WithName(job.name), gocron.WithTags(job.tags...)); err != nil {
+			log.Error().Err(err).
+				Str("job_name", job.name).
+				Msg("Failed to move job to the reconfigured scheduler")
+		}
+	}
+	s.oneTimeMu.Lock()
+	pending := s.oneTime
+	s.oneTime = make(map[uuid.UUID]*oneTimeJob, len(pending))
+	s.oneTimeMu.Unlock()
+	for _, job := range pending {
+		if job.claimed.Load() {
+			continue
+		}
+		if err := s.newOneTimeJobUnlocked(job); err != nil {
+			log.Error().Err(err).
+				Str("job_name", job.name).
+				Msg("Failed to move one-time job to the reconfigured scheduler")
+		}
+	}
+	if s.started {
+		cron.Start()
+	}
+	s.mu.Unlock()
+
+	// Runs in progress may need s.mu, so the previous scheduler is shut down
+	// without it.
+	if err := previous.Shutdown(); err != nil {
+		log.Warn().Err(err).Msg("Previous scheduler shutdown error")
+	}
 
 	log.Info().
 		Str("timezone", location.String()).
 		Int("max_concurrent_jobs", maxConcurrentJobs).
-		Msg("Scheduler initialized")
+		Msg("Scheduler reconfigured")
 
-	return &Scheduler{s: s}, nil
+	return nil
 }
 
 // AddJob registers a new job in the underlying scheduler with the given
@@ -78,7 +226,7 @@ func New(timezone string, maxConcurrentJobs int) (*Scheduler, error) {
 // The timezone parameter is ignored as gocron v2 doesn't support per-job timezones.
 // All jobs use the scheduler's timezone (set during initialization).
 func (s *Scheduler) AddJob(def gocron.JobDefinition, name string, fn func(), timezone string) error {
-	if s == nil || s.s == nil {
+	if s == nil || s.cron() == nil {
 		return fmt.Errorf("scheduler: not initialized")
 	}
 
@@ -90,11 +238,14 @@ func (s *Scheduler) AddJob(def gocron.JobDefinition, name string, fn func(), tim
 
 // Start starts the scheduler and blocks until the context is canceled.
 func (s *Scheduler) Start(ctx context.Context) error {
-	if s == nil || s.s == nil {
+	if s == nil || s.cron() == nil {
 		return fmt.Errorf("scheduler: not initialized")
 	}
 
+	s.mu.Lock()
 	s.s.Start()
+	s.started = true
+	s.mu.Unlock()
 
 	log.Info().Msg("Scheduler event loop started")
 
@@ -103,6 +254,7 @@ func (s *Scheduler) Start(ctx context.Context) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	s.started = false
 	if err := s.s.Shutdown(); err != nil {
 		log.Warn().Err(err).Msg("Scheduler shutdown error")
 	}
@@ -114,34 +266,38 @@ func (s *Scheduler) Start(ctx context.Context) error {
 
 // Stop stops the scheduler gracefully without waiting for the context.
 func (s *Scheduler) Stop() {
-	if s == nil || s.s == nil {
+	if s == nil || s.cron() == nil {
 		return
 	}
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.started = false
 	if err := s.s.Shutdown(); err != nil {
 		log.Warn().Err(err).Msg("Scheduler stop error")
 	}
 }
 
 // AddOneTimeJob adds a one-time job that will execute immediately.
-// The job function is a simple func() without parameters.
+// The job function is a simple func() without parameters. The job is removed
+// from the scheduler once it completes, so corrective jobs do not accumulate
+// over long uptimes.
 func (s *Scheduler) AddOneTimeJob(name string, fn func()) error {
-	if s == nil || s.s == nil {
+	if s == nil || s.cron() == nil {
 		return fmt.Errorf("scheduler: not initialized")
 	}
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	_, err := s.s.NewJob(
-		gocron.OneTimeJob(gocron.OneTimeJobStartImmediately()),
-		gocron.NewTask(fn),
-		gocron.WithName(name),
-	)
-	if err != nil {
-		return fmt.Errorf("scheduler: add one-time job %q: %w", name, err)
+	return s.addOneTimeJobUnlocked(name, time.Time{}, fn)
+}
+
+// addOneTimeJobUnlocked adds a one-time job starting at at, or immediately
+// if at is zero. It must be called with s.mu held.
+func (s *Scheduler) addOneTimeJobUnlocked(name string, at time.Time, fn func()) error {
+	if err := s.newOneTimeJobUnlocked(&oneTimeJob{name: name, at: at, fn: fn}); err != nil {
+		return err
 	}
 
 	log.Info().
@@ -151,17 +307,141 @@ func (s *Scheduler) AddOneTimeJob(name string, fn func()) error {
 	return nil
 }
 
+// newOneTimeJobUnlocked adds job to the gocron scheduler. It must be called
+// with s.mu held.
+func (s *Scheduler) newOneTimeJobUnlocked(job *oneTimeJob) error {
+	start := gocron.OneTimeJobStartImmediately()
+	if !job.at.IsZero() {
+		start = gocron.OneTimeJobStartDateTime(job.at)
+	}
+
+	// The listeners wait for s.oneTimeMu, so they see the job in s.oneTime
+	// even if it completes before NewJob returns.
+	s.oneTimeMu.Lock()
+	defer s.oneTimeMu.Unlock()
+	registered, err := s.s.NewJob(
+		gocron.OneTimeJob(start),
+		gocron.NewTask(job.run),
+		gocron.WithName(job.name),
+		gocron.WithEventListeners(
+			gocron.AfterJobRuns(func(id uuid.UUID, _ string) { s.completeOneTimeJob(id) }),
+			gocron.AfterJobRunsWithPanic(func(id uuid.UUID, _ string, _ any) { s.completeOneTimeJob(id) }),
+		),
+	)
+	if err != nil {
+		return fmt.Errorf("scheduler: add one-time job %q: %w", job.name, err)
+	}
+	s.oneTime[registered.ID()] = job
+	s.setOneTimeJobsGauge()
+	return nil
+}
+
+// completeOneTimeJob removes a completed one-time job from the scheduler.
+func (s *Scheduler) completeOneTimeJob(id uuid.UUID) {
+	s.oneTimeMu.Lock()
+	job, ok := s.oneTime[id]
+	delete(s.oneTime, id)
+	s.setOneTimeJobsGauge()
+	s.oneTimeMu.Unlock()
+	if !ok {
+		// The job was moved to a reconfigured scheduler.
+		return
+	}
+	name := job.name
+
+	// Removal waits for the scheduler loop, which may be waiting for this
+	// listener, so it runs in the background.
+	go func() {
+		if err := s.cron().RemoveJob(id); err != nil && !errors.Is(err, gocron.ErrJobNotFound) {
+			log.Warn().Err(err).
+				Str("job_name", name).
+				Msg("Failed to remove completed one-time job")
+			return
+		}
+		log.Debug().
+			Str("job_name", name).
+			Msg("Completed one-time job removed")
+	}()
+}
+
+// OutstandingOneTimeJobs returns the number of one-time jobs that have not
+// completed yet.
+func (s *Scheduler) OutstandingOneTimeJobs() int {
+	s.oneTimeMu.Lock()
+	defer s.oneTimeMu.Unlock()
+	return len(s.oneTime)
+}
+
+// Backlog returns the number of due jobs waiting for a free execution slot
+// because MaxConcurrentJobs jobs are already running.
+func (s *Scheduler) Backlog() int {
+	return s.cron().JobsWaitingInQueue()
+}
+
+// NextRuns returns the earliest next run of the jobs of every registered
+// schedule by schedule name. Schedules without a pending run are omitted.
+func (s *Scheduler) NextRuns() map[string]time.Time {
+	owner := make(map[string]string)
+	s.deps.mu.Lock()
+	for _, sch := range s.deps.schedules {
+		for name := range expectedJobs(sch) {
+			owner[name] = sch.Name
+		}
+	}
+	s.deps.mu.Unlock()
+
+	next := make(map[string]time.Time)
+	for _, job := range s.cron().Jobs() {
+		name, ok := owner[job.Name()]
+		if !ok || !slices.Contains(job.Tags(), managedScheduleTag) {
+			continue
+		}
+		at, err := job.NextRun()
+		if err != nil || at.IsZero() {
+			continue
+		}
+		if current, ok := next[name]; !ok || at.Before(current) {
+			next[name] = at
+		}
+	}
+	return next
+}
+
+// setOneTimeJobsGauge updates the outstanding one-time jobs gauge. It must be
+// called with s.oneTimeMu held.
+func (s *Scheduler) setOneTimeJobsGauge() {
+	if s.metrics != nil {
+		s.metrics.SetOneTimeJobs(len(s.oneTime))
+	}
+}
+
+// SetMetrics sets the metrics updated by the scheduler itself, such as the
+// outstanding one-time jobs gauge.
+func (s *Scheduler) SetMetrics(m *metrics.Metrics) {
+	s.oneTimeMu.Lock()
+	defer s.oneTimeMu.Unlock()
+
+	s.metrics = m
+	s.setOneTimeJobsGauge()
+}
+
 // RegisterSchedules registers all schedules from the configuration.
-// It iterates through all schedules and registers start/stop actions as jobs.
+// It iterates through all schedules and registers start/stop/snapshot/restart/scale actions as jobs.
 // If m is nil, metrics will not be recorded.
 func (s *Scheduler) RegisterSchedules(stateChecker resource.StateChecker, operator resource.Operator, cfg *config.Config, dryRun bool, m *metrics.Metrics) error {
-	if s == nil || s.s == nil {
+	if s == nil || s.cron() == nil {
 		return fmt.Errorf("scheduler: not initialized")
 	}
 
+	if err := validateDependencies(cfg.Schedules); err != nil {
+		return fmt.Errorf("scheduler: %w", err)
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	s.generation++
+	s.deps.setSchedules(cfg.Schedules)
 	for _, sch := range cfg.Schedules {
 		if err := registerScheduleUnlocked(s, stateChecker, operator, sch, dryRun, m); err != nil {
 			return err
@@ -173,14 +453,31 @@ func (s *Scheduler) RegisterSchedules(stateChecker resource.StateChecker, operat
 // ReplaceSchedules replaces all regular scheduled jobs with a new set from
 // manifests. In-flight jobs are not interrupted.
 func (s *Scheduler) ReplaceSchedules(stateChecker resource.StateChecker, operator resource.Operator, schedules []config.Schedule, dryRun bool, m *metrics.Metrics) error {
-	if s == nil || s.s == nil {
+	if s == nil || s.cron() == nil {
 		return fmt.Errorf("scheduler: not initialized")
 	}
 
+	if err := validateDependencies(schedules); err != nil {
+		return fmt.Errorf("scheduler: %w", err)
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.s.RemoveByTags(managedScheduleTag)
+	s.removeByTagUnlocked(managedScheduleTag)
+	s.generation++
+	for old, updated := range s.deps.setSchedules(schedules) {
+		s.stops.Rename(old, updated)
+		log.Info().
+			Str("schedule", updated).
+			Str("previous_name", old).
+			Msg("Schedule renamed, keeping its state")
+	}
+	names := make([]string, 0, len(schedules))
+	for _, sch := range schedules {
+		names = append(names, sch.Name)
+	}
+	s.stops.Retain(names)
 
 	for _, sch := range schedules {
 		if err := registerScheduleUnlocked(s, stateChecker, operator, sch, dryRun, m); err != nil {
@@ -196,13 +493,23 @@ func (s *Scheduler) ReplaceSchedules(stateChecker resource.StateChecker, operato
 }
 
 func registerScheduleUnlocked(s *Scheduler, stateChecker resource.StateChecker, operator resource.Operator, sch config.Schedule, dryRun bool, m *metrics.Metrics) error {
+	if seasonEnded(sch, s.clock.Now()) {
+		log.Info().
+			Str("schedule", sch.Name).
+			Str("active_until", sch.ActiveUntil.String()).
+			Msg("Schedule is no longer active, not registering it")
+		return nil
+	}
+	sch = withoutExpiredOneTimeActions(sch, s.clock.Now(), true)
+	s.recordMissedOnRegister(sch, m)
+
 	if sch.Actions.Start != nil && sch.Actions.Start.Enabled {
 		def, err := ScheduleToJobDefinition(sch, sch.Actions.Start)
 		if err != nil {
 			return fmt.Errorf("register schedule %q start action: %w", sch.Name, err)
 		}
 		name := sch.Name + ":start"
-		if err := s.addJobUnlocked(def, name, executor.Make(stateChecker, operator, sch, "start", dryRun, m)); err != nil {
+		if err := s.addJobUnlocked(def, name, s.job(stateChecker, operator, sch, "start", dryRun, m), scheduleTag(sch.Name)); err != nil {
 			return err
 		}
 	}
@@ -212,23 +519,201 @@ func registerScheduleUnlocked(s *Scheduler, stateChecker resource.StateChecker,
 			return fmt.Errorf("register schedule %q stop action: %w", sch.Name, err)
 		}
 		name := sch.Name + ":stop"
-		if err := s.addJobUnlocked(def, name, executor.Make(stateChecker, operator, sch, "stop", dryRun, m)); err != nil {
+		if err := s.addJobUnlocked(def, name, s.job(stateChecker, operator, sch, "stop", dryRun, m), scheduleTag(sch.Name)); err != nil {
+			return err
+		}
+		if gracePeriod(sch) > 0 {
+			if err := s.announceNextStopUnlocked(sch, s.clock.Now()); err != nil {
+				return fmt.Errorf("register schedule %q stop action: %w", sch.Name, err)
+			}
+		}
+	}
+	if sch.Actions.Snapshot != nil && sch.Actions.Snapshot.Enabled {
+		def, err := ScheduleToJobDefinition(sch, sch.Actions.Snapshot)
+		if err != nil {
+			return fmt.Errorf("register schedule %q snapshot action: %w", sch.Name, err)
+		}
+		name := sch.Name + ":snapshot"
+		if err := s.addJobUnlocked(def, name, s.job(stateChecker, operator, sch, "snapshot", dryRun, m), scheduleTag(sch.Name)); err != nil {
+			return err
+		}
+	}
+	if sch.Actions.Restart != nil && sch.Actions.Restart.Enabled {
+		def, err := ScheduleToJobDefinition(sch, sch.Actions.Restart)
+		if err != nil {
+			return fmt.Errorf("register schedule %q restart action: %w", sch.Name, err)
+		}
+		name := sch.Name + ":restart"
+		if err := s.addJobUnlocked(def, name, s.job(stateChecker, operator, sch, "restart", dryRun, m), scheduleTag(sch.Name)); err != nil {
+			return err
+		}
+	}
+	if sch.Actions.Resize != nil && sch.Actions.Resize.Enabled {
+		def, err := ScheduleToJobDefinition(sch, sch.Actions.Resize)
+		if err != nil {
+			return fmt.Errorf("register schedule %q resize action: %w", sch.Name, err)
+		}
+		name := sch.Name + ":resize"
+		if err := s.addJobUnlocked(def, name, s.job(stateChecker, operator, sch, "resize", dryRun, m), scheduleTag(sch.Name)); err != nil {
+			return err
+		}
+	}
+	for i, entry := range sch.Actions.Scale {
+		if !entry.Enabled {
+			continue
+		}
+		def, err := ScheduleToJobDefinition(sch, &entry)
+		if err != nil {
+			return fmt.Errorf("register schedule %q scale action %d: %w", sch.Name, i, err)
+		}
+		// Several scale entries of a schedule are named by their index.
+		name := sch.Name + ":scale"
+		if len(sch.Actions.Scale) > 1 {
+			name += ":" + strconv.Itoa(i)
+		}
+		scaled := sch.ForScaleEntry(i)
+		if err := s.addJobUnlocked(def, name, s.job(stateChecker, operator, scaled, "scale", dryRun, m), scheduleTag(sch.Name)); err != nil {
+			return err
+		}
+	}
+	for i, entry := range sch.Actions.Preemptible {
+		if !entry.Enabled {
+			continue
+		}
+		def, err := ScheduleToJobDefinition(sch, &entry)
+		if err != nil {
+			return fmt.Errorf("register schedule %q preemptible action %d: %w", sch.Name, i, err)
+		}
+		name := sch.Name + ":preemptible"
+		if len(sch.Actions.Preemptible) > 1 {
+			name += ":" + strconv.Itoa(i)
+		}
+		narrowed := sch.ForPreemptibleEntry(i)
+		if err := s.addJobUnlocked(def, name, s.job(stateChecker, operator, narrowed, "preemptible", dryRun, m), scheduleTag(sch.Name)); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	return s.deregisterAtSeasonEndUnlocked(sch)
+}
+
+// job returns the job function running action for the schedule. Runs are
+// delayed by the schedule jitter, skipped outside the schedule active period,
+// while the schedule is paused or a blackout window is active, and wait for
+// schedule dependencies. Starts due together with many others are staggered
+// across the warm-up window. Stops with a grace period are announced in
+// advance and may be postponed. Runs starting later than run_within after
+// their scheduled time are skipped as missed. Failed runs of actions with
+// retry_failed are retried after a backoff.
+func (s *Scheduler) job(stateChecker resource.StateChecker, operator resource.Operator, sch config.Schedule, action string, dryRun bool, m *metrics.Metrics) func() {
+	record := func(run executor.RunReport) { s.deps.recordRun(sch.Name, action, run) }
+	fn := s.retrying(stateChecker, operator, sch, action, dryRun, m, record)
+	fn = s.jittered(sch, action, s.seasonal(sch, action, m, s.pausable(sch, action, m, s.outsideBlackouts(sch, action, m, s.ordered(sch, action, m, fn)))))
+	switch action {
+	case "start":
+		fn = s.staggered(sch, fn)
+	case "stop":
+		fn = s.graced(sch, fn)
+	}
+	return s.punctual(sch, action, m, fn)
+}
+
+// SetExecutor sets the executor running scheduled actions. Sharing it with
+// the validator keeps their operations on a resource from overlapping.
+func (s *Scheduler) SetExecutor(e *executor.Executor) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.executor = e
+}
+
+// SetPauses sets the registry consulted before each scheduled run.
+// Runs of schedules matching an active pause are skipped.
+func (s *Scheduler) SetPauses(pauses *pause.Registry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pauses = pauses
 }
 
-func (s *Scheduler) addJobUnlocked(def gocron.JobDefinition, name string, fn func()) error {
+// SetVacations sets the namespace vacations consulted before each scheduled
+// run. Runs of schedules in a namespace on vacation are skipped.
+func (s *Scheduler) SetVacations(vacations *vacation.Manager) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.vacations = vacations
+}
+
+// pausable wraps a job function so it is skipped while the schedule is paused
+// or its namespace is on vacation.
+func (s *Scheduler) pausable(sch config.Schedule, action string, m *metrics.Metrics, fn func()) func() {
+	return func() {
+		s.mu.Lock()
+		pauses, vacations := s.pauses, s.vacations
+		s.mu.Unlock()
+
+		reason := ""
+		if p, paused := pauses.Paused(sch.Labels); paused {
+			reason = "paused"
+			log.Info().
+				Str("schedule", sch.Name).
+				Str("action", action).
+				Str("pause_id", p.ID).
+				Str("reason", p.Reason).
+				Msg("Schedule is paused, skipping run")
+		} else if v, onVacation := vacations.OnVacation(sch.Namespace); onVacation {
+			reason = "vacation"
+			log.Info().
+				Str("schedule", sch.Name).
+				Str("action", action).
+				Str("vacation_id", v.ID).
+				Str("reason", v.Reason).
+				Msg("Schedule namespace is on vacation, skipping run")
+		}
+		if reason == "" {
+			fn()
+			return
+		}
+
+		// Dependents of a skipped schedule are skipped as well.
+		s.deps.record(sch.Name, action, false)
+		if m != nil {
+			for _, target := range sch.Targets() {
+				m.IncOperation(target.Type, action, "skipped")
+				m.IncSchedulerSkip(target.Type, action, reason)
+			}
+		}
+	}
+}
+
+// removeByTagUnlocked removes the recurring jobs with tag. It must be called
+// with s.mu held.
+func (s *Scheduler) removeByTagUnlocked(tag string) {
+	s.s.RemoveByTags(tag)
+	s.jobs = slices.DeleteFunc(s.jobs, func(job recurringJob) bool {
+		return slices.Contains(job.tags, tag)
+	})
+}
+
+// cron returns the current gocron scheduler.
+func (s *Scheduler) cron() gocron.Scheduler {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.s
+}
+
+func (s *Scheduler) addJobUnlocked(def gocron.JobDefinition, name string, fn func(), tags ...string) error {
 	if s == nil || s.s == nil {
 		return fmt.Errorf("scheduler: not initialized")
 	}
 
-	_, err := s.s.NewJob(def, gocron.NewTask(fn), gocron.WithName(name), gocron.WithTags(managedScheduleTag))
+	tags = append([]string{managedScheduleTag}, tags...)
+	_, err := s.s.NewJob(def, gocron.NewTask(fn), gocron.WithName(name), gocron.WithTags(tags...))
 	if err != nil {
 		return fmt.Errorf("scheduler: add job %q: %w", name, err)
 	}
+	s.jobs = append(s.jobs, recurringJob{def: def, name: name, fn: fn, tags: tags})
 
 	log.Debug().
 		Str("job_name", name).
@@ -239,13 +724,15 @@ func (s *Scheduler) addJobUnlocked(def gocron.JobDefinition, name string, fn fun
 
 // ScheduleToJobDefinition converts a configuration schedule and action into a
 // gocron.JobDefinition. The action config contains the schedule-specific parameters.
+// Schedules with their own timezone are registered as cron jobs in it, since
+// other jobs follow the location of the scheduler.
 func ScheduleToJobDefinition(sch config.Schedule, action *config.ActionConfig) (gocron.JobDefinition, error) {
 	switch sch.Type {
 	case "cron":
 		if action.Crontab.String() == "" {
 			return nil, fmt.Errorf("scheduler: cron schedule %q missing crontab in action", sch.Name)
 		}
-		return gocron.CronJob(action.Crontab.String(), false), nil
+		return gocron.CronJob(schedule.Crontab(sch, action), false), nil
 	case "daily":
 		if action.Time == "" {
 			return nil, fmt.Errorf("scheduler: daily schedule %q missing time in action", sch.Name)
@@ -254,23 +741,46 @@ func ScheduleToJobDefinition(sch config.Schedule, action *config.ActionConfig) (
 		if err != nil {
 			return nil, fmt.Errorf("scheduler: daily schedule %q: %w", sch.Name, err)
 		}
+		if sch.Timezone != "" {
+			return zonedJob(sch, action, "*", "*"), nil
+		}
+		return gocron.DailyJob(1, at), nil
+	case "random_window":
+		if _, err := schedule.RandomWindow(action); err != nil {
+			return nil, fmt.Errorf("scheduler: random_window schedule %q: %w", sch.Name, err)
+		}
+		at, err := schedule.ParseTime(config.Time(action.Time))
+		if err != nil {
+			return nil, fmt.Errorf("scheduler: random_window schedule %q: %w", sch.Name, err)
+		}
+		// Runs are deferred within the window by jittered.
+		if sch.Timezone != "" {
+			return zonedJob(sch, action, "*", "*"), nil
+		}
 		return gocron.DailyJob(1, at), nil
 	case "weekly":
 		if action.Time == "" {
 			return nil, fmt.Errorf("scheduler: weekly schedule %q missing time in action", sch.Name)
 		}
-		if action.Day < 0 || action.Day > 6 {
-			return nil, fmt.Errorf("scheduler: weekly schedule %q missing or invalid day in action (got %d, expected 0-6)", sch.Name, action.Day)
-		}
 		at, err := schedule.ParseTime(config.Time(action.Time))
 		if err != nil {
 			return nil, fmt.Errorf("scheduler: weekly schedule %q: %w", sch.Name, err)
 		}
-		weekday, err := schedule.ParseWeekday(action.Day)
-		if err != nil {
-			return nil, fmt.Errorf("scheduler: weekly schedule %q: %w", sch.Name, err)
+		var weekdays []time.Weekday
+		for _, day := range action.WeekDays() {
+			if day < 0 || day > 6 {
+				return nil, fmt.Errorf("scheduler: weekly schedule %q missing or invalid day in action (got %d, expected 0-6)", sch.Name, day)
+			}
+			weekdays = append(weekdays, time.Weekday(day))
+		}
+		if sch.Timezone != "" {
+			days := make([]string, 0, len(weekdays))
+			for _, day := range weekdays {
+				days = append(days, strconv.Itoa(int(day)))
+			}
+			return zonedJob(sch, action, "*", strings.Join(days, ",")), nil
 		}
-		return gocron.WeeklyJob(1, weekday, at), nil
+		return gocron.WeeklyJob(1, gocron.NewWeekdays(weekdays[0], weekdays[1:]...), at), nil
 	case "monthly":
 		if action.Time == "" {
 			return nil, fmt.Errorf("scheduler: monthly schedule %q missing time in action", sch.Name)
@@ -286,8 +796,37 @@ func ScheduleToJobDefinition(sch config.Schedule, action *config.ActionConfig) (
 		if err != nil {
 			return nil, fmt.Errorf("scheduler: monthly schedule %q: %w", sch.Name, err)
 		}
+		if sch.Timezone != "" {
+			return zonedJob(sch, action, strconv.Itoa(day), "*"), nil
+		}
 		return gocron.MonthlyJob(1, gocron.NewDaysOfTheMonth(day), at), nil
+	case "one-time":
+		at, err := action.At.Time()
+		if err != nil {
+			return nil, fmt.Errorf("scheduler: one-time schedule %q invalid at %q: %w", sch.Name, action.At, err)
+		}
+		return gocron.OneTimeJob(gocron.OneTimeJobStartDateTime(at)), nil
+	case "duration":
+		offsets, err := schedule.IntervalOffsets(action)
+		if err != nil {
+			return nil, fmt.Errorf("scheduler: duration schedule %q: %w", sch.Name, err)
+		}
+		at := make([]gocron.AtTime, 0, len(offsets))
+		for _, offset := range offsets {
+			at = append(at, gocron.NewAtTime(uint(offset/time.Hour), uint(offset%time.Hour/time.Minute), uint(offset%time.Minute/time.Second)))
+		}
+		return gocron.DailyJob(1, gocron.NewAtTimes(at[0], at[1:]...)), nil
 	default:
 		return nil, fmt.Errorf("scheduler: unknown schedule type %q", sch.Type)
 	}
 }
+
+// zonedJob returns a cron job running the action at its time in the timezone
+// of the schedule on the days of month and week given as cron fields. The
+// time of the action must be valid.
+func zonedJob(sch config.Schedule, action *config.ActionConfig, dom, dow string) gocron.JobDefinition {
+	offset, _ := schedule.ParseTimeOfDay(action.Time)
+	crontab := fmt.Sprintf("CRON_TZ=%s %d %d %d %s * %s", sch.Timezone,
+		int(offset.Seconds())%60, int(offset.Minutes())%60, int(offset.Hours()), dom, dow)
+	return gocron.CronJob(crontab, true)
+}
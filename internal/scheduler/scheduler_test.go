@@ -2,7 +2,9 @@ package scheduler
 
 import (
 	"context"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/sentoz/yc-sheduler/internal/config"
 	"github.com/sentoz/yc-sheduler/internal/resource"
@@ -16,8 +18,21 @@ func (testStateChecker) GetState(context.Context, config.Resource) (string, bool
 
 type testOperator struct{}
 
-func (testOperator) Start(context.Context, config.Resource) error { return nil }
-func (testOperator) Stop(context.Context, config.Resource) error  { return nil }
+func (testOperator) Start(context.Context, config.Resource, resource.StartOptions) error {
+	return nil
+}
+func (testOperator) Stop(context.Context, config.Resource, resource.StopOptions) error {
+	return nil
+}
+func (testOperator) Snapshot(context.Context, config.Resource, int) error { return nil }
+func (testOperator) Restart(context.Context, config.Resource, resource.RestartOptions) error {
+	return nil
+}
+func (testOperator) Scale(context.Context, config.Resource, int) error { return nil }
+func (testOperator) Resize(context.Context, config.Resource, resource.ResizeOptions) error {
+	return nil
+}
+func (testOperator) SetPreemptible(context.Context, config.Resource, bool) error { return nil }
 
 func TestReplaceSchedules_ReplacesManagedJobsOnly(t *testing.T) {
 	t.Parallel()
@@ -42,7 +57,7 @@ func TestReplaceSchedules_ReplacesManagedJobsOnly(t *testing.T) {
 		t.Fatalf("AddOneTimeJob() error = %v", err)
 	}
 
-	if got := len(s.s.Jobs()); got != 2 {
+	if got := len(s.cron().Jobs()); got != 2 {
 		t.Fatalf("jobs before replace = %d, want 2", got)
 	}
 
@@ -51,7 +66,7 @@ func TestReplaceSchedules_ReplacesManagedJobsOnly(t *testing.T) {
 		t.Fatalf("ReplaceSchedules() error = %v", err)
 	}
 
-	jobs := s.s.Jobs()
+	jobs := s.cron().Jobs()
 	if len(jobs) != 2 {
 		t.Fatalf("jobs after replace = %d, want 2", len(jobs))
 	}
@@ -72,6 +87,36 @@ func TestReplaceSchedules_ReplacesManagedJobsOnly(t *testing.T) {
 	}
 }
 
+func TestRegisterSchedules_NamesScaleEntriesByIndex(t *testing.T) {
+	t.Parallel()
+
+	s, err := New("", 1)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	sch := makeSchedule("pool", "daily", false, false)
+	sch.Resource.Type = "k8s_node_group"
+	sch.Actions.Scale = []config.ActionConfig{
+		{Enabled: true, Time: "08:00", TargetSize: 5},
+		{Enabled: true, Time: "20:00", TargetSize: 1},
+	}
+
+	if err := s.RegisterSchedules(testStateChecker{}, testOperator{}, &config.Config{Schedules: []config.Schedule{sch}}, false, nil); err != nil {
+		t.Fatalf("RegisterSchedules() error = %v", err)
+	}
+
+	names := make(map[string]struct{})
+	for _, job := range s.cron().Jobs() {
+		names[job.Name()] = struct{}{}
+	}
+	for _, want := range []string{"pool:scale:0", "pool:scale:1"} {
+		if _, ok := names[want]; !ok {
+			t.Fatalf("job %q is missing, got %v", want, names)
+		}
+	}
+}
+
 func makeSchedule(name, kind string, withStart, withStop bool) config.Schedule {
 	sch := config.Schedule{
 		Name: name,
@@ -97,3 +142,88 @@ var (
 	_ resource.StateChecker = testStateChecker{}
 	_ resource.Operator     = testOperator{}
 )
+
+func TestAddOneTimeJob_RemovesCompletedJob(t *testing.T) {
+	t.Parallel()
+
+	s, err := New("", 1)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = s.Start(ctx) }()
+
+	ran := make(chan struct{})
+	if err := s.AddOneTimeJob("validator:vm-1", func() { close(ran) }); err != nil {
+		t.Fatalf("AddOneTimeJob() error = %v", err)
+	}
+	<-ran
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(s.cron().Jobs()) > 0 || s.OutstandingOneTimeJobs() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("jobs = %d, outstanding = %d after completion, want 0", len(s.cron().Jobs()), s.OutstandingOneTimeJobs())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestReconfigure_MovesJobs(t *testing.T) {
+	t.Parallel()
+
+	s, err := New("UTC", 1)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = s.Start(ctx) }()
+
+	sch := makeSchedule("vm", "daily", true, true)
+	if err := s.RegisterSchedules(testStateChecker{}, testOperator{}, &config.Config{Schedules: []config.Schedule{sch}}, false, nil); err != nil {
+		t.Fatalf("RegisterSchedules() error = %v", err)
+	}
+	var runs atomic.Int32
+	s.mu.Lock()
+	err = s.addOneTimeJobUnlocked("vm:start:retry", time.Now().Add(time.Hour), func() { runs.Add(1) })
+	s.mu.Unlock()
+	if err != nil {
+		t.Fatalf("addOneTimeJobUnlocked() error = %v", err)
+	}
+
+	if err := s.Reconfigure("Asia/Tokyo", 2); err != nil {
+		t.Fatalf("Reconfigure() error = %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	if got := len(s.cron().Jobs()); got != 3 {
+		t.Fatalf("jobs = %d after Reconfigure(), want 3", got)
+	}
+	if got := s.OutstandingOneTimeJobs(); got != 1 {
+		t.Fatalf("OutstandingOneTimeJobs() = %d, want 1", got)
+	}
+	if divergences := s.CheckConsistency(); len(divergences) != 0 {
+		t.Fatalf("CheckConsistency() = %+v, want jobs to run in the new timezone", divergences)
+	}
+	location, _ := time.LoadLocation("Asia/Tokyo")
+	if next := s.NextRuns()["vm"].In(location); next.Hour() != 9 && next.Hour() != 18 {
+		t.Fatalf("next run = %s, want 09:00 or 18:00 in Asia/Tokyo", next)
+	}
+}
+
+func TestReconfigure_RunsOneTimeJobOnce(t *testing.T) {
+	t.Parallel()
+
+	job := &oneTimeJob{name: "vm:stop:postponed"}
+	var runs atomic.Int32
+	job.fn = func() { runs.Add(1) }
+
+	// Both the previous and the new scheduler fire the moved job.
+	job.run()
+	job.run()
+
+	if got := runs.Load(); got != 1 {
+		t.Fatalf("runs = %d, want 1", got)
+	}
+}
@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/metrics"
+)
+
+// scheduleTag returns the tag of the regular jobs of a schedule.
+func scheduleTag(name string) string {
+	return "schedule:" + name
+}
+
+// seasonEnded reports whether the active period of the schedule ended at now.
+func seasonEnded(sch config.Schedule, now time.Time) bool {
+	_, until := sch.ActivePeriod()
+	return !until.IsZero() && !now.Before(until)
+}
+
+// seasonal wraps a job function so it is skipped outside the schedule active
+// period.
+func (s *Scheduler) seasonal(sch config.Schedule, action string, m *metrics.Metrics, fn func()) func() {
+	if sch.ActiveFrom == "" && sch.ActiveUntil == "" {
+		return fn
+	}
+
+	return func() {
+		if sch.ActiveAt(s.clock.Now()) {
+			fn()
+			return
+		}
+
+		log.Info().
+			Str("schedule", sch.Name).
+			Str("action", action).
+			Str("active_from", sch.ActiveFrom.String()).
+			Str("active_until", sch.ActiveUntil.String()).
+			Msg("Schedule is not active, skipping run")
+		s.deps.record(sch.Name, action, false)
+		if m != nil {
+			for _, target := range sch.Targets() {
+				m.IncOperation(target.Type, action, "skipped")
+				m.IncSchedulerSkip(target.Type, action, "inactive")
+			}
+		}
+	}
+}
+
+// deregisterAtSeasonEndUnlocked adds a one-time job removing the regular
+// jobs of the schedule when its active period ends. It must be called with
+// s.mu held.
+func (s *Scheduler) deregisterAtSeasonEndUnlocked(sch config.Schedule) error {
+	_, until := sch.ActivePeriod()
+	if until.IsZero() {
+		return nil
+	}
+
+	generation := s.generation
+	deregister := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		// Jobs of replaced schedules were already removed.
+		if s.generation != generation {
+			return
+		}
+		s.removeByTagUnlocked(scheduleTag(sch.Name))
+		log.Info().
+			Str("schedule", sch.Name).
+			Str("active_until", sch.ActiveUntil.String()).
+			Msg("Schedule is no longer active, jobs deregistered")
+	}
+	return s.addOneTimeJobUnlocked(sch.Name+":deregister", until, deregister)
+}
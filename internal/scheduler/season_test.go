@@ -0,0 +1,78 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+func TestRegisterSchedules_SkipsEndedSchedule(t *testing.T) {
+	t.Parallel()
+
+	s, err := New("", 1)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	sch := makeSchedule("load-test", "daily", true, true)
+	sch.ActiveUntil = config.RFC3339Time(time.Now().Add(-time.Hour).Format(time.RFC3339))
+	if err := s.RegisterSchedules(testStateChecker{}, testOperator{}, &config.Config{Schedules: []config.Schedule{sch}}, false, nil); err != nil {
+		t.Fatalf("RegisterSchedules() error = %v", err)
+	}
+	if got := len(s.cron().Jobs()); got != 0 {
+		t.Fatalf("jobs = %d, want 0 for a schedule past active_until", got)
+	}
+}
+
+func TestRegisterSchedules_DeregistersAtActiveUntil(t *testing.T) {
+	t.Parallel()
+
+	s, err := New("", 1)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = s.Start(ctx) }()
+
+	sch := makeSchedule("load-test", "daily", true, true)
+	sch.ActiveUntil = config.RFC3339Time(time.Now().Add(time.Second).Format(time.RFC3339))
+	other := makeSchedule("other", "daily", true, false)
+	if err := s.RegisterSchedules(testStateChecker{}, testOperator{}, &config.Config{Schedules: []config.Schedule{sch, other}}, false, nil); err != nil {
+		t.Fatalf("RegisterSchedules() error = %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var names []string
+		for _, job := range s.cron().Jobs() {
+			names = append(names, job.Name())
+		}
+		if len(names) == 1 && names[0] == "other:start" {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("jobs = %v, want only other:start after active_until", names)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestSeasonal_SkipsRunsBeforeActiveFrom(t *testing.T) {
+	t.Parallel()
+
+	s, err := New("", 1)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	sch := makeSchedule("load-test", "daily", true, false)
+	sch.ActiveFrom = config.RFC3339Time(time.Now().Add(time.Hour).Format(time.RFC3339))
+	ran := false
+	s.seasonal(sch, "start", nil, func() { ran = true })()
+	if ran {
+		t.Fatal("run before active_from was not skipped")
+	}
+}
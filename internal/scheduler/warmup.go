@@ -0,0 +1,134 @@
+package scheduler
+
+import (
+	"slices"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/schedule"
+)
+
+// SetWarmUp sets how starts of many resources due at the same time are
+// staggered. A nil cfg disables staggering.
+func (s *Scheduler) SetWarmUp(cfg *config.WarmUpConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.warmUp = cfg
+}
+
+// staggered wraps the start job function of a schedule so that, when enough
+// resources start at the same boundary, each schedule of the group is
+// deferred to its own slot of the warm-up window. Slots follow dependency
+// order, so dependencies start first.
+func (s *Scheduler) staggered(sch config.Schedule, fn func()) func() {
+	return func() {
+		s.mu.Lock()
+		warmUp := s.warmUp
+		s.mu.Unlock()
+		if warmUp == nil || warmUp.Window.Duration <= 0 {
+			fn()
+			return
+		}
+
+		offset := s.warmUpOffset(sch, warmUp, s.clock.Now())
+		if offset <= 0 {
+			fn()
+			return
+		}
+
+		s.mu.Lock()
+		err := s.addOneTimeJobUnlocked(sch.Name+":start:warmup", s.clock.Now().Add(offset), fn)
+		s.mu.Unlock()
+		if err != nil {
+			log.Error().Err(err).
+				Str("schedule", sch.Name).
+				Msg("Failed to defer staggered start, running it now")
+			fn()
+			return
+		}
+
+		log.Info().
+			Str("schedule", sch.Name).
+			Dur("offset", offset).
+			Msg("Start deferred by warm-up staggering")
+	}
+}
+
+// warmUpOffset returns the delay of the start of sch fired at now within the
+// warm-up window, or 0 if the starts due at the same boundary involve fewer
+// resources than the configured minimum.
+func (s *Scheduler) warmUpOffset(sch config.Schedule, warmUp *config.WarmUpConfig, now time.Time) time.Duration {
+	// Schedules fire at minute boundaries, so the boundary of this run is
+	// the only one within the last minute.
+	since := now.Add(-time.Minute)
+	location := s.deps.getLocation()
+	boundary, err := schedule.NextActionTime(sch, sch.Actions.Start, since, location)
+	if err != nil {
+		return 0
+	}
+
+	s.deps.mu.Lock()
+	var group []config.Schedule
+	for _, other := range s.deps.schedules {
+		if other.Actions.Start == nil || !other.Actions.Start.Enabled {
+			continue
+		}
+		at, err := schedule.NextActionTime(other, other.Actions.Start, since, location)
+		if err == nil && at.Equal(boundary) {
+			group = append(group, other)
+		}
+	}
+	s.deps.mu.Unlock()
+
+	resources := 0
+	for _, other := range group {
+		resources += len(other.Targets())
+	}
+	if resources < warmUp.EffectiveMinResources() {
+		return 0
+	}
+
+	order := warmUpOrder(group)
+	slot := slices.Index(order, sch.Name)
+	if slot < 0 {
+		return 0
+	}
+	return warmUp.Window.Duration * time.Duration(slot) / time.Duration(len(order))
+}
+
+// warmUpOrder returns the names of schedules ordered by name, with every
+// schedule placed after its dependencies within the group.
+func warmUpOrder(group []config.Schedule) []string {
+	byName := make(map[string]config.Schedule, len(group))
+	names := make([]string, 0, len(group))
+	for _, sch := range group {
+		byName[sch.Name] = sch
+		names = append(names, sch.Name)
+	}
+	slices.Sort(names)
+
+	order := make([]string, 0, len(names))
+	placed := make(map[string]bool, len(names))
+	// Dependencies are validated to be acyclic on registration.
+	var place func(name string)
+	place = func(name string) {
+		sch, ok := byName[name]
+		if !ok || placed[name] {
+			return
+		}
+		placed[name] = true
+		deps := slices.Clone(sch.DependsOn)
+		slices.Sort(deps)
+		for _, dep := range deps {
+			place(dep)
+		}
+		order = append(order, name)
+	}
+	for _, name := range names {
+		place(name)
+	}
+	return order
+}
@@ -0,0 +1,60 @@
+package scheduler
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+func TestWarmUpOffset_StaggersStartsInDependencyOrder(t *testing.T) {
+	t.Parallel()
+
+	s, err := New("", 1)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	// "app" depends on "db", so db gets the slot before app.
+	app := makeSchedule("app", "daily", true, false)
+	app.DependsOn = []string{"db"}
+	db := makeSchedule("db", "daily", true, false)
+	cache := makeSchedule("cache", "daily", true, false)
+	late := makeSchedule("late", "daily", true, false)
+	late.Actions.Start.Time = "10:00"
+	schedules := []config.Schedule{app, db, cache, late}
+	if err := s.RegisterSchedules(testStateChecker{}, testOperator{}, &config.Config{Schedules: schedules}, false, nil); err != nil {
+		t.Fatalf("RegisterSchedules() error = %v", err)
+	}
+
+	y, m, d := time.Now().Date()
+	fired := time.Date(y, m, d, 9, 0, 0, int(5*time.Millisecond), time.Local)
+	warmUp := &config.WarmUpConfig{Window: config.Duration{Duration: 30 * time.Minute}, MinResources: 3}
+
+	want := map[string]time.Duration{"db": 0, "app": 10 * time.Minute, "cache": 20 * time.Minute}
+	for _, sch := range []config.Schedule{app, db, cache} {
+		if got := s.warmUpOffset(sch, warmUp, fired); got != want[sch.Name] {
+			t.Fatalf("warmUpOffset(%s) = %v, want %v", sch.Name, got, want[sch.Name])
+		}
+	}
+
+	// Below the resource threshold starts are not staggered.
+	warmUp.MinResources = 4
+	if got := s.warmUpOffset(app, warmUp, fired); got != 0 {
+		t.Fatalf("warmUpOffset() below threshold = %v, want 0", got)
+	}
+}
+
+func TestWarmUpOrder(t *testing.T) {
+	t.Parallel()
+
+	group := []config.Schedule{
+		{Name: "c", DependsOn: []string{"b"}},
+		{Name: "a", DependsOn: []string{"c"}},
+		{Name: "b"},
+	}
+	if got, want := warmUpOrder(group), []string{"b", "c", "a"}; !slices.Equal(got, want) {
+		t.Fatalf("warmUpOrder() = %v, want %v", got, want)
+	}
+}
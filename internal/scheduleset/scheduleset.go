@@ -0,0 +1,110 @@
+// Package scheduleset holds immutable snapshots of the loaded schedules.
+// Readers iterate the current Set without locks while a reload publishes a
+// new Set atomically, so they never see a partially replaced schedule list.
+package scheduleset
+
+import (
+	"reflect"
+	"slices"
+	"sync/atomic"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+// Set is an immutable snapshot of schedules with the version it was
+// published with. Schedules share nested values with the loaded config, which
+// must not be modified once published.
+type Set struct {
+	// scheduleVersions holds the version of the set each schedule was last
+	// added or changed in.
+	scheduleVersions map[string]uint64
+	schedules        []config.Schedule
+	version          uint64
+}
+
+func newSet(prev *Set, version uint64, schedules []config.Schedule) *Set {
+	set := &Set{
+		scheduleVersions: make(map[string]uint64, len(schedules)),
+		schedules:        slices.Clone(schedules),
+		version:          version,
+	}
+	for _, sch := range set.schedules {
+		set.scheduleVersions[sch.Name] = version
+		if before, ok := prev.lookup(sch.Name); ok && reflect.DeepEqual(before, sch) {
+			set.scheduleVersions[sch.Name] = prev.scheduleVersions[sch.Name]
+		}
+	}
+	return set
+}
+
+func (s *Set) lookup(name string) (config.Schedule, bool) {
+	if s == nil {
+		return config.Schedule{}, false
+	}
+	i := slices.IndexFunc(s.schedules, func(sch config.Schedule) bool { return sch.Name == name })
+	if i < 0 {
+		return config.Schedule{}, false
+	}
+	return s.schedules[i], true
+}
+
+// Version returns the number of the set, increasing with every reload.
+func (s *Set) Version() uint64 {
+	if s == nil {
+		return 0
+	}
+	return s.version
+}
+
+// Schedules returns a copy of the schedules of the set.
+func (s *Set) Schedules() []config.Schedule {
+	if s == nil {
+		return nil
+	}
+	return slices.Clone(s.schedules)
+}
+
+// ScheduleVersion returns the version of the set the named schedule was last
+// added or changed in, or 0 if the set has no such schedule.
+func (s *Set) ScheduleVersion(name string) uint64 {
+	if s == nil {
+		return 0
+	}
+	return s.scheduleVersions[name]
+}
+
+// Len returns the number of schedules in the set.
+func (s *Set) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.schedules)
+}
+
+// Store publishes the current Set.
+type Store struct {
+	current atomic.Pointer[Set]
+}
+
+// NewStore creates a store with the initially loaded schedules as version 1.
+func NewStore(schedules []config.Schedule) *Store {
+	s := &Store{}
+	s.current.Store(newSet(nil, 1, schedules))
+	return s
+}
+
+// Load returns the current set.
+func (s *Store) Load() *Set {
+	return s.current.Load()
+}
+
+// Replace publishes schedules as the next version and returns the new set.
+func (s *Store) Replace(schedules []config.Schedule) *Set {
+	for {
+		prev := s.current.Load()
+		next := newSet(prev, prev.Version()+1, schedules)
+		if s.current.CompareAndSwap(prev, next) {
+			return next
+		}
+	}
+}
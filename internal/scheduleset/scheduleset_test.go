@@ -0,0 +1,91 @@
+package scheduleset
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+func TestStoreReplace(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore([]config.Schedule{{Name: "a"}})
+	first := store.Load()
+	if first.Version() != 1 || first.Len() != 1 {
+		t.Fatalf("initial set = version %d with %d schedules, want version 1 with 1", first.Version(), first.Len())
+	}
+
+	next := store.Replace([]config.Schedule{{Name: "b"}, {Name: "c"}})
+	if next.Version() != 2 || store.Load() != next {
+		t.Fatalf("Replace() = version %d, want the current set with version 2", next.Version())
+	}
+	if got := first.Schedules(); len(got) != 1 || got[0].Name != "a" {
+		t.Fatalf("previous set schedules = %+v, want it unchanged", got)
+	}
+
+	// Modifying returned schedules does not change the set.
+	schedules := next.Schedules()
+	schedules[0].Name = "changed"
+	if got := next.Schedules()[0].Name; got != "b" {
+		t.Fatalf("schedule name = %q after modifying a copy, want b", got)
+	}
+}
+
+func TestStoreScheduleVersions(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore([]config.Schedule{{Name: "kept", Type: "daily"}, {Name: "changed", Type: "daily"}, {Name: "removed"}})
+	set := store.Replace([]config.Schedule{{Name: "kept", Type: "daily"}, {Name: "changed", Type: "weekly"}, {Name: "added"}})
+
+	for name, want := range map[string]uint64{"kept": 1, "changed": 2, "added": 2, "removed": 0} {
+		if got := set.ScheduleVersion(name); got != want {
+			t.Errorf("ScheduleVersion(%q) = %d, want %d", name, got, want)
+		}
+	}
+}
+
+// TestStoreConcurrentReload is meant to run with -race.
+func TestStoreConcurrentReload(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(nil)
+	const reloads = 100
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := range reloads {
+			schedules := make([]config.Schedule, i%5+1)
+			for j := range schedules {
+				schedules[j].Name = strconv.Itoa(i)
+			}
+			store.Replace(schedules)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		var last uint64
+		for range reloads {
+			set := store.Load()
+			if set.Version() < last {
+				t.Errorf("version went back from %d to %d", last, set.Version())
+			}
+			last = set.Version()
+			// Every set holds schedules of a single reload.
+			schedules := set.Schedules()
+			for _, sch := range schedules {
+				if sch.Name != schedules[0].Name {
+					t.Errorf("set %d mixes schedules %q and %q", set.Version(), schedules[0].Name, sch.Name)
+				}
+			}
+		}
+	}()
+	wg.Wait()
+
+	if got := store.Load().Version(); got != reloads+1 {
+		t.Fatalf("version = %d after %d reloads, want %d", got, reloads, reloads+1)
+	}
+}
@@ -0,0 +1,149 @@
+// Package soak provides the developer soak-test mode: an accelerated clock
+// and a fake Yandex Cloud provider seeded with the scheduled resources.
+package soak
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+)
+
+// Clock is a clockwork.Clock running factor times faster than real time.
+// It starts at the current time; timers and tickers fire after their
+// duration in accelerated time.
+type Clock struct {
+	factor    time.Duration
+	realStart time.Time
+	start     time.Time
+}
+
+// Ensure Clock implements clockwork.Clock.
+var _ clockwork.Clock = (*Clock)(nil)
+
+// NewClock creates a clock running factor times faster than real time.
+func NewClock(factor int) *Clock {
+	now := time.Now()
+	return &Clock{
+		factor:    time.Duration(max(factor, 1)),
+		realStart: now,
+		start:     now,
+	}
+}
+
+// Now returns the accelerated current time.
+func (c *Clock) Now() time.Time {
+	return c.start.Add(time.Since(c.realStart) * c.factor)
+}
+
+// Since returns the accelerated time elapsed since t.
+func (c *Clock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// Until returns the accelerated duration until t.
+func (c *Clock) Until(t time.Time) time.Duration {
+	return t.Sub(c.Now())
+}
+
+// Sleep pauses the current goroutine for d of accelerated time.
+func (c *Clock) Sleep(d time.Duration) {
+	time.Sleep(c.real(d))
+}
+
+// After waits for d of accelerated time and sends the accelerated time.
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).Chan()
+}
+
+// NewTimer creates a timer firing after d of accelerated time.
+func (c *Clock) NewTimer(d time.Duration) clockwork.Timer {
+	t := &timer{clock: c, ch: make(chan time.Time, 1)}
+	t.timer = time.AfterFunc(c.real(d), t.fire)
+	return t
+}
+
+// AfterFunc calls f in its own goroutine after d of accelerated time.
+func (c *Clock) AfterFunc(d time.Duration, f func()) clockwork.Timer {
+	t := &timer{clock: c, fn: f}
+	t.timer = time.AfterFunc(c.real(d), t.fire)
+	return t
+}
+
+// NewTicker creates a ticker ticking every d of accelerated time.
+func (c *Clock) NewTicker(d time.Duration) clockwork.Ticker {
+	t := &ticker{clock: c, ticker: time.NewTicker(c.real(d)), ch: make(chan time.Time, 1), done: make(chan struct{})}
+	go t.run()
+	return t
+}
+
+// real converts an accelerated duration to real time, at least 1ns so that
+// timers and tickers accept it.
+func (c *Clock) real(d time.Duration) time.Duration {
+	return max(d/c.factor, time.Nanosecond)
+}
+
+type timer struct {
+	clock *Clock
+	timer *time.Timer
+	ch    chan time.Time
+	fn    func()
+}
+
+func (t *timer) fire() {
+	if t.fn != nil {
+		t.fn()
+		return
+	}
+	select {
+	case t.ch <- t.clock.Now():
+	default:
+	}
+}
+
+func (t *timer) Chan() <-chan time.Time {
+	return t.ch
+}
+
+func (t *timer) Reset(d time.Duration) bool {
+	return t.timer.Reset(t.clock.real(d))
+}
+
+func (t *timer) Stop() bool {
+	return t.timer.Stop()
+}
+
+type ticker struct {
+	clock    *Clock
+	ticker   *time.Ticker
+	ch       chan time.Time
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func (t *ticker) run() {
+	for {
+		select {
+		case <-t.done:
+			return
+		case <-t.ticker.C:
+			select {
+			case t.ch <- t.clock.Now():
+			default:
+			}
+		}
+	}
+}
+
+func (t *ticker) Chan() <-chan time.Time {
+	return t.ch
+}
+
+func (t *ticker) Reset(d time.Duration) {
+	t.ticker.Reset(t.clock.real(d))
+}
+
+func (t *ticker) Stop() {
+	t.ticker.Stop()
+	t.stopOnce.Do(func() { close(t.done) })
+}
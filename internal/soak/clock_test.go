@@ -0,0 +1,33 @@
+package soak
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClockAccelerates(t *testing.T) {
+	t.Parallel()
+
+	c := NewClock(3600)
+	start := c.Now()
+
+	fired := make(chan struct{})
+	c.AfterFunc(time.Hour, func() { close(fired) })
+	select {
+	case <-fired:
+	case <-time.After(2 * time.Second):
+		t.Fatal("AfterFunc(1h) did not fire within 2s at 3600x")
+	}
+
+	if elapsed := c.Since(start); elapsed < time.Hour {
+		t.Fatalf("Since(start) = %v, want at least 1h of accelerated time", elapsed)
+	}
+
+	ticker := c.NewTicker(time.Hour)
+	defer ticker.Stop()
+	select {
+	case <-ticker.Chan():
+	case <-time.After(2 * time.Second):
+		t.Fatal("ticker(1h) did not tick within 2s at 3600x")
+	}
+}
@@ -0,0 +1,53 @@
+package soak
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	computepb "github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
+	k8spb "github.com/yandex-cloud/go-genproto/yandex/cloud/k8s/v1"
+	"google.golang.org/grpc"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/ycstub"
+)
+
+// StartProvider starts a fake Yandex Cloud API holding the resources of the
+// schedules, initially stopped. Only resource types supported by the stub
+// server are added; others are logged and their operations fail. Interceptors
+// are passed to the stub server.
+func StartProvider(schedules []config.Schedule, interceptors ...grpc.UnaryServerInterceptor) (*ycstub.Server, error) {
+	stub, err := ycstub.Start(interceptors...)
+	if err != nil {
+		return nil, fmt.Errorf("soak: start fake provider: %w", err)
+	}
+
+	for _, sch := range schedules {
+		for _, target := range sch.Targets() {
+			switch {
+			case target.IsPattern():
+				log.Warn().
+					Str("schedule", sch.Name).
+					Str("name_pattern", target.NamePattern).
+					Msg("Fake provider does not support name patterns, resource not added")
+			case target.Type == "vm":
+				stub.AddInstance(target.FolderID, target.ID, computepb.Instance_STOPPED)
+			case target.Type == "k8s_cluster":
+				stub.AddCluster(target.FolderID, target.ID, k8spb.Cluster_STOPPED)
+			case target.Type == "k8s_node_group":
+				stub.AddNodeGroup("", target.ID, 1)
+			default:
+				log.Warn().
+					Str("schedule", sch.Name).
+					Str("resource_type", target.Type).
+					Str("resource_id", target.ID).
+					Msg("Fake provider does not support resource type, resource not added")
+			}
+		}
+	}
+
+	log.Info().
+		Str("addr", stub.Addr()).
+		Msg("Fake provider started")
+	return stub, nil
+}
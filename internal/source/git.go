@@ -0,0 +1,170 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultGitPollInterval = time.Minute
+	gitCommandTimeout      = 2 * time.Minute
+)
+
+// GitOptions describes a Git repository with schedule manifests.
+type GitOptions struct {
+	// URL is the repository URL in any form accepted by git clone.
+	URL string
+	// Branch is checked out instead of the default branch when set.
+	Branch string
+	// Path is the slash-separated directory of the manifests within the
+	// repository, the repository root when empty.
+	Path string
+	// DeployKeyFile is a private SSH key used for SSH repository URLs.
+	DeployKeyFile string
+	// KnownHostsFile verifies SSH host keys when set. Otherwise the host key
+	// is trusted on first use.
+	KnownHostsFile string
+	// PollInterval is the minimal interval between pulls, a minute when not
+	// positive.
+	PollInterval time.Duration
+}
+
+// gitSource keeps a shallow clone of a branch of a repository. It uses the
+// git command, which must be installed.
+type gitSource struct {
+	opts     GitOptions
+	base     string
+	lastPull time.Time
+	commit   string
+	now      func() time.Time
+}
+
+// NewGit creates a source pulling opts.Path of a Git repository into a new
+// temporary directory.
+func NewGit(opts GitOptions) (Source, error) {
+	if opts.URL == "" {
+		return nil, fmt.Errorf("schedules git source: empty repository URL")
+	}
+	if opts.Path != "" && !filepath.IsLocal(filepath.FromSlash(opts.Path)) {
+		return nil, fmt.Errorf("schedules git source: path %q must be relative to the repository root", opts.Path)
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultGitPollInterval
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("schedules git source: %w", err)
+	}
+
+	base, err := os.MkdirTemp("", "yc-scheduler-git-")
+	if err != nil {
+		return nil, fmt.Errorf("create schedules source directory: %w", err)
+	}
+	return &gitSource{opts: opts, base: base, now: time.Now}, nil
+}
+
+// Dir returns the manifests directory of the checkout.
+func (g *gitSource) Dir() string {
+	return filepath.Join(g.checkout(), filepath.FromSlash(g.opts.Path))
+}
+
+func (g *gitSource) checkout() string {
+	return filepath.Join(g.base, "repo")
+}
+
+// Sync clones the repository on the first call and later pulls the branch
+// when the poll interval has passed since the previous pull.
+func (g *gitSource) Sync(ctx context.Context) error {
+	if !g.lastPull.IsZero() && g.now().Sub(g.lastPull) < g.opts.PollInterval {
+		return nil
+	}
+	// Failed pulls are retried after the poll interval too, so an unavailable
+	// remote is not queried on every reload check.
+	g.lastPull = g.now()
+
+	if g.commit == "" {
+		args := []string{"clone", "--quiet", "--depth", "1", "--single-branch"}
+		if g.opts.Branch != "" {
+			args = append(args, "--branch", g.opts.Branch)
+		}
+		if _, err := g.git(ctx, append(args, "--", g.opts.URL, g.checkout())...); err != nil {
+			return err
+		}
+	} else {
+		ref := "HEAD"
+		if g.opts.Branch != "" {
+			ref = g.opts.Branch
+		}
+		if _, err := g.git(ctx, "-C", g.checkout(), "fetch", "--quiet", "--depth", "1", "origin", ref); err != nil {
+			return err
+		}
+		if _, err := g.git(ctx, "-C", g.checkout(), "reset", "--quiet", "--hard", "FETCH_HEAD"); err != nil {
+			return err
+		}
+	}
+
+	commit, err := g.git(ctx, "-C", g.checkout(), "rev-parse", "HEAD")
+	if err != nil {
+		return err
+	}
+	if commit != g.commit {
+		log.Info().
+			Str("repository", g.opts.URL).
+			Str("branch", g.opts.Branch).
+			Str("commit", commit).
+			Msg("Pulled schedules from git repository")
+		g.commit = commit
+	}
+
+	if info, err := os.Stat(g.Dir()); err != nil || !info.IsDir() {
+		return fmt.Errorf("schedules git source: path %q is not a directory of the repository", g.opts.Path)
+	}
+	return nil
+}
+
+// git runs a git command and returns its trimmed output. Arguments are not
+// included in errors, since the URL may contain credentials.
+func (g *gitSource) git(ctx context.Context, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, gitCommandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if g.opts.DeployKeyFile != "" {
+		cmd.Env = append(cmd.Env, "GIT_SSH_COMMAND="+g.sshCommand())
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		name := args[0]
+		if name == "-C" {
+			name = args[2]
+		}
+		return "", fmt.Errorf("git %s: %w: %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// sshCommand returns the ssh command authenticating with the deploy key.
+func (g *gitSource) sshCommand() string {
+	knownHosts := "-o UserKnownHostsFile=" + shellQuote(filepath.Join(g.base, "known_hosts")) + " -o StrictHostKeyChecking=accept-new"
+	if g.opts.KnownHostsFile != "" {
+		knownHosts = "-o UserKnownHostsFile=" + shellQuote(g.opts.KnownHostsFile) + " -o StrictHostKeyChecking=yes"
+	}
+	return "ssh -i " + shellQuote(g.opts.DeployKeyFile) + " -o IdentitiesOnly=yes -o BatchMode=yes " + knownHosts
+}
+
+// shellQuote quotes s for a POSIX shell, which git uses to run
+// GIT_SSH_COMMAND.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,73 @@
+package source
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGitSync(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	remote := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", remote, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	commit := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(remote, "schedules", name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		run("add", "-A")
+		run("commit", "--quiet", "-m", "update "+name)
+	}
+	run("init", "--quiet", "--initial-branch=main")
+	commit("vm.yaml", "vm")
+
+	src, err := NewGit(GitOptions{URL: "file://" + remote, Branch: "main", Path: "schedules", PollInterval: time.Hour})
+	if err != nil {
+		t.Fatalf("NewGit() error = %v", err)
+	}
+	git := src.(*gitSource)
+	now := time.Now()
+	git.now = func() time.Time { return now }
+
+	if err := src.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	assertFile(t, filepath.Join(src.Dir(), "vm.yaml"), "vm")
+
+	commit("vm.yaml", "vm-2")
+	if err := src.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	assertFile(t, filepath.Join(src.Dir(), "vm.yaml"), "vm")
+
+	now = now.Add(time.Hour)
+	if err := src.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	assertFile(t, filepath.Join(src.Dir(), "vm.yaml"), "vm-2")
+}
+
+func TestNewGitRejectsPathOutsideRepository(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewGit(GitOptions{URL: "https://example.com/repo.git", Path: "../etc"}); err == nil {
+		t.Fatal("NewGit() error = nil, want error for a path outside the repository")
+	}
+}
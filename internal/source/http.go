@@ -0,0 +1,143 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	httpRequestTimeout = 30 * time.Second
+	// httpManifestName is the name of the mirrored copy of the manifests
+	// served at a URL.
+	httpManifestName = "schedules.yaml"
+)
+
+// IsURL reports whether path is an HTTP(S) URL rather than a file path.
+func IsURL(path string) bool {
+	u, err := url.Parse(path)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// Fetch returns the document served at rawURL, e.g. a config file published
+// by a config service. Credentials in the URL are sent with basic auth.
+func Fetch(ctx context.Context, rawURL string) ([]byte, error) {
+	client := &http.Client{Timeout: httpRequestTimeout}
+	data, _, _, err := fetch(ctx, client, rawURL, "")
+	return data, err
+}
+
+// httpSource mirrors the manifests served at a URL as a single multi-document
+// file. The document is downloaded again only when its ETag changes.
+type httpSource struct {
+	url    string
+	client *http.Client
+	mirror mirror
+	etag   string
+}
+
+// NewHTTP creates a source fetching the manifests served at rawURL into a new
+// temporary directory.
+func NewHTTP(rawURL string) (Source, error) {
+	if !IsURL(rawURL) {
+		return nil, fmt.Errorf("unsupported schedules URL %q: expected http:// or https://", rawURL)
+	}
+	dir, err := os.MkdirTemp("", "yc-scheduler-http-")
+	if err != nil {
+		return nil, fmt.Errorf("create schedules source directory: %w", err)
+	}
+	return newHTTP(rawURL, dir), nil
+}
+
+func newHTTP(rawURL, dir string) *httpSource {
+	return &httpSource{
+		url:    rawURL,
+		client: &http.Client{Timeout: httpRequestTimeout},
+		mirror: mirror{dir: dir},
+	}
+}
+
+// Dir returns the directory the manifests are mirrored to.
+func (h *httpSource) Dir() string {
+	return h.mirror.dir
+}
+
+// Sync requests the manifests with the ETag of the mirrored copy, so an
+// unchanged document is neither transferred nor rewritten.
+func (h *httpSource) Sync(ctx context.Context) error {
+	etag := h.etag
+	if !h.mirror.exists(httpManifestName) {
+		etag = ""
+	}
+
+	data, newETag, modified, err := fetch(ctx, h.client, h.url, etag)
+	if err != nil {
+		return err
+	}
+	if !modified {
+		return nil
+	}
+	if err := h.mirror.write(httpManifestName, data); err != nil {
+		return err
+	}
+	h.etag = newETag
+	log.Debug().
+		Str("url", Redact(h.url)).
+		Str("etag", newETag).
+		Msg("Downloaded schedule manifests from URL")
+	return nil
+}
+
+// fetch sends a GET request for rawURL, conditional when etag is not empty,
+// and returns the body and the ETag of the response. It reports false when
+// the server responds that the document has not changed.
+func fetch(ctx context.Context, client *http.Client, rawURL, etag string) ([]byte, string, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("get %s: %w", Redact(rawURL), err)
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// The error of the client includes the URL, which may contain
+		// credentials.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, "", false, fmt.Errorf("get %s: %w", Redact(rawURL), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && etag != "" {
+		return nil, etag, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", false, fmt.Errorf("get %s: unexpected status %s", Redact(rawURL), resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("get %s: read response: %w", Redact(rawURL), err)
+	}
+	return body, resp.Header.Get("ETag"), true, nil
+}
+
+// Redact returns path with the password of an HTTP(S) URL removed, for logs
+// and errors. Other paths are returned as they are.
+func Redact(path string) string {
+	if !IsURL(path) {
+		return path
+	}
+	u, _ := url.Parse(path)
+	return u.Redacted()
+}
@@ -0,0 +1,74 @@
+package source
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestHTTPSync(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		content  = "vm"
+		etag     = `"1"`
+		served   int
+		modified int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if user, pass, _ := r.BasicAuth(); user != "user" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		served++
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		modified++
+		w.Header().Set("ETag", etag)
+		_, _ = w.Write([]byte(content))
+	}))
+	defer srv.Close()
+
+	src := newHTTP(strings.Replace(srv.URL, "http://", "http://user:secret@", 1), t.TempDir())
+	for range 2 {
+		if err := src.Sync(context.Background()); err != nil {
+			t.Fatalf("Sync() error = %v", err)
+		}
+	}
+	assertFile(t, filepath.Join(src.Dir(), httpManifestName), "vm")
+	if served != 2 || modified != 1 {
+		t.Fatalf("served %d requests with %d bodies, want 2 with 1", served, modified)
+	}
+
+	mu.Lock()
+	content, etag = "vm-2", `"2"`
+	mu.Unlock()
+	if err := src.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	assertFile(t, filepath.Join(src.Dir(), httpManifestName), "vm-2")
+}
+
+func TestFetchRedactsPassword(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	_, err := Fetch(context.Background(), strings.Replace(srv.URL, "http://", "http://user:secret@", 1)+"/config.yaml")
+	if err == nil {
+		t.Fatal("Fetch() error = nil, want not found")
+	}
+	if strings.Contains(err.Error(), "secret") {
+		t.Fatalf("Fetch() error = %q, want password redacted", err)
+	}
+}
@@ -0,0 +1,643 @@
+package source
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/sentoz/yc-sheduler/internal/logger"
+)
+
+const (
+	// serviceAccountDir holds the credentials of the pod service account.
+	serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+	kubeRequestTimeout = 30 * time.Second
+	// kubeWatchTimeout makes the API server end a watch, which is then
+	// resumed from the last seen resource version.
+	kubeWatchTimeout = 5 * time.Minute
+	// kubeRetryDelay is the delay before a failed list or watch is retried.
+	kubeRetryDelay = 5 * time.Second
+)
+
+// Kinds of Kubernetes objects holding schedule manifests.
+const (
+	KindConfigMap = "ConfigMap"
+	KindSecret    = "Secret"
+	// KindSchedule selects custom resources of the Schedule CRD, which are
+	// schedule manifests themselves.
+	KindSchedule = "Schedule"
+)
+
+// lastAppliedAnnotation is set by kubectl apply to the whole applied object
+// and is not passed to schedules.
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// scheduleFinalizer keeps deleted Schedule objects until the jobs of their
+// schedules are removed.
+const scheduleFinalizer = "scheduler.yc/schedule"
+
+// Watcher is a source that pushes changes instead of being polled.
+type Watcher interface {
+	Source
+	// Watch keeps the local directory up to date until ctx is canceled and
+	// calls changed after every change of it.
+	Watch(ctx context.Context, changed func())
+}
+
+// StatusWriter is a watched source of custom resources with a status
+// subresource, e.g. to report schedule runs back to Schedule objects.
+type StatusWriter interface {
+	Watcher
+	// Objects returns the names of the mirrored objects.
+	Objects() []string
+	// WriteStatus replaces the status of the named object.
+	WriteStatus(ctx context.Context, name string, status any) error
+	// Deleting returns the names of the objects being deleted. They are no
+	// longer mirrored and are kept until their finalizer is removed.
+	Deleting() []string
+	// AddFinalizer adds the finalizer of the scheduler to the named object,
+	// so its deletion waits for RemoveFinalizer.
+	AddFinalizer(ctx context.Context, name string) error
+	// RemoveFinalizer removes the finalizer of the scheduler from the named
+	// object.
+	RemoveFinalizer(ctx context.Context, name string) error
+}
+
+// KubernetesOptions selects Kubernetes objects with schedule manifests.
+type KubernetesOptions struct {
+	// Kind is KindConfigMap, KindSecret or KindSchedule, KindConfigMap when
+	// empty.
+	Kind string
+	// Namespace of the objects, the namespace of the pod when empty.
+	Namespace string
+	// LabelSelector selects the objects, e.g. "yc-scheduler/schedules=true".
+	// It is required for ConfigMaps and Secrets.
+	LabelSelector string
+}
+
+// kubeSource mirrors the manifest keys of the selected ConfigMaps and Secrets
+// as <object>_<key> files, and Schedule objects as <object>.yaml. Object
+// names cannot contain underscores, so the file names never collide.
+type kubeSource struct {
+	opts      KubernetesOptions
+	api       *url.URL
+	tokenFile string
+	client    *http.Client
+	watch     *http.Client
+	mirror    mirror
+
+	mu              sync.Mutex
+	files           map[string][]string
+	meta            map[string]kubeMetadata
+	resourceVersion string
+}
+
+// NewKubernetes creates a source listing and then watching the selected
+// objects through the API server the pod runs with, mirrored to a new
+// temporary directory. The source of Schedule objects is a StatusWriter.
+func NewKubernetes(opts KubernetesOptions) (Watcher, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, fmt.Errorf("schedules kubernetes source: not running in a Kubernetes cluster")
+	}
+	if opts.Namespace == "" {
+		namespace, err := os.ReadFile(filepath.Join(serviceAccountDir, "namespace"))
+		if err != nil {
+			return nil, fmt.Errorf("schedules kubernetes source: read pod namespace: %w", err)
+		}
+		opts.Namespace = strings.TrimSpace(string(namespace))
+	}
+
+	ca, err := os.ReadFile(filepath.Join(serviceAccountDir, "ca.crt"))
+	if err != nil {
+		return nil, fmt.Errorf("schedules kubernetes source: read cluster CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("schedules kubernetes source: no certificates in cluster CA")
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+
+	dir, err := os.MkdirTemp("", "yc-scheduler-kubernetes-")
+	if err != nil {
+		return nil, fmt.Errorf("create schedules source directory: %w", err)
+	}
+	api := &url.URL{Scheme: "https", Host: net.JoinHostPort(host, port)}
+	k, err := newKubernetes(opts, api, filepath.Join(serviceAccountDir, "token"), transport, dir)
+	if err != nil {
+		return nil, err
+	}
+	if k.opts.Kind == KindSchedule {
+		return &scheduleSource{kubeSource: k}, nil
+	}
+	return k, nil
+}
+
+func newKubernetes(opts KubernetesOptions, api *url.URL, tokenFile string, transport http.RoundTripper, dir string) (*kubeSource, error) {
+	opts.Kind = cmp.Or(opts.Kind, KindConfigMap)
+	if opts.Kind != KindConfigMap && opts.Kind != KindSecret && opts.Kind != KindSchedule {
+		return nil, fmt.Errorf("schedules kubernetes source: unsupported kind %q", opts.Kind)
+	}
+	if opts.LabelSelector == "" && opts.Kind != KindSchedule {
+		return nil, fmt.Errorf("schedules kubernetes source: empty label selector")
+	}
+	return &kubeSource{
+		opts:      opts,
+		api:       api,
+		tokenFile: tokenFile,
+		client:    &http.Client{Transport: transport, Timeout: kubeRequestTimeout},
+		watch:     &http.Client{Transport: transport},
+		mirror:    mirror{dir: dir},
+		files:     make(map[string][]string),
+		meta:      make(map[string]kubeMetadata),
+	}, nil
+}
+
+// Dir returns the directory the manifests are mirrored to.
+func (k *kubeSource) Dir() string {
+	return k.mirror.dir
+}
+
+// Sync lists the objects until the first list succeeds. Later changes are
+// applied by Watch.
+func (k *kubeSource) Sync(ctx context.Context) error {
+	k.mu.Lock()
+	listed := k.resourceVersion != ""
+	k.mu.Unlock()
+	if listed {
+		return nil
+	}
+	return k.list(ctx)
+}
+
+// Watch follows changes of the objects, listing them again when the watch
+// cannot be resumed.
+func (k *kubeSource) Watch(ctx context.Context, changed func()) {
+	log.Info().
+		Str("kind", k.opts.Kind).
+		Str("namespace", k.opts.Namespace).
+		Str("label_selector", k.opts.LabelSelector).
+		Msg("Watching Kubernetes objects for schedules")
+
+	for ctx.Err() == nil {
+		k.mu.Lock()
+		resourceVersion := k.resourceVersion
+		k.mu.Unlock()
+
+		var err error
+		if resourceVersion == "" {
+			if err = k.list(ctx); err == nil {
+				changed()
+			}
+		} else {
+			err = k.watchFrom(ctx, resourceVersion, changed)
+		}
+		if err == nil || ctx.Err() != nil {
+			continue
+		}
+
+		logger.Sampled("kubernetes-source").Warn().Err(err).
+			Str("kind", k.opts.Kind).
+			Str("namespace", k.opts.Namespace).
+			Msg("Failed to watch Kubernetes objects for schedules, retrying")
+		select {
+		case <-ctx.Done():
+		case <-time.After(kubeRetryDelay):
+		}
+	}
+}
+
+type kubeMetadata struct {
+	Name              string            `json:"name"`
+	Namespace         string            `json:"namespace,omitempty"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Annotations       map[string]string `json:"annotations,omitempty"`
+	Finalizers        []string          `json:"finalizers,omitempty"`
+	ResourceVersion   string            `json:"resourceVersion,omitempty"`
+	DeletionTimestamp string            `json:"deletionTimestamp,omitempty"`
+}
+
+type kubeObject struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Metadata   kubeMetadata      `json:"metadata"`
+	Data       map[string]string `json:"data,omitempty"`
+	Spec       json.RawMessage   `json:"spec,omitempty"`
+}
+
+type kubeList struct {
+	Metadata struct {
+		ResourceVersion string `json:"resourceVersion"`
+	} `json:"metadata"`
+	Items []kubeObject `json:"items"`
+}
+
+type kubeEvent struct {
+	Type   string          `json:"type"`
+	Object json.RawMessage `json:"object"`
+}
+
+type kubeStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// errWatchExpired reports that the watched resource version is too old and
+// the objects must be listed again.
+var errWatchExpired = errors.New("watch expired")
+
+// list replaces the mirror with the current objects.
+func (k *kubeSource) list(ctx context.Context) error {
+	resp, err := k.get(ctx, k.client, url.Values{})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var list kubeList
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return fmt.Errorf("list %s: decode response: %w", k.resource(), err)
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	files := make(map[string][]string, len(list.Items))
+	meta := make(map[string]kubeMetadata, len(list.Items))
+	for _, obj := range list.Items {
+		meta[obj.Metadata.Name] = obj.Metadata
+		// Objects being deleted are only waiting for their finalizers.
+		if obj.Metadata.DeletionTimestamp != "" {
+			continue
+		}
+		names, err := k.writeUnlocked(obj)
+		if err != nil {
+			return err
+		}
+		files[obj.Metadata.Name] = names
+	}
+	k.files = files
+	k.meta = meta
+	if err := k.pruneUnlocked(); err != nil {
+		return err
+	}
+	k.resourceVersion = list.Metadata.ResourceVersion
+	return nil
+}
+
+// watchFrom applies watch events after resourceVersion until the API server
+// ends the watch.
+func (k *kubeSource) watchFrom(ctx context.Context, resourceVersion string, changed func()) error {
+	resp, err := k.get(ctx, k.watch, url.Values{
+		"watch":               {"true"},
+		"resourceVersion":     {resourceVersion},
+		"allowWatchBookmarks": {"true"},
+		"timeoutSeconds":      {fmt.Sprint(int(kubeWatchTimeout.Seconds()))},
+	})
+	if errors.Is(err, errWatchExpired) {
+		k.expire()
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var event kubeEvent
+		if err := decoder.Decode(&event); err != nil {
+			if errors.Is(err, io.EOF) || ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("watch %s: decode event: %w", k.resource(), err)
+		}
+
+		applied, err := k.apply(event)
+		if errors.Is(err, errWatchExpired) {
+			k.expire()
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if applied {
+			changed()
+		}
+	}
+}
+
+// expire makes Watch list the objects again.
+func (k *kubeSource) expire() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.resourceVersion = ""
+}
+
+// apply updates the mirror with a watch event and reports whether the
+// manifests changed.
+func (k *kubeSource) apply(event kubeEvent) (bool, error) {
+	if event.Type == "ERROR" {
+		var status kubeStatus
+		_ = json.Unmarshal(event.Object, &status)
+		if status.Code == http.StatusGone {
+			return false, errWatchExpired
+		}
+		return false, fmt.Errorf("watch %s: %d %s", k.resource(), status.Code, status.Message)
+	}
+
+	var obj kubeObject
+	if err := json.Unmarshal(event.Object, &obj); err != nil {
+		return false, fmt.Errorf("watch %s: decode object: %w", k.resource(), err)
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.resourceVersion = obj.Metadata.ResourceVersion
+	switch event.Type {
+	case "ADDED", "MODIFIED":
+		k.meta[obj.Metadata.Name] = obj.Metadata
+		if obj.Metadata.DeletionTimestamp != "" {
+			delete(k.files, obj.Metadata.Name)
+			break
+		}
+		names, err := k.writeUnlocked(obj)
+		if err != nil {
+			return false, err
+		}
+		k.files[obj.Metadata.Name] = names
+	case "DELETED":
+		delete(k.files, obj.Metadata.Name)
+		delete(k.meta, obj.Metadata.Name)
+	default:
+		return false, nil
+	}
+	if err := k.pruneUnlocked(); err != nil {
+		return false, err
+	}
+	log.Debug().
+		Str("kind", k.opts.Kind).
+		Str("name", obj.Metadata.Name).
+		Str("event", event.Type).
+		Msg("Applied Kubernetes object change to schedules")
+	return true, nil
+}
+
+// writeUnlocked mirrors the manifests of obj and returns their file names.
+func (k *kubeSource) writeUnlocked(obj kubeObject) ([]string, error) {
+	if k.opts.Kind == KindSchedule {
+		return k.writeScheduleUnlocked(obj)
+	}
+
+	var names []string
+	for _, key := range slices.Sorted(maps.Keys(obj.Data)) {
+		if !isManifest(key) {
+			continue
+		}
+		data := []byte(obj.Data[key])
+		if k.opts.Kind == KindSecret {
+			decoded, err := base64.StdEncoding.DecodeString(obj.Data[key])
+			if err != nil {
+				return nil, fmt.Errorf("decode key %q of %s %s: %w", key, k.opts.Kind, obj.Metadata.Name, err)
+			}
+			data = decoded
+		}
+		name := obj.Metadata.Name + "_" + key
+		if err := k.mirror.write(name, data); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// writeScheduleUnlocked mirrors a Schedule object as a schedule manifest.
+// Server-set metadata and the status are dropped.
+func (k *kubeSource) writeScheduleUnlocked(obj kubeObject) ([]string, error) {
+	// The list of a custom resource does not repeat the kind of its items.
+	obj.APIVersion, obj.Kind = "scheduler.yc/v1alpha1", KindSchedule
+	obj.Metadata.ResourceVersion = ""
+	obj.Metadata.Finalizers = nil
+	if _, ok := obj.Metadata.Annotations[lastAppliedAnnotation]; ok {
+		obj.Metadata.Annotations = maps.Clone(obj.Metadata.Annotations)
+		delete(obj.Metadata.Annotations, lastAppliedAnnotation)
+	}
+	if len(obj.Metadata.Annotations) == 0 {
+		obj.Metadata.Annotations = nil
+	}
+
+	// JSON is valid YAML, so the object is mirrored as it is.
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("encode %s %s: %w", k.opts.Kind, obj.Metadata.Name, err)
+	}
+	name := obj.Metadata.Name + ".yaml"
+	if err := k.mirror.write(name, data); err != nil {
+		return nil, err
+	}
+	return []string{name}, nil
+}
+
+// pruneUnlocked removes the files of keys and objects that no longer exist.
+func (k *kubeSource) pruneUnlocked() error {
+	keep := make(map[string]struct{})
+	for _, names := range k.files {
+		for _, name := range names {
+			keep[name] = struct{}{}
+		}
+	}
+	return k.mirror.prune(keep)
+}
+
+// resource returns the API path segment of the kind.
+func (k *kubeSource) resource() string {
+	switch k.opts.Kind {
+	case KindSecret:
+		return "secrets"
+	case KindSchedule:
+		return "schedules"
+	default:
+		return "configmaps"
+	}
+}
+
+// path returns the API path of the objects of the namespace.
+func (k *kubeSource) path() string {
+	prefix := "/api/v1"
+	if k.opts.Kind == KindSchedule {
+		prefix = "/apis/scheduler.yc/v1alpha1"
+	}
+	return prefix + "/namespaces/" + url.PathEscape(k.opts.Namespace) + "/" + k.resource()
+}
+
+// get requests the selected objects with query and returns a successful
+// response.
+func (k *kubeSource) get(ctx context.Context, client *http.Client, query url.Values) (*http.Response, error) {
+	if k.opts.LabelSelector != "" {
+		query.Set("labelSelector", k.opts.LabelSelector)
+	}
+	resp, err := k.do(ctx, client, http.MethodGet, k.path(), query, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get %s: %w", k.resource(), err)
+	}
+	return resp, nil
+}
+
+// do sends an authorized API request and returns a successful response. The
+// service account token is read on every request, since the kubelet rotates
+// it. A request body is sent as a JSON merge patch.
+func (k *kubeSource) do(ctx context.Context, client *http.Client, method, path string, query url.Values, body []byte) (*http.Response, error) {
+	u := *k.api
+	u.Path = path
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	token, err := os.ReadFile(k.tokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("read service account token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusGone {
+		resp.Body.Close()
+		return nil, errWatchExpired
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		var status kubeStatus
+		if body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10)); json.Unmarshal(body, &status) == nil && status.Message != "" {
+			return nil, fmt.Errorf("%s: %s", resp.Status, status.Message)
+		}
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return resp, nil
+}
+
+// scheduleSource is the source of Schedule objects, which reports the runs
+// of their schedules in the status subresource.
+type scheduleSource struct {
+	*kubeSource
+}
+
+// Objects returns the names of the mirrored Schedule objects.
+func (s *scheduleSource) Objects() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Sorted(maps.Keys(s.files))
+}
+
+// WriteStatus replaces the status of the named Schedule object.
+func (s *scheduleSource) WriteStatus(ctx context.Context, name string, status any) error {
+	body, err := json.Marshal(map[string]any{"status": status})
+	if err != nil {
+		return fmt.Errorf("encode status of %s %s: %w", s.opts.Kind, name, err)
+	}
+	resp, err := s.do(ctx, s.client, http.MethodPatch, s.path()+"/"+url.PathEscape(name)+"/status", url.Values{}, body)
+	if err != nil {
+		return fmt.Errorf("write status of %s %s: %w", s.opts.Kind, name, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Deleting returns the names of the Schedule objects being deleted.
+func (s *scheduleSource) Deleting() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var names []string
+	for name, meta := range s.meta {
+		if meta.DeletionTimestamp != "" {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// AddFinalizer adds the finalizer of the scheduler to the named Schedule
+// object unless it has it already.
+func (s *scheduleSource) AddFinalizer(ctx context.Context, name string) error {
+	return s.setFinalizer(ctx, name, true)
+}
+
+// RemoveFinalizer removes the finalizer of the scheduler from the named
+// Schedule object if it has it.
+func (s *scheduleSource) RemoveFinalizer(ctx context.Context, name string) error {
+	return s.setFinalizer(ctx, name, false)
+}
+
+// setFinalizer adds or removes the finalizer of the scheduler. The patch
+// carries the last seen resource version, so it fails with a conflict
+// instead of overwriting finalizers changed by others meanwhile.
+func (s *scheduleSource) setFinalizer(ctx context.Context, name string, set bool) error {
+	s.mu.Lock()
+	meta, ok := s.meta[name]
+	s.mu.Unlock()
+	if !ok || slices.Contains(meta.Finalizers, scheduleFinalizer) == set {
+		return nil
+	}
+
+	finalizers := slices.DeleteFunc(slices.Clone(meta.Finalizers), func(f string) bool { return f == scheduleFinalizer })
+	if set {
+		finalizers = append(finalizers, scheduleFinalizer)
+	}
+	body, err := json.Marshal(map[string]any{"metadata": map[string]any{
+		"finalizers":      finalizers,
+		"resourceVersion": meta.ResourceVersion,
+	}})
+	if err != nil {
+		return fmt.Errorf("encode finalizers of %s %s: %w", s.opts.Kind, name, err)
+	}
+	resp, err := s.do(ctx, s.client, http.MethodPatch, s.path()+"/"+url.PathEscape(name), url.Values{}, body)
+	if err != nil {
+		return fmt.Errorf("update finalizers of %s %s: %w", s.opts.Kind, name, err)
+	}
+	defer resp.Body.Close()
+
+	// The watch reports the patched object later, so the patch is not
+	// repeated with a stale resource version meanwhile.
+	var obj kubeObject
+	if err := json.NewDecoder(resp.Body).Decode(&obj); err != nil {
+		return fmt.Errorf("update finalizers of %s %s: decode response: %w", s.opts.Kind, name, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.meta[name]; ok {
+		s.meta[name] = obj.Metadata
+	}
+	return nil
+}
@@ -0,0 +1,299 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeAPIServer serves a list of ConfigMaps and then streams events to
+// watches from the version of the last list. Older versions are gone.
+type fakeAPIServer struct {
+	events chan string
+	lists  atomic.Int32
+}
+
+func (s *fakeAPIServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/api/v1/namespaces/ops/configmaps" || r.Header.Get("Authorization") != "Bearer token" ||
+		r.URL.Query().Get("labelSelector") != "yc-scheduler/schedules=true" {
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+		return
+	}
+
+	if r.URL.Query().Get("watch") != "true" {
+		version := s.lists.Add(1)
+		fmt.Fprintf(w, `{"metadata":{"resourceVersion":"%d"},"items":[`+
+			`{"metadata":{"name":"team-a","resourceVersion":"1"},"data":{"vm.yaml":"vm","README":"skip"}}]}`, version)
+		return
+	}
+	if r.URL.Query().Get("resourceVersion") != fmt.Sprint(s.lists.Load()) {
+		w.WriteHeader(http.StatusGone)
+		return
+	}
+	w.(http.Flusher).Flush()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-s.events:
+			if !ok {
+				return
+			}
+			fmt.Fprintln(w, event)
+			w.(http.Flusher).Flush()
+		}
+	}
+}
+
+func configMapEvent(t *testing.T, eventType, name, version string, data map[string]string) string {
+	t.Helper()
+
+	obj := map[string]any{
+		"metadata": map[string]string{"name": name, "resourceVersion": version},
+		"data":     data,
+	}
+	event, err := json.Marshal(map[string]any{"type": eventType, "object": obj})
+	if err != nil {
+		t.Fatalf("marshal event: %v", err)
+	}
+	return string(event)
+}
+
+func TestKubernetesWatch(t *testing.T) {
+	t.Parallel()
+
+	api := &fakeAPIServer{events: make(chan string)}
+	srv := httptest.NewServer(api)
+	defer srv.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("token\n"), 0o600); err != nil {
+		t.Fatalf("write token: %v", err)
+	}
+	endpoint, _ := url.Parse(srv.URL)
+	src, err := newKubernetes(KubernetesOptions{Namespace: "ops", LabelSelector: "yc-scheduler/schedules=true"},
+		endpoint, tokenFile, http.DefaultTransport, t.TempDir())
+	if err != nil {
+		t.Fatalf("newKubernetes() error = %v", err)
+	}
+
+	if err := src.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	assertFile(t, filepath.Join(src.Dir(), "team-a_vm.yaml"), "vm")
+	if _, err := os.Stat(filepath.Join(src.Dir(), "team-a_README")); !os.IsNotExist(err) {
+		t.Fatal("README key is mirrored, want only manifests")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changed := make(chan struct{}, 10)
+	go src.Watch(ctx, func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	})
+
+	waitChanged := func() {
+		t.Helper()
+		select {
+		case <-changed:
+		case <-time.After(5 * time.Second):
+			t.Fatal("watch event was not applied")
+		}
+	}
+
+	api.events <- configMapEvent(t, "MODIFIED", "team-a", "2", map[string]string{"vm.yaml": "vm-2"})
+	waitChanged()
+	assertFile(t, filepath.Join(src.Dir(), "team-a_vm.yaml"), "vm-2")
+
+	api.events <- configMapEvent(t, "ADDED", "team-b", "3", map[string]string{"db.yml": "db"})
+	waitChanged()
+	assertFile(t, filepath.Join(src.Dir(), "team-b_db.yml"), "db")
+
+	api.events <- configMapEvent(t, "DELETED", "team-a", "4", nil)
+	waitChanged()
+	if _, err := os.Stat(filepath.Join(src.Dir(), "team-a_vm.yaml")); !os.IsNotExist(err) {
+		t.Fatal("team-a_vm.yaml is kept after the ConfigMap was deleted")
+	}
+}
+
+func TestKubernetesWatchRelistsExpiredVersion(t *testing.T) {
+	t.Parallel()
+
+	api := &fakeAPIServer{events: make(chan string)}
+	srv := httptest.NewServer(api)
+	defer srv.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("token"), 0o600); err != nil {
+		t.Fatalf("write token: %v", err)
+	}
+	endpoint, _ := url.Parse(srv.URL)
+	src, err := newKubernetes(KubernetesOptions{Namespace: "ops", LabelSelector: "yc-scheduler/schedules=true"},
+		endpoint, tokenFile, http.DefaultTransport, t.TempDir())
+	if err != nil {
+		t.Fatalf("newKubernetes() error = %v", err)
+	}
+	if err := src.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changed := make(chan struct{}, 10)
+	go src.Watch(ctx, func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	})
+
+	api.events <- `{"type":"ERROR","object":{"kind":"Status","code":410,"message":"too old resource version"}}`
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("objects were not listed again after the watch expired")
+	}
+}
+
+func TestKubernetesSchedules(t *testing.T) {
+	t.Parallel()
+
+	var patch atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const objects = "/apis/scheduler.yc/v1alpha1/namespaces/ops/schedules"
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == objects:
+			fmt.Fprint(w, `{"metadata":{"resourceVersion":"1"},"items":[{"metadata":{"name":"vm-nightly",`+
+				`"resourceVersion":"1","annotations":{"kubectl.kubernetes.io/last-applied-configuration":"{}"}},`+
+				`"spec":{"type":"cron"},"status":{"lastResult":"Succeeded"}}]}`)
+		case r.Method == http.MethodPatch && r.URL.Path == objects+"/vm-nightly/status" &&
+			r.Header.Get("Content-Type") == "application/merge-patch+json":
+			body, _ := io.ReadAll(r.Body)
+			patch.Store(string(body))
+			fmt.Fprint(w, `{}`)
+		default:
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("token"), 0o600); err != nil {
+		t.Fatalf("write token: %v", err)
+	}
+	endpoint, _ := url.Parse(srv.URL)
+	k, err := newKubernetes(KubernetesOptions{Namespace: "ops", Kind: KindSchedule},
+		endpoint, tokenFile, http.DefaultTransport, t.TempDir())
+	if err != nil {
+		t.Fatalf("newKubernetes() error = %v", err)
+	}
+	src := &scheduleSource{kubeSource: k}
+	if err := src.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	assertFile(t, filepath.Join(src.Dir(), "vm-nightly.yaml"),
+		`{"apiVersion":"scheduler.yc/v1alpha1","kind":"Schedule","metadata":{"name":"vm-nightly"},"spec":{"type":"cron"}}`)
+	if got := src.Objects(); len(got) != 1 || got[0] != "vm-nightly" {
+		t.Fatalf("Objects() = %v, want [vm-nightly]", got)
+	}
+
+	if err := src.WriteStatus(context.Background(), "vm-nightly", map[string]string{"lastResult": "Failed"}); err != nil {
+		t.Fatalf("WriteStatus() error = %v", err)
+	}
+	if got := patch.Load(); got != `{"status":{"lastResult":"Failed"}}` {
+		t.Fatalf("status patch = %v", got)
+	}
+	if err := src.WriteStatus(context.Background(), "missing", map[string]string{}); err == nil {
+		t.Fatal("WriteStatus() of a missing object succeeded")
+	}
+}
+
+func TestKubernetesScheduleFinalizers(t *testing.T) {
+	t.Parallel()
+
+	var patches []string
+	var mu sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const objects = "/apis/scheduler.yc/v1alpha1/namespaces/ops/schedules"
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == objects:
+			fmt.Fprint(w, `{"metadata":{"resourceVersion":"2"},"items":[`+
+				`{"metadata":{"name":"vm-nightly","resourceVersion":"1"},"spec":{"type":"cron"}},`+
+				`{"metadata":{"name":"gone","resourceVersion":"2","deletionTimestamp":"2026-03-02T08:00:00Z",`+
+				`"finalizers":["scheduler.yc/schedule","example.com/keep"]},"spec":{"type":"cron"}}]}`)
+		case r.Method == http.MethodPatch && strings.HasPrefix(r.URL.Path, objects+"/"):
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			patches = append(patches, string(body))
+			mu.Unlock()
+			var patch struct {
+				Metadata kubeMetadata `json:"metadata"`
+			}
+			_ = json.Unmarshal(body, &patch)
+			patch.Metadata.Name = strings.TrimPrefix(r.URL.Path, objects+"/")
+			patch.Metadata.ResourceVersion = "3"
+			_ = json.NewEncoder(w).Encode(map[string]any{"metadata": patch.Metadata})
+		default:
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("token"), 0o600); err != nil {
+		t.Fatalf("write token: %v", err)
+	}
+	endpoint, _ := url.Parse(srv.URL)
+	k, err := newKubernetes(KubernetesOptions{Namespace: "ops", Kind: KindSchedule},
+		endpoint, tokenFile, http.DefaultTransport, t.TempDir())
+	if err != nil {
+		t.Fatalf("newKubernetes() error = %v", err)
+	}
+	src := &scheduleSource{kubeSource: k}
+	if err := src.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(src.Dir(), "gone.yaml")); !os.IsNotExist(err) {
+		t.Fatal("Schedule being deleted is mirrored")
+	}
+	if got := src.Objects(); !slices.Equal(got, []string{"vm-nightly"}) {
+		t.Fatalf("Objects() = %v, want [vm-nightly]", got)
+	}
+	if got := src.Deleting(); !slices.Equal(got, []string{"gone"}) {
+		t.Fatalf("Deleting() = %v, want [gone]", got)
+	}
+
+	// Adding twice patches once, since the patched object is remembered.
+	for range 2 {
+		if err := src.AddFinalizer(context.Background(), "vm-nightly"); err != nil {
+			t.Fatalf("AddFinalizer() error = %v", err)
+		}
+	}
+	if err := src.RemoveFinalizer(context.Background(), "gone"); err != nil {
+		t.Fatalf("RemoveFinalizer() error = %v", err)
+	}
+	want := []string{
+		`{"metadata":{"finalizers":["scheduler.yc/schedule"],"resourceVersion":"1"}}`,
+		`{"metadata":{"finalizers":["example.com/keep"],"resourceVersion":"2"}}`,
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !slices.Equal(patches, want) {
+		t.Fatalf("patches = %v, want %v", patches, want)
+	}
+}
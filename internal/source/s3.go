@@ -0,0 +1,349 @@
+package source
+
+import (
+	"cmp"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// defaultS3Endpoint is the endpoint of Yandex Object Storage.
+	defaultS3Endpoint = "https://storage.yandexcloud.net"
+	defaultS3Region   = "ru-central1"
+	s3RequestTimeout  = 30 * time.Second
+	// emptyPayloadHash is the SHA-256 of an empty request body.
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// s3Config holds the connection settings of an S3-compatible storage.
+// Requests are anonymous without an access key.
+type s3Config struct {
+	endpoint     *url.URL
+	region       string
+	accessKey    string
+	secretKey    string
+	sessionToken string
+}
+
+// s3ConfigFromEnv reads the settings from the environment variables used by
+// AWS tools: AWS_ENDPOINT_URL_S3 or AWS_ENDPOINT_URL, AWS_REGION,
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN.
+func s3ConfigFromEnv(lookupEnv func(string) (string, bool)) (s3Config, error) {
+	get := func(names ...string) string {
+		for _, name := range names {
+			if value, ok := lookupEnv(name); ok && value != "" {
+				return value
+			}
+		}
+		return ""
+	}
+
+	endpoint := cmp.Or(get("AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL"), defaultS3Endpoint)
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return s3Config{}, fmt.Errorf("invalid S3 endpoint %q", endpoint)
+	}
+	cfg := s3Config{
+		endpoint:     u,
+		region:       cmp.Or(get("AWS_REGION", "AWS_DEFAULT_REGION"), defaultS3Region),
+		accessKey:    get("AWS_ACCESS_KEY_ID"),
+		secretKey:    get("AWS_SECRET_ACCESS_KEY"),
+		sessionToken: get("AWS_SESSION_TOKEN"),
+	}
+	if (cfg.accessKey == "") != (cfg.secretKey == "") {
+		return s3Config{}, fmt.Errorf("both AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set")
+	}
+	return cfg, nil
+}
+
+// s3Source mirrors the manifests stored under a prefix of a bucket. Objects
+// are downloaded again only when their ETag changes.
+type s3Source struct {
+	cfg    s3Config
+	client *http.Client
+	bucket string
+	prefix string
+	mirror mirror
+	etags  map[string]string
+	now    func() time.Time
+}
+
+func newS3(cfg s3Config, bucket, prefix, dir string) (*s3Source, error) {
+	if bucket == "" {
+		return nil, fmt.Errorf("schedules source: empty S3 bucket name")
+	}
+	return &s3Source{
+		cfg:    cfg,
+		client: &http.Client{Timeout: s3RequestTimeout},
+		bucket: bucket,
+		prefix: prefix,
+		mirror: mirror{dir: dir},
+		etags:  make(map[string]string),
+		now:    time.Now,
+	}, nil
+}
+
+// Dir returns the directory the manifests are mirrored to.
+func (s *s3Source) Dir() string {
+	return s.mirror.dir
+}
+
+// Sync downloads new and changed manifests and removes the copies of deleted
+// ones. Keys below the prefix become paths in the mirror, so manifests in
+// nested "folders" are loaded with schedules_recursive only.
+func (s *s3Source) Sync(ctx context.Context) error {
+	objects, err := s.list(ctx)
+	if err != nil {
+		return err
+	}
+
+	keep := make(map[string]struct{}, len(objects))
+	for _, obj := range objects {
+		name := strings.TrimPrefix(strings.TrimPrefix(obj.Key, s.prefix), "/")
+		if !isManifest(name) {
+			continue
+		}
+		if !filepath.IsLocal(filepath.FromSlash(name)) {
+			log.Warn().
+				Str("bucket", s.bucket).
+				Str("key", obj.Key).
+				Msg("Skipping S3 object with a key that is not a relative path")
+			continue
+		}
+		keep[name] = struct{}{}
+		if s.etags[name] == obj.ETag && s.mirror.exists(name) {
+			continue
+		}
+
+		data, err := s.get(ctx, obj.Key)
+		if err != nil {
+			return err
+		}
+		if err := s.mirror.write(name, data); err != nil {
+			return err
+		}
+		s.etags[name] = obj.ETag
+		log.Debug().
+			Str("bucket", s.bucket).
+			Str("key", obj.Key).
+			Msg("Downloaded schedule manifest from S3")
+	}
+
+	if err := s.mirror.prune(keep); err != nil {
+		return err
+	}
+	for name := range s.etags {
+		if _, ok := keep[name]; !ok {
+			delete(s.etags, name)
+		}
+	}
+	return nil
+}
+
+type s3Object struct {
+	Key  string `xml:"Key"`
+	ETag string `xml:"ETag"`
+}
+
+type listBucketResult struct {
+	Contents              []s3Object `xml:"Contents"`
+	IsTruncated           bool       `xml:"IsTruncated"`
+	NextContinuationToken string     `xml:"NextContinuationToken"`
+}
+
+type s3Error struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// list returns all objects under the prefix, following continuation tokens.
+func (s *s3Source) list(ctx context.Context) ([]s3Object, error) {
+	var objects []s3Object
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {s.prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		body, err := s.do(ctx, "", query)
+		if err != nil {
+			return nil, fmt.Errorf("list s3://%s/%s: %w", s.bucket, s.prefix, err)
+		}
+
+		var result listBucketResult
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("list s3://%s/%s: decode response: %w", s.bucket, s.prefix, err)
+		}
+		objects = append(objects, result.Contents...)
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// get returns the content of the object with key.
+func (s *s3Source) get(ctx context.Context, key string) ([]byte, error) {
+	body, err := s.do(ctx, key, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get s3://%s/%s: %w", s.bucket, key, err)
+	}
+	return body, nil
+}
+
+// do sends a signed GET request for key of the bucket, or for the bucket
+// itself when key is empty, and returns the response body.
+func (s *s3Source) do(ctx context.Context, key string, query url.Values) ([]byte, error) {
+	u := *s.cfg.endpoint
+	base := strings.TrimSuffix(u.Path, "/")
+	u.Path = base + "/" + s.bucket
+	u.RawPath = base + "/" + s3Escape(s.bucket, false)
+	if key != "" {
+		u.Path += "/" + key
+		u.RawPath += "/" + s3Escape(key, true)
+	}
+	u.RawQuery = canonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, emptyPayloadHash, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e s3Error
+		if xml.Unmarshal(body, &e) == nil && e.Code != "" {
+			return nil, fmt.Errorf("%s: %s: %s", resp.Status, e.Code, e.Message)
+		}
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return body, nil
+}
+
+// sign adds AWS Signature Version 4 headers to req. The host and all headers
+// already set on req are signed.
+func (s *s3Source) sign(req *http.Request, payloadHash string, now time.Time) {
+	if s.cfg.accessKey == "" {
+		return
+	}
+
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.cfg.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.cfg.sessionToken)
+	}
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalURI := req.URL.EscapedPath()
+	if canonicalURI == "" {
+		canonicalURI = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.cfg.region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.secretKey), date)
+	key = hmacSHA256(key, s.cfg.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+s.cfg.accessKey+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalQuery encodes query sorted by names and values as required for
+// signing, which also makes it a valid query string.
+func canonicalQuery(query url.Values) string {
+	type pair struct{ name, value string }
+	pairs := make([]pair, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, pair{s3Escape(name, false), s3Escape(value, false)})
+		}
+	}
+	slices.SortFunc(pairs, func(a, b pair) int {
+		return cmp.Or(strings.Compare(a.name, b.name), strings.Compare(a.value, b.value))
+	})
+	encoded := make([]string, 0, len(pairs))
+	for _, p := range pairs {
+		encoded = append(encoded, p.name+"="+p.value)
+	}
+	return strings.Join(encoded, "&")
+}
+
+// s3Escape percent-encodes all bytes of s except unreserved characters and,
+// when keepSlash, slashes.
+func s3Escape(s string, keepSlash bool) string {
+	var b strings.Builder
+	for i := range len(s) {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/' && keepSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
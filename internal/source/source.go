@@ -0,0 +1,124 @@
+// Package source mirrors schedule manifests kept outside the local file
+// system, such as in an S3-compatible bucket, a Git repository or a config
+// service reachable over HTTP(S), into a local directory. The directory is
+// then loaded and watched like any other schedules directory.
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Source is a remote location of schedule manifests.
+type Source interface {
+	// Dir returns the local directory the manifests are mirrored to.
+	Dir() string
+	// Sync brings the local directory up to date with the source.
+	Sync(ctx context.Context) error
+}
+
+// New creates a source for uri, e.g. s3://bucket/prefix, mirrored to a new
+// temporary directory. Settings not present in uri, such as credentials, are
+// read with lookupEnv.
+func New(uri string, lookupEnv func(string) (string, bool)) (Source, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("parse schedules source %q: %w", uri, err)
+	}
+
+	switch u.Scheme {
+	case "s3":
+		cfg, err := s3ConfigFromEnv(lookupEnv)
+		if err != nil {
+			return nil, err
+		}
+		dir, err := os.MkdirTemp("", "yc-scheduler-s3-")
+		if err != nil {
+			return nil, fmt.Errorf("create schedules source directory: %w", err)
+		}
+		return newS3(cfg, u.Host, strings.TrimPrefix(u.Path, "/"), dir)
+	default:
+		return nil, fmt.Errorf("unsupported schedules source %q: expected s3://bucket/prefix", uri)
+	}
+}
+
+// SyncAll syncs every source and returns the errors of all failed ones.
+func SyncAll(ctx context.Context, sources []Source) error {
+	var errs []error
+	for _, src := range sources {
+		if err := src.Sync(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// isManifest reports whether name has the extension of a schedule manifest.
+func isManifest(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// mirror is a local directory holding copies of remote manifests by their
+// slash-separated names relative to the source.
+type mirror struct {
+	dir string
+}
+
+// write replaces the copy of the named manifest. It is written to a temporary
+// file first, so a reload never reads a partially written manifest.
+func (m mirror) write(name string, data []byte) error {
+	path := filepath.Join(m.dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create directory for %q: %w", name, err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".download-*")
+	if err != nil {
+		return fmt.Errorf("create temporary file for %q: %w", name, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write %q: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write %q: %w", name, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("replace %q: %w", name, err)
+	}
+	return nil
+}
+
+// exists reports whether the named manifest has a local copy.
+func (m mirror) exists(name string) bool {
+	_, err := os.Stat(filepath.Join(m.dir, filepath.FromSlash(name)))
+	return err == nil
+}
+
+// prune removes copies of manifests that are not in keep.
+func (m mirror) prune(keep map[string]struct{}) error {
+	return filepath.WalkDir(m.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || !isManifest(entry.Name()) {
+			return err
+		}
+		rel, err := filepath.Rel(m.dir, path)
+		if err != nil {
+			return err
+		}
+		if _, ok := keep[filepath.ToSlash(rel)]; ok {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("remove %q: %w", rel, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,447 @@
+// Package vacation puts whole schedule namespaces on vacation: resources of
+// the namespace are stopped when a vacation starts, its schedules are
+// suspended while the vacation lasts, and the stopped resources are started
+// again when it ends.
+package vacation
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/rs/zerolog/log"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/resource"
+)
+
+// Vacation sources.
+const (
+	// SourceConfig marks vacations defined in the configuration file.
+	SourceConfig = "config"
+	// SourceAPI marks vacations created through the HTTP API.
+	SourceAPI = "api"
+)
+
+// Vacation suspends all schedules of Namespace between From and Until.
+type Vacation struct {
+	From      time.Time `json:"from"`
+	Until     time.Time `json:"until"`
+	ID        string    `json:"id"`
+	Namespace string    `json:"namespace"`
+	Reason    string    `json:"reason,omitempty"`
+	Source    string    `json:"source"`
+	// Started reports whether the resources of the namespace were stopped.
+	Started bool `json:"started"`
+	// Stopped lists the resources stopped by the vacation, which are started
+	// again when it ends. Resources that failed to start stay listed and are
+	// started again on the next check.
+	Stopped []config.Resource `json:"stopped,omitempty"`
+
+	starts []resource.StartOptions
+}
+
+// savedVacation is a vacation as written to the file of a Manager.
+type savedVacation struct {
+	Vacation
+	Starts []resource.StartOptions `json:"starts,omitempty"`
+}
+
+// Covers reports whether now is within the vacation.
+func (v Vacation) Covers(now time.Time) bool {
+	return !now.Before(v.From) && now.Before(v.Until)
+}
+
+// ScheduleSource provides the current schedules.
+type ScheduleSource interface {
+	Schedules() []config.Schedule
+}
+
+// Manager holds vacations and stops and restores their resources.
+type Manager struct {
+	stateChecker resource.StateChecker
+	operator     resource.Operator
+	schedules    ScheduleSource
+	clock        clockwork.Clock
+	vacations    []Vacation
+	path         string
+	mu           sync.Mutex
+	dryRun       bool
+}
+
+// NewManager creates a Manager without vacations. With dryRun, resources are
+// only logged instead of being stopped and started.
+func NewManager(stateChecker resource.StateChecker, operator resource.Operator, schedules ScheduleSource, dryRun bool) *Manager {
+	return &Manager{
+		stateChecker: stateChecker,
+		operator:     operator,
+		schedules:    schedules,
+		clock:        clockwork.NewRealClock(),
+		dryRun:       dryRun,
+	}
+}
+
+// SetClock sets the clock vacations are checked against. It must be called
+// before Start.
+func (m *Manager) SetClock(clock clockwork.Clock) {
+	m.clock = clock
+}
+
+// SetFile persists vacations to the file at path, so resources stopped by a
+// vacation are started again after a restart, and loads the vacations of an
+// existing file. It must be called before Add and Start.
+func (m *Manager) SetFile(path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.path = path
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read vacations: %w", err)
+	}
+	var saved []savedVacation
+	if err := json.Unmarshal(raw, &saved); err != nil {
+		return fmt.Errorf("decode vacations %s: %w", path, err)
+	}
+	for _, sv := range saved {
+		v := sv.Vacation
+		v.starts = sv.Starts
+		m.vacations = append(m.vacations, v)
+	}
+	return nil
+}
+
+// Add registers a vacation and returns it with a generated ID. A vacation
+// from the configuration that was loaded from the file of the Manager is
+// returned as loaded, so a started one is not started again.
+func (m *Manager) Add(v Vacation) Vacation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if v.Source == SourceConfig {
+		for _, loaded := range m.vacations {
+			if loaded.Source == SourceConfig && loaded.Namespace == v.Namespace &&
+				loaded.From.Equal(v.From) && loaded.Until.Equal(v.Until) {
+				return loaded
+			}
+		}
+	}
+
+	v.ID = newID()
+	v.Started = false
+	v.Stopped, v.starts = nil, nil
+	m.vacations = append(m.vacations, v)
+	m.saveLocked()
+
+	log.Info().
+		Str("vacation_id", v.ID).
+		Str("namespace", v.Namespace).
+		Time("from", v.From).
+		Time("until", v.Until).
+		Str("source", v.Source).
+		Str("reason", v.Reason).
+		Msg("Vacation added")
+
+	return v
+}
+
+// Remove ends the vacation with the given ID and reports whether it existed.
+// A vacation that has not started is dropped; a started one ends on the
+// next check, which restores its resources.
+func (m *Manager) Remove(id string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, v := range m.vacations {
+		if v.ID != id {
+			continue
+		}
+		if v.Started {
+			m.vacations[i].Until = m.clock.Now()
+		} else {
+			m.vacations = slices.Delete(m.vacations, i, i+1)
+		}
+		m.saveLocked()
+		log.Info().
+			Str("vacation_id", id).
+			Str("namespace", v.Namespace).
+			Msg("Vacation canceled")
+		return true
+	}
+	return false
+}
+
+// List returns the vacations that have not ended.
+func (m *Manager) List() []Vacation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return slices.Clone(m.vacations)
+}
+
+// OnVacation returns the vacation covering namespace now. A nil Manager has
+// no vacations.
+func (m *Manager) OnVacation(namespace string) (Vacation, bool) {
+	if m == nil {
+		return Vacation{}, false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.clock.Now()
+	for _, v := range m.vacations {
+		if v.Namespace == namespace && v.Covers(now) {
+			return v, true
+		}
+	}
+	return Vacation{}, false
+}
+
+// Start checks vacations every interval until ctx is canceled, starting and
+// ending them when due.
+func (m *Manager) Start(ctx context.Context, interval time.Duration) {
+	if m == nil {
+		return
+	}
+
+	go func() {
+		log.Info().
+			Dur("interval", interval).
+			Msg("Vacation loop started")
+
+		ticker := m.clock.NewTicker(interval)
+		defer ticker.Stop()
+
+		m.sync(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				log.Info().Msg("Vacation loop stopped")
+				return
+			case <-ticker.Chan():
+				m.sync(ctx)
+			}
+		}
+	}()
+}
+
+// sync starts due vacations and ends finished ones. A finished vacation is
+// kept until all of its resources are started again.
+func (m *Manager) sync(ctx context.Context) {
+	now := m.clock.Now()
+	for _, v := range m.List() {
+		switch {
+		case !now.Before(v.Until):
+			if v.Started {
+				m.restore(ctx, v)
+			} else {
+				m.drop(v.ID)
+			}
+		case !v.Started && v.Covers(now):
+			m.begin(ctx, v)
+		}
+	}
+}
+
+// begin stops the running resources of the vacation namespace.
+func (m *Manager) begin(ctx context.Context, v Vacation) {
+	log.Info().
+		Str("vacation_id", v.ID).
+		Str("namespace", v.Namespace).
+		Time("until", v.Until).
+		Msg("Vacation started, stopping namespace resources")
+
+	var stopped []config.Resource
+	var starts []resource.StartOptions
+	seen := make(map[string]bool)
+	for _, sch := range m.schedules.Schedules() {
+		if sch.Namespace != v.Namespace {
+			continue
+		}
+		targets, err := resource.ResolveTargets(ctx, m.stateChecker, sch.Targets())
+		if err != nil {
+			log.Warn().Err(err).
+				Str("vacation_id", v.ID).
+				Str("schedule", sch.Name).
+				Msg("Failed to resolve schedule resources for vacation")
+			continue
+		}
+		for _, target := range targets {
+			key := target.Type + "/" + target.ID
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			if m.stop(ctx, v, target, resource.StopOptionsFromAction(sch.Actions.Stop)) {
+				stopped = append(stopped, target)
+				starts = append(starts, resource.StartOptionsFromSchedule(sch))
+			}
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.vacations {
+		if m.vacations[i].ID == v.ID {
+			m.vacations[i].Started = true
+			m.vacations[i].Stopped = stopped
+			m.vacations[i].starts = starts
+		}
+	}
+	m.saveLocked()
+}
+
+// stop stops a running resource and reports whether it must be started when
+// the vacation ends.
+func (m *Manager) stop(ctx context.Context, v Vacation, target config.Resource, opts resource.StopOptions) bool {
+	state, _, err := m.stateChecker.GetState(ctx, target)
+	if err != nil {
+		log.Warn().Err(err).
+			Str("vacation_id", v.ID).
+			Str("resource_type", target.Type).
+			Str("resource_id", target.ID).
+			Msg("Failed to get resource state for vacation")
+		return false
+	}
+	if state != "running" {
+		return false
+	}
+
+	if m.dryRun {
+		log.Info().
+			Str("vacation_id", v.ID).
+			Str("resource_type", target.Type).
+			Str("resource_id", target.ID).
+			Msg("Dry run: vacation would stop resource")
+		return true
+	}
+	if err := m.operator.Stop(ctx, target, opts); err != nil {
+		log.Error().Err(err).
+			Str("vacation_id", v.ID).
+			Str("resource_type", target.Type).
+			Str("resource_id", target.ID).
+			Msg("Vacation failed to stop resource")
+		return false
+	}
+	log.Info().
+		Str("vacation_id", v.ID).
+		Str("resource_type", target.Type).
+		Str("resource_id", target.ID).
+		Msg("Vacation stopped resource")
+	return true
+}
+
+// restore starts the resources stopped by the vacation and drops it once all
+// of them are started. Resources that failed to start are kept for the next
+// check.
+func (m *Manager) restore(ctx context.Context, v Vacation) {
+	log.Info().
+		Str("vacation_id", v.ID).
+		Str("namespace", v.Namespace).
+		Int("resources", len(v.Stopped)).
+		Msg("Vacation ended, starting stopped resources")
+
+	var failed []config.Resource
+	var failedStarts []resource.StartOptions
+	for i, target := range v.Stopped {
+		if m.dryRun {
+			log.Info().
+				Str("vacation_id", v.ID).
+				Str("resource_type", target.Type).
+				Str("resource_id", target.ID).
+				Msg("Dry run: vacation would start resource")
+			continue
+		}
+		if err := m.operator.Start(ctx, target, v.starts[i]); err != nil {
+			log.Error().Err(err).
+				Str("vacation_id", v.ID).
+				Str("resource_type", target.Type).
+				Str("resource_id", target.ID).
+				Msg("Vacation failed to start resource, retrying on the next check")
+			failed = append(failed, target)
+			failedStarts = append(failedStarts, v.starts[i])
+		}
+	}
+	if len(failed) == 0 {
+		m.drop(v.ID)
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.vacations {
+		if m.vacations[i].ID == v.ID {
+			m.vacations[i].Stopped = failed
+			m.vacations[i].starts = failedStarts
+		}
+	}
+	m.saveLocked()
+}
+
+func (m *Manager) drop(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.vacations = slices.DeleteFunc(m.vacations, func(v Vacation) bool {
+		return v.ID == id
+	})
+	m.saveLocked()
+}
+
+// saveLocked writes the vacations to a temporary file renamed over the file
+// of the Manager, so a crash never leaves a truncated file. Vacations are
+// kept in memory when writing fails. It must be called with m.mu held.
+func (m *Manager) saveLocked() {
+	if m.path == "" {
+		return
+	}
+	if err := m.writeLocked(); err != nil {
+		log.Error().Err(err).
+			Str("path", m.path).
+			Msg("Failed to persist vacations")
+	}
+}
+
+func (m *Manager) writeLocked() error {
+	saved := make([]savedVacation, 0, len(m.vacations))
+	for _, v := range m.vacations {
+		saved = append(saved, savedVacation{Vacation: v, Starts: v.starts})
+	}
+	raw, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(m.path), filepath.Base(m.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), m.path)
+}
+
+func newID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
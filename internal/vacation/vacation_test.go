@@ -0,0 +1,186 @@
+package vacation
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/resource"
+)
+
+type staticSchedules []config.Schedule
+
+func (s staticSchedules) Schedules() []config.Schedule { return s }
+
+type stateChecker map[string]string
+
+func (c stateChecker) GetState(_ context.Context, res config.Resource) (string, bool, error) {
+	return c[res.ID], false, nil
+}
+
+type recordingOperator struct {
+	resource.Operator
+	calls []string
+	// failStarts is the number of starts that fail before they succeed.
+	failStarts int
+}
+
+func (o *recordingOperator) Start(_ context.Context, res config.Resource, _ resource.StartOptions) error {
+	o.calls = append(o.calls, "start "+res.ID)
+	if o.failStarts > 0 {
+		o.failStarts--
+		return errors.New("quota exceeded")
+	}
+	return nil
+}
+
+func (o *recordingOperator) Stop(_ context.Context, res config.Resource, _ resource.StopOptions) error {
+	o.calls = append(o.calls, "stop "+res.ID)
+	return nil
+}
+
+func TestManagerStopsAndRestoresNamespace(t *testing.T) {
+	t.Parallel()
+
+	clock := clockwork.NewFakeClockAt(time.Date(2026, 12, 20, 12, 0, 0, 0, time.UTC))
+	schedules := staticSchedules{
+		{Name: "app", Namespace: "dev", Resource: config.Resource{Type: "vm", ID: "running-vm"}},
+		{Name: "db", Namespace: "dev", Resource: config.Resource{Type: "vm", ID: "stopped-vm"}},
+		{Name: "prod", Namespace: "prod", Resource: config.Resource{Type: "vm", ID: "prod-vm"}},
+	}
+	states := stateChecker{"running-vm": "running", "stopped-vm": "stopped", "prod-vm": "running"}
+	op := &recordingOperator{}
+	m := NewManager(states, op, schedules, false)
+	m.SetClock(clock)
+
+	from := clock.Now().Add(time.Hour)
+	v := m.Add(Vacation{Namespace: "dev", From: from, Until: from.Add(24 * time.Hour), Source: SourceAPI})
+
+	m.sync(context.Background())
+	if len(op.calls) != 0 {
+		t.Fatalf("calls before vacation = %v, want none", op.calls)
+	}
+	if _, on := m.OnVacation("dev"); on {
+		t.Fatal("namespace is on vacation before it starts")
+	}
+
+	clock.Advance(time.Hour)
+	if got, on := m.OnVacation("dev"); !on || got.ID != v.ID {
+		t.Fatalf("OnVacation(dev) = %+v, %v; want vacation %s", got, on, v.ID)
+	}
+	if _, on := m.OnVacation("prod"); on {
+		t.Fatal("other namespace is on vacation")
+	}
+	m.sync(context.Background())
+	if len(op.calls) != 1 || op.calls[0] != "stop running-vm" {
+		t.Fatalf("calls at vacation start = %v, want [stop running-vm]", op.calls)
+	}
+
+	clock.Advance(24 * time.Hour)
+	m.sync(context.Background())
+	if len(op.calls) != 2 || op.calls[1] != "start running-vm" {
+		t.Fatalf("calls at vacation end = %v, want running-vm started again", op.calls)
+	}
+	if got := len(m.List()); got != 0 {
+		t.Fatalf("len(List()) = %d after vacation end, want 0", got)
+	}
+}
+
+func TestManagerRemoveEndsStartedVacation(t *testing.T) {
+	t.Parallel()
+
+	clock := clockwork.NewFakeClockAt(time.Date(2026, 12, 20, 12, 0, 0, 0, time.UTC))
+	schedules := staticSchedules{{Name: "app", Namespace: "dev", Resource: config.Resource{Type: "vm", ID: "vm-1"}}}
+	op := &recordingOperator{}
+	m := NewManager(stateChecker{"vm-1": "running"}, op, schedules, false)
+	m.SetClock(clock)
+
+	v := m.Add(Vacation{Namespace: "dev", From: clock.Now(), Until: clock.Now().Add(time.Hour)})
+	m.sync(context.Background())
+
+	if !m.Remove(v.ID) {
+		t.Fatal("Remove() = false, want true")
+	}
+	if _, on := m.OnVacation("dev"); on {
+		t.Fatal("namespace is still on vacation after Remove()")
+	}
+	m.sync(context.Background())
+	if len(op.calls) != 2 || op.calls[1] != "start vm-1" {
+		t.Fatalf("calls = %v, want vm-1 stopped and started again", op.calls)
+	}
+	if m.Remove(v.ID) {
+		t.Fatal("Remove() of an ended vacation = true")
+	}
+}
+
+func TestManagerRetriesFailedRestore(t *testing.T) {
+	t.Parallel()
+
+	clock := clockwork.NewFakeClockAt(time.Date(2026, 12, 20, 12, 0, 0, 0, time.UTC))
+	schedules := staticSchedules{{Name: "app", Namespace: "dev", Resource: config.Resource{Type: "vm", ID: "vm-1"}}}
+	op := &recordingOperator{failStarts: 1}
+	m := NewManager(stateChecker{"vm-1": "running"}, op, schedules, false)
+	m.SetClock(clock)
+
+	m.Add(Vacation{Namespace: "dev", From: clock.Now(), Until: clock.Now().Add(time.Hour)})
+	m.sync(context.Background())
+
+	clock.Advance(time.Hour)
+	m.sync(context.Background())
+	if got := m.List(); len(got) != 1 || len(got[0].Stopped) != 1 {
+		t.Fatalf("List() after failed restore = %+v, want vacation kept with vm-1", got)
+	}
+	if _, on := m.OnVacation("dev"); on {
+		t.Fatal("namespace is still on vacation after it ended")
+	}
+
+	m.sync(context.Background())
+	if want := []string{"stop vm-1", "start vm-1", "start vm-1"}; !slices.Equal(op.calls, want) {
+		t.Fatalf("calls = %v, want %v", op.calls, want)
+	}
+	if got := len(m.List()); got != 0 {
+		t.Fatalf("len(List()) = %d after restore, want 0", got)
+	}
+}
+
+func TestManagerRestoresAfterRestart(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "vacations.json")
+	clock := clockwork.NewFakeClockAt(time.Date(2026, 12, 20, 12, 0, 0, 0, time.UTC))
+	schedules := staticSchedules{{Name: "app", Namespace: "dev", Resource: config.Resource{Type: "vm", ID: "vm-1"}}}
+	configured := Vacation{Namespace: "dev", From: clock.Now(), Until: clock.Now().Add(time.Hour), Source: SourceConfig}
+
+	before := &recordingOperator{}
+	m := NewManager(stateChecker{"vm-1": "running"}, before, schedules, false)
+	m.SetClock(clock)
+	if err := m.SetFile(path); err != nil {
+		t.Fatalf("SetFile() error = %v", err)
+	}
+	m.Add(configured)
+	m.sync(context.Background())
+
+	// The restarted manager sees vm-1 stopped and must not lose it.
+	after := &recordingOperator{}
+	restarted := NewManager(stateChecker{"vm-1": "stopped"}, after, schedules, false)
+	restarted.SetClock(clock)
+	if err := restarted.SetFile(path); err != nil {
+		t.Fatalf("SetFile() after restart error = %v", err)
+	}
+	restarted.Add(configured)
+	if got := restarted.List(); len(got) != 1 || !got[0].Started {
+		t.Fatalf("List() after restart = %+v, want the started vacation only", got)
+	}
+
+	clock.Advance(time.Hour)
+	restarted.sync(context.Background())
+	if want := []string{"start vm-1"}; !slices.Equal(after.calls, want) {
+		t.Fatalf("calls after restart = %v, want %v", after.calls, want)
+	}
+}
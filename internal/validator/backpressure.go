@@ -0,0 +1,53 @@
+package validator
+
+import (
+	"github.com/rs/zerolog/log"
+)
+
+// backlogSource reports how many due jobs wait for a free execution slot.
+type backlogSource interface {
+	Backlog() int
+}
+
+// backpressured reports whether the validator must skip its run because the
+// job backlog grew above the configured maximum. Once paused, it resumes
+// when the backlog drains to half of the maximum, so it does not flap while
+// the backlog hovers around the threshold. Corrective jobs would only join
+// the queue and add load on a slow cloud API.
+func (v *Validator) backpressured() bool {
+	source, ok := v.scheduler.(backlogSource)
+	if !ok {
+		return false
+	}
+
+	backlog := source.Backlog()
+	limit := v.getConfig().EffectiveValidationMaxBacklog()
+	if v.metrics != nil {
+		v.metrics.SetJobBacklog(backlog)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	switch {
+	case !v.backpressure && backlog > limit:
+		v.backpressure = true
+		log.Warn().
+			Int("backlog", backlog).
+			Int("max_backlog", limit).
+			Msg("Job backlog exceeded the maximum, validator corrections are paused")
+	case v.backpressure && backlog <= limit/2:
+		v.backpressure = false
+		log.Info().
+			Int("backlog", backlog).
+			Int("max_backlog", limit).
+			Msg("Job backlog drained, validator corrections are resumed")
+	default:
+		return v.backpressure
+	}
+
+	if v.metrics != nil {
+		v.metrics.SetValidatorBackpressure(v.backpressure)
+	}
+	return v.backpressure
+}
@@ -0,0 +1,65 @@
+package validator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/scheduleset"
+)
+
+// backloggedScheduler records corrective jobs and reports a set backlog.
+type backloggedScheduler struct {
+	recordingScheduler
+	backlog int
+}
+
+func (s *backloggedScheduler) Backlog() int {
+	return s.backlog
+}
+
+func TestRunOncePausesCorrectionsUntilBacklogDrains(t *testing.T) {
+	t.Parallel()
+
+	sch := config.Schedule{
+		Name:     "vm",
+		Type:     "daily",
+		Resource: config.Resource{Type: "vm", ID: "vm"},
+		Actions: config.Actions{
+			Start: &config.ActionConfig{Enabled: true, Time: "09:00"},
+			Stop:  &config.ActionConfig{Enabled: true, Time: "20:00"},
+		},
+	}
+	sched := &backloggedScheduler{}
+	v := New(stoppedChecker{}, nopOperator{}, &config.Config{ValidationMaxBacklog: 10}, sched, nil, false)
+	v.SetScheduleSets(scheduleset.NewStore([]config.Schedule{sch}))
+	v.SetClock(clockwork.NewFakeClockAt(time.Date(2026, time.May, 4, 12, 0, 0, 0, time.Local)))
+
+	runs := func() int {
+		v.runOnce(context.Background())
+		sched.wg.Wait()
+		sched.mu.Lock()
+		defer sched.mu.Unlock()
+		return len(sched.order)
+	}
+
+	// Above the maximum the run is skipped, and it stays skipped until the
+	// backlog drains to half of the maximum.
+	for _, step := range []struct {
+		backlog int
+		jobs    int
+	}{
+		{backlog: 10, jobs: 1},
+		{backlog: 11, jobs: 1},
+		{backlog: 6, jobs: 1},
+		{backlog: 5, jobs: 2},
+	} {
+		sched.backlog = step.backlog
+		if got := runs(); got != step.jobs {
+			t.Fatalf("corrective jobs after a run with backlog %d = %d, want %d", step.backlog, got, step.jobs)
+		}
+	}
+}
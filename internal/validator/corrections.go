@@ -0,0 +1,139 @@
+package validator
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+// correction is a corrective action for a single-resource schedule.
+type correction struct {
+	sch     config.Schedule
+	action  string
+	jobName string
+}
+
+// startRank orders resource types within a schedule for starts: clusters
+// start before their node groups.
+var startRank = map[string]int{
+	"k8s_cluster":    0,
+	"k8s_node_group": 1,
+}
+
+// correctionWaves groups corrections into waves run one after another, so
+// that dependent corrections do not race: starts follow schedule
+// dependencies (a database before the application using it) and stops run
+// in reverse (node groups before their cluster). Starts and stops are
+// independent of each other and share waves.
+func correctionWaves(corrections []correction) [][]correction {
+	depths := dependencyDepths(corrections)
+
+	levels := make([]int, len(corrections))
+	maxLevel := 0
+	for i, c := range corrections {
+		levels[i] = depths[c.sch.Name]*len(startRank) + startRank[c.sch.Resource.Type]
+		maxLevel = max(maxLevel, levels[i])
+	}
+	for i, c := range corrections {
+		if c.action == "stop" {
+			levels[i] = maxLevel - levels[i]
+		}
+	}
+
+	waves := make([][]correction, maxLevel+1)
+	for i, c := range corrections {
+		waves[levels[i]] = append(waves[levels[i]], c)
+	}
+	return slices.DeleteFunc(waves, func(wave []correction) bool { return len(wave) == 0 })
+}
+
+// dependencyDepths returns the depth of each corrected schedule in the
+// depends_on graph restricted to corrected schedules: 0 for schedules
+// without corrected dependencies.
+func dependencyDepths(corrections []correction) map[string]int {
+	schedules := make(map[string]config.Schedule, len(corrections))
+	for _, c := range corrections {
+		schedules[c.sch.Name] = c.sch
+	}
+
+	depths := make(map[string]int, len(schedules))
+	// Dependencies are validated to be acyclic when schedules are registered.
+	var depth func(name string) int
+	depth = func(name string) int {
+		if d, ok := depths[name]; ok {
+			return d
+		}
+		d := 0
+		for _, dep := range schedules[name].DependsOn {
+			if _, ok := schedules[dep]; ok {
+				d = max(d, depth(dep)+1)
+			}
+		}
+		depths[name] = d
+		return d
+	}
+	for name := range schedules {
+		depth(name)
+	}
+	return depths
+}
+
+// dispatchCorrections adds the corrective jobs of the first wave and adds
+// each following wave once every job of the previous one has finished.
+func (v *Validator) dispatchCorrections(waves [][]correction) {
+	if len(waves) == 0 {
+		return
+	}
+
+	wave, rest := waves[0], waves[1:]
+	var mu sync.Mutex
+	pending := len(wave)
+	finished := func() {
+		mu.Lock()
+		pending--
+		last := pending == 0
+		mu.Unlock()
+		if last {
+			v.dispatchCorrections(rest)
+		}
+	}
+
+	for _, c := range wave {
+		run := v.executor.Make(v.stateChecker, v.operator, c.sch, c.action, v.dryRun, v.metrics)
+		job := func() {
+			defer finished()
+			run()
+		}
+		if err := v.scheduler.AddOneTimeJob(c.jobName, job); err != nil {
+			log.Error().Err(err).
+				Str("schedule", c.sch.Name).
+				Str("resource_type", c.sch.Resource.Type).
+				Str("resource_id", c.sch.Resource.ID).
+				Str("action", c.action).
+				Msg("Failed to create corrective job")
+			finished()
+			continue
+		}
+		if v.metrics != nil {
+			v.metrics.IncValidatorCorrection(c.sch.Resource.Type, c.action)
+		}
+		log.Info().
+			Str("schedule", c.sch.Name).
+			Str("resource_type", c.sch.Resource.Type).
+			Str("resource_id", c.sch.Resource.ID).
+			Str("action", c.action).
+			Int("waiting_corrections", countCorrections(rest)).
+			Msg("Corrective job created")
+	}
+}
+
+func countCorrections(waves [][]correction) int {
+	n := 0
+	for _, wave := range waves {
+		n += len(wave)
+	}
+	return n
+}
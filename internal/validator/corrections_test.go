@@ -0,0 +1,107 @@
+package validator
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/resource"
+	"github.com/sentoz/yc-sheduler/internal/scheduler"
+)
+
+func TestCorrectionWaves(t *testing.T) {
+	t.Parallel()
+
+	db := config.Schedule{Name: "db", Resource: config.Resource{Type: "vm", ID: "db-vm"}}
+	app := config.Schedule{Name: "app", DependsOn: []string{"db"}, Resource: config.Resource{Type: "vm", ID: "app-vm"}}
+	cluster := config.Schedule{Name: "k8s", Resource: config.Resource{Type: "k8s_cluster", ID: "cluster"}}
+	nodes := config.Schedule{Name: "k8s", Resource: config.Resource{Type: "k8s_node_group", ID: "nodes"}}
+
+	jobs := func(waves [][]correction) [][]string {
+		var names [][]string
+		for _, wave := range waves {
+			var wn []string
+			for _, c := range wave {
+				wn = append(wn, c.jobName)
+			}
+			slices.Sort(wn)
+			names = append(names, wn)
+		}
+		return names
+	}
+
+	starts := correctionWaves([]correction{
+		{sch: app, action: "start", jobName: "app"},
+		{sch: db, action: "start", jobName: "db"},
+	})
+	if got, want := jobs(starts), [][]string{{"db"}, {"app"}}; !slices.EqualFunc(got, want, slices.Equal) {
+		t.Fatalf("start waves = %v, want %v", got, want)
+	}
+
+	stops := correctionWaves([]correction{
+		{sch: cluster, action: "stop", jobName: "cluster"},
+		{sch: nodes, action: "stop", jobName: "nodes"},
+	})
+	if got, want := jobs(stops), [][]string{{"nodes"}, {"cluster"}}; !slices.EqualFunc(got, want, slices.Equal) {
+		t.Fatalf("stop waves = %v, want %v", got, want)
+	}
+}
+
+type recordingScheduler struct {
+	scheduler.Interface
+	mu    sync.Mutex
+	order []string
+	wg    sync.WaitGroup
+}
+
+func (s *recordingScheduler) AddOneTimeJob(name string, fn func()) error {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.mu.Lock()
+		s.order = append(s.order, name)
+		s.mu.Unlock()
+		fn()
+	}()
+	return nil
+}
+
+type stoppedChecker struct{}
+
+func (stoppedChecker) GetState(context.Context, config.Resource) (string, bool, error) {
+	return "stopped", false, nil
+}
+
+type nopOperator struct{ resource.Operator }
+
+func (nopOperator) Start(context.Context, config.Resource, resource.StartOptions) error { return nil }
+
+func TestDispatchCorrectionsRunsWavesInOrder(t *testing.T) {
+	t.Parallel()
+
+	sched := &recordingScheduler{}
+	v := New(stoppedChecker{}, nopOperator{}, &config.Config{}, sched, nil, false)
+
+	db := config.Schedule{Name: "db", Resource: config.Resource{Type: "vm", ID: "db-vm"}}
+	app := config.Schedule{Name: "app", DependsOn: []string{"db"}, Resource: config.Resource{Type: "vm", ID: "app-vm"}}
+	v.dispatchCorrections(correctionWaves([]correction{
+		{sch: app, action: "start", jobName: "app:validator:start"},
+		{sch: db, action: "start", jobName: "db:validator:start"},
+	}))
+
+	// Waves are added by finished jobs, so wait until none are running.
+	for {
+		sched.wg.Wait()
+		sched.mu.Lock()
+		n := len(sched.order)
+		sched.mu.Unlock()
+		if n == 2 {
+			break
+		}
+	}
+	if want := []string{"db:validator:start", "app:validator:start"}; !slices.Equal(sched.order, want) {
+		t.Fatalf("job order = %v, want %v", sched.order, want)
+	}
+}
@@ -0,0 +1,86 @@
+package validator
+
+import (
+	"maps"
+
+	"github.com/rs/zerolog"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/resource"
+)
+
+// Causes of resource state mismatches found by the validator.
+const (
+	// DriftScheduler marks resources left in their state by the last action
+	// of the scheduler itself, e.g. of another schedule of the resource or a
+	// correction of the expected state hint.
+	DriftScheduler = "scheduler"
+	// DriftExternal marks resources whose state changed since the last
+	// action of the scheduler, e.g. by a user in the console.
+	DriftExternal = "external"
+	// DriftUnknown marks resources without a recorded state-changing action
+	// of the scheduler.
+	DriftUnknown = "unknown"
+)
+
+// Drift is a resource state mismatch found by the last validation run.
+type Drift struct {
+	Resource config.Resource
+	Expected string
+	Actual   string
+	// Cause is DriftScheduler, DriftExternal or DriftUnknown.
+	Cause string
+}
+
+// Drifts returns the state mismatches found by the last validation run by
+// schedule name. Schedules the run skipped, e.g. paused ones, have none.
+func (v *Validator) Drifts() map[string][]Drift {
+	if v == nil {
+		return nil
+	}
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return maps.Clone(v.drifts)
+}
+
+func (v *Validator) setDrifts(drifts map[string][]Drift) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.drifts = drifts
+}
+
+// drift is the attributed cause of a resource state mismatch.
+type drift struct {
+	cause string
+	last  resource.LastAction
+}
+
+// attributeDrift compares the actual state of a mismatched resource with the
+// state the last action of the scheduler left it in.
+func (v *Validator) attributeDrift(target config.Resource, actualState string) drift {
+	last, ok := v.getLastActions().LastAction(target)
+	switch {
+	case !ok || last.ResultState() == "":
+		return drift{cause: DriftUnknown}
+	case last.ResultState() == actualState:
+		return drift{cause: DriftScheduler, last: last}
+	default:
+		return drift{cause: DriftExternal, last: last}
+	}
+}
+
+// fields adds the cause and the last action of the scheduler to a log event.
+func (d drift) fields(e *zerolog.Event) *zerolog.Event {
+	e = e.Str("drift_cause", d.cause)
+	if d.last.Action == "" {
+		return e
+	}
+	return e.
+		Str("last_action", d.last.Action).
+		Str("last_action_schedule", d.last.Schedule).
+		Time("last_action_at", d.last.At).
+		Str("last_operation_id", d.last.OperationID)
+}
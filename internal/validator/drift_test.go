@@ -0,0 +1,46 @@
+package validator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/resource"
+)
+
+func TestAttributeDrift(t *testing.T) {
+	t.Parallel()
+
+	actions, err := resource.NewActionStore("")
+	if err != nil {
+		t.Fatalf("NewActionStore() error = %v", err)
+	}
+	at := time.Date(2026, time.May, 4, 9, 0, 0, 0, time.UTC)
+	for _, a := range []resource.LastAction{
+		{At: at, ResourceType: "vm", ResourceID: "stopped-by-scheduler", Schedule: "other", Action: "stop", OperationID: "op-1"},
+		{At: at, ResourceType: "vm", ResourceID: "started-by-user", Schedule: "dev", Action: "stop", OperationID: "op-2"},
+		{At: at, ResourceType: "vm", ResourceID: "snapshotted", Schedule: "backup", Action: "snapshot"},
+	} {
+		if err := actions.Record(a); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	v := New(stoppedChecker{}, nopOperator{}, &config.Config{}, &recordingScheduler{}, nil, false)
+	v.SetLastActions(actions)
+
+	tests := []struct {
+		id, actual, want string
+	}{
+		{id: "stopped-by-scheduler", actual: "stopped", want: DriftScheduler},
+		{id: "started-by-user", actual: "running", want: DriftExternal},
+		{id: "snapshotted", actual: "running", want: DriftUnknown},
+		{id: "never-touched", actual: "running", want: DriftUnknown},
+	}
+	for _, tt := range tests {
+		got := v.attributeDrift(config.Resource{Type: "vm", ID: tt.id}, tt.actual)
+		if got.cause != tt.want {
+			t.Errorf("attributeDrift(%s, %s) = %q, want %q", tt.id, tt.actual, got.cause, tt.want)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package validator
+
+import (
+	"context"
+	"slices"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/resource"
+)
+
+// applyLabelHint combines the schedule-derived expectation with the desired
+// state hint from resource labels according to the namespace priority.
+// Without expected_state configuration the schedule expectation is returned.
+func (v *Validator) applyLabelHint(ctx context.Context, sch config.Schedule, state, action string) (string, string) {
+	cfg := v.getConfig().ExpectedState
+	if cfg == nil {
+		return state, action
+	}
+
+	reader, ok := v.stateChecker.(resource.LabelReader)
+	if !ok {
+		return state, action
+	}
+
+	labels, err := reader.GetLabels(ctx, sch.Resource)
+	if err != nil {
+		log.Warn().Err(err).
+			Str("schedule", sch.Name).
+			Str("resource_type", sch.Resource.Type).
+			Str("resource_id", sch.Resource.ID).
+			Msg("Failed to read resource labels, using schedule expectation")
+		return state, action
+	}
+
+	hint := labels[cfg.LabelKey()]
+	if hint != "" && hintAction(hint) == "" {
+		log.Warn().
+			Str("schedule", sch.Name).
+			Str("resource_type", sch.Resource.Type).
+			Str("resource_id", sch.Resource.ID).
+			Str("label", cfg.LabelKey()).
+			Str("value", hint).
+			Msg("Ignoring unknown desired state label value")
+	}
+
+	return resolveExpectedState(state, action, hint, cfg.PriorityFor(sch.Namespace))
+}
+
+// resolveExpectedState returns the winning expected state and action. The
+// label hint is used when the schedule has no expectation or when the label
+// source has priority; unknown hint values are ignored.
+func resolveExpectedState(state, action, hint string, priority config.StateSource) (string, string) {
+	labelAction := hintAction(hint)
+	if labelAction == "" {
+		return state, action
+	}
+	if action == "" || priority == config.StateSourceLabel {
+		return hint, labelAction
+	}
+	return state, action
+}
+
+// hintAction maps a desired state hint to the action that reaches it.
+func hintAction(hint string) string {
+	switch hint {
+	case "running":
+		return "start"
+	case "stopped":
+		return "stop"
+	default:
+		return ""
+	}
+}
+
+// ExpectedRunning returns the IDs of the resources of resourceType that an
+// active schedule currently expects to be running, so the validator would
+// start them again after they are stopped, e.g. by the idle policy.
+func (v *Validator) ExpectedRunning(ctx context.Context, resourceType string) map[string]bool {
+	now := v.clock.Now()
+	set := v.getScheduleSets().Load()
+	running := make(map[string]bool)
+	for _, sch := range set.Schedules() {
+		if v.superseded(set, sch.Name) || !sch.ActiveAt(now) {
+			continue
+		}
+		if _, paused := v.getPauses().Paused(sch.Labels); paused {
+			continue
+		}
+		if _, onVacation := v.getVacations().OnVacation(sch.Namespace); onVacation {
+			continue
+		}
+		if !slices.ContainsFunc(sch.Targets(), func(target config.Resource) bool { return target.Type == resourceType }) {
+			continue
+		}
+
+		targets, err := resource.ResolveTargets(ctx, v.stateChecker, sch.Targets())
+		if err != nil {
+			log.Warn().Err(err).
+				Str("schedule", sch.Name).
+				Msg("Failed to resolve schedule resources, skipping expected state")
+			continue
+		}
+		for _, target := range targets {
+			if target.Type != resourceType {
+				continue
+			}
+			if _, denied := v.executor.DeniedReason(sch, target); denied {
+				continue
+			}
+			targetSchedule := sch.ForResource(target)
+			state, action := v.determineExpectedState(targetSchedule, now)
+			if _, action = v.applyLabelHint(ctx, targetSchedule, state, action); action == "start" {
+				running[target.ID] = true
+			}
+		}
+	}
+	return running
+}
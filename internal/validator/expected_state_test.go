@@ -0,0 +1,213 @@
+package validator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/scheduler"
+)
+
+func TestResolveExpectedState(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		state      string
+		action     string
+		hint       string
+		priority   config.StateSource
+		wantState  string
+		wantAction string
+	}{
+		{
+			name: "schedule wins", state: "stopped", action: "stop", hint: "running",
+			priority: config.StateSourceSchedule, wantState: "stopped", wantAction: "stop",
+		},
+		{
+			name: "label wins", state: "stopped", action: "stop", hint: "running",
+			priority: config.StateSourceLabel, wantState: "running", wantAction: "start",
+		},
+		{
+			name: "label fills missing schedule expectation", hint: "stopped",
+			priority: config.StateSourceSchedule, wantState: "stopped", wantAction: "stop",
+		},
+		{
+			name: "unknown hint ignored", state: "running", action: "start", hint: "paused",
+			priority: config.StateSourceLabel, wantState: "running", wantAction: "start",
+		},
+		{
+			name: "no hint", state: "running", action: "start",
+			priority: config.StateSourceLabel, wantState: "running", wantAction: "start",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			state, action := resolveExpectedState(tt.state, tt.action, tt.hint, tt.priority)
+			if state != tt.wantState || action != tt.wantAction {
+				t.Fatalf("resolveExpectedState() = (%q, %q), want (%q, %q)", state, action, tt.wantState, tt.wantAction)
+			}
+		})
+	}
+}
+
+func TestExpectedStatePriorityFor(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.ExpectedStateConfig{
+		Priority:   config.StateSourceSchedule,
+		Namespaces: map[string]config.StateSource{"legacy": config.StateSourceLabel},
+	}
+
+	if got := cfg.PriorityFor("legacy"); got != config.StateSourceLabel {
+		t.Fatalf("PriorityFor(legacy) = %q, want %q", got, config.StateSourceLabel)
+	}
+	if got := cfg.PriorityFor("team-a"); got != config.StateSourceSchedule {
+		t.Fatalf("PriorityFor(team-a) = %q, want %q", got, config.StateSourceSchedule)
+	}
+	if got := (*config.ExpectedStateConfig)(nil).LabelKey(); got != "desired-state" {
+		t.Fatalf("LabelKey() = %q, want desired-state", got)
+	}
+}
+
+func TestDetermineExpectedStateDefersWithinJitter(t *testing.T) {
+	t.Parallel()
+
+	v := &Validator{cfg: &config.Config{}}
+	sch := config.Schedule{
+		Name: "vm",
+		Type: "daily",
+		Actions: config.Actions{
+			Start: &config.ActionConfig{Enabled: true, Time: "09:00"},
+			Stop:  &config.ActionConfig{Enabled: true, Time: "20:00"},
+		},
+		Jitter: config.Duration{Duration: 5 * time.Minute},
+	}
+
+	within := time.Date(2026, time.May, 4, 9, 3, 0, 0, time.Local)
+	if state, action := v.determineExpectedState(sch, within); action != "" {
+		t.Fatalf("determineExpectedState() within jitter = (%q, %q), want no expectation", state, action)
+	}
+
+	after := within.Add(5 * time.Minute)
+	if state, action := v.determineExpectedState(sch, after); state != "running" || action != "start" {
+		t.Fatalf("determineExpectedState() after jitter = (%q, %q), want (running, start)", state, action)
+	}
+}
+
+func TestDetermineExpectedStateBusinessHours(t *testing.T) {
+	t.Parallel()
+
+	v := &Validator{cfg: &config.Config{}}
+	sch := config.Schedule{
+		Name: "office",
+		Type: "weekly",
+		Actions: config.Actions{
+			Start: &config.ActionConfig{Enabled: true, Time: "09:00", Days: []int{1, 2, 3, 4, 5}},
+			Stop:  &config.ActionConfig{Enabled: true, Time: "19:00", Days: []int{1, 2, 3, 4, 5}},
+		},
+		BusinessHours: &config.BusinessHours{Start: "09:00", End: "19:00", Days: []int{1, 2, 3, 4, 5}},
+	}
+
+	tests := []struct {
+		at    time.Time
+		state string
+	}{
+		{at: time.Date(2026, time.May, 4, 12, 0, 0, 0, time.Local), state: "running"},
+		{at: time.Date(2026, time.May, 4, 8, 59, 0, 0, time.Local), state: "stopped"},
+		{at: time.Date(2026, time.May, 4, 19, 0, 0, 0, time.Local), state: "stopped"},
+		{at: time.Date(2026, time.May, 9, 12, 0, 0, 0, time.Local), state: "stopped"},
+	}
+	for _, tt := range tests {
+		if state, _ := v.determineExpectedState(sch, tt.at); state != tt.state {
+			t.Fatalf("determineExpectedState(%s) = %q, want %q", tt.at, state, tt.state)
+		}
+	}
+}
+
+// missedScheduler reports the runs skipped as missed at the given times by
+// schedule action.
+type missedScheduler struct {
+	scheduler.Interface
+	missed map[string]time.Time
+}
+
+func (s missedScheduler) MissedSince(schedule, action string, since time.Time) bool {
+	at, ok := s.missed[schedule+":"+action]
+	return ok && !at.Before(since)
+}
+
+func TestDetermineExpectedStateSkipsMissedAction(t *testing.T) {
+	t.Parallel()
+
+	sch := config.Schedule{
+		Name: "vm",
+		Type: "daily",
+		Actions: config.Actions{
+			Start: &config.ActionConfig{Enabled: true, Time: "09:00"},
+			Stop:  &config.ActionConfig{Enabled: true, Time: "20:00", RunWithin: config.Duration{Duration: 30 * time.Minute}},
+		},
+	}
+	// The process was down at 20:00 and came back at 2am.
+	late := time.Date(2026, time.May, 5, 2, 0, 0, 0, time.Local)
+
+	// A stop that ran on time is corrected long after run_within.
+	ran := &Validator{cfg: &config.Config{}, scheduler: missedScheduler{}}
+	if state, action := ran.determineExpectedState(sch, late); state != "stopped" || action != "stop" {
+		t.Fatalf("determineExpectedState() after an executed stop = (%q, %q), want (stopped, stop)", state, action)
+	}
+
+	// A stop of a previous day skipped as missed does not matter.
+	previous := &Validator{cfg: &config.Config{}, scheduler: missedScheduler{missed: map[string]time.Time{
+		"vm:stop": time.Date(2026, time.May, 3, 2, 0, 0, 0, time.Local),
+	}}}
+	if state, action := previous.determineExpectedState(sch, late); state != "stopped" || action != "stop" {
+		t.Fatalf("determineExpectedState() after an earlier missed stop = (%q, %q), want (stopped, stop)", state, action)
+	}
+
+	// The stop the scheduler skipped as missed is not run late by a correction.
+	missed := &Validator{cfg: &config.Config{}, scheduler: missedScheduler{missed: map[string]time.Time{"vm:stop": late}}}
+	if state, action := missed.determineExpectedState(sch, late); action != "" {
+		t.Fatalf("determineExpectedState() after a missed stop = (%q, %q), want no expectation", state, action)
+	}
+
+	// The next start is corrected as before.
+	morning := time.Date(2026, time.May, 5, 11, 0, 0, 0, time.Local)
+	if state, action := missed.determineExpectedState(sch, morning); state != "running" || action != "start" {
+		t.Fatalf("determineExpectedState() after start = (%q, %q), want (running, start)", state, action)
+	}
+}
+
+func TestDetermineExpectedStateAfterRestartPastRunWithin(t *testing.T) {
+	t.Parallel()
+
+	sch := config.Schedule{
+		Name:     "vm",
+		Type:     "daily",
+		Resource: config.Resource{Type: "vm", ID: "vm-1"},
+		Actions: config.Actions{
+			Start: &config.ActionConfig{Enabled: true, Time: "09:00"},
+			Stop:  &config.ActionConfig{Enabled: true, Time: "20:00", RunWithin: config.Duration{Duration: 30 * time.Minute}},
+		},
+	}
+	// The process was down at 20:00 and restarts at 2am.
+	late := time.Date(2026, time.May, 5, 2, 0, 0, 0, time.Local)
+	sched, err := scheduler.NewWithClock("Local", 1, clockwork.NewFakeClockAt(late))
+	if err != nil {
+		t.Fatalf("NewWithClock() error = %v", err)
+	}
+	cfg := &config.Config{Schedules: []config.Schedule{sch}}
+	if err := sched.RegisterSchedules(stoppedChecker{}, nopOperator{}, cfg, false, nil); err != nil {
+		t.Fatalf("RegisterSchedules() error = %v", err)
+	}
+
+	v := &Validator{cfg: &config.Config{}, scheduler: sched}
+	if state, action := v.determineExpectedState(sch, late); action != "" {
+		t.Fatalf("determineExpectedState() after restart = (%q, %q), want no expectation", state, action)
+	}
+}
@@ -0,0 +1,142 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Incident mode is enabled automatically when at least circuitBreakerRatio of
+// the state checks of a validation pass fail because the cloud API is
+// unavailable. Passes with fewer than circuitBreakerMinChecks checks are too
+// small to tell an outage from a few failing resources.
+const (
+	circuitBreakerRatio     = 0.5
+	circuitBreakerMinChecks = 5
+)
+
+// IncidentReasonCircuitBreaker is the incident reason used when incident mode
+// is enabled automatically by the circuit breaker.
+const IncidentReasonCircuitBreaker = "circuit_breaker"
+
+// IncidentState describes the validator incident mode.
+// While incident mode is active, corrective jobs are not created and state
+// mismatches are only recorded as observations.
+type IncidentState struct {
+	Since  time.Time `json:"since,omitzero"`
+	Reason string    `json:"reason,omitempty"`
+	Active bool      `json:"active"`
+	// Automatic is true when incident mode was enabled by the circuit breaker.
+	// Automatic incidents end after the next pass below the failure ratio.
+	Automatic bool `json:"automatic,omitempty"`
+}
+
+// IncidentMode returns the current incident mode state.
+func (v *Validator) IncidentMode() IncidentState {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return v.incident
+}
+
+// SetIncidentMode enables or disables incident mode manually.
+func (v *Validator) SetIncidentMode(active bool, reason string) IncidentState {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.setIncidentUnlocked(active, reason, false)
+	return v.incident
+}
+
+// recordStateCheck counts a state check of the current validation pass for
+// the circuit breaker. Only failures of an unavailable cloud API count as
+// failed; other errors, such as a missing resource, show the API answers.
+func (v *Validator) recordStateCheck(err error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.passChecks++
+	if unavailable(err) {
+		v.passFailures++
+	}
+}
+
+// evaluateCircuitBreaker opens the circuit breaker when the failure ratio of
+// the state checks of the finished validation pass reaches
+// circuitBreakerRatio, closes an automatic incident otherwise, and resets the
+// counts for the next pass. It reports whether the breaker opened.
+func (v *Validator) evaluateCircuitBreaker() bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	checks, failures := v.passChecks, v.passFailures
+	v.passChecks, v.passFailures = 0, 0
+	if checks < circuitBreakerMinChecks {
+		return false
+	}
+
+	if float64(failures)/float64(checks) >= circuitBreakerRatio {
+		if v.incident.Active {
+			return false
+		}
+		log.Warn().
+			Int("checks", checks).
+			Int("failures", failures).
+			Msg("State checks of the validation pass failed with the cloud API unavailable")
+		v.setIncidentUnlocked(true, IncidentReasonCircuitBreaker, true)
+		return true
+	}
+	if v.incident.Active && v.incident.Automatic {
+		v.setIncidentUnlocked(false, "", true)
+	}
+	return false
+}
+
+// unavailable reports whether err shows the cloud API is unavailable or does
+// not answer in time.
+func unavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+func (v *Validator) setIncidentUnlocked(active bool, reason string, automatic bool) {
+	if active == v.incident.Active && reason == v.incident.Reason {
+		return
+	}
+
+	if active {
+		v.incident = IncidentState{
+			Active:    true,
+			Reason:    reason,
+			Since:     time.Now(),
+			Automatic: automatic,
+		}
+		log.Warn().
+			Str("reason", reason).
+			Bool("automatic", automatic).
+			Msg("Validator incident mode enabled, corrections are suspended")
+	} else {
+		v.incident = IncidentState{}
+		log.Info().
+			Bool("automatic", automatic).
+			Msg("Validator incident mode disabled, corrections are resumed")
+	}
+
+	if v.metrics != nil {
+		v.metrics.SetIncidentMode(active)
+	}
+}
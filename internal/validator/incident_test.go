@@ -0,0 +1,98 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// recordPass records a validation pass with the given state check errors and
+// evaluates the circuit breaker.
+func recordPass(v *Validator, errs ...error) bool {
+	for _, err := range errs {
+		v.recordStateCheck(err)
+	}
+	return v.evaluateCircuitBreaker()
+}
+
+func TestCircuitBreakerTogglesIncidentMode(t *testing.T) {
+	t.Parallel()
+
+	unavailable := status.Error(codes.Unavailable, "service unavailable")
+	v := &Validator{}
+
+	// Two of five checks failing stays below the ratio.
+	if recordPass(v, nil, nil, nil, unavailable, unavailable) || v.IncidentMode().Active {
+		t.Fatal("incident mode enabled below the failure ratio")
+	}
+
+	if !recordPass(v, nil, nil, unavailable, unavailable, unavailable) {
+		t.Fatal("evaluateCircuitBreaker() = false for a pass at the failure ratio")
+	}
+	state := v.IncidentMode()
+	if !state.Active || !state.Automatic || state.Reason != IncidentReasonCircuitBreaker {
+		t.Fatalf("IncidentMode() = %+v, want automatic circuit breaker incident", state)
+	}
+
+	// A failing pass keeps the incident without opening it again.
+	if recordPass(v, unavailable, unavailable, unavailable, unavailable, unavailable) || !v.IncidentMode().Active {
+		t.Fatal("failing pass reopened or closed the incident")
+	}
+
+	recordPass(v, nil, nil, nil, nil, unavailable)
+	if v.IncidentMode().Active {
+		t.Fatal("automatic incident mode not closed after a pass below the failure ratio")
+	}
+}
+
+func TestCircuitBreakerCountsOnlyUnavailableAPI(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{err: status.Error(codes.Unavailable, "service unavailable"), want: true},
+		{err: fmt.Errorf("yc: get instance fhm1: %w", status.Error(codes.DeadlineExceeded, "deadline")), want: true},
+		{err: fmt.Errorf("get state: %w", context.DeadlineExceeded), want: true},
+		{err: status.Error(codes.NotFound, "instance not found"), want: false},
+		{err: status.Error(codes.PermissionDenied, "permission denied"), want: false},
+		{err: errors.New("unsupported resource type"), want: false},
+	}
+	for _, tt := range tests {
+		v := &Validator{}
+		recordPass(v, tt.err, tt.err, tt.err, tt.err, tt.err)
+		if got := v.IncidentMode().Active; got != tt.want {
+			t.Errorf("incident mode after a pass failing with %v = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestCircuitBreakerIgnoresSmallPasses(t *testing.T) {
+	t.Parallel()
+
+	unavailable := status.Error(codes.Unavailable, "service unavailable")
+	v := &Validator{}
+	for range 3 {
+		recordPass(v, unavailable, unavailable)
+	}
+	if v.IncidentMode().Active {
+		t.Fatal("incident mode enabled by passes below the minimum number of checks")
+	}
+}
+
+func TestManualIncidentModeSurvivesSuccessfulChecks(t *testing.T) {
+	t.Parallel()
+
+	v := &Validator{}
+	v.SetIncidentMode(true, "region outage")
+	recordPass(v, nil, nil, nil, nil, nil)
+
+	if state := v.IncidentMode(); !state.Active || state.Reason != "region outage" {
+		t.Fatalf("IncidentMode() = %+v, want manual incident kept", state)
+	}
+}
@@ -2,18 +2,26 @@ package validator
 
 import (
 	"context"
-	"fmt"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/jonboulle/clockwork"
 	"github.com/rs/zerolog/log"
 
+	"github.com/sentoz/yc-sheduler/internal/blackout"
 	"github.com/sentoz/yc-sheduler/internal/config"
 	"github.com/sentoz/yc-sheduler/internal/executor"
+	"github.com/sentoz/yc-sheduler/internal/grace"
+	"github.com/sentoz/yc-sheduler/internal/logger"
 	"github.com/sentoz/yc-sheduler/internal/metrics"
+	"github.com/sentoz/yc-sheduler/internal/pause"
 	"github.com/sentoz/yc-sheduler/internal/resource"
 	"github.com/sentoz/yc-sheduler/internal/schedule"
 	"github.com/sentoz/yc-sheduler/internal/scheduler"
+	"github.com/sentoz/yc-sheduler/internal/scheduleset"
+	"github.com/sentoz/yc-sheduler/internal/vacation"
 )
 
 // Interface defines the interface for validator operations.
@@ -26,12 +34,26 @@ type Interface interface {
 type Validator struct {
 	stateChecker resource.StateChecker
 	operator     resource.Operator
+	executor     *executor.Executor
 	scheduler    scheduler.Interface
 	cfg          *config.Config
 	metrics      *metrics.Metrics
-	schedules    []config.Schedule
+	schedules    *scheduleset.Store
+	incident     IncidentState
+	pauses       *pause.Registry
+	vacations    *vacation.Manager
+	blackouts    *blackout.Calendar
+	stops        *grace.Registry
+	lastActions  *resource.ActionStore
+	clock        clockwork.Clock
 	mu           sync.RWMutex
 	dryRun       bool
+
+	passChecks       int
+	passFailures     int
+	backpressure     bool
+	evaluatedVersion atomic.Uint64
+	drifts           map[string][]Drift
 }
 
 // Ensure Validator implements Interface.
@@ -43,11 +65,13 @@ func New(stateChecker resource.StateChecker, operator resource.Operator, cfg *co
 	v := &Validator{
 		stateChecker: stateChecker,
 		operator:     operator,
+		executor:     executor.New(),
 		cfg:          cfg,
 		scheduler:    sched,
 		metrics:      m,
 		dryRun:       dryRun,
-		schedules:    append([]config.Schedule(nil), cfg.Schedules...),
+		schedules:    scheduleset.NewStore(cfg.Schedules),
+		clock:        clockwork.NewRealClock(),
 	}
 	log.Info().
 		Int("schedules", len(cfg.Schedules)).
@@ -55,16 +79,149 @@ func New(stateChecker resource.StateChecker, operator resource.Operator, cfg *co
 	return v
 }
 
-// UpdateSchedules replaces schedules used by validation loop.
-func (v *Validator) UpdateSchedules(schedules []config.Schedule) {
+// UpdateSchedules publishes schedules used by validation loop as a new set
+// and returns it. Schedules the set removes or changes are dropped from a
+// validation run already in progress.
+func (v *Validator) UpdateSchedules(schedules []config.Schedule) *scheduleset.Set {
 	if v == nil {
-		return
+		return nil
+	}
+
+	return v.getScheduleSets().Replace(schedules)
+}
+
+// EvaluatedVersion returns the version of the schedule set the validator last
+// evaluated, or 0 if it has not completed a run yet.
+func (v *Validator) EvaluatedVersion() uint64 {
+	if v == nil {
+		return 0
 	}
+	return v.evaluatedVersion.Load()
+}
+
+// SetScheduleSets makes the validator read schedules from sets shared with
+// the rest of the application, so a reload publishes them to all readers at
+// once.
+func (v *Validator) SetScheduleSets(sets *scheduleset.Store) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.schedules = sets
+}
+
+func (v *Validator) getScheduleSets() *scheduleset.Store {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return v.schedules
+}
+
+// SetConfig replaces the configuration, e.g. after the config file was
+// reloaded. Schedules are published separately through the schedule sets.
+func (v *Validator) SetConfig(cfg *config.Config) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.cfg = cfg
+}
 
+func (v *Validator) getConfig() *config.Config {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return v.cfg
+}
+
+// SetPauses sets the registry of schedule pauses. Paused schedules are not validated.
+func (v *Validator) SetPauses(pauses *pause.Registry) {
 	v.mu.Lock()
 	defer v.mu.Unlock()
 
-	v.schedules = append([]config.Schedule(nil), schedules...)
+	v.pauses = pauses
+}
+
+func (v *Validator) getPauses() *pause.Registry {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return v.pauses
+}
+
+// SetVacations sets the namespace vacations. Schedules of a namespace on
+// vacation are not validated.
+func (v *Validator) SetVacations(vacations *vacation.Manager) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.vacations = vacations
+}
+
+func (v *Validator) getVacations() *vacation.Manager {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return v.vacations
+}
+
+// SetBlackouts sets the blackout windows. No resources are validated while
+// a window is active.
+func (v *Validator) SetBlackouts(blackouts *blackout.Calendar) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.blackouts = blackouts
+}
+
+func (v *Validator) getBlackouts() *blackout.Calendar {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return v.blackouts
+}
+
+// SetStops sets the registry of announced stops. Schedules with a postponed
+// stop are not validated until the postponement ends.
+func (v *Validator) SetStops(stops *grace.Registry) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.stops = stops
+}
+
+func (v *Validator) getStops() *grace.Registry {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return v.stops
+}
+
+// SetLastActions sets the last actions of the scheduler on resources. State
+// mismatches are attributed to the scheduler or to external changes by them.
+func (v *Validator) SetLastActions(actions *resource.ActionStore) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.lastActions = actions
+}
+
+func (v *Validator) getLastActions() *resource.ActionStore {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return v.lastActions
+}
+
+// SetExecutor sets the executor running corrective actions. Sharing it with
+// the scheduler keeps corrections from overlapping scheduled operations on
+// the same resource. It must be called before Start.
+func (v *Validator) SetExecutor(e *executor.Executor) {
+	v.executor = e
+}
+
+// SetClock sets the clock driving validation runs and expected states.
+// It must be called before Start.
+func (v *Validator) SetClock(clock clockwork.Clock) {
+	v.clock = clock
 }
 
 // Start runs validation in the background until the context is canceled.
@@ -78,7 +235,7 @@ func (v *Validator) Start(ctx context.Context, interval time.Duration) {
 			Dur("interval", interval).
 			Msg("Validator loop started")
 
-		ticker := time.NewTicker(interval)
+		ticker := v.clock.NewTicker(interval)
 		defer ticker.Stop()
 
 		for {
@@ -86,103 +243,243 @@ func (v *Validator) Start(ctx context.Context, interval time.Duration) {
 			case <-ctx.Done():
 				log.Info().Msg("Validator loop stopped")
 				return
-			case <-ticker.C:
+			case <-ticker.Chan():
 				v.runOnce(ctx)
 			}
 		}
 	}()
 }
 
-func (v *Validator) runOnce(ctx context.Context) {
-	now := time.Now()
-	schedules := v.getSchedulesSnapshot()
+// logComponent names the validator for sampling of its per-resource
+// messages.
+const logComponent = "validator"
 
-	for _, sch := range schedules {
-		log.Trace().
-			Str("schedule", sch.Name).
-			Str("resource_type", sch.Resource.Type).
-			Str("resource_id", sch.Resource.ID).
-			Time("now", now).
-			Msg("Validator is about to check resource state")
+func (v *Validator) runOnce(ctx context.Context) {
+	sampled := logger.Sampled(logComponent)
+	now := v.clock.Now()
+	if w, active := v.getBlackouts().Active(now); active {
+		sampled.Debug().
+			Str("window", w.Name).
+			Str("reason", w.Reason).
+			Msg("Blackout window is active, skipping validation")
+		return
+	}
+	if v.backpressured() {
+		sampled.Debug().Msg("Job backlog is too large, skipping validation")
+		return
+	}
+	set := v.getScheduleSets().Load()
 
-		actualState, isTransitional, err := v.stateChecker.GetState(ctx, sch.Resource)
-		if err != nil {
-			log.Warn().Err(err).
+	var corrections []correction
+	drifts := make(map[string][]Drift)
+	for _, sch := range set.Schedules() {
+		if v.superseded(set, sch.Name) {
+			sampled.Debug().
 				Str("schedule", sch.Name).
-				Str("resource_type", sch.Resource.Type).
-				Str("resource_id", sch.Resource.ID).
-				Msg("Failed to get actual resource state")
+				Msg("Schedule was removed or changed by a reload, skipping validation")
 			continue
 		}
-
-		// If resource is in transitional state, skip validation and wait for stable state
-		if isTransitional {
-			log.Debug().
+		if !sch.ActiveAt(now) {
+			sampled.Debug().
 				Str("schedule", sch.Name).
-				Str("resource_type", sch.Resource.Type).
-				Str("resource_id", sch.Resource.ID).
-				Str("current_state", actualState).
-				Msg("Resource is in transitional state, deferring validation until stable")
+				Msg("Schedule is not active, skipping validation")
 			continue
 		}
-
-		// Determine expected state based on schedule and current time
-		expectedState, expectedAction := v.determineExpectedState(sch, now)
-		if expectedAction == "" {
-			log.Debug().
+		if p, paused := v.getPauses().Paused(sch.Labels); paused {
+			sampled.Debug().
+				Str("schedule", sch.Name).
+				Str("pause_id", p.ID).
+				Msg("Schedule is paused, skipping validation")
+			continue
+		}
+		if vac, onVacation := v.getVacations().OnVacation(sch.Namespace); onVacation {
+			sampled.Debug().
+				Str("schedule", sch.Name).
+				Str("vacation_id", vac.ID).
+				Msg("Schedule namespace is on vacation, skipping validation")
+			continue
+		}
+		if stop, postponed := v.getStops().Postponed(sch.Name, now); postponed {
+			sampled.Debug().
 				Str("schedule", sch.Name).
-				Str("resource_type", sch.Resource.Type).
-				Str("resource_id", sch.Resource.ID).
-				Str("actual_state", actualState).
-				Msg("No corrective action needed")
+				Time("until", stop.Until).
+				Msg("Schedule stop is postponed, skipping validation")
 			continue
 		}
 
-		if actualState != expectedState {
-			log.Warn().
+		targets, err := resource.ResolveTargets(ctx, v.stateChecker, sch.Targets())
+		if err != nil {
+			log.Warn().Err(err).
 				Str("schedule", sch.Name).
-				Str("resource_type", sch.Resource.Type).
-				Str("resource_id", sch.Resource.ID).
-				Str("expected_state", expectedState).
-				Str("actual_state", actualState).
-				Str("corrective_action", expectedAction).
-				Msg("State mismatch detected, creating corrective job")
-
-			jobName := sch.Name + ":validator:" + expectedAction
-			if err := v.scheduler.AddOneTimeJob(jobName, executor.Make(v.stateChecker, v.operator, sch, expectedAction, v.dryRun, v.metrics)); err != nil {
-				log.Error().Err(err).
-					Str("schedule", sch.Name).
-					Str("resource_type", sch.Resource.Type).
-					Str("resource_id", sch.Resource.ID).
-					Str("action", expectedAction).
-					Msg("Failed to create corrective job")
-			} else {
-				if v.metrics != nil {
-					v.metrics.IncValidatorCorrection(sch.Resource.Type, expectedAction)
-				}
-				log.Info().
+				Msg("Failed to resolve schedule resources, skipping validation")
+			continue
+		}
+		// Corrective jobs of multi-resource schedules are named per resource.
+		// Name pattern matches change between runs, so they are always named
+		// per resource too.
+		perResource := len(targets) > 1 || slices.ContainsFunc(sch.Targets(), config.Resource.IsPattern)
+		for _, target := range targets {
+			// Denied resources are never corrected, so their state is not
+			// checked either.
+			if reason, denied := v.executor.DeniedReason(sch, target); denied {
+				sampled.Debug().
 					Str("schedule", sch.Name).
-					Str("resource_type", sch.Resource.Type).
-					Str("resource_id", sch.Resource.ID).
-					Str("action", expectedAction).
-					Msg("Corrective job created")
+					Str("resource_type", target.Type).
+					Str("resource_id", target.ID).
+					Str("reason", reason).
+					Msg("Resource is on the deny list, skipping validation")
+				continue
+			}
+			jobSuffix := ""
+			if perResource {
+				jobSuffix = ":" + target.ID
+			}
+			if c, ok := v.validateResource(ctx, sch.ForResource(target), jobSuffix, now, drifts); ok {
+				corrections = append(corrections, c)
+			}
+		}
+	}
+
+	// The circuit breaker decides on the whole pass, so corrections found in
+	// a pass that opens it are suspended as well.
+	if v.evaluateCircuitBreaker() {
+		if v.metrics != nil {
+			for _, c := range corrections {
+				v.metrics.IncValidatorSuppressedCorrection(c.sch.Resource.Type, c.action)
 			}
-		} else {
-			log.Debug().
-				Str("schedule", sch.Name).
-				Str("resource_type", sch.Resource.Type).
-				Str("resource_id", sch.Resource.ID).
-				Str("state", actualState).
-				Msg("Resource state matches expected state")
 		}
+		corrections = nil
+	}
+
+	// A reload may have published a new set while resources were checked.
+	corrections = slices.DeleteFunc(corrections, func(c correction) bool {
+		if !v.superseded(set, c.sch.Name) {
+			return false
+		}
+		log.Info().
+			Str("schedule", c.sch.Name).
+			Str("resource_type", c.sch.Resource.Type).
+			Str("resource_id", c.sch.Resource.ID).
+			Str("action", c.action).
+			Msg("Schedule was removed or changed by a reload, dropping correction")
+		return true
+	})
+	v.dispatchCorrections(correctionWaves(corrections))
+	v.setDrifts(drifts)
+
+	v.evaluatedVersion.Store(set.Version())
+	if v.metrics != nil {
+		v.metrics.SetValidatorScheduleSetVersion(set.Version())
 	}
 }
 
-func (v *Validator) getSchedulesSnapshot() []config.Schedule {
-	v.mu.RLock()
-	defer v.mu.RUnlock()
+// superseded reports whether the named schedule of set was removed or
+// changed in the currently published schedule set.
+func (v *Validator) superseded(set *scheduleset.Set, name string) bool {
+	return v.getScheduleSets().Load().ScheduleVersion(name) != set.ScheduleVersion(name)
+}
+
+// validateResource compares the actual state of a single-resource schedule
+// with the expected one and returns the correction needed on mismatch. The
+// mismatch is added to drifts under the schedule name.
+func (v *Validator) validateResource(ctx context.Context, sch config.Schedule, jobSuffix string, now time.Time, drifts map[string][]Drift) (correction, bool) {
+	sampled := logger.Sampled(logComponent)
+	sampled.Trace().
+		Str("schedule", sch.Name).
+		Str("resource_type", sch.Resource.Type).
+		Str("resource_id", sch.Resource.ID).
+		Time("now", now).
+		Msg("Validator is about to check resource state")
+
+	actualState, isTransitional, err := v.stateChecker.GetState(ctx, sch.Resource)
+	v.recordStateCheck(err)
+	if err != nil {
+		log.Warn().Err(err).
+			Str("schedule", sch.Name).
+			Str("resource_type", sch.Resource.Type).
+			Str("resource_id", sch.Resource.ID).
+			Msg("Failed to get actual resource state")
+		return correction{}, false
+	}
 
-	return append([]config.Schedule(nil), v.schedules...)
+	// If resource is in transitional state, skip validation and wait for stable state
+	if isTransitional {
+		sampled.Debug().
+			Str("schedule", sch.Name).
+			Str("resource_type", sch.Resource.Type).
+			Str("resource_id", sch.Resource.ID).
+			Str("current_state", actualState).
+			Msg("Resource is in transitional state, deferring validation until stable")
+		return correction{}, false
+	}
+
+	// Determine expected state based on schedule and current time
+	expectedState, expectedAction := v.determineExpectedState(sch, now)
+	expectedState, expectedAction = v.applyLabelHint(ctx, sch, expectedState, expectedAction)
+	// A stop action with stop_mode pause leaves the resource paused.
+	if expectedAction == "stop" {
+		expectedState = resource.StopOptionsFromAction(sch.Actions.Stop).TargetState()
+	}
+	if expectedAction == "" {
+		sampled.Debug().
+			Str("schedule", sch.Name).
+			Str("resource_type", sch.Resource.Type).
+			Str("resource_id", sch.Resource.ID).
+			Str("actual_state", actualState).
+			Msg("No corrective action needed")
+		return correction{}, false
+	}
+
+	if actualState == expectedState {
+		sampled.Debug().
+			Str("schedule", sch.Name).
+			Str("resource_type", sch.Resource.Type).
+			Str("resource_id", sch.Resource.ID).
+			Str("state", actualState).
+			Msg("Resource state matches expected state")
+		return correction{}, false
+	}
+
+	drifted := v.attributeDrift(sch.Resource, actualState)
+	drifts[sch.Name] = append(drifts[sch.Name], Drift{
+		Resource: sch.Resource,
+		Expected: expectedState,
+		Actual:   actualState,
+		Cause:    drifted.cause,
+	})
+	if v.metrics != nil {
+		v.metrics.IncValidatorDrift(sch.Resource.Type, drifted.cause)
+	}
+
+	if incident := v.IncidentMode(); incident.Active {
+		drifted.fields(log.Warn()).
+			Str("schedule", sch.Name).
+			Str("resource_type", sch.Resource.Type).
+			Str("resource_id", sch.Resource.ID).
+			Str("expected_state", expectedState).
+			Str("actual_state", actualState).
+			Str("incident_reason", incident.Reason).
+			Msg("State mismatch observed during incident mode, correction suspended")
+		if v.metrics != nil {
+			v.metrics.IncValidatorSuppressedCorrection(sch.Resource.Type, expectedAction)
+		}
+		return correction{}, false
+	}
+
+	drifted.fields(log.Warn()).
+		Str("schedule", sch.Name).
+		Str("resource_type", sch.Resource.Type).
+		Str("resource_id", sch.Resource.ID).
+		Str("expected_state", expectedState).
+		Str("actual_state", actualState).
+		Str("corrective_action", expectedAction).
+		Msg("State mismatch detected, creating corrective job")
+
+	return correction{sch: sch, action: expectedAction, jobName: sch.Name + ":validator:" + expectedAction + jobSuffix}, true
+}
+
+func (v *Validator) getSchedulesSnapshot() []config.Schedule {
+	return v.getScheduleSets().Load().Schedules()
 }
 
 // determineExpectedState determines the expected state and corrective action
@@ -191,47 +488,98 @@ func (v *Validator) getSchedulesSnapshot() []config.Schedule {
 // expectedState: "running" or "stopped"
 // correctiveAction: "start", "stop", or "" if no action needed.
 func (v *Validator) determineExpectedState(sch config.Schedule, now time.Time) (string, string) {
+	sampled := logger.Sampled(logComponent)
 	hasStart := sch.Actions.Start != nil && sch.Actions.Start.Enabled
 	hasStop := sch.Actions.Stop != nil && sch.Actions.Stop.Enabled
 
+	// A one-time action defines the state only once it has run.
+	if sch.Type == "one-time" {
+		if hasStart {
+			_, err := schedule.LastActionTime(sch, sch.Actions.Start, now, time.UTC)
+			hasStart = err == nil
+		}
+		if hasStop {
+			_, err := schedule.LastActionTime(sch, sch.Actions.Stop, now, time.UTC)
+			hasStop = err == nil
+		}
+	}
+
+	location := time.Local
+	if timezone := v.getConfig().Timezone.String(); timezone != "" {
+		loc, err := time.LoadLocation(timezone)
+		if err == nil {
+			location = loc
+		}
+	}
+	location = schedule.Location(sch, location)
+	nowInTZ := now.In(location)
+
 	if hasStart && !hasStop {
 		// Only start is enabled, expect running
+		if v.missed(sch, "start", sch.Actions.Start, nowInTZ, location) {
+			return "", ""
+		}
 		return "running", "start"
 	}
 
 	if hasStop && !hasStart {
 		// Only stop is enabled, expect stopped
+		if v.missed(sch, "stop", sch.Actions.Stop, nowInTZ, location) {
+			return "", ""
+		}
 		return "stopped", "stop"
 	}
 
 	if hasStart && hasStop {
 		// Both enabled: determine which action should have occurred last
 		// by comparing the last execution times of start and stop actions.
-		location := time.Local
-		if v.cfg.Timezone.String() != "" {
-			loc, err := time.LoadLocation(v.cfg.Timezone.String())
-			if err == nil {
-				location = loc
-			}
-		}
-		nowInTZ := now.In(location)
 
-		lastStartTime, err := v.getLastExecutionTime(sch, sch.Actions.Start, nowInTZ, location)
+		lastStartTime, err := schedule.LastActionTime(sch, sch.Actions.Start, nowInTZ, location)
 		if err != nil {
-			log.Debug().Err(err).
+			sampled.Debug().Err(err).
 				Str("schedule", sch.Name).
 				Msg("Failed to calculate last start time, defaulting to running")
 			return "running", "start"
 		}
 
-		lastStopTime, err := v.getLastExecutionTime(sch, sch.Actions.Stop, nowInTZ, location)
+		lastStopTime, err := schedule.LastActionTime(sch, sch.Actions.Stop, nowInTZ, location)
 		if err != nil {
-			log.Debug().Err(err).
+			sampled.Debug().Err(err).
 				Str("schedule", sch.Name).
 				Msg("Failed to calculate last stop time, defaulting to stopped")
 			return "stopped", "stop"
 		}
 
+		// The last action may still be deferred by the schedule jitter or
+		// random window, so the resource is not corrected within the window.
+		latest, window, action, cfg := lastStartTime, schedule.DeferralWindow(sch, sch.Actions.Start), "start", sch.Actions.Start
+		if lastStopTime.After(latest) {
+			latest, window, action, cfg = lastStopTime, schedule.DeferralWindow(sch, sch.Actions.Stop), "stop", sch.Actions.Stop
+		}
+		if window > 0 && nowInTZ.Sub(latest) < window {
+			sampled.Debug().
+				Str("schedule", sch.Name).
+				Time("last_action", latest).
+				Dur("jitter", window).
+				Msg("Last action is within the jitter window, deferring validation")
+			return "", ""
+		}
+		// A missed last action is not run late by a correction either.
+		if v.missed(sch, action, cfg, nowInTZ, location) {
+			return "", ""
+		}
+
+		// Business hours define the expected state directly by the window.
+		if sch.BusinessHours != nil {
+			inside, err := schedule.InBusinessHours(*sch.BusinessHours, nowInTZ)
+			if err == nil {
+				if inside {
+					return "running", "start"
+				}
+				return "stopped", "stop"
+			}
+		}
+
 		// If last start happened after last stop, resource should be running
 		// If last stop happened after last start, resource should be stopped
 		if lastStartTime.After(lastStopTime) {
@@ -244,37 +592,30 @@ func (v *Validator) determineExpectedState(sch config.Schedule, now time.Time) (
 	return "", ""
 }
 
-// getLastExecutionTime calculates the last execution time of an action before the given time.
-// Returns the last execution time or an error if calculation fails.
-func (v *Validator) getLastExecutionTime(sch config.Schedule, action *config.ActionConfig, now time.Time, location *time.Location) (time.Time, error) {
-	switch sch.Type {
-	case "daily":
-		if action.Time == "" {
-			return time.Time{}, fmt.Errorf("daily schedule missing time")
-		}
-		return schedule.GetLastDailyTime(action.Time, now, location)
-	case "weekly":
-		if action.Time == "" {
-			return time.Time{}, fmt.Errorf("weekly schedule missing time")
-		}
-		if action.Day < 0 || action.Day > 6 {
-			return time.Time{}, fmt.Errorf("weekly schedule invalid day: %d", action.Day)
-		}
-		return schedule.GetLastWeeklyTime(action.Time, action.Day, now, location)
-	case "monthly":
-		if action.Time == "" {
-			return time.Time{}, fmt.Errorf("monthly schedule missing time")
-		}
-		if action.Day < 1 || action.Day > 31 {
-			return time.Time{}, fmt.Errorf("monthly schedule invalid day: %d", action.Day)
-		}
-		return schedule.GetLastMonthlyTime(action.Time, action.Day, now, location)
-	case "cron":
-		if action.Crontab.String() == "" {
-			return time.Time{}, fmt.Errorf("cron schedule missing crontab")
-		}
-		return schedule.GetLastCronTime(action.Crontab.String(), now)
-	default:
-		return time.Time{}, fmt.Errorf("unknown schedule type: %s", sch.Type)
+// missedSource reports runs the scheduler skipped because they could not
+// start within run_within.
+type missedSource interface {
+	MissedSince(schedule, action string, since time.Time) bool
+}
+
+// missed reports whether the scheduler skipped the last run of the action
+// due before now as missed, so the state it sets is not corrected either.
+// Runs that executed, even late, are corrected as usual.
+func (v *Validator) missed(sch config.Schedule, action string, cfg *config.ActionConfig, now time.Time, location *time.Location) bool {
+	source, ok := v.scheduler.(missedSource)
+	if !ok || cfg == nil || cfg.RunWithin.Duration <= 0 {
+		return false
 	}
+	due, err := schedule.LastActionTime(sch, cfg, now, location)
+	if err != nil || due.IsZero() || !source.MissedSince(sch.Name, action, due) {
+		return false
+	}
+
+	logger.Sampled(logComponent).Debug().
+		Str("schedule", sch.Name).
+		Str("action", action).
+		Time("due", due).
+		Dur("run_within", cfg.RunWithin.Duration).
+		Msg("Last run of the action was missed, not correcting")
+	return true
 }
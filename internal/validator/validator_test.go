@@ -0,0 +1,209 @@
+package validator
+
+import (
+	"context"
+	"maps"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/denylist"
+	"github.com/sentoz/yc-sheduler/internal/executor"
+	"github.com/sentoz/yc-sheduler/internal/pause"
+	"github.com/sentoz/yc-sheduler/internal/scheduleset"
+)
+
+// TestRunOnceDuringReload is meant to run with -race: reloads publish new
+// schedule sets while the validator iterates the current one.
+func TestRunOnceDuringReload(t *testing.T) {
+	t.Parallel()
+
+	sets := scheduleset.NewStore(nil)
+	v := New(stoppedChecker{}, nopOperator{}, &config.Config{}, &recordingScheduler{}, nil, false)
+	v.SetScheduleSets(sets)
+
+	const reloads = 50
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := range reloads {
+			sets.Replace([]config.Schedule{{
+				Name:     "vm-" + strconv.Itoa(i),
+				Type:     "daily",
+				Resource: config.Resource{Type: "vm", ID: "vm-" + strconv.Itoa(i)},
+			}})
+		}
+	}()
+	for range reloads {
+		v.runOnce(context.Background())
+	}
+	wg.Wait()
+
+	if got := v.getSchedulesSnapshot(); len(got) != 1 || got[0].Name != "vm-"+strconv.Itoa(reloads-1) {
+		t.Fatalf("schedules = %+v, want the last published set", got)
+	}
+}
+
+// reloadingChecker publishes a new schedule set while the validator checks
+// resource states.
+type reloadingChecker struct {
+	sets   *scheduleset.Store
+	reload []config.Schedule
+	once   sync.Once
+}
+
+func (c *reloadingChecker) GetState(context.Context, config.Resource) (string, bool, error) {
+	c.once.Do(func() { c.sets.Replace(c.reload) })
+	return "stopped", false, nil
+}
+
+func TestRunOnceDropsCorrectionsOfRemovedSchedules(t *testing.T) {
+	t.Parallel()
+
+	daily := func(name string) config.Schedule {
+		return config.Schedule{
+			Name:     name,
+			Type:     "daily",
+			Resource: config.Resource{Type: "vm", ID: name},
+			Actions: config.Actions{
+				Start: &config.ActionConfig{Enabled: true, Time: "09:00"},
+				Stop:  &config.ActionConfig{Enabled: true, Time: "20:00"},
+			},
+		}
+	}
+	sets := scheduleset.NewStore([]config.Schedule{daily("kept"), daily("removed")})
+	sched := &recordingScheduler{}
+	checker := &reloadingChecker{sets: sets, reload: []config.Schedule{daily("kept")}}
+	v := New(checker, nopOperator{}, &config.Config{}, sched, nil, false)
+	v.SetScheduleSets(sets)
+	v.SetClock(clockwork.NewFakeClockAt(time.Date(2026, time.May, 4, 12, 0, 0, 0, time.Local)))
+
+	v.runOnce(context.Background())
+	sched.wg.Wait()
+
+	if len(sched.order) != 1 || sched.order[0] != "kept:validator:start" {
+		t.Fatalf("corrective jobs = %v, want only kept:validator:start", sched.order)
+	}
+	if got := v.EvaluatedVersion(); got != 1 {
+		t.Fatalf("EvaluatedVersion() = %d, want 1", got)
+	}
+}
+
+func TestRunOnceSkipsDeniedResources(t *testing.T) {
+	t.Parallel()
+
+	sch := config.Schedule{
+		Name:              "vms",
+		Type:              "daily",
+		DeniedResourceIDs: []string{"vm-denied-schedule"},
+		Actions: config.Actions{
+			Start: &config.ActionConfig{Enabled: true, Time: "09:00"},
+			Stop:  &config.ActionConfig{Enabled: true, Time: "20:00"},
+		},
+	}
+	for _, id := range []string{"vm-denied-global", "vm-denied-schedule", "vm-allowed"} {
+		sch.Resources = append(sch.Resources, config.Resource{Type: "vm", ID: id})
+	}
+	list := denylist.New()
+	list.Add(denylist.Entry{ID: "vm-denied-global", Source: denylist.SourceAPI})
+	exec := executor.New()
+	exec.SetDenyList(list)
+
+	sched := &recordingScheduler{}
+	v := New(stoppedChecker{}, nopOperator{}, &config.Config{}, sched, nil, false)
+	v.SetExecutor(exec)
+	v.SetScheduleSets(scheduleset.NewStore([]config.Schedule{sch}))
+	v.SetClock(clockwork.NewFakeClockAt(time.Date(2026, time.May, 4, 12, 0, 0, 0, time.Local)))
+
+	v.runOnce(context.Background())
+	sched.wg.Wait()
+
+	if len(sched.order) != 1 || sched.order[0] != "vms:validator:start:vm-allowed" {
+		t.Fatalf("corrective jobs = %v, want only vms:validator:start:vm-allowed", sched.order)
+	}
+}
+
+func TestExpectedRunning(t *testing.T) {
+	t.Parallel()
+
+	daily := func(name, start, stop string) config.Schedule {
+		return config.Schedule{
+			Name:     name,
+			Type:     "daily",
+			Resource: config.Resource{Type: "vm", ID: name},
+			Actions: config.Actions{
+				Start: &config.ActionConfig{Enabled: true, Time: start},
+				Stop:  &config.ActionConfig{Enabled: true, Time: stop},
+			},
+		}
+	}
+	office := daily("office", "09:00", "20:00")
+	night := daily("night", "22:00", "06:00")
+	paused := daily("paused", "09:00", "20:00")
+	paused.Labels = map[string]string{"team": "qa"}
+	disk := daily("disk", "09:00", "20:00")
+	disk.Resource.Type = "disk"
+
+	pauses := pause.NewRegistry()
+	pauses.Add(pause.Pause{Selector: map[string]string{"team": "qa"}})
+	v := New(stoppedChecker{}, nopOperator{}, &config.Config{}, &recordingScheduler{}, nil, false)
+	v.SetPauses(pauses)
+	v.SetScheduleSets(scheduleset.NewStore([]config.Schedule{office, night, paused, disk}))
+	v.SetClock(clockwork.NewFakeClockAt(time.Date(2026, time.May, 4, 12, 0, 0, 0, time.Local)))
+
+	got := v.ExpectedRunning(context.Background(), "vm")
+	if want := map[string]bool{"office": true}; !maps.Equal(got, want) {
+		t.Fatalf("ExpectedRunning() = %v, want %v", got, want)
+	}
+}
+
+type stateChecker string
+
+func (c stateChecker) GetState(context.Context, config.Resource) (string, bool, error) {
+	return string(c), false, nil
+}
+
+func TestValidateResourceExpectsPausedStop(t *testing.T) {
+	t.Parallel()
+
+	sch := func(mode config.StopMode) config.Schedule {
+		return config.Schedule{
+			Name:     "workers-night",
+			Type:     "daily",
+			Resource: config.Resource{Type: "instance_group", ID: "cl1", FolderID: "b1g1"},
+			Actions: config.Actions{
+				Stop: &config.ActionConfig{Enabled: true, Time: "20:00", StopMode: mode},
+			},
+		}
+	}
+	tests := []struct {
+		mode   config.StopMode
+		actual string
+		want   bool
+	}{
+		{mode: config.StopModePause, actual: "paused", want: false},
+		{mode: config.StopModePause, actual: "stopped", want: true},
+		{mode: config.StopModePause, actual: "running", want: true},
+		{mode: "", actual: "stopped", want: false},
+		{mode: config.StopModeStop, actual: "paused", want: true},
+	}
+	for _, tt := range tests {
+		v := New(stateChecker(tt.actual), nopOperator{}, &config.Config{}, &recordingScheduler{}, nil, false)
+		drifts := make(map[string][]Drift)
+		c, ok := v.validateResource(context.Background(), sch(tt.mode), "", time.Now(), drifts)
+		if ok != tt.want {
+			t.Errorf("validateResource() with stop_mode %q and state %s = %v, want %v", tt.mode, tt.actual, ok, tt.want)
+		}
+		if ok && c.action != "stop" {
+			t.Errorf("correction action = %q, want stop", c.action)
+		}
+		if got := drifts["workers-night"]; ok != (len(got) == 1) || ok && got[0].Actual != tt.actual {
+			t.Errorf("drifts with stop_mode %q and state %s = %+v, want the mismatch only", tt.mode, tt.actual, got)
+		}
+	}
+}
@@ -22,12 +22,16 @@ type ScheduleProvider interface {
 	ResourceStatuses(ctx context.Context, schedules []config.Schedule) map[string]ResourceStatus
 }
 
-// ResourceStatus describes the current live state of a resource.
+// ResourceStatus describes the current live state of a resource. When the
+// live state cannot be read, it may carry the last known state with Stale
+// set and the time it was observed.
 type ResourceStatus struct {
-	State string `json:"state"`
-	Error string `json:"error,omitempty"`
+	ObservedAt time.Time `json:"observed_at,omitzero"`
+	State      string    `json:"state"`
+	Error      string    `json:"error,omitempty"`
 
 	IsTransitional bool `json:"is_transitional,omitempty"`
+	Stale          bool `json:"stale,omitempty"`
 }
 
 type calendarResponse struct {
@@ -91,6 +95,8 @@ func mergeResourceStatuses(events []calendar.Event, statuses map[string]Resource
 		events[i].State = status.State
 		events[i].StatusError = status.Error
 		events[i].Transitional = status.IsTransitional
+		events[i].Stale = status.Stale
+		events[i].ObservedAt = status.ObservedAt
 	}
 }
 
@@ -141,5 +147,5 @@ func loadLocation(timezone string) (*time.Location, error) {
 
 // ResourceKey returns a stable key for resource status lookup in the UI layer.
 func ResourceKey(resource config.Resource) string {
-	return resource.Type + ":" + resource.FolderID + ":" + resource.ID
+	return resource.Type + ":" + resource.FolderID + ":" + resource.Identifier()
 }
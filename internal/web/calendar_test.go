@@ -12,7 +12,7 @@ import (
 )
 
 func TestCalendarAPI(t *testing.T) {
-	mux := newMux(false, testProvider{
+	mux := newMux(Options{ScheduleProvider: testProvider{
 		timezone: "Europe/Moscow",
 		schedules: []config.Schedule{
 			{
@@ -31,7 +31,7 @@ func TestCalendarAPI(t *testing.T) {
 				},
 			},
 		},
-	})
+	}})
 
 	req := httptest.NewRequest(http.MethodGet, "/api/calendar?from=2026-04-01&to=2026-04-02", nil)
 	rec := httptest.NewRecorder()
@@ -60,7 +60,7 @@ func TestCalendarAPI(t *testing.T) {
 }
 
 func TestCalendarAPIRejectsInvalidRange(t *testing.T) {
-	mux := newMux(false, testProvider{timezone: "Europe/Moscow"})
+	mux := newMux(Options{ScheduleProvider: testProvider{timezone: "Europe/Moscow"}})
 
 	req := httptest.NewRequest(http.MethodGet, "/api/calendar?from=2026-04-02&to=2026-04-01", nil)
 	rec := httptest.NewRecorder()
@@ -73,7 +73,7 @@ func TestCalendarAPIRejectsInvalidRange(t *testing.T) {
 }
 
 func TestUIIndexServed(t *testing.T) {
-	mux := newMux(false, testProvider{timezone: "Europe/Moscow"})
+	mux := newMux(Options{ScheduleProvider: testProvider{timezone: "Europe/Moscow"}})
 
 	req := httptest.NewRequest(http.MethodGet, "/ui/", nil)
 	rec := httptest.NewRecorder()
@@ -94,7 +94,7 @@ func TestUIIndexServed(t *testing.T) {
 }
 
 func TestUIDisabledWithoutProvider(t *testing.T) {
-	mux := newMux(false, nil)
+	mux := newMux(Options{})
 
 	req := httptest.NewRequest(http.MethodGet, "/ui/", nil)
 	rec := httptest.NewRecorder()
@@ -0,0 +1,28 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/sentoz/yc-sheduler/internal/scheduler"
+)
+
+// ConsistencyChecker compares the loaded schedules with the registered
+// scheduler jobs.
+type ConsistencyChecker interface {
+	CheckConsistency() []scheduler.Divergence
+}
+
+type consistencyResponse struct {
+	Divergences []scheduler.Divergence `json:"divergences"`
+	Consistent  bool                   `json:"consistent"`
+}
+
+func registerConsistencyAPI(mux *http.ServeMux, checker ConsistencyChecker) {
+	mux.HandleFunc("GET /api/v1/consistency", func(w http.ResponseWriter, _ *http.Request) {
+		divergences := checker.CheckConsistency()
+		if divergences == nil {
+			divergences = []scheduler.Divergence{}
+		}
+		writeJSON(w, http.StatusOK, consistencyResponse{Divergences: divergences, Consistent: len(divergences) == 0})
+	})
+}
@@ -0,0 +1,37 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sentoz/yc-sheduler/internal/scheduler"
+)
+
+type fakeConsistencyChecker []scheduler.Divergence
+
+func (f fakeConsistencyChecker) CheckConsistency() []scheduler.Divergence {
+	return f
+}
+
+func TestConsistencyAPI(t *testing.T) {
+	mux := newMux(Options{Consistency: fakeConsistencyChecker{
+		{Job: "vm:stop", Kind: scheduler.DivergenceMissing},
+	}})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/consistency", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	var resp consistencyResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Consistent || len(resp.Divergences) != 1 || resp.Divergences[0].Job != "vm:stop" {
+		t.Fatalf("response = %+v, want the missing vm:stop job", resp)
+	}
+}
@@ -0,0 +1,54 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/sentoz/yc-sheduler/internal/denylist"
+)
+
+// DeniedResourceController manages resources that are never operated on.
+type DeniedResourceController interface {
+	List() []denylist.Entry
+	Add(e denylist.Entry) denylist.Entry
+	Remove(id string) bool
+}
+
+type deniedResourceRequest struct {
+	ID     string `json:"id"`
+	Reason string `json:"reason"`
+}
+
+// registerDeniedResourceAPI serves the deny list to everyone and changing it
+// to operators, if operatorToken is set.
+func registerDeniedResourceAPI(mux *http.ServeMux, controller DeniedResourceController, operatorToken string) {
+	mux.HandleFunc("GET /api/v1/denied-resources", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, controller.List())
+	})
+	if operatorToken == "" {
+		return
+	}
+	mux.HandleFunc("POST /api/v1/denied-resources", operatorOnly(operatorToken, func(w http.ResponseWriter, r *http.Request) {
+		var req deniedResourceRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.ID == "" {
+			http.Error(w, "id is required", http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, http.StatusCreated, controller.Add(denylist.Entry{
+			ID:     req.ID,
+			Reason: req.Reason,
+			Source: denylist.SourceAPI,
+		}))
+	}))
+	mux.HandleFunc("DELETE /api/v1/denied-resources/{id}", operatorOnly(operatorToken, func(w http.ResponseWriter, r *http.Request) {
+		if !controller.Remove(r.PathValue("id")) {
+			http.Error(w, "denied resource not found among API entries", http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+}
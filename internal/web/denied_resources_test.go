@@ -0,0 +1,95 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sentoz/yc-sheduler/internal/denylist"
+)
+
+func TestDeniedResourceAPILifecycle(t *testing.T) {
+	list := denylist.New()
+	list.ReplaceSource(denylist.SourceConfig, []denylist.Entry{{ID: "fhm-config"}})
+	mux := newMux(Options{DeniedResources: list, OperatorToken: "secret"})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/denied-resources", strings.NewReader(`{"id":"fhm-prod","reason":"production"}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body.String())
+	}
+	if _, ok := list.Denied("fhm-prod"); !ok {
+		t.Fatal("resource is not denied after create")
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/denied-resources/fhm-config", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("delete config entry status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/denied-resources/fhm-prod", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("delete status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	if len(list.List()) != 1 {
+		t.Fatalf("List() = %+v, want only the config entry", list.List())
+	}
+}
+
+func TestDeniedResourceAPIRejectsEmptyID(t *testing.T) {
+	mux := newMux(Options{DeniedResources: denylist.New(), OperatorToken: "secret"})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/denied-resources", strings.NewReader(`{"reason":"production"}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestDeniedResourceAPIRequiresOperatorToken(t *testing.T) {
+	tests := []struct {
+		name          string
+		operatorToken string
+		token         string
+		status        int
+	}{
+		// Without an operator token the route is not registered at all.
+		{name: "no operator token", token: "secret", status: http.StatusOK},
+		{name: "no token", operatorToken: "secret", status: http.StatusUnauthorized},
+		{name: "wrong token", operatorToken: "secret", token: "guess", status: http.StatusUnauthorized},
+		{name: "operator", operatorToken: "secret", token: "secret", status: http.StatusCreated},
+	}
+	for _, tt := range tests {
+		list := denylist.New()
+		mux := newMux(Options{DeniedResources: list, OperatorToken: tt.operatorToken})
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/denied-resources", strings.NewReader(`{"id":"fhm-prod"}`))
+		if tt.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		if rec.Code != tt.status {
+			t.Fatalf("%s: status = %d, want %d: %s", tt.name, rec.Code, tt.status, rec.Body.String())
+		}
+		if _, denied := list.Denied("fhm-prod"); denied != (tt.status == http.StatusCreated) {
+			t.Fatalf("%s: resource denied = %v, want %v", tt.name, denied, !denied)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+// DeprecationProvider supplies the deprecated features used by the
+// configuration.
+type DeprecationProvider interface {
+	Deprecations() []config.Deprecation
+}
+
+type deprecationsResponse struct {
+	Deprecations []config.Deprecation `json:"deprecations"`
+}
+
+func registerDeprecationAPI(mux *http.ServeMux, provider DeprecationProvider) {
+	mux.HandleFunc("GET /api/v1/deprecations", func(w http.ResponseWriter, _ *http.Request) {
+		deprecations := provider.Deprecations()
+		if deprecations == nil {
+			deprecations = []config.Deprecation{}
+		}
+		writeJSON(w, http.StatusOK, deprecationsResponse{Deprecations: deprecations})
+	})
+}
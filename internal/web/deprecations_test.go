@@ -0,0 +1,37 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+)
+
+type fakeDeprecationProvider []config.Deprecation
+
+func (f fakeDeprecationProvider) Deprecations() []config.Deprecation {
+	return f
+}
+
+func TestDeprecationAPI(t *testing.T) {
+	mux := newMux(Options{Deprecations: fakeDeprecationProvider{
+		config.NewDeprecation(config.DeprecatedDailyJob, "legacy-vm"),
+	}})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/deprecations", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	var resp deprecationsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Deprecations) != 1 || resp.Deprecations[0].Feature != config.DeprecatedDailyJob || resp.Deprecations[0].Message == "" {
+		t.Fatalf("deprecations = %+v", resp.Deprecations)
+	}
+}
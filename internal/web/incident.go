@@ -0,0 +1,54 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// IncidentController reads and toggles validator incident mode.
+type IncidentController interface {
+	IncidentStatus() IncidentStatus
+	SetIncident(active bool, reason string) IncidentStatus
+}
+
+// IncidentStatus describes the current incident mode.
+type IncidentStatus struct {
+	Since     time.Time `json:"since,omitzero"`
+	Reason    string    `json:"reason,omitempty"`
+	Active    bool      `json:"active"`
+	Automatic bool      `json:"automatic,omitempty"`
+}
+
+type incidentRequest struct {
+	Reason string `json:"reason"`
+	Active bool   `json:"active"`
+}
+
+// registerIncidentAPI serves the incident mode to everyone and toggling it to
+// operators, if operatorToken is set.
+func registerIncidentAPI(mux *http.ServeMux, controller IncidentController, operatorToken string) {
+	mux.HandleFunc("GET /api/v1/incident", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, controller.IncidentStatus())
+	})
+	if operatorToken == "" {
+		return
+	}
+	mux.HandleFunc("PUT /api/v1/incident", operatorOnly(operatorToken, func(w http.ResponseWriter, r *http.Request) {
+		var req incidentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.Active && req.Reason == "" {
+			req.Reason = "manual"
+		}
+		writeJSON(w, http.StatusOK, controller.SetIncident(req.Active, req.Reason))
+	}))
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
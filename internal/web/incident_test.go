@@ -0,0 +1,92 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type testIncidentController struct {
+	status IncidentStatus
+}
+
+func (c *testIncidentController) IncidentStatus() IncidentStatus {
+	return c.status
+}
+
+func (c *testIncidentController) SetIncident(active bool, reason string) IncidentStatus {
+	c.status = IncidentStatus{Active: active, Reason: reason}
+	return c.status
+}
+
+func TestIncidentAPIToggle(t *testing.T) {
+	controller := &testIncidentController{}
+	mux := newMux(Options{Incident: controller, OperatorToken: "secret"})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/incident", strings.NewReader(`{"active":true}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if !controller.status.Active || controller.status.Reason != "manual" {
+		t.Fatalf("incident status = %+v, want active with manual reason", controller.status)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/incident", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if body := rec.Body.String(); !strings.Contains(body, "\"active\":true") {
+		t.Fatalf("body = %s, want active incident", body)
+	}
+}
+
+func TestIncidentAPIRejectsInvalidBody(t *testing.T) {
+	mux := newMux(Options{Incident: &testIncidentController{}, OperatorToken: "secret"})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/incident", strings.NewReader("{"))
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestIncidentAPIRequiresOperatorToken(t *testing.T) {
+	tests := []struct {
+		name          string
+		operatorToken string
+		token         string
+		status        int
+	}{
+		// Without an operator token the route is not registered at all.
+		{name: "no operator token", token: "secret", status: http.StatusOK},
+		{name: "no token", operatorToken: "secret", status: http.StatusUnauthorized},
+		{name: "wrong token", operatorToken: "secret", token: "guess", status: http.StatusUnauthorized},
+		{name: "operator", operatorToken: "secret", token: "secret", status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		controller := &testIncidentController{}
+		mux := newMux(Options{Incident: controller, OperatorToken: tt.operatorToken})
+
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/incident", strings.NewReader(`{"active":true}`))
+		if tt.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		if rec.Code != tt.status {
+			t.Fatalf("%s: status = %d, want %d: %s", tt.name, rec.Code, tt.status, rec.Body.String())
+		}
+		if want := tt.operatorToken != "" && tt.token == tt.operatorToken; controller.status.Active != want {
+			t.Fatalf("%s: incident active = %v, want %v", tt.name, controller.status.Active, want)
+		}
+	}
+}
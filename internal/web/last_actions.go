@@ -0,0 +1,35 @@
+package web
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/sentoz/yc-sheduler/internal/resource"
+)
+
+// LastActionProvider supplies the last action the scheduler performed on
+// each resource.
+type LastActionProvider interface {
+	LastActions() []resource.LastAction
+}
+
+type lastActionsResponse struct {
+	Actions []resource.LastAction `json:"actions"`
+}
+
+// registerLastActionAPI serves GET /api/v1/resources/last-actions. Repeated
+// id query parameters limit the response to the given resources.
+func registerLastActionAPI(mux *http.ServeMux, provider LastActionProvider) {
+	mux.HandleFunc("GET /api/v1/resources/last-actions", func(w http.ResponseWriter, r *http.Request) {
+		actions := provider.LastActions()
+		if ids, ok := r.URL.Query()["id"]; ok {
+			actions = slices.DeleteFunc(actions, func(a resource.LastAction) bool {
+				return !slices.Contains(ids, a.ResourceID)
+			})
+		}
+		if actions == nil {
+			actions = []resource.LastAction{}
+		}
+		writeJSON(w, http.StatusOK, lastActionsResponse{Actions: actions})
+	})
+}
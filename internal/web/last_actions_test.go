@@ -0,0 +1,40 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/resource"
+)
+
+type fakeLastActionProvider []resource.LastAction
+
+func (f fakeLastActionProvider) LastActions() []resource.LastAction {
+	return f
+}
+
+func TestLastActionAPI(t *testing.T) {
+	at := time.Date(2026, time.May, 4, 9, 0, 0, 0, time.UTC)
+	mux := newMux(Options{LastActions: fakeLastActionProvider{
+		{At: at, ResourceType: "vm", ResourceID: "fhm123", Schedule: "dev", Action: "stop", OperationID: "fhmop1"},
+		{At: at, ResourceType: "vm", ResourceID: "fhm456", Schedule: "dev", Action: "start", OperationID: "fhmop2"},
+	}})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/resources/last-actions?id=fhm123", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	var resp lastActionsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Actions) != 1 || resp.Actions[0].OperationID != "fhmop1" || !resp.Actions[0].At.Equal(at) {
+		t.Fatalf("actions = %+v, want the stop of fhm123", resp.Actions)
+	}
+}
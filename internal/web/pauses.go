@@ -0,0 +1,61 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/pause"
+)
+
+// PauseController manages label-selector pauses of schedules.
+type PauseController interface {
+	List() []pause.Pause
+	Add(p pause.Pause) pause.Pause
+	Remove(id string) bool
+}
+
+type pauseRequest struct {
+	Until    time.Time         `json:"until"`
+	Selector map[string]string `json:"selector"`
+	Reason   string            `json:"reason"`
+}
+
+// registerPauseAPI serves the pause list to everyone and creating and
+// removing pauses to operators, if operatorToken is set.
+func registerPauseAPI(mux *http.ServeMux, controller PauseController, operatorToken string) {
+	mux.HandleFunc("GET /api/v1/pauses", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, controller.List())
+	})
+	if operatorToken == "" {
+		return
+	}
+	mux.HandleFunc("POST /api/v1/pauses", operatorOnly(operatorToken, func(w http.ResponseWriter, r *http.Request) {
+		var req pauseRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if len(req.Selector) == 0 {
+			http.Error(w, "selector must contain at least one label", http.StatusBadRequest)
+			return
+		}
+		if !req.Until.IsZero() && !req.Until.After(time.Now()) {
+			http.Error(w, "until must be in the future", http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, http.StatusCreated, controller.Add(pause.Pause{
+			Selector: req.Selector,
+			Until:    req.Until,
+			Reason:   req.Reason,
+			Source:   pause.SourceAPI,
+		}))
+	}))
+	mux.HandleFunc("DELETE /api/v1/pauses/{id}", operatorOnly(operatorToken, func(w http.ResponseWriter, r *http.Request) {
+		if !controller.Remove(r.PathValue("id")) {
+			http.Error(w, "pause not found", http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+}
@@ -0,0 +1,70 @@
+package web
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	// ErrUnsupportedResourceType is returned by RawResourceProvider for
+	// resource types without a raw view.
+	ErrUnsupportedResourceType = errors.New("unsupported resource type")
+	// ErrResourceNotFound is returned by RawResourceProvider when the
+	// resource does not exist.
+	ErrResourceNotFound = errors.New("resource not found")
+)
+
+// RawResourceProvider fetches resources live from Yandex Cloud.
+type RawResourceProvider interface {
+	RawResource(ctx context.Context, resourceType, id string) (proto.Message, error)
+}
+
+func registerRawResourceAPI(mux *http.ServeMux, provider RawResourceProvider, operatorToken string) {
+	mux.HandleFunc("GET /api/v1/resources/{type}/{id}/raw", operatorOnly(operatorToken, func(w http.ResponseWriter, r *http.Request) {
+		msg, err := provider.RawResource(r.Context(), r.PathValue("type"), r.PathValue("id"))
+		switch {
+		case errors.Is(err, ErrUnsupportedResourceType):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case errors.Is(err, ErrResourceNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		body, err := protojson.Marshal(msg)
+		if err != nil {
+			http.Error(w, "encode resource: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}))
+}
+
+// operatorOnly wraps handler to answer 401 to requests without the operator
+// bearer token.
+func operatorOnly(operatorToken string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !isOperator(r, operatorToken) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="yc-scheduler"`)
+			http.Error(w, "operator token required", http.StatusUnauthorized)
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// isOperator reports whether the request carries the operator bearer token.
+func isOperator(r *http.Request, operatorToken string) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(operatorToken)) == 1
+}
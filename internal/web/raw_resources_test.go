@@ -0,0 +1,80 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	computepb "github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+type fakeRawResources map[string]proto.Message
+
+func (f fakeRawResources) RawResource(_ context.Context, resourceType, id string) (proto.Message, error) {
+	if resourceType != "vm" {
+		return nil, ErrUnsupportedResourceType
+	}
+	msg, ok := f[id]
+	if !ok {
+		return nil, ErrResourceNotFound
+	}
+	return msg, nil
+}
+
+func TestRawResourceAPI(t *testing.T) {
+	mux := newMux(Options{
+		RawResources:  fakeRawResources{"fhm1": &computepb.Instance{Id: "fhm1", FolderId: "b1g1", Status: computepb.Instance_RUNNING}},
+		OperatorToken: "secret",
+	})
+
+	tests := []struct {
+		name   string
+		path   string
+		token  string
+		status int
+	}{
+		{name: "no token", path: "/api/v1/resources/vm/fhm1/raw", status: http.StatusUnauthorized},
+		{name: "wrong token", path: "/api/v1/resources/vm/fhm1/raw", token: "guess", status: http.StatusUnauthorized},
+		{name: "unsupported type", path: "/api/v1/resources/alb/a1/raw", token: "secret", status: http.StatusBadRequest},
+		{name: "not found", path: "/api/v1/resources/vm/fhm2/raw", token: "secret", status: http.StatusNotFound},
+		{name: "found", path: "/api/v1/resources/vm/fhm1/raw", token: "secret", status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		if rec.Code != tt.status {
+			t.Fatalf("%s: status = %d, want %d: %s", tt.name, rec.Code, tt.status, rec.Body.String())
+		}
+		if tt.status != http.StatusOK {
+			continue
+		}
+		var resp map[string]any
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if resp["id"] != "fhm1" || resp["folderId"] != "b1g1" || resp["status"] != "RUNNING" {
+			t.Fatalf("response = %v, want the raw instance", resp)
+		}
+	}
+}
+
+func TestRawResourceAPIRequiresOperatorToken(t *testing.T) {
+	mux := newMux(Options{RawResources: fakeRawResources{}})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/resources/vm/fhm1/raw", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	// Without the raw handler the request falls through to build info.
+	if rec.Code != http.StatusOK || rec.Header().Get("WWW-Authenticate") != "" {
+		t.Fatalf("status = %d, want the raw resource API disabled without an operator token", rec.Code)
+	}
+}
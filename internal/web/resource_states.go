@@ -0,0 +1,27 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/sentoz/yc-sheduler/internal/resource"
+)
+
+// ResourceStateProvider supplies the last successfully observed resource
+// states.
+type ResourceStateProvider interface {
+	LastKnownStates() []resource.KnownState
+}
+
+type resourceStatesResponse struct {
+	States []resource.KnownState `json:"states"`
+}
+
+func registerResourceStateAPI(mux *http.ServeMux, provider ResourceStateProvider) {
+	mux.HandleFunc("GET /api/v1/resources/states", func(w http.ResponseWriter, _ *http.Request) {
+		states := provider.LastKnownStates()
+		if states == nil {
+			states = []resource.KnownState{}
+		}
+		writeJSON(w, http.StatusOK, resourceStatesResponse{States: states})
+	})
+}
@@ -0,0 +1,39 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/resource"
+)
+
+type fakeResourceStateProvider []resource.KnownState
+
+func (f fakeResourceStateProvider) LastKnownStates() []resource.KnownState {
+	return f
+}
+
+func TestResourceStateAPI(t *testing.T) {
+	observedAt := time.Date(2026, time.May, 4, 9, 0, 0, 0, time.UTC)
+	mux := newMux(Options{ResourceStates: fakeResourceStateProvider{
+		{ResourceType: "vm", ResourceID: "fhm123", State: "running", ObservedAt: observedAt},
+	}})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/resources/states", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	var resp resourceStatesResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.States) != 1 || resp.States[0].State != "running" || !resp.States[0].ObservedAt.Equal(observedAt) {
+		t.Fatalf("states = %+v", resp.States)
+	}
+}
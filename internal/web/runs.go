@@ -0,0 +1,34 @@
+package web
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/sentoz/yc-sheduler/internal/scheduler"
+)
+
+// RunProvider supplies the outcomes of the last runs of schedule actions.
+type RunProvider interface {
+	LastRuns() []scheduler.RunResult
+}
+
+type runsResponse struct {
+	Runs []scheduler.RunResult `json:"runs"`
+}
+
+// registerRunsAPI serves GET /api/v1/runs. Repeated schedule query
+// parameters limit the response to the given schedules.
+func registerRunsAPI(mux *http.ServeMux, provider RunProvider) {
+	mux.HandleFunc("GET /api/v1/runs", func(w http.ResponseWriter, r *http.Request) {
+		runs := provider.LastRuns()
+		if schedules, ok := r.URL.Query()["schedule"]; ok {
+			runs = slices.DeleteFunc(runs, func(run scheduler.RunResult) bool {
+				return !slices.Contains(schedules, run.Schedule)
+			})
+		}
+		if runs == nil {
+			runs = []scheduler.RunResult{}
+		}
+		writeJSON(w, http.StatusOK, runsResponse{Runs: runs})
+	})
+}
@@ -0,0 +1,50 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sentoz/yc-sheduler/internal/executor"
+	"github.com/sentoz/yc-sheduler/internal/scheduler"
+)
+
+type fakeRunProvider []scheduler.RunResult
+
+func (f fakeRunProvider) LastRuns() []scheduler.RunResult {
+	return f
+}
+
+func TestRunsAPI(t *testing.T) {
+	mux := newMux(Options{Runs: fakeRunProvider{
+		{Schedule: "dev-vms", Action: "stop", OK: true, Resources: []executor.ResourceOutcome{{
+			Type: "vm",
+			ID:   "fhm1",
+			OK:   true,
+			Utilization: &executor.UtilizationSnapshot{Metrics: []executor.Utilization{
+				{Metric: "cpu_utilization", Avg: 1.5, Max: 4, Samples: 60},
+			}},
+		}}},
+		{Schedule: "test-vms", Action: "start", OK: false},
+	}})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/runs?schedule=dev-vms", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	var resp runsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Runs) != 1 || resp.Runs[0].Schedule != "dev-vms" {
+		t.Fatalf("runs = %+v, want the dev-vms run", resp.Runs)
+	}
+	resources := resp.Runs[0].Resources
+	if len(resources) != 1 || resources[0].Utilization == nil || resources[0].Utilization.Metrics[0].Max != 4 {
+		t.Fatalf("resources = %+v, want the utilization snapshot of fhm1", resources)
+	}
+}
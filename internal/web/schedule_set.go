@@ -0,0 +1,84 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// ScheduleVersion is the version of the schedule set a schedule was last
+// added or changed in.
+type ScheduleVersion struct {
+	Name    string `json:"name"`
+	Version uint64 `json:"version"`
+}
+
+// ScheduleSetStatus describes the published schedule set and how far reloads
+// have propagated.
+type ScheduleSetStatus struct {
+	// Version is the version of the last published schedule set.
+	Version uint64 `json:"version"`
+	// ValidatorVersion is the version of the schedule set the validator last
+	// evaluated, or 0 if validation has not run yet.
+	ValidatorVersion uint64            `json:"validator_version"`
+	Schedules        []ScheduleVersion `json:"schedules"`
+	// Held is the schedules reload held back for changing too many
+	// schedules, if any.
+	Held *HeldReload `json:"held,omitempty"`
+}
+
+// HeldReload describes a schedules reload waiting for confirmation.
+type HeldReload struct {
+	Since time.Time `json:"since"`
+	// Summary counts the added, removed and changed schedules.
+	Summary string `json:"summary"`
+	// Percent is the share of the published schedules the reload changes.
+	Percent int `json:"percent"`
+}
+
+// ErrNoHeldReload is returned by ReloadConfirmer when no reload is held
+// back.
+var ErrNoHeldReload = errors.New("no reload is held back")
+
+// ScheduleSetProvider supplies the status of the published schedule set.
+type ScheduleSetProvider interface {
+	ScheduleSetStatus() ScheduleSetStatus
+}
+
+// ReloadConfirmer applies a schedules reload held back for changing too many
+// schedules.
+type ReloadConfirmer interface {
+	ConfirmReload(ctx context.Context) (ScheduleSetStatus, error)
+}
+
+// registerScheduleSetAPI serves GET /api/v1/schedule-set.
+func registerScheduleSetAPI(mux *http.ServeMux, provider ScheduleSetProvider) {
+	mux.HandleFunc("GET /api/v1/schedule-set", func(w http.ResponseWriter, _ *http.Request) {
+		status := provider.ScheduleSetStatus()
+		if status.Schedules == nil {
+			status.Schedules = []ScheduleVersion{}
+		}
+		writeJSON(w, http.StatusOK, status)
+	})
+}
+
+// registerReloadConfirmAPI serves POST /api/v1/schedule-set/confirm to
+// operators.
+func registerReloadConfirmAPI(mux *http.ServeMux, confirmer ReloadConfirmer, operatorToken string) {
+	mux.HandleFunc("POST /api/v1/schedule-set/confirm", operatorOnly(operatorToken, func(w http.ResponseWriter, r *http.Request) {
+		status, err := confirmer.ConfirmReload(r.Context())
+		switch {
+		case errors.Is(err, ErrNoHeldReload):
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		if status.Schedules == nil {
+			status.Schedules = []ScheduleVersion{}
+		}
+		writeJSON(w, http.StatusOK, status)
+	}))
+}
@@ -0,0 +1,101 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type fakeScheduleSetProvider ScheduleSetStatus
+
+func (f fakeScheduleSetProvider) ScheduleSetStatus() ScheduleSetStatus {
+	return ScheduleSetStatus(f)
+}
+
+func TestScheduleSetAPI(t *testing.T) {
+	mux := newMux(Options{ScheduleSet: fakeScheduleSetProvider{
+		Version:          3,
+		ValidatorVersion: 2,
+		Schedules:        []ScheduleVersion{{Name: "vm", Version: 3}},
+	}})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/schedule-set", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	var got ScheduleSetStatus
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if got.Version != 3 || got.ValidatorVersion != 2 || len(got.Schedules) != 1 || got.Schedules[0] != (ScheduleVersion{Name: "vm", Version: 3}) {
+		t.Fatalf("response = %+v, want version 3 evaluated up to 2", got)
+	}
+}
+
+type fakeReloadConfirmer struct {
+	held bool
+}
+
+func (f *fakeReloadConfirmer) ConfirmReload(context.Context) (ScheduleSetStatus, error) {
+	if !f.held {
+		return ScheduleSetStatus{}, ErrNoHeldReload
+	}
+	f.held = false
+	return ScheduleSetStatus{Version: 4}, nil
+}
+
+func TestReloadConfirmAPI(t *testing.T) {
+	confirmer := &fakeReloadConfirmer{held: true}
+	mux := newMux(Options{ReloadConfirmer: confirmer, OperatorToken: "secret"})
+
+	confirm := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/schedule-set/confirm", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, token := range []string{"", "guess"} {
+		if rec := confirm(token); rec.Code != http.StatusUnauthorized || !confirmer.held {
+			t.Fatalf("status with token %q = %d, want %d without confirming", token, rec.Code, http.StatusUnauthorized)
+		}
+	}
+
+	rec := confirm("secret")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	var got ScheduleSetStatus
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if got.Version != 4 || got.Schedules == nil {
+		t.Fatalf("response = %+v, want version 4 with a schedules list", got)
+	}
+
+	if rec := confirm("secret"); rec.Code != http.StatusConflict {
+		t.Fatalf("status without a held reload = %d, want %d", rec.Code, http.StatusConflict)
+	}
+}
+
+func TestReloadConfirmAPIRequiresOperatorToken(t *testing.T) {
+	confirmer := &fakeReloadConfirmer{held: true}
+	mux := newMux(Options{ReloadConfirmer: confirmer})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/schedule-set/confirm", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	// Without the confirm handler the request falls through to build info.
+	if !confirmer.held || rec.Header().Get("WWW-Authenticate") != "" {
+		t.Fatalf("status = %d, want the confirm API disabled without an operator token", rec.Code)
+	}
+}
@@ -0,0 +1,43 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/sentoz/yc-sheduler/static"
+)
+
+// registerSchemaAPI serves the JSON schemas embedded in the binary, so
+// editors validate against the exact schema version the scheduler enforces.
+// The kind query parameter selects the config (default) or schedule schema
+// and format=yaml returns the schema as YAML.
+func registerSchemaAPI(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/v1/schema", func(w http.ResponseWriter, r *http.Request) {
+		var schema []byte
+		switch kind := r.URL.Query().Get("kind"); kind {
+		case "", "config":
+			schema = static.ConfigSchema
+		case "schedule":
+			schema = static.ScheduleSchema
+		default:
+			http.Error(w, "unknown schema kind "+kind+", want config or schedule", http.StatusBadRequest)
+			return
+		}
+
+		switch format := r.URL.Query().Get("format"); format {
+		case "", "json":
+			w.Header().Set("Content-Type", "application/schema+json")
+		case "yaml":
+			converted, err := static.SchemaYAML(schema)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			schema = converted
+			w.Header().Set("Content-Type", "application/yaml")
+		default:
+			http.Error(w, "unknown format "+format+", want json or yaml", http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write(schema)
+	})
+}
@@ -0,0 +1,48 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSchemaAPIServesEmbeddedSchema(t *testing.T) {
+	mux := newMux(Options{})
+
+	for _, tt := range []struct {
+		query       string
+		contentType string
+		unmarshal   func([]byte, any) error
+	}{
+		{query: "", contentType: "application/schema+json", unmarshal: json.Unmarshal},
+		{query: "?kind=schedule&format=yaml", contentType: "application/yaml", unmarshal: yaml.Unmarshal},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/schema"+tt.query, nil)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s status = %d, want %d", tt.query, rec.Code, http.StatusOK)
+		}
+		if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+			t.Fatalf("GET %s Content-Type = %q, want %q", tt.query, got, tt.contentType)
+		}
+		var schema map[string]any
+		if err := tt.unmarshal(rec.Body.Bytes(), &schema); err != nil {
+			t.Fatalf("GET %s decode schema: %v", tt.query, err)
+		}
+		if id, _ := schema["$id"].(string); id == "" {
+			t.Fatalf("GET %s schema has no $id", tt.query)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/schema?kind=unknown", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("unknown kind status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
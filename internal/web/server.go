@@ -2,8 +2,12 @@ package web
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -13,24 +17,133 @@ import (
 // Server represents an HTTP server for metrics and health endpoints.
 type Server struct {
 	srv    *http.Server
-	ln     net.Listener
+	lns    []net.Listener
 	ctx    context.Context
 	cancel context.CancelFunc
 }
 
-func newMux(metricsEnabled bool, scheduleProvider ScheduleProvider) *http.ServeMux {
+// Options configures endpoints served by the HTTP server.
+type Options struct {
+	// ScheduleProvider enables the calendar UI and its API when set.
+	ScheduleProvider ScheduleProvider
+	// Incident enables the incident mode API when set. Incident mode is
+	// toggled only with OperatorToken.
+	Incident IncidentController
+	// Pauses enables the schedule pause API when set. Pauses are created and
+	// removed only with OperatorToken.
+	Pauses PauseController
+	// DeniedResources enables the resource deny list API when set. The list
+	// is changed only with OperatorToken.
+	DeniedResources DeniedResourceController
+	// Vacations enables the namespace vacation API when set. Vacations are
+	// created and canceled only with OperatorToken.
+	Vacations VacationController
+	// Stops enables the announced stops API used to postpone stops when set.
+	// Stops are postponed only with OperatorToken.
+	Stops StopController
+	// Suggestions enables the schedule optimization suggestions API when set.
+	Suggestions SuggestionProvider
+	// ResourceStates enables the last known resource states API when set.
+	ResourceStates ResourceStateProvider
+	// Deprecations enables the deprecated configuration features API when set.
+	Deprecations DeprecationProvider
+	// Consistency enables the schedule and job consistency API when set.
+	Consistency ConsistencyChecker
+	// Stats enables the per-namespace schedule statistics API when set.
+	Stats StatsProvider
+	// ScheduleSet enables the published schedule set version API when set.
+	ScheduleSet ScheduleSetProvider
+	// ReloadConfirmer enables confirming held back schedules reloads when
+	// set together with OperatorToken.
+	ReloadConfirmer ReloadConfirmer
+	// Runs enables the last runs API when set.
+	Runs RunProvider
+	// LastActions enables the last actions on resources API when set.
+	LastActions LastActionProvider
+	// RawResources enables the raw resource details API when set together
+	// with OperatorToken.
+	RawResources RawResourceProvider
+	// OperatorToken is the bearer token required by operator-only endpoints.
+	OperatorToken string
+	// MetricsEnabled toggles the Prometheus metrics endpoint.
+	MetricsEnabled bool
+}
+
+func newMux(opts Options) *http.ServeMux {
 	mux := http.NewServeMux()
 
 	// Register metrics endpoint if enabled (must be before /)
-	if metricsEnabled {
+	if opts.MetricsEnabled {
 		mux.Handle("/metrics", promhttp.Handler())
 	}
 
-	if scheduleProvider != nil {
-		registerCalendarAPI(mux, scheduleProvider)
+	if opts.ScheduleProvider != nil {
+		registerCalendarAPI(mux, opts.ScheduleProvider)
 		registerUIHandlers(mux)
 	}
 
+	if opts.Incident != nil {
+		registerIncidentAPI(mux, opts.Incident, opts.OperatorToken)
+	}
+
+	if opts.Pauses != nil {
+		registerPauseAPI(mux, opts.Pauses, opts.OperatorToken)
+	}
+
+	if opts.DeniedResources != nil {
+		registerDeniedResourceAPI(mux, opts.DeniedResources, opts.OperatorToken)
+	}
+
+	if opts.Vacations != nil {
+		registerVacationAPI(mux, opts.Vacations, opts.OperatorToken)
+	}
+
+	if opts.Stops != nil {
+		registerStopAPI(mux, opts.Stops, opts.OperatorToken)
+	}
+
+	if opts.Suggestions != nil {
+		registerSuggestionAPI(mux, opts.Suggestions)
+	}
+
+	if opts.ResourceStates != nil {
+		registerResourceStateAPI(mux, opts.ResourceStates)
+	}
+
+	if opts.Deprecations != nil {
+		registerDeprecationAPI(mux, opts.Deprecations)
+	}
+
+	if opts.Consistency != nil {
+		registerConsistencyAPI(mux, opts.Consistency)
+	}
+
+	if opts.Stats != nil {
+		registerStatsAPI(mux, opts.Stats)
+	}
+
+	if opts.ScheduleSet != nil {
+		registerScheduleSetAPI(mux, opts.ScheduleSet)
+	}
+
+	if opts.ReloadConfirmer != nil && opts.OperatorToken != "" {
+		registerReloadConfirmAPI(mux, opts.ReloadConfirmer, opts.OperatorToken)
+	}
+
+	if opts.Runs != nil {
+		registerRunsAPI(mux, opts.Runs)
+	}
+
+	if opts.LastActions != nil {
+		registerLastActionAPI(mux, opts.LastActions)
+	}
+
+	if opts.RawResources != nil && opts.OperatorToken != "" {
+		registerRawResourceAPI(mux, opts.RawResources, opts.OperatorToken)
+	}
+
+	registerSchemaAPI(mux)
+
 	// Register health endpoints
 	mux.HandleFunc("/health", HealthHandler)
 	mux.HandleFunc("/health/live", HealthHandler)
@@ -42,45 +155,104 @@ func newMux(metricsEnabled bool, scheduleProvider ScheduleProvider) *http.ServeM
 	return mux
 }
 
-// NewServer creates a new Server instance.
-func NewServer(ctx context.Context, addr string, metricsEnabled bool, scheduleProvider ScheduleProvider) (*Server, error) {
-	mux := newMux(metricsEnabled, scheduleProvider)
+// unixPrefix marks listen addresses of Unix sockets.
+const unixPrefix = "unix:"
+
+// NewServer creates a new Server instance listening on all addrs. An address
+// is either host:port or unix:<path> of a Unix socket.
+func NewServer(ctx context.Context, addrs []string, opts Options) (*Server, error) {
+	mux := newMux(opts)
 
 	srv := &http.Server{
-		Addr:              addr,
 		Handler:           mux,
 		ReadHeaderTimeout: 5 * time.Second,
 	}
 
-	ln, err := net.Listen("tcp", addr)
-	if err != nil {
-		return nil, err
+	lns := make([]net.Listener, 0, len(addrs))
+	for _, addr := range addrs {
+		ln, err := listen(addr)
+		if err != nil {
+			for _, l := range lns {
+				_ = l.Close()
+			}
+			return nil, err
+		}
+		lns = append(lns, ln)
 	}
 
 	serverCtx, cancel := context.WithCancel(ctx)
 
 	server := &Server{
 		srv:    srv,
-		ln:     ln,
+		lns:    lns,
 		ctx:    serverCtx,
 		cancel: cancel,
 	}
 
 	log.Info().
-		Str("addr", addr).
-		Bool("metrics_enabled", metricsEnabled).
+		Strs("addrs", addrs).
+		Bool("metrics_enabled", opts.MetricsEnabled).
 		Msg("Starting metrics and health HTTP server")
 
 	return server, nil
 }
 
+// listen opens a listener for a host:port or unix:<path> address. IPv4 and
+// IPv6 hosts are bound to their address family only, so "0.0.0.0:9090" and
+// "[::]:9090" can be listened on together.
+func listen(addr string) (net.Listener, error) {
+	if path, ok := strings.CutPrefix(addr, unixPrefix); ok {
+		// A socket left by an unclean shutdown would fail the bind.
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("web: remove stale socket %s: %w", path, err)
+		}
+		ln, err := net.Listen("unix", path)
+		if err != nil {
+			return nil, fmt.Errorf("web: listen on %s: %w", addr, err)
+		}
+		if err := os.Chmod(path, 0o660); err != nil {
+			_ = ln.Close()
+			return nil, fmt.Errorf("web: chmod socket %s: %w", path, err)
+		}
+		return ln, nil
+	}
+
+	network := "tcp"
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		if ip := net.ParseIP(host); ip != nil {
+			network = "tcp6"
+			if ip.To4() != nil {
+				network = "tcp4"
+			}
+		}
+	}
+	ln, err := net.Listen(network, addr)
+	if err != nil {
+		return nil, fmt.Errorf("web: listen on %s: %w", addr, err)
+	}
+	return ln, nil
+}
+
+// Addrs returns the addresses the server listens on.
+func (s *Server) Addrs() []net.Addr {
+	addrs := make([]net.Addr, 0, len(s.lns))
+	for _, ln := range s.lns {
+		addrs = append(addrs, ln.Addr())
+	}
+	return addrs
+}
+
 // Start starts the HTTP server in a separate goroutine.
 func (s *Server) Start() {
-	go func() {
-		if err := s.srv.Serve(s.ln); err != nil && err != http.ErrServerClosed {
-			log.Warn().Err(err).Msg("Metrics/health HTTP server stopped with error")
-		}
-	}()
+	for _, ln := range s.lns {
+		go func() {
+			if err := s.srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+				log.Warn().Err(err).
+					Str("addr", ln.Addr().String()).
+					Msg("Metrics/health HTTP server stopped with error")
+			}
+		}()
+	}
 
 	go func() {
 		<-s.ctx.Done()
@@ -0,0 +1,66 @@
+package web
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+func TestServerListensOnTCPAndUnixSocket(t *testing.T) {
+	t.Parallel()
+
+	socket := filepath.Join(t.TempDir(), "admin.sock")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv, err := NewServer(ctx, []string{"127.0.0.1:0", "unix:" + socket}, Options{})
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	srv.Start()
+	defer func() { _ = srv.Shutdown(context.Background()) }()
+
+	addrs := srv.Addrs()
+	if len(addrs) != 2 {
+		t.Fatalf("Addrs() = %v, want 2 listeners", addrs)
+	}
+
+	resp, err := http.Get("http://" + addrs[0].String() + "/health")
+	if err != nil {
+		t.Fatalf("GET over TCP error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("TCP status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+	}}
+	resp, err = client.Get("http://unix/health")
+	if err != nil {
+		t.Fatalf("GET over Unix socket error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Unix socket status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
+
+func TestNewServerFailsOnBusyAddress(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+
+	if _, err := NewServer(context.Background(), []string{"127.0.0.1:0", ln.Addr().String()}, Options{}); err == nil {
+		t.Fatal("NewServer() error = nil for busy address")
+	}
+}
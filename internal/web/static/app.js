@@ -330,16 +330,21 @@ function renderSelectedDay() {
           <span class="status-badge ${statusBadgeClass(event)}">${formatStateLabel(event)}</span>
         </div>
         ${displayNameMarkup(event)}
+        ${descriptionMarkup(event)}
         <ul class="event-row__fields">
           <li><span>scheduler name:</span> ${event.schedule_name}</li>
           <li><span>${resourceIDLabel(event)}:</span> ${event.resource_id}</li>
           <li><span>folder id:</span> ${event.folder_id || ""}</li>
+          ${runbookMarkup(event)}
         </ul>
         <div class="event-row__status">
           <span class="event-row__status-text">${statusDescription(event)}</span>
         </div>
       `;
-      row.addEventListener("click", () => {
+      row.addEventListener("click", (clickEvent) => {
+        if (clickEvent.target.closest("a")) {
+          return;
+        }
         toggleScheduleFilter(displayName(event));
       });
       groupCard.appendChild(row);
@@ -528,7 +533,7 @@ function groupEventsByAction(events) {
   });
 
   return Array.from(groups.values()).sort((left, right) => {
-    const order = { start: 0, stop: 1 };
+    const order = { start: 0, stop: 1, snapshot: 2, restart: 3, scale: 4, resize: 5, preemptible: 6 };
     const leftOrder = order[left.action] ?? 10;
     const rightOrder = order[right.action] ?? 10;
     if (leftOrder !== rightOrder) {
@@ -724,6 +729,7 @@ function formatEventTooltip(event) {
     `scheduler name: ${event.schedule_name}`,
     `${resourceIDLabel(event)}: ${event.resource_id}`,
     `folder id: ${event.folder_id || ""}`,
+    event.description || "",
     `Status: ${formatStateLabel(event)}`,
   ].filter(Boolean).join("\n");
 }
@@ -739,6 +745,20 @@ function displayNameMarkup(event) {
   return `<h3 class="event-row__display-name">${event.schedule_display_name}</h3>`;
 }
 
+function descriptionMarkup(event) {
+  if (!event.description) {
+    return "";
+  }
+  return `<p class="event-row__description">${event.description}</p>`;
+}
+
+function runbookMarkup(event) {
+  if (!event.runbook_url) {
+    return "";
+  }
+  return `<li><span>runbook:</span> <a href="${event.runbook_url}" target="_blank" rel="noopener">${event.runbook_url}</a></li>`;
+}
+
 function annotatedDisplayName(event) {
   if (!event.schedule_display_name || event.schedule_display_name === event.schedule_name) {
     return "";
@@ -804,13 +824,16 @@ function eventCountText(count) {
 }
 
 function formatStateLabel(event) {
-  if (event.status_error || !event.state) {
+  if ((event.status_error && !event.stale) || !event.state) {
     return "unknown";
   }
   return event.state.toLowerCase();
 }
 
 function statusDescription(event) {
+  if (event.stale) {
+    return `Последнее известное состояние на ${formatObservedAt(event.observed_at)}`;
+  }
   if (event.status_error) {
     return event.status_error;
   }
@@ -823,8 +846,18 @@ function statusDescription(event) {
   return "Стабильное состояние";
 }
 
+function formatObservedAt(value) {
+  return new Intl.DateTimeFormat("ru-RU", {
+    timeZone: state.timezone,
+    day: "numeric",
+    month: "long",
+    hour: "2-digit",
+    minute: "2-digit",
+  }).format(new Date(value));
+}
+
 function statusBadgeClass(event) {
-  if (event.status_error || !event.state) {
+  if ((event.status_error && !event.stale) || !event.state) {
     return "status-badge--unknown";
   }
   if (event.transitional) {
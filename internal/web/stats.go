@@ -0,0 +1,52 @@
+package web
+
+import (
+	"net/http"
+	"slices"
+)
+
+// NamespaceStats aggregates schedules and their last runs of a namespace.
+// Schedules without a namespace are counted under an empty namespace.
+type NamespaceStats struct {
+	Namespace string `json:"namespace"`
+	// Schedules is the number of loaded schedules.
+	Schedules int `json:"schedules"`
+	// Resources is the number of resources managed by the schedules.
+	Resources int `json:"resources"`
+	// Paused is the number of schedules matching an active pause.
+	Paused int `json:"paused"`
+	// Succeeded, Partial, Missed and Failed count schedule actions by the
+	// outcome of their last run since the scheduler started. Partial runs
+	// succeeded for some resources and failed for others, missed runs were
+	// skipped by run_within.
+	Succeeded int `json:"succeeded"`
+	Partial   int `json:"partial"`
+	Missed    int `json:"missed"`
+	Failed    int `json:"failed"`
+}
+
+// StatsProvider supplies schedule statistics per namespace.
+type StatsProvider interface {
+	Stats() []NamespaceStats
+}
+
+type statsResponse struct {
+	Namespaces []NamespaceStats `json:"namespaces"`
+}
+
+// registerStatsAPI serves GET /api/v1/stats. Repeated namespace query
+// parameters limit the response to the given namespaces.
+func registerStatsAPI(mux *http.ServeMux, provider StatsProvider) {
+	mux.HandleFunc("GET /api/v1/stats", func(w http.ResponseWriter, r *http.Request) {
+		stats := provider.Stats()
+		if namespaces, ok := r.URL.Query()["namespace"]; ok {
+			stats = slices.DeleteFunc(stats, func(s NamespaceStats) bool {
+				return !slices.Contains(namespaces, s.Namespace)
+			})
+		}
+		if stats == nil {
+			stats = []NamespaceStats{}
+		}
+		writeJSON(w, http.StatusOK, statsResponse{Namespaces: stats})
+	})
+}
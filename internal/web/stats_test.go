@@ -0,0 +1,60 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type fakeStatsProvider []NamespaceStats
+
+func (f fakeStatsProvider) Stats() []NamespaceStats {
+	return append([]NamespaceStats(nil), f...)
+}
+
+func TestStatsAPI(t *testing.T) {
+	mux := newMux(Options{Stats: fakeStatsProvider{
+		{Namespace: "", Schedules: 1, Resources: 1},
+		{Namespace: "dev", Schedules: 2, Resources: 3, Succeeded: 4},
+		{Namespace: "prod", Schedules: 1, Resources: 1, Paused: 1, Failed: 1},
+	}})
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "", want: []string{"", "dev", "prod"}},
+		{query: "?namespace=dev", want: []string{"dev"}},
+		{query: "?namespace=dev&namespace=prod", want: []string{"dev", "prod"}},
+		{query: "?namespace=missing", want: []string{}},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/stats"+tt.query, nil)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, want %d: %s", tt.query, rec.Code, http.StatusOK, rec.Body.String())
+		}
+		var resp statsResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("%s: decode response: %v", tt.query, err)
+		}
+		if resp.Namespaces == nil {
+			t.Fatalf("%s: namespaces = null, want a list", tt.query)
+		}
+		got := make([]string, 0, len(resp.Namespaces))
+		for _, s := range resp.Namespaces {
+			got = append(got, s.Namespace)
+		}
+		if len(got) != len(tt.want) {
+			t.Fatalf("%s: namespaces = %q, want %q", tt.query, got, tt.want)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Fatalf("%s: namespaces = %q, want %q", tt.query, got, tt.want)
+			}
+		}
+	}
+}
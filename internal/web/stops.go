@@ -0,0 +1,56 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/grace"
+)
+
+// StopController lists announced stops of schedules and postpones them.
+type StopController interface {
+	List() []grace.Stop
+	Postpone(schedule string, until time.Time, reason string) (grace.Stop, error)
+}
+
+type postponeRequest struct {
+	Until  time.Time `json:"until"`
+	Reason string    `json:"reason"`
+}
+
+// registerStopAPI serves announced stops to everyone and postponing them to
+// operators, if operatorToken is set.
+func registerStopAPI(mux *http.ServeMux, controller StopController, operatorToken string) {
+	mux.HandleFunc("GET /api/v1/stops", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, controller.List())
+	})
+	if operatorToken == "" {
+		return
+	}
+	mux.HandleFunc("POST /api/v1/stops/{schedule}/postpone", operatorOnly(operatorToken, func(w http.ResponseWriter, r *http.Request) {
+		var req postponeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !req.Until.After(time.Now()) {
+			http.Error(w, "until must be in the future", http.StatusBadRequest)
+			return
+		}
+		stop, err := controller.Postpone(r.PathValue("schedule"), req.Until, req.Reason)
+		switch {
+		case errors.Is(err, grace.ErrNotFound):
+			http.Error(w, "no pending stop for schedule", http.StatusNotFound)
+		case errors.Is(err, grace.ErrNotLater):
+			http.Error(w, "until must be after the pending stop time", http.StatusBadRequest)
+		case errors.Is(err, grace.ErrTooLong):
+			http.Error(w, "until must be at most "+grace.MaxPostponement.String()+" after the announced stop time", http.StatusBadRequest)
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		default:
+			writeJSON(w, http.StatusOK, stop)
+		}
+	}))
+}
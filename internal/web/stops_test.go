@@ -0,0 +1,98 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/grace"
+)
+
+func TestStopAPIPostponesAnnouncedStop(t *testing.T) {
+	registry := grace.NewRegistry()
+	at := time.Now().Add(10 * time.Minute).Truncate(time.Second)
+	registry.Announce("dev-vm", at)
+	mux := newMux(Options{Stops: registry, OperatorToken: "secret"})
+
+	until := at.Add(time.Hour).UTC()
+	body := `{"until":"` + until.Format(time.RFC3339) + `","reason":"demo"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/stops/dev-vm/postpone", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("postpone status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	if stop, ok := registry.Postponed("dev-vm", at); !ok || !stop.Until.Equal(until) {
+		t.Fatalf("Postponed() = %+v, %v; want postponed until %s", stop, ok, until)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/stops", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	var stops []grace.Stop
+	if err := json.NewDecoder(rec.Body).Decode(&stops); err != nil {
+		t.Fatalf("decode stops: %v", err)
+	}
+	if len(stops) != 1 || stops[0].Reason != "demo" {
+		t.Fatalf("stops = %+v, want the postponed stop", stops)
+	}
+}
+
+func TestStopAPIRejectsUnknownSchedule(t *testing.T) {
+	mux := newMux(Options{Stops: grace.NewRegistry(), OperatorToken: "secret"})
+
+	body := `{"until":"` + time.Now().Add(time.Hour).UTC().Format(time.RFC3339) + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/stops/unknown/postpone", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestStopAPIPostponeLimits(t *testing.T) {
+	at := time.Now().Add(10 * time.Minute).Truncate(time.Second)
+	tests := []struct {
+		name          string
+		operatorToken string
+		token         string
+		until         time.Time
+		status        int
+	}{
+		// Without an operator token the route is not registered at all.
+		{name: "no operator token", token: "secret", until: at.Add(time.Hour), status: http.StatusOK},
+		{name: "no token", operatorToken: "secret", until: at.Add(time.Hour), status: http.StatusUnauthorized},
+		{name: "wrong token", operatorToken: "secret", token: "guess", until: at.Add(time.Hour), status: http.StatusUnauthorized},
+		{name: "too long", operatorToken: "secret", token: "secret", until: at.Add(grace.MaxPostponement + time.Hour), status: http.StatusBadRequest},
+		{name: "operator", operatorToken: "secret", token: "secret", until: at.Add(time.Hour), status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		registry := grace.NewRegistry()
+		registry.Announce("dev-vm", at)
+		mux := newMux(Options{Stops: registry, OperatorToken: tt.operatorToken})
+
+		body := `{"until":"` + tt.until.UTC().Format(time.RFC3339) + `"}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/stops/dev-vm/postpone", strings.NewReader(body))
+		if tt.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		if rec.Code != tt.status {
+			t.Fatalf("%s: status = %d, want %d: %s", tt.name, rec.Code, tt.status, rec.Body.String())
+		}
+		wantPostponed := tt.status == http.StatusOK && tt.operatorToken != ""
+		if _, postponed := registry.Postponed("dev-vm", at); postponed != wantPostponed {
+			t.Fatalf("%s: postponed = %v, want %v", tt.name, postponed, wantPostponed)
+		}
+	}
+}
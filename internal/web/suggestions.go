@@ -0,0 +1,42 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/sentoz/yc-sheduler/internal/advisor"
+)
+
+// maxSuggestionDays caps the activity history requested via the API.
+const maxSuggestionDays = 90
+
+// SuggestionProvider computes schedule optimization suggestions from the
+// activity of the last days.
+type SuggestionProvider interface {
+	Suggestions(ctx context.Context, days int) []advisor.Suggestion
+}
+
+type suggestionsResponse struct {
+	Suggestions []advisor.Suggestion `json:"suggestions"`
+	Days        int                  `json:"days"`
+}
+
+func registerSuggestionAPI(mux *http.ServeMux, provider SuggestionProvider) {
+	mux.HandleFunc("GET /api/v1/suggestions", func(w http.ResponseWriter, r *http.Request) {
+		days := advisor.DefaultDays
+		if raw := r.URL.Query().Get("days"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 1 || n > maxSuggestionDays {
+				http.Error(w, "days must be between 1 and "+strconv.Itoa(maxSuggestionDays), http.StatusBadRequest)
+				return
+			}
+			days = n
+		}
+		suggestions := provider.Suggestions(r.Context(), days)
+		if suggestions == nil {
+			suggestions = []advisor.Suggestion{}
+		}
+		writeJSON(w, http.StatusOK, suggestionsResponse{Suggestions: suggestions, Days: days})
+	})
+}
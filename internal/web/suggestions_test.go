@@ -0,0 +1,56 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sentoz/yc-sheduler/internal/advisor"
+)
+
+type fakeSuggestionProvider struct {
+	days *int
+}
+
+func (f fakeSuggestionProvider) Suggestions(_ context.Context, days int) []advisor.Suggestion {
+	*f.days = days
+	return []advisor.Suggestion{{Schedule: "dev-vm", Action: "stop", SuggestedTime: "18:00"}}
+}
+
+func TestSuggestionAPI(t *testing.T) {
+	var days int
+	mux := newMux(Options{Suggestions: fakeSuggestionProvider{days: &days}})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/suggestions?days=14", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	if days != 14 {
+		t.Fatalf("provider called with days = %d, want 14", days)
+	}
+	var resp suggestionsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Suggestions) != 1 || resp.Suggestions[0].SuggestedTime != "18:00" {
+		t.Fatalf("suggestions = %+v", resp.Suggestions)
+	}
+}
+
+func TestSuggestionAPIRejectsInvalidDays(t *testing.T) {
+	var days int
+	mux := newMux(Options{Suggestions: fakeSuggestionProvider{days: &days}})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/suggestions?days=0", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
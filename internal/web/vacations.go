@@ -0,0 +1,66 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/vacation"
+)
+
+// VacationController manages namespace vacations.
+type VacationController interface {
+	List() []vacation.Vacation
+	Add(v vacation.Vacation) vacation.Vacation
+	Remove(id string) bool
+}
+
+type vacationRequest struct {
+	From      time.Time `json:"from"`
+	Until     time.Time `json:"until"`
+	Namespace string    `json:"namespace"`
+	Reason    string    `json:"reason"`
+}
+
+// registerVacationAPI serves the vacation list to everyone and creating and
+// canceling vacations to operators, if operatorToken is set.
+func registerVacationAPI(mux *http.ServeMux, controller VacationController, operatorToken string) {
+	mux.HandleFunc("GET /api/v1/vacations", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, controller.List())
+	})
+	if operatorToken == "" {
+		return
+	}
+	mux.HandleFunc("POST /api/v1/vacations", operatorOnly(operatorToken, func(w http.ResponseWriter, r *http.Request) {
+		var req vacationRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.Namespace == "" {
+			http.Error(w, "namespace is required", http.StatusBadRequest)
+			return
+		}
+		if req.From.IsZero() {
+			req.From = time.Now()
+		}
+		if !req.Until.After(req.From) || !req.Until.After(time.Now()) {
+			http.Error(w, "until must be in the future and after from", http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, http.StatusCreated, controller.Add(vacation.Vacation{
+			Namespace: req.Namespace,
+			From:      req.From,
+			Until:     req.Until,
+			Reason:    req.Reason,
+			Source:    vacation.SourceAPI,
+		}))
+	}))
+	mux.HandleFunc("DELETE /api/v1/vacations/{id}", operatorOnly(operatorToken, func(w http.ResponseWriter, r *http.Request) {
+		if !controller.Remove(r.PathValue("id")) {
+			http.Error(w, "vacation not found", http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+}
@@ -0,0 +1,95 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sentoz/yc-sheduler/internal/vacation"
+)
+
+func TestVacationAPILifecycle(t *testing.T) {
+	manager := vacation.NewManager(nil, nil, nil, false)
+	mux := newMux(Options{Vacations: manager, OperatorToken: "secret"})
+
+	until := time.Now().Add(24 * time.Hour).Format(time.RFC3339)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/vacations", strings.NewReader(`{"namespace":"dev","until":"`+until+`","reason":"holidays"}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body.String())
+	}
+	var created vacation.Vacation
+	if err := json.NewDecoder(rec.Body).Decode(&created); err != nil {
+		t.Fatalf("decode created vacation: %v", err)
+	}
+	if _, on := manager.OnVacation("dev"); !on {
+		t.Fatal("namespace is not on vacation")
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/vacations/"+created.ID, nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("delete status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	if _, on := manager.OnVacation("dev"); on {
+		t.Fatal("namespace is still on vacation after delete")
+	}
+}
+
+func TestVacationAPIRejectsPastUntil(t *testing.T) {
+	mux := newMux(Options{Vacations: vacation.NewManager(nil, nil, nil, false), OperatorToken: "secret"})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/vacations", strings.NewReader(`{"namespace":"dev","until":"2020-01-01T00:00:00Z"}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestVacationAPIRequiresOperatorToken(t *testing.T) {
+	until := time.Now().Add(24 * time.Hour).Format(time.RFC3339)
+	body := `{"namespace":"dev","until":"` + until + `"}`
+
+	tests := []struct {
+		name          string
+		operatorToken string
+		token         string
+		status        int
+	}{
+		// Without an operator token the route is not registered at all.
+		{name: "no operator token", token: "secret", status: http.StatusOK},
+		{name: "no token", operatorToken: "secret", status: http.StatusUnauthorized},
+		{name: "wrong token", operatorToken: "secret", token: "guess", status: http.StatusUnauthorized},
+		{name: "operator", operatorToken: "secret", token: "secret", status: http.StatusCreated},
+	}
+	for _, tt := range tests {
+		manager := vacation.NewManager(nil, nil, nil, false)
+		mux := newMux(Options{Vacations: manager, OperatorToken: tt.operatorToken})
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/vacations", strings.NewReader(body))
+		if tt.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		if rec.Code != tt.status {
+			t.Fatalf("%s: status = %d, want %d: %s", tt.name, rec.Code, tt.status, rec.Body.String())
+		}
+		if want := tt.status == http.StatusCreated; (len(manager.List()) == 1) != want {
+			t.Fatalf("%s: vacations = %v, want created %v", tt.name, manager.List(), want)
+		}
+	}
+}
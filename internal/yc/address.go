@@ -0,0 +1,55 @@
+package yc
+
+import (
+	"context"
+
+	vpcpb "github.com/yandex-cloud/go-genproto/yandex/cloud/vpc/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// GetAddressByValue retrieves a VPC address by its external IPv4 value.
+func (c *Client) GetAddressByValue(ctx context.Context, ipv4 string) (*vpcpb.Address, error) {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.vpc.v1.AddressService.GetByValue")
+	return getResource(ctx, c, endpoint, "get address by value", ipv4, func(ctx context.Context, conn grpc.ClientConnInterface) (*vpcpb.Address, error) {
+		client := vpcpb.NewAddressServiceClient(conn)
+		return client.GetByValue(ctx, &vpcpb.GetAddressByValueRequest{
+			Address: &vpcpb.GetAddressByValueRequest_ExternalIpv4Address{
+				ExternalIpv4Address: ipv4,
+			},
+		})
+	})
+}
+
+// SetAddressReserved switches a VPC address between reserved (static) and
+// ephemeral (dynamic).
+func (c *Client) SetAddressReserved(ctx context.Context, addressID string, reserved bool) error {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.vpc.v1.AddressService.Update")
+	return executeOperation(ctx, c, endpoint, "update address", addressID, func(ctx context.Context, conn grpc.ClientConnInterface) (string, error) {
+		client := vpcpb.NewAddressServiceClient(conn)
+		op, err := client.Update(ctx, &vpcpb.UpdateAddressRequest{
+			AddressId:  addressID,
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"reserved"}},
+			Reserved:   reserved,
+		})
+		if err != nil {
+			return "", err
+		}
+		return op.GetId(), nil
+	})
+}
+
+// GetAddress retrieves a VPC address.
+func (c *Client) GetAddress(ctx context.Context, folderID, addressID string) (*vpcpb.Address, error) {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.vpc.v1.AddressService.Get")
+	return getResource(ctx, c, endpoint, "get address", addressID, func(ctx context.Context, conn grpc.ClientConnInterface) (*vpcpb.Address, error) {
+		client := vpcpb.NewAddressServiceClient(conn)
+		return client.Get(ctx, &vpcpb.GetAddressRequest{
+			AddressId: addressID,
+		})
+	})
+}
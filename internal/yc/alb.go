@@ -0,0 +1,53 @@
+package yc
+
+import (
+	"context"
+
+	albpb "github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// StartLoadBalancer starts the specified Application Load Balancer.
+func (c *Client) StartLoadBalancer(ctx context.Context, folderID, loadBalancerID string) error {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.apploadbalancer.v1.LoadBalancerService.Start")
+	return executeOperation(ctx, c, endpoint, "start load balancer", loadBalancerID, func(ctx context.Context, conn grpc.ClientConnInterface) (string, error) {
+		client := albpb.NewLoadBalancerServiceClient(conn)
+		op, err := client.Start(ctx, &albpb.StartLoadBalancerRequest{
+			LoadBalancerId: loadBalancerID,
+		})
+		if err != nil {
+			return "", err
+		}
+		return op.GetId(), nil
+	})
+}
+
+// StopLoadBalancer stops the specified Application Load Balancer.
+func (c *Client) StopLoadBalancer(ctx context.Context, folderID, loadBalancerID string) error {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.apploadbalancer.v1.LoadBalancerService.Stop")
+	return executeOperation(ctx, c, endpoint, "stop load balancer", loadBalancerID, func(ctx context.Context, conn grpc.ClientConnInterface) (string, error) {
+		client := albpb.NewLoadBalancerServiceClient(conn)
+		op, err := client.Stop(ctx, &albpb.StopLoadBalancerRequest{
+			LoadBalancerId: loadBalancerID,
+		})
+		if err != nil {
+			return "", err
+		}
+		return op.GetId(), nil
+	})
+}
+
+// GetLoadBalancer retrieves the current state of an Application Load Balancer.
+func (c *Client) GetLoadBalancer(ctx context.Context, folderID, loadBalancerID string) (*albpb.LoadBalancer, error) {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.apploadbalancer.v1.LoadBalancerService.Get")
+	return getResource(ctx, c, endpoint, "get load balancer", loadBalancerID, func(ctx context.Context, conn grpc.ClientConnInterface) (*albpb.LoadBalancer, error) {
+		client := albpb.NewLoadBalancerServiceClient(conn)
+		return client.Get(ctx, &albpb.GetLoadBalancerRequest{
+			LoadBalancerId: loadBalancerID,
+		})
+	})
+}
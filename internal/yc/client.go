@@ -3,34 +3,109 @@ package yc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
+	albpb "github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
 	computepb "github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
+	instancegrouppb "github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1/instancegroup"
 	k8spb "github.com/yandex-cloud/go-genproto/yandex/cloud/k8s/v1"
+	greenplumpb "github.com/yandex-cloud/go-genproto/yandex/cloud/mdb/greenplum/v1"
+	mongodbpb "github.com/yandex-cloud/go-genproto/yandex/cloud/mdb/mongodb/v1"
+	resourcemanagerpb "github.com/yandex-cloud/go-genproto/yandex/cloud/resourcemanager/v1"
+	containerspb "github.com/yandex-cloud/go-genproto/yandex/cloud/serverless/containers/v1"
+	vpcpb "github.com/yandex-cloud/go-genproto/yandex/cloud/vpc/v1"
 	ycsdk "github.com/yandex-cloud/go-sdk/v2"
 	"github.com/yandex-cloud/go-sdk/v2/credentials"
+	"github.com/yandex-cloud/go-sdk/v2/pkg/endpoints"
 	"github.com/yandex-cloud/go-sdk/v2/pkg/options"
 	"google.golang.org/grpc"
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
-// ClientInterface defines the interface for Yandex Cloud client operations.
-type ClientInterface interface {
-	ValidateCredentials(ctx context.Context) error
+// ComputeAPI covers Compute Cloud instances, disk snapshots and instance
+// groups.
+type ComputeAPI interface {
 	StartInstance(ctx context.Context, folderID, instanceID string) error
 	StopInstance(ctx context.Context, folderID, instanceID string) error
 	GetInstance(ctx context.Context, folderID, instanceID string) (*computepb.Instance, error)
+	ListInstances(ctx context.Context, folderID string) ([]*computepb.Instance, error)
+	InstanceStartTime(ctx context.Context, folderID, instanceID string) (time.Time, error)
+	ResizeInstance(ctx context.Context, folderID, instanceID string, spec InstanceSpec) error
+	UpdateInstanceLabels(ctx context.Context, folderID, instanceID string, labels map[string]string) error
+	AddInstanceOneToOneNat(ctx context.Context, folderID, instanceID, networkInterfaceIndex, address string) error
+	RemoveInstanceOneToOneNat(ctx context.Context, folderID, instanceID, networkInterfaceIndex string) error
+	ReleaseInstancePublicIPs(ctx context.Context, folderID, instanceID string) error
+	RestoreInstancePublicIPs(ctx context.Context, folderID, instanceID string) error
+	SnapshotInstanceDisks(ctx context.Context, folderID, instanceID string, retention int) error
+	CreateDiskSnapshot(ctx context.Context, folderID, diskID, name string) error
+	ListDiskSnapshots(ctx context.Context, folderID, diskID string) ([]*computepb.Snapshot, error)
+	DeleteSnapshot(ctx context.Context, folderID, snapshotID string) error
+	StartInstanceGroup(ctx context.Context, folderID, groupID string) error
+	StopInstanceGroup(ctx context.Context, folderID, groupID string) error
+	PauseInstanceGroup(ctx context.Context, folderID, groupID string) error
+	ResumeInstanceGroup(ctx context.Context, folderID, groupID string) error
+	GetInstanceGroup(ctx context.Context, folderID, groupID string) (*instancegrouppb.InstanceGroup, error)
+	ScaleInstanceGroup(ctx context.Context, folderID, groupID string, size int64) error
+}
+
+// K8sAPI covers Managed Kubernetes clusters and node groups.
+type K8sAPI interface {
 	StartCluster(ctx context.Context, folderID, clusterID string) error
 	StopCluster(ctx context.Context, folderID, clusterID string) error
 	GetCluster(ctx context.Context, folderID, clusterID string) (*k8spb.Cluster, error)
+	ListClusters(ctx context.Context, folderID string) ([]*k8spb.Cluster, error)
+	StartNodeGroup(ctx context.Context, folderID, nodeGroupID string) error
+	StopNodeGroup(ctx context.Context, folderID, nodeGroupID string) error
+	GetNodeGroup(ctx context.Context, folderID, nodeGroupID string) (*k8spb.NodeGroup, error)
+	ListNodeGroups(ctx context.Context, folderID string) ([]*k8spb.NodeGroup, error)
+	ScaleNodeGroup(ctx context.Context, folderID, nodeGroupID string, size int64) error
+}
+
+// OperationsAPI waits for long-running Yandex Cloud operations.
+type OperationsAPI interface {
+	WaitOperation(ctx context.Context, operationID string) error
+}
+
+// ClientInterface defines the interface for Yandex Cloud client operations.
+// Consumers depending on it, or on one of the narrower APIs, can substitute
+// mocks for *Client.
+type ClientInterface interface {
+	ComputeAPI
+	K8sAPI
+	OperationsAPI
+	ValidateCredentials(ctx context.Context) error
+	GetAddressByValue(ctx context.Context, ipv4 string) (*vpcpb.Address, error)
+	SetAddressReserved(ctx context.Context, addressID string, reserved bool) error
+	GetAddress(ctx context.Context, folderID, addressID string) (*vpcpb.Address, error)
+	DisableNATGateway(ctx context.Context, folderID, gatewayID string) error
+	EnableNATGateway(ctx context.Context, folderID, gatewayID string) error
+	IsNATGatewayRouted(ctx context.Context, folderID, gatewayID string) (bool, error)
+	ListRouteTables(ctx context.Context, folderID string) ([]*vpcpb.RouteTable, error)
+	UpdateRouteTable(ctx context.Context, routeTableID string, labels map[string]string, routes []*vpcpb.StaticRoute) error
+	StartMongoDBCluster(ctx context.Context, folderID, clusterID string) error
+	StopMongoDBCluster(ctx context.Context, folderID, clusterID string) error
+	GetMongoDBCluster(ctx context.Context, folderID, clusterID string) (*mongodbpb.Cluster, error)
+	StartGreenplumCluster(ctx context.Context, folderID, clusterID string) error
+	StopGreenplumCluster(ctx context.Context, folderID, clusterID string) error
+	GetGreenplumCluster(ctx context.Context, folderID, clusterID string) (*greenplumpb.Cluster, error)
+	StartLoadBalancer(ctx context.Context, folderID, loadBalancerID string) error
+	StopLoadBalancer(ctx context.Context, folderID, loadBalancerID string) error
+	GetLoadBalancer(ctx context.Context, folderID, loadBalancerID string) (*albpb.LoadBalancer, error)
+	SetContainerProvisionedInstances(ctx context.Context, folderID, containerID string, instances int64) error
+	GetContainerRevision(ctx context.Context, folderID, containerID string) (*containerspb.Revision, error)
+	GetFolder(ctx context.Context, folderID string) (*resourcemanagerpb.Folder, error)
 	Shutdown(ctx context.Context) error
 }
 
 // Client wraps Yandex Cloud SDK and provides a narrow interface for
 // higher-level components such as the scheduler.
 type Client struct {
-	sdk *ycsdk.SDK
+	sdk         *ycsdk.SDK
+	compression bool
+	tokenAuth   bool
 }
 
 // Ensure Client implements ClientInterface.
@@ -49,9 +124,27 @@ type AuthConfig struct {
 	Token string
 }
 
+// ClientOptions tunes how the client talks to Yandex Cloud APIs.
+type ClientOptions struct {
+	// Endpoint sends all API calls to the given host:port instead of the
+	// endpoints discovered from Yandex Cloud, e.g. to a stub API server in
+	// integration tests. Without credentials, calls are unauthenticated.
+	Endpoint string
+
+	// Plaintext disables TLS for Endpoint.
+	Plaintext bool
+
+	// Compression enables gzip compression of List requests and responses.
+	Compression bool
+
+	// Interceptors run after the built-in interceptors on every unary call,
+	// e.g. to inject failures in resilience tests.
+	Interceptors []grpc.UnaryClientInterceptor
+}
+
 // NewClient creates a new Yandex Cloud SDK client using the provided
-// authentication configuration.
-func NewClient(ctx context.Context, auth AuthConfig) (*Client, error) {
+// authentication configuration and options.
+func NewClient(ctx context.Context, auth AuthConfig, opts ClientOptions) (*Client, error) {
 	var creds credentials.Credentials
 
 	switch {
@@ -63,20 +156,44 @@ func NewClient(ctx context.Context, auth AuthConfig) (*Client, error) {
 		}
 	case auth.Token != "":
 		creds = credentials.OAuthToken(auth.Token)
+	case opts.Endpoint != "":
+		creds = credentials.NoAuthentication()
 	default:
 		return nil, fmt.Errorf("yc: %w", ErrMissingCredentials)
 	}
 
-	sdk, err := ycsdk.Build(ctx, options.WithCredentials(creds))
+	sdkOpts := []options.Option{
+		options.WithCredentials(creds),
+		options.WithCustomDialOptions(
+			grpc.WithUserAgent(userAgent()),
+			grpc.WithChainUnaryInterceptor(append([]grpc.UnaryClientInterceptor{requestIDInterceptor}, opts.Interceptors...)...),
+		),
+	}
+	if opts.Endpoint != "" {
+		sdkOpts = append(sdkOpts, options.WithEndpointsResolver(endpoints.NewSingleEndpointResolver(opts.Endpoint)))
+	}
+	if opts.Plaintext {
+		sdkOpts = append(sdkOpts, options.WithPlaintext())
+	}
+
+	sdk, err := ycsdk.Build(ctx, sdkOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("yc: build SDK: %w", err)
 	}
 
 	return &Client{
-		sdk: sdk,
+		sdk:         sdk,
+		compression: opts.Compression,
+		tokenAuth:   auth.ServiceAccountKeyFile == "" && auth.Token != "",
 	}, nil
 }
 
+// UsesTokenAuth reports whether the client authenticates with a deprecated
+// OAuth/IAM token instead of a service account key.
+func (c *Client) UsesTokenAuth() bool {
+	return c != nil && c.tokenAuth
+}
+
 // ValidateCredentials checks if the current credentials are valid by attempting
 // to get a connection to Compute service, which requires authentication. This verifies
 // that the token/SA key is valid and not expired.
@@ -142,6 +259,7 @@ func (c *Client) getConnection(ctx context.Context, endpoint protoreflect.FullNa
 // getResource is a generic helper function that encapsulates the common logic
 // for Get operations (GetInstance, GetCluster, etc.).
 // It handles initialization check, connection retrieval, and error formatting.
+// getFunc runs with an explicit call deadline, see withCallDeadline.
 func getResource[T any](
 	ctx context.Context,
 	c *Client,
@@ -159,9 +277,12 @@ func getResource[T any](
 		return zero, err
 	}
 
-	result, err := getFunc(ctx, conn)
+	callCtx, cancel := withCallDeadline(ctx)
+	defer cancel()
+
+	result, err := getFunc(callCtx, conn)
 	if err != nil {
-		return zero, fmt.Errorf("yc: %s %s: %w", operation, resourceID, err)
+		return zero, wrapCallError(operation, resourceID, err)
 	}
 
 	return result, nil
@@ -171,6 +292,9 @@ func getResource[T any](
 // for Start/Stop operations (StartInstance, StopInstance, StartCluster, StopCluster, etc.).
 // It handles initialization check, connection retrieval, operation execution,
 // and waiting for operation completion.
+// opFunc runs with an explicit call deadline, see withCallDeadline.
+// If opFunc returns errNothingToDo, the resource is already in the requested
+// state and no operation is awaited.
 func executeOperation(
 	ctx context.Context,
 	c *Client,
@@ -187,14 +311,28 @@ func executeOperation(
 		return err
 	}
 
-	operationID, err := opFunc(ctx, conn)
+	callCtx, cancel := withCallDeadline(ctx)
+	operationID, err := opFunc(callCtx, conn)
+	cancel()
+	if errors.Is(err, errNothingToDo) {
+		return nil
+	}
 	if err != nil {
-		return fmt.Errorf("yc: %s %s: %w", operation, resourceID, err)
+		return wrapCallError(operation, resourceID, err)
 	}
 
 	return waitOperation(ctx, c.sdk, operationID)
 }
 
+// WaitOperation waits until the operation with the given ID completes and
+// returns its error, if any.
+func (c *Client) WaitOperation(ctx context.Context, operationID string) error {
+	if err := c.ensureInitialized(); err != nil {
+		return err
+	}
+	return waitOperation(ctx, c.sdk, operationID)
+}
+
 // Shutdown gracefully shuts down the underlying SDK, releasing any
 // held resources and terminating background goroutines.
 func (c *Client) Shutdown(ctx context.Context) error {
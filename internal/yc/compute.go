@@ -9,7 +9,10 @@ import (
 	operationpb "github.com/yandex-cloud/go-genproto/yandex/cloud/operation"
 	ycsdk "github.com/yandex-cloud/go-sdk/v2"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 // StartInstance starts a compute instance in the specified folder.
@@ -56,12 +59,89 @@ func (c *Client) GetInstance(ctx context.Context, folderID, instanceID string) (
 	})
 }
 
+// ListInstances returns all compute instances in the folder.
+func (c *Client) ListInstances(ctx context.Context, folderID string) ([]*computepb.Instance, error) {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.compute.v1.InstanceService.List")
+	return getResource(ctx, c, endpoint, "list instances", folderID, func(ctx context.Context, conn grpc.ClientConnInterface) ([]*computepb.Instance, error) {
+		client := computepb.NewInstanceServiceClient(conn)
+		return listAll(ctx, func(ctx context.Context, pageToken string) ([]*computepb.Instance, string, error) {
+			resp, err := client.List(ctx, &computepb.ListInstancesRequest{
+				FolderId:  folderID,
+				PageSize:  listPageSize,
+				PageToken: pageToken,
+			}, c.listCallOptions()...)
+			return resp.GetInstances(), resp.GetNextPageToken(), err
+		})
+	})
+}
+
+// UpdateInstanceLabels replaces the labels of a compute instance.
+func (c *Client) UpdateInstanceLabels(ctx context.Context, folderID, instanceID string, labels map[string]string) error {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.compute.v1.InstanceService.Update")
+	return executeOperation(ctx, c, endpoint, "update instance labels", instanceID, func(ctx context.Context, conn grpc.ClientConnInterface) (string, error) {
+		client := computepb.NewInstanceServiceClient(conn)
+		op, err := client.Update(ctx, &computepb.UpdateInstanceRequest{
+			InstanceId: instanceID,
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"labels"}},
+			Labels:     labels,
+		})
+		if err != nil {
+			return "", err
+		}
+		return op.GetId(), nil
+	})
+}
+
+// AddInstanceOneToOneNat attaches the reserved public IPv4 address to the
+// network interface with the given index, or a new ephemeral address when
+// address is empty.
+func (c *Client) AddInstanceOneToOneNat(ctx context.Context, folderID, instanceID, networkInterfaceIndex, address string) error {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.compute.v1.InstanceService.AddOneToOneNat")
+	return executeOperation(ctx, c, endpoint, "add instance one-to-one NAT", instanceID, func(ctx context.Context, conn grpc.ClientConnInterface) (string, error) {
+		client := computepb.NewInstanceServiceClient(conn)
+		op, err := client.AddOneToOneNat(ctx, &computepb.AddInstanceOneToOneNatRequest{
+			InstanceId:            instanceID,
+			NetworkInterfaceIndex: networkInterfaceIndex,
+			OneToOneNatSpec: &computepb.OneToOneNatSpec{
+				Address:   address,
+				IpVersion: computepb.IpVersion_IPV4,
+			},
+		})
+		if err != nil {
+			return "", err
+		}
+		return op.GetId(), nil
+	})
+}
+
+// RemoveInstanceOneToOneNat detaches the public IP address from the network
+// interface with the given index.
+func (c *Client) RemoveInstanceOneToOneNat(ctx context.Context, folderID, instanceID, networkInterfaceIndex string) error {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.compute.v1.InstanceService.RemoveOneToOneNat")
+	return executeOperation(ctx, c, endpoint, "remove instance one-to-one NAT", instanceID, func(ctx context.Context, conn grpc.ClientConnInterface) (string, error) {
+		client := computepb.NewInstanceServiceClient(conn)
+		op, err := client.RemoveOneToOneNat(ctx, &computepb.RemoveInstanceOneToOneNatRequest{
+			InstanceId:            instanceID,
+			NetworkInterfaceIndex: networkInterfaceIndex,
+		})
+		if err != nil {
+			return "", err
+		}
+		return op.GetId(), nil
+	})
+}
+
 // waitOperation polls the Operation service until the operation with the
 // given ID is completed or the context is canceled.
 func waitOperation(ctx context.Context, sdk *ycsdk.SDK, operationID string) error {
 	if operationID == "" {
 		return fmt.Errorf("yc: %w: empty operation id", ErrOperationFailed)
 	}
+	recordOperation(ctx, operationID)
 
 	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
 	endpoint := protoreflect.FullName("yandex.cloud.operation.OperationService.Get")
@@ -78,11 +158,18 @@ func waitOperation(ctx context.Context, sdk *ycsdk.SDK, operationID string) erro
 	for {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return wrapCallError("wait operation", operationID, ctx.Err())
 		case <-ticker.C:
-			op, err := client.Get(ctx, &operationpb.GetOperationRequest{OperationId: operationID})
+			callCtx, cancel := withCallDeadline(ctx)
+			op, err := client.Get(callCtx, &operationpb.GetOperationRequest{OperationId: operationID})
+			cancel()
 			if err != nil {
-				return fmt.Errorf("yc: get operation %s: %w", operationID, err)
+				// A timed out poll is retried on the next tick while the job
+				// deadline allows it.
+				if status.Code(err) == codes.DeadlineExceeded && ctx.Err() == nil {
+					continue
+				}
+				return wrapCallError("get operation", operationID, err)
 			}
 
 			if !op.GetDone() {
@@ -0,0 +1,36 @@
+package yc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// callTimeout caps a single Yandex Cloud API call. The effective gRPC
+// deadline is the smaller of this value and the job's remaining timeout.
+const callTimeout = 30 * time.Second
+
+// withCallDeadline returns a context with an explicit deadline for one API
+// call, so a hung call fails without consuming the whole job timeout.
+func withCallDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, callTimeout)
+}
+
+// IsDeadlineExceeded reports whether err is caused by an expired call or job
+// deadline.
+func IsDeadlineExceeded(err error) bool {
+	return errors.Is(err, ErrDeadlineExceeded) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// wrapCallError formats an API call error and marks expired deadlines with
+// ErrDeadlineExceeded.
+func wrapCallError(operation, resourceID string, err error) error {
+	if status.Code(err) == codes.DeadlineExceeded || errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("yc: %s %s: %w: %w", operation, resourceID, ErrDeadlineExceeded, err)
+	}
+	return fmt.Errorf("yc: %s %s: %w", operation, resourceID, err)
+}
@@ -0,0 +1,29 @@
+package yc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWrapCallErrorMarksDeadlineExceeded(t *testing.T) {
+	t.Parallel()
+
+	err := wrapCallError("start instance", "fhm123", status.Error(codes.DeadlineExceeded, "timeout"))
+	if !errors.Is(err, ErrDeadlineExceeded) || !IsDeadlineExceeded(err) {
+		t.Fatalf("wrapCallError() = %v, want ErrDeadlineExceeded", err)
+	}
+
+	err = wrapCallError("wait operation", "op1", context.DeadlineExceeded)
+	if !errors.Is(err, ErrDeadlineExceeded) {
+		t.Fatalf("wrapCallError(context deadline) = %v, want ErrDeadlineExceeded", err)
+	}
+
+	err = wrapCallError("start instance", "fhm123", status.Error(codes.NotFound, "missing"))
+	if IsDeadlineExceeded(err) {
+		t.Fatalf("wrapCallError(NotFound) = %v, want no deadline error", err)
+	}
+}
@@ -23,11 +23,35 @@ var (
 	// group cannot be found in the specified folder.
 	ErrNodeGroupNotFound = errors.New("node group not found")
 
+	// ErrContainerRevisionNotFound is returned when a serverless container
+	// has no active revision to copy.
+	ErrContainerRevisionNotFound = errors.New("active container revision not found")
+
+	// ErrUnsupportedScalePolicy is returned when a group uses a scale policy
+	// that cannot be scaled to zero and restored (e.g. auto scale).
+	ErrUnsupportedScalePolicy = errors.New("unsupported scale policy")
+
+	// ErrSavedScaleMissing is returned when a group scaled to zero has no
+	// saved scale size to restore on start.
+	ErrSavedScaleMissing = errors.New("saved scale size is missing")
+
+	// ErrTooManyLabels is returned when the labels recording the state of a
+	// stopped resource do not fit the label limit of a resource.
+	ErrTooManyLabels = errors.New("too many labels")
+
 	// ErrOperationFailed is returned when a long-running Yandex Cloud
 	// operation finishes in a failed state.
 	ErrOperationFailed = errors.New("operation failed")
 
+	// ErrDeadlineExceeded is returned when a Yandex Cloud API call or
+	// operation wait does not finish before its deadline.
+	ErrDeadlineExceeded = errors.New("deadline exceeded")
+
 	// ErrClientNotInitialized is returned when a Client method is called
 	// before the client has been properly initialized with NewClient.
 	ErrClientNotInitialized = errors.New("client is not initialized")
+
+	// errNothingToDo is returned by operation callbacks when the resource is
+	// already in the requested state and no API operation was started.
+	errNothingToDo = errors.New("nothing to do")
 )
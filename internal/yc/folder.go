@@ -0,0 +1,21 @@
+package yc
+
+import (
+	"context"
+
+	resourcemanagerpb "github.com/yandex-cloud/go-genproto/yandex/cloud/resourcemanager/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// GetFolder retrieves a cloud folder.
+func (c *Client) GetFolder(ctx context.Context, folderID string) (*resourcemanagerpb.Folder, error) {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.resourcemanager.v1.FolderService.Get")
+	return getResource(ctx, c, endpoint, "get folder", folderID, func(ctx context.Context, conn grpc.ClientConnInterface) (*resourcemanagerpb.Folder, error) {
+		client := resourcemanagerpb.NewFolderServiceClient(conn)
+		return client.Get(ctx, &resourcemanagerpb.GetFolderRequest{
+			FolderId: folderID,
+		})
+	})
+}
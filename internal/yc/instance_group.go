@@ -0,0 +1,227 @@
+package yc
+
+import (
+	"context"
+	"fmt"
+
+	instancegrouppb "github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1/instancegroup"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// StartInstanceGroup restores the fixed scale size of an instance group that
+// was previously scaled to zero by StopInstanceGroup.
+// It is a no-op if the group already has a non-zero size.
+func (c *Client) StartInstanceGroup(ctx context.Context, folderID, groupID string) error {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.compute.v1.instancegroup.InstanceGroupService.Update")
+	return executeOperation(ctx, c, endpoint, "start instance group", groupID, func(ctx context.Context, conn grpc.ClientConnInterface) (string, error) {
+		return startInstanceGroup(ctx, instancegrouppb.NewInstanceGroupServiceClient(conn), groupID)
+	})
+}
+
+// StopInstanceGroup scales an instance group to zero instances and records
+// the previous fixed scale size in a group label for StartInstanceGroup.
+// It is a no-op if the group is already scaled to zero.
+func (c *Client) StopInstanceGroup(ctx context.Context, folderID, groupID string) error {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.compute.v1.instancegroup.InstanceGroupService.Update")
+	return executeOperation(ctx, c, endpoint, "stop instance group", groupID, func(ctx context.Context, conn grpc.ClientConnInterface) (string, error) {
+		return stopInstanceGroup(ctx, instancegrouppb.NewInstanceGroupServiceClient(conn), groupID)
+	})
+}
+
+// ScaleInstanceGroup sets the fixed scale size of an instance group.
+// A saved size left by StopInstanceGroup is dropped, as the group is scaled
+// explicitly. It is a no-op if the group already has the size.
+func (c *Client) ScaleInstanceGroup(ctx context.Context, folderID, groupID string, size int64) error {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.compute.v1.instancegroup.InstanceGroupService.Update")
+	return executeOperation(ctx, c, endpoint, "scale instance group", groupID, func(ctx context.Context, conn grpc.ClientConnInterface) (string, error) {
+		return scaleInstanceGroup(ctx, instancegrouppb.NewInstanceGroupServiceClient(conn), groupID, size)
+	})
+}
+
+// startInstanceGroup restores the saved fixed scale size of the group and
+// returns the ID of the update operation, or errNothingToDo if the group is
+// not scaled to zero.
+func startInstanceGroup(ctx context.Context, client instancegrouppb.InstanceGroupServiceClient, groupID string) (string, error) {
+	group, err := client.Get(ctx, &instancegrouppb.GetInstanceGroupRequest{
+		InstanceGroupId: groupID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	current, err := fixedScaleSize(group)
+	if err != nil {
+		return "", err
+	}
+	if current > 0 {
+		return "", errNothingToDo
+	}
+
+	size, err := savedSize(group.GetLabels())
+	if err != nil {
+		return "", err
+	}
+
+	op, err := client.Update(ctx, &instancegrouppb.UpdateInstanceGroupRequest{
+		InstanceGroupId: groupID,
+		UpdateMask:      &fieldmaskpb.FieldMask{Paths: []string{"labels", "scale_policy"}},
+		Labels:          withoutSavedSize(group.GetLabels()),
+		ScalePolicy:     newFixedScalePolicy(size),
+	})
+	if err != nil {
+		return "", err
+	}
+	return op.GetId(), nil
+}
+
+// stopInstanceGroup scales the group to zero, saving its fixed scale size in
+// a label, and returns the ID of the update operation, or errNothingToDo if
+// the group is already scaled to zero.
+func stopInstanceGroup(ctx context.Context, client instancegrouppb.InstanceGroupServiceClient, groupID string) (string, error) {
+	group, err := client.Get(ctx, &instancegrouppb.GetInstanceGroupRequest{
+		InstanceGroupId: groupID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	current, err := fixedScaleSize(group)
+	if err != nil {
+		return "", err
+	}
+	if current == 0 {
+		return "", errNothingToDo
+	}
+
+	op, err := client.Update(ctx, &instancegrouppb.UpdateInstanceGroupRequest{
+		InstanceGroupId: groupID,
+		UpdateMask:      &fieldmaskpb.FieldMask{Paths: []string{"labels", "scale_policy"}},
+		Labels:          withSavedSize(group.GetLabels(), current),
+		ScalePolicy:     newFixedScalePolicy(0),
+	})
+	if err != nil {
+		return "", err
+	}
+	return op.GetId(), nil
+}
+
+// scaleInstanceGroup sets the fixed scale size of the group, dropping a saved
+// size, and returns the ID of the update operation, or errNothingToDo if the
+// group already has the size.
+func scaleInstanceGroup(ctx context.Context, client instancegrouppb.InstanceGroupServiceClient, groupID string, size int64) (string, error) {
+	group, err := client.Get(ctx, &instancegrouppb.GetInstanceGroupRequest{
+		InstanceGroupId: groupID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	current, err := fixedScaleSize(group)
+	if err != nil {
+		return "", err
+	}
+	if current == size {
+		return "", errNothingToDo
+	}
+
+	op, err := client.Update(ctx, &instancegrouppb.UpdateInstanceGroupRequest{
+		InstanceGroupId: groupID,
+		UpdateMask:      &fieldmaskpb.FieldMask{Paths: []string{"labels", "scale_policy"}},
+		Labels:          withoutSavedSize(group.GetLabels()),
+		ScalePolicy:     newFixedScalePolicy(size),
+	})
+	if err != nil {
+		return "", err
+	}
+	return op.GetId(), nil
+}
+
+// PauseInstanceGroup pauses the management processes of an instance group,
+// leaving its instances as they are. It is a no-op if the group is already
+// paused.
+func (c *Client) PauseInstanceGroup(ctx context.Context, folderID, groupID string) error {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.compute.v1.instancegroup.InstanceGroupService.PauseProcesses")
+	return executeOperation(ctx, c, endpoint, "pause instance group", groupID, func(ctx context.Context, conn grpc.ClientConnInterface) (string, error) {
+		client := instancegrouppb.NewInstanceGroupServiceClient(conn)
+		group, err := client.Get(ctx, &instancegrouppb.GetInstanceGroupRequest{
+			InstanceGroupId: groupID,
+		})
+		if err != nil {
+			return "", err
+		}
+		if group.GetStatus() == instancegrouppb.InstanceGroup_PAUSED {
+			return "", errNothingToDo
+		}
+
+		op, err := client.PauseProcesses(ctx, &instancegrouppb.PauseInstanceGroupProcessesRequest{
+			InstanceGroupId: groupID,
+		})
+		if err != nil {
+			return "", err
+		}
+		return op.GetId(), nil
+	})
+}
+
+// ResumeInstanceGroup resumes the management processes of an instance group
+// paused by PauseInstanceGroup. It is a no-op if the group is not paused.
+func (c *Client) ResumeInstanceGroup(ctx context.Context, folderID, groupID string) error {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.compute.v1.instancegroup.InstanceGroupService.ResumeProcesses")
+	return executeOperation(ctx, c, endpoint, "resume instance group", groupID, func(ctx context.Context, conn grpc.ClientConnInterface) (string, error) {
+		client := instancegrouppb.NewInstanceGroupServiceClient(conn)
+		group, err := client.Get(ctx, &instancegrouppb.GetInstanceGroupRequest{
+			InstanceGroupId: groupID,
+		})
+		if err != nil {
+			return "", err
+		}
+		if group.GetStatus() != instancegrouppb.InstanceGroup_PAUSED {
+			return "", errNothingToDo
+		}
+
+		op, err := client.ResumeProcesses(ctx, &instancegrouppb.ResumeInstanceGroupProcessesRequest{
+			InstanceGroupId: groupID,
+		})
+		if err != nil {
+			return "", err
+		}
+		return op.GetId(), nil
+	})
+}
+
+// GetInstanceGroup retrieves the current state of an instance group.
+func (c *Client) GetInstanceGroup(ctx context.Context, folderID, groupID string) (*instancegrouppb.InstanceGroup, error) {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.compute.v1.instancegroup.InstanceGroupService.Get")
+	return getResource(ctx, c, endpoint, "get instance group", groupID, func(ctx context.Context, conn grpc.ClientConnInterface) (*instancegrouppb.InstanceGroup, error) {
+		client := instancegrouppb.NewInstanceGroupServiceClient(conn)
+		return client.Get(ctx, &instancegrouppb.GetInstanceGroupRequest{
+			InstanceGroupId: groupID,
+		})
+	})
+}
+
+// fixedScaleSize returns the fixed scale size of an instance group or
+// ErrUnsupportedScalePolicy if the group is not using a fixed scale policy.
+func fixedScaleSize(group *instancegrouppb.InstanceGroup) (int64, error) {
+	fixed := group.GetScalePolicy().GetFixedScale()
+	if fixed == nil {
+		return 0, fmt.Errorf("%w: instance group %s does not use fixed scale", ErrUnsupportedScalePolicy, group.GetId())
+	}
+	return fixed.GetSize(), nil
+}
+
+func newFixedScalePolicy(size int64) *instancegrouppb.ScalePolicy {
+	return &instancegrouppb.ScalePolicy{
+		ScaleType: &instancegrouppb.ScalePolicy_FixedScale_{
+			FixedScale: &instancegrouppb.ScalePolicy_FixedScale{Size: size},
+		},
+	}
+}
@@ -0,0 +1,163 @@
+package yc
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"testing"
+
+	instancegrouppb "github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1/instancegroup"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/operation"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeInstanceGroups serves a single instance group and applies updates of
+// its labels and scale policy.
+type fakeInstanceGroups struct {
+	instancegrouppb.InstanceGroupServiceClient
+	group   *instancegrouppb.InstanceGroup
+	updates int
+}
+
+func newFakeInstanceGroups(size int64, labels map[string]string) *fakeInstanceGroups {
+	return &fakeInstanceGroups{group: &instancegrouppb.InstanceGroup{
+		Id:          "ig-1",
+		Labels:      labels,
+		ScalePolicy: newFixedScalePolicy(size),
+	}}
+}
+
+func (f *fakeInstanceGroups) Get(context.Context, *instancegrouppb.GetInstanceGroupRequest, ...grpc.CallOption) (*instancegrouppb.InstanceGroup, error) {
+	return proto.Clone(f.group).(*instancegrouppb.InstanceGroup), nil
+}
+
+func (f *fakeInstanceGroups) Update(_ context.Context, req *instancegrouppb.UpdateInstanceGroupRequest, _ ...grpc.CallOption) (*operation.Operation, error) {
+	f.updates++
+	f.group.Labels = req.GetLabels()
+	f.group.ScalePolicy = req.GetScalePolicy()
+	return &operation.Operation{Id: "op-1"}, nil
+}
+
+func (f *fakeInstanceGroups) size() int64 {
+	return f.group.GetScalePolicy().GetFixedScale().GetSize()
+}
+
+func TestInstanceGroupStopStartRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		size   int64
+		labels map[string]string
+	}{
+		{name: "single instance", size: 1},
+		{name: "with labels", size: 3, labels: map[string]string{"env": "dev"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			groups := newFakeInstanceGroups(tt.size, maps.Clone(tt.labels))
+			ctx := context.Background()
+
+			if _, err := stopInstanceGroup(ctx, groups, "ig-1"); err != nil {
+				t.Fatalf("stopInstanceGroup() error = %v", err)
+			}
+			if groups.size() != 0 {
+				t.Fatalf("size after stop = %d, want 0", groups.size())
+			}
+			if saved, err := savedSize(groups.group.GetLabels()); err != nil || saved != tt.size {
+				t.Fatalf("saved size after stop = %d, %v; want %d", saved, err, tt.size)
+			}
+
+			if _, err := startInstanceGroup(ctx, groups, "ig-1"); err != nil {
+				t.Fatalf("startInstanceGroup() error = %v", err)
+			}
+			if groups.size() != tt.size {
+				t.Fatalf("size after start = %d, want %d", groups.size(), tt.size)
+			}
+			// The saved size is dropped and other labels are kept.
+			if got := groups.group.GetLabels(); !maps.Equal(got, tt.labels) {
+				t.Fatalf("labels after start = %v, want %v", got, tt.labels)
+			}
+			if groups.updates != 2 {
+				t.Fatalf("updates = %d, want 2", groups.updates)
+			}
+		})
+	}
+}
+
+func TestInstanceGroupOperationsWithNothingToDo(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		size    int64
+		labels  map[string]string
+		operate func(context.Context, instancegrouppb.InstanceGroupServiceClient) (string, error)
+		wantErr error
+	}{
+		{
+			name: "start running group",
+			size: 2,
+			operate: func(ctx context.Context, client instancegrouppb.InstanceGroupServiceClient) (string, error) {
+				return startInstanceGroup(ctx, client, "ig-1")
+			},
+			wantErr: errNothingToDo,
+		},
+		{
+			name:   "stop stopped group",
+			labels: map[string]string{savedSizeLabel: "2"},
+			operate: func(ctx context.Context, client instancegrouppb.InstanceGroupServiceClient) (string, error) {
+				return stopInstanceGroup(ctx, client, "ig-1")
+			},
+			wantErr: errNothingToDo,
+		},
+		{
+			name: "scale to current size",
+			size: 2,
+			operate: func(ctx context.Context, client instancegrouppb.InstanceGroupServiceClient) (string, error) {
+				return scaleInstanceGroup(ctx, client, "ig-1", 2)
+			},
+			wantErr: errNothingToDo,
+		},
+		{
+			name: "start without saved size",
+			operate: func(ctx context.Context, client instancegrouppb.InstanceGroupServiceClient) (string, error) {
+				return startInstanceGroup(ctx, client, "ig-1")
+			},
+			wantErr: ErrSavedScaleMissing,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			groups := newFakeInstanceGroups(tt.size, tt.labels)
+			if _, err := tt.operate(context.Background(), groups); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if groups.updates != 0 {
+				t.Fatalf("updates = %d, want none", groups.updates)
+			}
+		})
+	}
+}
+
+func TestScaleInstanceGroupDropsSavedSize(t *testing.T) {
+	t.Parallel()
+
+	groups := newFakeInstanceGroups(0, map[string]string{savedSizeLabel: "3"})
+	if _, err := scaleInstanceGroup(context.Background(), groups, "ig-1", 5); err != nil {
+		t.Fatalf("scaleInstanceGroup() error = %v", err)
+	}
+	if groups.size() != 5 {
+		t.Fatalf("size = %d, want 5", groups.size())
+	}
+	if _, exists := groups.group.GetLabels()[savedSizeLabel]; exists {
+		t.Fatal("scaleInstanceGroup() kept the saved size label")
+	}
+}
@@ -52,3 +52,20 @@ func (c *Client) GetCluster(ctx context.Context, folderID, clusterID string) (*k
 		})
 	})
 }
+
+// ListClusters returns all Kubernetes clusters in the folder.
+func (c *Client) ListClusters(ctx context.Context, folderID string) ([]*k8spb.Cluster, error) {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.k8s.v1.ClusterService.List")
+	return getResource(ctx, c, endpoint, "list clusters", folderID, func(ctx context.Context, conn grpc.ClientConnInterface) ([]*k8spb.Cluster, error) {
+		client := k8spb.NewClusterServiceClient(conn)
+		return listAll(ctx, func(ctx context.Context, pageToken string) ([]*k8spb.Cluster, string, error) {
+			resp, err := client.List(ctx, &k8spb.ListClustersRequest{
+				FolderId:  folderID,
+				PageSize:  listPageSize,
+				PageToken: pageToken,
+			}, c.listCallOptions()...)
+			return resp.GetClusters(), resp.GetNextPageToken(), err
+		})
+	})
+}
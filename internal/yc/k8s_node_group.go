@@ -0,0 +1,171 @@
+package yc
+
+import (
+	"context"
+	"fmt"
+
+	k8spb "github.com/yandex-cloud/go-genproto/yandex/cloud/k8s/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// StartNodeGroup restores the fixed scale size of a Kubernetes node group
+// that was previously scaled to zero by StopNodeGroup.
+// It is a no-op if the node group already has a non-zero size.
+func (c *Client) StartNodeGroup(ctx context.Context, folderID, nodeGroupID string) error {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.k8s.v1.NodeGroupService.Update")
+	return executeOperation(ctx, c, endpoint, "start node group", nodeGroupID, func(ctx context.Context, conn grpc.ClientConnInterface) (string, error) {
+		client := k8spb.NewNodeGroupServiceClient(conn)
+		nodeGroup, err := client.Get(ctx, &k8spb.GetNodeGroupRequest{
+			NodeGroupId: nodeGroupID,
+		})
+		if err != nil {
+			return "", err
+		}
+
+		current, err := nodeGroupFixedScaleSize(nodeGroup)
+		if err != nil {
+			return "", err
+		}
+		if current > 0 {
+			return "", errNothingToDo
+		}
+
+		size, err := savedSize(nodeGroup.GetLabels())
+		if err != nil {
+			return "", err
+		}
+
+		op, err := client.Update(ctx, &k8spb.UpdateNodeGroupRequest{
+			NodeGroupId: nodeGroupID,
+			UpdateMask:  &fieldmaskpb.FieldMask{Paths: []string{"labels", "scale_policy"}},
+			Labels:      withoutSavedSize(nodeGroup.GetLabels()),
+			ScalePolicy: newNodeGroupFixedScalePolicy(size),
+		})
+		if err != nil {
+			return "", err
+		}
+		return op.GetId(), nil
+	})
+}
+
+// StopNodeGroup scales a Kubernetes node group to zero nodes and records the
+// previous fixed scale size in a node group label for StartNodeGroup.
+// It is a no-op if the node group is already scaled to zero.
+func (c *Client) StopNodeGroup(ctx context.Context, folderID, nodeGroupID string) error {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.k8s.v1.NodeGroupService.Update")
+	return executeOperation(ctx, c, endpoint, "stop node group", nodeGroupID, func(ctx context.Context, conn grpc.ClientConnInterface) (string, error) {
+		client := k8spb.NewNodeGroupServiceClient(conn)
+		nodeGroup, err := client.Get(ctx, &k8spb.GetNodeGroupRequest{
+			NodeGroupId: nodeGroupID,
+		})
+		if err != nil {
+			return "", err
+		}
+
+		current, err := nodeGroupFixedScaleSize(nodeGroup)
+		if err != nil {
+			return "", err
+		}
+		if current == 0 {
+			return "", errNothingToDo
+		}
+
+		op, err := client.Update(ctx, &k8spb.UpdateNodeGroupRequest{
+			NodeGroupId: nodeGroupID,
+			UpdateMask:  &fieldmaskpb.FieldMask{Paths: []string{"labels", "scale_policy"}},
+			Labels:      withSavedSize(nodeGroup.GetLabels(), current),
+			ScalePolicy: newNodeGroupFixedScalePolicy(0),
+		})
+		if err != nil {
+			return "", err
+		}
+		return op.GetId(), nil
+	})
+}
+
+// ScaleNodeGroup sets the fixed scale size of a Kubernetes node group.
+// A saved size left by StopNodeGroup is dropped, as the group is scaled
+// explicitly. It is a no-op if the node group already has the size.
+func (c *Client) ScaleNodeGroup(ctx context.Context, folderID, nodeGroupID string, size int64) error {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.k8s.v1.NodeGroupService.Update")
+	return executeOperation(ctx, c, endpoint, "scale node group", nodeGroupID, func(ctx context.Context, conn grpc.ClientConnInterface) (string, error) {
+		client := k8spb.NewNodeGroupServiceClient(conn)
+		nodeGroup, err := client.Get(ctx, &k8spb.GetNodeGroupRequest{
+			NodeGroupId: nodeGroupID,
+		})
+		if err != nil {
+			return "", err
+		}
+
+		current, err := nodeGroupFixedScaleSize(nodeGroup)
+		if err != nil {
+			return "", err
+		}
+		if current == size {
+			return "", errNothingToDo
+		}
+
+		op, err := client.Update(ctx, &k8spb.UpdateNodeGroupRequest{
+			NodeGroupId: nodeGroupID,
+			UpdateMask:  &fieldmaskpb.FieldMask{Paths: []string{"labels", "scale_policy"}},
+			Labels:      withoutSavedSize(nodeGroup.GetLabels()),
+			ScalePolicy: newNodeGroupFixedScalePolicy(size),
+		})
+		if err != nil {
+			return "", err
+		}
+		return op.GetId(), nil
+	})
+}
+
+// GetNodeGroup retrieves the current state of a Kubernetes node group.
+func (c *Client) GetNodeGroup(ctx context.Context, folderID, nodeGroupID string) (*k8spb.NodeGroup, error) {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.k8s.v1.NodeGroupService.Get")
+	return getResource(ctx, c, endpoint, "get node group", nodeGroupID, func(ctx context.Context, conn grpc.ClientConnInterface) (*k8spb.NodeGroup, error) {
+		client := k8spb.NewNodeGroupServiceClient(conn)
+		return client.Get(ctx, &k8spb.GetNodeGroupRequest{
+			NodeGroupId: nodeGroupID,
+		})
+	})
+}
+
+// ListNodeGroups returns all Kubernetes node groups in the folder.
+func (c *Client) ListNodeGroups(ctx context.Context, folderID string) ([]*k8spb.NodeGroup, error) {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.k8s.v1.NodeGroupService.List")
+	return getResource(ctx, c, endpoint, "list node groups", folderID, func(ctx context.Context, conn grpc.ClientConnInterface) ([]*k8spb.NodeGroup, error) {
+		client := k8spb.NewNodeGroupServiceClient(conn)
+		return listAll(ctx, func(ctx context.Context, pageToken string) ([]*k8spb.NodeGroup, string, error) {
+			resp, err := client.List(ctx, &k8spb.ListNodeGroupsRequest{
+				FolderId:  folderID,
+				PageSize:  listPageSize,
+				PageToken: pageToken,
+			}, c.listCallOptions()...)
+			return resp.GetNodeGroups(), resp.GetNextPageToken(), err
+		})
+	})
+}
+
+// nodeGroupFixedScaleSize returns the fixed scale size of a node group or
+// ErrUnsupportedScalePolicy if the node group is not using a fixed scale policy.
+func nodeGroupFixedScaleSize(nodeGroup *k8spb.NodeGroup) (int64, error) {
+	fixed := nodeGroup.GetScalePolicy().GetFixedScale()
+	if fixed == nil {
+		return 0, fmt.Errorf("%w: node group %s does not use fixed scale", ErrUnsupportedScalePolicy, nodeGroup.GetId())
+	}
+	return fixed.GetSize(), nil
+}
+
+func newNodeGroupFixedScalePolicy(size int64) *k8spb.ScalePolicy {
+	return &k8spb.ScalePolicy{
+		ScaleType: &k8spb.ScalePolicy_FixedScale_{
+			FixedScale: &k8spb.ScalePolicy_FixedScale{Size: size},
+		},
+	}
+}
@@ -0,0 +1,53 @@
+package yc
+
+import (
+	"context"
+
+	greenplumpb "github.com/yandex-cloud/go-genproto/yandex/cloud/mdb/greenplum/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// StartGreenplumCluster starts the specified Managed Service for Greenplum cluster.
+func (c *Client) StartGreenplumCluster(ctx context.Context, folderID, clusterID string) error {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.mdb.greenplum.v1.ClusterService.Start")
+	return executeOperation(ctx, c, endpoint, "start Greenplum cluster", clusterID, func(ctx context.Context, conn grpc.ClientConnInterface) (string, error) {
+		client := greenplumpb.NewClusterServiceClient(conn)
+		op, err := client.Start(ctx, &greenplumpb.StartClusterRequest{
+			ClusterId: clusterID,
+		})
+		if err != nil {
+			return "", err
+		}
+		return op.GetId(), nil
+	})
+}
+
+// StopGreenplumCluster stops the specified Managed Service for Greenplum cluster.
+func (c *Client) StopGreenplumCluster(ctx context.Context, folderID, clusterID string) error {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.mdb.greenplum.v1.ClusterService.Stop")
+	return executeOperation(ctx, c, endpoint, "stop Greenplum cluster", clusterID, func(ctx context.Context, conn grpc.ClientConnInterface) (string, error) {
+		client := greenplumpb.NewClusterServiceClient(conn)
+		op, err := client.Stop(ctx, &greenplumpb.StopClusterRequest{
+			ClusterId: clusterID,
+		})
+		if err != nil {
+			return "", err
+		}
+		return op.GetId(), nil
+	})
+}
+
+// GetGreenplumCluster retrieves the current state of a Managed Service for Greenplum cluster.
+func (c *Client) GetGreenplumCluster(ctx context.Context, folderID, clusterID string) (*greenplumpb.Cluster, error) {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.mdb.greenplum.v1.ClusterService.Get")
+	return getResource(ctx, c, endpoint, "get Greenplum cluster", clusterID, func(ctx context.Context, conn grpc.ClientConnInterface) (*greenplumpb.Cluster, error) {
+		client := greenplumpb.NewClusterServiceClient(conn)
+		return client.Get(ctx, &greenplumpb.GetClusterRequest{
+			ClusterId: clusterID,
+		})
+	})
+}
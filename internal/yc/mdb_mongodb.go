@@ -0,0 +1,53 @@
+package yc
+
+import (
+	"context"
+
+	mongodbpb "github.com/yandex-cloud/go-genproto/yandex/cloud/mdb/mongodb/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// StartMongoDBCluster starts the specified Managed Service for MongoDB cluster.
+func (c *Client) StartMongoDBCluster(ctx context.Context, folderID, clusterID string) error {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.mdb.mongodb.v1.ClusterService.Start")
+	return executeOperation(ctx, c, endpoint, "start MongoDB cluster", clusterID, func(ctx context.Context, conn grpc.ClientConnInterface) (string, error) {
+		client := mongodbpb.NewClusterServiceClient(conn)
+		op, err := client.Start(ctx, &mongodbpb.StartClusterRequest{
+			ClusterId: clusterID,
+		})
+		if err != nil {
+			return "", err
+		}
+		return op.GetId(), nil
+	})
+}
+
+// StopMongoDBCluster stops the specified Managed Service for MongoDB cluster.
+func (c *Client) StopMongoDBCluster(ctx context.Context, folderID, clusterID string) error {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.mdb.mongodb.v1.ClusterService.Stop")
+	return executeOperation(ctx, c, endpoint, "stop MongoDB cluster", clusterID, func(ctx context.Context, conn grpc.ClientConnInterface) (string, error) {
+		client := mongodbpb.NewClusterServiceClient(conn)
+		op, err := client.Stop(ctx, &mongodbpb.StopClusterRequest{
+			ClusterId: clusterID,
+		})
+		if err != nil {
+			return "", err
+		}
+		return op.GetId(), nil
+	})
+}
+
+// GetMongoDBCluster retrieves the current state of a Managed Service for MongoDB cluster.
+func (c *Client) GetMongoDBCluster(ctx context.Context, folderID, clusterID string) (*mongodbpb.Cluster, error) {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.mdb.mongodb.v1.ClusterService.Get")
+	return getResource(ctx, c, endpoint, "get MongoDB cluster", clusterID, func(ctx context.Context, conn grpc.ClientConnInterface) (*mongodbpb.Cluster, error) {
+		client := mongodbpb.NewClusterServiceClient(conn)
+		return client.Get(ctx, &mongodbpb.GetClusterRequest{
+			ClusterId: clusterID,
+		})
+	})
+}
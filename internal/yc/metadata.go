@@ -0,0 +1,101 @@
+package yc
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/sentoz/yc-sheduler/internal/vars"
+)
+
+const (
+	// clientRequestIDHeader carries a unique ID of every API request.
+	clientRequestIDHeader = "x-client-request-id"
+
+	// clientTraceIDHeader carries the execution ID shared by all API
+	// requests of one scheduler operation.
+	clientTraceIDHeader = "x-client-trace-id"
+)
+
+type executionIDKey struct{}
+
+// NewExecutionID returns a new unique execution ID.
+func NewExecutionID() string {
+	return uuid.NewString()
+}
+
+// WithExecutionID returns a context whose API calls are tagged with the
+// execution ID, so cloud-side audit logs can be traced to one scheduler
+// operation.
+func WithExecutionID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, executionIDKey{}, id)
+}
+
+// ExecutionID returns the execution ID stored in the context, if any.
+func ExecutionID(ctx context.Context) string {
+	id, _ := ctx.Value(executionIDKey{}).(string)
+	return id
+}
+
+type operationLogKey struct{}
+
+// OperationLog collects the IDs of the operations started with a context.
+type OperationLog struct {
+	ids []string
+	mu  sync.Mutex
+}
+
+// WithOperationLog returns a context whose operations are recorded in the
+// returned log.
+func WithOperationLog(ctx context.Context) (context.Context, *OperationLog) {
+	ops := &OperationLog{}
+	return context.WithValue(ctx, operationLogKey{}, ops), ops
+}
+
+// Last returns the ID of the last recorded operation, or an empty string
+// if there is none.
+func (l *OperationLog) Last() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.ids) == 0 {
+		return ""
+	}
+	return l.ids[len(l.ids)-1]
+}
+
+// recordOperation adds the operation ID to the operation log of the
+// context, if any.
+func recordOperation(ctx context.Context, operationID string) {
+	ops, ok := ctx.Value(operationLogKey{}).(*OperationLog)
+	if !ok {
+		return
+	}
+
+	ops.mu.Lock()
+	defer ops.mu.Unlock()
+	ops.ids = append(ops.ids, operationID)
+}
+
+// userAgent returns the user agent sent with all API calls.
+func userAgent() string {
+	return "yc-scheduler/" + vars.Version
+}
+
+// requestIDInterceptor adds a unique request ID and the execution ID from
+// the context to the outgoing metadata of every unary call.
+func requestIDInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return invoker(outgoingRequestIDs(ctx), method, req, reply, cc, opts...)
+}
+
+// outgoingRequestIDs returns ctx with request ID metadata appended.
+func outgoingRequestIDs(ctx context.Context) context.Context {
+	kv := []string{clientRequestIDHeader, uuid.NewString()}
+	if id := ExecutionID(ctx); id != "" {
+		kv = append(kv, clientTraceIDHeader, id)
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
@@ -0,0 +1,50 @@
+package yc
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func TestOutgoingRequestIDs(t *testing.T) {
+	t.Parallel()
+
+	ctx := WithExecutionID(context.Background(), "exec-1")
+	first, _ := metadata.FromOutgoingContext(outgoingRequestIDs(ctx))
+	second, _ := metadata.FromOutgoingContext(outgoingRequestIDs(ctx))
+
+	if got := first.Get(clientTraceIDHeader); len(got) != 1 || got[0] != "exec-1" {
+		t.Fatalf("trace ID = %v, want [exec-1]", got)
+	}
+	firstID, secondID := first.Get(clientRequestIDHeader), second.Get(clientRequestIDHeader)
+	if len(firstID) != 1 || len(secondID) != 1 || firstID[0] == secondID[0] {
+		t.Fatalf("request IDs = %v and %v, want two distinct IDs", firstID, secondID)
+	}
+}
+
+func TestOutgoingRequestIDsWithoutExecution(t *testing.T) {
+	t.Parallel()
+
+	md, _ := metadata.FromOutgoingContext(outgoingRequestIDs(context.Background()))
+	if got := md.Get(clientTraceIDHeader); len(got) != 0 {
+		t.Fatalf("trace ID = %v, want none", got)
+	}
+}
+
+func TestOperationLog(t *testing.T) {
+	t.Parallel()
+
+	ctx, ops := WithOperationLog(context.Background())
+	if got := ops.Last(); got != "" {
+		t.Fatalf("Last() = %q, want empty", got)
+	}
+	recordOperation(ctx, "op-1")
+	recordOperation(ctx, "op-2")
+	// Contexts without a log are ignored.
+	recordOperation(context.Background(), "op-3")
+
+	if got := ops.Last(); got != "op-2" {
+		t.Fatalf("Last() = %q, want op-2", got)
+	}
+}
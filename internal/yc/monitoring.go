@@ -0,0 +1,137 @@
+package yc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// monitoringReadURL is the Monitoring API endpoint for reading metric data.
+// Monitoring has no gRPC data API in the SDK, so it is called over REST.
+const monitoringReadURL = "https://monitoring.api.cloud.yandex.net/monitoring/v2/data/read"
+
+// maxMonitoringErrorBody limits how much of an error response is reported.
+const maxMonitoringErrorBody = 512
+
+// MetricPoint is a single value of a Monitoring time series.
+type MetricPoint struct {
+	Time  time.Time
+	Value float64
+}
+
+type metricsReadRequest struct {
+	Query        string             `json:"query"`
+	FromTime     string             `json:"fromTime"`
+	ToTime       string             `json:"toTime"`
+	Downsampling metricsDownsampler `json:"downsampling"`
+}
+
+type metricsDownsampler struct {
+	GridAggregation string `json:"gridAggregation"`
+	GridInterval    int64  `json:"gridInterval"`
+}
+
+type metricsReadResponse struct {
+	Metrics []struct {
+		Timeseries struct {
+			Timestamps   []int64   `json:"timestamps"`
+			DoubleValues []float64 `json:"doubleValues"`
+		} `json:"timeseries"`
+	} `json:"metrics"`
+}
+
+// ReadCPUUtilization returns CPU utilization (percent) of a compute instance
+// between from and to, downsampled to step using the maximum of each interval.
+func (c *Client) ReadCPUUtilization(ctx context.Context, folderID, instanceID string, from, to time.Time, step time.Duration) ([]MetricPoint, error) {
+	return c.ReadInstanceMetric(ctx, folderID, instanceID, "cpu_utilization", from, to, step)
+}
+
+// ReadInstanceMetric returns a Compute metric (e.g. "cpu_utilization" or
+// "network_received_bytes") of an instance between from and to, downsampled
+// to step using the maximum of each interval.
+func (c *Client) ReadInstanceMetric(ctx context.Context, folderID, instanceID, metric string, from, to time.Time, step time.Duration) ([]MetricPoint, error) {
+	if err := c.ensureInitialized(); err != nil {
+		return nil, err
+	}
+
+	token, err := c.sdk.CreateIAMToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("yc: create IAM token: %w", err)
+	}
+
+	req := metricsReadRequest{
+		Query:    fmt.Sprintf(`%s{service="compute", resource_id=%q}`, metric, instanceID),
+		FromTime: from.UTC().Format(time.RFC3339),
+		ToTime:   to.UTC().Format(time.RFC3339),
+		Downsampling: metricsDownsampler{
+			GridAggregation: "MAX",
+			GridInterval:    step.Milliseconds(),
+		},
+	}
+	points, err := readMetrics(ctx, http.DefaultClient, monitoringReadURL, token.GetIamToken(), folderID, req)
+	if err != nil {
+		return nil, wrapCallError("read "+metric+" of instance", instanceID, err)
+	}
+	return points, nil
+}
+
+// readMetrics posts a data read request to endpoint and returns the points of
+// the first time series in the response.
+func readMetrics(ctx context.Context, client *http.Client, endpoint, token, folderID string, req metricsReadRequest) ([]MetricPoint, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("encode request: %w", err)
+	}
+
+	ctx, cancel := withCallDeadline(ctx)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+"?folderId="+url.QueryEscape(folderID), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", userAgent())
+	httpReq.Header.Set(clientRequestIDHeader, uuid.NewString())
+	if id := ExecutionID(ctx); id != "" {
+		httpReq.Header.Set(clientTraceIDHeader, id)
+	}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxMonitoringErrorBody))
+		return nil, fmt.Errorf("unexpected status %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+
+	var data metricsReadResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	if len(data.Metrics) == 0 {
+		return nil, nil
+	}
+
+	series := data.Metrics[0].Timeseries
+	n := min(len(series.Timestamps), len(series.DoubleValues))
+	points := make([]MetricPoint, 0, n)
+	for i := range n {
+		points = append(points, MetricPoint{
+			Time:  time.UnixMilli(series.Timestamps[i]),
+			Value: series.DoubleValues[i],
+		})
+	}
+	return points, nil
+}
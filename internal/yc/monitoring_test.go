@@ -0,0 +1,68 @@
+package yc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadMetricsParsesTimeSeries(t *testing.T) {
+	t.Parallel()
+
+	var got metricsReadRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("folderId") != "folder-1" {
+			t.Errorf("folderId = %q", r.URL.Query().Get("folderId"))
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		if r.Header.Get(clientRequestIDHeader) == "" {
+			t.Error("request ID header is missing")
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"metrics":[{"timeseries":{"timestamps":[1000,2000],"doubleValues":[1.5,42]}}]}`))
+	}))
+	defer srv.Close()
+
+	req := metricsReadRequest{Query: "cpu_utilization", Downsampling: metricsDownsampler{GridAggregation: "MAX", GridInterval: 600000}}
+	points, err := readMetrics(context.Background(), srv.Client(), srv.URL, "token", "folder-1", req)
+	if err != nil {
+		t.Fatalf("readMetrics() error = %v", err)
+	}
+	if got.Query != "cpu_utilization" || got.Downsampling.GridInterval != 600000 {
+		t.Fatalf("request = %+v", got)
+	}
+	want := []MetricPoint{
+		{Time: time.UnixMilli(1000), Value: 1.5},
+		{Time: time.UnixMilli(2000), Value: 42},
+	}
+	if len(points) != len(want) {
+		t.Fatalf("points = %v, want %v", points, want)
+	}
+	for i := range want {
+		if !points[i].Time.Equal(want[i].Time) || points[i].Value != want[i].Value {
+			t.Fatalf("points[%d] = %v, want %v", i, points[i], want[i])
+		}
+	}
+}
+
+func TestReadMetricsReportsErrorStatus(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "permission denied", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	_, err := readMetrics(context.Background(), srv.Client(), srv.URL, "token", "folder-1", metricsReadRequest{})
+	if err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Fatalf("readMetrics() error = %v, want permission denied", err)
+	}
+}
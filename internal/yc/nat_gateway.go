@@ -0,0 +1,214 @@
+package yc
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	vpcpb "github.com/yandex-cloud/go-genproto/yandex/cloud/vpc/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// natRoutesLabelPrefix prefixes the route table labels recording destination
+// prefixes of static routes removed by DisableNATGateway, one label per
+// route. The gateway ID and the route number are appended to the prefix, so
+// one table can track several gateways. Label values cannot contain ":", so
+// the colons of IPv6 prefixes are recorded as "_".
+const natRoutesLabelPrefix = "yc-scheduler-nat-"
+
+// maxLabels is the number of labels a Yandex Cloud resource can have.
+const maxLabels = 64
+
+// DisableNATGateway removes static routes via the NAT gateway from all route
+// tables in the folder. Removed destination prefixes are recorded in route
+// table labels so EnableNATGateway can restore them. A table without room
+// for the labels is left as it is and ErrTooManyLabels is returned.
+func (c *Client) DisableNATGateway(ctx context.Context, folderID, gatewayID string) error {
+	tables, err := c.ListRouteTables(ctx, folderID)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range tables {
+		kept, removed := splitGatewayRoutes(table.GetStaticRoutes(), gatewayID)
+		if len(removed) == 0 {
+			continue
+		}
+
+		saved := append(savedNATRoutes(table.GetLabels(), gatewayID), removed...)
+		labels, err := withSavedNATRoutes(table.GetLabels(), gatewayID, saved)
+		if err != nil {
+			return fmt.Errorf("route table %s: %w", table.GetId(), err)
+		}
+
+		if err := c.UpdateRouteTable(ctx, table.GetId(), labels, kept); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// EnableNATGateway restores static routes via the NAT gateway removed by
+// DisableNATGateway. It is a no-op for route tables without saved routes.
+func (c *Client) EnableNATGateway(ctx context.Context, folderID, gatewayID string) error {
+	tables, err := c.ListRouteTables(ctx, folderID)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range tables {
+		saved := savedNATRoutes(table.GetLabels(), gatewayID)
+		if len(saved) == 0 {
+			continue
+		}
+
+		routes := slices.Clone(table.GetStaticRoutes())
+		_, existing := splitGatewayRoutes(routes, gatewayID)
+		for _, prefix := range saved {
+			if slices.Contains(existing, prefix) {
+				continue
+			}
+			routes = append(routes, &vpcpb.StaticRoute{
+				Destination: &vpcpb.StaticRoute_DestinationPrefix{DestinationPrefix: prefix},
+				NextHop:     &vpcpb.StaticRoute_GatewayId{GatewayId: gatewayID},
+			})
+		}
+
+		labels, _ := withSavedNATRoutes(table.GetLabels(), gatewayID, nil)
+		if err := c.UpdateRouteTable(ctx, table.GetId(), labels, routes); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IsNATGatewayRouted reports whether any route table in the folder has a
+// static route via the NAT gateway.
+func (c *Client) IsNATGatewayRouted(ctx context.Context, folderID, gatewayID string) (bool, error) {
+	tables, err := c.ListRouteTables(ctx, folderID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, table := range tables {
+		if _, routed := splitGatewayRoutes(table.GetStaticRoutes(), gatewayID); len(routed) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ListRouteTables returns all route tables in the folder.
+func (c *Client) ListRouteTables(ctx context.Context, folderID string) ([]*vpcpb.RouteTable, error) {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.vpc.v1.RouteTableService.List")
+	return getResource(ctx, c, endpoint, "list route tables", folderID, func(ctx context.Context, conn grpc.ClientConnInterface) ([]*vpcpb.RouteTable, error) {
+		client := vpcpb.NewRouteTableServiceClient(conn)
+		return listAll(ctx, func(ctx context.Context, pageToken string) ([]*vpcpb.RouteTable, string, error) {
+			resp, err := client.List(ctx, &vpcpb.ListRouteTablesRequest{
+				FolderId:  folderID,
+				PageSize:  listPageSize,
+				PageToken: pageToken,
+			}, c.listCallOptions()...)
+			return resp.GetRouteTables(), resp.GetNextPageToken(), err
+		})
+	})
+}
+
+// UpdateRouteTable replaces the labels and static routes of a route table.
+func (c *Client) UpdateRouteTable(ctx context.Context, routeTableID string, labels map[string]string, routes []*vpcpb.StaticRoute) error {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.vpc.v1.RouteTableService.Update")
+	return executeOperation(ctx, c, endpoint, "update route table", routeTableID, func(ctx context.Context, conn grpc.ClientConnInterface) (string, error) {
+		client := vpcpb.NewRouteTableServiceClient(conn)
+		op, err := client.Update(ctx, &vpcpb.UpdateRouteTableRequest{
+			RouteTableId: routeTableID,
+			UpdateMask:   &fieldmaskpb.FieldMask{Paths: []string{"labels", "static_routes"}},
+			Labels:       labels,
+			StaticRoutes: routes,
+		})
+		if err != nil {
+			return "", err
+		}
+		return op.GetId(), nil
+	})
+}
+
+// splitGatewayRoutes separates static routes via the gateway from the rest
+// and returns the remaining routes and destination prefixes of the gateway ones.
+func splitGatewayRoutes(routes []*vpcpb.StaticRoute, gatewayID string) ([]*vpcpb.StaticRoute, []string) {
+	kept := make([]*vpcpb.StaticRoute, 0, len(routes))
+	var prefixes []string
+	for _, route := range routes {
+		if route.GetGatewayId() == gatewayID {
+			prefixes = append(prefixes, route.GetDestinationPrefix())
+			continue
+		}
+		kept = append(kept, route)
+	}
+	return kept, prefixes
+}
+
+// natRouteIndex returns the route number of a label recording a saved route
+// of the gateway.
+func natRouteIndex(key, gatewayID string) (int, bool) {
+	suffix, ok := strings.CutPrefix(key, natRoutesLabelPrefix+gatewayID+"-")
+	if !ok {
+		return 0, false
+	}
+	index, err := strconv.Atoi(suffix)
+	return index, err == nil
+}
+
+// savedNATRoutes extracts destination prefixes recorded for the gateway in
+// the order they were recorded.
+func savedNATRoutes(labels map[string]string, gatewayID string) []string {
+	byIndex := make(map[int]string)
+	for key, value := range labels {
+		if index, ok := natRouteIndex(key, gatewayID); ok && value != "" {
+			byIndex[index] = strings.ReplaceAll(value, "_", ":")
+		}
+	}
+
+	prefixes := make([]string, 0, len(byIndex))
+	for _, index := range slices.Sorted(maps.Keys(byIndex)) {
+		prefixes = append(prefixes, byIndex[index])
+	}
+	if len(prefixes) == 0 {
+		return nil
+	}
+	return prefixes
+}
+
+// withSavedNATRoutes returns a copy of labels recording the destination
+// prefixes for the gateway, one label per unique prefix, or without the
+// labels of the gateway when prefixes is empty. It returns ErrTooManyLabels
+// if the labels would exceed the limit of a resource.
+func withSavedNATRoutes(labels map[string]string, gatewayID string, prefixes []string) (map[string]string, error) {
+	result := make(map[string]string, len(labels)+len(prefixes))
+	for key, value := range labels {
+		if _, ok := natRouteIndex(key, gatewayID); !ok {
+			result[key] = value
+		}
+	}
+
+	var seen []string
+	for _, prefix := range prefixes {
+		if slices.Contains(seen, prefix) {
+			continue
+		}
+		result[natRoutesLabelPrefix+gatewayID+"-"+strconv.Itoa(len(seen))] = strings.ReplaceAll(prefix, ":", "_")
+		seen = append(seen, prefix)
+	}
+	if len(result) > maxLabels {
+		return nil, fmt.Errorf("%w: recording %d routes via NAT gateway %s", ErrTooManyLabels, len(seen), gatewayID)
+	}
+	return result, nil
+}
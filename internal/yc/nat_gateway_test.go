@@ -0,0 +1,81 @@
+package yc
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	vpcpb "github.com/yandex-cloud/go-genproto/yandex/cloud/vpc/v1"
+)
+
+func TestSplitGatewayRoutes(t *testing.T) {
+	t.Parallel()
+
+	routes := []*vpcpb.StaticRoute{
+		{
+			Destination: &vpcpb.StaticRoute_DestinationPrefix{DestinationPrefix: "0.0.0.0/0"},
+			NextHop:     &vpcpb.StaticRoute_GatewayId{GatewayId: "gw-1"},
+		},
+		{
+			Destination: &vpcpb.StaticRoute_DestinationPrefix{DestinationPrefix: "10.0.0.0/8"},
+			NextHop:     &vpcpb.StaticRoute_NextHopAddress{NextHopAddress: "192.168.0.1"},
+		},
+		{
+			Destination: &vpcpb.StaticRoute_DestinationPrefix{DestinationPrefix: "172.16.0.0/12"},
+			NextHop:     &vpcpb.StaticRoute_GatewayId{GatewayId: "gw-2"},
+		},
+	}
+
+	kept, prefixes := splitGatewayRoutes(routes, "gw-1")
+	if len(kept) != 2 {
+		t.Fatalf("splitGatewayRoutes() kept %d routes, want 2", len(kept))
+	}
+	if !slices.Equal(prefixes, []string{"0.0.0.0/0"}) {
+		t.Fatalf("splitGatewayRoutes() prefixes = %v, want [0.0.0.0/0]", prefixes)
+	}
+}
+
+func TestSavedNATRoutesRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	prefixes := []string{"0.0.0.0/0", "10.1.0.0/16", "::/0", "10.1.0.0/16"}
+	labels, err := withSavedNATRoutes(map[string]string{"env": "dev"}, "gw-1", prefixes)
+	if err != nil {
+		t.Fatalf("withSavedNATRoutes() error = %v", err)
+	}
+	for key, value := range labels {
+		if len(value) > 63 || strings.Contains(value, ":") {
+			t.Fatalf("label %s = %q is not a valid label value", key, value)
+		}
+	}
+
+	if got := savedNATRoutes(labels, "gw-1"); !slices.Equal(got, []string{"0.0.0.0/0", "10.1.0.0/16", "::/0"}) {
+		t.Fatalf("savedNATRoutes() = %v", got)
+	}
+	if got := savedNATRoutes(labels, "gw-2"); got != nil {
+		t.Fatalf("savedNATRoutes() for unknown gateway = %v, want nil", got)
+	}
+
+	cleared, err := withSavedNATRoutes(labels, "gw-1", nil)
+	if err != nil {
+		t.Fatalf("withSavedNATRoutes() error = %v", err)
+	}
+	if want := map[string]string{"env": "dev"}; !maps.Equal(cleared, want) {
+		t.Fatalf("labels after restore = %v, want %v", cleared, want)
+	}
+}
+
+func TestWithSavedNATRoutesRejectsOverflow(t *testing.T) {
+	t.Parallel()
+
+	prefixes := make([]string, 0, maxLabels+1)
+	for i := range maxLabels + 1 {
+		prefixes = append(prefixes, fmt.Sprintf("10.%d.0.0/16", i))
+	}
+	if _, err := withSavedNATRoutes(nil, "gw-1", prefixes); !errors.Is(err, ErrTooManyLabels) {
+		t.Fatalf("withSavedNATRoutes() error = %v, want %v", err, ErrTooManyLabels)
+	}
+}
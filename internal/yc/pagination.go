@@ -0,0 +1,48 @@
+package yc
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding/gzip"
+)
+
+// listPageSize is the page size requested from List methods, the maximum
+// allowed by Yandex Cloud APIs.
+const listPageSize = 1000
+
+// listAll collects the items of all pages of a List method. fetch returns
+// the items of the page with the given token and the next page token; an
+// empty next token ends the listing.
+func listAll[T any](ctx context.Context, fetch func(ctx context.Context, pageToken string) ([]T, string, error)) ([]T, error) {
+	var items []T
+	seen := make(map[string]struct{})
+	pageToken := ""
+	for {
+		page, next, err := fetch(ctx, pageToken)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+		if next == "" {
+			return items, nil
+		}
+		// A repeated token would make the listing loop forever.
+		if _, repeated := seen[next]; repeated {
+			return nil, fmt.Errorf("repeated page token %q", next)
+		}
+		seen[next] = struct{}{}
+		pageToken = next
+	}
+}
+
+// listCallOptions returns the call options of List requests. With
+// compression enabled, requests are gzip-compressed and the server is
+// expected to compress responses the same way.
+func (c *Client) listCallOptions() []grpc.CallOption {
+	if !c.compression {
+		return nil
+	}
+	return []grpc.CallOption{grpc.UseCompressor(gzip.Name)}
+}
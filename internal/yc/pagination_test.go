@@ -0,0 +1,62 @@
+package yc
+
+import (
+	"context"
+	"strconv"
+	"testing"
+)
+
+// pagedFetch serves total items in pages of listPageSize.
+func pagedFetch(total int, calls *int) func(context.Context, string) ([]int, string, error) {
+	return func(_ context.Context, pageToken string) ([]int, string, error) {
+		*calls++
+		start := 0
+		if pageToken != "" {
+			start, _ = strconv.Atoi(pageToken)
+		}
+		end := min(start+listPageSize, total)
+		page := make([]int, 0, end-start)
+		for i := start; i < end; i++ {
+			page = append(page, i)
+		}
+		next := ""
+		if end < total {
+			next = strconv.Itoa(end)
+		}
+		return page, next, nil
+	}
+}
+
+func TestListAllCollectsAllPages(t *testing.T) {
+	t.Parallel()
+
+	for _, total := range []int{0, 999, 1000, 1001, 2500} {
+		calls := 0
+		items, err := listAll(context.Background(), pagedFetch(total, &calls))
+		if err != nil {
+			t.Fatalf("listAll(%d) error = %v", total, err)
+		}
+		if len(items) != total {
+			t.Fatalf("listAll(%d) returned %d items", total, len(items))
+		}
+		for i, item := range items {
+			if item != i {
+				t.Fatalf("listAll(%d) item %d = %d, want ordered items", total, i, item)
+			}
+		}
+		if wantCalls := max(1, (total+listPageSize-1)/listPageSize); calls != wantCalls {
+			t.Fatalf("listAll(%d) fetched %d pages, want %d", total, calls, wantCalls)
+		}
+	}
+}
+
+func TestListAllRejectsRepeatedPageToken(t *testing.T) {
+	t.Parallel()
+
+	fetch := func(context.Context, string) ([]int, string, error) {
+		return []int{1}, "same", nil
+	}
+	if _, err := listAll(context.Background(), fetch); err == nil {
+		t.Fatal("listAll() error = nil, want repeated page token error")
+	}
+}
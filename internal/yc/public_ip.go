@@ -0,0 +1,142 @@
+package yc
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// releasedNATLabel is the instance label listing network interface indexes
+// whose public addresses were released by ReleaseInstancePublicIPs.
+// Indexes are joined with "_" because commas are not allowed in label values.
+const releasedNATLabel = "yc-scheduler-released-nat"
+
+// releasedAddressLabelPrefix prefixes the instance labels recording the
+// reserved static address detached from the network interface with the
+// index following the prefix.
+const releasedAddressLabelPrefix = "yc-scheduler-released-address-"
+
+// ReleaseInstancePublicIPs detaches public IP addresses from all network
+// interfaces of a (stopped) instance. Ephemeral addresses are released.
+// Reserved static addresses stay reserved, as an unreserved address would be
+// deleted once detached. The affected interfaces and their static addresses
+// are recorded in instance labels so RestoreInstancePublicIPs can attach the
+// same static addresses and new ephemeral ones.
+func (c *Client) ReleaseInstancePublicIPs(ctx context.Context, folderID, instanceID string) error {
+	instance, err := c.GetInstance(ctx, folderID, instanceID)
+	if err != nil {
+		return err
+	}
+
+	indexes := make([]string, 0, len(instance.GetNetworkInterfaces()))
+	static := make(map[string]string)
+	for _, nic := range instance.GetNetworkInterfaces() {
+		nat := nic.GetPrimaryV4Address().GetOneToOneNat()
+		if nat == nil {
+			continue
+		}
+		indexes = append(indexes, nic.GetIndex())
+		if nat.GetAddress() == "" {
+			continue
+		}
+		address, err := c.GetAddressByValue(ctx, nat.GetAddress())
+		if err != nil {
+			return err
+		}
+		if address.GetReserved() {
+			static[nic.GetIndex()] = nat.GetAddress()
+		}
+	}
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	// Record interfaces before releasing so a partial failure can still be
+	// restored on the next start.
+	released := append(releasedNATIndexes(instance.GetLabels()), indexes...)
+	maps.Copy(static, releasedAddresses(instance.GetLabels()))
+	if err := c.UpdateInstanceLabels(ctx, folderID, instanceID, withReleasedNAT(instance.GetLabels(), released, static)); err != nil {
+		return err
+	}
+
+	for _, index := range indexes {
+		if err := c.RemoveInstanceOneToOneNat(ctx, folderID, instanceID, index); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RestoreInstancePublicIPs attaches public IP addresses to the network
+// interfaces released by ReleaseInstancePublicIPs: the recorded static
+// addresses and new ephemeral addresses to the others.
+// It is a no-op for instances without released interfaces.
+func (c *Client) RestoreInstancePublicIPs(ctx context.Context, folderID, instanceID string) error {
+	instance, err := c.GetInstance(ctx, folderID, instanceID)
+	if err != nil {
+		return err
+	}
+
+	released := releasedNATIndexes(instance.GetLabels())
+	if len(released) == 0 {
+		return nil
+	}
+
+	static := releasedAddresses(instance.GetLabels())
+	for _, nic := range instance.GetNetworkInterfaces() {
+		if !slices.Contains(released, nic.GetIndex()) || nic.GetPrimaryV4Address().GetOneToOneNat() != nil {
+			continue
+		}
+		if err := c.AddInstanceOneToOneNat(ctx, folderID, instanceID, nic.GetIndex(), static[nic.GetIndex()]); err != nil {
+			return err
+		}
+	}
+
+	return c.UpdateInstanceLabels(ctx, folderID, instanceID, withReleasedNAT(instance.GetLabels(), nil, nil))
+}
+
+// releasedNATIndexes returns network interface indexes recorded in labels.
+func releasedNATIndexes(labels map[string]string) []string {
+	value := labels[releasedNATLabel]
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, "_")
+}
+
+// releasedAddresses returns the static addresses recorded in labels by
+// network interface index.
+func releasedAddresses(labels map[string]string) map[string]string {
+	addresses := make(map[string]string)
+	for key, value := range labels {
+		if index, ok := strings.CutPrefix(key, releasedAddressLabelPrefix); ok && value != "" {
+			addresses[index] = value
+		}
+	}
+	return addresses
+}
+
+// withReleasedNAT returns a copy of labels with the released interface
+// indexes and static addresses recorded, or with the labels removed when
+// indexes is empty.
+func withReleasedNAT(labels map[string]string, indexes []string, addresses map[string]string) map[string]string {
+	result := make(map[string]string, len(labels)+1+len(addresses))
+	for key, value := range labels {
+		if key != releasedNATLabel && !strings.HasPrefix(key, releasedAddressLabelPrefix) {
+			result[key] = value
+		}
+	}
+
+	if len(indexes) > 0 {
+		unique := slices.Clone(indexes)
+		slices.Sort(unique)
+		result[releasedNATLabel] = strings.Join(slices.Compact(unique), "_")
+		for index, address := range addresses {
+			result[releasedAddressLabelPrefix+index] = address
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,24 @@
+package yc
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestReleasedNATLabelsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	labels := withReleasedNAT(map[string]string{"env": "dev"}, []string{"1", "0", "1"}, map[string]string{"1": "51.250.10.20"})
+	if got := releasedNATIndexes(labels); !slices.Equal(got, []string{"0", "1"}) {
+		t.Fatalf("releasedNATIndexes() = %v, want [0 1]", got)
+	}
+	if got := releasedAddresses(labels); !maps.Equal(got, map[string]string{"1": "51.250.10.20"}) {
+		t.Fatalf("releasedAddresses() = %v, want the static address of interface 1", got)
+	}
+
+	cleared := withReleasedNAT(labels, nil, nil)
+	if !maps.Equal(cleared, map[string]string{"env": "dev"}) {
+		t.Fatalf("labels after restore = %v, want only env", cleared)
+	}
+}
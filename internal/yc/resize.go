@@ -0,0 +1,102 @@
+package yc
+
+import (
+	"context"
+
+	computepb "github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// InstanceSpec describes the platform, computing resources and scheduling
+// policy set on a stopped VM. Zero fields keep the current value.
+type InstanceSpec struct {
+	PlatformID   string
+	Cores        int64
+	CoreFraction int64
+	// Memory is the memory size in bytes.
+	Memory int64
+	// Preemptible sets the preemptible scheduling policy when not nil.
+	Preemptible *bool
+}
+
+// Matches reports whether the instance already has the requested spec.
+func (s InstanceSpec) Matches(instance *computepb.Instance) bool {
+	return len(s.updatePaths(instance)) == 0
+}
+
+// updatePaths returns the update mask paths of spec fields that differ from
+// the instance.
+func (s InstanceSpec) updatePaths(instance *computepb.Instance) []string {
+	resources := instance.GetResources()
+	var paths []string
+	if s.PlatformID != "" && s.PlatformID != instance.GetPlatformId() {
+		paths = append(paths, "platform_id")
+	}
+	if s.Cores > 0 && s.Cores != resources.GetCores() {
+		paths = append(paths, "resources_spec.cores")
+	}
+	if s.CoreFraction > 0 && s.CoreFraction != resources.GetCoreFraction() {
+		paths = append(paths, "resources_spec.core_fraction")
+	}
+	if s.Memory > 0 && s.Memory != resources.GetMemory() {
+		paths = append(paths, "resources_spec.memory")
+	}
+	if s.Preemptible != nil && *s.Preemptible != instance.GetSchedulingPolicy().GetPreemptible() {
+		paths = append(paths, "scheduling_policy.preemptible")
+	}
+	return paths
+}
+
+// ResizeInstance updates the platform, computing resources and scheduling
+// policy of a stopped compute instance. It is a no-op when the instance
+// already has the spec.
+func (c *Client) ResizeInstance(ctx context.Context, folderID, instanceID string, spec InstanceSpec) error {
+	instance, err := c.GetInstance(ctx, folderID, instanceID)
+	if err != nil {
+		return err
+	}
+
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.compute.v1.InstanceService.Update")
+	return executeOperation(ctx, c, endpoint, "resize instance", instanceID, func(ctx context.Context, conn grpc.ClientConnInterface) (string, error) {
+		paths := spec.updatePaths(instance)
+		if len(paths) == 0 {
+			return "", errNothingToDo
+		}
+
+		resources := instance.GetResources()
+		client := computepb.NewInstanceServiceClient(conn)
+		req := &computepb.UpdateInstanceRequest{
+			InstanceId: instanceID,
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: paths},
+			PlatformId: spec.PlatformID,
+			ResourcesSpec: &computepb.ResourcesSpec{
+				Cores:        orDefault(spec.Cores, resources.GetCores()),
+				CoreFraction: orDefault(spec.CoreFraction, resources.GetCoreFraction()),
+				Memory:       orDefault(spec.Memory, resources.GetMemory()),
+				Gpus:         resources.GetGpus(),
+			},
+			SchedulingPolicy: &computepb.SchedulingPolicy{
+				Preemptible: instance.GetSchedulingPolicy().GetPreemptible(),
+			},
+		}
+		if spec.Preemptible != nil {
+			req.SchedulingPolicy.Preemptible = *spec.Preemptible
+		}
+		op, err := client.Update(ctx, req)
+		if err != nil {
+			return "", err
+		}
+		return op.GetId(), nil
+	})
+}
+
+// orDefault returns value, or fallback when value is not set.
+func orDefault(value, fallback int64) int64 {
+	if value > 0 {
+		return value
+	}
+	return fallback
+}
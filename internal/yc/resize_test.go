@@ -0,0 +1,60 @@
+package yc
+
+import (
+	"slices"
+	"testing"
+
+	computepb "github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
+)
+
+func TestInstanceSpecUpdatePaths(t *testing.T) {
+	t.Parallel()
+
+	instance := &computepb.Instance{
+		PlatformId: "standard-v3",
+		Resources: &computepb.Resources{
+			Cores:        4,
+			CoreFraction: 100,
+			Memory:       8 << 30,
+		},
+	}
+
+	preemptible := true
+	tests := []struct {
+		name string
+		spec InstanceSpec
+		want []string
+	}{
+		{name: "empty spec", spec: InstanceSpec{}},
+		{name: "same values", spec: InstanceSpec{PlatformID: "standard-v3", Cores: 4, Memory: 8 << 30}},
+		{
+			name: "downsize",
+			spec: InstanceSpec{Cores: 2, CoreFraction: 20, Memory: 2 << 30},
+			want: []string{"resources_spec.cores", "resources_spec.core_fraction", "resources_spec.memory"},
+		},
+		{
+			name: "preemptible",
+			spec: InstanceSpec{Preemptible: &preemptible},
+			want: []string{"scheduling_policy.preemptible"},
+		},
+		{
+			name: "platform only",
+			spec: InstanceSpec{PlatformID: "standard-v2", Cores: 4},
+			want: []string{"platform_id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := tt.spec.updatePaths(instance)
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("updatePaths() = %v, want %v", got, tt.want)
+			}
+			if matches := tt.spec.Matches(instance); matches != (len(tt.want) == 0) {
+				t.Fatalf("Matches() = %v, want %v", matches, len(tt.want) == 0)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package yc
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+)
+
+// savedSizeLabel is the resource label used to remember the fixed scale size
+// of a group before it was scaled to zero, so the size can be restored on start
+// even after the scheduler process restarts.
+const savedSizeLabel = "yc-scheduler-saved-size"
+
+// withSavedSize returns a copy of labels with the saved scale size recorded.
+func withSavedSize(labels map[string]string, size int64) map[string]string {
+	result := make(map[string]string, len(labels)+1)
+	maps.Copy(result, labels)
+	result[savedSizeLabel] = strconv.FormatInt(size, 10)
+	return result
+}
+
+// withoutSavedSize returns a copy of labels without the saved scale size.
+func withoutSavedSize(labels map[string]string) map[string]string {
+	result := make(map[string]string, len(labels))
+	maps.Copy(result, labels)
+	delete(result, savedSizeLabel)
+	return result
+}
+
+// savedSize extracts the scale size recorded by withSavedSize.
+func savedSize(labels map[string]string) (int64, error) {
+	value, ok := labels[savedSizeLabel]
+	if !ok || value == "" {
+		return 0, fmt.Errorf("%w: label %q is not set", ErrSavedScaleMissing, savedSizeLabel)
+	}
+
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || size <= 0 {
+		return 0, fmt.Errorf("%w: label %q has invalid value %q", ErrSavedScaleMissing, savedSizeLabel, value)
+	}
+
+	return size, nil
+}
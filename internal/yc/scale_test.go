@@ -0,0 +1,48 @@
+package yc
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSavedSizeRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	labels := map[string]string{"env": "dev"}
+
+	saved := withSavedSize(labels, 3)
+	if _, exists := labels[savedSizeLabel]; exists {
+		t.Fatal("withSavedSize() modified the input labels")
+	}
+
+	size, err := savedSize(saved)
+	if err != nil {
+		t.Fatalf("savedSize() error = %v", err)
+	}
+	if size != 3 {
+		t.Fatalf("savedSize() = %d, want 3", size)
+	}
+
+	restored := withoutSavedSize(saved)
+	if _, exists := restored[savedSizeLabel]; exists {
+		t.Fatal("withoutSavedSize() kept the saved size label")
+	}
+	if restored["env"] != "dev" {
+		t.Fatalf("withoutSavedSize() dropped unrelated labels: %v", restored)
+	}
+}
+
+func TestSavedSizeRejectsMissingOrInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, labels := range []map[string]string{
+		nil,
+		{savedSizeLabel: ""},
+		{savedSizeLabel: "abc"},
+		{savedSizeLabel: "0"},
+	} {
+		if _, err := savedSize(labels); !errors.Is(err, ErrSavedScaleMissing) {
+			t.Fatalf("savedSize(%v) error = %v, want ErrSavedScaleMissing", labels, err)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package yc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	containerspb "github.com/yandex-cloud/go-genproto/yandex/cloud/serverless/containers/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// SetContainerProvisionedInstances deploys a copy of the latest active
+// revision of a serverless container with the given number of provisioned
+// instances. It is a no-op if the revision already uses that number.
+func (c *Client) SetContainerProvisionedInstances(ctx context.Context, folderID, containerID string, instances int64) error {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.serverless.containers.v1.ContainerService.DeployRevision")
+	return executeOperation(ctx, c, endpoint, "deploy container revision", containerID, func(ctx context.Context, conn grpc.ClientConnInterface) (string, error) {
+		client := containerspb.NewContainerServiceClient(conn)
+		revision, err := latestContainerRevision(ctx, client, containerID)
+		if err != nil {
+			return "", err
+		}
+		if revision.GetProvisionPolicy().GetMinInstances() == instances {
+			return "", errNothingToDo
+		}
+
+		req := revisionDeployRequest(revision)
+		req.ProvisionPolicy = &containerspb.ProvisionPolicy{MinInstances: instances}
+		op, err := client.DeployRevision(ctx, req)
+		if err != nil {
+			return "", err
+		}
+		return op.GetId(), nil
+	})
+}
+
+// GetContainerRevision retrieves the latest active revision of a serverless container.
+func (c *Client) GetContainerRevision(ctx context.Context, folderID, containerID string) (*containerspb.Revision, error) {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.serverless.containers.v1.ContainerService.ListRevisions")
+	return getResource(ctx, c, endpoint, "get container revision", containerID, func(ctx context.Context, conn grpc.ClientConnInterface) (*containerspb.Revision, error) {
+		return latestContainerRevision(ctx, containerspb.NewContainerServiceClient(conn), containerID)
+	})
+}
+
+// latestContainerRevision returns the most recently created active revision.
+func latestContainerRevision(ctx context.Context, client containerspb.ContainerServiceClient, containerID string) (*containerspb.Revision, error) {
+	var latest *containerspb.Revision
+	pageToken := ""
+	for {
+		resp, err := client.ListRevisions(ctx, &containerspb.ListContainersRevisionsRequest{
+			Id:        &containerspb.ListContainersRevisionsRequest_ContainerId{ContainerId: containerID},
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, revision := range resp.GetRevisions() {
+			if revision.GetStatus() != containerspb.Revision_ACTIVE {
+				continue
+			}
+			if latest == nil || revision.GetCreatedAt().AsTime().After(latest.GetCreatedAt().AsTime()) {
+				latest = revision
+			}
+		}
+		pageToken = resp.GetNextPageToken()
+		if pageToken == "" {
+			break
+		}
+	}
+
+	if latest == nil {
+		return nil, fmt.Errorf("%w: container %s", ErrContainerRevisionNotFound, containerID)
+	}
+	return latest, nil
+}
+
+// revisionDeployRequest builds a deploy request reproducing the revision.
+// The image is pinned by digest so a moved tag does not change the workload.
+func revisionDeployRequest(revision *containerspb.Revision) *containerspb.DeployContainerRevisionRequest {
+	image := revision.GetImage()
+	imageURL := image.GetImageUrl()
+	if digest := image.GetImageDigest(); digest != "" {
+		imageURL = imageRepository(imageURL) + "@" + digest
+	}
+
+	return &containerspb.DeployContainerRevisionRequest{
+		ContainerId:      revision.GetContainerId(),
+		Description:      revision.GetDescription(),
+		Resources:        revision.GetResources(),
+		ExecutionTimeout: revision.GetExecutionTimeout(),
+		ServiceAccountId: revision.GetServiceAccountId(),
+		ImageSpec: &containerspb.ImageSpec{
+			ImageUrl:    imageURL,
+			Command:     image.GetCommand(),
+			Args:        image.GetArgs(),
+			Environment: image.GetEnvironment(),
+			WorkingDir:  image.GetWorkingDir(),
+		},
+		Concurrency:           revision.GetConcurrency(),
+		Secrets:               revision.GetSecrets(),
+		Connectivity:          revision.GetConnectivity(),
+		ProvisionPolicy:       revision.GetProvisionPolicy(),
+		ScalingPolicy:         revision.GetScalingPolicy(),
+		LogOptions:            revision.GetLogOptions(),
+		StorageMounts:         revision.GetStorageMounts(),
+		Mounts:                revision.GetMounts(),
+		Runtime:               revision.GetRuntime(),
+		MetadataOptions:       revision.GetMetadataOptions(),
+		AsyncInvocationConfig: revision.GetAsyncInvocationConfig(),
+	}
+}
+
+// imageRepository strips the tag or digest from an image reference.
+func imageRepository(ref string) string {
+	if i := strings.Index(ref, "@"); i >= 0 {
+		ref = ref[:i]
+	}
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		ref = ref[:i]
+	}
+	return ref
+}
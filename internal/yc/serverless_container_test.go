@@ -0,0 +1,19 @@
+package yc
+
+import "testing"
+
+func TestImageRepository(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"cr.yandex/crp123/app:v1":          "cr.yandex/crp123/app",
+		"cr.yandex/crp123/app@sha256:abcd": "cr.yandex/crp123/app",
+		"cr.yandex/crp123/app":             "cr.yandex/crp123/app",
+		"registry:5000/app":                "registry:5000/app",
+	}
+	for ref, want := range tests {
+		if got := imageRepository(ref); got != want {
+			t.Errorf("imageRepository(%q) = %q, want %q", ref, got, want)
+		}
+	}
+}
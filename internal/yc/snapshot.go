@@ -0,0 +1,136 @@
+package yc
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	computepb "github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// snapshotDiskLabel is the snapshot label holding the source disk ID.
+// Only snapshots carrying this label are subject to retention cleanup.
+const snapshotDiskLabel = "yc-scheduler-disk"
+
+// snapshotTimeLayout is the UTC timestamp suffix of snapshot names.
+const snapshotTimeLayout = "20060102-150405"
+
+// SnapshotInstanceDisks creates a snapshot of every disk attached to an
+// instance. When retention is positive, only the newest retention snapshots
+// created by the scheduler are kept for each disk; 0 keeps all snapshots.
+func (c *Client) SnapshotInstanceDisks(ctx context.Context, folderID, instanceID string, retention int) error {
+	instance, err := c.GetInstance(ctx, folderID, instanceID)
+	if err != nil {
+		return err
+	}
+
+	diskIDs := make([]string, 0, 1+len(instance.GetSecondaryDisks()))
+	if boot := instance.GetBootDisk().GetDiskId(); boot != "" {
+		diskIDs = append(diskIDs, boot)
+	}
+	for _, disk := range instance.GetSecondaryDisks() {
+		diskIDs = append(diskIDs, disk.GetDiskId())
+	}
+
+	now := time.Now().UTC()
+	for _, diskID := range diskIDs {
+		if err := c.CreateDiskSnapshot(ctx, folderID, diskID, snapshotName(diskID, now)); err != nil {
+			return err
+		}
+		if retention <= 0 {
+			continue
+		}
+
+		snapshots, err := c.ListDiskSnapshots(ctx, folderID, diskID)
+		if err != nil {
+			return err
+		}
+		for _, snapshot := range expiredSnapshots(snapshots, retention) {
+			if err := c.DeleteSnapshot(ctx, folderID, snapshot.GetId()); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// CreateDiskSnapshot creates a snapshot of the specified disk labeled with
+// its source disk ID.
+func (c *Client) CreateDiskSnapshot(ctx context.Context, folderID, diskID, name string) error {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.compute.v1.SnapshotService.Create")
+	return executeOperation(ctx, c, endpoint, "create snapshot", diskID, func(ctx context.Context, conn grpc.ClientConnInterface) (string, error) {
+		client := computepb.NewSnapshotServiceClient(conn)
+		op, err := client.Create(ctx, &computepb.CreateSnapshotRequest{
+			FolderId: folderID,
+			DiskId:   diskID,
+			Name:     name,
+			Labels:   map[string]string{snapshotDiskLabel: diskID},
+		})
+		if err != nil {
+			return "", err
+		}
+		return op.GetId(), nil
+	})
+}
+
+// ListDiskSnapshots returns all snapshots in the folder created by the
+// scheduler for the specified disk.
+func (c *Client) ListDiskSnapshots(ctx context.Context, folderID, diskID string) ([]*computepb.Snapshot, error) {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.compute.v1.SnapshotService.List")
+	return getResource(ctx, c, endpoint, "list snapshots", diskID, func(ctx context.Context, conn grpc.ClientConnInterface) ([]*computepb.Snapshot, error) {
+		client := computepb.NewSnapshotServiceClient(conn)
+		snapshots, err := listAll(ctx, func(ctx context.Context, pageToken string) ([]*computepb.Snapshot, string, error) {
+			resp, err := client.List(ctx, &computepb.ListSnapshotsRequest{
+				FolderId:  folderID,
+				PageSize:  listPageSize,
+				PageToken: pageToken,
+			}, c.listCallOptions()...)
+			return resp.GetSnapshots(), resp.GetNextPageToken(), err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return slices.DeleteFunc(snapshots, func(snapshot *computepb.Snapshot) bool {
+			return snapshot.GetLabels()[snapshotDiskLabel] != diskID
+		}), nil
+	})
+}
+
+// DeleteSnapshot deletes the specified snapshot.
+func (c *Client) DeleteSnapshot(ctx context.Context, folderID, snapshotID string) error {
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.compute.v1.SnapshotService.Delete")
+	return executeOperation(ctx, c, endpoint, "delete snapshot", snapshotID, func(ctx context.Context, conn grpc.ClientConnInterface) (string, error) {
+		client := computepb.NewSnapshotServiceClient(conn)
+		op, err := client.Delete(ctx, &computepb.DeleteSnapshotRequest{
+			SnapshotId: snapshotID,
+		})
+		if err != nil {
+			return "", err
+		}
+		return op.GetId(), nil
+	})
+}
+
+// snapshotName builds a snapshot name from the disk ID and creation time.
+func snapshotName(diskID string, at time.Time) string {
+	return diskID + "-" + at.UTC().Format(snapshotTimeLayout)
+}
+
+// expiredSnapshots returns the snapshots beyond the newest retention ones.
+func expiredSnapshots(snapshots []*computepb.Snapshot, retention int) []*computepb.Snapshot {
+	if retention <= 0 || len(snapshots) <= retention {
+		return nil
+	}
+
+	sorted := slices.Clone(snapshots)
+	slices.SortFunc(sorted, func(a, b *computepb.Snapshot) int {
+		return b.GetCreatedAt().AsTime().Compare(a.GetCreatedAt().AsTime())
+	})
+	return sorted[retention:]
+}
@@ -0,0 +1,48 @@
+package yc
+
+import (
+	"testing"
+	"time"
+
+	computepb "github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestExpiredSnapshotsKeepsNewest(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	snapshots := []*computepb.Snapshot{
+		{Id: "old", CreatedAt: timestamppb.New(base)},
+		{Id: "newest", CreatedAt: timestamppb.New(base.Add(2 * time.Hour))},
+		{Id: "middle", CreatedAt: timestamppb.New(base.Add(time.Hour))},
+	}
+
+	expired := expiredSnapshots(snapshots, 2)
+	if len(expired) != 1 || expired[0].GetId() != "old" {
+		t.Fatalf("expiredSnapshots() = %v, want [old]", expired)
+	}
+	if snapshots[0].GetId() != "old" {
+		t.Fatal("expiredSnapshots() reordered the input slice")
+	}
+}
+
+func TestExpiredSnapshotsWithinRetention(t *testing.T) {
+	t.Parallel()
+
+	snapshots := []*computepb.Snapshot{{Id: "a"}, {Id: "b"}}
+	for _, retention := range []int{0, 2, 5} {
+		if expired := expiredSnapshots(snapshots, retention); len(expired) != 0 {
+			t.Fatalf("expiredSnapshots(retention=%d) = %v, want none", retention, expired)
+		}
+	}
+}
+
+func TestSnapshotName(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2025, 3, 4, 5, 6, 7, 0, time.FixedZone("MSK", 3*60*60))
+	if got, want := snapshotName("fhm123", at), "fhm123-20250304-020607"; got != want {
+		t.Fatalf("snapshotName() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,26 @@
+package yc
+
+import (
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// IsTransient reports whether err is a temporary API failure, such as an
+// unavailable service, an exhausted quota or an expired call deadline, after
+// which the call may succeed when repeated.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrDeadlineExceeded) {
+		return true
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted, codes.Internal, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,32 @@
+package yc
+
+import (
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIsTransient(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{err: wrapCallError("start instance", "fhm123", status.Error(codes.Unavailable, "unavailable")), want: true},
+		{err: wrapCallError("start instance", "fhm123", status.Error(codes.ResourceExhausted, "quota")), want: true},
+		{err: wrapCallError("start instance", "fhm123", status.Error(codes.DeadlineExceeded, "timeout")), want: true},
+		{err: fmt.Errorf("stop: %w", status.Error(codes.Internal, "internal")), want: true},
+		{err: wrapCallError("start instance", "fhm123", status.Error(codes.NotFound, "missing"))},
+		{err: wrapCallError("start instance", "fhm123", status.Error(codes.PermissionDenied, "denied"))},
+		{err: ErrOperationFailed},
+		{err: nil},
+	}
+	for _, tt := range tests {
+		if got := IsTransient(tt.err); got != tt.want {
+			t.Errorf("IsTransient(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package yc
+
+import (
+	"context"
+	"time"
+
+	computepb "github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
+	operationpb "github.com/yandex-cloud/go-genproto/yandex/cloud/operation"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// InstanceStartTime returns when a compute instance was last started: the
+// completion time of its latest successful start operation, or its creation
+// time if it was not started since.
+func (c *Client) InstanceStartTime(ctx context.Context, folderID, instanceID string) (time.Time, error) {
+	instance, err := c.GetInstance(ctx, folderID, instanceID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// Use protoreflect.FullName as SDK v2 requires this format for endpoint resolution
+	endpoint := protoreflect.FullName("yandex.cloud.compute.v1.InstanceService.ListOperations")
+	operations, err := getResource(ctx, c, endpoint, "list instance operations", instanceID, func(ctx context.Context, conn grpc.ClientConnInterface) ([]*operationpb.Operation, error) {
+		client := computepb.NewInstanceServiceClient(conn)
+		return listAll(ctx, func(ctx context.Context, pageToken string) ([]*operationpb.Operation, string, error) {
+			resp, err := client.ListOperations(ctx, &computepb.ListInstanceOperationsRequest{
+				InstanceId: instanceID,
+				PageSize:   listPageSize,
+				PageToken:  pageToken,
+			}, c.listCallOptions()...)
+			return resp.GetOperations(), resp.GetNextPageToken(), err
+		})
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return lastStartTime(operations, instance.GetCreatedAt().AsTime()), nil
+}
+
+// lastStartTime returns the completion time of the latest successful start
+// operation, or createdAt if there is none.
+func lastStartTime(operations []*operationpb.Operation, createdAt time.Time) time.Time {
+	started := createdAt
+	for _, op := range operations {
+		if !op.GetDone() || op.GetError() != nil || !op.GetMetadata().MessageIs(&computepb.StartInstanceMetadata{}) {
+			continue
+		}
+		if at := op.GetModifiedAt().AsTime(); at.After(started) {
+			started = at
+		}
+	}
+	return started
+}
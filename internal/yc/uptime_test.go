@@ -0,0 +1,40 @@
+package yc
+
+import (
+	"testing"
+	"time"
+
+	computepb "github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
+	operationpb "github.com/yandex-cloud/go-genproto/yandex/cloud/operation"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestLastStartTime(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	operation := func(metadata proto.Message, at time.Time, done bool) *operationpb.Operation {
+		packed, err := anypb.New(metadata)
+		if err != nil {
+			t.Fatalf("anypb.New() error = %v", err)
+		}
+		return &operationpb.Operation{Metadata: packed, ModifiedAt: timestamppb.New(at), Done: done}
+	}
+
+	if got := lastStartTime(nil, created); !got.Equal(created) {
+		t.Fatalf("lastStartTime() without operations = %s, want creation time %s", got, created)
+	}
+
+	started := created.Add(48 * time.Hour)
+	operations := []*operationpb.Operation{
+		operation(&computepb.StartInstanceMetadata{}, created.Add(24*time.Hour), true),
+		operation(&computepb.StartInstanceMetadata{}, started, true),
+		operation(&computepb.StopInstanceMetadata{}, started.Add(time.Hour), true),
+		operation(&computepb.StartInstanceMetadata{}, started.Add(2*time.Hour), false),
+	}
+	if got := lastStartTime(operations, created); !got.Equal(started) {
+		t.Fatalf("lastStartTime() = %s, want latest completed start %s", got, started)
+	}
+}
@@ -0,0 +1,332 @@
+// Package ycstub provides an in-memory gRPC server implementing the subset of
+// Yandex Cloud Compute, Kubernetes and Operation services used by the yc
+// client, for full-stack tests without access to the cloud.
+package ycstub
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+
+	computepb "github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
+	k8spb "github.com/yandex-cloud/go-genproto/yandex/cloud/k8s/v1"
+	operationpb "github.com/yandex-cloud/go-genproto/yandex/cloud/operation"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Server is a stub Yandex Cloud API server. Operations complete immediately.
+type Server struct {
+	grpc *grpc.Server
+	ln   net.Listener
+
+	mu         sync.Mutex
+	instances  map[string]*computepb.Instance
+	clusters   map[string]*k8spb.Cluster
+	nodeGroups map[string]*k8spb.NodeGroup
+	operations map[string]*operationpb.Operation
+	calls      []string
+	nextOpID   int
+}
+
+// Start starts a stub server listening on a random local port. Interceptors
+// run after the call has been recorded, e.g. to inject failures.
+func Start(interceptors ...grpc.UnaryServerInterceptor) (*Server, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		ln:         ln,
+		instances:  make(map[string]*computepb.Instance),
+		clusters:   make(map[string]*k8spb.Cluster),
+		nodeGroups: make(map[string]*k8spb.NodeGroup),
+		operations: make(map[string]*operationpb.Operation),
+	}
+	s.grpc = grpc.NewServer(grpc.ChainUnaryInterceptor(append([]grpc.UnaryServerInterceptor{s.recordCall}, interceptors...)...))
+	computepb.RegisterInstanceServiceServer(s.grpc, &instanceService{s: s})
+	k8spb.RegisterClusterServiceServer(s.grpc, &clusterService{s: s})
+	k8spb.RegisterNodeGroupServiceServer(s.grpc, &nodeGroupService{s: s})
+	operationpb.RegisterOperationServiceServer(s.grpc, &operationService{s: s})
+
+	go func() { _ = s.grpc.Serve(ln) }()
+	return s, nil
+}
+
+// Addr returns the host:port the server listens on.
+func (s *Server) Addr() string {
+	return s.ln.Addr().String()
+}
+
+// Close stops the server.
+func (s *Server) Close() {
+	s.grpc.Stop()
+}
+
+// AddInstance adds a compute instance with the given status.
+func (s *Server) AddInstance(folderID, instanceID string, st computepb.Instance_Status) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.instances[instanceID] = &computepb.Instance{
+		Id:        instanceID,
+		FolderId:  folderID,
+		Name:      instanceID,
+		Status:    st,
+		Resources: &computepb.Resources{Cores: 2, CoreFraction: 100, Memory: 4 << 30},
+	}
+}
+
+// InstanceStatus returns the current status of an instance.
+func (s *Server) InstanceStatus(instanceID string) computepb.Instance_Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.instances[instanceID].GetStatus()
+}
+
+// AddCluster adds a Kubernetes cluster with the given status.
+func (s *Server) AddCluster(folderID, clusterID string, st k8spb.Cluster_Status) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.clusters[clusterID] = &k8spb.Cluster{Id: clusterID, FolderId: folderID, Name: clusterID, Status: st}
+}
+
+// ClusterStatus returns the current status of a Kubernetes cluster.
+func (s *Server) ClusterStatus(clusterID string) k8spb.Cluster_Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.clusters[clusterID].GetStatus()
+}
+
+// AddNodeGroup adds a fixed-scale Kubernetes node group.
+func (s *Server) AddNodeGroup(clusterID, nodeGroupID string, size int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nodeGroups[nodeGroupID] = &k8spb.NodeGroup{
+		Id:          nodeGroupID,
+		ClusterId:   clusterID,
+		Name:        nodeGroupID,
+		Status:      k8spb.NodeGroup_RUNNING,
+		ScalePolicy: fixedScale(size),
+	}
+}
+
+// NodeGroupSize returns the fixed scale size of a node group.
+func (s *Server) NodeGroupSize(nodeGroupID string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.nodeGroups[nodeGroupID].GetScalePolicy().GetFixedScale().GetSize()
+}
+
+// Calls returns the full names of all gRPC methods called so far.
+func (s *Server) Calls() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.calls...)
+}
+
+func (s *Server) recordCall(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	s.mu.Lock()
+	s.calls = append(s.calls, info.FullMethod)
+	s.mu.Unlock()
+	return handler(ctx, req)
+}
+
+// doneOperation records a completed operation. The caller must hold s.mu.
+func (s *Server) doneOperation(description string) *operationpb.Operation {
+	s.nextOpID++
+	op := &operationpb.Operation{
+		Id:          "op-" + strconv.Itoa(s.nextOpID),
+		Description: description,
+		Done:        true,
+	}
+	s.operations[op.Id] = op
+	return op
+}
+
+func fixedScale(size int64) *k8spb.ScalePolicy {
+	return &k8spb.ScalePolicy{
+		ScaleType: &k8spb.ScalePolicy_FixedScale_{FixedScale: &k8spb.ScalePolicy_FixedScale{Size: size}},
+	}
+}
+
+func notFound(kind, id string) error {
+	return status.Errorf(codes.NotFound, "%s %s not found", kind, id)
+}
+
+type instanceService struct {
+	computepb.UnimplementedInstanceServiceServer
+	s *Server
+}
+
+func (svc *instanceService) Get(_ context.Context, req *computepb.GetInstanceRequest) (*computepb.Instance, error) {
+	svc.s.mu.Lock()
+	defer svc.s.mu.Unlock()
+
+	instance, ok := svc.s.instances[req.GetInstanceId()]
+	if !ok {
+		return nil, notFound("instance", req.GetInstanceId())
+	}
+	return proto.Clone(instance).(*computepb.Instance), nil
+}
+
+func (svc *instanceService) List(_ context.Context, req *computepb.ListInstancesRequest) (*computepb.ListInstancesResponse, error) {
+	svc.s.mu.Lock()
+	defer svc.s.mu.Unlock()
+
+	resp := &computepb.ListInstancesResponse{}
+	for _, instance := range svc.s.instances {
+		if instance.GetFolderId() == req.GetFolderId() {
+			resp.Instances = append(resp.Instances, proto.Clone(instance).(*computepb.Instance))
+		}
+	}
+	return resp, nil
+}
+
+func (svc *instanceService) Start(_ context.Context, req *computepb.StartInstanceRequest) (*operationpb.Operation, error) {
+	return svc.setStatus(req.GetInstanceId(), computepb.Instance_RUNNING, "Start instance")
+}
+
+func (svc *instanceService) Stop(_ context.Context, req *computepb.StopInstanceRequest) (*operationpb.Operation, error) {
+	return svc.setStatus(req.GetInstanceId(), computepb.Instance_STOPPED, "Stop instance")
+}
+
+func (svc *instanceService) Update(_ context.Context, req *computepb.UpdateInstanceRequest) (*operationpb.Operation, error) {
+	svc.s.mu.Lock()
+	defer svc.s.mu.Unlock()
+
+	instance, ok := svc.s.instances[req.GetInstanceId()]
+	if !ok {
+		return nil, notFound("instance", req.GetInstanceId())
+	}
+	for _, path := range req.GetUpdateMask().GetPaths() {
+		switch path {
+		case "labels":
+			instance.Labels = req.GetLabels()
+		case "platform_id":
+			instance.PlatformId = req.GetPlatformId()
+		case "resources_spec.cores":
+			instance.Resources.Cores = req.GetResourcesSpec().GetCores()
+		case "resources_spec.core_fraction":
+			instance.Resources.CoreFraction = req.GetResourcesSpec().GetCoreFraction()
+		case "resources_spec.memory":
+			instance.Resources.Memory = req.GetResourcesSpec().GetMemory()
+		case "scheduling_policy.preemptible":
+			instance.SchedulingPolicy = &computepb.SchedulingPolicy{Preemptible: req.GetSchedulingPolicy().GetPreemptible()}
+		default:
+			return nil, status.Errorf(codes.Unimplemented, "update of %q is not supported by the stub", path)
+		}
+	}
+	return svc.s.doneOperation("Update instance"), nil
+}
+
+func (svc *instanceService) setStatus(instanceID string, st computepb.Instance_Status, description string) (*operationpb.Operation, error) {
+	svc.s.mu.Lock()
+	defer svc.s.mu.Unlock()
+
+	instance, ok := svc.s.instances[instanceID]
+	if !ok {
+		return nil, notFound("instance", instanceID)
+	}
+	instance.Status = st
+	return svc.s.doneOperation(description), nil
+}
+
+type clusterService struct {
+	k8spb.UnimplementedClusterServiceServer
+	s *Server
+}
+
+func (svc *clusterService) Get(_ context.Context, req *k8spb.GetClusterRequest) (*k8spb.Cluster, error) {
+	svc.s.mu.Lock()
+	defer svc.s.mu.Unlock()
+
+	cluster, ok := svc.s.clusters[req.GetClusterId()]
+	if !ok {
+		return nil, notFound("cluster", req.GetClusterId())
+	}
+	return proto.Clone(cluster).(*k8spb.Cluster), nil
+}
+
+func (svc *clusterService) Start(_ context.Context, req *k8spb.StartClusterRequest) (*operationpb.Operation, error) {
+	return svc.setStatus(req.GetClusterId(), k8spb.Cluster_RUNNING, "Start cluster")
+}
+
+func (svc *clusterService) Stop(_ context.Context, req *k8spb.StopClusterRequest) (*operationpb.Operation, error) {
+	return svc.setStatus(req.GetClusterId(), k8spb.Cluster_STOPPED, "Stop cluster")
+}
+
+func (svc *clusterService) setStatus(clusterID string, st k8spb.Cluster_Status, description string) (*operationpb.Operation, error) {
+	svc.s.mu.Lock()
+	defer svc.s.mu.Unlock()
+
+	cluster, ok := svc.s.clusters[clusterID]
+	if !ok {
+		return nil, notFound("cluster", clusterID)
+	}
+	cluster.Status = st
+	return svc.s.doneOperation(description), nil
+}
+
+type nodeGroupService struct {
+	k8spb.UnimplementedNodeGroupServiceServer
+	s *Server
+}
+
+func (svc *nodeGroupService) Get(_ context.Context, req *k8spb.GetNodeGroupRequest) (*k8spb.NodeGroup, error) {
+	svc.s.mu.Lock()
+	defer svc.s.mu.Unlock()
+
+	nodeGroup, ok := svc.s.nodeGroups[req.GetNodeGroupId()]
+	if !ok {
+		return nil, notFound("node group", req.GetNodeGroupId())
+	}
+	return proto.Clone(nodeGroup).(*k8spb.NodeGroup), nil
+}
+
+func (svc *nodeGroupService) Update(_ context.Context, req *k8spb.UpdateNodeGroupRequest) (*operationpb.Operation, error) {
+	svc.s.mu.Lock()
+	defer svc.s.mu.Unlock()
+
+	nodeGroup, ok := svc.s.nodeGroups[req.GetNodeGroupId()]
+	if !ok {
+		return nil, notFound("node group", req.GetNodeGroupId())
+	}
+	for _, path := range req.GetUpdateMask().GetPaths() {
+		switch path {
+		case "labels":
+			nodeGroup.Labels = req.GetLabels()
+		case "scale_policy", "scale_policy.fixed_scale", "scale_policy.fixed_scale.size":
+			nodeGroup.ScalePolicy = fixedScale(req.GetScalePolicy().GetFixedScale().GetSize())
+		default:
+			return nil, status.Errorf(codes.Unimplemented, "update of %q is not supported by the stub", path)
+		}
+	}
+	return svc.s.doneOperation("Update node group"), nil
+}
+
+type operationService struct {
+	operationpb.UnimplementedOperationServiceServer
+	s *Server
+}
+
+func (svc *operationService) Get(_ context.Context, req *operationpb.GetOperationRequest) (*operationpb.Operation, error) {
+	svc.s.mu.Lock()
+	defer svc.s.mu.Unlock()
+
+	op, ok := svc.s.operations[req.GetOperationId()]
+	if !ok {
+		return nil, notFound("operation", req.GetOperationId())
+	}
+	return proto.Clone(op).(*operationpb.Operation), nil
+}
@@ -0,0 +1,103 @@
+package ycstub_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	computepb "github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
+	k8spb "github.com/yandex-cloud/go-genproto/yandex/cloud/k8s/v1"
+
+	"github.com/sentoz/yc-sheduler/internal/config"
+	"github.com/sentoz/yc-sheduler/internal/executor"
+	"github.com/sentoz/yc-sheduler/internal/resource"
+	"github.com/sentoz/yc-sheduler/internal/scheduler"
+	"github.com/sentoz/yc-sheduler/internal/validator"
+	"github.com/sentoz/yc-sheduler/internal/yc"
+	"github.com/sentoz/yc-sheduler/internal/ycstub"
+)
+
+func startStub(t *testing.T) (*ycstub.Server, *yc.Client) {
+	t.Helper()
+
+	stub, err := ycstub.Start()
+	if err != nil {
+		t.Fatalf("ycstub.Start() error = %v", err)
+	}
+	t.Cleanup(stub.Close)
+
+	client, err := yc.NewClient(context.Background(), yc.AuthConfig{}, yc.ClientOptions{Endpoint: stub.Addr(), Plaintext: true})
+	if err != nil {
+		t.Fatalf("yc.NewClient() error = %v", err)
+	}
+	return stub, client
+}
+
+func TestExecutorAgainstStub(t *testing.T) {
+	t.Parallel()
+
+	stub, client := startStub(t)
+	stub.AddInstance("folder-1", "vm-1", computepb.Instance_STOPPED)
+	stub.AddCluster("folder-1", "k8s-1", k8spb.Cluster_RUNNING)
+
+	checker := resource.NewYCStateChecker(client)
+	operator := resource.NewYCOperator(client)
+
+	vm := config.Schedule{
+		Name:     "vm-start",
+		Type:     "daily",
+		Resource: config.Resource{Type: "vm", ID: "vm-1", FolderID: "folder-1"},
+		Actions:  config.Actions{Start: &config.ActionConfig{Enabled: true, Time: "09:00"}},
+	}
+	exec := executor.New()
+	exec.Make(checker, operator, vm, "start", false, nil)()
+	if got := stub.InstanceStatus("vm-1"); got != computepb.Instance_RUNNING {
+		t.Fatalf("instance status = %v, want RUNNING", got)
+	}
+
+	k8s := config.Schedule{
+		Name:     "k8s-stop",
+		Type:     "daily",
+		Resource: config.Resource{Type: "k8s_cluster", ID: "k8s-1", FolderID: "folder-1"},
+		Actions:  config.Actions{Stop: &config.ActionConfig{Enabled: true, Time: "19:00"}},
+	}
+	exec.Make(checker, operator, k8s, "stop", false, nil)()
+	if got := stub.ClusterStatus("k8s-1"); got != k8spb.Cluster_STOPPED {
+		t.Fatalf("cluster status = %v, want STOPPED", got)
+	}
+}
+
+func TestValidatorCorrectsStateAgainstStub(t *testing.T) {
+	t.Parallel()
+
+	stub, client := startStub(t)
+	stub.AddInstance("folder-1", "vm-2", computepb.Instance_RUNNING)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sched, err := scheduler.New("UTC", 1)
+	if err != nil {
+		t.Fatalf("scheduler.New() error = %v", err)
+	}
+	go func() { _ = sched.Start(ctx) }()
+
+	cfg := &config.Config{
+		Schedules: []config.Schedule{{
+			Name:     "vm-stop",
+			Type:     "daily",
+			Resource: config.Resource{Type: "vm", ID: "vm-2", FolderID: "folder-1"},
+			Actions:  config.Actions{Stop: &config.ActionConfig{Enabled: true, Time: "19:00"}},
+		}},
+	}
+	v := validator.New(resource.NewYCStateChecker(client), resource.NewYCOperator(client), cfg, sched, nil, false)
+	v.Start(ctx, 100*time.Millisecond)
+
+	deadline := time.Now().Add(10 * time.Second)
+	for stub.InstanceStatus("vm-2") != computepb.Instance_STOPPED {
+		if time.Now().After(deadline) {
+			t.Fatalf("instance status = %v, want STOPPED after validation", stub.InstanceStatus("vm-2"))
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
@@ -0,0 +1,10 @@
+package static
+
+import "embed"
+
+// InitTemplates contains the templates of the configuration scaffolded by
+// the init command: config.yaml.tmpl and example schedule manifests in
+// schedules/. It is embedded at build time from templates/init.
+//
+//go:embed templates/init
+var InitTemplates embed.FS
@@ -0,0 +1,38 @@
+package static
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaYAML converts a JSON schema to YAML in block style, keeping the
+// order of keys.
+func SchemaYAML(schema []byte) ([]byte, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(schema, &node); err != nil {
+		return nil, fmt.Errorf("static: parse schema: %w", err)
+	}
+	resetStyle(&node)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return nil, fmt.Errorf("static: encode schema: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("static: encode schema: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// resetStyle drops the JSON flow style and quoting of node and its
+// children, so they are quoted only where YAML requires it.
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}